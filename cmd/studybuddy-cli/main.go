@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/scenario"
+)
+
+// 終了コード
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run 引数に従って問題を作り、練習プリントを出力する
+func run(args []string) int {
+	flags := flag.NewFlagSet("studybuddy-cli", flag.ContinueOnError)
+	subject := flags.String("subject", "", "科目（"+strings.Join(config.Subjects, "・")+"）")
+	grade := flags.Int("grade", 0, "学年（1:中1, 2:中2, 3:中3。0ならアプリの設定）")
+	count := flags.Int("n", 10, "作る問題の数")
+	difficulty := flags.Int("difficulty", 0, "難易度（1-5。0ならアプリの科目別の設定）")
+	topics := flags.String("topics", "", "単元（カンマ区切り。複数なら順に出題。空なら学年の学習範囲全体）")
+	output := flags.String("o", "", "出力するファイル（空なら標準出力）")
+	format := flags.String("format", "", "出力の形式（text・json・pdf。空ならファイルの拡張子から決める）")
+	title := flags.String("title", "", "プリントの題名（空なら「科目の練習プリント」）")
+	model := flags.String("model", "", "使うモデル（空ならアプリの設定）")
+	offline := flags.Bool("offline", false, "AIに接続せず、用意してある問題だけを使う")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "使い方: studybuddy-cli -subject 数学 -grade 2 -n 10 -o practice.pdf")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if !slices.Contains(config.Subjects, *subject) {
+		fmt.Fprintf(os.Stderr, "科目を %s のどれかで指定してください\n", strings.Join(config.Subjects, "・"))
+		return exitUsage
+	}
+	if *count < 1 || *count > maxWorksheetProblems {
+		fmt.Fprintf(os.Stderr, "問題の数は1〜%dで指定してください\n", maxWorksheetProblems)
+		return exitUsage
+	}
+	outFormat, err := worksheetFormat(*format, *output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
+	}
+	if *grade == 0 {
+		*grade = cfg.UserGrade
+	}
+	if *grade < 1 || *grade > 3 {
+		fmt.Fprintln(os.Stderr, "学年は1〜3で指定してください")
+		return exitUsage
+	}
+	if *difficulty == 0 {
+		*difficulty = cfg.DifficultyFor(*subject)
+	}
+	if *difficulty < 1 || *difficulty > 5 {
+		fmt.Fprintln(os.Stderr, "難易度は1〜5で指定してください")
+		return exitUsage
+	}
+	if *model != "" {
+		cfg.UpdateAIModel(*model)
+	}
+
+	// 進み具合は標準エラーに出し、標準出力には練習プリントだけを書く
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	engine, closeEngine, err := openEngine(ctx, cfg, *offline)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	defer closeEngine()
+
+	req := worksheetRequest{
+		Subject:    *subject,
+		Grade:      *grade,
+		Difficulty: *difficulty,
+		Topics:     splitTopics(*topics),
+		Count:      *count,
+	}
+	problems, err := generateWorksheet(ctx, engine, req, func(done int) {
+		fmt.Fprintf(os.Stderr, "\r問題を作成しています（%d/%d）", done, req.Count)
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	if *title == "" {
+		*title = fmt.Sprintf("%sの練習プリント", *subject)
+	}
+	if err := writeWorksheet(*output, outFormat, *title, req, problems); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	if *output != "" {
+		fmt.Fprintf(os.Stderr, "%d問を %s に書き出しました\n", len(problems), *output)
+	}
+	return exitOK
+}
+
+// openEngine アプリと同じ設定でAIエンジンを用意（使用量・応答の保存先はアプリのデータベース）
+func openEngine(ctx context.Context, cfg *config.Config, offline bool) (*ai.Engine, func(), error) {
+	if offline {
+		engine, err := scenario.NewOfflineEngine(cfg.AI)
+		if err != nil {
+			return nil, nil, err
+		}
+		return engine, func() { _ = engine.Close() }, nil
+	}
+
+	// データベースがなくても問題は作れる（クラウドAIは使用量を記録できないため使わない）
+	db, err := database.Open(cfg.DatabaseSource())
+	if err != nil {
+		slog.Warn("データベースに接続できないため、クラウドAIを使わずに作成します", "error", err)
+		cfg.AI.Cloud.Provider = config.CloudProviderNone
+		db = nil
+	}
+	engine, err := ai.NewEngine(cfg.AI)
+	if err != nil {
+		if db != nil {
+			_ = db.Close()
+		}
+		return nil, nil, fmt.Errorf("AI初期化エラー: %w", err)
+	}
+	if db != nil {
+		engine.SetUsageStore(db)
+		engine.SetResponseCache(db)
+		engine.SetMetricsStore(db)
+	}
+	closeAll := func() {
+		_ = engine.Close()
+		if db != nil {
+			_ = db.Close()
+		}
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	health := engine.CheckHealth(checkCtx)
+	cancel()
+	fmt.Fprintf(os.Stderr, "%s %s（%s）\n", health.Status.Icon(), health.Status.Label(), engine.GetCurrentModel())
+	return engine, closeAll, nil
+}
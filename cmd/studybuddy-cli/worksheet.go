@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/export"
+)
+
+// 練習プリントの作成
+const (
+	maxWorksheetProblems = 100 // 1回に作る問題の上限
+	generateAttempts     = 3   // 1問あたりの作成の試行回数
+	generateTimeout      = 60 * time.Second
+)
+
+// 出力の形式
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatPDF  = "pdf"
+)
+
+// worksheetRequest 練習プリントの条件
+type worksheetRequest struct {
+	Subject    string   `json:"subject"`
+	Grade      int      `json:"grade"`
+	Difficulty int      `json:"difficulty"`
+	Topics     []string `json:"topics,omitempty"`
+	Count      int      `json:"count"`
+}
+
+// worksheetProblem JSONで出力する問題
+type worksheetProblem struct {
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Options       []string `json:"options"`
+	CorrectAnswer int      `json:"correct_answer"` // Optionsの添字（0から）
+	Explanation   string   `json:"explanation"`
+	Difficulty    int      `json:"difficulty"`
+	Topic         string   `json:"topic"`
+	Model         string   `json:"model,omitempty"` // 問題を作ったモデル（用意してある問題なら空）
+}
+
+// splitTopics カンマ区切りの単元（空の要素は除く）
+func splitTopics(text string) []string {
+	var topics []string
+	for _, topic := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '、' }) {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// worksheetFormat 出力の形式（指定がなければファイルの拡張子から。標準出力ならテキスト）
+func worksheetFormat(format, output string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(output)) {
+		case ".pdf":
+			return formatPDF, nil
+		case ".json":
+			return formatJSON, nil
+		default:
+			return formatText, nil
+		}
+	}
+	switch format {
+	case formatText, formatJSON, formatPDF:
+		return format, nil
+	}
+	return "", fmt.Errorf("出力の形式は %s・%s・%s のどれかで指定してください: %s", formatText, formatJSON, formatPDF, format)
+}
+
+// generateWorksheet アプリの模擬テストと同じく、最近作った問題とほぼ同じ問題を避けながら問題を作る
+func generateWorksheet(ctx context.Context, engine *ai.Engine, req worksheetRequest, progress func(done int)) ([]*ai.Problem, error) {
+	var problems []*ai.Problem
+	var recentHashes []string
+	for i := 0; i < req.Count; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("作成を中止しました: %w", err)
+		}
+
+		topic := ""
+		if len(req.Topics) > 0 {
+			topic = req.Topics[i%len(req.Topics)]
+		}
+		studyContext := ai.StudyContext{
+			Subject:      req.Subject,
+			Grade:        req.Grade,
+			Difficulty:   req.Difficulty,
+			Emotion:      "neutral",
+			Topic:        topic,
+			RecentHashes: recentHashes,
+		}
+
+		for attempt := 0; attempt < generateAttempts; attempt++ {
+			problemCtx, cancel := context.WithTimeout(ctx, generateTimeout)
+			problem, err := engine.GeneratePersonalizedProblem(problemCtx, studyContext)
+			cancel()
+			if err != nil {
+				slog.Warn("問題生成エラー", "topic", topic, "error", err)
+				continue
+			}
+			if topic != "" {
+				problem.ProblemType = topic
+			}
+			problems = append(problems, problem)
+			recentHashes = append(recentHashes, ai.SimilarityHash(problem.Description))
+			break
+		}
+		progress(i + 1)
+	}
+
+	if len(problems) == 0 {
+		return nil, fmt.Errorf("問題を作成できませんでした")
+	}
+	return problems, nil
+}
+
+// writeWorksheet 練習プリントを出力（outputが空なら標準出力）
+func writeWorksheet(output, format, title string, req worksheetRequest, problems []*ai.Problem) error {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case formatPDF:
+		var exporter *export.Exporter
+		if exporter, err = export.NewExporter(); err == nil {
+			err = exporter.WriteProblemSetPDF(&buf, title, problems)
+		}
+	case formatJSON:
+		err = writeWorksheetJSON(&buf, title, req, problems)
+	default:
+		err = writeWorksheetText(&buf, title, problems)
+	}
+	if err != nil {
+		return fmt.Errorf("練習プリント作成エラー: %w", err)
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+	} else {
+		err = os.WriteFile(output, buf.Bytes(), 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("練習プリント書き込みエラー: %w", err)
+	}
+	return nil
+}
+
+// writeWorksheetText 印刷やコピーに使うテキストの練習プリント（最後に解答・解説）
+func writeWorksheetText(w io.Writer, title string, problems []*ai.Problem) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n作成日: %s\n\n", title, time.Now().Format("2006年01月02日"))
+	for i, problem := range problems {
+		fmt.Fprintf(&b, "第%d問　%s\n%s\n", i+1, problem.Title, problem.Description)
+		for j, option := range problem.Options {
+			fmt.Fprintf(&b, "  %d. %s\n", j+1, option)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("---- 解答・解説 ----\n")
+	for i, problem := range problems {
+		answer := ""
+		if problem.CorrectAnswer >= 0 && problem.CorrectAnswer < len(problem.Options) {
+			answer = problem.Options[problem.CorrectAnswer]
+		}
+		fmt.Fprintf(&b, "第%d問　正解: %d. %s\n", i+1, problem.CorrectAnswer+1, answer)
+		if problem.Explanation != "" {
+			fmt.Fprintf(&b, "  %s\n", problem.Explanation)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeWorksheetJSON ほかのツールで読み込むためのJSONの練習プリント
+func writeWorksheetJSON(w io.Writer, title string, req worksheetRequest, problems []*ai.Problem) error {
+	sheet := struct {
+		Title     string             `json:"title"`
+		CreatedAt time.Time          `json:"created_at"`
+		Request   worksheetRequest   `json:"request"`
+		Problems  []worksheetProblem `json:"problems"`
+	}{Title: title, CreatedAt: time.Now(), Request: req}
+
+	for _, problem := range problems {
+		sheet.Problems = append(sheet.Problems, worksheetProblem{
+			Title:         problem.Title,
+			Description:   problem.Description,
+			Options:       problem.Options,
+			CorrectAnswer: problem.CorrectAnswer,
+			Explanation:   problem.Explanation,
+			Difficulty:    problem.Difficulty,
+			Topic:         problem.ProblemType,
+			Model:         problem.Model,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(sheet)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/scenario"
+)
+
+func TestWorksheetFormat(t *testing.T) {
+	tests := []struct {
+		format, output, want string
+	}{
+		{"", "", formatText},
+		{"", "practice.PDF", formatPDF},
+		{"", "practice.json", formatJSON},
+		{"", "practice.txt", formatText},
+		{formatJSON, "practice.pdf", formatJSON},
+	}
+	for _, tt := range tests {
+		if got, err := worksheetFormat(tt.format, tt.output); err != nil || got != tt.want {
+			t.Errorf("worksheetFormat(%q, %q) = %q, %v, want %q", tt.format, tt.output, got, err, tt.want)
+		}
+	}
+	if _, err := worksheetFormat("docx", ""); err == nil {
+		t.Error("未対応の形式はエラーになるはず")
+	}
+}
+
+func TestSplitTopics(t *testing.T) {
+	got := splitTopics(" 過去形,不定詞、 ,比較 ")
+	if strings.Join(got, "|") != "過去形|不定詞|比較" {
+		t.Errorf("splitTopics = %q", got)
+	}
+}
+
+func TestGenerateWorksheetOffline(t *testing.T) {
+	engine, err := scenario.NewOfflineEngine(config.Default().AI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := worksheetRequest{Subject: "数学", Grade: 2, Difficulty: 2, Topics: []string{"一次関数", "連立方程式"}, Count: 3}
+
+	var done []int
+	problems, err := generateWorksheet(context.Background(), engine, req, func(n int) { done = append(done, n) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 3 || len(done) != 3 {
+		t.Fatalf("問題 = %d問, 進み具合の通知 = %v", len(problems), done)
+	}
+	if problems[1].ProblemType != "連立方程式" || problems[2].ProblemType != "一次関数" {
+		t.Errorf("単元を順に出題していない: %q %q", problems[1].ProblemType, problems[2].ProblemType)
+	}
+
+	var b strings.Builder
+	if err := writeWorksheetText(&b, "数学の練習プリント", problems); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"数学の練習プリント", "第3問", "---- 解答・解説 ----", "第1問　正解: "} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("テキストに %q がない:\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package achievement
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// Achievement 実績（賞状を発行できる大きな達成）
+type Achievement struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"` // 賞状の本文
+	Emoji       string `json:"emoji"`
+	Category    string `json:"category"` // "streak" | "problems" | "level"
+	Threshold   int    `json:"threshold"`
+}
+
+// Achievements 実績の一覧（カテゴリ内は達成しやすい順）
+var Achievements = []Achievement{
+	{ID: "streak_7", Title: "7日連続学習", Description: "7日間毎日欠かさず学習を続けました。", Emoji: "🔥", Category: "streak", Threshold: 7},
+	{ID: "streak_30", Title: "30日連続学習", Description: "30日間毎日欠かさず学習を続けました。", Emoji: "🔥", Category: "streak", Threshold: 30},
+	{ID: "streak_100", Title: "100日連続学習", Description: "100日間毎日欠かさず学習を続けました。その努力は本物です。", Emoji: "👑", Category: "streak", Threshold: 100},
+	{ID: "problems_100", Title: "100問達成", Description: "これまでに100問の問題に取り組みました。", Emoji: "📚", Category: "problems", Threshold: 100},
+	{ID: "problems_500", Title: "500問達成", Description: "これまでに500問の問題に取り組みました。", Emoji: "📚", Category: "problems", Threshold: 500},
+	{ID: "problems_1000", Title: "1000問達成", Description: "これまでに1000問もの問題に取り組みました。積み重ねた努力をたたえます。", Emoji: "🏆", Category: "problems", Threshold: 1000},
+	{ID: "level_10", Title: "レベル10到達", Description: "学習を積み重ねてレベル10に到達しました。", Emoji: "⭐", Category: "level", Threshold: 10},
+	{ID: "level_30", Title: "レベル30到達", Description: "学習を積み重ねてレベル30に到達しました。", Emoji: "🌟", Category: "level", Threshold: 30},
+}
+
+// Lookup IDから実績を取得
+func Lookup(id string) (Achievement, bool) {
+	for _, achievement := range Achievements {
+		if achievement.ID == id {
+			return achievement, true
+		}
+	}
+	return Achievement{}, false
+}
+
+// Stats 実績の判定に使う学習記録
+type Stats struct {
+	CurrentStreak int
+	LongestStreak int
+	TotalProblems int
+	Level         int
+}
+
+// value 実績のカテゴリに対応する記録の値
+func (s Stats) value(category string) int {
+	switch category {
+	case "streak":
+		return max(s.CurrentStreak, s.LongestStreak)
+	case "problems":
+		return s.TotalProblems
+	case "level":
+		return s.Level
+	}
+	return 0
+}
+
+// Earned 獲得済みの実績
+type Earned struct {
+	Achievement
+	EarnedAt time.Time `json:"earned_at"`
+}
+
+// Manager 実績の判定と記録
+type Manager struct {
+	db *database.DB
+}
+
+// NewManager 実績管理システムを作成
+func NewManager(db *database.DB) *Manager {
+	return &Manager{db: db}
+}
+
+// Check 学習記録から実績を判定し、新しく獲得した実績を返す
+func (m *Manager) Check(userID string, stats Stats) ([]Achievement, error) {
+	var unlocked []Achievement
+	now := time.Now()
+	for _, achievement := range Achievements {
+		if stats.value(achievement.Category) < achievement.Threshold {
+			continue
+		}
+
+		created, err := m.db.CreateAchievement(&database.EarnedAchievement{
+			UserID:        userID,
+			AchievementID: achievement.ID,
+			EarnedAt:      now,
+		})
+		if err != nil {
+			return unlocked, fmt.Errorf("実績記録エラー: %w", err)
+		}
+		if created {
+			unlocked = append(unlocked, achievement)
+		}
+	}
+
+	return unlocked, nil
+}
+
+// Earned 獲得済みの実績を取得（古い順）
+func (m *Manager) Earned(userID string) ([]Earned, error) {
+	records, err := m.db.GetAchievements(userID)
+	if err != nil {
+		return nil, fmt.Errorf("実績取得エラー: %w", err)
+	}
+
+	var earned []Earned
+	for _, record := range records {
+		// 削除された実績の記録は無視
+		if achievement, exists := Lookup(record.AchievementID); exists {
+			earned = append(earned, Earned{Achievement: achievement, EarnedAt: record.EarnedAt})
+		}
+	}
+
+	return earned, nil
+}
@@ -5,26 +5,46 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/curriculum"
+	"github.com/okamyuji/studybuddy-ai/internal/figure"
+	"github.com/okamyuji/studybuddy-ai/internal/mathcheck"
+	"github.com/okamyuji/studybuddy-ai/internal/privacy"
 )
 
 // Engine AI推論エンジン
 type Engine struct {
 	config       config.AIConfig
 	httpClient   *http.Client
-	isOnline     bool
-	lastCheck    time.Time
-	failureCount int
+	health       Health
+	healthHooks  []HealthHook
 	mu           sync.RWMutex
 	problemIndex map[string]int // 教科別の問題インデックス
+	usageStore   CloudUsageStore
+	embedStore   EmbeddingStore
+	profileID    string // クラウドAIの1日の使用量を数えるプロフィール
+	redactor     *privacy.Redactor
+	repairStats  RepairStats
+	paneWidth    float32 // 解説欄の大きさ（生成する文章の長さの目安）
+	paneHeight   float32
+
+	modelStates     map[string]*modelState // モデルごとの生成の失敗の記録（予備のモデルに切り替えるため）
+	responseCache   ResponseCache          // AIの応答の保存先（nilなら保存しない）
+	metricsStore    MetricsStore           // 生成の計測の記録先（nilなら記録しない）
+	curriculum      curriculum.Curriculum  // 問題の生成に使う学習範囲（保護者・先生が編集できる）
+	offlineProblems []OfflineProblem       // 内容パックで配られた問題（AIを使えないときに出題する）
+	rotationStore   RotationStore          // 内蔵の問題を出題した位置の保存先（nilなら保存しない）
 }
 
 // Problem 問題構造体
@@ -38,6 +58,9 @@ type Problem struct {
 	EstimatedTime int // 秒
 	Encouragement string
 	ProblemType   string
+	Model         string         // 問題を作ったモデル（用意してある問題なら空）
+	Audio         string         // 読み上げる英文（リスニング問題のみ。問題文には含めない）
+	Figure        *figure.Figure // 問題の図（図表の読み取り問題のみ）
 }
 
 // StudyContext 学習コンテキスト
@@ -48,18 +71,13 @@ type StudyContext struct {
 	Difficulty     int
 	Emotion        string
 	Progress       float64
-	Strengths      []string
-	Weaknesses     []string
-	PreviousErrors []ErrorPattern
+	Strengths      []string // 得意な科目（フィードバックのたとえに使う。フィードバックを作るときに設定）
 	SessionHistory []SessionInfo
-}
+	Topic          string   // 出題する単元（空なら学年の学習範囲全体）
+	RecentHashes   []string // 最近出題した問題の類似ハッシュ（ほぼ同じ問題は作り直してもらう）
 
-// ErrorPattern エラーパターン
-type ErrorPattern struct {
-	ProblemType  string
-	ErrorType    string
-	Frequency    int
-	LastOccurred time.Time
+	// 出題に関係する最近の間違い（関係の強い順。プロンプトに具体例として含める）
+	RecentMistakes []MistakeExample
 }
 
 // SessionInfo セッション情報
@@ -80,23 +98,51 @@ type FeedbackRequest struct {
 	TimeTaken    int
 	Emotion      string
 	StudyContext StudyContext
+	// 生徒が答えた、その答えを選んだ理由（聞かなかったときは空）
+	Rationale string
 }
 
 // FeedbackResponse フィードバック応答
 type FeedbackResponse struct {
 	Message       string
 	Explanation   string
+	Calculation   string // 計算過程（数学の問題のみ）
 	Encouragement string
 	NextSteps     string
 	TipOfDay      string
+	Analogy       string // 得意な科目や身近な例へのたとえ（関連付け説明を使うときのみ）
+}
+
+// WeeklySummaryRequest 週間レポート要約要求
+type WeeklySummaryRequest struct {
+	Grade          int
+	StudyDays      int
+	StudyMinutes   int
+	ManualMinutes  int    // StudyMinutesのうちアプリ外（塾・紙のドリルなど）の学習時間
+	FocusNote      string // 集中度のまとめ（例: 平均集中度72点（夕方の集中度が低い: 55点））
+	TotalProblems  int
+	AccuracyRate   float64
+	SubjectResults []SessionInfo
+	Strengths      []string
+	Weaknesses     []string
+}
+
+// WeeklySummary 週間レポート要約
+type WeeklySummary struct {
+	Summary    string
+	Strengths  string
+	Weaknesses string
+	Advice     string
 }
 
 // OllamaRequest Ollama API リクエスト
 type OllamaRequest struct {
-	Model   string                 `json:"model"`
-	Prompt  string                 `json:"prompt"`
-	Stream  bool                   `json:"stream"`
-	Options map[string]interface{} `json:"options,omitempty"`
+	Model     string                 `json:"model"`
+	Prompt    string                 `json:"prompt"`
+	Stream    bool                   `json:"stream"`
+	Options   map[string]interface{} `json:"options,omitempty"`
+	KeepAlive string                 `json:"keep_alive,omitempty"`
+	Images    []string               `json:"images,omitempty"` // 画像対応モデルに渡す画像（base64）
 }
 
 // OllamaResponse Ollama API レスポンス
@@ -104,6 +150,12 @@ type OllamaResponse struct {
 	Response string `json:"response"`
 	Done     bool   `json:"done"`
 	Error    string `json:"error,omitempty"`
+
+	// 生成の計測（最後の応答（done）にだけ含まれる。時間はナノ秒）
+	PromptEvalCount int   `json:"prompt_eval_count,omitempty"`
+	EvalCount       int   `json:"eval_count,omitempty"`
+	TotalDuration   int64 `json:"total_duration,omitempty"`
+	EvalDuration    int64 `json:"eval_duration,omitempty"`
 }
 
 // NewEngine AI エンジンを作成
@@ -113,52 +165,51 @@ func NewEngine(config config.AIConfig) (*Engine, error) {
 		httpClient: &http.Client{
 			Timeout: 300 * time.Second, // Ollamaモデルロード用5分タイムアウト
 		},
-		isOnline:     true, // 初期状態でAIを試行
-		lastCheck:    time.Time{},
-		failureCount: 0, // 失敗カウント初期化
+		health:       Health{Status: HealthOnline}, // 初期状態でAIを試行（実際の接続は初回利用時・定期的な確認でテスト）
 		problemIndex: make(map[string]int),
+		redactor:     privacy.NewRedactor(),
+		curriculum:   curriculum.Default(),
 	}
-
-	// 初期状態をオンラインに設定（実際の接続は初回利用時にテスト）
-	engine.setOnline()
+	engine.redactor.SetLocalAccount()
+	engine.redactor.Set("api_key", config.Cloud.APIKey, privacy.RedactedSecret)
 
 	return engine, nil
 }
 
-// setOnline AIオンライン状態を設定
-func (e *Engine) setOnline() {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	e.isOnline = true
-	e.failureCount = 0
-	e.lastCheck = time.Now()
+// SetTransport Ollama・クラウドAIへの通信に使うトランスポートを差し替える
+// （テストで記録した応答を返すため。最初の通信より前に呼ぶ）
+func (e *Engine) SetTransport(transport http.RoundTripper) {
+	e.httpClient.Transport = transport
 }
 
-// shouldTryAI AI接続を試行すべきか判定（常に試行）
+// shouldTryAI AI接続を試行すべきか判定（Ollamaに接続できないと確認できたときは、クラウドAIがなければ試行しない）
 func (e *Engine) shouldTryAI() bool {
-	e.mu.RLock()
-	defer e.mu.RUnlock()
-
-	// 常にAI接続を試行（学習アプリとしてAI生成が最優先）
-	return true
+	// 学習アプリとしてAI生成が最優先。生成の失敗だけではオフラインにせず、定期的な確認の結果で判断する
+	if e.Health().Status != HealthOffline {
+		return true
+	}
+	return e.cloudEnabled()
 }
 
 // recordFailure AI失敗を記録
 func (e *Engine) recordFailure() {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	e.isOnline = false
-	e.failureCount++
-	e.lastCheck = time.Now()
+	e.setHealth(func(h *Health) {
+		h.Failures++
+		if h.Status == HealthOffline {
+			return
+		}
+		h.Status = HealthDegraded
+		h.Detail = fmt.Sprintf("AIの生成に%d回続けて失敗しました", h.Failures)
+	})
 }
 
 // recordSuccess AI成功を記録
 func (e *Engine) recordSuccess() {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	e.isOnline = true
-	e.failureCount = 0
-	e.lastCheck = time.Now()
+	e.setHealth(func(h *Health) {
+		h.Status = HealthOnline
+		h.Detail = ""
+		h.Failures = 0
+	})
 }
 
 // testConnection Ollamaサーバーとの接続をテスト
@@ -182,68 +233,143 @@ func (e *Engine) testConnection(ctx context.Context) error {
 // GeneratePersonalizedProblem 個人に最適化された問題を生成（オフライン対応）
 func (e *Engine) GeneratePersonalizedProblem(ctx context.Context, studyContext StudyContext) (*Problem, error) {
 	// オンライン状態チェック
+	if isListening(studyContext) {
+		return e.generateListeningProblem(ctx, studyContext), nil
+	}
+	if isFigure(studyContext) {
+		return e.generateFigureProblem(ctx, studyContext), nil
+	}
 	if !e.shouldTryAI() {
 		return e.generateOfflineProblem(studyContext), nil
 	}
 
-	prompt := e.buildPersonalizedPrompt(studyContext)
-	response, err := e.generate(ctx, prompt)
+	// 検証に通らない問題や最近出題した問題・例に挙げた間違いとほぼ同じ問題は自動修正し、それでも直らなければ内蔵問題を使う
+	problem, err := e.generateProblem(ctx, e.buildPersonalizedPrompt(studyContext), checkPersonalizedProblem(studyContext))
 	if err != nil {
-		e.recordFailure()
 		return e.generateOfflineProblem(studyContext), nil
 	}
-
-	e.recordSuccess()
-	return e.parseProblemResponse(response)
+	if studyContext.Topic != "" {
+		problem.ProblemType = studyContext.Topic
+	}
+	return problem, nil
 }
 
-// GenerateFeedback フィードバックを生成（オフライン対応）
-func (e *Engine) GenerateFeedback(ctx context.Context, req FeedbackRequest) (*FeedbackResponse, error) {
-	// オンライン状態チェック
+// GenerateVariant 同じ考え方を問う、数値や言い回しを変えた類題を生成（オフライン時は同じ科目の内蔵問題）
+func (e *Engine) GenerateVariant(ctx context.Context, problem Problem, studyContext StudyContext) (*Problem, error) {
+	if studyContext.Topic == "" {
+		studyContext.Topic = problem.ProblemType
+	}
+	if problem.Audio != "" {
+		// リスニング問題の類題は、別の英文を聞いて答える問題にする
+		studyContext.Topic = ListeningTopic
+		return e.generateListeningProblem(ctx, studyContext), nil
+	}
+	if problem.Figure != nil {
+		// 図表の読み取り問題の類題は、別の図を読み取る問題にする
+		studyContext.Topic = FigureTopic
+		return e.generateFigureProblem(ctx, studyContext), nil
+	}
 	if !e.shouldTryAI() {
-		return e.generateOfflineFeedback(req), nil
+		return e.generateOfflineProblem(studyContext), nil
 	}
 
+	variant, err := e.generateProblem(ctx, e.buildVariantPrompt(problem, studyContext), func(variant *Problem) error {
+		if strings.TrimSpace(variant.Description) == strings.TrimSpace(problem.Description) {
+			return fmt.Errorf("類題が元の問題と同じです。数値や言い回しを変えてください")
+		}
+		return validateGuardedOutput(problem.Description+problem.Explanation, problemOutputs(variant)...)
+	})
+	if err != nil {
+		return e.generateOfflineProblem(studyContext), nil
+	}
+	if variant.ProblemType == "" {
+		variant.ProblemType = problem.ProblemType
+	}
+	return variant, nil
+}
+
+// buildVariantPrompt 類題生成プロンプト
+func (e *Engine) buildVariantPrompt(problem Problem, context StudyContext) string {
+	gradeText := []string{"", "中1", "中2", "中3"}
+	correct := ""
+	if problem.CorrectAnswer >= 0 && problem.CorrectAnswer < len(problem.Options) {
+		correct = problem.Options[problem.CorrectAnswer]
+	}
+	options := len(problem.Options)
+	if options < MinOptions || options > MaxOptions {
+		options = DefaultOptions
+	}
+
+	mathConstraints := ""
+	if context.Subject == "数学" || context.Subject == "算数" {
+		mathConstraints = `
+- 数値を変えたら必ず計算し直し、代入して検算すること`
+	}
+
+	return fmt.Sprintf(`%s%sの次の問題の類題を1問作成。
+
+【元の問題】
+%s
+
+【重要な制約】
+%s
+- 元の問題と同じ考え方・解き方で解ける問題にすること
+- 数値、語句、場面、言い回しを変え、元の問題と同じ問題文にしないこと
+- 難易度は元の問題と同じくらいにすること
+- 架空の資料、文章、教科書は一切参照しないこと
+- 問題文には必要なすべての情報を直接含め、完全に自己完結させること
+- 選択肢は元の問題と同じ%d個にすること%s
+
+形式:
+TITLE: タイトル
+DESCRIPTION: 問題文
+%s
+EXPLANATION: 解説
+DIFFICULTY: %d
+TIME: 180
+ENCOURAGEMENT: 応援メッセージ
+TYPE: %s
+
+上記形式のみで回答。`,
+		gradeText[context.Grade], context.Subject,
+		fenceContent(fmt.Sprintf("問題: %s\n正解: %s\n解説: %s", problem.Description, correct, problem.Explanation)),
+		fencedContentRule, options, mathConstraints, optionFormat(options), max(problem.Difficulty, 1), sanitizeContent(problem.ProblemType))
+}
+
+// GenerateFeedback フィードバックを生成（オフライン対応）
+func (e *Engine) GenerateFeedback(ctx context.Context, req FeedbackRequest) (*FeedbackResponse, error) {
+	// 同じ問題・同じ解答へのフィードバックは保存したものを使う（オフラインのときも保存したものがあれば使う）
 	prompt := e.buildFeedbackPrompt(req)
-	response, err := e.generate(ctx, prompt)
+	response, usedCache, err := e.generateCached(ctx, cacheKindFeedback, prompt, feedbackCacheTTL)
 	if err != nil {
-		e.recordFailure()
+		if !errors.Is(err, errAIUnavailable) {
+			e.recordFailure()
+		}
 		return e.generateOfflineFeedback(req), nil
 	}
 
-	e.recordSuccess()
-	return e.parseFeedbackResponse(response)
+	if !usedCache {
+		e.recordSuccess()
+	}
+	feedback, err := e.parseFeedbackResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	e.adaptFeedbackReadingLevel(feedback)
+	return feedback, nil
 }
 
 // buildPersonalizedPrompt 学習指導要領準拠プロンプト（架空資料参照禁止）
 func (e *Engine) buildPersonalizedPrompt(context StudyContext) string {
-	// 学年別学習内容マップ（2024年度学習指導要領準拠）
-	gradeContent := map[int]map[string]string{
-		1: {
-			"数学": "正の数・負の数、文字と式、一次方程式、比例と反比例、平面図形、空間図形、データの活用",
-			"英語": "アルファベット、基本単語、be動詞、一般動詞、疑問文、否定文、現在進行形",
-			"国語": "漢字の読み書き、詩歌の鑑賞、説明文の読解、古典の基礎、文法（品詞）",
-			"理科": "植物の生活と種類、身のまわりの物質、光・音・力、大地の変化",
-			"社会": "世界の地理、日本の地理、歴史（古代文明から平安時代）",
-		},
-		2: {
-			"数学": "式の計算、連立方程式、一次関数、図形の性質と合同、確率、データの活用",
-			"英語": "過去形、未来形、助動詞、比較級・最上級、不定詞、動名詞",
-			"国語": "短歌・俳句、説明文・論説文、小説、古典（古文・漢文の基礎）、敬語",
-			"理科": "動物の生活と生物の変遷、電流とその利用、化学変化と原子・分子、天気とその変化",
-			"社会": "日本の歴史（鎌倉時代から江戸時代）、世界と日本の地理",
-		},
-		3: {
-			"数学": "二次方程式、二次関数、相似、三平方の定理、円の性質、標本調査",
-			"英語": "現在完了、受動態、関係代名詞、間接疑問文、分詞",
-			"国語": "近現代文学、古典文学、文法の総復習、論説文・評論文の読解",
-			"理科": "生命の連続性、運動とエネルギー、化学変化とイオン、地球と宇宙",
-			"社会": "日本の歴史（明治維新から現代）、公民（政治・経済・国際社会）",
-		},
-	}
-
 	gradeText := []string{"", "中1", "中2", "中3"}
-	content := gradeContent[context.Grade][context.Subject]
+	content := e.curriculumContent(context.Grade, context.Subject)
+	options := optionCountFor(context)
+	problemType := "カテゴリ"
+	if context.Topic != "" {
+		// 生徒が選んだ単元は、その単元だけから出題してもらい、単元名で記録する
+		content = fmt.Sprintf("単元「%s」（この単元の内容だけを問うこと）", context.Topic)
+		problemType = context.Topic
+	}
 
 	// 数学問題の場合の追加制約
 	mathConstraints := ""
@@ -267,24 +393,23 @@ func (e *Engine) buildPersonalizedPrompt(context StudyContext) string {
 - 架空の資料、文章、教科書は一切参照しないこと
 - "次の文中から""下の図""以下の文""次の文字""次の単語""次の数式""次の図""次の表は""次の資料"といった、問題文には存在しない資料への言及は絶対禁止
 - 問題文には必要なすべての情報（例文、数式、数値など）を直接含めること
-- 問題文は必ず完全に自己完結させること%s
+- 問題文は必ず完全に自己完結させること
+- %s
+- %s%s%s
 
 形式:
 TITLE: タイトル
 DESCRIPTION: 問題文
-OPTION1: 選択肢1
-OPTION2: 選択肢2
-OPTION3: 選択肢3
-OPTION4: 選択肢4
-CORRECT: 1
+%s
 EXPLANATION: 解説
 DIFFICULTY: %d
 TIME: 180
 ENCOURAGEMENT: 応援メッセージ
-TYPE: カテゴリ
+TYPE: %s
 
 上記形式のみで回答。`,
-		gradeText[context.Grade], context.Subject, content, mathConstraints, context.Difficulty)
+		gradeText[context.Grade], context.Subject, content, optionInstruction(options), e.verbosityInstruction(), mathConstraints,
+		buildMistakeSection(context.RecentMistakes), optionFormat(options), context.Difficulty, problemType)
 }
 
 // buildFeedbackPrompt 数学的正確性重視フィードバックプロンプト
@@ -310,7 +435,20 @@ func (e *Engine) buildFeedbackPrompt(req FeedbackRequest) string {
 	basePrompt := fmt.Sprintf(`結果: %s
 問題: %s
 回答: %s
-正解: %s`, resultText, req.Problem.Description, req.UserAnswer, req.Problem.Options[req.Problem.CorrectAnswer])
+正解: %s
+解説の長さ: %s
+解説の表現: %s`, resultText, req.Problem.Description, req.UserAnswer, req.Problem.Options[req.Problem.CorrectAnswer], e.verbosityInstruction(), e.readingLevelInstruction())
+
+	if instruction := rationaleInstruction(req.Rationale); instruction != "" {
+		basePrompt += "\n" + instruction
+	}
+
+	// 関連付け説明（得意な科目や身近な例へのたとえ）を使うときは、回答形式にたとえの欄を加える
+	analogyField := ""
+	if instruction := e.analogyInstruction(req.StudyContext); instruction != "" {
+		basePrompt += "\n関連付け: " + instruction
+		analogyField = "\nANALOGY: 得意な科目や身近な例へのたとえ（合わなければ空）"
+	}
 
 	if isMathProblem {
 		return basePrompt + `
@@ -324,7 +462,7 @@ CALCULATION: 段階的計算過程（必須）
 EXPLANATION: 数学的根拠と解説
 ENCOURAGEMENT: 励まし
 NEXT_STEPS: 次のステップ
-TIP: 数学のコツ
+TIP: 数学のコツ` + analogyField + `
 
 例）二等辺三角形で角A=角C=60度の場合:
 CALCULATION: 角A + 角B + 角C = 180度, 60度 + 角B + 60度 = 180度, 角B = 180度 - 120度 = 60度
@@ -340,24 +478,56 @@ MESSAGE: メッセージ
 EXPLANATION: 解説
 ENCOURAGEMENT: 励まし
 NEXT_STEPS: 次のステップ
-TIP: コツ
+TIP: コツ` + analogyField + `
 
 上記形式のみで回答。`
 }
 
-// generate Ollama APIを使用してテキスト生成
+// generate テキスト生成（ローカルのOllamaが使えないときは、保護者が有効にしたクラウドAIを使用）
+// 生徒の名前などの個人情報はAIに送る前に仮名に置き換え、応答では元に戻す
 func (e *Engine) generate(ctx context.Context, prompt string) (string, error) {
+	response, _, err := e.generateWithModel(ctx, prompt)
+	return response, err
+}
+
+// generateWithModel テキスト生成（生成したモデルも返す。予備のモデル・クラウドAIを使ったときはそのモデル）
+func (e *Engine) generateWithModel(ctx context.Context, prompt string) (string, string, error) {
+	prompt = e.redactor.Redact(prompt)
+
+	response, model, err := e.generateFallback(ctx, prompt)
+	if err != nil && ctx.Err() == nil && e.cloudEnabled() {
+		var cloudErr error
+		if response, cloudErr = e.generateCloud(ctx, prompt); cloudErr != nil {
+			return "", "", fmt.Errorf("%w（クラウドAI: %w）", err, cloudErr)
+		}
+		model, err = e.cloudModelName(), nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return e.redactor.Restore(response), model, nil
+}
+
+// generateOllama Ollama APIを使用して、設定したモデルでテキスト生成
+func (e *Engine) generateOllama(ctx context.Context, prompt string) (string, error) {
+	return e.generateOllamaModel(ctx, e.GetCurrentModel(), prompt)
+}
+
+// generateOllamaModel Ollama APIを使用して、指定したモデルでテキスト生成
+func (e *Engine) generateOllamaModel(ctx context.Context, model, prompt string) (string, error) {
+	return e.generateOllamaImages(ctx, model, prompt, nil)
+}
+
+// generateOllamaImages Ollama APIを使用して、指定したモデルで画像（base64）つきのテキスト生成（画像がなければ文章だけ）
+func (e *Engine) generateOllamaImages(ctx context.Context, model, prompt string, images []string) (string, error) {
+	options, keepAlive := e.ollamaOptions()
 	reqBody := OllamaRequest{
-		Model:  e.config.Model,
-		Prompt: prompt,
-		Stream: true, // 500エラー解決: ストリーミングモード使用
-		Options: map[string]interface{}{
-			"temperature": 0.7,  // 日本語モデル最適値
-			"top_p":       0.9,  // 多様性バランス
-			"top_k":       40,   // 選択肢制限
-			"num_predict": 512,  // 処理時間短縮用制限
-			"num_ctx":     8192, // コンテキスト長
-		},
+		Model:     model,
+		Prompt:    prompt,
+		Stream:    true, // 500エラー解決: ストリーミングモード使用
+		Options:   options,
+		KeepAlive: keepAlive,
+		Images:    images,
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -365,7 +535,7 @@ func (e *Engine) generate(ctx context.Context, prompt string) (string, error) {
 		return "", fmt.Errorf("リクエスト作成エラー: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", e.config.OllamaURL+"/api/generate", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", e.ollamaURL()+"/api/generate", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return "", fmt.Errorf("HTTPリクエスト作成エラー: %w", err)
 	}
@@ -386,6 +556,7 @@ func (e *Engine) generate(ctx context.Context, prompt string) (string, error) {
 	// ストリーミングレスポンス処理（NDJSON形式）
 	scanner := bufio.NewScanner(resp.Body)
 	var fullResponse strings.Builder
+	reporter := e.newStreamReporter(ctx)
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -404,9 +575,11 @@ func (e *Engine) generate(ctx context.Context, prompt string) (string, error) {
 
 		// レスポンステキストを蓄積
 		fullResponse.WriteString(ollamaResp.Response)
+		reporter.add(fullResponse.String())
 
 		// 生成完了チェック
 		if ollamaResp.Done {
+			e.recordMetrics(model, ollamaResp)
 			break
 		}
 	}
@@ -414,10 +587,30 @@ func (e *Engine) generate(ctx context.Context, prompt string) (string, error) {
 	if err := scanner.Err(); err != nil {
 		return "", fmt.Errorf("ストリーミング読み取りエラー: %w", err)
 	}
+	reporter.finish(fullResponse.String())
 
 	return strings.TrimSpace(fullResponse.String()), nil
 }
 
+// ollamaOptions 設定から、Ollamaの生成オプションとモデルをメモリに残す時間を決める
+func (e *Engine) ollamaOptions() (map[string]interface{}, string) {
+	numPredict := e.outputTokens() // 説明の詳しさと解説欄の大きさで決める
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	numCtx := e.config.ContextLength
+	if numCtx <= 0 {
+		numCtx = config.DefaultContextLength
+	}
+	return map[string]interface{}{
+		"temperature": e.config.Temperature,
+		"top_p":       e.config.TopP,
+		"top_k":       40, // 選択肢制限
+		"num_predict": numPredict,
+		"num_ctx":     numCtx,
+	}, e.config.KeepAlive
+}
+
 // parseProblemResponse 問題生成レスポンスをパース
 func (e *Engine) parseProblemResponse(response string) (*Problem, error) {
 	// キー:値形式でパース
@@ -427,20 +620,16 @@ func (e *Engine) parseProblemResponse(response string) (*Problem, error) {
 	}
 
 	problem := &Problem{
-		Title:       getField(fields, "TITLE", ""),
-		Description: getField(fields, "DESCRIPTION", ""),
-		Options: []string{
-			getField(fields, "OPTION1", ""),
-			getField(fields, "OPTION2", ""),
-			getField(fields, "OPTION3", ""),
-			getField(fields, "OPTION4", ""),
-		},
+		Title:         getField(fields, "TITLE", ""),
+		Description:   getField(fields, "DESCRIPTION", ""),
+		Options:       parseOptions(fields),
 		CorrectAnswer: parseInt(getField(fields, "CORRECT", "1")) - 1, // 1-indexedから0-indexedに変換
 		Explanation:   getField(fields, "EXPLANATION", ""),
 		Difficulty:    parseInt(getField(fields, "DIFFICULTY", "3")),
 		EstimatedTime: parseInt(getField(fields, "TIME", "300")),
 		Encouragement: getField(fields, "ENCOURAGEMENT", ""),
 		ProblemType:   getField(fields, "TYPE", ""),
+		Audio:         getField(fields, "AUDIO", ""),
 	}
 
 	// 必須フィールドの検証
@@ -462,9 +651,11 @@ func (e *Engine) parseFeedbackResponse(response string) (*FeedbackResponse, erro
 	feedback := &FeedbackResponse{
 		Message:       getField(fields, "MESSAGE", ""),
 		Explanation:   getField(fields, "EXPLANATION", ""),
+		Calculation:   getField(fields, "CALCULATION", ""),
 		Encouragement: getField(fields, "ENCOURAGEMENT", ""),
 		NextSteps:     getField(fields, "NEXT_STEPS", ""),
 		TipOfDay:      getField(fields, "TIP", ""),
+		Analogy:       getField(fields, "ANALOGY", ""),
 	}
 
 	return feedback, nil
@@ -533,6 +724,12 @@ func parseInt(value string) int {
 	return 0 // デフォルト値
 }
 
+// forbiddenPhrases 数学問題で使わせない、問題文には存在しない資料への言及
+var forbiddenPhrases = []string{
+	"次の文中から", "下の図", "以下の文", "次の文字は", "次の単語は",
+	"次の数式は", "次の図", "次の表は", "次の資料",
+}
+
 // validateProblem 問題の妥当性チェック（数学的正確性検証を含む）
 func validateProblem(problem *Problem) error {
 	if problem.Title == "" {
@@ -541,12 +738,18 @@ func validateProblem(problem *Problem) error {
 	if problem.Description == "" {
 		return fmt.Errorf("問題文が空です")
 	}
-	if len(problem.Options) < 2 {
-		return fmt.Errorf("選択肢が不足しています（最低2つ必要）")
+	if len(problem.Options) < MinOptions {
+		return fmt.Errorf("選択肢が不足しています（最低%dつ必要）", MinOptions)
+	}
+	if len(problem.Options) > MaxOptions {
+		return fmt.Errorf("選択肢が多すぎます（最大%d個）", MaxOptions)
 	}
 	if problem.CorrectAnswer < 0 || problem.CorrectAnswer >= len(problem.Options) {
 		return fmt.Errorf("正解インデックスが無効です")
 	}
+	if err := validateOptions(problem.Options); err != nil {
+		return err
+	}
 	if problem.Difficulty < 1 || problem.Difficulty > 5 {
 		return fmt.Errorf("難易度が範囲外です（1-5）")
 	}
@@ -565,35 +768,56 @@ func validateProblem(problem *Problem) error {
 		strings.Contains(problem.Description, "="); isMathProblem {
 		
 		// 架空資料参照の禁止チェック
-		forbiddenPhrases := []string{
-			"次の文中から", "下の図", "以下の文", "次の文字は", "次の単語は", 
-			"次の数式は", "次の図", "次の表は", "次の資料",
-		}
 		for _, phrase := range forbiddenPhrases {
 			if strings.Contains(problem.Description, phrase) {
 				return fmt.Errorf("数学問題で架空資料への参照が検出されました: %s", phrase)
 			}
 		}
 
-		// 二等辺三角形の角度問題の検証例
-		if strings.Contains(problem.Description, "二等辺三角形") && strings.Contains(problem.Description, "角") {
-			if err := validateIsoscelesTriangleProblem(problem); err != nil {
-				return fmt.Errorf("二等辺三角形問題の数学的エラー: %w", err)
-			}
+		// 方程式・計算・三角形の角の問題は、実際に計算して正解を確認
+		if err := verifyMathAnswer(problem); err != nil {
+			return fmt.Errorf("数学的エラー: %w", err)
 		}
 	}
 
 	return nil
 }
 
-// validateIsoscelesTriangleProblem 二等辺三角形問題の数学的正確性を検証
-func validateIsoscelesTriangleProblem(problem *Problem) error {
-	// 簡単な検証例：角A=角C=45度で角B=90度の場合
-	if strings.Contains(problem.Description, "45度") && strings.Contains(problem.Description, "角B") {
-		correctAnswer := problem.Options[problem.CorrectAnswer]
-		if !strings.Contains(correctAnswer, "90") {
-			return fmt.Errorf("二等辺三角形で角A=角C=45度の場合、角B=90度が正解ですが、設定された正解は %s です", correctAnswer)
+// validateOptions 選択肢が空でなく、重複していないか確認
+func validateOptions(options []string) error {
+	seen := make(map[string]int)
+	for i, option := range options {
+		normalized := strings.Join(strings.Fields(option), "")
+		if normalized == "" {
+			return fmt.Errorf("選択肢%dが空です", i+1)
+		}
+		if j, exists := seen[normalized]; exists {
+			return fmt.Errorf("選択肢%dと選択肢%dが同じです: %s", j+1, i+1, option)
 		}
+		seen[normalized] = i
+	}
+	return nil
+}
+
+// verifyMathAnswer 計算で求めた答えと正解の選択肢を比べ、正解がちょうど1つか確認
+func verifyMathAnswer(problem *Problem) error {
+	correctAnswer := problem.Options[problem.CorrectAnswer]
+	check := mathcheck.VerifyAnswer(problem.Description, correctAnswer)
+	var matching []int
+	for i, option := range problem.Options {
+		if mathcheck.VerifyAnswer(problem.Description, option).Verdict == mathcheck.Correct {
+			matching = append(matching, i+1)
+		}
+	}
+
+	switch {
+	case check.Verdict == mathcheck.Incorrect && len(matching) == 1:
+		return fmt.Errorf("計算した答えは %s で選択肢%dですが、設定された正解は選択肢%d（%s）です",
+			check.Expected, matching[0], problem.CorrectAnswer+1, correctAnswer)
+	case check.Verdict == mathcheck.Incorrect:
+		return fmt.Errorf("計算した答えは %s ですが、設定された正解は %s で、正しい答えが選択肢にありません", check.Expected, correctAnswer)
+	case len(matching) > 1:
+		return fmt.Errorf("正解になる選択肢が複数あります: %v", matching)
 	}
 	return nil
 }
@@ -613,38 +837,17 @@ func containsJapanese(text string) bool {
 
 // GetAvailableModels 利用可能なモデル一覧を取得
 func (e *Engine) GetAvailableModels(ctx context.Context) ([]string, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", e.config.OllamaURL+"/api/tags", nil)
-	if err != nil {
-		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
-	}
-
-	resp, err := e.httpClient.Do(req)
+	models, err := e.ListModels(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("HTTPリクエストエラー: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("レスポンス読み取りエラー: %w", err)
-	}
-
-	var result struct {
-		Models []struct {
-			Name string `json:"name"`
-		} `json:"models"`
+		return nil, err
 	}
 
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	names := make([]string, len(models))
+	for i, model := range models {
+		names[i] = model.Name
 	}
 
-	models := make([]string, len(result.Models))
-	for i, model := range result.Models {
-		models[i] = model.Name
-	}
-
-	return models, nil
+	return names, nil
 }
 
 // UpdateConfig AI設定を更新
@@ -659,10 +862,12 @@ func (e *Engine) UpdateConfig(newConfig config.AIConfig) error {
 
 // GetCurrentModel 現在使用中のモデルを取得
 func (e *Engine) GetCurrentModel() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	return e.config.Model
 }
 
-// GenerateStudyTip 学習のコツを生成
+// GenerateStudyTip 学習のコツを生成（同じ教科・苦手分野のコツは保存したものを使う）
 func (e *Engine) GenerateStudyTip(ctx context.Context, subject string, weakness string) (string, error) {
 	prompt := fmt.Sprintf(`
 中学生向けの学習アドバイザーとして、以下の情報に基づいて具体的で実践的な学習のコツを1つ提供してください。
@@ -680,16 +885,603 @@ func (e *Engine) GenerateStudyTip(ctx context.Context, subject string, weakness
 一つの学習のコツのみを返してください。
 `, subject, weakness)
 
-	return e.generate(ctx, prompt)
+	response, _, err := e.generateCached(ctx, cacheKindStudyTip, prompt, studyTipCacheTTL)
+	return response, err
+}
+
+// offlineSlowDownMessage オフライン時の「問題をよく読もう」メッセージ
+const offlineSlowDownMessage = "あせらなくて大丈夫だよ。問題文を最後までゆっくり読んでから答えてみよう。じっくり考えた1問が、いちばん力になるよ。"
+
+// GenerateSlowDownMessage 当てずっぽうの連続解答に対して、問題をよく読むよう優しく促すメッセージを生成（オフライン対応）
+func (e *Engine) GenerateSlowDownMessage(ctx context.Context, subject string, grade int) string {
+	if !e.shouldTryAI() {
+		return offlineSlowDownMessage
+	}
+
+	prompt := fmt.Sprintf(`中学%d年生が%sの問題を、問題文を読まずに数秒で次々と答えてしまい、ほとんど間違えています。
+本人を責めずに、問題文をゆっくり読んで考えることをすすめる短いメッセージを作成。
+
+【重要な制約】
+- 叱らない、責めない、前向きな表現
+- 「連打」「ずる」などの否定的な言葉は使わない
+- 60文字以内の日本語で1〜2文
+
+メッセージのみを回答。`, grade, subject)
+
+	response, err := e.generate(ctx, prompt)
+	if err != nil {
+		e.recordFailure()
+		return offlineSlowDownMessage
+	}
+	e.recordSuccess()
+
+	message := strings.TrimSpace(response)
+	if message == "" || !containsJapanese(message) {
+		return offlineSlowDownMessage
+	}
+	return message
+}
+
+// maxTakeaways 1セッションから作る復習カードの数
+const maxTakeaways = 3
+
+// Takeaway セッションの解説から作った1行の要点と一問一答
+type Takeaway struct {
+	Point    string
+	Question string
+	Answer   string
+}
+
+// GenerateTakeaways セッションで解いた問題の解説を1行の要点と一問一答にまとめる（オフライン対応）
+func (e *Engine) GenerateTakeaways(ctx context.Context, subject string, grade int, problems []Problem) []Takeaway {
+	offline := offlineTakeaways(problems)
+	if len(problems) == 0 || !e.shouldTryAI() {
+		return offline
+	}
+
+	var problemLines strings.Builder
+	for i, problem := range problems {
+		correct := ""
+		if problem.CorrectAnswer >= 0 && problem.CorrectAnswer < len(problem.Options) {
+			correct = problem.Options[problem.CorrectAnswer]
+		}
+		fmt.Fprintf(&problemLines, "%d. 問題: %s\n   正解: %s\n   解説: %s\n", i+1, problem.Description, correct, problem.Explanation)
+	}
+
+	prompt := fmt.Sprintf(`中学%d年生が%sで解いた問題と解説です。翌日の復習用に、大事なポイントを%dつにまとめてください。
+
+【解いた問題】
+%s
+【重要な制約】
+- 上記の解説にある内容のみを使うこと
+- TAKEAWAYは30文字以内の1行の要点
+- QUESTIONは要点を思い出せる短い問い、ANSWERはその答え（15文字以内）
+
+形式:
+TAKEAWAY1: 要点
+QUESTION1: 問い
+ANSWER1: 答え
+（2、3も同じ形式）
+
+上記形式のみで回答。`, grade, subject, maxTakeaways, problemLines.String())
+
+	response, err := e.generate(ctx, prompt)
+	if err != nil {
+		e.recordFailure()
+		return offline
+	}
+	e.recordSuccess()
+
+	fields := parseKeyValueResponse(response)
+	var takeaways []Takeaway
+	for i := 1; i <= maxTakeaways; i++ {
+		takeaway := Takeaway{
+			Point:    getField(fields, fmt.Sprintf("TAKEAWAY%d", i), ""),
+			Question: getField(fields, fmt.Sprintf("QUESTION%d", i), ""),
+			Answer:   getField(fields, fmt.Sprintf("ANSWER%d", i), ""),
+		}
+		if takeaway.Point == "" || takeaway.Question == "" || takeaway.Answer == "" {
+			continue
+		}
+		takeaways = append(takeaways, takeaway)
+	}
+	if len(takeaways) == 0 {
+		return offline
+	}
+	return takeaways
+}
+
+// offlineTakeaways オフライン時の復習カード（直近の問題の解説の1文目と問題・正解をそのまま使う）
+func offlineTakeaways(problems []Problem) []Takeaway {
+	var takeaways []Takeaway
+	for i := len(problems) - 1; i >= 0 && len(takeaways) < maxTakeaways; i-- {
+		problem := problems[i]
+		if problem.CorrectAnswer < 0 || problem.CorrectAnswer >= len(problem.Options) {
+			continue
+		}
+
+		point, _, _ := strings.Cut(strings.TrimSpace(problem.Explanation), "。")
+		if point == "" {
+			point = problem.Title
+		}
+		takeaways = append(takeaways, Takeaway{
+			Point:    point,
+			Question: problem.Description,
+			Answer:   problem.Options[problem.CorrectAnswer],
+		})
+	}
+	return takeaways
+}
+
+// maxSolutionSteps 順番に見る解説のステップ数の上限
+const maxSolutionSteps = 8
+
+// GenerateSolutionSteps 数学の問題の解き方を順番に見られるステップに分ける（オフライン時は解説を1文ずつに分ける）
+func (e *Engine) GenerateSolutionSteps(ctx context.Context, problem Problem, userAnswer string) []string {
+	offline := offlineSolutionSteps(problem)
+	if !e.shouldTryAI() {
+		return offline
+	}
+
+	correct := ""
+	if problem.CorrectAnswer >= 0 && problem.CorrectAnswer < len(problem.Options) {
+		correct = problem.Options[problem.CorrectAnswer]
+	}
+	prompt := fmt.Sprintf(`中学生が間違えた数学の問題です。解き方を、1つずつ順番に確認できるステップに分けてください。
+
+%s
+
+【重要な制約】
+%s
+- 1ステップでは1つの操作や考え方だけを書くこと（40文字程度）
+- 式変形や計算は途中式を省略せず、検算して正しいことを確かめること
+- 最後のステップで正解にたどり着くこと
+- ステップは%d個以内
+
+形式:
+STEP1: 最初にすること
+STEP2: 次にすること
+（必要な数だけ続ける）
+
+上記形式のみで回答。`,
+		fenceContent(fmt.Sprintf("問題: %s\n正解: %s\n生徒の解答: %s\n解説: %s", problem.Description, correct, userAnswer, problem.Explanation)),
+		fencedContentRule, maxSolutionSteps)
+
+	response, err := e.generate(ctx, prompt)
+	if err != nil {
+		e.recordFailure()
+		return offline
+	}
+	e.recordSuccess()
+
+	fields := parseKeyValueResponse(response)
+	var steps []string
+	for i := 1; i <= maxSolutionSteps; i++ {
+		step := getField(fields, fmt.Sprintf("STEP%d", i), "")
+		if step == "" {
+			break
+		}
+		steps = append(steps, step)
+	}
+	if len(steps) == 0 {
+		return offline
+	}
+	if err := validateGuardedOutput(problem.Description+problem.Explanation, steps...); err != nil {
+		slog.Warn("解き方のステップを破棄", "error", err)
+		return offline
+	}
+	return steps
+}
+
+// offlineSolutionSteps オフライン時のステップ（問題の解説を1文ずつに分ける）
+func offlineSolutionSteps(problem Problem) []string {
+	var steps []string
+	for _, line := range strings.Split(problem.Explanation, "\n") {
+		for _, sentence := range strings.SplitAfter(line, "。") {
+			if sentence = strings.TrimSpace(sentence); sentence != "" {
+				steps = append(steps, sentence)
+			}
+		}
+	}
+	if problem.CorrectAnswer >= 0 && problem.CorrectAnswer < len(problem.Options) {
+		steps = append(steps, fmt.Sprintf("答えは「%s」です。", problem.Options[problem.CorrectAnswer]))
+	}
+	return steps
+}
+
+// CaptureRequest アプリの外で見つけた問題の解説要求
+type CaptureRequest struct {
+	Question   string
+	Subject    string // 空なら問題文から判断
+	Grade      int
+	Boundaries config.TutorBoundaries // 保護者が決めた、聞ける内容の範囲
+	StepByStep bool                   // 解説を番号つきの手順に分けて書く（写真で取り込んだ問題など）
+}
+
+// CaptureExplanation アプリの外で見つけた問題の解説
+type CaptureExplanation struct {
+	Title       string
+	Answer      string
+	Explanation string
+	Topic       string       // 問題の単元（わからなければ空）
+	Subject     string       // AIが判断した教科（わからなければ空）
+	Offline     bool         // AIを使えず解説を作れなかった
+	Refused     *BoundaryHit // 範囲外の質問として答えなかった（答えたならnil）
+}
+
+// offlineCaptureExplanation オフライン時の解説
+const offlineCaptureExplanation = "今はAIに接続できないため、解説を作れませんでした。問題は保存できるので、あとで先生や友だちに聞いてみましょう。"
+
+// ExplainCapturedQuestion アプリの外で見つけた問題を解いて解説（オフライン対応）
+func (e *Engine) ExplainCapturedQuestion(ctx context.Context, req CaptureRequest) *CaptureExplanation {
+	offline := &CaptureExplanation{Explanation: offlineCaptureExplanation, Offline: true}
+	if strings.TrimSpace(req.Question) == "" {
+		return offline
+	}
+	if hit := ClassifyQuestion(req.Boundaries, req.Question, req.Subject); hit != nil {
+		return refusedExplanation(hit)
+	}
+	if !e.shouldTryAI() {
+		return offline
+	}
+
+	subject := req.Subject
+	if subject == "" {
+		subject = "（問題文から判断）"
+	}
+	prompt := fmt.Sprintf(`中学%d年生が宿題などで見つけた問題です。正しい答えと、中学生にわかる解説を作成してください。
+
+【教科】%s
+【問題】
+%s
+
+【重要な制約】
+%s
+- 問題文にない資料や図を勝手に想定しないこと。情報が足りない場合はEXPLANATIONでそう伝える
+- 計算問題は段階的に計算し、検算してから答えること
+- 答えを教えるだけでなく、考え方がわかる解説にすること%s%s
+
+形式:
+TITLE: 問題の短いタイトル
+SUBJECT: 教科名
+TOPIC: 単元名
+ANSWER: 答え
+EXPLANATION: 解説
+
+上記形式のみで回答。`, req.Grade, subject, fenceContent(req.Question), fencedContentRule, stepByStepInstruction(req.StepByStep), boundaryInstruction(req.Boundaries))
+
+	response, err := e.generate(ctx, prompt)
+	if err != nil {
+		e.recordFailure()
+		return offline
+	}
+	e.recordSuccess()
+
+	fields := parseKeyValueResponse(response)
+	explanation := &CaptureExplanation{
+		Title:       getField(fields, "TITLE", ""),
+		Answer:      getField(fields, "ANSWER", ""),
+		Explanation: getField(fields, "EXPLANATION", ""),
+		Topic:       getField(fields, "TOPIC", ""),
+		Subject:     getField(fields, "SUBJECT", ""),
+	}
+	if hit := checkAnswerBoundary(req.Boundaries, explanation.Subject, explanation.Title, explanation.Answer, explanation.Explanation); hit != nil {
+		slog.Info("クイック質問の範囲外の解説を表示しません", "category", hit.Category)
+		return refusedExplanation(hit)
+	}
+	if explanation.Answer == "" && explanation.Explanation == "" {
+		return offline
+	}
+	if err := validateGuardedOutput(req.Question, explanation.Title, explanation.Answer, explanation.Explanation, explanation.Topic); err != nil {
+		slog.Warn("クイック質問の解説を破棄", "error", err)
+		return offline
+	}
+	return explanation
+}
+
+// 単語カードの種類
+const (
+	FlashcardVocab = "vocab" // 英単語
+	FlashcardKanji = "kanji" // 漢字
+)
+
+// FlashcardRequest 単語カード生成要求
+type FlashcardRequest struct {
+	Kind       string // FlashcardVocab | FlashcardKanji
+	Grade      int
+	Weaknesses []string // 苦手な単元（カードの題材に優先して使う）
+	Exclude    []string // すでにあるカードの表面（重複を避ける）
+	Count      int
+}
+
+// FlashcardContent 単語カードの内容
+type FlashcardContent struct {
+	Front string // 表面（英単語・漢字）
+	Back  string // 裏面（意味・読み）
+	Hint  string // 例文など
+}
+
+// GenerateFlashcards 学年と苦手な単元に合わせた英単語・漢字のカードを生成（オフライン対応）
+func (e *Engine) GenerateFlashcards(ctx context.Context, req FlashcardRequest) []FlashcardContent {
+	offline := offlineFlashcards(req)
+	if !e.shouldTryAI() {
+		return offline
+	}
+
+	gradeText := []string{"", "中1", "中2", "中3"}
+	grade := ""
+	if req.Grade >= 1 && req.Grade <= 3 {
+		grade = gradeText[req.Grade]
+	}
+	weaknesses := "特になし"
+	if len(req.Weaknesses) > 0 {
+		weaknesses = strings.Join(req.Weaknesses, "、")
+	}
+	exclude := "なし"
+	if len(req.Exclude) > 0 {
+		exclude = strings.Join(req.Exclude, "、")
+	}
+
+	instruction := `英単語カードを作成。
+- FRONT: 教科書に出てくる英単語
+- BACK: 日本語の意味（品詞も）
+- HINT: その単語を使った短い英文と和訳`
+	if req.Kind == FlashcardKanji {
+		instruction = `漢字カードを作成。
+- FRONT: 学年で習う漢字を使った熟語
+- BACK: ひらがなの読みと意味
+- HINT: その熟語を使った短い例文`
+	}
+
+	prompt := fmt.Sprintf(`%s生向けの%s
+
+【条件】
+- 苦手な単元: %s（関係する語を優先）
+- 次の語は作らない: %s
+- %d枚作成
+
+形式:
+FRONT1: 表面
+BACK1: 裏面
+HINT1: 例文
+（2枚目以降も同じ形式で番号を増やす）
+
+上記形式のみで回答。`, grade, instruction, weaknesses, exclude, req.Count)
+
+	response, err := e.generate(ctx, prompt)
+	if err != nil {
+		e.recordFailure()
+		return offline
+	}
+	e.recordSuccess()
+
+	fields := parseKeyValueResponse(response)
+	var cards []FlashcardContent
+	for i := 1; i <= req.Count; i++ {
+		card := FlashcardContent{
+			Front: getField(fields, fmt.Sprintf("FRONT%d", i), ""),
+			Back:  getField(fields, fmt.Sprintf("BACK%d", i), ""),
+			Hint:  getField(fields, fmt.Sprintf("HINT%d", i), ""),
+		}
+		if card.Front == "" || card.Back == "" {
+			continue
+		}
+		cards = append(cards, card)
+	}
+	if len(cards) == 0 {
+		return offline
+	}
+	return cards
+}
+
+// offlineFlashcardSets オフライン時の単語カード（種類・学年別）
+var offlineFlashcardSets = map[string]map[int][]FlashcardContent{
+	FlashcardVocab: {
+		1: {
+			{Front: "library", Back: "図書館（名詞）", Hint: "I study in the library. 私は図書館で勉強します。"},
+			{Front: "often", Back: "よく、しばしば（副詞）", Hint: "I often play tennis. 私はよくテニスをします。"},
+			{Front: "breakfast", Back: "朝食（名詞）", Hint: "I eat breakfast at seven. 私は7時に朝食を食べます。"},
+			{Front: "favorite", Back: "お気に入りの（形容詞）", Hint: "My favorite subject is math. 私の好きな教科は数学です。"},
+			{Front: "practice", Back: "練習する（動詞）", Hint: "We practice soccer every day. 私たちは毎日サッカーを練習します。"},
+		},
+		2: {
+			{Front: "remember", Back: "覚えている、思い出す（動詞）", Hint: "I remember his name. 私は彼の名前を覚えています。"},
+			{Front: "important", Back: "重要な（形容詞）", Hint: "This is an important test. これは大切なテストです。"},
+			{Front: "decide", Back: "決める（動詞）", Hint: "I decided to study abroad. 私は留学することに決めました。"},
+			{Front: "culture", Back: "文化（名詞）", Hint: "I am interested in Japanese culture. 私は日本の文化に興味があります。"},
+			{Front: "borrow", Back: "借りる（動詞）", Hint: "Can I borrow your pen? ペンを借りてもいいですか。"},
+		},
+		3: {
+			{Front: "environment", Back: "環境（名詞）", Hint: "We must protect the environment. 私たちは環境を守らなければなりません。"},
+			{Front: "experience", Back: "経験（名詞）、経験する（動詞）", Hint: "It was a good experience. それはよい経験でした。"},
+			{Front: "recently", Back: "最近（副詞）", Hint: "I have been busy recently. 最近忙しいです。"},
+			{Front: "communicate", Back: "意思を伝え合う（動詞）", Hint: "We communicate in English. 私たちは英語でやりとりします。"},
+			{Front: "although", Back: "〜だけれども（接続詞）", Hint: "Although it was raining, we went out. 雨が降っていたけれど、出かけました。"},
+		},
+	},
+	FlashcardKanji: {
+		1: {
+			{Front: "観察", Back: "かんさつ：物事をよく見て調べること", Hint: "アサガオの成長を観察する。"},
+			{Front: "規則", Back: "きそく：守るべき決まり", Hint: "学校の規則を守る。"},
+			{Front: "貿易", Back: "ぼうえき：外国と品物の売り買いをすること", Hint: "日本は多くの国と貿易をしている。"},
+			{Front: "郷土", Back: "きょうど：生まれ育った土地", Hint: "郷土の歴史を調べる。"},
+			{Front: "穏やか", Back: "おだやか：静かで落ち着いている様子", Hint: "穏やかな天気が続く。"},
+		},
+		2: {
+			{Front: "抑揚", Back: "よくよう：声の調子の上げ下げ", Hint: "抑揚をつけて音読する。"},
+			{Front: "顕著", Back: "けんちょ：はっきりと目立つこと", Hint: "効果が顕著に表れた。"},
+			{Front: "循環", Back: "じゅんかん：ひと回りして元にもどることをくり返すこと", Hint: "血液が体内を循環する。"},
+			{Front: "把握", Back: "はあく：しっかり理解すること", Hint: "状況を把握する。"},
+			{Front: "謙虚", Back: "けんきょ：ひかえめで素直な様子", Hint: "謙虚な態度で学ぶ。"},
+		},
+		3: {
+			{Front: "概要", Back: "がいよう：全体のあらまし", Hint: "計画の概要を説明する。"},
+			{Front: "普遍", Back: "ふへん：すべてのものに共通すること", Hint: "普遍的な真理を探る。"},
+			{Front: "矛盾", Back: "むじゅん：つじつまが合わないこと", Hint: "話の内容に矛盾がある。"},
+			{Front: "貢献", Back: "こうけん：役に立つように力をつくすこと", Hint: "地域社会に貢献する。"},
+			{Front: "懸念", Back: "けねん：気にかかって不安に思うこと", Hint: "天候の悪化が懸念される。"},
+		},
+	},
+}
+
+// offlineFlashcards オフライン時の単語カード（すでにあるカードは除く）
+func offlineFlashcards(req FlashcardRequest) []FlashcardContent {
+	var cards []FlashcardContent
+	for _, card := range offlineFlashcardSets[req.Kind][req.Grade] {
+		if len(cards) >= req.Count {
+			break
+		}
+		if !slices.Contains(req.Exclude, card.Front) {
+			cards = append(cards, card)
+		}
+	}
+	return cards
+}
+
+// GenerateWeeklySummary 週間レポートの要約を生成（オフライン対応）
+func (e *Engine) GenerateWeeklySummary(ctx context.Context, req WeeklySummaryRequest) (*WeeklySummary, error) {
+	if !e.shouldTryAI() {
+		return e.generateOfflineWeeklySummary(req), nil
+	}
+
+	prompt := e.buildWeeklySummaryPrompt(req)
+	response, err := e.generate(ctx, prompt)
+	if err != nil {
+		e.recordFailure()
+		return e.generateOfflineWeeklySummary(req), nil
+	}
+
+	e.recordSuccess()
+
+	fields := parseKeyValueResponse(response)
+	if len(fields) == 0 {
+		return e.generateOfflineWeeklySummary(req), nil
+	}
+
+	offline := e.generateOfflineWeeklySummary(req)
+	return &WeeklySummary{
+		Summary:    getField(fields, "SUMMARY", offline.Summary),
+		Strengths:  getField(fields, "STRENGTHS", offline.Strengths),
+		Weaknesses: getField(fields, "WEAKNESSES", offline.Weaknesses),
+		Advice:     getField(fields, "ADVICE", offline.Advice),
+	}, nil
+}
+
+// buildWeeklySummaryPrompt 週間レポート要約プロンプト
+func (e *Engine) buildWeeklySummaryPrompt(req WeeklySummaryRequest) string {
+	var subjectLines strings.Builder
+	for _, subject := range req.SubjectResults {
+		fmt.Fprintf(&subjectLines, "- %s: %d問, 正解率%.0f%%, 学習時間%d分\n",
+			subject.Subject, subject.ProblemsCount, subject.AccuracyRate*100, subject.StudyTime/60)
+	}
+
+	strengths := "なし"
+	if len(req.Strengths) > 0 {
+		strengths = strings.Join(req.Strengths, "、")
+	}
+	weaknesses := "なし"
+	if len(req.Weaknesses) > 0 {
+		weaknesses = strings.Join(req.Weaknesses, "、")
+	}
+
+	focus := "記録なし"
+	if req.FocusNote != "" {
+		focus = req.FocusNote
+	}
+
+	gradeText := []string{"", "中1", "中2", "中3"}
+	grade := ""
+	if req.Grade >= 1 && req.Grade <= 3 {
+		grade = gradeText[req.Grade]
+	}
+
+	return fmt.Sprintf(`%s生の1週間の学習記録から、本人と保護者向けの週間レポートを作成。
+
+【今週の記録】
+- 学習日数: %d日
+- 学習時間: %d分（うちアプリ外の学習 %d分）
+- 解答数: %d問
+- 正解率: %.0f%%
+- 集中度: %s
+- 得意な分野: %s
+- 苦手な分野: %s
+
+【科目別】
+%s
+【重要な制約】
+- 上記の記録にある事実のみを使うこと
+- 前向きで具体的な表現にすること
+- 各項目は2文以内
+
+形式:
+SUMMARY: 今週のまとめ
+STRENGTHS: よくできたこと
+WEAKNESSES: 課題
+ADVICE: 来週へのアドバイス
+
+上記形式のみで回答。`,
+		grade, req.StudyDays, req.StudyMinutes, req.ManualMinutes, req.TotalProblems, req.AccuracyRate*100,
+		focus, strengths, weaknesses, subjectLines.String())
+}
+
+// generateOfflineWeeklySummary オフライン時の週間レポート要約を生成
+func (e *Engine) generateOfflineWeeklySummary(req WeeklySummaryRequest) *WeeklySummary {
+	if req.TotalProblems == 0 && req.ManualMinutes > 0 {
+		return &WeeklySummary{
+			Summary:    fmt.Sprintf("今週は%d日、塾や紙のドリルなどで合計%d分学習しました。", req.StudyDays, req.StudyMinutes),
+			Strengths:  "アプリの外でもしっかり学習を続けられました。",
+			Weaknesses: "アプリでの問題演習はまだありません。",
+			Advice:     "学んだ内容をアプリの問題で確認してみましょう。",
+		}
+	}
+	if req.TotalProblems == 0 {
+		return &WeeklySummary{
+			Summary:    "今週はまだ学習記録がありません。",
+			Strengths:  "これから伸びしろがたくさんあります。",
+			Weaknesses: "学習する日を作ることが最初の課題です。",
+			Advice:     "まずは1日10分、好きな科目から始めてみましょう。",
+		}
+	}
+
+	summary := &WeeklySummary{
+		Summary: fmt.Sprintf("今週は%d日、合計%d分学習し、%d問に挑戦しました（正解率%.0f%%）。",
+			req.StudyDays, req.StudyMinutes, req.TotalProblems, req.AccuracyRate*100),
+		Strengths:  "コツコツ学習を続けられました。",
+		Weaknesses: "特に大きな課題はありません。",
+		Advice:     "来週も同じペースで続けていきましょう。",
+	}
+	if len(req.Strengths) > 0 {
+		summary.Strengths = fmt.Sprintf("%sがよくできています。", strings.Join(req.Strengths, "、"))
+	}
+	if len(req.Weaknesses) > 0 {
+		summary.Weaknesses = fmt.Sprintf("%sの正解率が低めです。", strings.Join(req.Weaknesses, "、"))
+		summary.Advice = fmt.Sprintf("来週は%sの基礎問題から復習しましょう。", req.Weaknesses[0])
+	}
+	return summary
 }
 
 // generateOfflineProblem オフライン時の代替問題を生成
 func (e *Engine) generateOfflineProblem(context StudyContext) *Problem {
+	if isFigure(context) {
+		return e.getFigureProblem(context.Subject, context.Grade)
+	}
+	// 内容パックで先生が配った問題があれば、内蔵の問題集より先に出題する
+	if !isListening(context) {
+		if problem := e.getOfflinePackProblem(context); problem != nil {
+			return problem
+		}
+		if problem := e.getOfflineBankProblem(context); problem != nil {
+			return problem
+		}
+	}
 	// 教科と学年に基づいてサンプル問題を提供
 	switch context.Subject {
 	case "数学", "算数":
 		return e.getMathProblem(context.Grade, context.Difficulty)
 	case "英語":
+		if isListening(context) {
+			return e.getListeningProblem(context.Grade)
+		}
 		return e.getEnglishProblem(context.Grade, context.Difficulty)
 	case "国語":
 		return e.getJapaneseProblem(context.Grade, context.Difficulty)
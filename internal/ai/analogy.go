@@ -0,0 +1,36 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetAnalogies 関連付け説明（得意な科目や身近な例へのたとえ）を使うかどうかを設定
+func (e *Engine) SetAnalogies(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.config.Analogies = enabled
+}
+
+// analogyInstruction 関連付け説明の書き方（使わないときは空）
+// 生徒の得意な科目（StudyContext.Strengths。解いている科目は除く）があればその科目に、なければ身近な例に結びつける
+func (e *Engine) analogyInstruction(context StudyContext) string {
+	e.mu.RLock()
+	enabled := e.config.Analogies
+	e.mu.RUnlock()
+	if !enabled {
+		return ""
+	}
+
+	var strengths []string
+	for _, subject := range context.Strengths {
+		if subject != context.Subject {
+			strengths = append(strengths, subject)
+		}
+	}
+	target := "ゲームの得点・部活動・買い物などの身近な例"
+	if len(strengths) > 0 {
+		target = fmt.Sprintf("生徒が得意な%s、またはゲームの得点などの身近な例", strings.Join(strengths, "・"))
+	}
+	return fmt.Sprintf("抽象的な考え方を説明するときは、%sに結びつけたたとえを1つ書くこと（例: 比例を「1体倒すごとに10点増えるゲームの得点」で説明する）。たとえは正確さを損なわないものにすること", target)
+}
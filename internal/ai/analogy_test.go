@@ -0,0 +1,40 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFeedbackAnalogy(t *testing.T) {
+	server, prompts := fakeOllama(t, "MESSAGE: 正解です！\nEXPLANATION: yはxに比例します\nANALOGY: 1体倒すごとに10点増えるゲームの得点と同じです")
+	engine := newTestEngine(t, server.URL)
+	engine.config.Cloud.Consent = false
+
+	req := FeedbackRequest{
+		Problem:      Problem{Description: "y = 3x のとき、yはxに比例しますか？", Options: []string{"する", "しない"}, CorrectAnswer: 0},
+		UserAnswer:   "する",
+		IsCorrect:    true,
+		StudyContext: StudyContext{Subject: "数学", Strengths: []string{"数学", "理科"}},
+	}
+	ctx := context.Background()
+	if _, err := engine.GenerateFeedback(ctx, req); err != nil {
+		t.Fatalf("フィードバック生成エラー: %v", err)
+	}
+	if got := prompts(); strings.Contains(got[0], "ANALOGY") {
+		t.Error("関連付け説明を使わないときは、たとえの欄を求めないはず")
+	}
+
+	engine.SetAnalogies(true)
+	feedback, err := engine.GenerateFeedback(ctx, req)
+	if err != nil {
+		t.Fatalf("フィードバック生成エラー: %v", err)
+	}
+	prompt := prompts()[1]
+	if !strings.Contains(prompt, "生徒が得意な理科") || strings.Contains(prompt, "得意な数学") || !strings.Contains(prompt, "ANALOGY:") {
+		t.Errorf("関連付け説明のプロンプト:\n%s", prompt)
+	}
+	if feedback.Analogy != "1体倒すごとに10点増えるゲームの得点と同じです" {
+		t.Errorf("Analogy = %q", feedback.Analogy)
+	}
+}
@@ -0,0 +1,115 @@
+package ai
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+)
+
+//go:embed offline_bank.json
+var offlineBankJSON []byte
+
+// loadOfflineBank 埋め込みの内蔵問題集を読み込む（初回だけ読み込み、以降は同じものを使う）
+var loadOfflineBank = sync.OnceValues(func() ([]OfflineProblem, error) {
+	var problems []OfflineProblem
+	if err := json.Unmarshal(offlineBankJSON, &problems); err != nil {
+		return nil, fmt.Errorf("内蔵問題集読み込みエラー: %w", err)
+	}
+	return problems, nil
+})
+
+// RotationStore 内蔵の問題を出題した位置の保存先（再起動しても、解いたばかりの問題から出題し直さないようにする）
+type RotationStore interface {
+	GetOfflineRotation(userID, key string) (int, error)
+	SaveOfflineRotation(userID, key string, position int) error
+}
+
+// SetRotationStore 内蔵の問題を出題した位置の保存先を設定
+func (e *Engine) SetRotationStore(store RotationStore) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rotationStore = store
+}
+
+// getOfflineBankProblem 内蔵問題集から、学年・科目・単元・難易度の合う問題を一巡するまで重ならない順に取得（なければnil）
+func (e *Engine) getOfflineBankProblem(context StudyContext) *Problem {
+	bank, err := loadOfflineBank()
+	if err != nil {
+		slog.Error("内蔵問題集の読み込みエラー", "error", err)
+		return nil
+	}
+
+	var problems []OfflineProblem
+	for _, p := range bank {
+		if p.Grade == context.Grade && p.Subject == context.Subject && (context.Topic == "" || p.Topic == context.Topic) {
+			problems = append(problems, p)
+		}
+	}
+	problems = filterDifficulty(problems, context.Difficulty)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	// 出題順は組み合わせごとに決まった並びにする（毎回同じ順で単元が偏らないよう、ファイルの順からは並べ替える）
+	key := fmt.Sprintf("bank_%s_%s_G%d_D%d", context.Subject, context.Topic, context.Grade, context.Difficulty)
+	order := rotationOrder(key, len(problems))
+	problem := problems[order[e.nextRotation(key, len(problems))]].Problem()
+	problem.Encouragement = "AIを使えないので、用意してある問題から出題しています。じっくり考えてみよう！"
+	return problem
+}
+
+// filterDifficulty 目標の難易度の前後1段階の問題にしぼる（合う問題がなければしぼらない）
+func filterDifficulty(problems []OfflineProblem, difficulty int) []OfflineProblem {
+	if difficulty <= 0 {
+		return problems
+	}
+	var filtered []OfflineProblem
+	for _, p := range problems {
+		if p.Difficulty >= difficulty-1 && p.Difficulty <= difficulty+1 {
+			filtered = append(filtered, p)
+		}
+	}
+	if len(filtered) == 0 {
+		return problems
+	}
+	return filtered
+}
+
+// rotationOrder 出題順の名前から決まる、0〜n-1の並べ替え
+func rotationOrder(key string, n int) []int {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return rand.New(rand.NewPCG(h.Sum64(), uint64(n))).Perm(n)
+}
+
+// nextRotation 出題順の次の位置（0〜n-1）を取得して進める（保存先があれば、プロフィールごとに保存した位置から続ける）
+func (e *Engine) nextRotation(key string, n int) int {
+	e.mu.Lock()
+	store, profileID := e.rotationStore, e.profileID
+	indexKey := profileID + "_" + key // プロフィールを切り替えたら、そのプロフィールの位置から続ける
+	position, started := e.problemIndex[indexKey]
+	e.mu.Unlock()
+
+	if !started && store != nil && profileID != "" {
+		saved, err := store.GetOfflineRotation(profileID, key)
+		if err != nil {
+			slog.Error("出題位置の取得エラー", "error", err)
+		}
+		position = saved
+	}
+
+	e.mu.Lock()
+	e.problemIndex[indexKey] = position + 1
+	e.mu.Unlock()
+
+	if store != nil && profileID != "" {
+		if err := store.SaveOfflineRotation(profileID, key, position+1); err != nil {
+			slog.Error("出題位置の保存エラー", "error", err)
+		}
+	}
+	return position % n
+}
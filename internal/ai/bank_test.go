@@ -0,0 +1,105 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+func TestOfflineBankValid(t *testing.T) {
+	problems, err := loadOfflineBank()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[string]int{}
+	seen := map[string]bool{}
+	for _, p := range problems {
+		if err := p.Validate(); err != nil {
+			t.Errorf("中%d %s: %v", p.Grade, p.Subject, err)
+		}
+		if seen[p.Description] {
+			t.Errorf("同じ問題が2つあります: %s", p.Description)
+		}
+		seen[p.Description] = true
+		counts[p.Subject]++
+	}
+	for _, subject := range config.Subjects {
+		for grade := 1; grade <= 3; grade++ {
+			n := 0
+			for _, p := range problems {
+				if p.Grade == grade && p.Subject == subject {
+					n++
+				}
+			}
+			if n < 40 {
+				t.Errorf("中%d %sの問題が少なすぎます: %d問", grade, subject, n)
+			}
+		}
+	}
+}
+
+// memoryRotationStore テスト用の出題位置の保存先
+type memoryRotationStore map[string]int
+
+func (s memoryRotationStore) GetOfflineRotation(userID, key string) (int, error) {
+	return s[userID+"/"+key], nil
+}
+
+func (s memoryRotationStore) SaveOfflineRotation(userID, key string, position int) error {
+	s[userID+"/"+key] = position
+	return nil
+}
+
+func TestOfflineBankRotation(t *testing.T) {
+	store := memoryRotationStore{}
+	newEngine := func() *Engine {
+		engine := newTestEngine(t, "http://127.0.0.1:0")
+		engine.setHealth(func(h *Health) { h.Status = HealthOffline })
+		engine.config.Cloud.Consent = false
+		engine.SetRotationStore(store)
+		engine.SetProfile("student", "生徒")
+		return engine
+	}
+	studyContext := StudyContext{Subject: "理科", Grade: 2, Difficulty: 3, Topic: "電流とその利用"}
+
+	engine := newEngine()
+	seen := map[string]bool{}
+	for range 5 {
+		problem, err := engine.GeneratePersonalizedProblem(context.Background(), studyContext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if problem.ProblemType != "電流とその利用" || problem.Difficulty < 2 || problem.Difficulty > 4 {
+			t.Errorf("単元・難易度の合わない問題 = %+v", problem)
+		}
+		if seen[problem.Description] {
+			t.Errorf("一巡する前に同じ問題を出題しました: %s", problem.Description)
+		}
+		seen[problem.Description] = true
+	}
+
+	// 再起動しても、保存した位置から続ける
+	problem, err := newEngine().GeneratePersonalizedProblem(context.Background(), studyContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seen[problem.Description] {
+		t.Errorf("再起動後に出題済みの問題を出題しました: %s", problem.Description)
+	}
+}
+
+func TestFilterDifficulty(t *testing.T) {
+	problems := []OfflineProblem{{Title: "易", Difficulty: 1}, {Title: "中", Difficulty: 3}, {Title: "難", Difficulty: 5}}
+
+	if got := filterDifficulty(problems, 4); len(got) != 2 || got[0].Title != "中" || got[1].Title != "難" {
+		t.Errorf("難易度4 = %+v", got)
+	}
+	if got := filterDifficulty(problems[:1], 5); len(got) != 1 {
+		t.Errorf("合う問題がなければしぼらないはず: %+v", got)
+	}
+	if got := filterDifficulty(problems, 0); len(got) != 3 {
+		t.Errorf("難易度の指定がなければしぼらないはず: %+v", got)
+	}
+}
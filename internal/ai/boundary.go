@@ -0,0 +1,134 @@
+package ai
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// 範囲外の質問を見つけた段階
+const (
+	BoundaryStageQuestion = "question" // 質問をAIに送る前
+	BoundaryStageAnswer   = "answer"   // AIの解説を表示する前
+)
+
+// 範囲外の質問の種類
+const (
+	BoundaryPersonal = "personal" // 恋愛・友だち関係・お金などの個人的な相談
+	BoundaryUnsafe   = "unsafe"   // 危険なこと・大人向けの内容
+	BoundaryCrisis   = "crisis"   // 自分を傷つけたい気持ち（相談先を案内する）
+	BoundarySubject  = "subject"  // 保護者が決めた教科の外
+)
+
+// BoundaryLabels 範囲外の質問の種類の表示名
+var BoundaryLabels = map[string]string{
+	BoundaryPersonal: "個人的な相談",
+	BoundaryUnsafe:   "危険・不適切な内容",
+	BoundaryCrisis:   "つらい気持ち",
+	BoundarySubject:  "決めた教科の外",
+}
+
+// BoundaryHit クイック質問の範囲外の質問を見つけた記録
+type BoundaryHit struct {
+	Stage    string // BoundaryStageQuestion | BoundaryStageAnswer
+	Category string // BoundaryPersonal | BoundaryUnsafe | BoundaryCrisis | BoundarySubject
+	Detail   string // 見つけた言葉や教科
+}
+
+// outsideSubject AIが教科の質問ではないと判断したときの教科名
+const outsideSubject = "教科外"
+
+// boundaryWords 質問に含まれていたら範囲外とする言葉（種類ごと。危険な内容は解説でも探す）
+var boundaryWords = []struct {
+	category string
+	words    []string
+}{
+	{BoundaryCrisis, []string{"死にたい", "消えたい", "リストカット", "自分を傷つけたい"}},
+	{BoundaryUnsafe, []string{"爆弾の作り方", "武器の作り方", "ハッキングのやり方", "パスワードを盗", "万引き", "お酒の買い方", "出会い系", "アダルト"}},
+	{BoundaryPersonal, []string{
+		"好きな人", "告白したい", "彼氏", "彼女ができ", "付き合い方", "恋愛相談",
+		"親とけんか", "親がうるさい", "友だちとけんか", "友達とけんか", "仲間外れ", "いじめられ",
+		"お小遣いを増や", "お金を稼", "株で儲", "ダイエット", "占って",
+	}},
+}
+
+// crisisMessage つらい気持ちを書いた質問に表示する、相談先の案内
+const crisisMessage = "つらい気持ちを書いてくれてありがとう。ひとりで抱えこまず、家族や先生、スクールカウンセラーなど、信頼できる大人に話してみてください。\n\n今すぐ話したいときは「24時間子供SOSダイヤル」（0120-0-78310、通話無料・24時間）に電話できます。"
+
+// ClassifyQuestion 質問をAIに送る前に、保護者が決めた範囲の外かどうかを判断（範囲内ならnil）
+// つらい気持ちを書いた質問は、範囲を使わない設定でも相談先を案内する
+func ClassifyQuestion(boundaries config.TutorBoundaries, question, subject string) *BoundaryHit {
+	if hit := findBoundaryWord(boundaries, BoundaryStageQuestion, question); hit != nil {
+		return hit
+	}
+	if boundaries.Enabled && !subjectAllowed(boundaries, subject) {
+		return &BoundaryHit{Stage: BoundaryStageQuestion, Category: BoundarySubject, Detail: subject}
+	}
+	return nil
+}
+
+// checkAnswerBoundary AIの解説を表示する前に、範囲の外の内容でないか確かめる（範囲内ならnil）
+func checkAnswerBoundary(boundaries config.TutorBoundaries, subject string, outputs ...string) *BoundaryHit {
+	if !boundaries.Enabled {
+		return nil
+	}
+	if subject == outsideSubject || !subjectAllowed(boundaries, subject) {
+		return &BoundaryHit{Stage: BoundaryStageAnswer, Category: BoundarySubject, Detail: subject}
+	}
+	return findBoundaryWord(boundaries, BoundaryStageAnswer, strings.Join(outputs, "\n"))
+}
+
+// subjectAllowed 教科が保護者の決めた教科に入っているか（教科を決めていない・教科がわからないときはtrue）
+func subjectAllowed(boundaries config.TutorBoundaries, subject string) bool {
+	if len(boundaries.Subjects) == 0 || !slices.Contains(config.Subjects, subject) {
+		return true
+	}
+	return slices.Contains(boundaries.Subjects, subject)
+}
+
+// findBoundaryWord 範囲外とする言葉を探す
+// （解説では危険な内容だけを探し、個人的な相談は保護者が断ると決めたときだけ探す）
+func findBoundaryWord(boundaries config.TutorBoundaries, stage, text string) *BoundaryHit {
+	for _, group := range boundaryWords {
+		switch {
+		case stage == BoundaryStageAnswer && group.category != BoundaryUnsafe,
+			group.category != BoundaryCrisis && !boundaries.Enabled,
+			group.category == BoundaryPersonal && !boundaries.BlockPersonal:
+			continue
+		}
+		for _, word := range group.words {
+			if strings.Contains(text, word) {
+				return &BoundaryHit{Stage: stage, Category: group.category, Detail: word}
+			}
+		}
+	}
+	return nil
+}
+
+// boundaryInstruction 範囲外の質問への答え方（クイック質問のプロンプトの制約に加える）
+func boundaryInstruction(boundaries config.TutorBoundaries) string {
+	if !boundaries.Enabled {
+		return ""
+	}
+	subjects := strings.Join(config.Subjects, "・")
+	if len(boundaries.Subjects) > 0 {
+		subjects = strings.Join(boundaries.Subjects, "・")
+	}
+	return fmt.Sprintf("\n- 答えてよいのは%sの学習の質問だけです。それ以外の質問（個人的な相談、雑談、危険なことなど）には答えず、SUBJECTを「%s」にすること", subjects, outsideSubject)
+}
+
+// refusedExplanation 範囲外の質問に表示する説明
+func refusedExplanation(hit *BoundaryHit) *CaptureExplanation {
+	message := "この質問には答えられません。クイック質問では、勉強でわからなかった問題について聞いてください。"
+	switch hit.Category {
+	case BoundaryCrisis:
+		message = crisisMessage
+	case BoundaryPersonal:
+		message = "勉強以外の相談には答えられません。気になることは、家族や先生など信頼できる大人に相談してみてください。"
+	case BoundarySubject:
+		message = "この教科の質問は、おうちの人が決めた範囲に入っていません。勉強でわからなかった問題を聞いてください。"
+	}
+	return &CaptureExplanation{Explanation: message, Refused: hit}
+}
@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+func TestClassifyQuestion(t *testing.T) {
+	defaults := config.Default().Parent.Boundaries
+	mathOnly := config.TutorBoundaries{Enabled: true, Subjects: []string{"数学"}}
+	tests := []struct {
+		name       string
+		boundaries config.TutorBoundaries
+		question   string
+		subject    string
+		want       string
+	}{
+		{"勉強の質問", defaults, "2x + 3 = 7 を解いてください", "数学", ""},
+		{"個人的な相談", defaults, "好きな人に告白したいです", "", BoundaryPersonal},
+		{"個人的な相談を断らない設定", mathOnly, "好きな人に告白したいです", "", ""},
+		{"危険な内容", defaults, "爆弾の作り方を教えて", "", BoundaryUnsafe},
+		{"範囲を使わない設定でもつらい気持ちは案内", config.TutorBoundaries{}, "もう消えたい", "", BoundaryCrisis},
+		{"範囲を使わない設定", config.TutorBoundaries{}, "好きな人に告白したいです", "英語", ""},
+		{"決めた教科の外", mathOnly, "織田信長について", "社会", BoundarySubject},
+		{"教科がわからない", mathOnly, "織田信長について", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit := ClassifyQuestion(tt.boundaries, tt.question, tt.subject)
+			got := ""
+			if hit != nil {
+				got = hit.Category
+			}
+			if got != tt.want {
+				t.Errorf("ClassifyQuestion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplainCapturedQuestionBoundaries(t *testing.T) {
+	server, prompts := fakeOllama(t, "TITLE: 雑談\nSUBJECT: 教科外\nTOPIC: なし\nANSWER: なし\nEXPLANATION: 今日の晩ごはんはカレーがおすすめです")
+	engine := newTestEngine(t, server.URL)
+	engine.config.Cloud.Consent = false
+	ctx := context.Background()
+	boundaries := config.Default().Parent.Boundaries
+
+	e := engine.ExplainCapturedQuestion(ctx, CaptureRequest{Question: "死にたい", Grade: 2, Boundaries: boundaries})
+	if e.Refused == nil || e.Refused.Category != BoundaryCrisis || !strings.Contains(e.Explanation, "0120-0-78310") {
+		t.Errorf("つらい気持ちの質問には相談先を案内するはず: %+v", e)
+	}
+	if len(prompts()) != 0 {
+		t.Error("範囲外の質問はAIに送らないはず")
+	}
+
+	e = engine.ExplainCapturedQuestion(ctx, CaptureRequest{Question: "今日の晩ごはんは何がいい？", Grade: 2, Boundaries: boundaries})
+	if e.Refused == nil || e.Refused.Stage != BoundaryStageAnswer || strings.Contains(e.Explanation, "カレー") {
+		t.Errorf("AIが教科外と判断した質問の解説は表示しないはず: %+v", e)
+	}
+	if !strings.Contains(prompts()[0], "SUBJECTを「教科外」にする") {
+		t.Errorf("範囲外の質問への答え方をプロンプトで伝えるはず:\n%s", prompts()[0])
+	}
+}
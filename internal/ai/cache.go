@@ -0,0 +1,154 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// AIの応答の種類ごとの保存期間（過ぎたら生成し直す。AIに接続できないときは過ぎていても使う）
+const (
+	studyTipCacheTTL    = 7 * 24 * time.Hour
+	feedbackCacheTTL    = 30 * 24 * time.Hour
+	translationCacheTTL = 90 * 24 * time.Hour // 問題の翻訳は内容が変わらないので長く使う
+	modelListCacheTTL   = 30 * time.Second
+
+	// ResponseCacheRetention 保存期間が過ぎてからも、オフラインのときのために残しておく期間
+	ResponseCacheRetention = 90 * 24 * time.Hour
+)
+
+// 保存するAIの応答の種類
+const (
+	cacheKindStudyTip    = "study_tip"
+	cacheKindFeedback    = "feedback"
+	cacheKindTranslation = "translation"
+	cacheKindModelList   = "model_list"
+)
+
+// errAIUnavailable AIに接続できず、保存した応答もない
+var errAIUnavailable = errors.New("AIに接続できません")
+
+// ResponseCache AIの応答の保存先（同じプロンプトでOllamaに何度も問い合わせず、オフラインでも前の応答を使うため）
+type ResponseCache interface {
+	GetCachedResponse(key string) (response string, expiresAt time.Time, err error) // 保存していなければ空
+	SaveCachedResponse(key, kind, response string, expiresAt time.Time) error
+	DeleteCachedResponse(key string) error
+}
+
+// SetResponseCache AIの応答の保存先を設定
+func (e *Engine) SetResponseCache(cache ResponseCache) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.responseCache = cache
+}
+
+// cacheKey 保存する応答のキー（種類・モデル・プロンプトのSHA-256）
+func cacheKey(kind, model, prompt string) string {
+	sum := sha256.Sum256([]byte(kind + "\x00" + model + "\x00" + prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedResponse 保存した応答（freshは保存期間内か。保存していなければokがfalse）
+func (e *Engine) cachedResponse(key string) (response string, fresh, ok bool) {
+	e.mu.RLock()
+	cache := e.responseCache
+	e.mu.RUnlock()
+	if cache == nil {
+		return "", false, false
+	}
+
+	response, expiresAt, err := cache.GetCachedResponse(key)
+	if err != nil {
+		slog.Error("AIの応答の読み込みエラー", "error", err)
+		return "", false, false
+	}
+	if response == "" {
+		return "", false, false
+	}
+	return response, time.Now().Before(expiresAt), true
+}
+
+// saveResponse 応答を保存
+func (e *Engine) saveResponse(key, kind, response string, ttl time.Duration) {
+	e.mu.RLock()
+	cache := e.responseCache
+	e.mu.RUnlock()
+	if cache == nil || response == "" {
+		return
+	}
+	if err := cache.SaveCachedResponse(key, kind, response, time.Now().Add(ttl)); err != nil {
+		slog.Error("AIの応答の保存エラー", "error", err)
+	}
+}
+
+// generateCached 保存期間内の同じプロンプトの応答があれば使い、なければ生成して保存
+// （AIに接続できない・生成に失敗したときは、保存期間が過ぎた応答でも使う。usedCacheは保存した応答を使ったか）
+// 応答は仮名のまま保存し、別のプロフィールの同じ問題にも使えるようにする
+func (e *Engine) generateCached(ctx context.Context, kind, prompt string, ttl time.Duration) (response string, usedCache bool, err error) {
+	key := cacheKey(kind, e.GetCurrentModel(), e.redactor.Redact(prompt))
+	cached, fresh, ok := e.cachedResponse(key)
+	if ok && fresh {
+		return e.redactor.Restore(cached), true, nil
+	}
+
+	if e.shouldTryAI() {
+		response, err = e.generate(ctx, prompt)
+		if err == nil {
+			e.saveResponse(key, kind, e.redactor.Redact(response), ttl)
+			return response, false, nil
+		}
+	} else {
+		err = errAIUnavailable
+	}
+	if ok {
+		slog.Info("AIが使えないため、前に生成した応答を使います", "kind", kind)
+		return e.redactor.Restore(cached), true, nil
+	}
+	return "", false, err
+}
+
+// cachedModels 保存期間内のモデル一覧（なければnil）
+func (e *Engine) cachedModels() []ModelInfo {
+	cached, fresh, ok := e.cachedResponse(e.modelListKey())
+	if !ok || !fresh {
+		return nil
+	}
+	var models []ModelInfo
+	if err := json.Unmarshal([]byte(cached), &models); err != nil {
+		slog.Error("モデル一覧の読み込みエラー", "error", err)
+		return nil
+	}
+	return models
+}
+
+// saveModels モデル一覧を保存
+func (e *Engine) saveModels(models []ModelInfo) {
+	data, err := json.Marshal(models)
+	if err != nil {
+		slog.Error("モデル一覧の保存エラー", "error", err)
+		return
+	}
+	e.saveResponse(e.modelListKey(), cacheKindModelList, string(data), modelListCacheTTL)
+}
+
+// forgetModels 保存したモデル一覧を消す（モデルを追加・削除したとき）
+func (e *Engine) forgetModels() {
+	e.mu.RLock()
+	cache := e.responseCache
+	e.mu.RUnlock()
+	if cache == nil {
+		return
+	}
+	if err := cache.DeleteCachedResponse(e.modelListKey()); err != nil {
+		slog.Error("モデル一覧の削除エラー", "error", err)
+	}
+}
+
+// modelListKey モデル一覧を保存するキー（接続先のOllamaごと）
+func (e *Engine) modelListKey() string {
+	return cacheKey(cacheKindModelList, "", e.ollamaURL())
+}
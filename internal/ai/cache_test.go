@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryCache テスト用のメモリ上の応答の保存先
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	response  string
+	expiresAt time.Time
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{entries: make(map[string]memoryCacheEntry)}
+}
+
+func (c *memoryCache) GetCachedResponse(key string) (string, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := c.entries[key]
+	return entry.response, entry.expiresAt, nil
+}
+
+func (c *memoryCache) SaveCachedResponse(key, _, response string, expiresAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = memoryCacheEntry{response: response, expiresAt: expiresAt}
+	return nil
+}
+
+func (c *memoryCache) DeleteCachedResponse(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+// expireAll 保存した応答をすべて保存期間切れにする
+func (c *memoryCache) expireAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		entry.expiresAt = time.Now().Add(-time.Minute)
+		c.entries[key] = entry
+	}
+}
+
+func TestStudyTipIsCached(t *testing.T) {
+	server, prompts := fakeOllama(t, "声に出して読もう")
+	engine := newTestEngine(t, server.URL)
+	engine.config.Cloud.Consent = false
+	cache := newMemoryCache()
+	engine.SetResponseCache(cache)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		tip, err := engine.GenerateStudyTip(ctx, "英語", "過去形")
+		if err != nil || tip != "声に出して読もう" {
+			t.Fatalf("%d回目: tip=%q err=%v", i+1, tip, err)
+		}
+	}
+	if got := len(prompts()); got != 1 {
+		t.Errorf("Ollamaへの問い合わせ = %d回, want 1回（2回目は保存した応答を使う）", got)
+	}
+
+	// オフラインなら、保存期間が過ぎた応答でも使う
+	cache.expireAll()
+	engine.setHealth(func(h *Health) { h.Status = HealthOffline })
+	tip, err := engine.GenerateStudyTip(ctx, "英語", "過去形")
+	if err != nil || tip != "声に出して読もう" {
+		t.Errorf("オフライン: tip=%q err=%v", tip, err)
+	}
+	if _, err := engine.GenerateStudyTip(ctx, "数学", "関数"); err == nil {
+		t.Error("保存した応答がなくオフラインならエラーになるはず")
+	}
+	if got := len(prompts()); got != 1 {
+		t.Errorf("オフラインでOllamaに問い合わせた: %d回", got)
+	}
+}
+
+func TestCachedFeedbackRestoresStudentName(t *testing.T) {
+	server, prompts := fakeOllama(t, "MESSAGE: 佐藤花子さん、正解です！\nEXPLANATION: 5になります")
+	engine := newTestEngine(t, server.URL)
+	engine.config.Cloud.Consent = false
+	cache := newMemoryCache()
+	engine.SetResponseCache(cache)
+	engine.SetProfile("user-1", "佐藤花子")
+
+	req := FeedbackRequest{
+		Problem:    Problem{Description: "2 + 3 はいくつですか？", Options: []string{"4", "5"}, CorrectAnswer: 1},
+		UserAnswer: "5",
+		IsCorrect:  true,
+	}
+	ctx := context.Background()
+	if _, err := engine.GenerateFeedback(ctx, req); err != nil {
+		t.Fatalf("フィードバック生成エラー: %v", err)
+	}
+	for _, entry := range cache.entries {
+		if strings.Contains(entry.response, "佐藤花子") {
+			t.Errorf("保存した応答に名前が含まれている: %q", entry.response)
+		}
+	}
+
+	feedback, err := engine.GenerateFeedback(ctx, req)
+	if err != nil {
+		t.Fatalf("フィードバック生成エラー: %v", err)
+	}
+	if feedback.Message != "佐藤花子さん、正解です！" {
+		t.Errorf("Message = %q", feedback.Message)
+	}
+	if got := len(prompts()); got != 1 {
+		t.Errorf("Ollamaへの問い合わせ = %d回, want 1回", got)
+	}
+}
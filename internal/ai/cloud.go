@@ -0,0 +1,306 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/privacy"
+)
+
+// クラウドAIの提供元ごとの既定モデル
+var defaultCloudModels = map[string]string{
+	config.CloudProviderOpenAI: "gpt-4o-mini",
+	config.CloudProviderGemini: "gemini-2.0-flash",
+}
+
+// クラウドAIのAPIエンドポイント
+const (
+	openAIChatURL     = "https://api.openai.com/v1/chat/completions"
+	geminiGenerateURL = "https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent"
+)
+
+// CloudUsageStore クラウドAIの使用量の保存先（月ごとは家庭全体、日ごとはプロフィール別）
+type CloudUsageStore interface {
+	GetCloudTokenUsage(month string) (int, error)
+	AddCloudTokenUsage(month string, tokens int) error
+	GetCloudDailyUsage(userID, date string) (requests, tokens int, err error)
+	AddCloudDailyUsage(userID, date string, tokens int) error
+}
+
+// クラウドAIの使用量の上限に達した（上限に達した後はオフラインの内蔵問題などを使う）
+var (
+	ErrCloudBudgetExceeded = errors.New("今月のクラウドAIの利用上限に達しました")
+	ErrCloudDailyLimit     = errors.New("今日のクラウドAIの利用上限に達しました")
+)
+
+// CloudUsage クラウドAIの使用量と上限（上限0は無制限）
+type CloudUsage struct {
+	MonthTokens       int
+	MonthlyTokenLimit int
+	TodayRequests     int
+	DailyRequestLimit int
+	TodayTokens       int
+	DailyTokenLimit   int
+}
+
+// SetUsageStore クラウドAIの使用トークン数の保存先を設定
+func (e *Engine) SetUsageStore(store CloudUsageStore) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.usageStore = store
+}
+
+// SetCloudConfig クラウドAIの設定を更新
+func (e *Engine) SetCloudConfig(cloud config.CloudAIConfig) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.config.Cloud = cloud
+	e.redactor.Set("api_key", cloud.APIKey, privacy.RedactedSecret)
+}
+
+// cloudEnabled クラウドAIを使う設定になっているか
+func (e *Engine) cloudEnabled() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.config.Cloud.Enabled()
+}
+
+// cloudModelName 生成したモデルとして記録するクラウドAIの名前（例: openai/gpt-4o-mini）
+func (e *Engine) cloudModelName() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.config.Cloud.Provider + "/" + cloudModel(e.config.Cloud)
+}
+
+// cloudModel クラウドAIで使うモデル（設定がなければ提供元の既定モデル）
+func cloudModel(cloud config.CloudAIConfig) string {
+	if cloud.Model != "" {
+		return cloud.Model
+	}
+	return defaultCloudModels[cloud.Provider]
+}
+
+// SetProfile 現在のプロフィールを設定（クラウドAIの1日の使用量を数え、名前はAIに送る前に仮名にする）
+func (e *Engine) SetProfile(userID, name string) {
+	e.mu.Lock()
+	e.profileID = userID
+	e.mu.Unlock()
+
+	e.redactor.SetPseudonym("name", name, privacy.StudentPseudonym)
+	e.redactor.Set("user_id", userID, privacy.RedactedAccount)
+}
+
+// CloudUsage 今月（家庭全体）と今日（現在のプロフィール）のクラウドAIの使用量
+func (e *Engine) CloudUsage() (*CloudUsage, error) {
+	e.mu.RLock()
+	store := e.usageStore
+	cloud := e.config.Cloud
+	profileID := e.profileID
+	e.mu.RUnlock()
+
+	usage := &CloudUsage{
+		MonthlyTokenLimit: cloud.MonthlyTokens,
+		DailyRequestLimit: cloud.DailyRequests,
+		DailyTokenLimit:   cloud.DailyTokens,
+	}
+	if store == nil {
+		return usage, nil
+	}
+
+	now := time.Now()
+	var err error
+	if usage.MonthTokens, err = store.GetCloudTokenUsage(usageMonth(now)); err != nil {
+		return nil, fmt.Errorf("クラウドAI使用量取得エラー: %w", err)
+	}
+	if usage.TodayRequests, usage.TodayTokens, err = store.GetCloudDailyUsage(profileID, usageDate(now)); err != nil {
+		return nil, fmt.Errorf("クラウドAI使用量取得エラー: %w", err)
+	}
+	return usage, nil
+}
+
+// checkBudget 今月と今日の上限に達していないか確認
+func (u *CloudUsage) checkBudget() error {
+	if u.MonthTokens >= u.MonthlyTokenLimit {
+		return ErrCloudBudgetExceeded
+	}
+	if u.DailyRequestLimit > 0 && u.TodayRequests >= u.DailyRequestLimit {
+		return ErrCloudDailyLimit
+	}
+	if u.DailyTokenLimit > 0 && u.TodayTokens >= u.DailyTokenLimit {
+		return ErrCloudDailyLimit
+	}
+	return nil
+}
+
+// usageMonth 使用量を集計する月（"2006-01"形式）
+func usageMonth(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+// usageDate 使用量を集計する日（"2006-01-02"形式）
+func usageDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// generateCloud クラウドAIでテキスト生成（今月の上限を超える場合は送信しない）
+func (e *Engine) generateCloud(ctx context.Context, prompt string) (string, error) {
+	e.mu.RLock()
+	cloud := e.config.Cloud
+	store := e.usageStore
+	profileID := e.profileID
+	e.mu.RUnlock()
+
+	usage, err := e.CloudUsage()
+	if err != nil {
+		return "", err
+	}
+	if err := usage.checkBudget(); err != nil {
+		return "", err
+	}
+
+	model := cloudModel(cloud)
+
+	var response string
+	var tokens int
+	switch cloud.Provider {
+	case config.CloudProviderOpenAI:
+		response, tokens, err = e.generateOpenAI(ctx, cloud.APIKey, model, prompt)
+	case config.CloudProviderGemini:
+		response, tokens, err = e.generateGemini(ctx, cloud.APIKey, model, prompt)
+	default:
+		return "", fmt.Errorf("未対応のクラウドAI: %s", cloud.Provider)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// 使用量が返ってこない場合は文字数で見積もる
+	if tokens == 0 {
+		tokens = len([]rune(prompt)) + len([]rune(response))
+	}
+	if store != nil {
+		now := time.Now()
+		if err := store.AddCloudTokenUsage(usageMonth(now), tokens); err != nil {
+			return "", fmt.Errorf("クラウドAI使用量記録エラー: %w", err)
+		}
+		if err := store.AddCloudDailyUsage(profileID, usageDate(now), tokens); err != nil {
+			return "", fmt.Errorf("クラウドAI使用量記録エラー: %w", err)
+		}
+	}
+	return strings.TrimSpace(response), nil
+}
+
+// generateOpenAI OpenAIのChat Completions APIでテキスト生成
+func (e *Engine) generateOpenAI(ctx context.Context, apiKey, model, prompt string) (string, int, error) {
+	reqBody := map[string]interface{}{
+		"model": model,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+		"temperature": e.config.Temperature,
+		"top_p":       e.config.TopP,
+		"max_tokens":  e.outputTokens(),
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			TotalTokens int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	headers := map[string]string{"Authorization": "Bearer " + apiKey}
+	if err := e.postCloudJSON(ctx, openAIChatURL, headers, reqBody, &result); err != nil {
+		return "", 0, err
+	}
+	if len(result.Choices) == 0 {
+		return "", 0, fmt.Errorf("openai APIエラー: 応答が空です")
+	}
+	return result.Choices[0].Message.Content, result.Usage.TotalTokens, nil
+}
+
+// generateGemini GeminiのgenerateContent APIでテキスト生成
+func (e *Engine) generateGemini(ctx context.Context, apiKey, model, prompt string) (string, int, error) {
+	reqBody := map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{"parts": []map[string]string{{"text": prompt}}},
+		},
+		"generationConfig": map[string]interface{}{
+			"temperature":     e.config.Temperature,
+			"topP":            e.config.TopP,
+			"maxOutputTokens": e.outputTokens(),
+		},
+	}
+
+	var result struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+		UsageMetadata struct {
+			TotalTokenCount int `json:"totalTokenCount"`
+		} `json:"usageMetadata"`
+	}
+	headers := map[string]string{"x-goog-api-key": apiKey}
+	if err := e.postCloudJSON(ctx, fmt.Sprintf(geminiGenerateURL, model), headers, reqBody, &result); err != nil {
+		return "", 0, err
+	}
+	if len(result.Candidates) == 0 {
+		return "", 0, fmt.Errorf("gemini APIエラー: 応答が空です")
+	}
+
+	var text strings.Builder
+	for _, part := range result.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	return text.String(), result.UsageMetadata.TotalTokenCount, nil
+}
+
+// postCloudJSON クラウドAIのAPIにJSONを送信し、応答をresultに読み込む
+func (e *Engine) postCloudJSON(ctx context.Context, url string, headers map[string]string, body, result interface{}) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("HTTPリクエスト作成エラー: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTPリクエストエラー: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("レスポンス読み取りエラー: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("クラウドAI APIエラー: %d - %s", resp.StatusCode, string(data))
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package ai
+
+import (
+	"slices"
+
+	"github.com/okamyuji/studybuddy-ai/internal/curriculum"
+)
+
+// SetCurriculum 問題の生成に使う学習範囲を差し替える（保護者・先生が編集した学習範囲）
+func (e *Engine) SetCurriculum(c curriculum.Curriculum) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.curriculum = c.Clone()
+}
+
+// Curriculum 問題の生成に使っている学習範囲のコピー
+func (e *Engine) Curriculum() curriculum.Curriculum {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.curriculum.Clone()
+}
+
+// curriculumContent 学年・科目の学習範囲の文章（プロンプトに使う）
+func (e *Engine) curriculumContent(grade int, subject string) string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.curriculum.Content(grade, subject)
+}
+
+// CurriculumTopics 学年・科目の学習範囲の単元一覧（英語にはリスニング、数学・理科・社会には図表の読み取りを加える）
+func (e *Engine) CurriculumTopics(grade int, subject string) []string {
+	e.mu.RLock()
+	topics := e.curriculum.Topics(grade, subject)
+	e.mu.RUnlock()
+	if len(topics) == 0 {
+		return nil
+	}
+	if subject == "英語" {
+		topics = append(topics, ListeningTopic)
+	}
+	if slices.Contains(figureSubjects, subject) {
+		topics = append(topics, FigureTopic)
+	}
+	return topics
+}
@@ -0,0 +1,33 @@
+package ai
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/okamyuji/studybuddy-ai/internal/curriculum"
+)
+
+func TestSetCurriculum(t *testing.T) {
+	engine := newTestEngine(t, "http://127.0.0.1:1")
+	c := curriculum.Default()
+	c.Set(3, "数学", []string{"高校範囲：数と式", "二次関数"})
+	engine.SetCurriculum(c)
+	c.Set(3, "数学", []string{"あとから変えた単元"})
+
+	topics := engine.CurriculumTopics(3, "数学")
+	if !slices.Equal(topics, []string{"高校範囲：数と式", "二次関数", FigureTopic}) {
+		t.Errorf("中3 数学の単元 = %v", topics)
+	}
+	prompt := engine.buildPersonalizedPrompt(StudyContext{Subject: "数学", Grade: 3, Difficulty: 3})
+	if !strings.Contains(prompt, "高校範囲：数と式、二次関数") {
+		t.Errorf("編集した学習範囲がプロンプトにない:\n%s", prompt)
+	}
+	prompt = engine.buildPersonalizedPrompt(StudyContext{Subject: "数学", Grade: 3, Difficulty: 3, Topic: "二次関数"})
+	if !strings.Contains(prompt, "単元「二次関数」") || !strings.Contains(prompt, "TYPE: 二次関数") {
+		t.Errorf("選んだ単元がプロンプトにない:\n%s", prompt)
+	}
+	if engine.CurriculumTopics(3, "体育") != nil {
+		t.Error("学習範囲にない科目の単元はないはず")
+	}
+}
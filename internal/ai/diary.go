@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// maxDiaryRunes 学習日記の下書きの最大文字数
+const maxDiaryRunes = 400
+
+// DiaryActivity 学習日記の下書きに使う、1つの科目の1日の学習記録
+type DiaryActivity struct {
+	Subject  string
+	Minutes  int
+	Problems int
+	Correct  int
+	Topics   []string // 解いた問題の単元（多い順）
+	Notes    []string // アプリ外の学習のメモ（塾・紙のドリルなど）
+}
+
+// DiaryRequest 学習日記の下書きの要求
+type DiaryRequest struct {
+	Grade      int
+	Date       time.Time
+	Activities []DiaryActivity
+}
+
+// GenerateDiaryDraft 1日の学習記録から、生徒本人が書く学習日記の下書きを生成
+// （記録にない内容は書かせない。AIが使えないときは記録をそのまま文章にする）
+func (e *Engine) GenerateDiaryDraft(ctx context.Context, req DiaryRequest) string {
+	offline := offlineDiaryDraft(req)
+	if len(req.Activities) == 0 || !e.shouldTryAI() {
+		return offline
+	}
+
+	response, err := e.generate(ctx, buildDiaryPrompt(req))
+	if err != nil {
+		e.recordFailure()
+		return offline
+	}
+	e.recordSuccess()
+
+	draft := strings.TrimSpace(strings.ReplaceAll(response, "```", ""))
+	if draft == "" || !containsJapanese(draft) || len([]rune(draft)) > maxDiaryRunes {
+		return offline
+	}
+	if err := validateGuardedOutput(diaryNotes(req), draft); err != nil {
+		slog.Warn("学習日記の下書きを使いません", "error", err)
+		return offline
+	}
+	return draft
+}
+
+// buildDiaryPrompt 学習日記の下書きプロンプト
+func buildDiaryPrompt(req DiaryRequest) string {
+	var records strings.Builder
+	for _, activity := range req.Activities {
+		fmt.Fprintf(&records, "- %s: %d分", activity.Subject, activity.Minutes)
+		if activity.Problems > 0 {
+			fmt.Fprintf(&records, "、%d問中%d問正解", activity.Problems, activity.Correct)
+		}
+		if len(activity.Topics) > 0 {
+			fmt.Fprintf(&records, "、単元: %s", strings.Join(activity.Topics, "・"))
+		}
+		records.WriteString("\n")
+	}
+
+	notes := ""
+	if n := diaryNotes(req); n != "" {
+		notes = fmt.Sprintf("\n【アプリの外での学習のメモ（生徒が入力）】\n%s\n", fenceContent(n))
+	}
+
+	return fmt.Sprintf(`中学%d年生が学校に提出する学習記録（学習日記）の、%sの分の下書きを作成。
+
+【この日の学習の記録】
+%s%s
+【重要な制約】
+- 生徒本人が書いた文章として「です・ます」調の一人称で書くこと
+- 上記の記録にある科目・単元・時間・問題数だけを使い、記録にないことは書かないこと
+- 何をどれくらい学習したか、できたこと、次にがんばりたいことを3〜5文で書くこと
+- 200文字以内の日本語
+%s
+
+日記の文章のみを回答。`,
+		req.Grade, req.Date.Format("1月2日"), records.String(), notes, fencedContentRule)
+}
+
+// diaryNotes アプリ外の学習のメモをまとめた文章
+func diaryNotes(req DiaryRequest) string {
+	var notes []string
+	for _, activity := range req.Activities {
+		for _, note := range activity.Notes {
+			notes = append(notes, fmt.Sprintf("%s: %s", activity.Subject, note))
+		}
+	}
+	return strings.Join(notes, "\n")
+}
+
+// offlineDiaryDraft 記録をそのまま文章にした学習日記の下書き
+func offlineDiaryDraft(req DiaryRequest) string {
+	if len(req.Activities) == 0 {
+		return "今日は学習の記録がありません。"
+	}
+
+	var b strings.Builder
+	total := 0
+	for _, activity := range req.Activities {
+		total += activity.Minutes
+		b.WriteString(activity.Subject)
+		if len(activity.Topics) > 0 {
+			fmt.Fprintf(&b, "の%s", strings.Join(activity.Topics, "・"))
+		}
+		fmt.Fprintf(&b, "を%d分学習し", activity.Minutes)
+		if activity.Problems > 0 {
+			fmt.Fprintf(&b, "、%d問中%d問正解しました。", activity.Problems, activity.Correct)
+		} else {
+			b.WriteString("ました。")
+		}
+	}
+	fmt.Fprintf(&b, "合計%d分学習しました。次も続けてがんばります。", total)
+	return b.String()
+}
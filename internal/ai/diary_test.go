@@ -0,0 +1,46 @@
+package ai_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+func diaryRequest() ai.DiaryRequest {
+	return ai.DiaryRequest{
+		Grade: 2,
+		Date:  time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local),
+		Activities: []ai.DiaryActivity{
+			{Subject: "数学", Minutes: 20, Problems: 3, Correct: 2, Topics: []string{"一次関数"}},
+		},
+	}
+}
+
+func TestGenerateDiaryDraftUsesAIResponse(t *testing.T) {
+	cassette := testutil.LoadCassette(t, "diary_draft")
+	engine := testutil.NewEngine(t, cassette)
+
+	draft := engine.GenerateDiaryDraft(context.Background(), diaryRequest())
+	if !strings.HasPrefix(draft, "今日は数学を20分学習しました。") {
+		t.Errorf("draft = %q", draft)
+	}
+	if unused := cassette.Unused(); unused != 0 {
+		t.Errorf("使われていない応答 = %d", unused)
+	}
+}
+
+func TestGenerateDiaryDraftFallsBackOnError(t *testing.T) {
+	cassette := testutil.NewCassette(t,
+		testutil.Interaction{Method: "POST", Path: "/api/generate", Status: 500, Body: "model not found"},
+	)
+	engine := testutil.NewEngine(t, cassette)
+
+	draft := engine.GenerateDiaryDraft(context.Background(), diaryRequest())
+	if !strings.Contains(draft, "数学") || !strings.Contains(draft, "20分") {
+		t.Errorf("オフラインの下書きに記録が含まれていない: %q", draft)
+	}
+}
@@ -0,0 +1,199 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// 埋め込みベクトルのコサイン類似度の目安
+const (
+	SimilarThreshold   = 0.8  // 似た問題
+	DuplicateThreshold = 0.95 // ほぼ同じ問題
+)
+
+// EmbeddingStore 埋め込みベクトルの保存先（同じ文章を何度も計算しないため）
+type EmbeddingStore interface {
+	GetEmbedding(model, key string) ([]float64, error) // 保存していなければnil
+	SaveEmbedding(model, key string, embedding []float64) error
+}
+
+// ErrEmbeddingUnavailable AIに接続できず、埋め込みベクトルを計算できない
+var ErrEmbeddingUnavailable = errors.New("AIに接続できないため、文章の類似度を計算できません")
+
+// OllamaEmbeddingRequest Ollama埋め込みAPIリクエスト
+type OllamaEmbeddingRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+// OllamaEmbeddingResponse Ollama埋め込みAPIレスポンス
+type OllamaEmbeddingResponse struct {
+	Embedding []float64 `json:"embedding"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// SetEmbeddingStore 埋め込みベクトルの保存先を設定
+func (e *Engine) SetEmbeddingStore(store EmbeddingStore) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.embedStore = store
+}
+
+// embeddingModel 埋め込みに使うモデル（設定がなければ問題生成と同じモデル）
+func (e *Engine) embeddingModel() string {
+	if e.config.EmbeddingModel != "" {
+		return e.config.EmbeddingModel
+	}
+	return e.GetCurrentModel()
+}
+
+// Embed 文章の埋め込みベクトルを取得（Ollamaの/api/embeddingsで計算し、保存先があれば保存して使い回す）
+func (e *Engine) Embed(ctx context.Context, text string) ([]float64, error) {
+	text = e.redactor.Redact(strings.TrimSpace(text))
+	if text == "" {
+		return nil, fmt.Errorf("文章が空です")
+	}
+	model := e.embeddingModel()
+	sum := sha256.Sum256([]byte(text))
+	key := hex.EncodeToString(sum[:])
+
+	e.mu.RLock()
+	store := e.embedStore
+	e.mu.RUnlock()
+	if store != nil {
+		embedding, err := store.GetEmbedding(model, key)
+		if err != nil {
+			slog.Error("埋め込みベクトル読み込みエラー", "error", err)
+		} else if embedding != nil {
+			return embedding, nil
+		}
+	}
+
+	if !e.shouldTryAI() {
+		return nil, ErrEmbeddingUnavailable
+	}
+	embedding, err := e.requestEmbedding(ctx, model, text)
+	if err != nil {
+		return nil, err
+	}
+	if store != nil {
+		if err := store.SaveEmbedding(model, key, embedding); err != nil {
+			slog.Error("埋め込みベクトル保存エラー", "error", err)
+		}
+	}
+	return embedding, nil
+}
+
+// requestEmbedding Ollama APIで埋め込みベクトルを計算
+func (e *Engine) requestEmbedding(ctx context.Context, model, text string) ([]float64, error) {
+	jsonData, err := json.Marshal(OllamaEmbeddingRequest{Model: model, Prompt: text})
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.config.OllamaURL+"/api/embeddings", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエスト作成エラー: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストエラー: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama 埋め込みAPIエラー: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var result OllamaEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("ollama処理エラー: %s", result.Error)
+	}
+	if len(result.Embedding) == 0 {
+		return nil, fmt.Errorf("埋め込みベクトルが空です（モデル: %s）", model)
+	}
+	return result.Embedding, nil
+}
+
+// CosineSimilarity 2つの埋め込みベクトルのコサイン類似度（-1〜1。長さが違う・0のベクトルは0）
+func CosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// NearestEmbeddings 類似度がthreshold以上の候補の番号を、似ている順に最大limit件返す
+func NearestEmbeddings(query []float64, candidates [][]float64, threshold float64, limit int) []int {
+	type match struct {
+		index      int
+		similarity float64
+	}
+	var matches []match
+	for i, candidate := range candidates {
+		if similarity := CosineSimilarity(query, candidate); similarity >= threshold {
+			matches = append(matches, match{i, similarity})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].similarity > matches[j].similarity })
+
+	indices := make([]int, 0, min(limit, len(matches)))
+	for _, m := range matches[:min(limit, len(matches))] {
+		indices = append(indices, m.index)
+	}
+	return indices
+}
+
+// ClusterEmbeddings 埋め込みベクトルを、似たもの同士のまとまりに分ける
+// （先頭から順に、いちばん似ているまとまりの平均との類似度がthreshold以上ならそこに入れ、なければ新しいまとまりにする）
+func ClusterEmbeddings(embeddings [][]float64, threshold float64) [][]int {
+	var clusters [][]int
+	var centroids [][]float64
+	for i, embedding := range embeddings {
+		best, bestSimilarity := -1, threshold
+		for c, centroid := range centroids {
+			if similarity := CosineSimilarity(embedding, centroid); similarity >= bestSimilarity {
+				best, bestSimilarity = c, similarity
+			}
+		}
+		if best < 0 {
+			clusters = append(clusters, []int{i})
+			centroids = append(centroids, append([]float64(nil), embedding...))
+			continue
+		}
+
+		// まとまりの平均を更新
+		n := float64(len(clusters[best]))
+		for d := range centroids[best] {
+			centroids[best][d] = (centroids[best][d]*n + embedding[d]) / (n + 1)
+		}
+		clusters[best] = append(clusters[best], i)
+	}
+	return clusters
+}
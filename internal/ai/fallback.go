@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 予備のモデルへの切り替えの設定
+const (
+	modelFailureLimit = 2               // 続けてこの回数失敗したモデルはしばらく使わない
+	modelCooldown     = 5 * time.Minute // 使わないでおく時間（過ぎたらまた試す）
+)
+
+// modelState モデルごとの生成の失敗の記録
+type modelState struct {
+	failures int       // 続けて失敗した回数
+	until    time.Time // この時刻までは予備のモデルを先に使う
+}
+
+// modelChain 生成に使うモデルの順（設定したモデル、予備のモデルの順。重複は除く）
+func (e *Engine) modelChain() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	chain := []string{e.config.Model}
+	for _, model := range e.config.FallbackModels {
+		model = strings.TrimSpace(model)
+		if model == "" || slices.ContainsFunc(chain, func(m string) bool { return SameModel(m, model) }) {
+			continue
+		}
+		chain = append(chain, model)
+	}
+	return chain
+}
+
+// generateFallback 設定したモデルで生成し、時間切れ・エラーのときは予備のモデルで生成し直す
+// （生成したモデルも返す。続けて失敗しているモデルは、最後の1つでなければしばらく飛ばす）
+func (e *Engine) generateFallback(ctx context.Context, prompt string) (string, string, error) {
+	chain := e.modelChain()
+	if len(chain) == 1 {
+		response, err := e.generateOllamaModel(ctx, chain[0], prompt)
+		return response, chain[0], err
+	}
+
+	var errs []error
+	for i, model := range chain {
+		last := i == len(chain)-1
+		if !last && e.modelCoolingDown(model) {
+			continue
+		}
+
+		attemptCtx, cancel := fallbackContext(ctx, last)
+		response, err := e.generateOllamaModel(attemptCtx, model, prompt)
+		cancel()
+		if err == nil {
+			e.recordModelResult(model, true)
+			return response, model, nil
+		}
+		if ctx.Err() != nil {
+			return "", model, err // 呼び出し元の時間切れ・取り消し
+		}
+
+		e.recordModelResult(model, false)
+		errs = append(errs, fmt.Errorf("%s: %w", model, err))
+		if !last {
+			slog.Warn("生成に失敗したため、予備のモデルで生成し直します", "model", model, "error", err)
+		}
+	}
+	return "", "", errors.Join(errs...)
+}
+
+// fallbackContext 1つのモデルでの生成に使うコンテキスト
+// （最後のモデルでなければ残り時間の半分までにして、予備のモデルで生成し直す時間を残す）
+func fallbackContext(ctx context.Context, last bool) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if last || !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Until(deadline)/2)
+}
+
+// modelCoolingDown 続けて失敗したため、しばらく使わないでおくモデルか
+func (e *Engine) modelCoolingDown(model string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	state, ok := e.modelStates[model]
+	return ok && state.failures >= modelFailureLimit && time.Now().Before(state.until)
+}
+
+// recordModelResult モデルごとの生成の成否を記録
+func (e *Engine) recordModelResult(model string, ok bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if ok {
+		delete(e.modelStates, model)
+		return
+	}
+	if e.modelStates == nil {
+		e.modelStates = make(map[string]*modelState)
+	}
+	state, exists := e.modelStates[model]
+	if !exists {
+		state = &modelState{}
+		e.modelStates[model] = state
+	}
+	state.failures++
+	if state.failures >= modelFailureLimit {
+		state.until = time.Now().Add(modelCooldown)
+	}
+}
@@ -0,0 +1,107 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeModels モデルごとに失敗するか決められる、偽のOllama（問い合わせたモデルの順を返す）
+func fakeModels(t *testing.T, failing map[string]bool, response string) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var models []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req OllamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("リクエスト解析エラー: %v", err)
+		}
+		mu.Lock()
+		models = append(models, req.Model)
+		mu.Unlock()
+		if failing[req.Model] {
+			http.Error(w, "model failed", http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(OllamaResponse{Response: response, Done: true})
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, models...)
+	}
+}
+
+func TestGenerateFallsBackToNextModel(t *testing.T) {
+	server, models := fakeModels(t, map[string]bool{"big:8b": true}, "こんにちは")
+	engine := newTestEngine(t, server.URL)
+	engine.config.Model = "big:8b"
+	engine.config.FallbackModels = []string{"big:8b", " small:2b "}
+
+	for i := 0; i < 3; i++ {
+		response, model, err := engine.generateWithModel(context.Background(), "あいさつ")
+		if err != nil {
+			t.Fatalf("%d回目: 予備のモデルで生成できるはず: %v", i+1, err)
+		}
+		if response != "こんにちは" || model != "small:2b" {
+			t.Errorf("%d回目: response=%q model=%q", i+1, response, model)
+		}
+	}
+
+	// 2回続けて失敗したら、しばらくは設定したモデルを飛ばして予備のモデルだけを使う
+	want := []string{"big:8b", "small:2b", "big:8b", "small:2b", "small:2b"}
+	got := models()
+	if len(got) != len(want) {
+		t.Fatalf("問い合わせたモデル = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("問い合わせたモデル = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestGenerateFailsWhenAllModelsFail(t *testing.T) {
+	server, models := fakeModels(t, map[string]bool{"big:8b": true, "small:2b": true}, "")
+	engine := newTestEngine(t, server.URL)
+	engine.config.Model = "big:8b"
+	engine.config.FallbackModels = []string{"small:2b"}
+	engine.config.Cloud.Consent = false
+
+	if _, _, err := engine.generateWithModel(context.Background(), "あいさつ"); err == nil {
+		t.Fatal("すべてのモデルが失敗したらエラーになるはず")
+	}
+	if got := models(); len(got) != 2 {
+		t.Errorf("問い合わせたモデル = %v, want 2件", got)
+	}
+}
+
+func TestProblemRecordsModel(t *testing.T) {
+	server, _ := fakeModels(t, map[string]bool{"big:8b": true}, `TITLE: たし算
+DESCRIPTION: 2 + 3 はいくつですか？
+OPTION1: 4
+OPTION2: 5
+OPTION3: 6
+OPTION4: 7
+CORRECT: 2
+EXPLANATION: 2 と 3 をたすと 5 になります。
+DIFFICULTY: 1
+TIME: 30
+ENCOURAGEMENT: よくできました！
+TYPE: 計算`)
+	engine := newTestEngine(t, server.URL)
+	engine.config.Model = "big:8b"
+	engine.config.FallbackModels = []string{"small:2b"}
+
+	problem, err := engine.generateProblem(context.Background(), "問題を作って", nil)
+	if err != nil {
+		t.Fatalf("問題生成エラー: %v", err)
+	}
+	if problem.Model != "small:2b" {
+		t.Errorf("Model = %q, want small:2b", problem.Model)
+	}
+}
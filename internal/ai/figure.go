@@ -0,0 +1,194 @@
+package ai
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/okamyuji/studybuddy-ai/internal/figure"
+)
+
+// FigureTopic 図表の読み取り問題の単元名（グラフや資料の図を見て答える）
+const FigureTopic = "図表の読み取り"
+
+// figureSubjects 図表の読み取り問題を出題する科目
+var figureSubjects = []string{"数学", "理科", "社会"}
+
+// isFigure 図表の読み取り問題を出題するかどうか
+func isFigure(context StudyContext) bool {
+	return context.Topic == FigureTopic && slices.Contains(figureSubjects, context.Subject)
+}
+
+// generateFigureProblem 内蔵の図を画像対応モデルに見せて、その図を読み取る問題を生成
+// （画像対応モデルを使えない・問題が検証に通らないときは、内蔵の図の問題をそのまま使う）
+func (e *Engine) generateFigureProblem(ctx context.Context, studyContext StudyContext) *Problem {
+	builtin := e.getFigureProblem(studyContext.Subject, studyContext.Grade)
+	if !e.shouldTryAI() {
+		return builtin
+	}
+	image, err := figure.EncodePNG(*builtin.Figure)
+	if err != nil {
+		slog.Error("図の画像変換エラー", "error", err)
+		return builtin
+	}
+
+	// 画像対応モデルを入れていないだけのことが多いため、問題生成の失敗としては記録しない
+	model := e.VisionModel()
+	response, err := e.generateOllamaImages(ctx, model, e.buildFigurePrompt(studyContext, *builtin.Figure), []string{base64.StdEncoding.EncodeToString(image)})
+	if err != nil {
+		slog.Info("画像対応モデルで図の問題を作れないため、内蔵の問題を使います", "model", model, "error", err)
+		return builtin
+	}
+	problem, err := e.parseCheckedProblem(response, validateFigure)
+	if err != nil {
+		slog.Info("図の問題が検証に通らないため、内蔵の問題を使います", "error", err)
+		return builtin
+	}
+	problem.Figure = builtin.Figure
+	problem.Model = model
+	problem.ProblemType = FigureTopic
+	return problem
+}
+
+// buildFigurePrompt 図表の読み取り問題の生成プロンプト（図の内容も文章で渡し、答えを確かめられるようにする）
+func (e *Engine) buildFigurePrompt(context StudyContext, f figure.Figure) string {
+	gradeText := []string{"", "中1", "中2", "中3"}
+	return fmt.Sprintf(`画像の図を見て答える、%s%sの「%s」の問題を1問作成。
+
+【図の内容（答えの確認用）】
+%s
+【重要な制約】
+- 学習範囲: %s
+- 図を見ないと答えられない問題にし、問題文（DESCRIPTION）に図の数値や式を書き写さないこと
+- 問題文では、図を「図」または「グラフ」と呼ぶこと
+- 図の内容と矛盾しない、正しい答えが1つだけの4択にすること
+- 解説（EXPLANATION）では、図のどこを読めば答えがわかるかを説明すること
+
+形式:
+TITLE: タイトル
+DESCRIPTION: 問題文
+OPTION1: 選択肢1
+OPTION2: 選択肢2
+OPTION3: 選択肢3
+OPTION4: 選択肢4
+CORRECT: 1
+EXPLANATION: 解説
+DIFFICULTY: %d
+TIME: 180
+ENCOURAGEMENT: 応援メッセージ
+TYPE: %s
+
+上記形式のみで回答。`,
+		gradeText[context.Grade], context.Subject, FigureTopic, f.Describe(),
+		e.curriculumContent(context.Grade, context.Subject), max(context.Difficulty, 1), FigureTopic)
+}
+
+// validateFigure 図表の読み取り問題が図を使う問題になっているか検証
+func validateFigure(problem *Problem) error {
+	if !strings.Contains(problem.Description, "図") && !strings.Contains(problem.Description, "グラフ") {
+		return fmt.Errorf("問題文が図を使っていません。図を見て答える問題にしてください")
+	}
+	return nil
+}
+
+// figureProblem 内蔵の図表の読み取り問題（gradeが0ならどの学年でも出題する）
+type figureProblem struct {
+	grade   int
+	problem Problem
+}
+
+// figureProblems 内蔵の図表の読み取り問題（科目別）
+var figureProblems = map[string][]figureProblem{
+	"数学": {
+		{1, Problem{
+			Title:         "比例のグラフ",
+			Figure:        &figure.Figure{Caption: "比例 y = ax のグラフ", Curves: []figure.Curve{{B: -2}}},
+			Description:   "図のグラフは、比例 y = ax のグラフです。aの値を選んでください。",
+			Options:       []string{"-2", "2", "-1/2", "1/2"},
+			CorrectAnswer: 0,
+			Explanation:   "グラフは原点と点(1, -2)を通ります。y = ax に x = 1、y = -2 を代入すると a = -2 です。右下がりのグラフなので、aは負の数になります。",
+		}},
+		{2, Problem{
+			Title:         "一次関数のグラフ",
+			Figure:        &figure.Figure{Caption: "一次関数のグラフ", Curves: []figure.Curve{{B: 2, C: 1}}},
+			Description:   "図の直線の式を選んでください。",
+			Options:       []string{"y = 2x + 1", "y = x + 2", "y = -2x + 1", "y = 2x - 1"},
+			CorrectAnswer: 0,
+			Explanation:   "直線はy軸と点(0, 1)で交わるので切片は1です。xが1増えるとyが2増えるので傾きは2です。よって y = 2x + 1 です。",
+		}},
+		{3, Problem{
+			Title:         "関数 y = ax² のグラフ",
+			Figure:        &figure.Figure{Caption: "関数 y = ax² のグラフ", Curves: []figure.Curve{{A: 0.5}}},
+			Description:   "図のグラフは、関数 y = ax² のグラフです。aの値を選んでください。",
+			Options:       []string{"1/2", "2", "-1/2", "1/4"},
+			CorrectAnswer: 0,
+			Explanation:   "グラフは点(2, 2)を通ります。y = ax² に x = 2、y = 2 を代入すると 2 = 4a なので a = 1/2 です。",
+		}},
+	},
+	"理科": {
+		{0, Problem{
+			Title:         "ばねののび",
+			Figure:        &figure.Figure{Caption: "ばねにつるしたおもりの質量（g、横軸）と、ばねののび（cm、縦軸）", Labels: []string{"10", "20", "30", "40"}, Values: []float64{2, 4, 6, 8}, Step: 2},
+			Description:   "図は、ばねにつるしたおもりの質量とばねののびの関係を表したグラフです。おもりの質量を50gにしたときのばねののびを選んでください。",
+			Options:       []string{"10cm", "9cm", "12cm", "8cm"},
+			CorrectAnswer: 0,
+			Explanation:   "グラフから、おもりが10g増えるごとにばねは2cmずつのびています。ばねののびはおもりの質量に比例する（フックの法則）ので、50gでは10cmのびます。",
+		}},
+		{0, Problem{
+			Title:         "1日の気温の変化",
+			Figure:        &figure.Figure{Caption: "ある晴れた日の気温（℃）を、6時から18時まで3時間ごとに測った結果（横軸は時刻）", Labels: []string{"6", "9", "12", "15", "18"}, Values: []float64{12, 16, 21, 23, 18}, Step: 5},
+			Description:   "図は、ある晴れた日の気温を3時間ごとに測った結果のグラフです。測った時刻のうち、気温が最も高かった時刻を選んでください。",
+			Options:       []string{"15時", "12時", "18時", "9時"},
+			CorrectAnswer: 0,
+			Explanation:   "棒が最も高いのは15時（23℃）です。晴れた日は、地面があたためられてから空気があたたまるため、正午より少し後の午後2時ごろに気温が最も高くなることが多いです。",
+		}},
+	},
+	"社会": {
+		{0, Problem{
+			Title:         "年代別の人口",
+			Figure:        &figure.Figure{Caption: "ある町の年代別の人口（百人）。A: 0〜14歳、B: 15〜64歳、C: 65歳以上", Labels: []string{"A", "B", "C"}, Values: []float64{12, 48, 30}, Step: 10},
+			Description:   "図は、ある町の年代別の人口を表したグラフです。65歳以上の人口は、0〜14歳の人口の何倍ですか。",
+			Options:       []string{"2.5倍", "2倍", "3倍", "4倍"},
+			CorrectAnswer: 0,
+			Explanation:   "グラフから、65歳以上（C）は30百人、0〜14歳（A）は12百人です。30 ÷ 12 = 2.5 なので2.5倍です。子どもより高齢者が多い状態を少子高齢化といいます。",
+		}},
+		{0, Problem{
+			Title:         "米の収穫量",
+			Figure:        &figure.Figure{Caption: "A〜Eの5つの県の米の収穫量（万t）（学習用の架空の資料）", Labels: []string{"A", "B", "C", "D", "E"}, Values: []float64{60, 35, 50, 20, 45}, Step: 10},
+			Description:   "図は、A〜Eの5つの県の米の収穫量を表したグラフです。収穫量が2番目に多い県を選んでください。",
+			Options:       []string{"C", "E", "A", "B"},
+			CorrectAnswer: 0,
+			Explanation:   "グラフの棒の高さを比べると、A（60万t）が最も多く、次がC（50万t）です。資料を読み取るときは、縦軸の目盛りと単位を先に確かめましょう。",
+		}},
+	},
+}
+
+// getFigureProblem 内蔵の図表の読み取り問題を順番に取得（数学は学年に合った問題）
+func (e *Engine) getFigureProblem(subject string, grade int) *Problem {
+	var problems []Problem
+	for _, p := range figureProblems[subject] {
+		if p.grade == 0 || p.grade == grade {
+			problems = append(problems, p.problem)
+		}
+	}
+	if len(problems) == 0 {
+		problems = append(problems, figureProblems["数学"][0].problem)
+	}
+
+	subjectKey := fmt.Sprintf("%s_%s_G%d", subject, FigureTopic, grade)
+	e.mu.Lock()
+	index := e.problemIndex[subjectKey] % len(problems)
+	e.problemIndex[subjectKey] = index + 1
+	e.mu.Unlock()
+
+	problem := problems[index]
+	problem.Options = append([]string(nil), problem.Options...)
+	problem.Difficulty = min(grade+1, 5)
+	problem.EstimatedTime = 180
+	problem.Encouragement = "グラフは、軸の目盛りと単位を確かめてから読むのがコツです！"
+	problem.ProblemType = FigureTopic
+	return &problem
+}
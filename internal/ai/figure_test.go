@@ -0,0 +1,73 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestFigureProblems(t *testing.T) {
+	engine := newTestEngine(t, "http://127.0.0.1:1")
+	for subject, problems := range figureProblems {
+		for _, p := range problems {
+			problem := p.problem
+			if problem.Figure == nil {
+				t.Errorf("%s「%s」に図がない", subject, problem.Title)
+			}
+			if err := validateFigure(&problem); err != nil {
+				t.Errorf("%s「%s」: %v", subject, problem.Title, err)
+			}
+		}
+		for grade := 1; grade <= 3; grade++ {
+			if problem := engine.getFigureProblem(subject, grade); problem.Figure == nil || problem.ProblemType != FigureTopic {
+				t.Errorf("%s %d年の内蔵の図の問題 = %+v", subject, grade, problem)
+			}
+		}
+	}
+	if !slices.Contains(engine.CurriculumTopics(2, "社会"), FigureTopic) || slices.Contains(engine.CurriculumTopics(2, "英語"), FigureTopic) {
+		t.Error("図表の読み取りは数学・理科・社会の単元のはず")
+	}
+}
+
+func TestGenerateFigureProblem(t *testing.T) {
+	var got OllamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("リクエスト解析エラー: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(OllamaResponse{Response: `TITLE: 直線の傾き
+DESCRIPTION: 図の直線の傾きを選んでください。
+OPTION1: 2
+OPTION2: 1
+OPTION3: -2
+OPTION4: 1/2
+CORRECT: 1
+EXPLANATION: xが1増えるとyが2増えるので、傾きは2です。
+DIFFICULTY: 3
+TIME: 180
+ENCOURAGEMENT: その調子！
+TYPE: 図表の読み取り`, Done: true})
+	}))
+	t.Cleanup(server.Close)
+	engine := newTestEngine(t, server.URL)
+	engine.config.Cloud.Consent = false
+
+	problem, err := engine.GeneratePersonalizedProblem(context.Background(), StudyContext{Subject: "数学", Grade: 2, Difficulty: 3, Topic: FigureTopic})
+	if err != nil {
+		t.Fatalf("問題生成エラー: %v", err)
+	}
+	if got.Model != "llava" || len(got.Images) != 1 {
+		t.Errorf("画像対応モデルに図を渡すはず: model=%q images=%d", got.Model, len(got.Images))
+	}
+	if problem.Title != "直線の傾き" || problem.Figure == nil || problem.Figure.Curves[0].Expression() != "y = 2x + 1" || problem.Model != "llava" {
+		t.Errorf("図の問題 = %+v", problem)
+	}
+
+	variant, err := engine.GenerateVariant(context.Background(), *problem, StudyContext{Subject: "数学", Grade: 2})
+	if err != nil || variant.Figure == nil {
+		t.Errorf("図の問題の類題も図を使うはず: %+v, %v", variant, err)
+	}
+}
@@ -0,0 +1,150 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// AIの状態の確認の時間設定
+const (
+	HealthCheckInterval = 30 * time.Second // 定期的な確認の間隔
+	healthCheckTimeout  = 5 * time.Second
+)
+
+// HealthStatus AIの状態
+type HealthStatus int
+
+const (
+	HealthOnline   HealthStatus = iota // Ollamaが応答し、使うモデルもある
+	HealthDegraded                     // Ollamaは動いているが、モデルがない・生成に失敗している
+	HealthOffline                      // Ollamaに接続できない（用意してある問題で学習する）
+)
+
+// Icon 状態を表すアイコン
+func (s HealthStatus) Icon() string {
+	switch s {
+	case HealthOnline:
+		return "🟢"
+	case HealthDegraded:
+		return "🟡"
+	default:
+		return "🔴"
+	}
+}
+
+// Label 状態の短い説明
+func (s HealthStatus) Label() string {
+	switch s {
+	case HealthOnline:
+		return "AI接続中"
+	case HealthDegraded:
+		return "AIが不安定"
+	default:
+		return "オフライン"
+	}
+}
+
+// Health AIの状態
+type Health struct {
+	Status    HealthStatus
+	Version   string    // Ollamaのバージョン（接続できたときのみ）
+	Detail    string    // うまくいっていないときの説明
+	Failures  int       // 続けて失敗した生成の回数
+	CheckedAt time.Time // 最後に状態が分かった時刻（確認・生成の成否）
+}
+
+// HealthHook AIの状態が変わったときの処理（状態を更新したゴルーチンから呼ばれる）
+type HealthHook func(Health)
+
+// Health 今のAIの状態
+func (e *Engine) Health() Health {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.health
+}
+
+// OnHealthChange AIの状態が変わったときの処理を登録
+func (e *Engine) OnHealthChange(hook HealthHook) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.healthHooks = append(e.healthHooks, hook)
+}
+
+// CheckHealth Ollamaが動いているか、使うモデルがあるかを確かめて状態を更新
+func (e *Engine) CheckHealth(ctx context.Context) Health {
+	version, err := e.OllamaVersion(ctx)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return e.Health() // アプリの終了などで取り消された
+		}
+		detail := "Ollamaに接続できません。用意してある問題で学習します"
+		if e.cloudEnabled() {
+			detail = "Ollamaに接続できません。クラウドAIを使います"
+		}
+		return e.setHealth(func(h *Health) {
+			h.Status = HealthOffline
+			h.Version = ""
+			h.Detail = detail
+		})
+	}
+
+	model := e.GetCurrentModel()
+	models, err := e.ListModels(ctx)
+	installed := err != nil || slices.ContainsFunc(models, func(info ModelInfo) bool { return SameModel(info.Name, model) })
+	return e.setHealth(func(h *Health) {
+		h.Version = version
+		switch {
+		case !installed:
+			h.Status = HealthDegraded
+			h.Detail = fmt.Sprintf("モデル %s がインストールされていません", model)
+		case h.Status == HealthDegraded && h.Failures > 0:
+			// Ollamaは動いているが生成に失敗している。次の生成が成功するまで戻さない
+		default:
+			h.Status = HealthOnline
+			h.Detail = ""
+			h.Failures = 0
+		}
+	})
+}
+
+// MonitorHealth ctxが取り消されるまで、intervalごとにAIの状態を確かめる
+func (e *Engine) MonitorHealth(ctx context.Context, interval time.Duration) {
+	check := func() {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		defer cancel()
+		e.CheckHealth(checkCtx)
+	}
+
+	check()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+// setHealth AIの状態を更新し、変わっていれば登録した処理に知らせる
+func (e *Engine) setHealth(update func(*Health)) Health {
+	e.mu.Lock()
+	before := e.health
+	update(&e.health)
+	e.health.CheckedAt = time.Now()
+	health := e.health
+	hooks := slices.Clone(e.healthHooks)
+	e.mu.Unlock()
+
+	if health.Status != before.Status || health.Detail != before.Detail {
+		for _, hook := range hooks {
+			hook(health)
+		}
+	}
+	return health
+}
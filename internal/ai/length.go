@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"slices"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// verbosityTokens 説明の詳しさごとの1回の生成の最大トークン数（標準の大きさの解説欄のとき）
+var verbosityTokens = map[string]int{
+	config.VerbosityConcise:  384,
+	config.VerbosityNormal:   768,
+	config.VerbosityDetailed: 1536,
+}
+
+// verbosityInstructions 説明の詳しさごとの解説の書き方（プロンプトに加える）
+var verbosityInstructions = map[string]string{
+	config.VerbosityConcise:  "解説は要点だけを2〜3文で簡潔に書くこと",
+	config.VerbosityNormal:   "解説は考え方と計算の流れがわかるように5文程度で書くこと",
+	config.VerbosityDetailed: "解説は途中式や理由を省略せず、つまずきやすい点も含めて詳しく書くこと",
+}
+
+// 解説欄の標準の大きさ（これより広ければ長め、狭ければ短めに生成する）
+const (
+	referencePaneWidth  = 600
+	referencePaneHeight = 400
+)
+
+// minOutputTokens 1回の生成の最小トークン数（回答形式が途中で切れないようにする）
+const minOutputTokens = 256
+
+// SetVerbosity 説明の詳しさを設定
+func (e *Engine) SetVerbosity(verbosity string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.config.Verbosity = verbosity
+}
+
+// SetGenerationOptions 生成の詳細設定（温度・トップP・最大トークン数・コンテキスト長・モデルの保持時間・予備のモデル）を設定
+func (e *Engine) SetGenerationOptions(options config.AIConfig) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.config.Temperature = options.Temperature
+	e.config.TopP = options.TopP
+	e.config.MaxTokens = options.MaxTokens
+	e.config.ContextLength = options.ContextLength
+	e.config.KeepAlive = options.KeepAlive
+	e.config.FallbackModels = slices.Clone(options.FallbackModels)
+}
+
+// SetPaneSize 解説を表示する欄の大きさを設定（生成する文章の長さの目安にする）
+func (e *Engine) SetPaneSize(width, height float32) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.paneWidth, e.paneHeight = width, height
+}
+
+// outputTokens 説明の詳しさと解説欄の大きさから、1回の生成の最大トークン数を決める
+func (e *Engine) outputTokens() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	tokens, ok := verbosityTokens[e.config.Verbosity]
+	if !ok {
+		tokens = verbosityTokens[config.VerbosityNormal]
+	}
+	if e.paneWidth > 0 && e.paneHeight > 0 {
+		scale := float64(e.paneWidth*e.paneHeight) / (referencePaneWidth * referencePaneHeight)
+		tokens = int(float64(tokens) * min(max(scale, 0.5), 2))
+	}
+
+	tokens = max(tokens, minOutputTokens)
+	if e.config.MaxTokens > 0 {
+		tokens = min(tokens, max(e.config.MaxTokens, minOutputTokens))
+	}
+	return tokens
+}
+
+// verbosityInstruction 説明の詳しさに合わせた解説の書き方
+func (e *Engine) verbosityInstruction() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if instruction, ok := verbosityInstructions[e.config.Verbosity]; ok {
+		return instruction
+	}
+	return verbosityInstructions[config.VerbosityNormal]
+}
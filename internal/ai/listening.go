@@ -0,0 +1,166 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ListeningTopic 英語のリスニング問題の単元名（読み上げる英文を聞いて答える）
+const ListeningTopic = "リスニング"
+
+// maxListeningWords 読み上げる英文の語数の上限（1回で聞き取れる長さにする）
+const maxListeningWords = 40
+
+// isListening リスニング問題を出題するかどうか
+func isListening(context StudyContext) bool {
+	return context.Subject == "英語" && context.Topic == ListeningTopic
+}
+
+// generateListeningProblem 読み上げる英文つきのリスニング問題を生成（オフライン時は内蔵のリスニング問題）
+func (e *Engine) generateListeningProblem(ctx context.Context, studyContext StudyContext) *Problem {
+	if !e.shouldTryAI() {
+		return e.getListeningProblem(studyContext.Grade)
+	}
+	check := checkPersonalizedProblem(studyContext)
+	problem, err := e.generateProblem(ctx, e.buildListeningPrompt(studyContext), func(problem *Problem) error {
+		if err := validateListening(problem); err != nil {
+			return err
+		}
+		return check(problem)
+	})
+	if err != nil {
+		return e.getListeningProblem(studyContext.Grade)
+	}
+	problem.ProblemType = ListeningTopic
+	return problem
+}
+
+// buildListeningPrompt リスニング問題の生成プロンプト
+func (e *Engine) buildListeningPrompt(context StudyContext) string {
+	gradeText := []string{"", "中1", "中2", "中3"}
+	return fmt.Sprintf(`%s英語のリスニング問題を1問作成。
+
+【重要な制約】
+- 文法・語彙の範囲: %s
+- AUDIOには読み上げる英文だけを書くこと（%d語以内、2〜3文の会話または説明）
+- AUDIOには日本語や記号の説明を入れないこと
+- 問題文（DESCRIPTION）は日本語で、英文を聞いて答える質問だけを書き、英文そのものは書かないこと
+- 選択肢は日本語または短い英語にし、英文を聞かないと答えられない内容にすること
+- 解説（EXPLANATION）では、答えの手がかりになる英語の部分とその意味を説明すること
+
+形式:
+TITLE: タイトル
+AUDIO: 読み上げる英文
+DESCRIPTION: 問題文
+OPTION1: 選択肢1
+OPTION2: 選択肢2
+OPTION3: 選択肢3
+OPTION4: 選択肢4
+CORRECT: 1
+EXPLANATION: 解説
+DIFFICULTY: %d
+TIME: 120
+ENCOURAGEMENT: 応援メッセージ
+TYPE: %s
+
+上記形式のみで回答。`,
+		gradeText[context.Grade], e.curriculumContent(context.Grade, "英語"), maxListeningWords,
+		max(context.Difficulty, 1), ListeningTopic)
+}
+
+// validateListening 読み上げる英文がリスニング問題に使えるか検証
+func validateListening(problem *Problem) error {
+	audio := strings.TrimSpace(problem.Audio)
+	switch {
+	case audio == "":
+		return fmt.Errorf("AUDIOに読み上げる英文がありません")
+	case containsJapanese(audio):
+		return fmt.Errorf("AUDIOには英文だけを書いてください")
+	case len(strings.Fields(audio)) > maxListeningWords:
+		return fmt.Errorf("AUDIOの英文が長すぎます（%d語以内にしてください）", maxListeningWords)
+	case strings.Contains(problem.Description, audio):
+		return fmt.Errorf("問題文に読み上げる英文が書かれています。英文は問題文に書かないでください")
+	}
+	return nil
+}
+
+// listeningProblems 内蔵のリスニング問題（学年別）
+var listeningProblems = map[int][]Problem{
+	1: {
+		{
+			Title:         "好きなスポーツ",
+			Audio:         "Hi, I'm Ken. I like soccer very much. I play it with my friends every Sunday.",
+			Description:   "英文を聞いて、ケンが毎週日曜日にすることを選んでください。",
+			Options:       []string{"友だちとサッカーをする", "家族と野球をする", "ひとりでテニスをする", "友だちと泳ぎに行く"},
+			CorrectAnswer: 0,
+			Explanation:   "「I play it with my friends every Sunday.」の it は soccer のことです。毎週日曜日に友だちとサッカーをします。",
+		},
+		{
+			Title:         "持ち物",
+			Audio:         "This is my bag. I have two notebooks and a pencil case in it.",
+			Description:   "英文を聞いて、かばんの中に入っている物を選んでください。",
+			Options:       []string{"ノート2冊と筆箱", "教科書2冊と筆箱", "ノート1冊と辞書", "ノート2冊と弁当"},
+			CorrectAnswer: 0,
+			Explanation:   "「two notebooks and a pencil case」は「ノート2冊と筆箱」という意味です。",
+		},
+	},
+	2: {
+		{
+			Title:         "週末の予定",
+			Audio:         "A: What are you going to do this Saturday? B: I'm going to visit my grandmother in Nagano.",
+			Description:   "英文を聞いて、Bさんが土曜日にすることを選んでください。",
+			Options:       []string{"長野の祖母をたずねる", "長野へ旅行に行く", "祖母と買い物をする", "家で勉強する"},
+			CorrectAnswer: 0,
+			Explanation:   "「I'm going to visit my grandmother in Nagano.」は「長野にいる祖母をたずねるつもりです」という意味です。",
+		},
+		{
+			Title:         "昨日のこと",
+			Audio:         "Yesterday it was rainy, so I stayed home and read a book about space.",
+			Description:   "英文を聞いて、話し手が昨日したことを選んでください。",
+			Options:       []string{"家で宇宙の本を読んだ", "図書館で本を借りた", "雨の中を散歩した", "宇宙の映画を見た"},
+			CorrectAnswer: 0,
+			Explanation:   "「I stayed home and read a book about space.」は「家にいて宇宙についての本を読んだ」という意味です。read は過去形で「リード」ではなく「レッド」と発音します。",
+		},
+	},
+	3: {
+		{
+			Title:         "住んでいる期間",
+			Audio:         "My family moved to Osaka when I was seven. We have lived here for eight years.",
+			Description:   "英文を聞いて、話し手の家族が大阪に住んでいる期間を選んでください。",
+			Options:       []string{"8年間", "7年間", "15年間", "1年間"},
+			CorrectAnswer: 0,
+			Explanation:   "「We have lived here for eight years.」は現在完了で「8年間ずっと住んでいる」という意味です。seven は引っ越したときの年齢です。",
+		},
+		{
+			Title:         "人物の説明",
+			Audio:         "The girl who is talking with our teacher is Emi. She came from Canada last month.",
+			Description:   "英文を聞いて、エミについて正しいものを選んでください。",
+			Options:       []string{"先月カナダから来た", "先生の娘である", "来月カナダへ行く", "先生と英語を勉強している"},
+			CorrectAnswer: 0,
+			Explanation:   "「who is talking with our teacher」は関係代名詞で the girl を説明しています。「She came from Canada last month.」から、先月カナダから来たことがわかります。",
+		},
+	},
+}
+
+// getListeningProblem 内蔵のリスニング問題を順番に取得
+func (e *Engine) getListeningProblem(grade int) *Problem {
+	problems := listeningProblems[grade]
+	if len(problems) == 0 {
+		problems = listeningProblems[1]
+	}
+
+	subjectKey := fmt.Sprintf("英語_%s_G%d", ListeningTopic, grade)
+	e.mu.Lock()
+	index := e.problemIndex[subjectKey] % len(problems)
+	e.problemIndex[subjectKey] = index + 1
+	e.mu.Unlock()
+
+	problem := problems[index]
+	problem.Options = append([]string(nil), problem.Options...)
+	problem.Difficulty = min(grade+1, 5)
+	problem.EstimatedTime = 120
+	problem.Encouragement = "英語の音に慣れると、聞き取れることがどんどん増えます！"
+	problem.ProblemType = ListeningTopic
+	return &problem
+}
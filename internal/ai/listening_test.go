@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOfflineListeningProblems(t *testing.T) {
+	engine := newTestEngine(t, "http://127.0.0.1:0")
+	engine.setHealth(func(h *Health) { h.Status = HealthOffline })
+	engine.config.Cloud.Consent = false
+
+	if !slices.Contains(engine.CurriculumTopics(2, "英語"), ListeningTopic) {
+		t.Error("英語の単元にリスニングがあるはず")
+	}
+	if slices.Contains(engine.CurriculumTopics(2, "数学"), ListeningTopic) {
+		t.Error("数学の単元にリスニングはないはず")
+	}
+
+	for grade := 1; grade <= 3; grade++ {
+		studyContext := StudyContext{Subject: "英語", Grade: grade, Topic: ListeningTopic}
+		problem, err := engine.GeneratePersonalizedProblem(context.Background(), studyContext)
+		if err != nil {
+			t.Fatalf("リスニング問題の生成エラー: %v", err)
+		}
+		if err := validateListening(problem); err != nil {
+			t.Errorf("中%dの内蔵リスニング問題: %v", grade, err)
+		}
+		if err := validateProblem(problem); err != nil {
+			t.Errorf("中%dの内蔵リスニング問題の検証エラー: %v", grade, err)
+		}
+	}
+}
+
+func TestGenerateListeningProblem(t *testing.T) {
+	server, prompts := fakeOllama(t, `TITLE: 放課後
+AUDIO: I usually go to the library after school.
+DESCRIPTION: 英文を聞いて、話し手がふだん放課後に行く場所を選んでください。
+OPTION1: 図書館
+OPTION2: 公園
+OPTION3: 体育館
+OPTION4: 駅
+CORRECT: 1
+EXPLANATION: library は「図書館」です。
+DIFFICULTY: 2
+TIME: 120
+ENCOURAGEMENT: よく聞けました！
+TYPE: 語彙`)
+	engine := newTestEngine(t, server.URL)
+	engine.config.Cloud.Consent = false
+
+	problem, err := engine.GeneratePersonalizedProblem(context.Background(), StudyContext{Subject: "英語", Grade: 1, Topic: ListeningTopic, Difficulty: 2})
+	if err != nil {
+		t.Fatalf("リスニング問題の生成エラー: %v", err)
+	}
+	if problem.Audio != "I usually go to the library after school." || problem.ProblemType != ListeningTopic {
+		t.Errorf("Audio = %q, ProblemType = %q", problem.Audio, problem.ProblemType)
+	}
+	if sent := prompts(); len(sent) != 1 || !strings.Contains(sent[0], "AUDIO:") {
+		t.Errorf("リスニング問題のプロンプト: %v", sent)
+	}
+}
+
+func TestValidateListening(t *testing.T) {
+	if err := validateListening(&Problem{Audio: "こんにちは"}); err == nil {
+		t.Error("日本語のAUDIOは使えないはず")
+	}
+	audio := "I like music."
+	if err := validateListening(&Problem{Audio: audio, Description: "次の英文を聞いて答えなさい。" + audio}); err == nil {
+		t.Error("問題文に英文が書かれていたら使えないはず")
+	}
+	if err := validateListening(&Problem{Audio: strings.Repeat("word ", maxListeningWords+1)}); err == nil {
+		t.Error("長すぎる英文は使えないはず")
+	}
+	if err := validateListening(&Problem{Audio: audio, Description: "英文を聞いて答えなさい。"}); err != nil {
+		t.Errorf("使えるはずの英文: %v", err)
+	}
+}
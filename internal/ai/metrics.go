@@ -0,0 +1,37 @@
+package ai
+
+import (
+	"log/slog"
+	"time"
+)
+
+// MetricsRetention 生成の計測を残しておく期間
+const MetricsRetention = 90 * 24 * time.Hour
+
+// MetricsStore 生成の計測の記録先（モデルごとの速さを比べ、パソコンに合ったモデルを選べるようにする）
+type MetricsStore interface {
+	RecordAIMetric(model string, promptTokens, outputTokens int, totalDuration, evalDuration time.Duration) error
+}
+
+// SetMetricsStore 生成の計測の記録先を設定
+func (e *Engine) SetMetricsStore(store MetricsStore) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.metricsStore = store
+}
+
+// recordMetrics Ollamaの最後の応答に含まれるトークン数と時間を記録
+func (e *Engine) recordMetrics(model string, resp OllamaResponse) {
+	e.mu.RLock()
+	store := e.metricsStore
+	e.mu.RUnlock()
+	if store == nil || resp.TotalDuration <= 0 {
+		return
+	}
+
+	err := store.RecordAIMetric(model, resp.PromptEvalCount, resp.EvalCount,
+		time.Duration(resp.TotalDuration), time.Duration(resp.EvalDuration))
+	if err != nil {
+		slog.Error("AIの計測の記録エラー", "error", err)
+	}
+}
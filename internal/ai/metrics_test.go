@@ -0,0 +1,67 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// recordedMetric テスト用に記録した生成の計測
+type recordedMetric struct {
+	model                      string
+	promptTokens, outputTokens int
+	total, eval                time.Duration
+}
+
+type memoryMetrics struct {
+	metrics []recordedMetric
+}
+
+func (m *memoryMetrics) RecordAIMetric(model string, promptTokens, outputTokens int, total, eval time.Duration) error {
+	m.metrics = append(m.metrics, recordedMetric{model, promptTokens, outputTokens, total, eval})
+	return nil
+}
+
+func TestOllamaMetricsAreRecorded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+		_ = encoder.Encode(OllamaResponse{Response: "こんにちは"})
+		_ = encoder.Encode(OllamaResponse{
+			Done:            true,
+			PromptEvalCount: 26,
+			EvalCount:       290,
+			TotalDuration:   int64(5 * time.Second),
+			EvalDuration:    int64(4 * time.Second),
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	engine := newTestEngine(t, server.URL)
+	store := &memoryMetrics{}
+	engine.SetMetricsStore(store)
+
+	if _, err := engine.generateOllamaModel(context.Background(), "gemma2:2b", "あいさつ"); err != nil {
+		t.Fatalf("生成エラー: %v", err)
+	}
+	want := recordedMetric{"gemma2:2b", 26, 290, 5 * time.Second, 4 * time.Second}
+	if len(store.metrics) != 1 || store.metrics[0] != want {
+		t.Errorf("記録 = %+v, want [%+v]", store.metrics, want)
+	}
+}
+
+func TestMetricsSkippedWithoutDurations(t *testing.T) {
+	server, _ := fakeOllama(t, "こんにちは")
+	engine := newTestEngine(t, server.URL)
+	store := &memoryMetrics{}
+	engine.SetMetricsStore(store)
+
+	if _, err := engine.generateOllamaModel(context.Background(), "gemma2:2b", "あいさつ"); err != nil {
+		t.Fatalf("生成エラー: %v", err)
+	}
+	if len(store.metrics) != 0 {
+		t.Errorf("時間のない応答は記録しないはず: %+v", store.metrics)
+	}
+}
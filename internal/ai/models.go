@@ -0,0 +1,257 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RecommendedModel おすすめの日本語モデル
+type RecommendedModel struct {
+	Name        string
+	Description string
+}
+
+// RecommendedModels おすすめの日本語モデル（最初が既定のモデル）
+var RecommendedModels = []RecommendedModel{
+	{"7shi/ezo-gemma-2-jpn:2b-instruct-q8_0", "軽量（約2.8GB）。メモリ8GBのパソコンでも動きます"},
+	{"dsasai/llama3-elyza-jp-8b", "高精度（約4.9GB）。メモリ16GB以上がおすすめです"},
+	{"hf.co/mmnga/cyberagent-DeepSeek-R1-Distill-Qwen-14B-Japanese-gguf", "さらに高精度（約9GB）。メモリ32GB以上がおすすめです"},
+}
+
+// ModelInfo インストール済みのモデル
+type ModelInfo struct {
+	Name          string
+	Size          int64 // バイト
+	ModifiedAt    time.Time
+	ParameterSize string // 例: 2.6B
+	Quantization  string // 例: Q8_0
+}
+
+// PullProgress モデルのダウンロードの途中経過
+type PullProgress struct {
+	Status    string // Ollamaの状態（pulling manifest・downloading など）
+	Completed int64  // ダウンロード済みのバイト数
+	Total     int64  // ダウンロードするバイト数（不明なら0）
+}
+
+// Fraction ダウンロードの進み具合（0〜1。不明なら0）
+func (p PullProgress) Fraction() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return min(float64(p.Completed)/float64(p.Total), 1)
+}
+
+// OllamaVersion Ollamaが動いているか確かめ、バージョンを取得（/api/version）
+func (e *Engine) OllamaVersion(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", e.ollamaURL()+"/api/version", nil)
+	if err != nil {
+		return "", fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTPリクエストエラー: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("ollama APIエラー: %d - %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+	return result.Version, nil
+}
+
+// ListModels インストール済みのモデルの一覧を取得（少しのあいだは保存した一覧を使う）
+func (e *Engine) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	if models := e.cachedModels(); models != nil {
+		return models, nil
+	}
+	models, err := e.requestModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e.saveModels(models)
+	return models, nil
+}
+
+// requestModels Ollamaにインストール済みのモデル一覧を問い合わせる（/api/tags）
+func (e *Engine) requestModels(ctx context.Context) ([]ModelInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", e.ollamaURL()+"/api/tags", nil)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストエラー: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama APIエラー: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Models []struct {
+			Name       string    `json:"name"`
+			Size       int64     `json:"size"`
+			ModifiedAt time.Time `json:"modified_at"`
+			Details    struct {
+				ParameterSize     string `json:"parameter_size"`
+				QuantizationLevel string `json:"quantization_level"`
+			} `json:"details"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	models := make([]ModelInfo, len(result.Models))
+	for i, model := range result.Models {
+		models[i] = ModelInfo{
+			Name:          model.Name,
+			Size:          model.Size,
+			ModifiedAt:    model.ModifiedAt,
+			ParameterSize: model.Details.ParameterSize,
+			Quantization:  model.Details.QuantizationLevel,
+		}
+	}
+	return models, nil
+}
+
+// PullModel モデルをダウンロード（/api/pull）。途中経過はonProgressに知らせる（呼び出したゴルーチンから呼ばれる）
+func (e *Engine) PullModel(ctx context.Context, model string, onProgress func(PullProgress)) error {
+	jsonData, err := json.Marshal(map[string]any{"model": model, "stream": true})
+	if err != nil {
+		return fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", e.ollamaURL()+"/api/pull", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("HTTPリクエスト作成エラー: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// 数GBあるため全体の時間制限は設けず、コンテキストで取り消す
+	pullClient := &http.Client{Transport: e.httpClient.Transport}
+	resp, err := pullClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTPリクエストエラー: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ollama APIエラー: %d - %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	// 途中経過はNDJSON形式で届く（最後はstatusがsuccess）
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var line struct {
+			Status    string `json:"status"`
+			Completed int64  `json:"completed"`
+			Total     int64  `json:"total"`
+			Error     string `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue // 不正なJSONはスキップ
+		}
+		if line.Error != "" {
+			return fmt.Errorf("モデルのダウンロードエラー: %s", line.Error)
+		}
+		if onProgress != nil {
+			onProgress(PullProgress{Status: line.Status, Completed: line.Completed, Total: line.Total})
+		}
+		if line.Status == "success" {
+			e.forgetModels()
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ストリーミング読み取りエラー: %w", err)
+	}
+	return fmt.Errorf("モデルのダウンロードが途中で終わりました")
+}
+
+// DeleteModel インストール済みのモデルを削除（/api/delete）
+func (e *Engine) DeleteModel(ctx context.Context, model string) error {
+	jsonData, err := json.Marshal(map[string]string{"model": model})
+	if err != nil {
+		return fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "DELETE", e.ollamaURL()+"/api/delete", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("HTTPリクエスト作成エラー: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTPリクエストエラー: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ollama APIエラー: %d - %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	e.forgetModels()
+	return nil
+}
+
+// SetModel 問題の生成などに使うローカルのモデルを切り替える
+func (e *Engine) SetModel(model string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.config.Model = model
+}
+
+// TestModel 今のモデルがローカルのOllamaで日本語の応答を返すか確かめる（クラウドAIは使わない）
+func (e *Engine) TestModel(ctx context.Context) error {
+	response, err := e.generateOllama(ctx, "こんにちは。一言であいさつを返してください。")
+	if err != nil {
+		e.recordFailure()
+		return fmt.Errorf("接続テストエラー: %w", err)
+	}
+	e.recordSuccess()
+	if !containsJapanese(response) {
+		return fmt.Errorf("日本語応答が確認できません。モデル設定を確認してください")
+	}
+	return nil
+}
+
+// ollamaURL OllamaサーバーのURL
+func (e *Engine) ollamaURL() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.config.OllamaURL
+}
+
+// SameModel 2つのモデル名が同じモデルを指すか（タグを省略したモデル名は:latestとみなす）
+func SameModel(a, b string) bool {
+	return withDefaultTag(strings.TrimSpace(a)) == withDefaultTag(strings.TrimSpace(b))
+}
+
+// withDefaultTag タグのないモデル名に:latestを付ける
+func withDefaultTag(model string) string {
+	if strings.Contains(model[strings.LastIndex(model, "/")+1:], ":") {
+		return model
+	}
+	return model + ":latest"
+}
@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// OfflineProblem 内容パックで配られた問題（AIを使えないときに、内蔵の問題より先に出題する）
+type OfflineProblem struct {
+	Grade         int      `json:"grade"`
+	Subject       string   `json:"subject"`
+	Topic         string   `json:"topic,omitempty"` // 単元（空ならどの単元の練習でも出題する）
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Options       []string `json:"options"`
+	CorrectAnswer int      `json:"correct_answer"` // 0から数えた正解の選択肢
+	Explanation   string   `json:"explanation"`
+	Difficulty    int      `json:"difficulty"`
+}
+
+// Problem 出題する問題にする
+func (p OfflineProblem) Problem() *Problem {
+	problemType := p.Topic
+	if problemType == "" {
+		problemType = p.Subject
+	}
+	return &Problem{
+		Title:         p.Title,
+		Description:   p.Description,
+		Options:       slices.Clone(p.Options),
+		CorrectAnswer: p.CorrectAnswer,
+		Explanation:   p.Explanation,
+		Difficulty:    p.Difficulty,
+		EstimatedTime: 180,
+		Encouragement: "先生が用意した問題です。落ち着いて解いてみよう！",
+		ProblemType:   problemType,
+	}
+}
+
+// Validate 配られた問題の妥当性チェック（AIが作った問題と同じ検証に加えて、学年と科目を確かめる）
+func (p OfflineProblem) Validate() error {
+	if p.Grade < 1 || p.Grade > 3 {
+		return fmt.Errorf("無効な学年: %d (1-3である必要があります)", p.Grade)
+	}
+	if !slices.Contains(config.Subjects, p.Subject) {
+		return fmt.Errorf("無効な科目: %s", p.Subject)
+	}
+	if err := validateProblem(p.Problem()); err != nil {
+		return fmt.Errorf("「%s」: %w", p.Title, err)
+	}
+	return nil
+}
+
+// SetOfflineProblems AIを使えないときに出題する、内容パックで配られた問題を差し替える
+func (e *Engine) SetOfflineProblems(problems []OfflineProblem) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.offlineProblems = slices.Clone(problems)
+}
+
+// OfflineProblems 内容パックで配られた問題のコピー
+func (e *Engine) OfflineProblems() []OfflineProblem {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return slices.Clone(e.offlineProblems)
+}
+
+// getOfflinePackProblem 学年・科目（単元を指定していれば単元も）の合う配られた問題を順番に取得（なければnil）
+func (e *Engine) getOfflinePackProblem(context StudyContext) *Problem {
+	e.mu.RLock()
+	var problems []OfflineProblem
+	for _, p := range e.offlineProblems {
+		if p.Grade == context.Grade && p.Subject == context.Subject && (context.Topic == "" || p.Topic == context.Topic) {
+			problems = append(problems, p)
+		}
+	}
+	e.mu.RUnlock()
+	if len(problems) == 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf("pack_%s_%s_G%d", context.Subject, context.Topic, context.Grade)
+	return problems[e.nextRotation(key, len(problems))].Problem()
+}
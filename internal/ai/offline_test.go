@@ -0,0 +1,73 @@
+package ai
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOfflinePackProblems(t *testing.T) {
+	engine := newTestEngine(t, "http://127.0.0.1:0")
+	engine.setHealth(func(h *Health) { h.Status = HealthOffline })
+	engine.config.Cloud.Consent = false
+
+	packProblems := []OfflineProblem{
+		{Grade: 2, Subject: "数学", Topic: "連立方程式", Title: "連立方程式の解", Description: "x + y = 5、x - y = 1 のとき、xの値を選んでください。",
+			Options: []string{"3", "2", "4", "1"}, CorrectAnswer: 0, Explanation: "2つの式をたすと 2x = 6 なので x = 3 です。", Difficulty: 2},
+		{Grade: 2, Subject: "数学", Topic: "確率", Title: "さいころの確率", Description: "さいころを1回投げて、偶数の目が出る確率を選んでください。",
+			Options: []string{"1/2", "1/3", "1/6", "2/3"}, CorrectAnswer: 0, Explanation: "偶数の目は2・4・6の3通りなので 3/6 = 1/2 です。", Difficulty: 2},
+	}
+	for _, p := range packProblems {
+		if err := p.Validate(); err != nil {
+			t.Fatalf("%s: %v", p.Title, err)
+		}
+	}
+	engine.SetOfflineProblems(packProblems)
+
+	problem, err := engine.GeneratePersonalizedProblem(context.Background(), StudyContext{Subject: "数学", Grade: 2, Difficulty: 2, Topic: "確率"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problem.Title != "さいころの確率" || problem.ProblemType != "確率" {
+		t.Errorf("単元を指定したときの問題 = %+v", problem)
+	}
+
+	titles := map[string]bool{}
+	for range 2 {
+		problem, err := engine.GeneratePersonalizedProblem(context.Background(), StudyContext{Subject: "数学", Grade: 2, Difficulty: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		titles[problem.Title] = true
+	}
+	if len(titles) != 2 {
+		t.Errorf("配られた問題を順番に出題するはず: %v", titles)
+	}
+
+	problem, err = engine.GeneratePersonalizedProblem(context.Background(), StudyContext{Subject: "数学", Grade: 1, Difficulty: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problem.Title == "さいころの確率" || problem.Title == "連立方程式の解" {
+		t.Error("学年の違う配られた問題を出題しました")
+	}
+}
+
+func TestOfflineProblemValidate(t *testing.T) {
+	valid := OfflineProblem{Grade: 1, Subject: "英語", Title: "be動詞", Description: "I ___ a student. に入る語を選んでください。",
+		Options: []string{"am", "is", "are", "be"}, CorrectAnswer: 0, Explanation: "主語がIのときは am を使います。", Difficulty: 1}
+	if err := valid.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for name, modify := range map[string]func(p *OfflineProblem){
+		"学年":  func(p *OfflineProblem) { p.Grade = 4 },
+		"科目":  func(p *OfflineProblem) { p.Subject = "体育" },
+		"正解":  func(p *OfflineProblem) { p.CorrectAnswer = 4 },
+		"難易度": func(p *OfflineProblem) { p.Difficulty = 0 },
+	} {
+		p := valid
+		modify(&p)
+		if err := p.Validate(); err == nil {
+			t.Errorf("%s: エラーになりません", name)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package ai
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// ShuffleOptions 選択肢の順番を並べ替え、正解の番号も合わせる
+// AIは正解を1番目に置きがちなので、表示する前に必ず並べ替える（同じ問題を出し直すときも毎回変わる）
+func (p *Problem) ShuffleOptions() {
+	if len(p.Options) < 2 || p.CorrectAnswer < 0 || p.CorrectAnswer >= len(p.Options) {
+		return
+	}
+	options := make([]string, len(p.Options))
+	correct := p.CorrectAnswer
+	for i, from := range rand.Perm(len(p.Options)) {
+		options[i] = p.Options[from]
+		if from == p.CorrectAnswer {
+			correct = i
+		}
+	}
+	p.Options, p.CorrectAnswer = options, correct
+}
+
+// 選択肢の数
+const (
+	MinOptions     = 2
+	MaxOptions     = 6
+	DefaultOptions = 4
+)
+
+// optionCountFor 問題の種類と難易度に合わせた選択肢の数
+// （やさしい用語・考え方の確認は正誤の2択、難しい暗記の問題は5択・6択。計算で答えを確かめる数学は4択）
+func optionCountFor(context StudyContext) int {
+	if context.Subject == "数学" || context.Subject == "算数" {
+		return DefaultOptions
+	}
+	switch {
+	case context.Difficulty <= 1:
+		return MinOptions
+	case context.Difficulty == 4:
+		return 5
+	case context.Difficulty >= 5:
+		return MaxOptions
+	}
+	return DefaultOptions
+}
+
+// optionInstruction 選択肢の数の指示（プロンプトの制約に加える）
+func optionInstruction(count int) string {
+	if count == MinOptions {
+		return "選択肢は2つにし、用語や考え方の説明が正しいかを問う正誤問題にすること（OPTION1: 正しい、OPTION2: 誤り）"
+	}
+	return fmt.Sprintf("選択肢は%d個にし、正しい答えが1つだけになるようにすること", count)
+}
+
+// optionFormat 選択肢の数に合わせた回答形式（OPTION1〜とCORRECT）
+func optionFormat(count int) string {
+	var b strings.Builder
+	for i := 1; i <= count; i++ {
+		fmt.Fprintf(&b, "OPTION%d: 選択肢%d\n", i, i)
+	}
+	b.WriteString("CORRECT: 1")
+	return b.String()
+}
+
+// parseOptions 応答のOPTION1〜OPTION6（最後にある番号までを読み、途中の空の選択肢は検証でエラーにする）
+func parseOptions(fields map[string]string) []string {
+	last := 0
+	for i := 1; i <= MaxOptions; i++ {
+		if getField(fields, fmt.Sprintf("OPTION%d", i), "") != "" {
+			last = i
+		}
+	}
+	options := make([]string, 0, last)
+	for i := 1; i <= last; i++ {
+		options = append(options, getField(fields, fmt.Sprintf("OPTION%d", i), ""))
+	}
+	return options
+}
@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestShuffleOptions(t *testing.T) {
+	options := []string{"3", "2", "4", "1"}
+	correctAt := make([]int, len(options))
+	for range 400 {
+		problem := &Problem{Options: options, CorrectAnswer: 0}
+		problem.ShuffleOptions()
+		if len(problem.Options) != len(options) || problem.Options[problem.CorrectAnswer] != "3" {
+			t.Fatalf("並べ替えた選択肢 = %v・正解 = %d", problem.Options, problem.CorrectAnswer)
+		}
+		correctAt[problem.CorrectAnswer]++
+	}
+	if options[0] != "3" {
+		t.Errorf("元の選択肢を書き換えました: %v", options)
+	}
+	// 正解はどの位置にも置かれる
+	for position, count := range correctAt {
+		if count < 50 {
+			t.Errorf("正解が%d番目になった回数 = %d（400回中）", position+1, count)
+		}
+	}
+
+	// 正解の番号がおかしい問題はそのまま
+	broken := &Problem{Options: []string{"a", "b"}, CorrectAnswer: 5}
+	broken.ShuffleOptions()
+	if broken.Options[0] != "a" || broken.CorrectAnswer != 5 {
+		t.Errorf("正解の番号がおかしい問題を並べ替えました: %+v", broken)
+	}
+}
+
+func TestAdaptiveOptionCount(t *testing.T) {
+	engine := newTestEngine(t, "http://localhost:0")
+	for _, tc := range []struct {
+		subject    string
+		difficulty int
+		want       int
+	}{
+		{"理科", 1, 2}, {"社会", 3, 4}, {"英語", 4, 5}, {"国語", 5, 6}, {"数学", 1, 4}, {"数学", 5, 4},
+	} {
+		context := StudyContext{Subject: tc.subject, Grade: 2, Difficulty: tc.difficulty}
+		if got := optionCountFor(context); got != tc.want {
+			t.Errorf("%s 難易度%d の選択肢の数 = %d, want %d", tc.subject, tc.difficulty, got, tc.want)
+		}
+		prompt := engine.buildPersonalizedPrompt(context)
+		last := fmt.Sprintf("OPTION%d:", tc.want)
+		if !strings.Contains(prompt, last) || strings.Contains(prompt, fmt.Sprintf("OPTION%d:", tc.want+1)) {
+			t.Errorf("%s 難易度%d のプロンプトの選択肢の形式がちがいます", tc.subject, tc.difficulty)
+		}
+	}
+}
+
+func TestParseOptionCount(t *testing.T) {
+	engine := newTestEngine(t, "http://localhost:0")
+	base := "TITLE: 光合成\nDESCRIPTION: 植物は光合成で酸素を作る。この説明は正しいか。\nCORRECT: 1\nEXPLANATION: 正しい。\nDIFFICULTY: 1\n"
+
+	problem, err := engine.parseProblemResponse(base + "OPTION1: 正しい\nOPTION2: 誤り")
+	if err != nil || len(problem.Options) != 2 {
+		t.Fatalf("2択 = %+v, %v", problem, err)
+	}
+
+	six := "OPTION1: 鎌倉\nOPTION2: 室町\nOPTION3: 江戸\nOPTION4: 奈良\nOPTION5: 平安\nOPTION6: 明治"
+	if problem, err = engine.parseProblemResponse(base + six); err != nil || len(problem.Options) != 6 {
+		t.Fatalf("6択 = %+v, %v", problem, err)
+	}
+
+	// 途中の選択肢が抜けていれば使わない
+	if _, err := engine.parseProblemResponse(base + "OPTION1: 正しい\nOPTION3: 誤り"); err == nil {
+		t.Error("選択肢2が空の問題を受け入れました")
+	}
+
+	seven := Problem{Title: "t", Description: "d", Options: []string{"1", "2", "3", "4", "5", "6", "7"}, Difficulty: 3}
+	if err := validateProblem(&seven); err == nil {
+		t.Error("7択の問題を受け入れました")
+	}
+}
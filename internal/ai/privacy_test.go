@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// fakeOllama 受け取ったプロンプトを記録し、responseを返すOllamaサーバー
+func fakeOllama(t *testing.T, response string) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req OllamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("リクエスト解析エラー: %v", err)
+		}
+		mu.Lock()
+		prompts = append(prompts, req.Prompt)
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(OllamaResponse{Response: response, Done: true})
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, prompts...)
+	}
+}
+
+func newTestEngine(t *testing.T, url string) *Engine {
+	t.Helper()
+	cfg := config.Default().AI
+	cfg.OllamaURL = url
+	cfg.Cloud.APIKey = "sk-test-secret"
+	engine, err := NewEngine(cfg)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	return engine
+}
+
+func TestPromptsDoNotContainStudentName(t *testing.T) {
+	server, prompts := fakeOllama(t, "ヒカルさん、その調子！")
+	engine := newTestEngine(t, server.URL)
+	engine.SetProfile("user-1", "佐藤花子")
+
+	ctx := context.Background()
+	_ = engine.ExplainCapturedQuestion(ctx, CaptureRequest{
+		Question: "佐藤花子の宿題: 3x + 5 = 20 を解け。連絡先 hanako@example.com",
+		Subject:  "数学",
+		Grade:    1,
+	})
+	_, _ = engine.GenerateStudyTip(ctx, "数学", "佐藤花子が苦手な方程式")
+	_ = engine.GenerateSlowDownMessage(ctx, "数学", 1)
+
+	sent := prompts()
+	if len(sent) != 3 {
+		t.Fatalf("送信されたプロンプト数 = %d, want 3", len(sent))
+	}
+	for _, prompt := range sent {
+		for _, leaked := range []string{"佐藤花子", "hanako@example.com", "sk-test-secret", "user-1"} {
+			if strings.Contains(prompt, leaked) {
+				t.Errorf("プロンプトに %q が含まれています:\n%s", leaked, prompt)
+			}
+		}
+	}
+	if !strings.Contains(sent[0], "ヒカル") {
+		t.Errorf("名前が仮名に置き換わっていません:\n%s", sent[0])
+	}
+}
+
+func TestResponsesRestoreStudentName(t *testing.T) {
+	server, _ := fakeOllama(t, "ヒカルさん、ゆっくり読んでみよう。")
+	engine := newTestEngine(t, server.URL)
+	engine.SetProfile("user-1", "佐藤花子")
+
+	got, err := engine.GenerateStudyTip(context.Background(), "数学", "方程式")
+	if err != nil {
+		t.Fatalf("GenerateStudyTip: %v", err)
+	}
+	if want := "佐藤花子さん、ゆっくり読んでみよう。"; got != want {
+		t.Errorf("GenerateStudyTip() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+package ai
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/okamyuji/studybuddy-ai/internal/mathcheck"
+)
+
+// DuplicateWindow ほぼ同じ問題を出さない期間
+const DuplicateWindow = 7 * 24 * time.Hour
+
+// 問題の品質スコアの配点（合計100点）
+const (
+	qualityVerifiedPoints = 40 // 計算で正解を確認できた（確認できない問題は半分）
+	qualityLengthPoints   = 20 // 問題文と解説が適切な長さ
+	qualityCleanPoints    = 20 // 架空資料への参照や指示の書き換えのような文がない
+	qualityAccuracyPoints = 20 // 生徒の正答率（まだ解かれていない問題は半分）
+)
+
+// 問題文・解説の適切な長さ（文字数）
+const (
+	minDescriptionRunes = 10
+	maxDescriptionRunes = 400
+	minExplanationRunes = 10
+)
+
+// similarityNGram 類似ハッシュに使う文字のまとまりの長さ
+const similarityNGram = 3
+
+// maxSimilarityDistance ほぼ同じ問題とみなす類似ハッシュの違い（ビット数）の上限
+const maxSimilarityDistance = 3
+
+// QualityScore 問題の品質スコア（0〜100）
+// （計算で正解を確認できたか・問題文と解説の長さ・禁止表現がないか・同じ問題の生徒の正答率）
+func QualityScore(problem *Problem, attempts, correct int) int {
+	score := 0
+
+	if problem.CorrectAnswer >= 0 && problem.CorrectAnswer < len(problem.Options) {
+		switch mathcheck.VerifyAnswer(problem.Description, problem.Options[problem.CorrectAnswer]).Verdict {
+		case mathcheck.Correct:
+			score += qualityVerifiedPoints
+		case mathcheck.Unverifiable:
+			score += qualityVerifiedPoints / 2
+		}
+	}
+
+	descriptionRunes := len([]rune(strings.TrimSpace(problem.Description)))
+	if descriptionRunes >= minDescriptionRunes && descriptionRunes <= maxDescriptionRunes {
+		score += qualityLengthPoints / 2
+	}
+	if len([]rune(strings.TrimSpace(problem.Explanation))) >= minExplanationRunes {
+		score += qualityLengthPoints / 2
+	}
+
+	if isCleanProblem(problem) {
+		score += qualityCleanPoints
+	}
+
+	if attempts > 0 {
+		score += qualityAccuracyPoints * min(correct, attempts) / attempts
+	} else {
+		score += qualityAccuracyPoints / 2
+	}
+	return score
+}
+
+// isCleanProblem 架空資料への参照や、指示の書き換えのような文を含まないか
+func isCleanProblem(problem *Problem) bool {
+	for _, phrase := range forbiddenPhrases {
+		if strings.Contains(problem.Description, phrase) {
+			return false
+		}
+	}
+	for _, output := range problemOutputs(problem) {
+		if containsInjection(output) {
+			return false
+		}
+	}
+	return true
+}
+
+// SimilarityHash 問題文の類似ハッシュ（空白・記号・大文字小文字の違いを除いた文字の3文字ずつのまとまりから求めるSimHash。16進数16桁）
+func SimilarityHash(description string) string {
+	runes := []rune(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) && !strings.ContainsRune("+×÷=<>^", r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, description))
+	if len(runes) == 0 {
+		return ""
+	}
+
+	var weights [64]int
+	n := min(similarityNGram, len(runes))
+	for i := 0; i+n <= len(runes); i++ {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(string(runes[i : i+n])))
+		sum := h.Sum64()
+		for bit := range weights {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var hash uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			hash |= 1 << bit
+		}
+	}
+	return fmt.Sprintf("%016x", hash)
+}
+
+// IsNearDuplicate 類似ハッシュが、最近出題した問題のどれかとほぼ同じか
+func IsNearDuplicate(hash string, recent []string) bool {
+	h, err := strconv.ParseUint(hash, 16, 64)
+	if err != nil {
+		return false
+	}
+	for _, other := range recent {
+		o, err := strconv.ParseUint(other, 16, 64)
+		if err != nil {
+			continue
+		}
+		if bits.OnesCount64(h^o) <= maxSimilarityDistance {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNotRecent 最近出題した問題とほぼ同じ問題を検証エラーにする（問題の自動修正で作り直してもらう）
+func checkNotRecent(problem *Problem, recent []string) error {
+	if IsNearDuplicate(SimilarityHash(problem.Description), recent) {
+		return fmt.Errorf("最近出題した問題とほぼ同じです。単元は同じまま、別の内容の問題を作ってください")
+	}
+	return nil
+}
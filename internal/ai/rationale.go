@@ -0,0 +1,25 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxRationaleRunes 生徒が答える、答えを選んだ理由の長さの上限（文字数）
+const MaxRationaleRunes = 100
+
+// rationaleInstruction 生徒が答えた理由に直接こたえるためのフィードバックの指示（理由がなければ空）
+// 理由は生徒が入力した文章なので、区切りで囲んで指示として扱わないようにする
+func rationaleInstruction(rationale string) string {
+	rationale = strings.TrimSpace(rationale)
+	if rationale == "" {
+		return ""
+	}
+	if runes := []rune(rationale); len(runes) > MaxRationaleRunes {
+		rationale = string(runes[:MaxRationaleRunes])
+	}
+	return fmt.Sprintf(`生徒がこの答えを選んだ理由:
+%s
+理由への応答: MESSAGEとEXPLANATIONでは、生徒が書いた理由の考え方にそのまま触れること。考え方が正しければどこが良いかを具体的にほめ、考え違いがあればどこで思い違いをしたのかをやさしく説明すること（正解でも理由がちがっていれば指摘する）
+%s`, fenceContent(rationale), fencedContentRule)
+}
@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// readingLevelInstructions 解説の表現ごとの書き方（フィードバックのプロンプトに加える）
+var readingLevelInstructions = map[string]string{
+	config.ReadingLevelEasy:   "中学1年生が読んでわかる、やさしい言葉で書くこと。「すなわち」「ゆえに」などのかたい言葉や、習っていない用語は使わないこと",
+	config.ReadingLevelStrict: "入試の解答として通用する、厳密で正確な表現で書くこと。用語は教科書どおりに使い、「だいたい」「なんとなく」などのあいまいな言葉や話し言葉は使わないこと",
+}
+
+// VocabularyIssue 解説の表現に合わない言葉（言いかえがあれば Suggestion に入れる）
+type VocabularyIssue struct {
+	Word       string
+	Suggestion string
+}
+
+// readingLevelWords 解説の表現ごとに使わない言葉と言いかえ（言いかえが空なら指摘だけ）
+var readingLevelWords = map[string][]VocabularyIssue{
+	config.ReadingLevelEasy: {
+		{Word: "すなわち", Suggestion: "つまり"},
+		{Word: "ゆえに", Suggestion: "だから"},
+		{Word: "したがって", Suggestion: "だから"},
+		{Word: "任意の", Suggestion: "どんな"},
+		{Word: "自明", Suggestion: "すぐにわかること"},
+		{Word: "用いて", Suggestion: "使って"},
+		{Word: "用いる", Suggestion: "使う"},
+		{Word: "用いた", Suggestion: "使った"},
+		{Word: "及び", Suggestion: "と"},
+		{Word: "並びに", Suggestion: "と"},
+		{Word: "若しくは", Suggestion: "または"},
+		{Word: "但し", Suggestion: "ただし"},
+		{Word: "概ね", Suggestion: "だいたい"},
+		{Word: "当該", Suggestion: "その"},
+		{Word: "留意", Suggestion: "注意"},
+		{Word: "考慮して", Suggestion: "考えて"},
+		{Word: "考慮する", Suggestion: "考える"},
+		{Word: "顕著", Suggestion: "はっきりしている"},
+		{Word: "必要十分条件"},
+		{Word: "同値"},
+		{Word: "命題"},
+		{Word: "演繹"},
+		{Word: "帰納"},
+	},
+	config.ReadingLevelStrict: {
+		{Word: "だいたい", Suggestion: "およそ"},
+		{Word: "ざっくり", Suggestion: "おおまかに"},
+		{Word: "みたいな", Suggestion: "のような"},
+		{Word: "みたいに", Suggestion: "のように"},
+		{Word: "ちょっと", Suggestion: "少し"},
+		{Word: "すごく", Suggestion: "非常に"},
+		{Word: "めっちゃ", Suggestion: "非常に"},
+		{Word: "なんとなく"},
+		{Word: "っぽい"},
+		{Word: "感覚で"},
+	},
+}
+
+// SetReadingLevel 解説の表現を設定
+func (e *Engine) SetReadingLevel(level string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.config.ReadingLevel = level
+}
+
+// readingLevel 設定された解説の表現（不明な値ならやさしい表現）
+func (e *Engine) readingLevel() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if _, ok := readingLevelInstructions[e.config.ReadingLevel]; ok {
+		return e.config.ReadingLevel
+	}
+	return config.ReadingLevelEasy
+}
+
+// readingLevelInstruction 解説の表現に合わせた書き方
+func (e *Engine) readingLevelInstruction() string {
+	return readingLevelInstructions[e.readingLevel()]
+}
+
+// CheckReadingLevel 文章の中の、解説の表現に合わない言葉を出てくる順に返す
+func CheckReadingLevel(level, text string) []VocabularyIssue {
+	var issues []VocabularyIssue
+	for _, word := range readingLevelWords[level] {
+		if strings.Contains(text, word.Word) {
+			issues = append(issues, word)
+		}
+	}
+	return issues
+}
+
+// AdaptReadingLevel 解説の表現に合わない言葉を言いかえる（言いかえのない言葉はそのまま残す）
+func AdaptReadingLevel(level, text string) string {
+	for _, word := range readingLevelWords[level] {
+		if word.Suggestion != "" {
+			text = strings.ReplaceAll(text, word.Word, word.Suggestion)
+		}
+	}
+	return text
+}
+
+// adaptFeedbackReadingLevel AIのフィードバックの言葉を解説の表現に合わせ、言いかえられなかった言葉を記録する
+func (e *Engine) adaptFeedbackReadingLevel(feedback *FeedbackResponse) {
+	level := e.readingLevel()
+	var remaining []string
+	for _, field := range []*string{
+		&feedback.Message, &feedback.Explanation, &feedback.Calculation,
+		&feedback.Encouragement, &feedback.NextSteps, &feedback.TipOfDay, &feedback.Analogy,
+	} {
+		*field = AdaptReadingLevel(level, *field)
+		for _, issue := range CheckReadingLevel(level, *field) {
+			remaining = append(remaining, issue.Word)
+		}
+	}
+	if len(remaining) > 0 {
+		slog.Info("解説の表現に合わない言葉が残っています", "reading_level", level, "words", remaining)
+	}
+}
@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+func TestCheckReadingLevel(t *testing.T) {
+	text := "すなわち、両辺に3を加えると任意のxで成り立つ。これは命題として正しい。"
+	issues := CheckReadingLevel(config.ReadingLevelEasy, text)
+	var words []string
+	for _, issue := range issues {
+		words = append(words, issue.Word)
+	}
+	if strings.Join(words, "|") != "すなわち|任意の|命題" {
+		t.Errorf("やさしい表現に合わない言葉 = %v", words)
+	}
+
+	adapted := AdaptReadingLevel(config.ReadingLevelEasy, text)
+	if adapted != "つまり、両辺に3を加えるとどんなxで成り立つ。これは命題として正しい。" {
+		t.Errorf("言いかえ = %q", adapted)
+	}
+	if issues := CheckReadingLevel(config.ReadingLevelEasy, adapted); len(issues) != 1 || issues[0].Word != "命題" {
+		t.Errorf("言いかえたあとに残る言葉 = %+v", issues)
+	}
+
+	if issues := CheckReadingLevel(config.ReadingLevelStrict, "だいたい5になるみたいな感じ"); len(issues) != 2 {
+		t.Errorf("厳密な表現に合わない言葉 = %+v", issues)
+	}
+}
+
+func TestFeedbackFollowsReadingLevel(t *testing.T) {
+	server, prompts := fakeOllama(t, "MESSAGE: 正解です！\nEXPLANATION: 両辺から2を引く。ゆえに x = 3")
+	engine := newTestEngine(t, server.URL)
+	engine.config.Cloud.Consent = false
+	engine.SetReadingLevel(config.ReadingLevelEasy)
+
+	req := FeedbackRequest{
+		Problem:    Problem{Description: "x + 2 = 5 のとき x はいくつですか？", Options: []string{"3", "7"}, CorrectAnswer: 0},
+		UserAnswer: "3",
+		IsCorrect:  true,
+	}
+	feedback, err := engine.GenerateFeedback(context.Background(), req)
+	if err != nil {
+		t.Fatalf("フィードバック生成エラー: %v", err)
+	}
+	if feedback.Explanation != "両辺から2を引く。だから x = 3" {
+		t.Errorf("Explanation = %q", feedback.Explanation)
+	}
+	if got := prompts(); len(got) != 1 || !strings.Contains(got[0], readingLevelInstructions[config.ReadingLevelEasy]) {
+		t.Errorf("プロンプトに解説の表現が含まれていない: %q", got)
+	}
+
+	engine.SetReadingLevel(config.ReadingLevelStrict)
+	if _, err := engine.GenerateFeedback(context.Background(), req); err != nil {
+		t.Fatalf("フィードバック生成エラー: %v", err)
+	}
+	if got := prompts(); len(got) != 2 || !strings.Contains(got[1], readingLevelInstructions[config.ReadingLevelStrict]) {
+		t.Errorf("表現を変えると新しいプロンプトで問い合わせるはず: %d回", len(got))
+	}
+}
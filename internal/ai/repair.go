@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// maxRepairAttempts 検証に通らなかった問題をAIに直してもらう最大回数
+const maxRepairAttempts = 2
+
+// RepairStats 問題の自動修正の統計（アプリ起動からの累計）
+type RepairStats struct {
+	Rejected  int // 検証に通らなかった問題の数
+	Attempts  int // 修正を依頼した回数
+	Repaired  int // 修正して検証に通った問題の数
+	Fallbacks int // 修正できず内蔵問題にした数
+}
+
+// problemFormat 問題生成の回答形式
+const problemFormat = `TITLE: タイトル
+DESCRIPTION: 問題文
+OPTION1: 選択肢1
+OPTION2: 選択肢2
+OPTION3: 選択肢3
+OPTION4: 選択肢4
+（選択肢は2〜6個。5個以上ならOPTION5・OPTION6と続ける）
+CORRECT: 正解の選択肢の番号（1から選択肢の数まで）
+EXPLANATION: 解説
+DIFFICULTY: 難易度（1-5）
+TIME: 目安の解答時間（秒）
+ENCOURAGEMENT: 応援メッセージ
+TYPE: 単元名`
+
+// RepairStats 問題の自動修正の統計を取得
+func (e *Engine) RepairStats() RepairStats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.repairStats
+}
+
+// generateProblem 問題を生成し、検証に通らなければ誤りを伝えて最大maxRepairAttempts回直してもらう
+// （check は形式の検証のあとに行う追加の検証。nilなら省略）
+func (e *Engine) generateProblem(ctx context.Context, prompt string, check func(*Problem) error) (*Problem, error) {
+	response, model, err := e.generateWithModel(ctx, prompt)
+	if err != nil {
+		e.recordFailure()
+		return nil, err
+	}
+	e.recordSuccess()
+
+	problem, err := e.parseCheckedProblem(response, check)
+	if err == nil {
+		problem.Model = model
+		return problem, nil
+	}
+	e.updateRepairStats(func(stats *RepairStats) { stats.Rejected++ })
+
+	for attempt := 1; attempt <= maxRepairAttempts && ctx.Err() == nil; attempt++ {
+		e.updateRepairStats(func(stats *RepairStats) { stats.Attempts++ })
+		slog.Info("問題の自動修正", "attempt", attempt, "max_attempts", maxRepairAttempts, "error", err)
+
+		response, model, genErr := e.generateWithModel(ctx, buildRepairPrompt(response, err))
+		if genErr != nil {
+			e.recordFailure()
+			break
+		}
+		if problem, err = e.parseCheckedProblem(response, check); err == nil {
+			problem.Model = model
+			e.updateRepairStats(func(stats *RepairStats) { stats.Repaired++ })
+			e.logRepairStats()
+			return problem, nil
+		}
+	}
+
+	e.updateRepairStats(func(stats *RepairStats) { stats.Fallbacks++ })
+	e.logRepairStats()
+	return nil, fmt.Errorf("問題を自動修正できませんでした: %w", err)
+}
+
+// parseCheckedProblem 問題生成レスポンスをパースし、追加の検証を行う
+func (e *Engine) parseCheckedProblem(response string, check func(*Problem) error) (*Problem, error) {
+	problem, err := e.parseProblemResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	if check != nil {
+		if err := check(problem); err != nil {
+			return nil, fmt.Errorf("問題検証エラー: %w", err)
+		}
+	}
+	return problem, nil
+}
+
+// buildRepairPrompt 検証に通らなかった問題を直してもらうプロンプト
+func buildRepairPrompt(response string, validationErr error) string {
+	return fmt.Sprintf(`先ほど作成した問題に誤りがありました。誤りを直した問題を、同じ形式で作り直してください。
+
+【先ほどの回答】
+%s
+
+【誤り】
+%v
+
+【重要な制約】
+- 誤りの部分を直し、それ以外はできるだけ元の問題のままにすること
+- 正解の選択肢が本当に正しいか、計算して確かめること
+- 選択肢はすべて異なる内容にし、正解は1つだけにすること
+- 架空の資料、文章、図は参照しないこと
+
+形式:
+%s
+
+上記形式のみで回答。`, response, validationErr, problemFormat)
+}
+
+// updateRepairStats 自動修正の統計を更新
+func (e *Engine) updateRepairStats(update func(*RepairStats)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	update(&e.repairStats)
+}
+
+// logRepairStats 自動修正の統計をログに出力
+func (e *Engine) logRepairStats() {
+	stats := e.RepairStats()
+	slog.Info("問題の自動修正の統計",
+		"rejected", stats.Rejected, "attempts", stats.Attempts, "repaired", stats.Repaired, "fallbacks", stats.Fallbacks)
+}
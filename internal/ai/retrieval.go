@@ -0,0 +1,81 @@
+package ai
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RelevantThreshold 単元名と問題文のように長さの違う文章どうしで、関係があるとみなす埋め込みベクトルの類似度
+const RelevantThreshold = 0.6
+
+// プロンプトに含める最近の間違い
+const (
+	MaxMistakeExamples     = 3   // 最大件数
+	maxExampleFieldRunes   = 200 // 問題文・解答1つあたりの最大文字数
+	mistakeExamplesHeading = "【この生徒の最近の間違い】"
+)
+
+// MistakeExample 生徒が最近間違えた問題（問題生成のプロンプトに具体例として含める）
+type MistakeExample struct {
+	ProblemType   string
+	Description   string
+	UserAnswer    string
+	CorrectAnswer string
+}
+
+// buildMistakeSection 最近の間違いの具体例と、それを踏まえた出題の指示（間違いがなければ空）
+func buildMistakeSection(examples []MistakeExample) string {
+	if len(examples) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i, example := range examples[:min(len(examples), MaxMistakeExamples)] {
+		fmt.Fprintf(&b, "%d. ", i+1)
+		if example.ProblemType != "" {
+			fmt.Fprintf(&b, "単元: %s / ", truncateRunes(example.ProblemType, maxExampleFieldRunes))
+		}
+		fmt.Fprintf(&b, "問題: %s / 生徒の解答: %s / 正解: %s\n",
+			truncateRunes(example.Description, maxExampleFieldRunes),
+			truncateRunes(example.UserAnswer, maxExampleFieldRunes),
+			truncateRunes(example.CorrectAnswer, maxExampleFieldRunes))
+	}
+
+	return fmt.Sprintf(`
+
+%s
+%s
+- 上の間違いと同じ考え方を使う問題にし、生徒がつまずいた点を理解できたか確かめられるようにすること
+- 上の問題と同じ問題は出さず、数値や場面を変えること
+%s`, mistakeExamplesHeading, fenceContent(b.String()), fencedContentRule)
+}
+
+// checkPersonalizedProblem 個人に合わせた問題の追加の検証
+// （最近出題した問題や、例に挙げた間違いとほぼ同じ問題でないか。例に挙げた間違いの文章に従った痕跡がないか）
+func checkPersonalizedProblem(studyContext StudyContext) func(*Problem) error {
+	recent := slices.Clone(studyContext.RecentHashes)
+	var examples strings.Builder
+	for _, example := range studyContext.RecentMistakes {
+		recent = append(recent, SimilarityHash(example.Description))
+		examples.WriteString(example.Description + example.UserAnswer + example.CorrectAnswer)
+	}
+	return func(problem *Problem) error {
+		if err := checkNotRecent(problem, recent); err != nil {
+			return err
+		}
+		if len(studyContext.RecentMistakes) > 0 {
+			return validateGuardedOutput(examples.String(), problemOutputs(problem)...)
+		}
+		return nil
+	}
+}
+
+// truncateRunes 文字数の上限を超える部分を「…」にする
+func truncateRunes(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > limit {
+		return string(runes[:limit]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,116 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// streamInterval 生成中の途中経過を知らせる最短の間隔（画面の更新が多くなりすぎないようにする）
+const streamInterval = 100 * time.Millisecond
+
+// StreamProgress 生成中の文章の途中経過
+type StreamProgress struct {
+	Tokens  int           // これまでに受け取ったトークン数
+	Elapsed time.Duration // 生成を始めてからの時間
+	Text    string        // これまでに受け取った文章（仮名は元の名前に戻したもの）
+	Done    bool          // 生成が終わった
+}
+
+// TokensPerSecond 1秒あたりに受け取ったトークン数
+func (p StreamProgress) TokensPerSecond() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Tokens) / p.Elapsed.Seconds()
+}
+
+// streamCallbackKey 途中経過を受け取る関数のコンテキストのキー
+type streamCallbackKey struct{}
+
+// WithStreamCallback 生成中の途中経過を受け取る関数をコンテキストに設定
+// （ローカルのOllamaで生成するときに、生成処理のゴルーチンから呼ばれる。クラウドAIでは呼ばれない）
+func WithStreamCallback(ctx context.Context, onProgress func(StreamProgress)) context.Context {
+	return context.WithValue(ctx, streamCallbackKey{}, onProgress)
+}
+
+// streamCallback コンテキストに設定された、途中経過を受け取る関数（なければnil）
+func streamCallback(ctx context.Context) func(StreamProgress) {
+	onProgress, _ := ctx.Value(streamCallbackKey{}).(func(StreamProgress))
+	return onProgress
+}
+
+// streamReporter 受け取ったトークンを数え、一定の間隔で途中経過を知らせる
+type streamReporter struct {
+	onProgress func(StreamProgress)
+	restore    func(string) string
+	start      time.Time
+	last       time.Time
+	tokens     int
+}
+
+// newStreamReporter 途中経過を知らせる準備（知らせる先がなければnil）
+func (e *Engine) newStreamReporter(ctx context.Context) *streamReporter {
+	onProgress := streamCallback(ctx)
+	if onProgress == nil {
+		return nil
+	}
+	return &streamReporter{onProgress: onProgress, restore: e.redactor.Restore, start: time.Now()}
+}
+
+// add トークンを1つ受け取る（前に知らせてから一定の時間がたっていれば知らせる）
+func (r *streamReporter) add(text string) {
+	if r == nil {
+		return
+	}
+	r.tokens++
+	if now := time.Now(); now.Sub(r.last) >= streamInterval {
+		r.last = now
+		r.report(text, false)
+	}
+}
+
+// finish 生成が終わったことを知らせる
+func (r *streamReporter) finish(text string) {
+	if r == nil {
+		return
+	}
+	r.report(text, true)
+}
+
+// report 途中経過を知らせる
+func (r *streamReporter) report(text string, done bool) {
+	r.onProgress(StreamProgress{
+		Tokens:  r.tokens,
+		Elapsed: time.Since(r.start),
+		Text:    r.restore(text),
+		Done:    done,
+	})
+}
+
+// ProblemPreview 生成中の問題の文章から、解く前に見せてよいタイトルと問題文だけを取り出す
+// （選択肢や正解は含めない。書きかけの最後の行が項目名の途中なら除く）
+func ProblemPreview(text string) (title, description string) {
+	if i := strings.LastIndex(text, "\n"); i >= 0 && isPartialKey(text[i+1:]) {
+		text = text[:i]
+	} else if i < 0 && isPartialKey(text) {
+		return "", ""
+	}
+	fields := parseKeyValueResponse(text)
+	return fields["TITLE"], fields["DESCRIPTION"]
+}
+
+// isPartialKey 書きかけの行が、項目名（TITLE・DESCRIPTIONなど）の途中か
+func isPartialKey(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.Contains(line, ":") {
+		return false
+	}
+	for _, r := range line {
+		if r > unicode.MaxASCII || !(unicode.IsUpper(r) || unicode.IsDigit(r) || r == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// 翻訳先の言語
+const (
+	LanguageEnglish  = "英語"
+	LanguageJapanese = "日本語"
+)
+
+// Translation 問題・解説の翻訳（原文と並べて表示する）
+type Translation struct {
+	Language    string // 翻訳先の言語（LanguageEnglish | LanguageJapanese）
+	Title       string
+	Description string
+	Options     []string
+	Explanation string // 解説を渡さなかったときは空
+}
+
+// TranslationLanguage 文章の翻訳先の言語（ひらがな・カタカナ・漢字を含めば英語、含まなければ日本語）
+func TranslationLanguage(text string) string {
+	for _, r := range text {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			return LanguageEnglish
+		}
+	}
+	return LanguageJapanese
+}
+
+// TranslateProblem 問題・選択肢・解説をローカルのモデルで翻訳（同じ問題の翻訳は保存して使い回す）
+// 解答前に解説まで翻訳しないよう、解説を見せたくないときはExplanationを空にして渡す
+func (e *Engine) TranslateProblem(ctx context.Context, problem Problem, language string) (*Translation, error) {
+	var source strings.Builder
+	fmt.Fprintf(&source, "【タイトル】\n%s\n【問題文】\n%s\n", problem.Title, problem.Description)
+	for i, option := range problem.Options {
+		fmt.Fprintf(&source, "【選択肢%d】\n%s\n", i+1, option)
+	}
+	if problem.Explanation != "" {
+		fmt.Fprintf(&source, "【解説】\n%s\n", problem.Explanation)
+	}
+
+	format := "TITLE: タイトルの翻訳\nDESCRIPTION: 問題文の翻訳\n"
+	for i := range problem.Options {
+		format += fmt.Sprintf("OPTION%d: 選択肢%dの翻訳\n", i+1, i+1)
+	}
+	if problem.Explanation != "" {
+		format += "EXPLANATION: 解説の翻訳\n"
+	}
+
+	prompt := fmt.Sprintf(`次の中学生向けの問題を%sに翻訳してください。
+
+%s
+
+【制約】
+- 意味を変えずに自然な%sにすること。問題の答えや解き方を書き足さないこと
+- 数式・数値・記号・単位はそのまま残すこと
+- 英語の問題で、問われている英文や英単語はそのまま残し、日本語の指示だけを翻訳すること
+- 選択肢の順番を変えないこと
+%s
+
+形式:
+%s
+上記形式のみで回答。`, language, fenceContent(source.String()), language, fencedContentRule, format)
+
+	response, _, err := e.generateCached(ctx, cacheKindTranslation, prompt, translationCacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("翻訳エラー: %w", err)
+	}
+
+	fields := parseKeyValueResponse(response)
+	translation := &Translation{
+		Language:    language,
+		Title:       getField(fields, "TITLE", ""),
+		Description: getField(fields, "DESCRIPTION", ""),
+		Explanation: getField(fields, "EXPLANATION", ""),
+	}
+	for i := range problem.Options {
+		translation.Options = append(translation.Options, getField(fields, fmt.Sprintf("OPTION%d", i+1), ""))
+	}
+
+	if translation.Description == "" || (problem.Explanation != "" && translation.Explanation == "") {
+		return nil, fmt.Errorf("翻訳エラー: 応答に問題文か解説がありません")
+	}
+	for i, option := range translation.Options {
+		if option == "" {
+			return nil, fmt.Errorf("翻訳エラー: 選択肢%dの翻訳がありません", i+1)
+		}
+	}
+	outputs := append([]string{translation.Title, translation.Description, translation.Explanation}, translation.Options...)
+	if err := validateGuardedOutput(source.String(), outputs...); err != nil {
+		return nil, fmt.Errorf("翻訳エラー: %w", err)
+	}
+	return translation, nil
+}
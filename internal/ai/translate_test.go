@@ -0,0 +1,49 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslateProblem(t *testing.T) {
+	server, prompts := fakeOllama(t, "TITLE: Simultaneous equations\nDESCRIPTION: When x + y = 5 and x - y = 1, choose the value of x.\nOPTION1: 3\nOPTION2: 2\nOPTION3: 4\nOPTION4: 1")
+	engine := newTestEngine(t, server.URL)
+	engine.config.Cloud.Consent = false
+	engine.SetResponseCache(newMemoryCache())
+
+	problem := Problem{
+		Title: "連立方程式", Description: "x + y = 5、x - y = 1 のとき、xの値を選んでください。",
+		Options: []string{"3", "2", "4", "1"}, CorrectAnswer: 0,
+	}
+	ctx := context.Background()
+	for range 2 {
+		translation, err := engine.TranslateProblem(ctx, problem, TranslationLanguage(problem.Description))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if translation.Language != LanguageEnglish || !strings.HasPrefix(translation.Description, "When x + y = 5") || len(translation.Options) != 4 {
+			t.Errorf("翻訳 = %+v", translation)
+		}
+	}
+	if got := prompts(); len(got) != 1 {
+		t.Errorf("Ollamaへの問い合わせ = %d回, want 1回（2回目は保存した翻訳を使う）", len(got))
+	} else if strings.Contains(got[0], "EXPLANATION:") {
+		t.Error("解説を渡していないのに解説の翻訳を頼んでいます")
+	}
+
+	// 解説を渡したのに、応答に解説がなければ使わない
+	problem.Explanation = "2つの式をたすと 2x = 6 なので x = 3 です。"
+	if _, err := engine.TranslateProblem(ctx, problem, LanguageEnglish); err == nil {
+		t.Error("解説のない翻訳を受け入れました")
+	}
+}
+
+func TestTranslationLanguage(t *testing.T) {
+	if got := TranslationLanguage("次の英文を読んでください。I like cats."); got != LanguageEnglish {
+		t.Errorf("日本語を含む文章の翻訳先 = %s", got)
+	}
+	if got := TranslationLanguage("Choose the correct word."); got != LanguageJapanese {
+		t.Errorf("英語だけの文章の翻訳先 = %s", got)
+	}
+}
@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// MaxImageBytes 読み取る写真の大きさの上限
+const MaxImageBytes = 10 << 20
+
+// 写真の読み取りのエラー
+var (
+	ErrImageFormat     = errors.New("PNGまたはJPEGの写真を選んでください")
+	ErrImageTooLarge   = fmt.Errorf("写真が大きすぎます（%dMBまで）", MaxImageBytes>>20)
+	ErrImageUnreadable = errors.New("写真から問題の文字を読み取れませんでした")
+)
+
+// imageUnreadableMark 画像対応モデルが文字を読めなかったときに答える言葉
+const imageUnreadableMark = "読み取れません"
+
+// VisionModel 写真の問題の読み取りに使う画像対応モデル
+func (e *Engine) VisionModel() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.config.VisionModel != "" {
+		return e.config.VisionModel
+	}
+	return config.DefaultVisionModel
+}
+
+// ReadProblemImage 教科書やプリントの写真から、問題の文章と数式を読み取る
+// （写真はローカルのOllamaの画像対応モデルにだけ渡し、予備のモデルやクラウドAIには送らない）
+func (e *Engine) ReadProblemImage(ctx context.Context, image []byte) (string, error) {
+	if len(image) > MaxImageBytes {
+		return "", ErrImageTooLarge
+	}
+	switch http.DetectContentType(image) {
+	case "image/png", "image/jpeg":
+	default:
+		return "", ErrImageFormat
+	}
+
+	prompt := fmt.Sprintf(`この写真は中学生の教科書やプリントの問題です。写っている問題の文章・数式・選択肢を、そのまま日本語で書き写してください。
+
+【重要な制約】
+- 問題を解いたり、説明を加えたりしないこと
+- 数式は x^2、1/2、√3 のように1行で書くこと
+- 図や表は、読み取れる数値や言葉だけを書くこと
+- 文字が読み取れない場合は「%s」とだけ答えること
+
+書き写した問題だけを答えること。`, imageUnreadableMark)
+
+	model := e.VisionModel()
+	text, err := e.generateOllamaImages(ctx, model, prompt, []string{base64.StdEncoding.EncodeToString(image)})
+	if err != nil {
+		return "", fmt.Errorf("写真の読み取りエラー（%s）: %w", model, err)
+	}
+	text = strings.TrimSpace(strings.ReplaceAll(text, "```", ""))
+	if text == "" || strings.Contains(text, imageUnreadableMark) {
+		return "", ErrImageUnreadable
+	}
+	return text, nil
+}
+
+// stepByStepInstruction 解説を手順に分けて書く指示（クイック質問のプロンプトの制約に加える）
+func stepByStepInstruction(stepByStep bool) string {
+	if !stepByStep {
+		return ""
+	}
+	return "\n- EXPLANATIONは「1.」「2.」のように番号を付け、1行に1つずつ手順を書くこと（手順の中ではコロンを使わない）"
+}
@@ -0,0 +1,62 @@
+package ai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// pngHeader PNGと判定される最小のデータ
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestReadProblemImage(t *testing.T) {
+	var got OllamaRequest
+	response := "次の方程式を解きなさい。\n2x + 3 = 7"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("リクエスト解析エラー: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(OllamaResponse{Response: response, Done: true})
+	}))
+	t.Cleanup(server.Close)
+	engine := newTestEngine(t, server.URL)
+	ctx := context.Background()
+
+	text, err := engine.ReadProblemImage(ctx, pngHeader)
+	if err != nil {
+		t.Fatalf("写真の読み取りエラー: %v", err)
+	}
+	if text != response {
+		t.Errorf("読み取った問題 = %q", text)
+	}
+	if got.Model != "llava" || len(got.Images) != 1 || got.Images[0] != base64.StdEncoding.EncodeToString(pngHeader) {
+		t.Errorf("画像対応モデルに写真を渡すはず: model=%q images=%d", got.Model, len(got.Images))
+	}
+
+	response = "読み取れません"
+	if _, err := engine.ReadProblemImage(ctx, pngHeader); !errors.Is(err, ErrImageUnreadable) {
+		t.Errorf("文字を読めなかったときのエラー = %v", err)
+	}
+	if _, err := engine.ReadProblemImage(ctx, []byte("%PDF-1.7")); !errors.Is(err, ErrImageFormat) {
+		t.Errorf("写真でないときのエラー = %v", err)
+	}
+}
+
+func TestExplainCapturedQuestionStepByStep(t *testing.T) {
+	server, prompts := fakeOllama(t, "TITLE: 一次方程式\nSUBJECT: 数学\nTOPIC: 一次方程式\nANSWER: x = 2\nEXPLANATION: 1. 両辺から3を引く\n2. 両辺を2でわる")
+	engine := newTestEngine(t, server.URL)
+	engine.config.Cloud.Consent = false
+
+	e := engine.ExplainCapturedQuestion(context.Background(), CaptureRequest{Question: "2x + 3 = 7", Grade: 1, StepByStep: true})
+	if !strings.Contains(prompts()[0], "番号を付け") {
+		t.Errorf("手順に分けて書く指示がプロンプトにない:\n%s", prompts()[0])
+	}
+	if e.Explanation != "1. 両辺から3を引く\n2. 両辺を2でわる" {
+		t.Errorf("Explanation = %q", e.Explanation)
+	}
+}
@@ -0,0 +1,75 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// Calendar 日本の祝日と学校の年間予定を考慮した学校カレンダー
+type Calendar struct {
+	config *config.Config
+}
+
+// Day 1日分のカレンダー情報
+type Day struct {
+	Date    time.Time `json:"date"`
+	Holiday string    `json:"holiday"` // 祝日名（祝日でなければ空）
+	Break   string    `json:"break"`   // 長期休み名（夏休みなど）
+	Exam    string    `json:"exam"`    // 定期テスト期間名
+}
+
+// New 学校カレンダーを作成（学校の予定は設定から都度読み込む）
+func New(cfg *config.Config) *Calendar {
+	return &Calendar{config: cfg}
+}
+
+// Day 指定日のカレンダー情報を取得
+func (c *Calendar) Day(date time.Time) Day {
+	day := Day{
+		Date:    time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location()),
+		Holiday: HolidayName(date),
+	}
+
+	if c == nil || c.config == nil {
+		return day
+	}
+
+	for _, period := range c.config.School.Breaks {
+		if period.Contains(date) {
+			day.Break = period.Name
+			break
+		}
+	}
+	for _, period := range c.config.School.ExamWeeks {
+		if period.Contains(date) {
+			day.Exam = period.Name
+			break
+		}
+	}
+
+	return day
+}
+
+// IsSchoolDay 授業のある日かどうか（平日で祝日・長期休みでない日）
+func (d Day) IsSchoolDay() bool {
+	weekday := d.Date.Weekday()
+	return weekday != time.Saturday && weekday != time.Sunday && d.Holiday == "" && d.Break == ""
+}
+
+// IsGraceDay 学習しなくても連続記録が途切れない日（祝日・長期休み）
+func (d Day) IsGraceDay() bool {
+	return d.Holiday != "" || d.Break != ""
+}
+
+// Label 表示用の名前（祝日 > 長期休み > テスト期間の順）
+func (d Day) Label() string {
+	switch {
+	case d.Holiday != "":
+		return d.Holiday
+	case d.Break != "":
+		return d.Break
+	default:
+		return d.Exam
+	}
+}
@@ -0,0 +1,86 @@
+package calendar
+
+import "time"
+
+// fixedHolidays 日付が固定の祝日（"01-02"形式）
+var fixedHolidays = map[string]string{
+	"01-01": "元日",
+	"02-11": "建国記念の日",
+	"02-23": "天皇誕生日",
+	"04-29": "昭和の日",
+	"05-03": "憲法記念日",
+	"05-04": "みどりの日",
+	"05-05": "こどもの日",
+	"08-11": "山の日",
+	"11-03": "文化の日",
+	"11-23": "勤労感謝の日",
+}
+
+// happyMondays ハッピーマンデー制度の祝日（第n月曜日）
+var happyMondays = []struct {
+	month time.Month
+	week  int
+	name  string
+}{
+	{time.January, 2, "成人の日"},
+	{time.July, 3, "海の日"},
+	{time.September, 3, "敬老の日"},
+	{time.October, 2, "スポーツの日"},
+}
+
+// HolidayName 指定日の祝日名を取得（振替休日・国民の休日を含む。祝日でなければ空文字）
+func HolidayName(date time.Time) string {
+	if name := baseHoliday(date); name != "" {
+		return name
+	}
+
+	// 振替休日: 日曜日の祝日の後、最初の祝日でない日
+	for prev := date.AddDate(0, 0, -1); baseHoliday(prev) != ""; prev = prev.AddDate(0, 0, -1) {
+		if prev.Weekday() == time.Sunday {
+			return "振替休日"
+		}
+	}
+
+	// 国民の休日: 前日と翌日が祝日の平日
+	if date.Weekday() != time.Sunday &&
+		baseHoliday(date.AddDate(0, 0, -1)) != "" && baseHoliday(date.AddDate(0, 0, 1)) != "" {
+		return "国民の休日"
+	}
+
+	return ""
+}
+
+// baseHoliday 国民の祝日に関する法律で定められた祝日名を取得（振替休日を除く）
+func baseHoliday(date time.Time) string {
+	if name, exists := fixedHolidays[date.Format("01-02")]; exists {
+		return name
+	}
+
+	if date.Weekday() == time.Monday {
+		week := (date.Day()-1)/7 + 1
+		for _, holiday := range happyMondays {
+			if holiday.month == date.Month() && holiday.week == week {
+				return holiday.name
+			}
+		}
+	}
+
+	switch date.Month() {
+	case time.March:
+		if date.Day() == equinoxDay(date.Year(), 20.8431) {
+			return "春分の日"
+		}
+	case time.September:
+		if date.Day() == equinoxDay(date.Year(), 23.2488) {
+			return "秋分の日"
+		}
+	}
+
+	return ""
+}
+
+// equinoxDay 春分・秋分の日の近似計算（1980〜2099年で有効）
+func equinoxDay(year int, base float64) int {
+	elapsed := year - 1980
+	return int(base+0.242194*float64(elapsed)) - elapsed/4
+}
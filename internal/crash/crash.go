@@ -0,0 +1,163 @@
+package crash
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 残しておくクラッシュレポートの数（古いものから消す）
+const maxReports = 20
+
+// reportFilePrefix クラッシュレポートのファイル名の先頭
+const reportFilePrefix = "crash-"
+
+var (
+	mu         sync.Mutex
+	reportDir  string
+	appVersion string
+)
+
+// Configure クラッシュレポートの保存先とアプリのバージョンを設定（dirが空なら保存しない）
+func Configure(dir, version string) {
+	mu.Lock()
+	defer mu.Unlock()
+	reportDir = dir
+	appVersion = version
+}
+
+// Report パニックの記録
+type Report struct {
+	Task  string // パニックが起きた処理（例: 問題の生成）
+	Value any    // recoverで受け取った値
+	Stack []byte
+	Time  time.Time
+}
+
+// String クラッシュレポートの本文（問い合わせのときにそのまま送れる形式）
+func (r Report) String() string {
+	mu.Lock()
+	version := appVersion
+	mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "StudyBuddy AI クラッシュレポート\n\n")
+	fmt.Fprintf(&b, "日時: %s\n", r.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "バージョン: %s\n", version)
+	fmt.Fprintf(&b, "環境: %s/%s %s\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+	fmt.Fprintf(&b, "処理: %s\n", r.Task)
+	fmt.Fprintf(&b, "エラー: %v\n\n", r.Value)
+	b.Write(r.Stack)
+	return b.String()
+}
+
+// Write クラッシュレポートをファイルに保存し、パスを返す（古いレポートは maxReports 件まで残す）
+func Write(report Report) (string, error) {
+	mu.Lock()
+	dir := reportDir
+	mu.Unlock()
+	if dir == "" {
+		return "", nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("クラッシュレポート保存エラー: %w", err)
+	}
+	name := fmt.Sprintf("%s%s.txt", reportFilePrefix, report.Time.Format("20060102-150405.000"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(report.String()), 0600); err != nil {
+		return "", fmt.Errorf("クラッシュレポート保存エラー: %w", err)
+	}
+	pruneReports(dir)
+	return path, nil
+}
+
+// pruneReports 新しい maxReports 件より古いクラッシュレポートを消す
+func pruneReports(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), reportFilePrefix) {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) <= maxReports {
+		return
+	}
+	slices.Sort(names) // ファイル名の日時の順
+	for _, name := range names[:len(names)-maxReports] {
+		_ = os.Remove(filepath.Join(dir, name))
+	}
+}
+
+// Recover パニックから復帰し、クラッシュレポートを保存してonPanicを呼ぶ（deferで直接呼ぶこと）
+// （pathは保存したレポート。保存できなかったときは空）
+func Recover(task string, onPanic func(path string)) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	report := Report{Task: task, Value: value, Stack: debug.Stack(), Time: time.Now()}
+	path, err := Write(report)
+	if err != nil {
+		slog.Error("クラッシュレポート保存エラー", "error", err)
+	}
+	slog.Error("パニックから復帰しました", "task", task, "panic", fmt.Sprint(value), "report", path)
+	if onPanic != nil {
+		onPanic(path)
+	}
+}
+
+// Go パニックしてもアプリを終了させないgoroutineを開始
+func Go(task string, fn func(), onPanic func(path string)) {
+	go func() {
+		defer Recover(task, onPanic)
+		fn()
+	}()
+}
+
+// Version Configureで設定したアプリのバージョン
+func Version() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return appVersion
+}
+
+// RecentReports 保存したクラッシュレポートのうち、新しいものからn件のパス（新しい順）
+func RecentReports(n int) []string {
+	mu.Lock()
+	dir := reportDir
+	mu.Unlock()
+	if dir == "" || n <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), reportFilePrefix) {
+			names = append(names, entry.Name())
+		}
+	}
+	slices.Sort(names) // ファイル名の日時の順
+	slices.Reverse(names)
+	paths := make([]string, 0, min(n, len(names)))
+	for _, name := range names[:min(n, len(names))] {
+		paths = append(paths, filepath.Join(dir, name))
+	}
+	return paths
+}
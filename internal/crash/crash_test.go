@@ -0,0 +1,76 @@
+package crash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoRecoversAndWritesReport(t *testing.T) {
+	dir := t.TempDir()
+	Configure(dir, "1.2.3")
+	t.Cleanup(func() { Configure("", "") })
+
+	done := make(chan string, 1)
+	Go("問題の生成", func() {
+		var problems []string
+		_ = problems[3] // 範囲外でパニック
+	}, func(path string) { done <- path })
+
+	var path string
+	select {
+	case path = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("パニックから復帰しなかった")
+	}
+	if filepath.Dir(path) != dir {
+		t.Fatalf("レポートの保存先 = %q, want %q", path, dir)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("レポート読み込みエラー: %v", err)
+	}
+	for _, want := range []string{"処理: 問題の生成", "バージョン: 1.2.3", "index out of range", "crash_test.go"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("レポートに %q がない:\n%s", want, data)
+		}
+	}
+}
+
+func TestRecoverWithoutPanic(t *testing.T) {
+	called := false
+	func() {
+		defer Recover("何もしない", func(string) { called = true })
+	}()
+	if called {
+		t.Error("パニックしていなければonPanicは呼ばないはず")
+	}
+}
+
+func TestPruneReports(t *testing.T) {
+	dir := t.TempDir()
+	Configure(dir, "test")
+	t.Cleanup(func() { Configure("", "") })
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < maxReports+3; i++ {
+		if _, err := Write(Report{Task: "test", Value: i, Time: start.Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatalf("保存エラー: %v", err)
+		}
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != maxReports {
+		t.Fatalf("残ったレポート = %d件, want %d件", len(entries), maxReports)
+	}
+	if entries[0].Name() != "crash-20260101-000003.000.txt" {
+		t.Errorf("古いレポートから消すはず: 最も古いのは %s", entries[0].Name())
+	}
+
+	recent := RecentReports(2)
+	if len(recent) != 2 || filepath.Base(recent[0]) != "crash-20260101-000022.000.txt" || filepath.Base(recent[1]) != "crash-20260101-000021.000.txt" {
+		t.Errorf("新しいレポート = %v", recent)
+	}
+}
@@ -0,0 +1,200 @@
+package curriculum
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// FileName 保護者・先生が編集した学習範囲のファイル名（アプリケーションデータディレクトリに置く）
+const FileName = "curriculum.json"
+
+// 1つの科目に登録できる単元の数と、単元名の長さの上限
+const (
+	MaxTopics      = 30
+	MaxTopicLength = 40
+)
+
+// Curriculum 学年・科目ごとの学習範囲の単元一覧（学年 → 科目 → 単元）
+type Curriculum map[int]map[string][]string
+
+// defaultCurriculum 標準の学習範囲（2024年度学習指導要領準拠）
+var defaultCurriculum = Curriculum{
+	1: {
+		"数学": {"正の数・負の数", "文字と式", "一次方程式", "比例と反比例", "平面図形", "空間図形", "データの活用"},
+		"英語": {"アルファベット", "基本単語", "be動詞", "一般動詞", "疑問文", "否定文", "現在進行形"},
+		"国語": {"漢字の読み書き", "詩歌の鑑賞", "説明文の読解", "古典の基礎", "文法（品詞）"},
+		"理科": {"植物の生活と種類", "身のまわりの物質", "光・音・力", "大地の変化"},
+		"社会": {"世界の地理", "日本の地理", "歴史（古代文明から平安時代）"},
+	},
+	2: {
+		"数学": {"式の計算", "連立方程式", "一次関数", "図形の性質と合同", "確率", "データの活用"},
+		"英語": {"過去形", "未来形", "助動詞", "比較級・最上級", "不定詞", "動名詞"},
+		"国語": {"短歌・俳句", "説明文・論説文", "小説", "古典（古文・漢文の基礎）", "敬語"},
+		"理科": {"動物の生活と生物の変遷", "電流とその利用", "化学変化と原子・分子", "天気とその変化"},
+		"社会": {"日本の歴史（鎌倉時代から江戸時代）", "世界と日本の地理"},
+	},
+	3: {
+		"数学": {"二次方程式", "二次関数", "相似", "三平方の定理", "円の性質", "標本調査"},
+		"英語": {"現在完了", "受動態", "関係代名詞", "間接疑問文", "分詞"},
+		"国語": {"近現代文学", "古典文学", "文法の総復習", "論説文・評論文の読解"},
+		"理科": {"生命の連続性", "運動とエネルギー", "化学変化とイオン", "地球と宇宙"},
+		"社会": {"日本の歴史（明治維新から現代）", "公民（政治・経済・国際社会）"},
+	},
+}
+
+// Default 標準の学習範囲
+func Default() Curriculum {
+	return defaultCurriculum.Clone()
+}
+
+// Path 編集した学習範囲のファイルのパス
+func Path() string {
+	return filepath.Join(config.GetAppDir(), FileName)
+}
+
+// Load 学習範囲のファイルを読み込む（ファイルがなければ標準の学習範囲。ファイルにない学年・科目は標準の単元を使う）
+func Load(path string) (Curriculum, error) {
+	c := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("学習範囲ファイル読み込みエラー: %w", err)
+	}
+
+	var edited Curriculum
+	if err := json.Unmarshal(data, &edited); err != nil {
+		return nil, fmt.Errorf("学習範囲ファイル解析エラー: %w", err)
+	}
+	if err := edited.Validate(); err != nil {
+		return nil, fmt.Errorf("学習範囲ファイルの内容が正しくありません: %w", err)
+	}
+	for grade, subjects := range edited {
+		for subject, topics := range subjects {
+			c.Set(grade, subject, topics)
+		}
+	}
+	return c, nil
+}
+
+// Save 学習範囲をファイルに保存
+func Save(path string, c Curriculum) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("学習範囲ディレクトリ作成エラー: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("学習範囲データ変換エラー: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("学習範囲ファイル保存エラー: %w", err)
+	}
+	return nil
+}
+
+// Validate 学習範囲の妥当性チェック（学年は1〜3、科目はアプリの5教科、単元は空でなく重複しない）
+func (c Curriculum) Validate() error {
+	for grade, subjects := range c {
+		if grade < 1 || grade > 3 {
+			return fmt.Errorf("無効な学年: %d (1-3である必要があります)", grade)
+		}
+		for subject, topics := range subjects {
+			if !slices.Contains(config.Subjects, subject) {
+				return fmt.Errorf("無効な科目: %s", subject)
+			}
+			if len(topics) == 0 {
+				return fmt.Errorf("中%d %sの単元がありません", grade, subject)
+			}
+			if len(topics) > MaxTopics {
+				return fmt.Errorf("中%d %sの単元が多すぎます: %d (%d個以下である必要があります)", grade, subject, len(topics), MaxTopics)
+			}
+			for i, topic := range topics {
+				switch {
+				case strings.TrimSpace(topic) == "":
+					return fmt.Errorf("中%d %sに空の単元があります", grade, subject)
+				case len([]rune(topic)) > MaxTopicLength:
+					return fmt.Errorf("単元名が長すぎます: %s (%d文字以下である必要があります)", topic, MaxTopicLength)
+				case slices.Contains(topics[:i], topic):
+					return fmt.Errorf("中%d %sの単元が重複しています: %s", grade, subject, topic)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Topics 学年・科目の単元一覧（登録がなければnil）
+func (c Curriculum) Topics(grade int, subject string) []string {
+	return slices.Clone(c[grade][subject])
+}
+
+// Content 学年・科目の学習範囲の文章（AIへのプロンプトに使う。例: 「式の計算、連立方程式、一次関数」）
+func (c Curriculum) Content(grade int, subject string) string {
+	return strings.Join(c[grade][subject], "、")
+}
+
+// Set 学年・科目の単元一覧を置き換える（前後の空白は取り除く）
+func (c Curriculum) Set(grade int, subject string, topics []string) {
+	if c[grade] == nil {
+		c[grade] = make(map[string][]string)
+	}
+	trimmed := make([]string, len(topics))
+	for i, topic := range topics {
+		trimmed[i] = strings.TrimSpace(topic)
+	}
+	c[grade][subject] = trimmed
+}
+
+// Merge 別の学習範囲の単元を、登録していない単元だけ後ろに加える（加えた単元の数を返す）
+func (c Curriculum) Merge(other Curriculum) int {
+	added := 0
+	for grade, subjects := range other {
+		for subject, topics := range subjects {
+			merged := slices.Clone(c[grade][subject])
+			for _, topic := range topics {
+				if topic = strings.TrimSpace(topic); !slices.Contains(merged, topic) {
+					merged = append(merged, topic)
+					added++
+				}
+			}
+			c.Set(grade, subject, merged)
+		}
+	}
+	return added
+}
+
+// Customized 標準の学習範囲と違う学年・科目だけの学習範囲（編集した単元を配るときに使う）
+func (c Curriculum) Customized() Curriculum {
+	customized := make(Curriculum)
+	for grade, subjects := range c {
+		for subject, topics := range subjects {
+			if !slices.Equal(topics, defaultCurriculum[grade][subject]) {
+				customized.Set(grade, subject, topics)
+			}
+		}
+	}
+	return customized
+}
+
+// Clone 学習範囲のコピー（編集しても元の学習範囲は変わらない）
+func (c Curriculum) Clone() Curriculum {
+	cloned := make(Curriculum, len(c))
+	for grade, subjects := range c {
+		cloned[grade] = make(map[string][]string, len(subjects))
+		for subject, topics := range subjects {
+			cloned[grade][subject] = slices.Clone(topics)
+		}
+	}
+	return cloned
+}
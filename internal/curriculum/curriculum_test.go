@@ -0,0 +1,98 @@
+package curriculum
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadMissingFileUsesDefault(t *testing.T) {
+	c, err := Load(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Content(2, "数学"); got != "式の計算、連立方程式、一次関数、図形の性質と合同、確率、データの活用" {
+		t.Errorf("中2 数学 = %q", got)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("標準の学習範囲が検証に通りません: %v", err)
+	}
+}
+
+func TestSaveAndLoadKeepsEditedTopics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", FileName)
+	c := Default()
+	c.Set(3, "数学", append(c.Topics(3, "数学"), " 高校範囲：数と式 "))
+	if err := Save(path, c); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topics := loaded.Topics(3, "数学"); topics[len(topics)-1] != "高校範囲：数と式" {
+		t.Errorf("中3 数学 = %v", topics)
+	}
+	if !slices.Equal(loaded.Topics(1, "英語"), Default().Topics(1, "英語")) {
+		t.Errorf("編集していない科目が変わりました: %v", loaded.Topics(1, "英語"))
+	}
+	if slices.Contains(Default().Topics(3, "数学"), "高校範囲：数と式") {
+		t.Error("編集で標準の学習範囲が変わりました")
+	}
+}
+
+func TestLoadMergesPartialFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(`{"1": {"社会": ["私立中対策：時事問題"]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Content(1, "社会"); got != "私立中対策：時事問題" {
+		t.Errorf("中1 社会 = %q", got)
+	}
+	if c.Content(1, "数学") != Default().Content(1, "数学") {
+		t.Errorf("ファイルにない科目は標準の単元を使う: %q", c.Content(1, "数学"))
+	}
+}
+
+func TestLoadRejectsInvalidFile(t *testing.T) {
+	for name, data := range map[string]string{
+		"JSONでない": `{"1": `,
+		"学年":      `{"4": {"数学": ["数列"]}}`,
+		"科目":      `{"1": {"体育": ["球技"]}}`,
+		"単元なし":    `{"1": {"数学": []}}`,
+		"空の単元":    `{"1": {"数学": ["正の数・負の数", " "]}}`,
+		"重複":      `{"1": {"数学": ["一次方程式", "一次方程式"]}}`,
+	} {
+		path := filepath.Join(t.TempDir(), FileName)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("%s: エラーになりません", name)
+		}
+	}
+}
+
+func TestMergeAddsNewTopics(t *testing.T) {
+	c := Default()
+	added := c.Merge(Curriculum{2: {"数学": {"連立方程式", "私立中対策：規則性"}}, 3: {"国語": {"高校範囲：古文の助動詞"}}})
+	if added != 2 {
+		t.Errorf("加えた単元 = %d", added)
+	}
+	topics := c.Topics(2, "数学")
+	if topics[len(topics)-1] != "私立中対策：規則性" || len(topics) != len(Default().Topics(2, "数学"))+1 {
+		t.Errorf("中2 数学 = %v", topics)
+	}
+	if !slices.Contains(c.Topics(3, "国語"), "高校範囲：古文の助動詞") {
+		t.Errorf("中3 国語 = %v", c.Topics(3, "国語"))
+	}
+	if customized := c.Customized(); len(customized) != 2 || len(customized[2]) != 1 || len(customized[3]) != 1 {
+		t.Errorf("標準と違う学習範囲 = %v", customized)
+	}
+}
@@ -0,0 +1,386 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 教室モードの役割
+const (
+	RoleTeacher   = "teacher"   // 先生
+	RoleAssistant = "assistant" // 補助の先生（課題の作成とクラスの記録の確認だけ）
+	RoleStudent   = "student"   // 生徒（役割を登録していないプロフィールも生徒）
+)
+
+// 役割ごとにできること
+const (
+	CapCreateAssignments = "create_assignments" // 課題を作る
+	CapViewAllData       = "view_all_data"      // クラス全員の課題・学習記録を見る（なければ自分の分だけ）
+	CapManageFlags       = "manage_flags"       // 機能フラグを切り替える
+	CapManageRoles       = "manage_roles"       // メンバーの役割を変える
+)
+
+// RoleCapabilities 役割ごとにできること
+var RoleCapabilities = map[string][]string{
+	RoleTeacher:   {CapCreateAssignments, CapViewAllData, CapManageFlags, CapManageRoles},
+	RoleAssistant: {CapCreateAssignments, CapViewAllData},
+	RoleStudent:   nil,
+}
+
+// ErrPermissionDenied 役割にない操作
+var ErrPermissionDenied = errors.New("この操作をする権限がありません")
+
+// ClassroomMember 教室モードのメンバー（プロフィールと役割）
+type ClassroomMember struct {
+	User User   `json:"user"`
+	Role string `json:"role"`
+}
+
+// Assignment 教室モードの課題
+type Assignment struct {
+	ID           string    `json:"id"`
+	CreatedBy    string    `json:"created_by"`
+	Subject      string    `json:"subject"`
+	Topic        string    `json:"topic"` // 空なら科目全体
+	ProblemCount int       `json:"problem_count"`
+	DueDate      string    `json:"due_date"` // 取り組む日（"2006-01-02"）
+	Note         string    `json:"note"`
+	StudentIDs   []string  `json:"student_ids"` // 課題を配った生徒
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Classroom 教室モードのデータの読み書き（操作するプロフィールの役割で、できることを確かめる）
+type Classroom struct {
+	db     *DB
+	userID string
+	role   string
+}
+
+// Classroom 操作するプロフィールの役割で、教室モードのデータを読み書きする
+func (db *DB) Classroom(userID string) (*Classroom, error) {
+	role, err := db.classroomRole(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &Classroom{db: db, userID: userID, role: role}, nil
+}
+
+// classroomRole プロフィールの役割（登録がなければ生徒）
+func (db *DB) classroomRole(userID string) (string, error) {
+	var role string
+	err := db.QueryRow(`SELECT role FROM classroom_members WHERE user_id = ?`, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return RoleStudent, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("役割取得エラー: %w", err)
+	}
+	return role, nil
+}
+
+// countTeachers 先生の人数（削除したプロフィールの役割は数えない）
+func (db *DB) countTeachers() (int, error) {
+	var count int
+	err := db.QueryRow(`
+		SELECT COUNT(*) FROM classroom_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.role = ?
+	`, RoleTeacher).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("先生の人数取得エラー: %w", err)
+	}
+	return count, nil
+}
+
+// HasTeacher 教室モードの先生が決まっているか（決まるまでは役割で操作を制限しない）
+func (db *DB) HasTeacher() (bool, error) {
+	count, err := db.countTeachers()
+	return count > 0, err
+}
+
+// AuthorizeClassroom プロフィールが役割でできる操作か確かめる（先生が決まるまでは、家庭で使うときと同じく制限しない）
+func (db *DB) AuthorizeClassroom(userID, capability string) error {
+	hasTeacher, err := db.HasTeacher()
+	if err != nil || !hasTeacher {
+		return err
+	}
+	c, err := db.Classroom(userID)
+	if err != nil {
+		return err
+	}
+	return c.Authorize(capability)
+}
+
+// Role 操作するプロフィールの役割
+func (c *Classroom) Role() string {
+	return c.role
+}
+
+// Can 役割でできる操作か
+func (c *Classroom) Can(capability string) bool {
+	return slices.Contains(RoleCapabilities[c.role], capability)
+}
+
+// Authorize 役割でできる操作か確かめる（できなければErrPermissionDenied）
+func (c *Classroom) Authorize(capability string) error {
+	if !c.Can(capability) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// BecomeTeacher 先生がまだいない教室で、操作するプロフィールを先生にする
+func (c *Classroom) BecomeTeacher() error {
+	hasTeacher, err := c.db.HasTeacher()
+	if err != nil {
+		return err
+	}
+	if hasTeacher {
+		return ErrPermissionDenied
+	}
+	if err := c.db.setClassroomRole(c.userID, RoleTeacher); err != nil {
+		return err
+	}
+	c.role = RoleTeacher
+	return nil
+}
+
+// SetRole メンバーの役割を変える（先生だけ。最後の先生は先生のままにする）
+func (c *Classroom) SetRole(userID, role string) error {
+	if err := c.Authorize(CapManageRoles); err != nil {
+		return err
+	}
+	if _, ok := RoleCapabilities[role]; !ok {
+		return fmt.Errorf("無効な役割: %s", role)
+	}
+	current, err := c.db.classroomRole(userID)
+	if err != nil {
+		return err
+	}
+	if current == RoleTeacher && role != RoleTeacher {
+		count, err := c.db.countTeachers()
+		if err != nil {
+			return err
+		}
+		if count <= 1 {
+			return errors.New("先生が1人もいなくなるため、役割を変えられません。先にほかのメンバーを先生にしてください")
+		}
+	}
+	if err := c.db.setClassroomRole(userID, role); err != nil {
+		return err
+	}
+	if userID == c.userID {
+		c.role = role
+	}
+	return nil
+}
+
+// setClassroomRole プロフィールの役割を保存
+func (db *DB) setClassroomRole(userID, role string) error {
+	_, err := db.Exec(`
+		INSERT INTO classroom_members (user_id, role, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET role = excluded.role, updated_at = excluded.updated_at
+	`, userID, role, time.Now())
+	if err != nil {
+		return fmt.Errorf("役割保存エラー: %w", err)
+	}
+	return nil
+}
+
+// Members 教室のメンバー（クラス全員を見られない役割では自分だけ）
+func (c *Classroom) Members() ([]ClassroomMember, error) {
+	query := `
+		SELECT u.id, u.name, u.grade, u.created_at, u.last_login, COALESCE(m.role, ?)
+		FROM users u
+		LEFT JOIN classroom_members m ON m.user_id = u.id
+	`
+	args := []any{RoleStudent}
+	if !c.Can(CapViewAllData) {
+		query += ` WHERE u.id = ?`
+		args = append(args, c.userID)
+	}
+	query += ` ORDER BY u.created_at ASC, u.id ASC`
+
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("メンバー取得エラー: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var members []ClassroomMember
+	for rows.Next() {
+		var member ClassroomMember
+		user := &member.User
+		if err := rows.Scan(&user.ID, &user.Name, &user.Grade, &user.CreatedAt, &user.LastLogin, &member.Role); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+// CreateAssignment 課題を作って生徒に配る（先生・補助の先生だけ）
+func (c *Classroom) CreateAssignment(a *Assignment) error {
+	if err := c.Authorize(CapCreateAssignments); err != nil {
+		return err
+	}
+	if strings.TrimSpace(a.Subject) == "" || a.ProblemCount <= 0 {
+		return errors.New("課題の科目と問題数を指定してください")
+	}
+	if _, err := time.Parse(time.DateOnly, a.DueDate); err != nil {
+		return fmt.Errorf("課題の日付が正しくありません: %w", err)
+	}
+	if len(a.StudentIDs) == 0 {
+		return errors.New("課題を配る生徒を選んでください")
+	}
+	a.CreatedBy = c.userID
+
+	return c.db.inTx(func(exec execFunc) error {
+		_, err := exec(`
+			INSERT INTO classroom_assignments (id, created_by, subject, topic, problem_count, due_date, note, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, a.ID, a.CreatedBy, a.Subject, a.Topic, a.ProblemCount, a.DueDate, a.Note, a.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("課題保存エラー: %w", err)
+		}
+		for _, studentID := range a.StudentIDs {
+			if _, err := exec(`INSERT INTO assignment_students (assignment_id, user_id) VALUES (?, ?)`, a.ID, studentID); err != nil {
+				return fmt.Errorf("課題の配布先保存エラー: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// Assignments 期間内（from以上to以下の日付）の課題（クラス全員を見られない役割では自分に配られた課題だけ）
+func (c *Classroom) Assignments(from, to string) ([]Assignment, error) {
+	query := `
+		SELECT id, created_by, subject, topic, problem_count, due_date, note, created_at
+		FROM classroom_assignments
+		WHERE due_date >= ? AND due_date <= ?
+	`
+	args := []any{from, to}
+	if !c.Can(CapViewAllData) {
+		query += ` AND id IN (SELECT assignment_id FROM assignment_students WHERE user_id = ?)`
+		args = append(args, c.userID)
+	}
+	query += ` ORDER BY due_date ASC, created_at ASC`
+
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("課題取得エラー: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var assignments []Assignment
+	for rows.Next() {
+		var a Assignment
+		if err := rows.Scan(&a.ID, &a.CreatedBy, &a.Subject, &a.Topic, &a.ProblemCount, &a.DueDate, &a.Note, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	_ = rows.Close()
+
+	for i := range assignments {
+		studentIDs, err := c.assignmentStudents(assignments[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		assignments[i].StudentIDs = studentIDs
+	}
+	return assignments, nil
+}
+
+// assignmentStudents 課題を配った生徒（クラス全員を見られない役割では自分だけ）
+func (c *Classroom) assignmentStudents(assignmentID string) ([]string, error) {
+	rows, err := c.db.Query(`SELECT user_id FROM assignment_students WHERE assignment_id = ? ORDER BY user_id ASC`, assignmentID)
+	if err != nil {
+		return nil, fmt.Errorf("課題の配布先取得エラー: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var studentIDs []string
+	for rows.Next() {
+		var studentID string
+		if err := rows.Scan(&studentID); err != nil {
+			return nil, err
+		}
+		if studentID == c.userID || c.Can(CapViewAllData) {
+			studentIDs = append(studentIDs, studentID)
+		}
+	}
+	return studentIDs, rows.Err()
+}
+
+// StudySessions メンバーの期間内の学習セッション（クラス全員を見られない役割では自分の分だけ）
+func (c *Classroom) StudySessions(userID string, from, to time.Time) ([]StudySession, error) {
+	if userID != c.userID && !c.Can(CapViewAllData) {
+		return nil, ErrPermissionDenied
+	}
+	return c.db.GetStudySessionsBetween(userID, from, to)
+}
+
+// AssignmentProgress 課題に生徒が取り組んだ状況
+type AssignmentProgress struct {
+	Assignment Assignment
+	Solved     map[string]int // 生徒ごとの、取り組む日にその科目（単元があればその単元）で解いた問題数
+}
+
+// Completed 生徒が課題の問題数まで解いたか
+func (p *AssignmentProgress) Completed(userID string) bool {
+	return p.Solved[userID] >= p.Assignment.ProblemCount
+}
+
+// Participation 期間内（from以上to以下の日付）の課題に、配った生徒が取り組んだ状況（生徒の役割では自分の分だけ）
+func (c *Classroom) Participation(from, to string) ([]AssignmentProgress, error) {
+	assignments, err := c.Assignments(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make([]AssignmentProgress, 0, len(assignments))
+	for _, assignment := range assignments {
+		day, err := time.ParseInLocation(time.DateOnly, assignment.DueDate, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("課題の日付が正しくありません: %w", err)
+		}
+		p := AssignmentProgress{Assignment: assignment, Solved: make(map[string]int)}
+		for _, studentID := range assignment.StudentIDs {
+			solved, err := c.db.countSolved(studentID, assignment.Subject, assignment.Topic, day, day.AddDate(0, 0, 1))
+			if err != nil {
+				return nil, err
+			}
+			p.Solved[studentID] = solved
+		}
+		progress = append(progress, p)
+	}
+	return progress, nil
+}
+
+// countSolved 期間内に科目（topicが空でなければその単元）で解いた問題数
+func (db *DB) countSolved(userID, subject, topic string, from, to time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM problem_results r
+		JOIN study_sessions s ON s.id = r.session_id
+		WHERE s.user_id = ? AND s.subject = ? AND r.created_at >= ? AND r.created_at < ?
+	`
+	args := []any{userID, subject, from, to}
+	if topic != "" {
+		query += ` AND r.problem_type = ?`
+		args = append(args, topic)
+	}
+
+	var count int
+	if err := db.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("課題の取り組み状況取得エラー: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,162 @@
+package database_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+// createMembers テスト用のプロフィールを作る
+func createMembers(t *testing.T, db *database.DB, ids ...string) {
+	t.Helper()
+	for i, id := range ids {
+		user := &database.User{ID: id, Name: id, Grade: 2, CreatedAt: time.Date(2026, 4, 1, 9, i, 0, 0, time.UTC)}
+		if err := db.CreateUser(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// classroom プロフィールの役割で教室モードのデータを読み書きする
+func classroom(t *testing.T, db *database.DB, userID string) *database.Classroom {
+	t.Helper()
+	c, err := db.Classroom(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestClassroomRoles(t *testing.T) {
+	db := testutil.NewDB(t)
+	createMembers(t, db, "teacher", "assistant", "student")
+
+	if err := db.AuthorizeClassroom("student", database.CapManageFlags); err != nil {
+		t.Errorf("先生が決まるまでは制限しない: %v", err)
+	}
+
+	teacher := classroom(t, db, "teacher")
+	if err := teacher.BecomeTeacher(); err != nil {
+		t.Fatal(err)
+	}
+	if err := classroom(t, db, "student").BecomeTeacher(); !errors.Is(err, database.ErrPermissionDenied) {
+		t.Errorf("先生がいる教室で先生になれました: %v", err)
+	}
+	if err := teacher.SetRole("assistant", database.RoleAssistant); err != nil {
+		t.Fatal(err)
+	}
+
+	assistant := classroom(t, db, "assistant")
+	if err := assistant.SetRole("student", database.RoleTeacher); !errors.Is(err, database.ErrPermissionDenied) {
+		t.Errorf("補助の先生が役割を変えられました: %v", err)
+	}
+	if err := db.AuthorizeClassroom("assistant", database.CapManageFlags); !errors.Is(err, database.ErrPermissionDenied) {
+		t.Errorf("補助の先生が機能フラグを切り替えられます: %v", err)
+	}
+	if err := db.AuthorizeClassroom("teacher", database.CapManageFlags); err != nil {
+		t.Errorf("先生が機能フラグを切り替えられません: %v", err)
+	}
+	if err := teacher.SetRole("teacher", database.RoleStudent); err == nil {
+		t.Error("最後の先生が生徒になりました")
+	}
+
+	if members, err := assistant.Members(); err != nil || len(members) != 3 || members[0].Role != database.RoleTeacher {
+		t.Errorf("補助の先生から見たメンバー = %+v, %v", members, err)
+	}
+	if members, err := classroom(t, db, "student").Members(); err != nil || len(members) != 1 || members[0].Role != database.RoleStudent {
+		t.Errorf("生徒から見たメンバー = %+v, %v", members, err)
+	}
+}
+
+func TestClassroomAssignments(t *testing.T) {
+	db := testutil.NewDB(t)
+	createMembers(t, db, "teacher", "student1", "student2")
+	teacher := classroom(t, db, "teacher")
+	if err := teacher.BecomeTeacher(); err != nil {
+		t.Fatal(err)
+	}
+
+	assignment := &database.Assignment{
+		ID: "a1", Subject: "数学", Topic: "一次関数", ProblemCount: 10, DueDate: "2026-10-16",
+		StudentIDs: []string{"student1", "student2"}, CreatedAt: time.Now(),
+	}
+	student1 := classroom(t, db, "student1")
+	if err := student1.CreateAssignment(assignment); !errors.Is(err, database.ErrPermissionDenied) {
+		t.Fatalf("生徒が課題を作れました: %v", err)
+	}
+	if err := teacher.CreateAssignment(assignment); err != nil {
+		t.Fatal(err)
+	}
+	if err := teacher.CreateAssignment(&database.Assignment{
+		ID: "a2", Subject: "英語", ProblemCount: 5, DueDate: "2026-10-17", StudentIDs: []string{"student2"}, CreatedAt: time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := teacher.Assignments("2026-10-16", "2026-10-17")
+	if err != nil || len(all) != 2 || len(all[0].StudentIDs) != 2 || all[0].CreatedBy != "teacher" {
+		t.Errorf("先生から見た課題 = %+v, %v", all, err)
+	}
+	own, err := student1.Assignments("2026-10-16", "2026-10-17")
+	if err != nil || len(own) != 1 || own[0].ID != "a1" || len(own[0].StudentIDs) != 1 {
+		t.Errorf("生徒から見た課題 = %+v, %v", own, err)
+	}
+
+	from, to := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	if _, err := student1.StudySessions("student2", from, to); !errors.Is(err, database.ErrPermissionDenied) {
+		t.Errorf("生徒がほかの生徒の記録を見られました: %v", err)
+	}
+	if _, err := teacher.StudySessions("student2", from, to); err != nil {
+		t.Errorf("先生が生徒の記録を見られません: %v", err)
+	}
+}
+
+func TestClassroomParticipation(t *testing.T) {
+	db := testutil.NewDB(t)
+	createMembers(t, db, "teacher", "student1", "student2")
+	teacher := classroom(t, db, "teacher")
+	if err := teacher.BecomeTeacher(); err != nil {
+		t.Fatal(err)
+	}
+	if err := teacher.CreateAssignment(&database.Assignment{
+		ID: "a1", Subject: "数学", Topic: "一次関数", ProblemCount: 2, DueDate: "2026-10-16",
+		StudentIDs: []string{"student1", "student2"}, CreatedAt: time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// student1は課題の単元を2問、student2はほかの単元を1問と課題の単元を1問
+	answered := time.Date(2026, 10, 16, 17, 0, 0, 0, time.Local)
+	for i, answer := range []struct{ userID, topic string }{
+		{"student1", "一次関数"}, {"student1", "一次関数"}, {"student2", "連立方程式"}, {"student2", "一次関数"},
+	} {
+		sessionID := fmt.Sprintf("s%d", i)
+		if err := db.CreateStudySession(&database.StudySession{
+			ID: sessionID, UserID: answer.userID, Subject: "数学", StartTime: answered, CreatedAt: answered,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.CreateProblemResult(&database.ProblemResult{
+			ID: fmt.Sprintf("r%d", i), SessionID: sessionID, ProblemType: answer.topic, Difficulty: 2, CreatedAt: answered,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	progress, err := teacher.Participation("2026-10-16", "2026-10-16")
+	if err != nil || len(progress) != 1 {
+		t.Fatalf("取り組み状況 = %+v, %v", progress, err)
+	}
+	if p := progress[0]; !p.Completed("student1") || p.Completed("student2") || p.Solved["student2"] != 1 {
+		t.Errorf("解いた問題数 = %v", p.Solved)
+	}
+
+	own, err := classroom(t, db, "student2").Participation("2026-10-16", "2026-10-16")
+	if err != nil || len(own) != 1 || len(own[0].Solved) != 1 {
+		t.Errorf("生徒から見た取り組み状況 = %+v, %v", own, err)
+	}
+}
@@ -1,10 +1,15 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -13,18 +18,39 @@ import (
 // DB データベース接続
 type DB struct {
 	*sql.DB
+	dialect dialect   // SQLite・PostgreSQLの違い
+	stmts   stmtCache // 解答ごとの書き込みに使う準備済みの文
 }
 
-// Initialize データベースを初期化
+// Initialize データベースを初期化（SQLite。dbPathのファイルに保存する）
 func Initialize(dbPath string) (*DB, error) {
+	return Open(DriverSQLite, dbPath)
+}
+
+// Open データベースに接続し、スキーマを作成・更新する
+// （driverはDriverSQLite・DriverPostgres。dsnはSQLiteならファイルのパス、PostgreSQLなら接続URL）
+func Open(driver, dsn string) (*DB, error) {
+	d, err := dialectFor(driver)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(sql.Drivers(), d.driverName) {
+		return nil, fmt.Errorf("%sのドライバーが組み込まれていません（-tags %s でビルドしてください）", d.name, d.name)
+	}
+
 	// データベースディレクトリを作成
-	dbDir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dbDir, 0755); err != nil {
-		return nil, fmt.Errorf("データベースディレクトリ作成エラー: %w", err)
+	if d.name == DriverSQLite {
+		dbDir := filepath.Dir(dsn)
+		if err := os.MkdirAll(dbDir, 0755); err != nil {
+			return nil, fmt.Errorf("データベースディレクトリ作成エラー: %w", err)
+		}
 	}
 
 	// データベース接続
-	db, err := sql.Open("sqlite3", dbPath)
+	if d.name == DriverSQLite {
+		dsn = sqliteDSN(dsn)
+	}
+	db, err := sql.Open(d.driverName, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("データベース接続エラー: %w", err)
 	}
@@ -34,7 +60,7 @@ func Initialize(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("データベース接続テストエラー: %w", err)
 	}
 
-	wrapper := &DB{db}
+	wrapper := &DB{DB: db, dialect: d}
 
 	// スキーマ作成
 	if err := wrapper.createSchema(); err != nil {
@@ -53,18 +79,192 @@ func (db *DB) createSchema() error {
 		createLearningProgressTable,
 		createVirtualPetsTable,
 		createErrorPatternsTable,
+		createTimetableEntriesTable,
+		createScheduleExceptionsTable,
+		createSessionFocusTable,
+		createXPEventsTable,
+		createAchievementsTable,
+		createTopicMasteryTable,
+		createReviewCardsTable,
+		createFlashcardDecksTable,
+		createFlashcardsTable,
+		createStudyTipsTable,
+		createProblemBankTable,
+		createCloudTokenUsageTable,
+		createCloudDailyUsageTable,
+		createTextEmbeddingsTable,
+		createStudyDiaryTable,
+		createAIResponseCacheTable,
+		createAIMetricsTable,
+		createProblemSketchesTable,
+		createBoundaryHitsTable,
+		createTutorTranscriptsTable,
+		createClassroomMembersTable,
+		createClassroomAssignmentsTable,
+		createAssignmentStudentsTable,
+		createOfflineRotationTable,
 		createIndices,
 	}
 
 	for _, schema := range schemas {
-		if _, err := db.Exec(schema); err != nil {
+		if _, err := db.Exec(db.dialect.schema(schema)); err != nil {
 			return fmt.Errorf("スキーマ実行エラー: %w", err)
 		}
 	}
 
+	return db.migrateSchema()
+}
+
+// migrateSchema 既存のデータベースに後から追加した列を反映
+func (db *DB) migrateSchema() error {
+	columns := []struct {
+		table      string
+		column     string
+		definition string
+	}{
+		{"study_sessions", "session_type", "TEXT NOT NULL DEFAULT 'app'"},
+		{"study_sessions", "note", "TEXT NOT NULL DEFAULT ''"},
+		{"study_sessions", "max_combo", "INTEGER NOT NULL DEFAULT 0"},
+		{"study_sessions", "paused_seconds", "INTEGER NOT NULL DEFAULT 0"},
+		{"problem_results", "problem_title", "TEXT NOT NULL DEFAULT ''"},
+		{"problem_results", "problem_options", "TEXT NOT NULL DEFAULT ''"},
+		{"problem_results", "explanation", "TEXT NOT NULL DEFAULT ''"},
+		{"problem_results", "similarity_hash", "TEXT NOT NULL DEFAULT ''"},
+		{"problem_results", "quality_score", "INTEGER NOT NULL DEFAULT 0"},
+		{"problem_results", "model", "TEXT NOT NULL DEFAULT ''"},
+		{"problem_results", "answer_position", "INTEGER NOT NULL DEFAULT -1"},
+		{"problem_results", "option_count", "INTEGER NOT NULL DEFAULT 0"},
+		{"problem_results", "rationale", "TEXT NOT NULL DEFAULT ''"},
+	}
+
+	for _, c := range columns {
+		exists, err := db.columnExists(c.table, c.column)
+		if err != nil {
+			return fmt.Errorf("スキーマ確認エラー: %w", err)
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)); err != nil {
+			return fmt.Errorf("列追加エラー（%s.%s）: %w", c.table, c.column, err)
+		}
+	}
+
+	if err := db.migrateSessionTypeCheck(); err != nil {
+		return fmt.Errorf("セッション種別の制約更新エラー: %w", err)
+	}
+
+	return db.backfillTopicMastery()
+}
+
+// migrateSessionTypeCheck セッション種別の制約に、あとから追加した種別（模擬テスト）を反映
+func (db *DB) migrateSessionTypeCheck() error {
+	if db.dialect.name == DriverPostgres {
+		var definition string
+		err := db.QueryRow(`
+			SELECT pg_get_constraintdef(oid) FROM pg_constraint
+			WHERE conname = 'valid_session_type' AND conrelid = 'study_sessions'::regclass
+		`).Scan(&definition)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if strings.Contains(definition, "'exam'") {
+			return nil
+		}
+		if _, err := db.Exec(`ALTER TABLE study_sessions DROP CONSTRAINT IF EXISTS valid_session_type`); err != nil {
+			return err
+		}
+		_, err = db.Exec(`ALTER TABLE study_sessions ADD CONSTRAINT valid_session_type CHECK (session_type IN ('app', 'manual', 'exam'))`)
+		return err
+	}
+
+	var ddl string
+	if err := db.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'study_sessions'`).Scan(&ddl); err != nil {
+		return err
+	}
+	if strings.Contains(ddl, "'exam'") {
+		return nil
+	}
+	return db.rebuildStudySessionsTable()
+}
+
+// rebuildStudySessionsTable SQLiteの学習セッションテーブルを今のスキーマで作り直し、記録を移す
+// （SQLiteは制約を変更できないため。外部キーの確認はトランザクションの中では切り替えられないので、
+// 1つの接続で確認を止めてから作り直す）
+func (db *DB) rebuildStudySessionsTable() error {
+	columns, err := db.dialect.tableColumns(db, "study_sessions")
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer func() { _, _ = conn.ExecContext(ctx, "PRAGMA foreign_keys = ON") }()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	list := strings.Join(columns, ", ")
+	statements := []string{
+		strings.Replace(createStudySessionsTable, "IF NOT EXISTS study_sessions", "study_sessions_new", 1),
+		fmt.Sprintf("INSERT INTO study_sessions_new (%s) SELECT %s FROM study_sessions", list, list),
+		"DROP TABLE study_sessions",
+		"ALTER TABLE study_sessions_new RENAME TO study_sessions",
+		createIndices,
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// backfillTopicMastery 単元別習熟度が空なら、これまでの解答結果から集計
+func (db *DB) backfillTopicMastery() error {
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM topic_mastery`).Scan(&count); err != nil {
+		return fmt.Errorf("単元別習熟度確認エラー: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO topic_mastery (user_id, subject, topic, attempts, correct_answers, last_studied)
+		SELECT ss.user_id, ss.subject, pr.problem_type, COUNT(*),
+			SUM(CASE WHEN pr.is_correct THEN 1 ELSE 0 END), MAX(pr.created_at)
+		FROM problem_results pr
+		JOIN study_sessions ss ON pr.session_id = ss.id
+		WHERE pr.problem_type != ''
+		GROUP BY ss.user_id, ss.subject, pr.problem_type
+	`
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("単元別習熟度集計エラー: %w", err)
+	}
 	return nil
 }
 
+// columnExists テーブルに列が存在するかチェック
+func (db *DB) columnExists(table, column string) (bool, error) {
+	columns, err := db.dialect.tableColumns(db, table)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(columns, column), nil
+}
+
 // ユーザーテーブル作成SQL
 const createUsersTable = `
 CREATE TABLE IF NOT EXISTS users (
@@ -87,9 +287,14 @@ CREATE TABLE IF NOT EXISTS study_sessions (
     total_problems INTEGER DEFAULT 0,
     correct_answers INTEGER DEFAULT 0,
     average_emotion TEXT DEFAULT 'neutral',
+    session_type TEXT NOT NULL DEFAULT 'app',
+    note TEXT NOT NULL DEFAULT '',
+    max_combo INTEGER NOT NULL DEFAULT 0,
+    paused_seconds INTEGER NOT NULL DEFAULT 0,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (user_id) REFERENCES users(id),
-    CONSTRAINT valid_subject CHECK (subject IN ('数学', '英語', '国語', '理科', '社会'))
+    CONSTRAINT valid_subject CHECK (subject IN ('数学', '英語', '国語', '理科', '社会')),
+    CONSTRAINT valid_session_type CHECK (session_type IN ('app', 'manual', 'exam'))
 );`
 
 // 問題解答記録テーブル作成SQL
@@ -168,15 +373,319 @@ CREATE TABLE IF NOT EXISTS error_patterns (
     CONSTRAINT valid_subject CHECK (subject IN ('数学', '英語', '国語', '理科', '社会'))
 );`
 
+// 時間割テーブル作成SQL（学校の授業・部活動など学習できない時間帯）
+const createTimetableEntriesTable = `
+CREATE TABLE IF NOT EXISTS timetable_entries (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    weekday INTEGER NOT NULL,
+    start_time TEXT NOT NULL,
+    end_time TEXT NOT NULL,
+    label TEXT NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'school',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT valid_weekday CHECK (weekday BETWEEN 0 AND 6),
+    CONSTRAINT valid_kind CHECK (kind IN ('school', 'club', 'juku', 'other'))
+);`
+
+// 予定の例外日テーブル作成SQL（休校日・行事など時間割を適用しない日）
+const createScheduleExceptionsTable = `
+CREATE TABLE IF NOT EXISTS schedule_exceptions (
+    user_id TEXT NOT NULL,
+    date TEXT NOT NULL, -- "2006-01-02"
+    note TEXT,
+    PRIMARY KEY (user_id, date),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
+// セッション集中度テーブル作成SQL
+const createSessionFocusTable = `
+CREATE TABLE IF NOT EXISTS session_focus (
+    session_id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    pause_count INTEGER DEFAULT 0,
+    breaks_suggested INTEGER DEFAULT 0,
+    breaks_taken INTEGER DEFAULT 0,
+    answer_count INTEGER DEFAULT 0,
+    answer_time_cv REAL DEFAULT 0,
+    focus_score REAL NOT NULL,
+    started_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES study_sessions(id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT valid_focus_score CHECK (focus_score >= 0 AND focus_score <= 100)
+);`
+
+// 経験値獲得履歴テーブル作成SQL
+const createXPEventsTable = `
+CREATE TABLE IF NOT EXISTS xp_events (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    source TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    reason TEXT DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
+// 実績テーブル作成SQL
+const createAchievementsTable = `
+CREATE TABLE IF NOT EXISTS achievements (
+    user_id TEXT NOT NULL,
+    achievement_id TEXT NOT NULL,
+    earned_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, achievement_id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
+// 単元別習熟度テーブル作成SQL
+const createTopicMasteryTable = `
+CREATE TABLE IF NOT EXISTS topic_mastery (
+    user_id TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    correct_answers INTEGER NOT NULL DEFAULT 0,
+    last_studied DATETIME NOT NULL,
+    PRIMARY KEY (user_id, subject, topic),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
+// 復習カードテーブル作成SQL
+const createReviewCardsTable = `
+CREATE TABLE IF NOT EXISTS review_cards (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    takeaway TEXT NOT NULL,
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    reviewed_at DATETIME,
+    remembered BOOLEAN,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (session_id) REFERENCES study_sessions(id)
+);`
+
+// 単語カードのデッキテーブル作成SQL
+const createFlashcardDecksTable = `
+CREATE TABLE IF NOT EXISTS flashcard_decks (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE (user_id, kind),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
+// 単語カードテーブル作成SQL
+const createFlashcardsTable = `
+CREATE TABLE IF NOT EXISTS flashcards (
+    id TEXT PRIMARY KEY,
+    deck_id TEXT NOT NULL,
+    front TEXT NOT NULL,
+    back TEXT NOT NULL,
+    hint TEXT NOT NULL DEFAULT '',
+    ease_factor REAL NOT NULL DEFAULT 2.5,
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    repetitions INTEGER NOT NULL DEFAULT 0,
+    due_at DATETIME NOT NULL,
+    last_reviewed DATETIME,
+    created_at DATETIME NOT NULL,
+    UNIQUE (deck_id, front),
+    FOREIGN KEY (deck_id) REFERENCES flashcard_decks(id)
+);`
+
+// 学習のコツテーブル作成SQL（フィードバックのTIPを保存）
+const createStudyTipsTable = `
+CREATE TABLE IF NOT EXISTS study_tips (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    tip TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE (user_id, tip),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
+// 問題バンクテーブル作成SQL（アプリの外で見つけた問題などを保存）
+const createProblemBankTable = `
+CREATE TABLE IF NOT EXISTS problem_bank (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    title TEXT NOT NULL DEFAULT '',
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL DEFAULT '',
+    explanation TEXT NOT NULL DEFAULT '',
+    topic TEXT NOT NULL DEFAULT '',
+    tag TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
+// クラウドAIの月ごとの使用トークン数テーブル作成SQL（家庭全体で共有）
+const createCloudTokenUsageTable = `
+CREATE TABLE IF NOT EXISTS cloud_token_usage (
+    month TEXT PRIMARY KEY, -- "2006-01"形式
+    tokens INTEGER NOT NULL DEFAULT 0
+);`
+
+// クラウドAIのプロフィールごと・日ごとの使用量テーブル作成SQL
+const createCloudDailyUsageTable = `
+CREATE TABLE IF NOT EXISTS cloud_daily_usage (
+    user_id TEXT NOT NULL,
+    date TEXT NOT NULL, -- "2006-01-02"形式
+    requests INTEGER NOT NULL DEFAULT 0,
+    tokens INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, date),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
+// 文章の埋め込みベクトルテーブル作成SQL（類似した問題を探すため、計算結果を使い回す）
+const createTextEmbeddingsTable = `
+CREATE TABLE IF NOT EXISTS text_embeddings (
+    model TEXT NOT NULL,
+    text_hash TEXT NOT NULL, -- 文章のSHA-256
+    embedding TEXT NOT NULL, -- JSON配列
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (model, text_hash)
+);`
+
+// 学習日記テーブル作成SQL（1日1件。AIが学習記録から作った下書きを生徒が書き直して保存）
+const createStudyDiaryTable = `
+CREATE TABLE IF NOT EXISTS study_diary (
+    user_id TEXT NOT NULL,
+    date TEXT NOT NULL, -- "2006-01-02"形式
+    content TEXT NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, date),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
+// AIの応答の保存テーブル作成SQL（同じプロンプトの応答を使い回し、オフラインでも前の応答を使う）
+const createAIResponseCacheTable = `
+CREATE TABLE IF NOT EXISTS ai_response_cache (
+    cache_key TEXT PRIMARY KEY, -- 種類・モデル・プロンプトのSHA-256
+    kind TEXT NOT NULL, -- study_tip | feedback | model_list
+    response TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL
+);`
+
+// AIの生成の計測テーブル作成SQL（モデルごとの生成の速さを比べる）
+const createAIMetricsTable = `
+CREATE TABLE IF NOT EXISTS ai_metrics (
+    model TEXT NOT NULL,
+    prompt_tokens INTEGER NOT NULL DEFAULT 0, -- Ollamaのprompt_eval_count
+    output_tokens INTEGER NOT NULL DEFAULT 0, -- Ollamaのeval_count
+    total_ms INTEGER NOT NULL DEFAULT 0, -- モデルの読み込みを含む生成全体の時間（total_duration）
+    eval_ms INTEGER NOT NULL DEFAULT 0, -- 応答の生成にかかった時間（eval_duration）
+    created_at DATETIME NOT NULL
+);`
+
+// 計算メモテーブル作成SQL（解答といっしょに保存した手書きの計算メモ。1問1枚）
+const createProblemSketchesTable = `
+CREATE TABLE IF NOT EXISTS problem_sketches (
+    result_id TEXT PRIMARY KEY,
+    image TEXT NOT NULL, -- PNG画像（base64）
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (result_id) REFERENCES problem_results(id)
+);`
+
+// クイック質問の範囲外の質問の記録テーブル作成SQL（保護者ダッシュボードで確認する）
+const createBoundaryHitsTable = `
+CREATE TABLE IF NOT EXISTS boundary_hits (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    stage TEXT NOT NULL, -- question | answer
+    category TEXT NOT NULL, -- personal | unsafe | crisis | subject
+    detail TEXT NOT NULL DEFAULT '', -- 見つけた言葉や教科
+    question TEXT NOT NULL, -- 質問の先頭（長い質問は省略）
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
+// AIとのやりとりの記録テーブル作成SQL（生徒があとで見直し、保護者が確認する。保存期間を過ぎたら削除）
+const createTutorTranscriptsTable = `
+CREATE TABLE IF NOT EXISTS tutor_transcripts (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    session_id TEXT NOT NULL DEFAULT '', -- 学習セッション中の質問ならそのセッション
+    source TEXT NOT NULL, -- capture | ask
+    subject TEXT NOT NULL DEFAULT '',
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL, -- 表示した解説（Markdown）
+    refused INTEGER NOT NULL DEFAULT 0, -- 範囲外の質問として答えなかった
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
+// 教室モードの役割テーブル作成SQL（登録がないプロフィールは生徒）
+const createClassroomMembersTable = `
+CREATE TABLE IF NOT EXISTS classroom_members (
+    user_id TEXT PRIMARY KEY,
+    role TEXT NOT NULL, -- teacher | assistant | student
+    updated_at DATETIME NOT NULL
+);`
+
+// 教室モードの課題テーブル作成SQL
+const createClassroomAssignmentsTable = `
+CREATE TABLE IF NOT EXISTS classroom_assignments (
+    id TEXT PRIMARY KEY,
+    created_by TEXT NOT NULL, -- 課題を作った先生のプロフィール
+    subject TEXT NOT NULL,
+    topic TEXT NOT NULL DEFAULT '', -- 空なら科目全体
+    problem_count INTEGER NOT NULL,
+    due_date TEXT NOT NULL, -- 取り組む日（"2006-01-02"）
+    note TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL
+);`
+
+// 課題を配った生徒のテーブル作成SQL
+const createAssignmentStudentsTable = `
+CREATE TABLE IF NOT EXISTS assignment_students (
+    assignment_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    PRIMARY KEY (assignment_id, user_id),
+    FOREIGN KEY (assignment_id) REFERENCES classroom_assignments(id)
+);`
+
+// 内蔵の問題を出題した位置のテーブル作成SQL（再起動しても同じ問題から出題し直さないため）
+const createOfflineRotationTable = `
+CREATE TABLE IF NOT EXISTS offline_rotation (
+    user_id TEXT NOT NULL,
+    rotation_key TEXT NOT NULL, -- 科目・単元・学年・難易度ごとの出題順の名前
+    position INTEGER NOT NULL DEFAULT 0, -- 次に出題する位置
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, rotation_key),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);`
+
 // インデックス作成SQL
 const createIndices = `
 CREATE INDEX IF NOT EXISTS idx_study_sessions_user_id ON study_sessions(user_id);
 CREATE INDEX IF NOT EXISTS idx_study_sessions_subject ON study_sessions(subject);
 CREATE INDEX IF NOT EXISTS idx_study_sessions_start_time ON study_sessions(start_time);
+CREATE INDEX IF NOT EXISTS idx_study_sessions_user_start ON study_sessions(user_id, start_time);
 CREATE INDEX IF NOT EXISTS idx_problem_results_session_id ON problem_results(session_id);
 CREATE INDEX IF NOT EXISTS idx_problem_results_is_correct ON problem_results(is_correct);
+CREATE INDEX IF NOT EXISTS idx_problem_results_session_created ON problem_results(session_id, created_at);
 CREATE INDEX IF NOT EXISTS idx_error_patterns_user_subject ON error_patterns(user_id, subject);
 CREATE INDEX IF NOT EXISTS idx_learning_progress_last_study ON learning_progress(last_study_date);
+CREATE INDEX IF NOT EXISTS idx_timetable_entries_user_weekday ON timetable_entries(user_id, weekday);
+CREATE INDEX IF NOT EXISTS idx_session_focus_user_started ON session_focus(user_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_xp_events_user_id ON xp_events(user_id);
+CREATE INDEX IF NOT EXISTS idx_xp_events_user_amount ON xp_events(user_id, amount);
+CREATE INDEX IF NOT EXISTS idx_review_cards_user_created ON review_cards(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_flashcards_deck_due ON flashcards(deck_id, due_at);
+CREATE INDEX IF NOT EXISTS idx_study_tips_user_created ON study_tips(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_problem_bank_user_tag ON problem_bank(user_id, tag, created_at);
+CREATE INDEX IF NOT EXISTS idx_ai_metrics_created ON ai_metrics(created_at);
+CREATE INDEX IF NOT EXISTS idx_classroom_assignments_due ON classroom_assignments(due_date);
+CREATE INDEX IF NOT EXISTS idx_assignment_students_user ON assignment_students(user_id);
 `
 
 // User ユーザー構造体
@@ -198,9 +707,38 @@ type StudySession struct {
 	TotalProblems  int       `json:"total_problems"`
 	CorrectAnswers int       `json:"correct_answers"`
 	AverageEmotion string    `json:"average_emotion"`
+	SessionType    string    `json:"session_type"` // "app" | "manual" | "exam"
+	Note           string    `json:"note"`         // 手動記録のメモ（塾・ドリルなど）・模擬テストの条件
+	MaxCombo       int       `json:"max_combo"`    // セッション中の最大連続正解数
+	PausedSeconds  int       `json:"paused_seconds"` // 一時停止していた時間（秒。学習時間に含めない）
 	CreatedAt      time.Time `json:"created_at"`
 }
 
+// セッション種別
+const (
+	SessionTypeApp    = "app"    // アプリでの学習
+	SessionTypeManual = "manual" // アプリ外の学習（塾・紙のドリルなど）の手動記録
+	SessionTypeExam   = "exam"   // 時間制限つきの模擬テスト
+)
+
+// DurationSeconds 学習時間（秒。一時停止していた時間を除く）。終了していないセッションは0
+func (s *StudySession) DurationSeconds() int {
+	if s.EndTime == nil {
+		return 0
+	}
+	return max(int(s.EndTime.Sub(s.StartTime).Seconds())-s.PausedSeconds, 0)
+}
+
+// IsManual アプリ外の学習の手動記録かどうか
+func (s *StudySession) IsManual() bool {
+	return s.SessionType == SessionTypeManual
+}
+
+// IsExam 模擬テストかどうか
+func (s *StudySession) IsExam() bool {
+	return s.SessionType == SessionTypeExam
+}
+
 // ProblemResult 問題解答結果構造体
 type ProblemResult struct {
 	ID              string    `json:"id"`
@@ -215,6 +753,39 @@ type ProblemResult struct {
 	UserAnswer      string    `json:"user_answer"`
 	CorrectAnswer   string    `json:"correct_answer"`
 	CreatedAt       time.Time `json:"created_at"`
+
+	// 間違いノートで同じ問題を出し直すための問題の内容
+	ProblemTitle   string `json:"problem_title"`
+	ProblemOptions string `json:"problem_options"` // 選択肢（JSON配列）
+	Explanation    string `json:"explanation"`
+
+	// ほぼ同じ問題を避けるための問題文の類似ハッシュと、問題の品質スコア（0〜100）
+	SimilarityHash string `json:"similarity_hash"`
+	QualityScore   int    `json:"quality_score"`
+
+	// 問題を作ったAIのモデル（用意してある問題なら空）
+	Model string `json:"model"`
+
+	// 選んだ選択肢の表示位置（0から。未解答は-1）と選択肢の数（位置を記録していなければ0）
+	AnswerPosition int `json:"answer_position"`
+	OptionCount    int `json:"option_count"`
+
+	// 生徒が答えた、その答えを選んだ理由（聞かなかったときは空）
+	Rationale string `json:"rationale"`
+}
+
+// Mistake 間違いノートの1件（解答結果とセッションの科目）
+type Mistake struct {
+	ProblemResult
+	Subject   string `json:"subject"`
+	HasSketch bool   `json:"has_sketch"` // 計算メモを保存している
+}
+
+// MistakeFilter 間違いノートの絞り込み条件（空の項目は絞り込まない）
+type MistakeFilter struct {
+	Subject     string
+	ProblemType string
+	Since       time.Time
 }
 
 // LearningProgress 学習進捗構造体
@@ -260,6 +831,169 @@ type ErrorPattern struct {
 	ResolutionDate *time.Time `json:"resolution_date"`
 }
 
+// TimetableEntry 時間割構造体
+type TimetableEntry struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Weekday   int       `json:"weekday"`    // 0:日曜 〜 6:土曜
+	StartTime string    `json:"start_time"` // "08:30"
+	EndTime   string    `json:"end_time"`   // "15:30"
+	Label     string    `json:"label"`
+	Kind      string    `json:"kind"` // "school" | "club" | "juku" | "other"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ScheduleException 予定の例外日構造体
+type ScheduleException struct {
+	UserID string `json:"user_id"`
+	Date   string `json:"date"` // "2006-01-02"
+	Note   string `json:"note"`
+}
+
+// SessionFocus セッションの集中度（ポモドーロ統計）
+type SessionFocus struct {
+	SessionID       string    `json:"session_id"`
+	UserID          string    `json:"user_id"`
+	PauseCount      int       `json:"pause_count"`
+	BreaksSuggested int       `json:"breaks_suggested"`
+	BreaksTaken     int       `json:"breaks_taken"`
+	AnswerCount     int       `json:"answer_count"`
+	AnswerTimeCV    float64   `json:"answer_time_cv"` // 解答時間の変動係数
+	FocusScore      float64   `json:"focus_score"`    // 0-100
+	StartedAt       time.Time `json:"started_at"`
+}
+
+// XPEvent 経験値の獲得記録
+type XPEvent struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Source    string    `json:"source"` // "answer" | "manual" | "backfill" など
+	Amount    int       `json:"amount"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EarnedAchievement 獲得した実績
+type EarnedAchievement struct {
+	UserID        string    `json:"user_id"`
+	AchievementID string    `json:"achievement_id"`
+	EarnedAt      time.Time `json:"earned_at"`
+}
+
+// TopicMastery 単元別の解答統計（ProblemType単位）
+type TopicMastery struct {
+	UserID         string    `json:"user_id"`
+	Subject        string    `json:"subject"`
+	Topic          string    `json:"topic"`
+	Attempts       int       `json:"attempts"`
+	CorrectAnswers int       `json:"correct_answers"`
+	LastStudied    time.Time `json:"last_studied"`
+}
+
+// ReviewCard セッションの解説から作った復習カード（要点と一問一答）
+type ReviewCard struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	SessionID  string     `json:"session_id"`
+	Subject    string     `json:"subject"`
+	Takeaway   string     `json:"takeaway"` // 1行の要点
+	Question   string     `json:"question"`
+	Answer     string     `json:"answer"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	Remembered bool       `json:"remembered"`
+}
+
+// FlashcardDeck 単語カードのデッキ
+type FlashcardDeck struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"` // "vocab" | "kanji"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Flashcard 単語カード（SM-2の復習スケジュールを含む）
+type Flashcard struct {
+	ID           string     `json:"id"`
+	DeckID       string     `json:"deck_id"`
+	Front        string     `json:"front"`
+	Back         string     `json:"back"`
+	Hint         string     `json:"hint"`
+	EaseFactor   float64    `json:"ease_factor"`
+	IntervalDays int        `json:"interval_days"`
+	Repetitions  int        `json:"repetitions"`
+	DueAt        time.Time  `json:"due_at"`
+	LastReviewed *time.Time `json:"last_reviewed"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// StudyTip フィードバックで受け取った学習のコツ
+type StudyTip struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Subject   string    `json:"subject"`
+	Tip       string    `json:"tip"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 問題バンクのタグ
+const (
+	BankTagCaptured = "captured" // クイック質問で保存した問題
+)
+
+// BankProblem 問題バンクの問題
+type BankProblem struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	Subject     string    `json:"subject"`
+	Title       string    `json:"title"`
+	Question    string    `json:"question"`
+	Answer      string    `json:"answer"`
+	Explanation string    `json:"explanation"`
+	Topic       string    `json:"topic"`
+	Tag         string    `json:"tag"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// BoundaryHit クイック質問で範囲外として答えなかった質問の記録
+type BoundaryHit struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Stage     string    `json:"stage"`    // "question" | "answer"
+	Category  string    `json:"category"` // "personal" | "unsafe" | "crisis" | "subject"
+	Detail    string    `json:"detail"`
+	Question  string    `json:"question"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AIとのやりとりの記録の種類
+const (
+	TranscriptSourceCapture = "capture" // クイック質問
+	TranscriptSourceAsk     = "ask"     // 質問する（写真で取り込んだ問題など）
+)
+
+// TutorTranscript AIとのやりとりの記録（質問と表示した解説）
+type TutorTranscript struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	SessionID string    `json:"session_id"` // 学習セッション中でなければ空
+	Source    string    `json:"source"`     // TranscriptSource〜
+	Subject   string    `json:"subject"`
+	Question  string    `json:"question"`
+	Answer    string    `json:"answer"`
+	Refused   bool      `json:"refused"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DiaryEntry 学習日記の1日分
+type DiaryEntry struct {
+	UserID    string    `json:"user_id"`
+	Date      string    `json:"date"` // "2006-01-02"形式
+	Content   string    `json:"content"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 // CreateUser ユーザー作成
 func (db *DB) CreateUser(user *User) error {
 	query := `
@@ -287,6 +1021,25 @@ func (db *DB) GetUser(userID string) (*User, error) {
 	return &user, nil
 }
 
+// GetUsers すべてのユーザー（作成した順）
+func (db *DB) GetUsers() ([]User, error) {
+	rows, err := db.Query(`SELECT id, name, grade, created_at, last_login FROM users ORDER BY created_at ASC, id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.Name, &user.Grade, &user.CreatedAt, &user.LastLogin); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
 // UpdateUserLastLogin ユーザーの最終ログイン時刻を更新
 func (db *DB) UpdateUserLastLogin(userID string) error {
 	query := `UPDATE users SET last_login = ? WHERE id = ?`
@@ -294,14 +1047,25 @@ func (db *DB) UpdateUserLastLogin(userID string) error {
 	return err
 }
 
+// UpdateUserGrade ユーザーの学年を更新
+func (db *DB) UpdateUserGrade(userID string, grade int) error {
+	query := `UPDATE users SET grade = ? WHERE id = ?`
+	_, err := db.Exec(query, grade, userID)
+	return err
+}
+
 // CreateStudySession 学習セッション作成
 func (db *DB) CreateStudySession(session *StudySession) error {
 	query := `
-		INSERT INTO study_sessions (id, user_id, subject, start_time, end_time, total_problems, correct_answers, average_emotion, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO study_sessions (id, user_id, subject, start_time, end_time, total_problems, correct_answers, average_emotion, session_type, note, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
+	sessionType := session.SessionType
+	if sessionType == "" {
+		sessionType = SessionTypeApp
+	}
 	_, err := db.Exec(query, session.ID, session.UserID, session.Subject, session.StartTime, 
-		session.EndTime, session.TotalProblems, session.CorrectAnswers, session.AverageEmotion, session.CreatedAt)
+		session.EndTime, session.TotalProblems, session.CorrectAnswers, session.AverageEmotion, sessionType, session.Note, session.CreatedAt)
 	return err
 }
 
@@ -309,24 +1073,40 @@ func (db *DB) CreateStudySession(session *StudySession) error {
 func (db *DB) UpdateStudySession(session *StudySession) error {
 	query := `
 		UPDATE study_sessions 
-		SET end_time = ?, total_problems = ?, correct_answers = ?, average_emotion = ?
+		SET end_time = ?, total_problems = ?, correct_answers = ?, average_emotion = ?, max_combo = ?,
+			paused_seconds = ?
 		WHERE id = ?
 	`
-	_, err := db.Exec(query, session.EndTime, session.TotalProblems, session.CorrectAnswers, 
-		session.AverageEmotion, session.ID)
+	_, err := db.exec(query, session.EndTime, session.TotalProblems, session.CorrectAnswers, 
+		session.AverageEmotion, session.MaxCombo, session.PausedSeconds, session.ID)
 	return err
 }
 
 // CreateProblemResult 問題解答結果作成
 func (db *DB) CreateProblemResult(result *ProblemResult) error {
+	return insertProblemResult(db.exec, result)
+}
+
+// insertProblemResult 問題解答結果を追加（準備済みの文で実行する）
+func insertProblemResult(exec execFunc, result *ProblemResult) error {
 	query := `
 		INSERT INTO problem_results (id, session_id, problem_type, difficulty, is_correct, time_taken, 
-			emotion_at_answer, error_category, problem_content, user_answer, correct_answer, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			emotion_at_answer, error_category, problem_content, user_answer, correct_answer, created_at,
+			problem_title, problem_options, explanation, similarity_hash, quality_score, model,
+			answer_position, option_count, rationale)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := db.Exec(query, result.ID, result.SessionID, result.ProblemType, result.Difficulty,
+	_, err := exec(query, result.ID, result.SessionID, result.ProblemType, result.Difficulty,
 		result.IsCorrect, result.TimeTaken, result.EmotionAtAnswer, result.ErrorCategory,
-		result.ProblemContent, result.UserAnswer, result.CorrectAnswer, result.CreatedAt)
+		result.ProblemContent, result.UserAnswer, result.CorrectAnswer, result.CreatedAt,
+		result.ProblemTitle, result.ProblemOptions, result.Explanation, result.SimilarityHash, result.QualityScore,
+		result.Model, result.AnswerPosition, result.OptionCount, result.Rationale)
+	return err
+}
+
+// UpdateProblemResultRationale 解答のあとに聞いた、答えを選んだ理由を保存
+func (db *DB) UpdateProblemResultRationale(resultID, rationale string) error {
+	_, err := db.exec(`UPDATE problem_results SET rationale = ? WHERE id = ?`, rationale, resultID)
 	return err
 }
 
@@ -425,17 +1205,38 @@ func (db *DB) UpdateVirtualPet(pet *VirtualPet) error {
 	return err
 }
 
+// GetStudySession 学習セッション取得（見つからなければnil）
+func (db *DB) GetStudySession(sessionID string) (*StudySession, error) {
+	query := `
+		SELECT id, user_id, subject, start_time, end_time, total_problems,
+			correct_answers, average_emotion, session_type, note, max_combo, paused_seconds, created_at
+		FROM study_sessions
+		WHERE id = ?
+	`
+	var session StudySession
+	err := db.QueryRow(query, sessionID).Scan(&session.ID, &session.UserID, &session.Subject, &session.StartTime,
+		&session.EndTime, &session.TotalProblems, &session.CorrectAnswers,
+		&session.AverageEmotion, &session.SessionType, &session.Note, &session.MaxCombo, &session.PausedSeconds, &session.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
 // GetRecentStudySessions 最近の学習セッション取得
 func (db *DB) GetRecentStudySessions(userID string, limit int) ([]StudySession, error) {
 	query := `
 		SELECT id, user_id, subject, start_time, end_time, total_problems, 
-			correct_answers, average_emotion, created_at
+			correct_answers, average_emotion, session_type, note, max_combo, paused_seconds, created_at
 		FROM study_sessions 
 		WHERE user_id = ? 
 		ORDER BY start_time DESC 
 		LIMIT ?
 	`
-	rows, err := db.Query(query, userID, limit)
+	rows, err := db.query(query, userID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -446,7 +1247,7 @@ func (db *DB) GetRecentStudySessions(userID string, limit int) ([]StudySession,
 		var session StudySession
 		err := rows.Scan(&session.ID, &session.UserID, &session.Subject, &session.StartTime,
 			&session.EndTime, &session.TotalProblems, &session.CorrectAnswers, 
-			&session.AverageEmotion, &session.CreatedAt)
+			&session.AverageEmotion, &session.SessionType, &session.Note, &session.MaxCombo, &session.PausedSeconds, &session.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -456,6 +1257,1486 @@ func (db *DB) GetRecentStudySessions(userID string, limit int) ([]StudySession,
 	return sessions, nil
 }
 
+// GetStudySessionsBetween 期間内の学習セッション取得
+func (db *DB) GetStudySessionsBetween(userID string, from, to time.Time) ([]StudySession, error) {
+	query := `
+		SELECT id, user_id, subject, start_time, end_time, total_problems,
+			correct_answers, average_emotion, session_type, note, max_combo, paused_seconds, created_at
+		FROM study_sessions
+		WHERE user_id = ? AND start_time >= ? AND start_time < ?
+		ORDER BY start_time ASC
+	`
+	rows, err := db.Query(query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sessions []StudySession
+	for rows.Next() {
+		var session StudySession
+		err := rows.Scan(&session.ID, &session.UserID, &session.Subject, &session.StartTime,
+			&session.EndTime, &session.TotalProblems, &session.CorrectAnswers,
+			&session.AverageEmotion, &session.SessionType, &session.Note, &session.MaxCombo, &session.PausedSeconds, &session.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// GetOpenStudySessions 終了していない学習セッション取得（アプリが途中で落ちたセッション。開始順）
+func (db *DB) GetOpenStudySessions(userID string) ([]StudySession, error) {
+	query := `
+		SELECT id, user_id, subject, start_time, end_time, total_problems,
+			correct_answers, average_emotion, session_type, note, max_combo, paused_seconds, created_at
+		FROM study_sessions
+		WHERE user_id = ? AND end_time IS NULL
+		ORDER BY start_time ASC
+	`
+	rows, err := db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sessions []StudySession
+	for rows.Next() {
+		var session StudySession
+		err := rows.Scan(&session.ID, &session.UserID, &session.Subject, &session.StartTime,
+			&session.EndTime, &session.TotalProblems, &session.CorrectAnswers,
+			&session.AverageEmotion, &session.SessionType, &session.Note, &session.MaxCombo, &session.PausedSeconds, &session.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// StudyTotals 学習時間・学習日数の合計（終了していないセッションの学習時間は0）
+type StudyTotals struct {
+	StudySeconds  int // 学習時間（秒）
+	ManualSeconds int // うちアプリ外の学習の手動記録（秒）
+	StudyDays     int // 学習した日数
+}
+
+// GetStudyTotals ユーザーの全セッションの学習時間・学習日数を集計（セッションを読み込まずに数える）
+func (db *DB) GetStudyTotals(userID string) (StudyTotals, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(seconds), 0),
+			COALESCE(SUM(CASE WHEN session_type = ? THEN seconds ELSE 0 END), 0),
+			COUNT(DISTINCT day)
+		FROM (
+			-- 日付は保存した時刻の表記のまま数える（UTCに直すと朝の学習が前日になる）
+			SELECT session_type, %s AS day, COALESCE(%s - paused_seconds, 0) AS seconds
+			FROM study_sessions
+			WHERE user_id = ?
+		) AS sessions
+	`
+	query = fmt.Sprintf(query, db.dialect.localDate("start_time"), db.dialect.secondsBetween("start_time", "end_time"))
+	var totals StudyTotals
+	err := db.QueryRow(query, SessionTypeManual, userID).Scan(&totals.StudySeconds, &totals.ManualSeconds, &totals.StudyDays)
+	return totals, err
+}
+
+// FeatureUsage 機能ごとの利用回数（利用統計に表示する）
+type FeatureUsage struct {
+	Exams            int // 模擬テスト
+	ManualLogs       int // アプリ外の学習の手動記録
+	ReviewQuizzes    int // 翌日の復習クイズ（答えたもの）
+	FlashcardReviews int // 復習したことのある単語カード
+	DiaryEntries     int // 学習日記を書いた日
+	BankProblems     int // 問題バンクに保存した問題
+}
+
+// GetFeatureUsage ユーザーの機能ごとの利用回数を集計
+func (db *DB) GetFeatureUsage(userID string) (FeatureUsage, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM study_sessions WHERE user_id = ? AND session_type = ?),
+			(SELECT COUNT(*) FROM study_sessions WHERE user_id = ? AND session_type = ?),
+			(SELECT COUNT(*) FROM review_cards WHERE user_id = ? AND reviewed_at IS NOT NULL),
+			(SELECT COUNT(*) FROM flashcards f JOIN flashcard_decks d ON d.id = f.deck_id
+				WHERE d.user_id = ? AND f.last_reviewed IS NOT NULL),
+			(SELECT COUNT(*) FROM study_diary WHERE user_id = ?),
+			(SELECT COUNT(*) FROM problem_bank WHERE user_id = ?)
+	`
+	var usage FeatureUsage
+	err := db.QueryRow(query, userID, SessionTypeExam, userID, SessionTypeManual, userID, userID, userID, userID).Scan(
+		&usage.Exams, &usage.ManualLogs, &usage.ReviewQuizzes, &usage.FlashcardReviews, &usage.DiaryEntries, &usage.BankProblems)
+	return usage, err
+}
+
+// GetProblemResultsBetween 期間内の問題解答結果取得
+func (db *DB) GetProblemResultsBetween(userID string, from, to time.Time) ([]ProblemResult, error) {
+	query := `
+		SELECT r.id, r.session_id, r.problem_type, r.difficulty, r.is_correct, r.time_taken,
+			COALESCE(r.emotion_at_answer, ''), COALESCE(r.error_category, ''),
+			COALESCE(r.problem_content, ''), COALESCE(r.user_answer, ''),
+			COALESCE(r.correct_answer, ''), r.created_at
+		FROM problem_results r
+		JOIN study_sessions s ON s.id = r.session_id
+		WHERE s.user_id = ? AND r.created_at >= ? AND r.created_at < ?
+		ORDER BY r.created_at ASC
+	`
+	rows, err := db.Query(query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []ProblemResult
+	for rows.Next() {
+		var result ProblemResult
+		err := rows.Scan(&result.ID, &result.SessionID, &result.ProblemType, &result.Difficulty,
+			&result.IsCorrect, &result.TimeTaken, &result.EmotionAtAnswer, &result.ErrorCategory,
+			&result.ProblemContent, &result.UserAnswer, &result.CorrectAnswer, &result.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// GetMistakes 間違えた問題を取得（新しい順、最大limit件）
+func (db *DB) GetMistakes(userID string, filter MistakeFilter, limit int) ([]Mistake, error) {
+	query := `
+		SELECT r.id, r.session_id, r.problem_type, r.difficulty, r.is_correct, r.time_taken,
+			COALESCE(r.emotion_at_answer, ''), COALESCE(r.error_category, ''),
+			COALESCE(r.problem_content, ''), COALESCE(r.user_answer, ''),
+			COALESCE(r.correct_answer, ''), r.created_at,
+			r.problem_title, r.problem_options, r.explanation, s.subject,
+			EXISTS (SELECT 1 FROM problem_sketches ps WHERE ps.result_id = r.id)
+		FROM problem_results r
+		JOIN study_sessions s ON s.id = r.session_id
+		WHERE s.user_id = ? AND NOT r.is_correct
+	`
+	args := []any{userID}
+	if filter.Subject != "" {
+		query += " AND s.subject = ?"
+		args = append(args, filter.Subject)
+	}
+	if filter.ProblemType != "" {
+		query += " AND r.problem_type = ?"
+		args = append(args, filter.ProblemType)
+	}
+	if !filter.Since.IsZero() {
+		query += " AND r.created_at >= ?"
+		args = append(args, filter.Since)
+	}
+	query += " ORDER BY r.created_at DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var mistakes []Mistake
+	for rows.Next() {
+		var m Mistake
+		err := rows.Scan(&m.ID, &m.SessionID, &m.ProblemType, &m.Difficulty,
+			&m.IsCorrect, &m.TimeTaken, &m.EmotionAtAnswer, &m.ErrorCategory,
+			&m.ProblemContent, &m.UserAnswer, &m.CorrectAnswer, &m.CreatedAt,
+			&m.ProblemTitle, &m.ProblemOptions, &m.Explanation, &m.Subject, &m.HasSketch)
+		if err != nil {
+			return nil, err
+		}
+		mistakes = append(mistakes, m)
+	}
+
+	return mistakes, rows.Err()
+}
+
+// SaveProblemSketch 解答結果に手書きの計算メモ（PNG画像）を保存
+func (db *DB) SaveProblemSketch(resultID string, image []byte) error {
+	query := `
+		INSERT INTO problem_sketches (result_id, image, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(result_id) DO UPDATE SET image = excluded.image, created_at = excluded.created_at
+	`
+	_, err := db.Exec(query, resultID, base64.StdEncoding.EncodeToString(image), time.Now())
+	return err
+}
+
+// GetProblemSketch 解答結果の計算メモ（PNG画像）を取得（保存していなければnil）
+func (db *DB) GetProblemSketch(resultID string) ([]byte, error) {
+	var data string
+	err := db.QueryRow(`SELECT image FROM problem_sketches WHERE result_id = ?`, resultID).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("計算メモ取得エラー: %w", err)
+	}
+	image, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("計算メモ解析エラー: %w", err)
+	}
+	return image, nil
+}
+
+// GetMistakeProblemTypes 間違えた問題の単元の一覧（科目が空ならすべての科目）
+func (db *DB) GetMistakeProblemTypes(userID, subject string) ([]string, error) {
+	query := `
+		SELECT DISTINCT r.problem_type
+		FROM problem_results r
+		JOIN study_sessions s ON s.id = r.session_id
+		WHERE s.user_id = ? AND NOT r.is_correct AND r.problem_type != '' AND (? = '' OR s.subject = ?)
+		ORDER BY r.problem_type
+	`
+	rows, err := db.Query(query, userID, subject, subject)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var types []string
+	for rows.Next() {
+		var problemType string
+		if err := rows.Scan(&problemType); err != nil {
+			return nil, err
+		}
+		types = append(types, problemType)
+	}
+
+	return types, rows.Err()
+}
+
+// AnswerPositionCount 選択肢の数ごとの、何番目の選択肢を選んだかの件数
+type AnswerPositionCount struct {
+	OptionCount int
+	Position    int // 0から
+	Answers     int
+}
+
+// GetAnswerPositionCounts 期間内の解答で、選択肢の何番目を選んだかの件数を取得（位置を記録した解答のみ）
+func (db *DB) GetAnswerPositionCounts(userID string, since time.Time) ([]AnswerPositionCount, error) {
+	query := `
+		SELECT pr.option_count, pr.answer_position, COUNT(*)
+		FROM problem_results pr
+		JOIN study_sessions ss ON pr.session_id = ss.id
+		WHERE ss.user_id = ? AND pr.created_at >= ? AND pr.option_count > 0 AND pr.answer_position >= 0
+		GROUP BY pr.option_count, pr.answer_position
+		ORDER BY pr.option_count, pr.answer_position
+	`
+	rows, err := db.Query(query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var counts []AnswerPositionCount
+	for rows.Next() {
+		var count AnswerPositionCount
+		if err := rows.Scan(&count.OptionCount, &count.Position, &count.Answers); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// GetProblemResultsBySession セッションの問題解答結果取得（解答順）
+func (db *DB) GetProblemResultsBySession(sessionID string) ([]ProblemResult, error) {
+	query := `
+		SELECT id, session_id, problem_type, difficulty, is_correct, time_taken,
+			COALESCE(emotion_at_answer, ''), COALESCE(error_category, ''),
+			COALESCE(problem_content, ''), COALESCE(user_answer, ''),
+			COALESCE(correct_answer, ''), created_at
+		FROM problem_results
+		WHERE session_id = ?
+		ORDER BY created_at ASC
+	`
+	rows, err := db.Query(query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []ProblemResult
+	for rows.Next() {
+		var result ProblemResult
+		err := rows.Scan(&result.ID, &result.SessionID, &result.ProblemType, &result.Difficulty,
+			&result.IsCorrect, &result.TimeTaken, &result.EmotionAtAnswer, &result.ErrorCategory,
+			&result.ProblemContent, &result.UserAnswer, &result.CorrectAnswer, &result.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// CreateTimetableEntry 時間割を追加
+func (db *DB) CreateTimetableEntry(entry *TimetableEntry) error {
+	query := `
+		INSERT INTO timetable_entries (id, user_id, weekday, start_time, end_time, label, kind, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := db.Exec(query, entry.ID, entry.UserID, entry.Weekday, entry.StartTime,
+		entry.EndTime, entry.Label, entry.Kind, entry.CreatedAt)
+	return err
+}
+
+// DeleteTimetableEntry 時間割を削除
+func (db *DB) DeleteTimetableEntry(userID, entryID string) error {
+	_, err := db.Exec(`DELETE FROM timetable_entries WHERE id = ? AND user_id = ?`, entryID, userID)
+	return err
+}
+
+// GetTimetableEntries 時間割を取得（曜日・開始時刻順）
+func (db *DB) GetTimetableEntries(userID string) ([]TimetableEntry, error) {
+	query := `
+		SELECT id, user_id, weekday, start_time, end_time, label, kind, created_at
+		FROM timetable_entries
+		WHERE user_id = ?
+		ORDER BY weekday ASC, start_time ASC
+	`
+	rows, err := db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []TimetableEntry
+	for rows.Next() {
+		var entry TimetableEntry
+		err := rows.Scan(&entry.ID, &entry.UserID, &entry.Weekday, &entry.StartTime,
+			&entry.EndTime, &entry.Label, &entry.Kind, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// UpsertScheduleException 例外日を登録
+func (db *DB) UpsertScheduleException(exception *ScheduleException) error {
+	query := `
+		INSERT INTO schedule_exceptions (user_id, date, note)
+		VALUES (?, ?, ?)
+		ON CONFLICT(user_id, date) DO UPDATE SET note = excluded.note
+	`
+	_, err := db.Exec(query, exception.UserID, exception.Date, exception.Note)
+	return err
+}
+
+// DeleteScheduleException 例外日を削除
+func (db *DB) DeleteScheduleException(userID, date string) error {
+	_, err := db.Exec(`DELETE FROM schedule_exceptions WHERE user_id = ? AND date = ?`, userID, date)
+	return err
+}
+
+// GetScheduleExceptions 例外日を取得（日付順）
+func (db *DB) GetScheduleExceptions(userID string) ([]ScheduleException, error) {
+	query := `
+		SELECT user_id, date, COALESCE(note, '')
+		FROM schedule_exceptions
+		WHERE user_id = ?
+		ORDER BY date ASC
+	`
+	rows, err := db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var exceptions []ScheduleException
+	for rows.Next() {
+		var exception ScheduleException
+		if err := rows.Scan(&exception.UserID, &exception.Date, &exception.Note); err != nil {
+			return nil, err
+		}
+		exceptions = append(exceptions, exception)
+	}
+
+	return exceptions, rows.Err()
+}
+
+// UpsertSessionFocus セッションの集中度を保存
+func (db *DB) UpsertSessionFocus(focus *SessionFocus) error {
+	query := `
+		INSERT INTO session_focus (session_id, user_id, pause_count, breaks_suggested,
+			breaks_taken, answer_count, answer_time_cv, focus_score, started_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+			user_id = excluded.user_id, pause_count = excluded.pause_count,
+			breaks_suggested = excluded.breaks_suggested, breaks_taken = excluded.breaks_taken,
+			answer_count = excluded.answer_count, answer_time_cv = excluded.answer_time_cv,
+			focus_score = excluded.focus_score, started_at = excluded.started_at
+	`
+	_, err := db.Exec(query, focus.SessionID, focus.UserID, focus.PauseCount, focus.BreaksSuggested,
+		focus.BreaksTaken, focus.AnswerCount, focus.AnswerTimeCV, focus.FocusScore, focus.StartedAt)
+	return err
+}
+
+// GetSessionFocusSince 指定日時以降のセッション集中度を取得（古い順）
+func (db *DB) GetSessionFocusSince(userID string, since time.Time) ([]SessionFocus, error) {
+	query := `
+		SELECT session_id, user_id, pause_count, breaks_suggested, breaks_taken,
+			answer_count, answer_time_cv, focus_score, started_at
+		FROM session_focus
+		WHERE user_id = ? AND started_at >= ?
+		ORDER BY started_at ASC
+	`
+	rows, err := db.Query(query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var focuses []SessionFocus
+	for rows.Next() {
+		var focus SessionFocus
+		err := rows.Scan(&focus.SessionID, &focus.UserID, &focus.PauseCount, &focus.BreaksSuggested,
+			&focus.BreaksTaken, &focus.AnswerCount, &focus.AnswerTimeCV, &focus.FocusScore, &focus.StartedAt)
+		if err != nil {
+			return nil, err
+		}
+		focuses = append(focuses, focus)
+	}
+
+	return focuses, rows.Err()
+}
+
+// CreateXPEvent 経験値の獲得を記録
+func (db *DB) CreateXPEvent(event *XPEvent) error {
+	return insertXPEvent(db.exec, event)
+}
+
+// insertXPEvent 経験値の獲得を追加（準備済みの文で実行する）
+func insertXPEvent(exec execFunc, event *XPEvent) error {
+	query := `
+		INSERT INTO xp_events (id, user_id, source, amount, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := exec(query, event.ID, event.UserID, event.Source, event.Amount, event.Reason, event.CreatedAt)
+	return err
+}
+
+// GetTotalXP ユーザーの累計経験値を取得
+func (db *DB) GetTotalXP(userID string) (int, error) {
+	var total int
+	err := db.QueryRow(`SELECT COALESCE(SUM(amount), 0) FROM xp_events WHERE user_id = ?`, userID).Scan(&total)
+	return total, err
+}
+
+// GetXPBetween 期間内に獲得した経験値の合計を取得
+func (db *DB) GetXPBetween(userID string, from, to time.Time) (int, error) {
+	var total int
+	query := `SELECT COALESCE(SUM(amount), 0) FROM xp_events WHERE user_id = ? AND created_at >= ? AND created_at <= ?`
+	err := db.QueryRow(query, userID, from, to).Scan(&total)
+	return total, err
+}
+
+// CountXPEvents ユーザーの経験値獲得記録の件数を取得
+func (db *DB) CountXPEvents(userID string) (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM xp_events WHERE user_id = ?`, userID).Scan(&count)
+	return count, err
+}
+
+// CountProblemResults ユーザーが解答した問題数を取得
+func (db *DB) CountProblemResults(userID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM problem_results pr
+		JOIN study_sessions ss ON pr.session_id = ss.id
+		WHERE ss.user_id = ?
+	`
+	var count int
+	err := db.QueryRow(query, userID).Scan(&count)
+	return count, err
+}
+
+// CreateAchievement 実績の獲得を記録（獲得済みなら何もしない）。新しく獲得した場合はtrue
+func (db *DB) CreateAchievement(earned *EarnedAchievement) (bool, error) {
+	query := `
+		INSERT INTO achievements (user_id, achievement_id, earned_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT DO NOTHING
+	`
+	result, err := db.Exec(query, earned.UserID, earned.AchievementID, earned.EarnedAt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// GetAchievements 獲得した実績を取得（古い順）
+func (db *DB) GetAchievements(userID string) ([]EarnedAchievement, error) {
+	query := `
+		SELECT user_id, achievement_id, earned_at
+		FROM achievements
+		WHERE user_id = ?
+		ORDER BY earned_at ASC
+	`
+	rows, err := db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var achievements []EarnedAchievement
+	for rows.Next() {
+		var earned EarnedAchievement
+		if err := rows.Scan(&earned.UserID, &earned.AchievementID, &earned.EarnedAt); err != nil {
+			return nil, err
+		}
+		achievements = append(achievements, earned)
+	}
+
+	return achievements, rows.Err()
+}
+
+// RecordTopicResult 単元別の解答統計に1問分を加算
+func (db *DB) RecordTopicResult(userID, subject, topic string, isCorrect bool, answeredAt time.Time) error {
+	return addTopicResult(db.exec, userID, subject, topic, isCorrect, answeredAt)
+}
+
+// addTopicResult 単元別の解答統計に1問分を加算（準備済みの文で実行する）
+func addTopicResult(exec execFunc, userID, subject, topic string, isCorrect bool, answeredAt time.Time) error {
+	correct := 0
+	if isCorrect {
+		correct = 1
+	}
+	query := `
+		INSERT INTO topic_mastery (user_id, subject, topic, attempts, correct_answers, last_studied)
+		VALUES (?, ?, ?, 1, ?, ?)
+		ON CONFLICT (user_id, subject, topic) DO UPDATE SET
+			attempts = attempts + 1,
+			correct_answers = correct_answers + excluded.correct_answers,
+			last_studied = excluded.last_studied
+	`
+	_, err := exec(query, userID, subject, topic, correct, answeredAt)
+	return err
+}
+
+// GetTopicMastery 単元別の解答統計を取得（科目・単元順）
+func (db *DB) GetTopicMastery(userID string) ([]TopicMastery, error) {
+	query := `
+		SELECT user_id, subject, topic, attempts, correct_answers, last_studied
+		FROM topic_mastery
+		WHERE user_id = ?
+		ORDER BY subject, topic
+	`
+	rows, err := db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var topics []TopicMastery
+	for rows.Next() {
+		var topic TopicMastery
+		err := rows.Scan(&topic.UserID, &topic.Subject, &topic.Topic, &topic.Attempts,
+			&topic.CorrectAnswers, &topic.LastStudied)
+		if err != nil {
+			return nil, err
+		}
+		topics = append(topics, topic)
+	}
+
+	return topics, rows.Err()
+}
+
+// CreateReviewCard 復習カードを作成
+func (db *DB) CreateReviewCard(card *ReviewCard) error {
+	query := `
+		INSERT INTO review_cards (id, user_id, session_id, subject, takeaway, question, answer, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := db.Exec(query, card.ID, card.UserID, card.SessionID, card.Subject,
+		card.Takeaway, card.Question, card.Answer, card.CreatedAt)
+	return err
+}
+
+// GetPendingReviewCards 指定日時より前に作られた未復習の復習カードを取得（新しいセッションの順、最大limit件）
+func (db *DB) GetPendingReviewCards(userID string, before time.Time, limit int) ([]ReviewCard, error) {
+	query := `
+		SELECT id, user_id, session_id, subject, takeaway, question, answer, created_at
+		FROM review_cards
+		WHERE user_id = ? AND created_at < ? AND reviewed_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+	rows, err := db.Query(query, userID, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var cards []ReviewCard
+	for rows.Next() {
+		var card ReviewCard
+		err := rows.Scan(&card.ID, &card.UserID, &card.SessionID, &card.Subject,
+			&card.Takeaway, &card.Question, &card.Answer, &card.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+
+	return cards, rows.Err()
+}
+
+// MarkReviewCard 復習カードの復習結果を記録
+func (db *DB) MarkReviewCard(id string, remembered bool, reviewedAt time.Time) error {
+	_, err := db.Exec(`UPDATE review_cards SET reviewed_at = ?, remembered = ? WHERE id = ?`, reviewedAt, remembered, id)
+	return err
+}
+
+// GetFlashcardDeck 種類を指定してデッキを取得（存在しない場合はsql.ErrNoRows）
+func (db *DB) GetFlashcardDeck(userID, kind string) (*FlashcardDeck, error) {
+	query := `SELECT id, user_id, name, kind, created_at FROM flashcard_decks WHERE user_id = ? AND kind = ?`
+	var deck FlashcardDeck
+	err := db.QueryRow(query, userID, kind).Scan(&deck.ID, &deck.UserID, &deck.Name, &deck.Kind, &deck.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &deck, nil
+}
+
+// CreateFlashcardDeck デッキを作成
+func (db *DB) CreateFlashcardDeck(deck *FlashcardDeck) error {
+	query := `INSERT INTO flashcard_decks (id, user_id, name, kind, created_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := db.Exec(query, deck.ID, deck.UserID, deck.Name, deck.Kind, deck.CreatedAt)
+	return err
+}
+
+// CreateFlashcard 単語カードを追加（同じデッキに同じ表面のカードがあれば追加しない）。追加した場合はtrue
+func (db *DB) CreateFlashcard(card *Flashcard) (bool, error) {
+	query := `
+		INSERT INTO flashcards (id, deck_id, front, back, hint, ease_factor, interval_days,
+			repetitions, due_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT DO NOTHING
+	`
+	result, err := db.Exec(query, card.ID, card.DeckID, card.Front, card.Back, card.Hint,
+		card.EaseFactor, card.IntervalDays, card.Repetitions, card.DueAt, card.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// UpdateFlashcardSchedule 単語カードの復習スケジュールを更新
+func (db *DB) UpdateFlashcardSchedule(card *Flashcard) error {
+	query := `
+		UPDATE flashcards
+		SET ease_factor = ?, interval_days = ?, repetitions = ?, due_at = ?, last_reviewed = ?
+		WHERE id = ?
+	`
+	_, err := db.Exec(query, card.EaseFactor, card.IntervalDays, card.Repetitions, card.DueAt, card.LastReviewed, card.ID)
+	return err
+}
+
+// GetDueFlashcards 復習時期になった単語カードを取得（期限の古い順、最大limit件）
+func (db *DB) GetDueFlashcards(deckID string, now time.Time, limit int) ([]Flashcard, error) {
+	query := `
+		SELECT id, deck_id, front, back, hint, ease_factor, interval_days, repetitions,
+			due_at, last_reviewed, created_at
+		FROM flashcards
+		WHERE deck_id = ? AND due_at <= ?
+		ORDER BY due_at ASC
+		LIMIT ?
+	`
+	return db.queryFlashcards(query, deckID, now, limit)
+}
+
+// GetFlashcards デッキのすべての単語カードを取得（追加した順）
+func (db *DB) GetFlashcards(deckID string) ([]Flashcard, error) {
+	query := `
+		SELECT id, deck_id, front, back, hint, ease_factor, interval_days, repetitions,
+			due_at, last_reviewed, created_at
+		FROM flashcards
+		WHERE deck_id = ?
+		ORDER BY created_at ASC
+	`
+	return db.queryFlashcards(query, deckID)
+}
+
+// queryFlashcards 単語カードの一覧を取得
+func (db *DB) queryFlashcards(query string, args ...any) ([]Flashcard, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var cards []Flashcard
+	for rows.Next() {
+		var card Flashcard
+		err := rows.Scan(&card.ID, &card.DeckID, &card.Front, &card.Back, &card.Hint,
+			&card.EaseFactor, &card.IntervalDays, &card.Repetitions, &card.DueAt,
+			&card.LastReviewed, &card.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+
+	return cards, rows.Err()
+}
+
+// GetFlashcardFronts デッキにあるカードの表面の一覧を取得
+func (db *DB) GetFlashcardFronts(deckID string) ([]string, error) {
+	rows, err := db.Query(`SELECT front FROM flashcards WHERE deck_id = ? ORDER BY created_at ASC`, deckID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var fronts []string
+	for rows.Next() {
+		var front string
+		if err := rows.Scan(&front); err != nil {
+			return nil, err
+		}
+		fronts = append(fronts, front)
+	}
+
+	return fronts, rows.Err()
+}
+
+// CountFlashcards デッキのカード数と復習時期になったカード数を取得
+func (db *DB) CountFlashcards(deckID string, now time.Time) (total, due int, err error) {
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN due_at <= ? THEN 1 ELSE 0 END), 0)
+		FROM flashcards
+		WHERE deck_id = ?
+	`
+	err = db.QueryRow(query, now, deckID).Scan(&total, &due)
+	return total, due, err
+}
+
+// SaveStudyTip 学習のコツを保存（同じコツがすでにあれば保存しない）
+func (db *DB) SaveStudyTip(tip *StudyTip) error {
+	query := `INSERT INTO study_tips (id, user_id, subject, tip, created_at) VALUES (?, ?, ?, ?, ?) ON CONFLICT DO NOTHING`
+	_, err := db.Exec(query, tip.ID, tip.UserID, tip.Subject, tip.Tip, tip.CreatedAt)
+	return err
+}
+
+// GetRecentStudyTips 最近の学習のコツを取得（新しい順、最大limit件）
+func (db *DB) GetRecentStudyTips(userID string, limit int) ([]StudyTip, error) {
+	query := `
+		SELECT id, user_id, subject, tip, created_at
+		FROM study_tips
+		WHERE user_id = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+	rows, err := db.Query(query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tips []StudyTip
+	for rows.Next() {
+		var tip StudyTip
+		if err := rows.Scan(&tip.ID, &tip.UserID, &tip.Subject, &tip.Tip, &tip.CreatedAt); err != nil {
+			return nil, err
+		}
+		tips = append(tips, tip)
+	}
+
+	return tips, rows.Err()
+}
+
+// CreateBankProblem 問題バンクに問題を保存
+func (db *DB) CreateBankProblem(problem *BankProblem) error {
+	query := `
+		INSERT INTO problem_bank (id, user_id, subject, title, question, answer, explanation, topic, tag, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := db.Exec(query, problem.ID, problem.UserID, problem.Subject, problem.Title, problem.Question,
+		problem.Answer, problem.Explanation, problem.Topic, problem.Tag, problem.CreatedAt)
+	return err
+}
+
+// GetBankProblems タグを指定して問題バンクの問題を取得（新しい順、最大limit件）
+func (db *DB) GetBankProblems(userID, tag string, limit int) ([]BankProblem, error) {
+	query := `
+		SELECT id, user_id, subject, title, question, answer, explanation, topic, tag, created_at
+		FROM problem_bank
+		WHERE user_id = ? AND tag = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+	rows, err := db.Query(query, userID, tag, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var problems []BankProblem
+	for rows.Next() {
+		var p BankProblem
+		err := rows.Scan(&p.ID, &p.UserID, &p.Subject, &p.Title, &p.Question, &p.Answer,
+			&p.Explanation, &p.Topic, &p.Tag, &p.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, p)
+	}
+
+	return problems, rows.Err()
+}
+
+// GetCloudTokenUsage 指定月のクラウドAIの使用トークン数を取得（"2006-01"形式）
+func (db *DB) GetCloudTokenUsage(month string) (int, error) {
+	var tokens int
+	err := db.QueryRow(`SELECT COALESCE(SUM(tokens), 0) FROM cloud_token_usage WHERE month = ?`, month).Scan(&tokens)
+	return tokens, err
+}
+
+// AddCloudTokenUsage 指定月のクラウドAIの使用トークン数を加算
+func (db *DB) AddCloudTokenUsage(month string, tokens int) error {
+	query := `
+		INSERT INTO cloud_token_usage (month, tokens)
+		VALUES (?, ?)
+		ON CONFLICT(month) DO UPDATE SET tokens = tokens + excluded.tokens
+	`
+	_, err := db.Exec(query, month, tokens)
+	return err
+}
+
+// GetCloudDailyUsage 指定日のプロフィールのクラウドAIの使用回数とトークン数を取得（"2006-01-02"形式）
+func (db *DB) GetCloudDailyUsage(userID, date string) (requests, tokens int, err error) {
+	query := `
+		SELECT COALESCE(SUM(requests), 0), COALESCE(SUM(tokens), 0)
+		FROM cloud_daily_usage
+		WHERE user_id = ? AND date = ?
+	`
+	err = db.QueryRow(query, userID, date).Scan(&requests, &tokens)
+	return requests, tokens, err
+}
+
+// AddCloudDailyUsage 指定日のプロフィールのクラウドAIの使用回数を1回、トークン数をtokens加算
+func (db *DB) AddCloudDailyUsage(userID, date string, tokens int) error {
+	query := `
+		INSERT INTO cloud_daily_usage (user_id, date, requests, tokens)
+		VALUES (?, ?, 1, ?)
+		ON CONFLICT(user_id, date) DO UPDATE SET
+			requests = requests + 1,
+			tokens = tokens + excluded.tokens
+	`
+	_, err := db.Exec(query, userID, date, tokens)
+	return err
+}
+
+// GetOfflineRotation プロフィールが内蔵の問題を次に出題する位置を取得（まだなければ0）
+func (db *DB) GetOfflineRotation(userID, key string) (int, error) {
+	var position int
+	err := db.QueryRow(`SELECT position FROM offline_rotation WHERE user_id = ? AND rotation_key = ?`, userID, key).Scan(&position)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("出題位置取得エラー: %w", err)
+	}
+	return position, nil
+}
+
+// SaveOfflineRotation プロフィールが内蔵の問題を次に出題する位置を保存
+func (db *DB) SaveOfflineRotation(userID, key string, position int) error {
+	query := `
+		INSERT INTO offline_rotation (user_id, rotation_key, position, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(user_id, rotation_key) DO UPDATE SET
+			position = excluded.position,
+			updated_at = excluded.updated_at
+	`
+	if _, err := db.Exec(query, userID, key, position, time.Now()); err != nil {
+		return fmt.Errorf("出題位置保存エラー: %w", err)
+	}
+	return nil
+}
+
+// GetRecentSimilarityHashes 指定日時以降に出題した問題の類似ハッシュを取得（科目が空なら全科目）
+func (db *DB) GetRecentSimilarityHashes(userID, subject string, since time.Time) ([]string, error) {
+	query := `
+		SELECT DISTINCT pr.similarity_hash
+		FROM problem_results pr
+		JOIN study_sessions ss ON ss.id = pr.session_id
+		WHERE ss.user_id = ? AND (? = '' OR ss.subject = ?) AND pr.created_at >= ? AND pr.similarity_hash != ''
+	`
+	rows, err := db.Query(query, userID, subject, subject, since)
+	if err != nil {
+		return nil, fmt.Errorf("類似ハッシュ取得エラー: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("類似ハッシュ読み取りエラー: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
+
+// GetSimilarProblemAccuracy 同じ類似ハッシュの問題の解答数と正解数を取得（全ユーザー）
+func (db *DB) GetSimilarProblemAccuracy(hash string) (attempts, correct int, err error) {
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN is_correct THEN 1 ELSE 0 END), 0)
+		FROM problem_results WHERE similarity_hash = ?
+	`
+	stmt, err := db.prepared(query)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := stmt.QueryRow(hash).Scan(&attempts, &correct); err != nil {
+		return 0, 0, fmt.Errorf("正答率取得エラー: %w", err)
+	}
+	return attempts, correct, nil
+}
+
+// GetEmbedding 保存した埋め込みベクトルを取得（保存していなければnil）
+func (db *DB) GetEmbedding(model, key string) ([]float64, error) {
+	var data string
+	err := db.QueryRow(`SELECT embedding FROM text_embeddings WHERE model = ? AND text_hash = ?`, model, key).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("埋め込みベクトル取得エラー: %w", err)
+	}
+
+	var embedding []float64
+	if err := json.Unmarshal([]byte(data), &embedding); err != nil {
+		return nil, fmt.Errorf("埋め込みベクトル解析エラー: %w", err)
+	}
+	return embedding, nil
+}
+
+// SaveEmbedding 埋め込みベクトルを保存
+func (db *DB) SaveEmbedding(model, key string, embedding []float64) error {
+	data, err := json.Marshal(embedding)
+	if err != nil {
+		return fmt.Errorf("埋め込みベクトル変換エラー: %w", err)
+	}
+	query := `
+		INSERT INTO text_embeddings (model, text_hash, embedding, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(model, text_hash) DO UPDATE SET embedding = excluded.embedding, created_at = excluded.created_at
+	`
+	_, err = db.Exec(query, model, key, string(data), time.Now())
+	return err
+}
+
+// GetDiaryEntry 学習日記の1日分を取得（まだ書いていなければnil）
+func (db *DB) GetDiaryEntry(userID, date string) (*DiaryEntry, error) {
+	query := `SELECT user_id, date, content, updated_at FROM study_diary WHERE user_id = ? AND date = ?`
+	var entry DiaryEntry
+	err := db.QueryRow(query, userID, date).Scan(&entry.UserID, &entry.Date, &entry.Content, &entry.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// SaveDiaryEntry 学習日記の1日分を保存（同じ日の日記は上書き）
+func (db *DB) SaveDiaryEntry(entry *DiaryEntry) error {
+	query := `
+		INSERT INTO study_diary (user_id, date, content, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(user_id, date) DO UPDATE SET content = excluded.content, updated_at = excluded.updated_at
+	`
+	_, err := db.Exec(query, entry.UserID, entry.Date, entry.Content, entry.UpdatedAt)
+	return err
+}
+
+// GetDiaryEntries 期間内の学習日記を日付順に取得（from・toは"2006-01-02"形式。toの日を含む）
+func (db *DB) GetDiaryEntries(userID, from, to string) ([]DiaryEntry, error) {
+	query := `
+		SELECT user_id, date, content, updated_at
+		FROM study_diary
+		WHERE user_id = ? AND date >= ? AND date <= ?
+		ORDER BY date ASC
+	`
+	rows, err := db.Query(query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []DiaryEntry
+	for rows.Next() {
+		var entry DiaryEntry
+		if err := rows.Scan(&entry.UserID, &entry.Date, &entry.Content, &entry.UpdatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// CreateBoundaryHit クイック質問の範囲外の質問を記録
+func (db *DB) CreateBoundaryHit(hit *BoundaryHit) error {
+	query := `
+		INSERT INTO boundary_hits (id, user_id, stage, category, detail, question, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := db.Exec(query, hit.ID, hit.UserID, hit.Stage, hit.Category, hit.Detail, hit.Question, hit.CreatedAt)
+	return err
+}
+
+// GetBoundaryHits since以降の範囲外の質問の記録を新しい順に取得（最大limit件）
+func (db *DB) GetBoundaryHits(userID string, since time.Time, limit int) ([]BoundaryHit, error) {
+	query := `
+		SELECT id, user_id, stage, category, detail, question, created_at
+		FROM boundary_hits
+		WHERE user_id = ? AND created_at >= ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+	rows, err := db.Query(query, userID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var hits []BoundaryHit
+	for rows.Next() {
+		var hit BoundaryHit
+		if err := rows.Scan(&hit.ID, &hit.UserID, &hit.Stage, &hit.Category, &hit.Detail, &hit.Question, &hit.CreatedAt); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// CreateTutorTranscript AIとのやりとりを記録
+func (db *DB) CreateTutorTranscript(transcript *TutorTranscript) error {
+	query := `
+		INSERT INTO tutor_transcripts (id, user_id, session_id, source, subject, question, answer, refused, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := db.Exec(query, transcript.ID, transcript.UserID, transcript.SessionID, transcript.Source, transcript.Subject,
+		transcript.Question, transcript.Answer, transcript.Refused, transcript.CreatedAt)
+	return err
+}
+
+// GetTutorTranscripts since以降のAIとのやりとりの記録を古い順に取得
+func (db *DB) GetTutorTranscripts(userID string, since time.Time) ([]TutorTranscript, error) {
+	query := `
+		SELECT id, user_id, session_id, source, subject, question, answer, refused, created_at
+		FROM tutor_transcripts
+		WHERE user_id = ? AND created_at >= ?
+		ORDER BY created_at
+	`
+	rows, err := db.Query(query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var transcripts []TutorTranscript
+	for rows.Next() {
+		var t TutorTranscript
+		if err := rows.Scan(&t.ID, &t.UserID, &t.SessionID, &t.Source, &t.Subject, &t.Question, &t.Answer, &t.Refused, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		transcripts = append(transcripts, t)
+	}
+	return transcripts, rows.Err()
+}
+
+// PruneTutorTranscripts beforeより前のAIとのやりとりの記録を削除し、削除した件数を返す
+func (db *DB) PruneTutorTranscripts(before time.Time) (int64, error) {
+	result, err := db.Exec(`DELETE FROM tutor_transcripts WHERE created_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("AIとのやりとりの記録の削除エラー: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ProfileData 1人分のプロフィールの全データ（テーブルごとの行。別のパソコンへ移すため）
+type ProfileData struct {
+	UserID string                      `json:"user_id"`
+	Tables map[string][]map[string]any `json:"tables"`
+}
+
+// profileTable プロフィールに含めるテーブルと、そのプロフィールの行を選ぶ条件（?にユーザーID）
+type profileTable struct {
+	name  string
+	where string
+}
+
+// profileTables プロフィールに含めるテーブル（読み込むときはこの順に追加し、逆の順に削除する）
+// クラウドAIの使用量は家庭ごとの上限の管理に使うため含めない。教室モードの役割・課題も教室のパソコンで管理するため含めない
+var profileTables = []profileTable{
+	{"users", "id = ?"},
+	{"study_sessions", "user_id = ?"},
+	{"problem_results", "session_id IN (SELECT id FROM study_sessions WHERE user_id = ?)"},
+	{"problem_sketches", "result_id IN (SELECT id FROM problem_results WHERE session_id IN (SELECT id FROM study_sessions WHERE user_id = ?))"},
+	{"learning_progress", "user_id = ?"},
+	{"virtual_pets", "user_id = ?"},
+	{"error_patterns", "user_id = ?"},
+	{"timetable_entries", "user_id = ?"},
+	{"schedule_exceptions", "user_id = ?"},
+	{"session_focus", "user_id = ?"},
+	{"xp_events", "user_id = ?"},
+	{"achievements", "user_id = ?"},
+	{"topic_mastery", "user_id = ?"},
+	{"review_cards", "user_id = ?"},
+	{"flashcard_decks", "user_id = ?"},
+	{"flashcards", "deck_id IN (SELECT id FROM flashcard_decks WHERE user_id = ?)"},
+	{"study_tips", "user_id = ?"},
+	{"problem_bank", "user_id = ?"},
+	{"study_diary", "user_id = ?"},
+	{"boundary_hits", "user_id = ?"},
+	{"tutor_transcripts", "user_id = ?"},
+	{"offline_rotation", "user_id = ?"},
+}
+
+// sqliteTimeLayout go-sqlite3が日時を保存する形式（読み込んだ日時も同じ形式で保存し、日時の比較が変わらないようにする）
+const sqliteTimeLayout = "2006-01-02 15:04:05.999999999-07:00"
+
+// ExportProfile プロフィールの全データを取得
+func (db *DB) ExportProfile(userID string) (*ProfileData, error) {
+	data := &ProfileData{UserID: userID, Tables: make(map[string][]map[string]any)}
+	for _, table := range profileTables {
+		rows, err := db.exportProfileRows(table, userID)
+		if err != nil {
+			return nil, fmt.Errorf("プロフィール取得エラー（%s）: %w", table.name, err)
+		}
+		if len(rows) > 0 {
+			data.Tables[table.name] = rows
+		}
+	}
+	if len(data.Tables["users"]) == 0 {
+		return nil, fmt.Errorf("プロフィールが見つかりません: %s", userID)
+	}
+	return data, nil
+}
+
+// exportProfileRows テーブルから、プロフィールの行をすべての列について取得
+func (db *DB) exportProfileRows(table profileTable, userID string) ([]map[string]any, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT * FROM %s WHERE %s", table.name, table.where), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]any
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			switch v := values[i].(type) {
+			case []byte:
+				row[column] = string(v)
+			case time.Time:
+				row[column] = v.Format(sqliteTimeLayout)
+			default:
+				row[column] = v
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// ImportProfile userIDのプロフィールのデータを、書き出したプロフィールのデータで置き換える
+// （別のパソコンで書き出したプロフィールも、このパソコンのプロフィールとして読み込む）
+func (db *DB) ImportProfile(userID string, data *ProfileData) error {
+	if data == nil || len(data.Tables["users"]) != 1 {
+		return fmt.Errorf("プロフィールのデータが正しくありません")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクション開始エラー: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	exec := db.dialect.txExec(tx)
+
+	// プロフィールの行（users）は削除せずに書き換える
+	// （含めないテーブル（クラウドAIの使用量）がこのプロフィールを外部キーで参照しているため）
+	for i := len(profileTables) - 1; i > 0; i-- {
+		table := profileTables[i]
+		if _, err := exec(fmt.Sprintf("DELETE FROM %s WHERE %s", table.name, table.where), userID); err != nil {
+			return fmt.Errorf("プロフィール削除エラー（%s）: %w", table.name, err)
+		}
+	}
+
+	for _, table := range profileTables {
+		columns, err := db.dialect.tableColumns(tx, table.name)
+		if err != nil {
+			return fmt.Errorf("スキーマ確認エラー: %w", err)
+		}
+		if table.name == "users" {
+			if err := replaceProfileUser(exec, columns, data.Tables[table.name][0], userID); err != nil {
+				return fmt.Errorf("プロフィール保存エラー（%s）: %w", table.name, err)
+			}
+			continue
+		}
+		for _, row := range data.Tables[table.name] {
+			if err := insertProfileRow(exec, table.name, columns, row, userID); err != nil {
+				return fmt.Errorf("プロフィール追加エラー（%s）: %w", table.name, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("プロフィール保存エラー: %w", err)
+	}
+	return nil
+}
+
+// replaceProfileUser 読み込み先のプロフィールの行を、書き出したプロフィールの内容に書き換える（まだなければ追加）
+func replaceProfileUser(exec execFunc, columns []string, row map[string]any, userID string) error {
+	names, args := profileRowValues("users", columns, row, userID)
+	assignments := make([]string, len(names))
+	for i, name := range names {
+		assignments[i] = name + " = ?"
+	}
+	query := fmt.Sprintf("UPDATE users SET %s WHERE id = ?", strings.Join(assignments, ", "))
+	result, err := exec(query, append(args, userID)...)
+	if err != nil {
+		return err
+	}
+	if updated, err := result.RowsAffected(); err != nil || updated > 0 {
+		return err
+	}
+	return insertProfileRow(exec, "users", columns, row, userID)
+}
+
+// insertProfileRow 書き出したプロフィールの1行を追加
+func insertProfileRow(exec execFunc, table string, columns []string, row map[string]any, userID string) error {
+	names, args := profileRowValues(table, columns, row, userID)
+	if len(names) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(names))
+	for i := range placeholders {
+		placeholders[i] = "?"
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(names, ", "), strings.Join(placeholders, ", "))
+	_, err := exec(query, args...)
+	return err
+}
+
+// profileRowValues 書き出したプロフィールの1行の列名と値
+// （このデータベースにある列だけを使い、ユーザーIDは読み込み先のプロフィールに置き換える）
+func profileRowValues(table string, columns []string, row map[string]any, userID string) ([]string, []any) {
+	var names []string
+	var args []any
+	for _, column := range columns {
+		value, exists := row[column]
+		if !exists {
+			continue
+		}
+		if column == "user_id" || table == "users" && column == "id" {
+			value = userID
+		}
+		if number, ok := value.(json.Number); ok {
+			if n, err := number.Int64(); err == nil {
+				value = n
+			} else if f, err := number.Float64(); err == nil {
+				value = f
+			}
+		}
+		names = append(names, column)
+		args = append(args, value)
+	}
+	return names, args
+}
+
+// GetCachedResponse 保存したAIの応答を取得（保存していなければ空）
+func (db *DB) GetCachedResponse(key string) (string, time.Time, error) {
+	var response string
+	var expiresAt time.Time
+	err := db.QueryRow(`SELECT response, expires_at FROM ai_response_cache WHERE cache_key = ?`, key).Scan(&response, &expiresAt)
+	if err == sql.ErrNoRows {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("AIの応答取得エラー: %w", err)
+	}
+	return response, expiresAt, nil
+}
+
+// SaveCachedResponse AIの応答を保存（同じキーの応答は置き換える）
+func (db *DB) SaveCachedResponse(key, kind, response string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO ai_response_cache (cache_key, kind, response, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(cache_key) DO UPDATE SET
+			response = excluded.response, created_at = excluded.created_at, expires_at = excluded.expires_at
+	`
+	_, err := db.Exec(query, key, kind, response, time.Now(), expiresAt)
+	return err
+}
+
+// DeleteCachedResponse 保存したAIの応答を削除
+func (db *DB) DeleteCachedResponse(key string) error {
+	_, err := db.Exec(`DELETE FROM ai_response_cache WHERE cache_key = ?`, key)
+	return err
+}
+
+// PruneCachedResponses 保存期間がbefore より前に過ぎたAIの応答を削除し、削除した件数を返す
+func (db *DB) PruneCachedResponses(before time.Time) (int64, error) {
+	result, err := db.Exec(`DELETE FROM ai_response_cache WHERE expires_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("AIの応答削除エラー: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// AIMetricSummary モデルごとの生成の速さの集計
+type AIMetricSummary struct {
+	Model         string        `json:"model"`
+	Calls         int           `json:"calls"`
+	PromptTokens  int           `json:"prompt_tokens"`
+	OutputTokens  int           `json:"output_tokens"`
+	TotalDuration time.Duration `json:"total_duration"` // 生成全体の時間の合計
+	EvalDuration  time.Duration `json:"eval_duration"`  // 応答の生成にかかった時間の合計
+}
+
+// AverageLatency 1回の生成にかかった平均の時間
+func (s AIMetricSummary) AverageLatency() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.TotalDuration / time.Duration(s.Calls)
+}
+
+// TokensPerSecond 1秒あたりに生成したトークン数（計測できていなければ0）
+func (s AIMetricSummary) TokensPerSecond() float64 {
+	if s.EvalDuration <= 0 {
+		return 0
+	}
+	return float64(s.OutputTokens) / s.EvalDuration.Seconds()
+}
+
+// RecordAIMetric AIの1回の生成のトークン数と時間を記録
+func (db *DB) RecordAIMetric(model string, promptTokens, outputTokens int, totalDuration, evalDuration time.Duration) error {
+	query := `
+		INSERT INTO ai_metrics (model, prompt_tokens, output_tokens, total_ms, eval_ms, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := db.exec(query, model, promptTokens, outputTokens,
+		totalDuration.Milliseconds(), evalDuration.Milliseconds(), time.Now())
+	if err != nil {
+		return fmt.Errorf("AIの計測記録エラー: %w", err)
+	}
+	return nil
+}
+
+// GetAIMetricSummaries since以降のAIの生成を、モデルごとに集計（生成した回数の多い順）
+func (db *DB) GetAIMetricSummaries(since time.Time) ([]AIMetricSummary, error) {
+	query := `
+		SELECT model, COUNT(*), SUM(prompt_tokens), SUM(output_tokens), SUM(total_ms), SUM(eval_ms)
+		FROM ai_metrics
+		WHERE created_at >= ?
+		GROUP BY model
+		ORDER BY COUNT(*) DESC, model ASC
+	`
+	rows, err := db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("AIの計測読み込みエラー: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var summaries []AIMetricSummary
+	for rows.Next() {
+		var s AIMetricSummary
+		var totalMS, evalMS int64
+		if err := rows.Scan(&s.Model, &s.Calls, &s.PromptTokens, &s.OutputTokens, &totalMS, &evalMS); err != nil {
+			return nil, fmt.Errorf("AIの計測読み込みエラー: %w", err)
+		}
+		s.TotalDuration = time.Duration(totalMS) * time.Millisecond
+		s.EvalDuration = time.Duration(evalMS) * time.Millisecond
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
+
+// ModelOutput AIのモデルが作った問題の文章（問題文と解説）
+type ModelOutput struct {
+	Model       string `json:"model"`
+	Description string `json:"description"`
+	Explanation string `json:"explanation"`
+}
+
+// GetRecentModelOutputs since以降にAIが作った問題の文章を、モデルごとに新しい順にperModel件まで取得（全プロフィール）
+func (db *DB) GetRecentModelOutputs(since time.Time, perModel int) ([]ModelOutput, error) {
+	query := `
+		SELECT model, COALESCE(problem_content, ''), explanation
+		FROM problem_results
+		WHERE model != '' AND created_at >= ?
+		ORDER BY created_at DESC
+	`
+	rows, err := db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("AIが作った問題の取得エラー: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var outputs []ModelOutput
+	counts := make(map[string]int)
+	for rows.Next() {
+		var o ModelOutput
+		if err := rows.Scan(&o.Model, &o.Description, &o.Explanation); err != nil {
+			return nil, fmt.Errorf("AIが作った問題の取得エラー: %w", err)
+		}
+		if counts[o.Model] >= perModel {
+			continue
+		}
+		counts[o.Model]++
+		outputs = append(outputs, o)
+	}
+	return outputs, rows.Err()
+}
+
+// PruneAIMetrics beforeより前のAIの計測を削除し、削除した件数を返す
+func (db *DB) PruneAIMetrics(before time.Time) (int64, error) {
+	result, err := db.Exec(`DELETE FROM ai_metrics WHERE created_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("AIの計測削除エラー: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 // Cleanup データベース接続を閉じる
 func (db *DB) Cleanup() error {
 	return db.Close()
@@ -0,0 +1,208 @@
+package database_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+func TestCreateExamSession(t *testing.T) {
+	db := testutil.NewDB(t)
+	createMembers(t, db, "student")
+
+	session := &database.StudySession{
+		ID: "exam-1", UserID: "student", Subject: "数学",
+		StartTime: time.Date(2026, 5, 1, 10, 0, 0, 0, time.Local), SessionType: database.SessionTypeExam,
+	}
+	if err := db.CreateStudySession(session); err != nil {
+		t.Fatalf("模擬テストのセッションを作れない: %v", err)
+	}
+	got, err := db.GetStudySession("exam-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsExam() {
+		t.Errorf("セッション種別 = %s, want %s", got.SessionType, database.SessionTypeExam)
+	}
+}
+
+// 模擬テストを追加する前の学習セッションテーブル
+const oldStudySessionsTable = `
+CREATE TABLE users (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    grade INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_login DATETIME
+);
+CREATE TABLE study_sessions (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    start_time DATETIME NOT NULL,
+    end_time DATETIME,
+    total_problems INTEGER DEFAULT 0,
+    correct_answers INTEGER DEFAULT 0,
+    average_emotion TEXT DEFAULT 'neutral',
+    session_type TEXT NOT NULL DEFAULT 'app',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT valid_session_type CHECK (session_type IN ('app', 'manual'))
+);
+INSERT INTO users (id, name, grade) VALUES ('student', 'student', 2);
+INSERT INTO study_sessions (id, user_id, subject, start_time, total_problems, session_type)
+VALUES ('old-1', 'student', '英語', '2026-04-01 10:00:00', 7, 'manual');
+`
+
+func TestMigrateSessionTypeCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "studybuddy.db")
+	old, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.Exec(oldStudySessionsTable); err != nil {
+		t.Fatal(err)
+	}
+	_ = old.Close()
+
+	db, err := database.Initialize(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// これまでの記録は残り、模擬テストも記録できる
+	kept, err := db.GetStudySession("old-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !kept.IsManual() || kept.TotalProblems != 7 || kept.Subject != "英語" {
+		t.Errorf("作り直したあとのセッション = %+v", kept)
+	}
+	session := &database.StudySession{
+		ID: "exam-1", UserID: "student", Subject: "数学",
+		StartTime: time.Date(2026, 5, 1, 10, 0, 0, 0, time.Local), SessionType: database.SessionTypeExam,
+	}
+	if err := db.CreateStudySession(session); err != nil {
+		t.Fatalf("古い制約のデータベースで模擬テストのセッションを作れない: %v", err)
+	}
+	result := &database.ProblemResult{ID: "result-1", SessionID: "exam-1", ProblemType: "計算", Difficulty: 2, AnswerPosition: -1}
+	if err := db.CreateProblemResult(result); err != nil {
+		t.Fatalf("作り直したテーブルへの外部キー: %v", err)
+	}
+
+	// 2回目に開いたときは作り直さない
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	db, err = database.Initialize(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.GetStudySession("exam-1"); err != nil {
+		t.Error(err)
+	}
+}
+
+// roundTripProfile プロフィールのファイルと同じようにJSONにして読み戻す
+func roundTripProfile(t *testing.T, data *database.ProfileData) *database.ProfileData {
+	t.Helper()
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var decoded database.ProfileData
+	if err := decoder.Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	return &decoded
+}
+
+func TestProfileRoundTripWithForeignKeys(t *testing.T) {
+	now := time.Now()
+	db := testutil.NewDB(t)
+	user := testutil.Seed(t, db, testutil.DefaultFixture(now))
+	// 含めないテーブルがプロフィールを参照していても、読み込める
+	if err := db.AddCloudDailyUsage(user.ID, now.Format("2006-01-02"), 120); err != nil {
+		t.Fatal(err)
+	}
+
+	exported, err := db.ExportProfile(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := roundTripProfile(t, exported)
+
+	// 同じパソコンの同じプロフィールに読み戻す
+	if err := db.ImportProfile(user.ID, data); err != nil {
+		t.Fatalf("同じプロフィールへの読み込み: %v", err)
+	}
+	sessions, err := db.GetRecentStudySessions(user.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 3 {
+		t.Errorf("読み込んだセッション数 = %d, want 3", len(sessions))
+	}
+	if requests, tokens, err := db.GetCloudDailyUsage(user.ID, now.Format("2006-01-02")); err != nil || requests != 1 || tokens != 120 {
+		t.Errorf("クラウドAIの使用量 = %d回 %dトークン (%v)", requests, tokens, err)
+	}
+
+	// 別のパソコンの別のプロフィールに読み込む
+	other := testutil.NewDB(t)
+	createMembers(t, other, "other")
+	if err := other.ImportProfile("other", data); err != nil {
+		t.Fatalf("別のプロフィールへの読み込み: %v", err)
+	}
+	imported, err := other.GetUser("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Name != user.Name || imported.Grade != user.Grade {
+		t.Errorf("読み込んだプロフィール = %+v", imported)
+	}
+	results, err := other.GetProblemResultsBySession("user-test-session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Errorf("読み込んだ解答数 = %d, want 3", len(results))
+	}
+
+	// まだないプロフィールにも読み込める
+	fresh := testutil.NewDB(t)
+	if err := fresh.ImportProfile("fresh", data); err != nil {
+		t.Fatalf("新しいプロフィールへの読み込み: %v", err)
+	}
+	if _, err := fresh.GetUser("fresh"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSaveStudyTipSkipsDuplicates(t *testing.T) {
+	db := testutil.NewDB(t)
+	createMembers(t, db, "student")
+
+	now := time.Date(2026, 5, 1, 10, 0, 0, 0, time.Local)
+	for i, id := range []string{"tip-1", "tip-2"} {
+		tip := &database.StudyTip{ID: id, UserID: "student", Subject: "数学", Tip: "図をかいてから式を立てる", CreatedAt: now.Add(time.Duration(i) * time.Minute)}
+		if err := db.SaveStudyTip(tip); err != nil {
+			t.Fatalf("同じコツの保存はエラーにしない: %v", err)
+		}
+	}
+	tips, err := db.GetRecentStudyTips("student", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tips) != 1 || tips[0].ID != "tip-1" {
+		t.Errorf("保存したコツ = %+v", tips)
+	}
+}
@@ -0,0 +1,168 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 使えるデータベース
+const (
+	DriverSQLite   = "sqlite"   // 1台のパソコンで使う（既定）
+	DriverPostgres = "postgres" // 教室のサーバーで多くの生徒が共有する
+)
+
+// dialect データベースごとのSQLの違い
+// （SQLはSQLiteの書き方で書き、PostgreSQLではプレースホルダーと型を置き換える）
+type dialect struct {
+	name       string
+	driverName string // database/sqlのドライバー名
+}
+
+var (
+	sqliteDialect   = dialect{name: DriverSQLite, driverName: "sqlite3"}
+	postgresDialect = dialect{name: DriverPostgres, driverName: "pgx"}
+)
+
+// sqliteOptions SQLiteの接続ごとの設定（go-sqlite3の接続文字列の引数。接続を開くたびに設定される）
+// WALで学習画面の書き込み中も別の画面から読めるようにし、ほかの接続が書き込み中ならエラーにせず5秒まで待つ
+const sqliteOptions = "_journal_mode=WAL&_synchronous=NORMAL&_foreign_keys=on&_busy_timeout=5000"
+
+// sqliteDSN SQLiteのファイルのパスに接続ごとの設定を加える（すでに引数があればそのあとに続ける）
+func sqliteDSN(path string) string {
+	if strings.Contains(path, "?") {
+		return path + "&" + sqliteOptions
+	}
+	return path + "?" + sqliteOptions
+}
+
+// dialectFor 設定のドライバー名に合うdialect（空ならSQLite）
+func dialectFor(driver string) (dialect, error) {
+	switch driver {
+	case "", DriverSQLite:
+		return sqliteDialect, nil
+	case DriverPostgres:
+		return postgresDialect, nil
+	default:
+		return dialect{}, fmt.Errorf("未対応のデータベース: %s", driver)
+	}
+}
+
+// postgresTypes PostgreSQLにない型の置き換え
+var postgresTypes = map[string]string{"DATETIME": "TIMESTAMPTZ", "REAL": "DOUBLE PRECISION"}
+
+// postgresTypeWords 置き換える型名（列名などの一部は置き換えない）
+var postgresTypeWords = regexp.MustCompile(`\b(DATETIME|REAL)\b`)
+
+// schema テーブル作成SQLをこのデータベースの型に合わせる
+func (d dialect) schema(ddl string) string {
+	if d.name != DriverPostgres {
+		return ddl
+	}
+	return postgresTypeWords.ReplaceAllStringFunc(ddl, func(t string) string { return postgresTypes[t] })
+}
+
+// rebind プレースホルダー（?）をこのデータベースの書き方にする（PostgreSQLでは $1, $2, ...）
+func (d dialect) rebind(query string) string {
+	if d.name != DriverPostgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == '?' && !quoted:
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// localDate 日時の列の日付（"2006-01-02"。保存した時刻の表記・接続のタイムゾーンのまま）
+func (d dialect) localDate(column string) string {
+	if d.name == DriverPostgres {
+		return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD')", column)
+	}
+	return fmt.Sprintf("substr(%s, 1, 10)", column)
+}
+
+// secondsBetween 2つの日時の列の差（秒。どちらかがNULLならNULL）
+func (d dialect) secondsBetween(from, to string) string {
+	if d.name == DriverPostgres {
+		return fmt.Sprintf("CAST(ROUND(EXTRACT(EPOCH FROM (%s - %s))) AS INTEGER)", to, from)
+	}
+	return fmt.Sprintf("CAST(ROUND((julianday(%s) - julianday(%s)) * 86400) AS INTEGER)", to, from)
+}
+
+// columnsQuery テーブルの列名を返すSQL
+func (d dialect) columnsQuery(table string) string {
+	if d.name == DriverPostgres {
+		return fmt.Sprintf("SELECT column_name FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = '%s' ORDER BY ordinal_position", table)
+	}
+	return fmt.Sprintf("SELECT name FROM pragma_table_info('%s')", table)
+}
+
+// queryer 列名の問い合わせに使う接続（データベースまたはトランザクション）
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// tableColumns テーブルの列名（作成した順）
+func (d dialect) tableColumns(q queryer, table string) ([]string, error) {
+	rows, err := q.Query(d.columnsQuery(table))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+	return columns, rows.Err()
+}
+
+// txExec トランザクションで、プレースホルダーをこのデータベースの書き方にして実行
+func (d dialect) txExec(tx *sql.Tx) execFunc {
+	return func(query string, args ...any) (sql.Result, error) {
+		return tx.Exec(d.rebind(query), args...)
+	}
+}
+
+// Exec プレースホルダーをこのデータベースの書き方にして実行
+func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
+	return db.DB.Exec(db.dialect.rebind(query), args...)
+}
+
+// Query プレースホルダーをこのデータベースの書き方にして問い合わせ
+func (db *DB) Query(query string, args ...any) (*sql.Rows, error) {
+	return db.DB.Query(db.dialect.rebind(query), args...)
+}
+
+// QueryRow プレースホルダーをこのデータベースの書き方にして1行を問い合わせ
+func (db *DB) QueryRow(query string, args ...any) *sql.Row {
+	return db.DB.QueryRow(db.dialect.rebind(query), args...)
+}
+
+// Prepare プレースホルダーをこのデータベースの書き方にして文を準備
+func (db *DB) Prepare(query string) (*sql.Stmt, error) {
+	return db.DB.Prepare(db.dialect.rebind(query))
+}
+
+// Driver 使っているデータベース（DriverSQLite・DriverPostgres）
+func (db *DB) Driver() string {
+	return db.dialect.name
+}
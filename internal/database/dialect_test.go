@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostgresRebind(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT * FROM users WHERE id = ?", "SELECT * FROM users WHERE id = $1"},
+		{"INSERT INTO t (a, b) VALUES (?, ?)", "INSERT INTO t (a, b) VALUES ($1, $2)"},
+		{"SELECT '?' FROM t WHERE a = ? AND b != ''", "SELECT '?' FROM t WHERE a = $1 AND b != ''"},
+		{"SELECT 1", "SELECT 1"},
+	}
+	for _, tt := range tests {
+		if got := postgresDialect.rebind(tt.query); got != tt.want {
+			t.Errorf("rebind(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+		if got := sqliteDialect.rebind(tt.query); got != tt.query {
+			t.Errorf("SQLiteでは置き換えないはず: %q", got)
+		}
+	}
+}
+
+func TestPostgresSchema(t *testing.T) {
+	schemas := []string{
+		createUsersTable, createStudySessionsTable, createProblemResultsTable, createSessionFocusTable,
+		createFlashcardsTable, createAIResponseCacheTable, createAIMetricsTable, createIndices,
+	}
+	for _, schema := range schemas {
+		ddl := postgresDialect.schema(schema)
+		if strings.Contains(ddl, "DATETIME") || strings.Contains(ddl, " REAL") {
+			t.Errorf("PostgreSQLにない型が残っている:\n%s", ddl)
+		}
+		if sqliteDialect.schema(schema) != schema {
+			t.Error("SQLiteでは置き換えないはず")
+		}
+	}
+	if ddl := postgresDialect.schema(createSessionFocusTable); !strings.Contains(ddl, "answer_time_cv DOUBLE PRECISION") {
+		t.Errorf("REALがDOUBLE PRECISIONにならない:\n%s", ddl)
+	}
+}
+
+func TestOpenRejectsUnknownDriver(t *testing.T) {
+	if _, err := Open("mysql", "studybuddy"); err == nil {
+		t.Error("未対応のデータベースはエラーになるはず")
+	}
+}
+
+func TestSQLiteConnectionOptions(t *testing.T) {
+	if got := sqliteDSN("file:test?mode=memory&cache=shared"); got != "file:test?mode=memory&cache=shared&"+sqliteOptions {
+		t.Errorf("sqliteDSN = %q", got)
+	}
+
+	db, err := Initialize(filepath.Join(t.TempDir(), "studybuddy.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// 接続ごとの設定なので、同時に使う別の接続でも同じになる
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	var journalMode string
+	var foreignKeys, busyTimeout int
+	for _, pragma := range []struct {
+		name string
+		dest any
+	}{{"journal_mode", &journalMode}, {"foreign_keys", &foreignKeys}, {"busy_timeout", &busyTimeout}} {
+		if err := conn.QueryRowContext(context.Background(), "PRAGMA "+pragma.name).Scan(pragma.dest); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if journalMode != "wal" || foreignKeys != 1 || busyTimeout != 5000 {
+		t.Errorf("journal_mode=%s foreign_keys=%d busy_timeout=%d", journalMode, foreignKeys, busyTimeout)
+	}
+
+	// 存在しないセッションの解答は保存しない
+	if err := db.CreateProblemResult(&ProblemResult{ID: "orphan", SessionID: "missing", CreatedAt: time.Now()}); err == nil {
+		t.Error("存在しないセッションの解答を保存しました")
+	}
+}
@@ -0,0 +1,6 @@
+//go:build postgres
+
+package database
+
+// PostgreSQLのドライバー（教室のサーバーで使うときだけ -tags postgres で組み込む）
+import _ "github.com/jackc/pgx/v5/stdlib"
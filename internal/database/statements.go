@@ -0,0 +1,150 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// stmtCache 準備済みの文（同じSQLを毎回解析しないよう、SQLごとに1度だけ準備する）
+type stmtCache struct {
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+}
+
+// execFunc 文の実行（準備済みの文を、データベースまたはトランザクションで実行する）
+type execFunc func(query string, args ...any) (sql.Result, error)
+
+// prepared queryの準備済みの文（初めて使うときに準備する）
+func (db *DB) prepared(query string) (*sql.Stmt, error) {
+	db.stmts.mu.Lock()
+	defer db.stmts.mu.Unlock()
+
+	if stmt, ok := db.stmts.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return nil, fmt.Errorf("SQL準備エラー: %w", err)
+	}
+	if db.stmts.stmts == nil {
+		db.stmts.stmts = make(map[string]*sql.Stmt)
+	}
+	db.stmts.stmts[query] = stmt
+	return stmt, nil
+}
+
+// exec 準備済みの文で実行（解答ごとに何度も実行する書き込みに使う）
+func (db *DB) exec(query string, args ...any) (sql.Result, error) {
+	stmt, err := db.prepared(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Exec(args...)
+}
+
+// query 準備済みの文で問い合わせ（画面を開くたびに何度も実行する読み込みに使う）
+func (db *DB) query(query string, args ...any) (*sql.Rows, error) {
+	stmt, err := db.prepared(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Query(args...)
+}
+
+// inTx 1つのトランザクションでまとめて書き込む（ディスクへの書き出しが1回で済む）
+func (db *DB) inTx(fn func(exec execFunc) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクション開始エラー: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	txStmts := make(map[string]*sql.Stmt)
+	exec := func(query string, args ...any) (sql.Result, error) {
+		stmt, ok := txStmts[query]
+		if !ok {
+			prepared, err := db.prepared(query)
+			if err != nil {
+				return nil, err
+			}
+			stmt = tx.Stmt(prepared)
+			txStmts[query] = stmt
+		}
+		return stmt.Exec(args...)
+	}
+	if err := fn(exec); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクション確定エラー: %w", err)
+	}
+	return nil
+}
+
+// Close 準備済みの文を閉じてから、データベースを閉じる
+func (db *DB) Close() error {
+	db.stmts.mu.Lock()
+	var errs []error
+	for _, stmt := range db.stmts.stmts {
+		errs = append(errs, stmt.Close())
+	}
+	db.stmts.stmts = nil
+	db.stmts.mu.Unlock()
+
+	errs = append(errs, db.DB.Close())
+	return errors.Join(errs...)
+}
+
+// AnswerRecord 1問分の解答の記録（解答結果と、単元別の解答統計への加算）
+type AnswerRecord struct {
+	Result     *ProblemResult
+	Subject    string    // 単元別の解答統計に加える科目（空なら加えない。単元は Result.ProblemType）
+	AnsweredAt time.Time // 単元別の解答統計の最終学習日時
+}
+
+// RecordAnswers 解答の記録を1つのトランザクションでまとめて保存
+func (db *DB) RecordAnswers(userID string, records []AnswerRecord) error {
+	return db.inTx(func(exec execFunc) error {
+		for _, record := range records {
+			if err := insertProblemResult(exec, record.Result); err != nil {
+				return fmt.Errorf("結果保存エラー: %w", err)
+			}
+			if record.Subject == "" || record.Result.ProblemType == "" {
+				continue
+			}
+			err := addTopicResult(exec, userID, record.Subject, record.Result.ProblemType, record.Result.IsCorrect, record.AnsweredAt)
+			if err != nil {
+				return fmt.Errorf("単元別習熟度更新エラー: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// CreateXPEvents 経験値の獲得を1つのトランザクションでまとめて記録
+func (db *DB) CreateXPEvents(events []*XPEvent) error {
+	return db.inTx(func(exec execFunc) error {
+		for _, event := range events {
+			if err := insertXPEvent(exec, event); err != nil {
+				return fmt.Errorf("経験値記録エラー: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// RescheduleFlashcards 単語カードの次の復習日を1つのトランザクションでまとめて変更
+func (db *DB) RescheduleFlashcards(cards []Flashcard) error {
+	return db.inTx(func(exec execFunc) error {
+		for _, card := range cards {
+			if _, err := exec(`UPDATE flashcards SET due_at = ? WHERE id = ?`, card.DueAt, card.ID); err != nil {
+				return fmt.Errorf("復習日変更エラー: %w", err)
+			}
+		}
+		return nil
+	})
+}
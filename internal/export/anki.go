@@ -0,0 +1,349 @@
+package export
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// ankiDeckPrefix Ankiに書き出すデッキ名の親デッキ
+const ankiDeckPrefix = "StudyBuddy AI::"
+
+// AnkiCard Ankiに書き出すカード（表面・裏面はプレーンテキスト）
+type AnkiCard struct {
+	Front string
+	Back  string
+	Tags  []string
+
+	// 復習スケジュール（Repetitionsが0なら新しいカードとして書き出す）
+	Repetitions  int
+	IntervalDays int
+	EaseFactor   float64
+	DueAt        time.Time
+}
+
+// AnkiDeck Ankiに書き出すデッキ
+type AnkiDeck struct {
+	Name  string
+	Cards []AnkiCard
+}
+
+// FlashcardAnkiDeck 単語カードのデッキをAnki用に変換（復習スケジュールも引き継ぐ）
+func FlashcardAnkiDeck(name string, cards []database.Flashcard) AnkiDeck {
+	deck := AnkiDeck{Name: name}
+	for _, card := range cards {
+		back := card.Back
+		if card.Hint != "" {
+			back += "\n\n" + card.Hint
+		}
+		deck.Cards = append(deck.Cards, AnkiCard{
+			Front:        card.Front,
+			Back:         back,
+			Tags:         []string{name},
+			Repetitions:  card.Repetitions,
+			IntervalDays: card.IntervalDays,
+			EaseFactor:   card.EaseFactor,
+			DueAt:        card.DueAt,
+		})
+	}
+	return deck
+}
+
+// MissedProblemsAnkiDeck 間違えた問題をAnki用のデッキに変換（表面が問題文、裏面が正解）
+func MissedProblemsAnkiDeck(results []database.Mistake) AnkiDeck {
+	deck := AnkiDeck{Name: "間違えた問題"}
+	for _, result := range results {
+		if result.ProblemContent == "" {
+			continue
+		}
+		back := "正解: " + result.CorrectAnswer
+		if result.UserAnswer != "" {
+			back += "\n（あなたの解答: " + result.UserAnswer + "）"
+		}
+		tags := []string{result.Subject}
+		if result.ProblemType != "" {
+			tags = append(tags, result.ProblemType)
+		}
+		deck.Cards = append(deck.Cards, AnkiCard{
+			Front: result.ProblemContent,
+			Back:  back,
+			Tags:  tags,
+		})
+	}
+	return deck
+}
+
+// WriteAnkiPackage デッキをAnkiで読み込める.apkg形式で出力
+func WriteAnkiPackage(w io.Writer, decks []AnkiDeck) error {
+	dir, err := os.MkdirTemp("", "studybuddy-anki-")
+	if err != nil {
+		return fmt.Errorf("一時ディレクトリ作成エラー: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	collectionPath := filepath.Join(dir, "collection.anki2")
+	if err := writeAnkiCollection(collectionPath, decks, time.Now()); err != nil {
+		return err
+	}
+	collection, err := os.ReadFile(collectionPath)
+	if err != nil {
+		return fmt.Errorf("Ankiコレクション読み込みエラー: %w", err)
+	}
+
+	archive := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"collection.anki2", collection},
+		{"media", []byte("{}")},
+	}
+	for _, file := range files {
+		fw, err := archive.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("apkg作成エラー: %w", err)
+		}
+		if _, err := fw.Write(file.data); err != nil {
+			return fmt.Errorf("apkg書き込みエラー: %w", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("apkg書き込みエラー: %w", err)
+	}
+	return nil
+}
+
+// ankiSchema Ankiコレクション（スキーマバージョン11）のテーブル
+const ankiSchema = `
+CREATE TABLE col (
+	id INTEGER PRIMARY KEY, crt INTEGER NOT NULL, mod INTEGER NOT NULL, scm INTEGER NOT NULL,
+	ver INTEGER NOT NULL, dty INTEGER NOT NULL, usn INTEGER NOT NULL, ls INTEGER NOT NULL,
+	conf TEXT NOT NULL, models TEXT NOT NULL, decks TEXT NOT NULL, dconf TEXT NOT NULL, tags TEXT NOT NULL
+);
+CREATE TABLE notes (
+	id INTEGER PRIMARY KEY, guid TEXT NOT NULL, mid INTEGER NOT NULL, mod INTEGER NOT NULL,
+	usn INTEGER NOT NULL, tags TEXT NOT NULL, flds TEXT NOT NULL, sfld INTEGER NOT NULL,
+	csum INTEGER NOT NULL, flags INTEGER NOT NULL, data TEXT NOT NULL
+);
+CREATE TABLE cards (
+	id INTEGER PRIMARY KEY, nid INTEGER NOT NULL, did INTEGER NOT NULL, ord INTEGER NOT NULL,
+	mod INTEGER NOT NULL, usn INTEGER NOT NULL, type INTEGER NOT NULL, queue INTEGER NOT NULL,
+	due INTEGER NOT NULL, ivl INTEGER NOT NULL, factor INTEGER NOT NULL, reps INTEGER NOT NULL,
+	lapses INTEGER NOT NULL, left INTEGER NOT NULL, odue INTEGER NOT NULL, odid INTEGER NOT NULL,
+	flags INTEGER NOT NULL, data TEXT NOT NULL
+);
+CREATE TABLE revlog (
+	id INTEGER PRIMARY KEY, cid INTEGER NOT NULL, usn INTEGER NOT NULL, ease INTEGER NOT NULL,
+	ivl INTEGER NOT NULL, lastIvl INTEGER NOT NULL, factor INTEGER NOT NULL, time INTEGER NOT NULL,
+	type INTEGER NOT NULL
+);
+CREATE TABLE graves (usn INTEGER NOT NULL, oid INTEGER NOT NULL, type INTEGER NOT NULL);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+// ankiModelID 表面・裏面の2フィールドのノートタイプのID（読み込み先で同じノートタイプにまとまるよう固定）
+const ankiModelID = 1718000000000
+
+// writeAnkiCollection Ankiコレクションのデータベースを作成
+func writeAnkiCollection(path string, decks []AnkiDeck, now time.Time) error {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("Ankiコレクション作成エラー: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if _, err := db.Exec(ankiSchema); err != nil {
+		return fmt.Errorf("Ankiコレクション作成エラー: %w", err)
+	}
+
+	// 復習日は作成日（crt）からの日数で表す
+	crt := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	mod := now.UnixMilli()
+
+	deckJSON := map[string]any{"1": ankiDeckJSON(1, "Default", mod)}
+	for i := range decks {
+		deckID := mod + int64(i) + 1
+		deckJSON[fmt.Sprint(deckID)] = ankiDeckJSON(deckID, ankiDeckPrefix+decks[i].Name, mod)
+	}
+	models := map[string]any{fmt.Sprint(ankiModelID): ankiModelJSON(mod)}
+
+	var jsonErr error
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			jsonErr = err
+		}
+		return string(data)
+	}
+	colValues := []any{
+		crt.Unix(), mod, mod, 11, 0, 0, 0,
+		encode(ankiCollectionConf()), encode(models), encode(deckJSON), encode(ankiDeckConf()), "{}",
+	}
+	if jsonErr != nil {
+		return fmt.Errorf("Ankiコレクション作成エラー: %w", jsonErr)
+	}
+	_, err = db.Exec(`INSERT INTO col (id, crt, mod, scm, ver, dty, usn, ls, conf, models, decks, dconf, tags)
+		VALUES (1, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, colValues...)
+	if err != nil {
+		return fmt.Errorf("Ankiコレクション作成エラー: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("Ankiカード書き込みエラー: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	id := mod     // ノートとカードのID（作成時刻のミリ秒から連番）
+	position := 0 // 新しいカードの出題順
+	for i, deck := range decks {
+		deckID := mod + int64(i) + 1
+		for _, card := range deck.Cards {
+			id++
+			position++
+			if err := insertAnkiCard(tx, id, deckID, deck.Name, card, crt, position, mod); err != nil {
+				return fmt.Errorf("Ankiカード書き込みエラー: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Ankiカード書き込みエラー: %w", err)
+	}
+	return nil
+}
+
+// insertAnkiCard ノートとカードを1枚追加
+func insertAnkiCard(tx *sql.Tx, id, deckID int64, deckName string, card AnkiCard, crt time.Time, position int, mod int64) error {
+	front := ankiField(card.Front)
+	fields := front + "\x1f" + ankiField(card.Back)
+	tags := ""
+	if len(card.Tags) > 0 {
+		tags = " " + strings.Join(ankiTags(card.Tags), " ") + " "
+	}
+
+	_, err := tx.Exec(`INSERT INTO notes (id, guid, mid, mod, usn, tags, flds, sfld, csum, flags, data)
+		VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')`,
+		id, ankiGUID(deckName, card.Front), ankiModelID, mod/1000, tags, fields, card.Front, ankiChecksum(card.Front))
+	if err != nil {
+		return err
+	}
+
+	// 新しいカード（type=0, queue=0）は出題順、復習中のカード（type=2, queue=2）は作成日からの日数
+	cardType, due, interval, factor := 0, position, 0, 2500
+	if card.Repetitions > 0 {
+		cardType = 2
+		due = max(int(card.DueAt.Sub(crt).Hours()/24), 0)
+		interval = max(card.IntervalDays, 1)
+		factor = int(card.EaseFactor * 1000)
+	}
+	_, err = tx.Exec(`INSERT INTO cards (id, nid, did, ord, mod, usn, type, queue, due, ivl, factor, reps,
+			lapses, left, odue, odid, flags, data)
+		VALUES (?, ?, ?, 0, ?, -1, ?, ?, ?, ?, ?, ?, 0, 0, 0, 0, 0, '')`,
+		id, id, deckID, mod/1000, cardType, cardType, due, interval, factor, card.Repetitions)
+	return err
+}
+
+// ankiField テキストをAnkiのフィールド（HTML）に変換
+func ankiField(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
+
+// ankiTags タグの空白を「_」に置き換え（Ankiのタグは空白区切り）
+func ankiTags(tags []string) []string {
+	result := make([]string, len(tags))
+	for i, tag := range tags {
+		result[i] = strings.Join(strings.Fields(tag), "_")
+	}
+	return result
+}
+
+// ankiGUID デッキ名と表面から決まるノートのGUID（同じカードを書き出し直しても重複しない）
+func ankiGUID(deckName, front string) string {
+	sum := sha1.Sum([]byte("studybuddy:" + deckName + ":" + front))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// ankiChecksum 並べ替えフィールドのチェックサム（SHA1の先頭32ビット）
+func ankiChecksum(text string) int64 {
+	sum := sha1.Sum([]byte(text))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
+
+// ankiDeckJSON デッキの設定
+func ankiDeckJSON(id int64, name string, mod int64) map[string]any {
+	return map[string]any{
+		"id": id, "name": name, "mod": mod / 1000, "usn": -1, "desc": "",
+		"dyn": 0, "conf": 1, "collapsed": false, "extendNew": 10, "extendRev": 50,
+		"newToday": []int{0, 0}, "revToday": []int{0, 0}, "lrnToday": []int{0, 0}, "timeToday": []int{0, 0},
+	}
+}
+
+// ankiModelJSON 表面・裏面の2フィールドのノートタイプ
+func ankiModelJSON(mod int64) map[string]any {
+	field := func(name string, ord int) map[string]any {
+		return map[string]any{"name": name, "ord": ord, "sticky": false, "rtl": false, "font": "Arial", "size": 20, "media": []any{}}
+	}
+	return map[string]any{
+		"id": ankiModelID, "name": "StudyBuddy AI", "type": 0, "mod": mod / 1000, "usn": -1,
+		"sortf": 0, "did": 1, "tags": []any{}, "vers": []any{},
+		"flds": []any{field("Front", 0), field("Back", 1)},
+		"tmpls": []any{map[string]any{
+			"name": "Card 1", "ord": 0, "did": nil, "bqfmt": "", "bafmt": "",
+			"qfmt": "{{Front}}",
+			"afmt": "{{FrontSide}}<hr id=answer>{{Back}}",
+		}},
+		"css":       ".card { font-family: sans-serif; font-size: 24px; text-align: center; }",
+		"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"req":       []any{[]any{0, "all", []int{0}}},
+	}
+}
+
+// ankiCollectionConf コレクションの設定
+func ankiCollectionConf() map[string]any {
+	return map[string]any{
+		"nextPos": 1, "estTimes": true, "activeDecks": []int{1}, "sortType": "noteFld",
+		"timeLim": 0, "sortBackwards": false, "addToCur": true, "curDeck": 1,
+		"newBf": 0, "dueCounts": true, "curModel": fmt.Sprint(ankiModelID), "collapseTime": 1200,
+	}
+}
+
+// ankiDeckConf デッキの学習設定（Ankiの既定値）
+func ankiDeckConf() map[string]any {
+	return map[string]any{"1": map[string]any{
+		"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true,
+		"timer": 0, "replayq": true, "dyn": false,
+		"new": map[string]any{
+			"delays": []int{1, 10}, "ints": []int{1, 4, 7}, "initialFactor": 2500,
+			"separate": true, "order": 1, "perDay": 20, "bury": true,
+		},
+		"rev": map[string]any{
+			"perDay": 100, "ease4": 1.3, "fuzz": 0.05, "minSpace": 1, "ivlFct": 1,
+			"maxIvl": 36500, "bury": true,
+		},
+		"lapse": map[string]any{
+			"delays": []int{10}, "mult": 0, "minInt": 1, "leechFails": 8, "leechAction": 0,
+		},
+	}}
+}
@@ -0,0 +1,132 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 課題の取り組み表の記号
+const (
+	AttendanceDone    = "○" // その日の課題をすべて終えた
+	AttendancePartial = "△" // 取り組んだが、問題数に届いていない課題がある
+	AttendanceAbsent  = "×" // その日の課題に取り組まなかった
+	AttendanceNone    = "－" // 課題がない日
+)
+
+// AttendanceGrid クラスの課題の取り組み表（行が生徒、列が日付）
+type AttendanceGrid struct {
+	Dates    []string // 期間の日付（"2006-01-02"）
+	Students []database.User
+	marks    map[string]map[string]string // 生徒ID → 日付 → 記号
+}
+
+// NewAttendanceGrid 期間内の課題の取り組み状況から取り組み表を作る
+func NewAttendanceGrid(from time.Time, days int, students []database.User, progress []database.AssignmentProgress) *AttendanceGrid {
+	grid := &AttendanceGrid{Students: students, marks: make(map[string]map[string]string)}
+	for i := range days {
+		grid.Dates = append(grid.Dates, from.AddDate(0, 0, i).Format(time.DateOnly))
+	}
+
+	// 生徒・日付ごとに、配られた課題の数・終えた課題の数・少しでも取り組んだか
+	type dayStatus struct{ assigned, completed, solved int }
+	statuses := make(map[string]map[string]*dayStatus)
+	for _, p := range progress {
+		for _, studentID := range p.Assignment.StudentIDs {
+			if statuses[studentID] == nil {
+				statuses[studentID] = make(map[string]*dayStatus)
+			}
+			status := statuses[studentID][p.Assignment.DueDate]
+			if status == nil {
+				status = &dayStatus{}
+				statuses[studentID][p.Assignment.DueDate] = status
+			}
+			status.assigned++
+			status.solved += p.Solved[studentID]
+			if p.Completed(studentID) {
+				status.completed++
+			}
+		}
+	}
+
+	for studentID, days := range statuses {
+		grid.marks[studentID] = make(map[string]string)
+		for date, status := range days {
+			switch {
+			case status.completed == status.assigned:
+				grid.marks[studentID][date] = AttendanceDone
+			case status.solved > 0:
+				grid.marks[studentID][date] = AttendancePartial
+			default:
+				grid.marks[studentID][date] = AttendanceAbsent
+			}
+		}
+	}
+	return grid
+}
+
+// Mark 生徒のその日の記号（課題がない日はAttendanceNone）
+func (g *AttendanceGrid) Mark(userID, date string) string {
+	if mark, ok := g.marks[userID][date]; ok {
+		return mark
+	}
+	return AttendanceNone
+}
+
+// Completion 期間内に課題があった日のうち、課題をすべて終えた日数
+func (g *AttendanceGrid) Completion(userID string) (done, assigned int) {
+	for _, date := range g.Dates {
+		switch g.Mark(userID, date) {
+		case AttendanceDone:
+			done++
+			assigned++
+		case AttendancePartial, AttendanceAbsent:
+			assigned++
+		}
+	}
+	return done, assigned
+}
+
+// WriteAttendanceXLSX 課題の取り組み表をExcel形式（.xlsx）で出力（学校の出席簿に転記したり、印刷したりする用）
+func WriteAttendanceXLSX(w io.Writer, grid *AttendanceGrid) error {
+	if len(grid.Dates) == 0 {
+		return fmt.Errorf("出力する期間がありません")
+	}
+
+	last := xlsxColumn(len(grid.Dates) + 1)
+	xs := xlsxSheet{
+		Name: "課題の取り組み",
+		Rows: [][]xlsxCell{
+			{xlsxText("課題の取り組み表", xlsxStyleTitle)},
+			{xlsxText(fmt.Sprintf("%s〜%s　%s 課題をすべて終えた　%s 途中まで　%s 取り組まなかった　%s 課題なし",
+				grid.Dates[0], grid.Dates[len(grid.Dates)-1], AttendanceDone, AttendancePartial, AttendanceAbsent, AttendanceNone), xlsxStyleNormal)},
+			{},
+		},
+		Widths: []float64{16},
+		Merges: []string{"A1:" + last + "1", "A2:" + last + "2"},
+	}
+
+	header := []xlsxCell{xlsxText("名前", xlsxStyleHeader)}
+	for _, date := range grid.Dates {
+		day, _ := time.Parse(time.DateOnly, date)
+		header = append(header, xlsxText(day.Format("1/2"), xlsxStyleHeader))
+		xs.Widths = append(xs.Widths, 6)
+	}
+	header = append(header, xlsxText("終えた日", xlsxStyleHeader))
+	xs.Widths = append(xs.Widths, 10)
+	xs.Rows = append(xs.Rows, header)
+
+	for _, student := range grid.Students {
+		row := []xlsxCell{xlsxText(student.Name, xlsxStyleCell)}
+		for _, date := range grid.Dates {
+			row = append(row, xlsxText(grid.Mark(student.ID, date), xlsxStyleCell))
+		}
+		done, assigned := grid.Completion(student.ID)
+		row = append(row, xlsxText(fmt.Sprintf("%d/%d日", done, assigned), xlsxStyleCell))
+		xs.Rows = append(xs.Rows, row)
+	}
+
+	return writeXLSX(w, xs)
+}
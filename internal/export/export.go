@@ -0,0 +1,291 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/figure"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+)
+
+// figurePDFWidth 問題集の図の幅（ポイント）
+const figurePDFWidth = 240.0
+
+// Exporter 学習レポート・問題集のPDF出力
+type Exporter struct {
+	font *pdfFont
+}
+
+// NewExporter PDF出力システムを作成（日本語フォントを読み込み）
+func NewExporter() (*Exporter, error) {
+	fontPath, err := findFontPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(fontPath)
+	if err != nil {
+		return nil, fmt.Errorf("フォント読み込みエラー: %w", err)
+	}
+
+	f, err := newPDFFont(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Exporter{font: f}, nil
+}
+
+// findFontPath 埋め込み用の日本語フォントを探す（main.goのフォント設定と同じ候補）
+func findFontPath() (string, error) {
+	var candidates []string
+	if fontPath := os.Getenv("FYNE_FONT"); fontPath != "" {
+		candidates = append(candidates, fontPath)
+	}
+	if execPath, err := os.Executable(); err == nil {
+		candidates = append(candidates, filepath.Join(filepath.Dir(execPath), "assets", "fonts", "Mplus1-Regular.ttf"))
+	}
+	candidates = append(candidates, filepath.Join("assets", "fonts", "Mplus1-Regular.ttf"))
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("日本語フォントファイルが見つかりません")
+}
+
+// WriteAnalysisPDF 学習分析レポートをPDFで出力
+func (e *Exporter) WriteAnalysisPDF(w io.Writer, userName string, analysis *progress.LearningAnalysis) error {
+	doc := newPDFDocument(e.font)
+
+	doc.Paragraph("学習レポート", 22, true)
+	doc.Paragraph(fmt.Sprintf("%s さん　作成日: %s", userName, analysis.LastUpdated.Format("2006年01月02日")), 10, false)
+	doc.Rule()
+
+	if overall := analysis.OverallProgress; overall != nil {
+		doc.Heading("全体の進捗", 14)
+		doc.Paragraph(fmt.Sprintf("解答した問題: %d問（正解 %d問）", overall.TotalProblems, overall.TotalCorrect), 11, false)
+		doc.Paragraph(fmt.Sprintf("正解率: %.1f%%", overall.AccuracyRate*100), 11, false)
+		doc.Paragraph(fmt.Sprintf("学習時間: %d分　学習日数: %d日", overall.TotalStudyTime/60, overall.StudyDaysCount), 11, false)
+		if overall.ManualStudyTime > 0 {
+			doc.IndentedParagraph(fmt.Sprintf("うち塾・紙のドリルなどアプリ外の学習: %d分", overall.ManualStudyTime/60), 10, false, 16)
+		}
+		doc.Paragraph(fmt.Sprintf("レベル: %d（経験値 %d）", overall.CurrentLevel, overall.ExperiencePoints), 11, false)
+	}
+
+	if streak := analysis.StudyStreak; streak != nil {
+		doc.Heading("学習の継続", 14)
+		doc.Paragraph(fmt.Sprintf("連続学習: %d日（最長 %d日）", streak.CurrentStreak, streak.LongestStreak), 11, false)
+		doc.Paragraph(fmt.Sprintf("今週の学習日数: %d日　今月の学習日数: %d日", streak.StudyDaysThisWeek, streak.StudyDaysThisMonth), 11, false)
+	}
+
+	doc.Heading("科目別の進捗", 14)
+	for _, subject := range []string{"数学", "英語", "国語", "理科", "社会"} {
+		subjectAnalysis, exists := analysis.SubjectProgress[subject]
+		if !exists || subjectAnalysis.TotalProblems == 0 {
+			continue
+		}
+		doc.Paragraph(fmt.Sprintf("%s: %d問　正解率 %.1f%%　レベル %d　傾向 %s",
+			subject, subjectAnalysis.TotalProblems, subjectAnalysis.AccuracyRate*100,
+			subjectAnalysis.ProgressLevel, trendText(subjectAnalysis.RecentTrend)), 11, false)
+		if len(subjectAnalysis.StrengthAreas) > 0 {
+			doc.IndentedParagraph("得意: "+strings.Join(subjectAnalysis.StrengthAreas, "、"), 10, false, 16)
+		}
+		if len(subjectAnalysis.WeaknessAreas) > 0 {
+			doc.IndentedParagraph("苦手: "+strings.Join(subjectAnalysis.WeaknessAreas, "、"), 10, false, 16)
+		}
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		doc.Heading("おすすめの学習", 14)
+		for _, rec := range analysis.Recommendations {
+			doc.Paragraph("■ "+rec.Title, 11, true)
+			doc.IndentedParagraph(rec.Description, 10, false, 16)
+			for _, action := range rec.Actions {
+				doc.IndentedParagraph("・"+action, 10, false, 16)
+			}
+		}
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// WriteWeeklyReportPDF 週間レポートをPDFで出力
+func (e *Exporter) WriteWeeklyReportPDF(w io.Writer, userName string, report *progress.WeeklyReport) error {
+	doc := newPDFDocument(e.font)
+
+	doc.Paragraph("週間学習レポート", 22, true)
+	doc.Paragraph(fmt.Sprintf("%s さん　%s 〜 %s", userName,
+		report.WeekStart.Format("2006年01月02日"), report.WeekEnd.AddDate(0, 0, -1).Format("01月02日")), 10, false)
+	doc.Rule()
+
+	doc.Heading("今週の記録", 14)
+	doc.Paragraph(fmt.Sprintf("学習日数: %d日　学習時間: %d分", report.StudyDays, report.TotalStudyTime/60), 11, false)
+	if report.ManualTime > 0 {
+		doc.IndentedParagraph(fmt.Sprintf("うち塾・紙のドリルなどアプリ外の学習: %d分", report.ManualTime/60), 10, false, 16)
+	}
+	doc.Paragraph(fmt.Sprintf("解答数: %d問　正解率: %.1f%%", report.TotalProblems, report.AccuracyRate*100), 11, false)
+
+	doc.Heading("科目別", 14)
+	for _, subject := range []string{"数学", "英語", "国語", "理科", "社会"} {
+		stats, exists := report.SubjectStats[subject]
+		if !exists {
+			continue
+		}
+		doc.Paragraph(fmt.Sprintf("%s: %d回　%d問　正解率 %.1f%%　%d分",
+			subject, stats.Sessions, stats.TotalProblems, stats.AccuracyRate*100, stats.StudyTime/60), 11, false)
+	}
+
+	if summary := report.Summary; summary != nil {
+		doc.Heading("まとめ", 14)
+		doc.Paragraph(summary.Summary, 11, false)
+		doc.Heading("よくできたこと", 14)
+		doc.Paragraph(summary.Strengths, 11, false)
+		doc.Heading("課題", 14)
+		doc.Paragraph(summary.Weaknesses, 11, false)
+		doc.Heading("来週へのアドバイス", 14)
+		doc.Paragraph(summary.Advice, 11, false)
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// WriteProblemSetPDF 問題集（練習プリント）をPDFで出力（最終ページに解答）
+func (e *Exporter) WriteProblemSetPDF(w io.Writer, title string, problems []*ai.Problem) error {
+	if len(problems) == 0 {
+		return fmt.Errorf("出力する問題がありません")
+	}
+
+	doc := newPDFDocument(e.font)
+
+	doc.Paragraph(title, 20, true)
+	doc.Paragraph(fmt.Sprintf("作成日: %s　名前: ＿＿＿＿＿＿＿＿＿＿", time.Now().Format("2006年01月02日")), 10, false)
+	doc.Rule()
+
+	for i, problem := range problems {
+		doc.Heading(fmt.Sprintf("第%d問　%s", i+1, problem.Title), 13)
+		doc.Paragraph(problem.Description, 11, false)
+		if problem.Figure != nil {
+			doc.Space(4)
+			doc.Image(figure.Render(*problem.Figure), figurePDFWidth)
+			doc.Paragraph(problem.Figure.Caption, 9, false)
+		}
+		doc.Space(4)
+		for j, option := range problem.Options {
+			doc.IndentedParagraph(fmt.Sprintf("%d. %s", j+1, option), 11, false, 16)
+		}
+		doc.Space(8)
+	}
+
+	// 解答・解説ページ
+	doc.addPage()
+	doc.Paragraph("解答・解説", 18, true)
+	doc.Rule()
+	for i, problem := range problems {
+		answer := ""
+		if problem.CorrectAnswer >= 0 && problem.CorrectAnswer < len(problem.Options) {
+			answer = problem.Options[problem.CorrectAnswer]
+		}
+		doc.Paragraph(fmt.Sprintf("第%d問　正解: %d. %s", i+1, problem.CorrectAnswer+1, answer), 11, true)
+		if problem.Audio != "" {
+			doc.IndentedParagraph("読み上げる英文: "+problem.Audio, 10, false, 16)
+		}
+		if problem.Explanation != "" {
+			doc.IndentedParagraph(problem.Explanation, 10, false, 16)
+		}
+		doc.Space(4)
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// WriteDiaryPDF 学習日記をPDFで出力（学校に提出する学習記録用）
+func (e *Exporter) WriteDiaryPDF(w io.Writer, userName string, entries []database.DiaryEntry) error {
+	if len(entries) == 0 {
+		return fmt.Errorf("出力する日記がありません")
+	}
+
+	doc := newPDFDocument(e.font)
+
+	doc.Paragraph("学習記録", 22, true)
+	doc.Paragraph(fmt.Sprintf("%s さん　%s〜%s", userName, diaryDateText(entries[0].Date), diaryDateText(entries[len(entries)-1].Date)), 10, false)
+	doc.Rule()
+
+	for _, entry := range entries {
+		doc.Heading(diaryDateText(entry.Date), 13)
+		doc.Paragraph(entry.Content, 11, false)
+		doc.Space(6)
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// diaryDateText 日記の日付の表示（"2006-01-02"形式でなければそのまま）
+func diaryDateText(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.Format("2006年1月2日") + "（" + weekdayText(t) + "）"
+}
+
+// weekdayText 曜日の1文字の表示（日〜土）
+func weekdayText(t time.Time) string {
+	return string([]rune("日月火水木金土")[t.Weekday()])
+}
+
+// Certificate 賞状の内容
+type Certificate struct {
+	StudentName string
+	Title       string // 実績名（例: 100日連続学習）
+	Body        string // 本文
+	Date        time.Time
+}
+
+// WriteCertificatePDF 実績の賞状をPDFで出力（A4縦・印刷用）
+func (e *Exporter) WriteCertificatePDF(w io.Writer, certificate Certificate) error {
+	doc := newPDFDocument(e.font)
+	doc.Frame()
+
+	doc.Space(90)
+	doc.CenteredParagraph("賞　状", 44, true)
+	doc.Space(50)
+	doc.CenteredParagraph(fmt.Sprintf("%s　さん", certificate.StudentName), 26, true)
+	doc.Space(40)
+	doc.CenteredParagraph(fmt.Sprintf("「%s」", certificate.Title), 22, true)
+	doc.Space(30)
+	doc.CenteredParagraph(certificate.Body, 15, false)
+	doc.Space(10)
+	doc.CenteredParagraph("よってその努力をたたえ、ここに賞します。", 15, false)
+	doc.Space(120)
+	doc.CenteredParagraph(certificate.Date.Format("2006年1月2日"), 14, false)
+	doc.Space(10)
+	doc.CenteredParagraph("StudyBuddy AI", 16, true)
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// trendText トレンドの表示名
+func trendText(trend string) string {
+	switch trend {
+	case "improving":
+		return "上昇中"
+	case "declining":
+		return "下降気味"
+	default:
+		return "安定"
+	}
+}
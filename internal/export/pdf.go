@@ -0,0 +1,477 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// A4サイズ（ポイント）とページ余白
+const (
+	pageWidth    = 595.28
+	pageHeight   = 841.89
+	pageMargin   = 50.0
+	contentWidth = pageWidth - pageMargin*2
+	lineSpacing  = 1.6
+)
+
+// pdfFont PDFに埋め込むTrueTypeフォント
+type pdfFont struct {
+	font *sfnt.Font
+	data []byte
+	name string
+	buf  sfnt.Buffer
+}
+
+// pdfDocument 最小構成のPDF文書（日本語CIDフォント埋め込み対応）
+type pdfDocument struct {
+	font   *pdfFont
+	pages  []*bytes.Buffer
+	page   *bytes.Buffer
+	y      float64
+	glyphs map[sfnt.GlyphIndex]rune    // 使用したグリフ（ToUnicode用）
+	widths map[sfnt.GlyphIndex]float64 // グリフ幅（1000単位）
+	images []image.Image               // 埋め込む画像（/Im1から順に、すべてのページから参照する）
+}
+
+// newPDFFont TrueTypeフォントデータを解析
+func newPDFFont(data []byte) (*pdfFont, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("フォント解析エラー: %w", err)
+	}
+
+	pf := &pdfFont{font: f, data: data}
+	name, err := f.Name(&pf.buf, sfnt.NameIDPostScript)
+	if err != nil || name == "" {
+		name = "EmbeddedFont"
+	}
+	pf.name = strings.ReplaceAll(name, " ", "")
+
+	return pf, nil
+}
+
+// newPDFDocument 新しいPDF文書を作成
+func newPDFDocument(f *pdfFont) *pdfDocument {
+	doc := &pdfDocument{
+		font:   f,
+		glyphs: make(map[sfnt.GlyphIndex]rune),
+		widths: make(map[sfnt.GlyphIndex]float64),
+	}
+	doc.addPage()
+	return doc
+}
+
+// addPage 改ページ
+func (d *pdfDocument) addPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pageMargin
+}
+
+// ensureSpace 残りの高さが足りなければ改ページ
+func (d *pdfDocument) ensureSpace(height float64) {
+	if d.y+height > pageHeight-pageMargin {
+		d.addPage()
+	}
+}
+
+// glyph 文字に対応するグリフと幅を取得（フォントに無い文字は0）
+func (d *pdfDocument) glyph(r rune) (sfnt.GlyphIndex, float64) {
+	gid, err := d.font.font.GlyphIndex(&d.font.buf, r)
+	if err != nil || gid == 0 {
+		return 0, 0
+	}
+
+	if width, exists := d.widths[gid]; exists {
+		return gid, width
+	}
+
+	advance, err := d.font.font.GlyphAdvance(&d.font.buf, gid, fixed.I(1000), font.HintingNone)
+	if err != nil {
+		return 0, 0
+	}
+	width := float64(advance) / 64
+	d.widths[gid] = width
+	d.glyphs[gid] = r
+	return gid, width
+}
+
+// textWidth 文字列の描画幅を計算
+func (d *pdfDocument) textWidth(text string, size float64) float64 {
+	total := 0.0
+	for _, r := range text {
+		_, width := d.glyph(r)
+		total += width
+	}
+	return total * size / 1000
+}
+
+// drawText 指定位置（上端基準）にテキストを描画
+func (d *pdfDocument) drawText(x, y, size float64, text string, bold bool) {
+	var hex strings.Builder
+	for _, r := range text {
+		gid, _ := d.glyph(r)
+		if gid == 0 {
+			continue // 絵文字などフォントに無い文字は描画しない
+		}
+		fmt.Fprintf(&hex, "%04X", uint16(gid))
+	}
+	if hex.Len() == 0 {
+		return
+	}
+
+	// PDF座標系は左下原点のため変換（ベースラインはフォントサイズ分下）
+	baseline := pageHeight - y - size
+	if bold {
+		// 太字は塗り＋線描画で表現（フォントは1つだけ埋め込む）
+		fmt.Fprintf(d.page, "q %.2f w BT /F1 %.2f Tf 2 Tr %.2f %.2f Td <%s> Tj ET Q\n",
+			size/30, size, x, baseline, hex.String())
+		return
+	}
+	fmt.Fprintf(d.page, "BT /F1 %.2f Tf %.2f %.2f Td <%s> Tj ET\n", size, x, baseline, hex.String())
+}
+
+// wrapText 指定幅で折り返した行に分割
+func (d *pdfDocument) wrapText(text string, size, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := []rune{}
+		lineWidth := 0.0
+		for _, r := range paragraph {
+			_, w := d.glyph(r)
+			w = w * size / 1000
+			if lineWidth+w > width && len(line) > 0 {
+				// 英数字の単語途中であれば直前の空白で折り返す
+				breakAt := len(line)
+				if r < unicode.MaxASCII && !unicode.IsSpace(r) {
+					for i := len(line) - 1; i > 0; i-- {
+						if line[i] == ' ' {
+							breakAt = i + 1
+							break
+						}
+						if line[i] >= unicode.MaxASCII {
+							break
+						}
+					}
+				}
+				lines = append(lines, string(line[:breakAt]))
+				line = append([]rune{}, line[breakAt:]...)
+				lineWidth = d.textWidth(string(line), size)
+			}
+			line = append(line, r)
+			lineWidth += w
+		}
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// Paragraph 折り返し付きで段落を描画
+func (d *pdfDocument) Paragraph(text string, size float64, bold bool) {
+	d.IndentedParagraph(text, size, bold, 0)
+}
+
+// IndentedParagraph 字下げ付きで段落を描画
+func (d *pdfDocument) IndentedParagraph(text string, size float64, bold bool, indent float64) {
+	lineHeight := size * lineSpacing
+	for _, line := range d.wrapText(text, size, contentWidth-indent) {
+		d.ensureSpace(lineHeight)
+		d.drawText(pageMargin+indent, d.y, size, line, bold)
+		d.y += lineHeight
+	}
+}
+
+// Heading 見出しを描画
+func (d *pdfDocument) Heading(text string, size float64) {
+	d.ensureSpace(size * lineSpacing * 2)
+	d.Space(size * 0.4)
+	d.Paragraph(text, size, true)
+}
+
+// CenteredParagraph 中央揃えで段落を描画
+func (d *pdfDocument) CenteredParagraph(text string, size float64, bold bool) {
+	lineHeight := size * lineSpacing
+	for _, line := range d.wrapText(text, size, contentWidth) {
+		d.ensureSpace(lineHeight)
+		d.drawText((pageWidth-d.textWidth(line, size))/2, d.y, size, line, bold)
+		d.y += lineHeight
+	}
+}
+
+// Frame ページの外周に二重の枠線を描画（賞状用）
+func (d *pdfDocument) Frame() {
+	outer, inner := pageMargin/2, pageMargin/2+6
+	fmt.Fprintf(d.page, "q 3 w 0.7 0.55 0.2 RG %.2f %.2f %.2f %.2f re S Q\n",
+		outer, outer, pageWidth-outer*2, pageHeight-outer*2)
+	fmt.Fprintf(d.page, "q 1 w 0.7 0.55 0.2 RG %.2f %.2f %.2f %.2f re S Q\n",
+		inner, inner, pageWidth-inner*2, pageHeight-inner*2)
+}
+
+// Image 画像を左寄せで描画（幅はwidthポイントまでで、本文の幅に収める）
+func (d *pdfDocument) Image(img image.Image, width float64) {
+	bounds := img.Bounds()
+	width = min(width, contentWidth)
+	height := width * float64(bounds.Dy()) / float64(bounds.Dx())
+	d.ensureSpace(height)
+	d.images = append(d.images, img)
+	fmt.Fprintf(d.page, "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n",
+		width, height, pageMargin, pageHeight-d.y-height, len(d.images))
+	d.y += height
+}
+
+// Space 縦方向の余白
+func (d *pdfDocument) Space(height float64) {
+	d.y += height
+}
+
+// Rule 区切り線を描画
+func (d *pdfDocument) Rule() {
+	d.ensureSpace(12)
+	y := pageHeight - d.y - 6
+	fmt.Fprintf(d.page, "q 0.5 w 0.6 G %.2f %.2f m %.2f %.2f l S Q\n",
+		pageMargin, y, pageWidth-pageMargin, y)
+	d.y += 12
+}
+
+// tableCellPadding 表のセルの内側の余白
+const tableCellPadding = 4.0
+
+// Table 罫線付きの表を描画（列の幅は本文の幅に対する比率。改ページしたら見出しの行をもう一度描画する）
+func (d *pdfDocument) Table(widths []float64, header []string, rows [][]string, size float64) {
+	d.tableRow(widths, header, size, true)
+	for _, row := range rows {
+		if d.y+d.tableRowHeight(widths, row, size) > pageHeight-pageMargin {
+			d.addPage()
+			d.tableRow(widths, header, size, true)
+		}
+		d.tableRow(widths, row, size, false)
+	}
+}
+
+// tableRowHeight 表の1行の高さ（いちばん行数の多いセルに合わせる）
+func (d *pdfDocument) tableRowHeight(widths []float64, cells []string, size float64) float64 {
+	lines := 1
+	for i, cell := range cells {
+		lines = max(lines, len(d.wrapText(cell, size, widths[i]*contentWidth-tableCellPadding*2)))
+	}
+	return float64(lines)*size*lineSpacing + tableCellPadding*2
+}
+
+// tableRow 表の1行を描画（見出しの行は背景を塗って太字にする）
+func (d *pdfDocument) tableRow(widths []float64, cells []string, size float64, header bool) {
+	height := d.tableRowHeight(widths, cells, size)
+	d.ensureSpace(height)
+
+	x := pageMargin
+	bottom := pageHeight - d.y - height
+	for i, cell := range cells {
+		width := widths[i] * contentWidth
+		if header {
+			fmt.Fprintf(d.page, "q 0.92 g %.2f %.2f %.2f %.2f re f Q\n", x, bottom, width, height)
+		}
+		fmt.Fprintf(d.page, "q 0.5 w 0.4 G %.2f %.2f %.2f %.2f re S Q\n", x, bottom, width, height)
+		for j, line := range d.wrapText(cell, size, width-tableCellPadding*2) {
+			d.drawText(x+tableCellPadding, d.y+tableCellPadding+float64(j)*size*lineSpacing, size, line, header)
+		}
+		x += width
+	}
+	d.y += height
+}
+
+// WriteTo PDFバイナリを書き出す
+func (d *pdfDocument) WriteTo(w io.Writer) (int64, error) {
+	out := &bytes.Buffer{}
+	var offsets []int
+
+	beginObject := func() int {
+		offsets = append(offsets, out.Len())
+		id := len(offsets)
+		fmt.Fprintf(out, "%d 0 obj\n", id)
+		return id
+	}
+	writeStream := func(dict string, data []byte) {
+		fmt.Fprintf(out, "<< %s /Length %d >>\nstream\n", dict, len(data))
+		out.Write(data)
+		out.WriteString("\nendstream\nendobj\n")
+	}
+
+	out.WriteString("%PDF-1.7\n%\xE2\xE3\xCF\xD3\n")
+
+	// オブジェクト番号の割り当て（固定部分）
+	const (
+		catalogID = 1
+		pagesID   = 2
+		fontID    = 3
+		cidFontID = 4
+		descID    = 5
+		fileID    = 6
+		cmapID    = 7
+		firstPage = 8
+	)
+
+	// 1: カタログ
+	beginObject()
+	fmt.Fprintf(out, "<< /Type /Catalog /Pages %d 0 R >>\nendobj\n", pagesID)
+
+	// 2: ページツリー
+	beginObject()
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+i*2)
+	}
+	fmt.Fprintf(out, "<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(d.pages))
+
+	// 3: Type0フォント
+	beginObject()
+	fmt.Fprintf(out, "<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>\nendobj\n",
+		d.font.name, cidFontID, cmapID)
+
+	// 4: CIDフォント
+	beginObject()
+	fmt.Fprintf(out, "<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /DW 1000 /W [%s] >>\nendobj\n",
+		d.font.name, descID, d.widthArray())
+
+	// 5: フォント記述子
+	beginObject()
+	metrics, _ := d.font.font.Metrics(&d.font.buf, fixed.I(1000), font.HintingNone)
+	bounds, _ := d.font.font.Bounds(&d.font.buf, fixed.I(1000), font.HintingNone)
+	fmt.Fprintf(out, "<< /Type /FontDescriptor /FontName /%s /Flags 4 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>\nendobj\n",
+		d.font.name, bounds.Min.X.Round(), -bounds.Max.Y.Round(), bounds.Max.X.Round(), -bounds.Min.Y.Round(),
+		metrics.Ascent.Round(), -metrics.Descent.Round(), metrics.CapHeight.Round(), fileID)
+
+	// 6: フォント本体
+	beginObject()
+	fontData, err := deflate(d.font.data)
+	if err != nil {
+		return 0, err
+	}
+	writeStream(fmt.Sprintf("/Filter /FlateDecode /Length1 %d", len(d.font.data)), fontData)
+
+	// 7: ToUnicode CMap（テキストのコピー・検索用）
+	beginObject()
+	cmap, err := deflate([]byte(d.toUnicodeCMap()))
+	if err != nil {
+		return 0, err
+	}
+	writeStream("/Filter /FlateDecode", cmap)
+
+	// 8〜: ページとコンテンツ（画像はページのあと）
+	firstImage := firstPage + len(d.pages)*2
+	var xobjects []string
+	for i := range d.images {
+		xobjects = append(xobjects, fmt.Sprintf("/Im%d %d 0 R", i+1, firstImage+i))
+	}
+	resources := fmt.Sprintf("/Font << /F1 %d 0 R >>", fontID)
+	if len(xobjects) > 0 {
+		resources += fmt.Sprintf(" /XObject << %s >>", strings.Join(xobjects, " "))
+	}
+	for i, page := range d.pages {
+		pageID := beginObject()
+		fmt.Fprintf(out, "<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources << %s >> /Contents %d 0 R >>\nendobj\n",
+			pagesID, pageWidth, pageHeight, resources, pageID+1)
+
+		beginObject()
+		content, err := deflate(page.Bytes())
+		if err != nil {
+			return 0, fmt.Errorf("ページ%d圧縮エラー: %w", i+1, err)
+		}
+		writeStream("/Filter /FlateDecode", content)
+	}
+	for i, img := range d.images {
+		beginObject()
+		bounds := img.Bounds()
+		pixels := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				r, g, b, _ := img.At(x, y).RGBA()
+				pixels = append(pixels, byte(r>>8), byte(g>>8), byte(b>>8))
+			}
+		}
+		data, err := deflate(pixels)
+		if err != nil {
+			return 0, fmt.Errorf("画像%d圧縮エラー: %w", i+1, err)
+		}
+		writeStream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode",
+			bounds.Dx(), bounds.Dy()), data)
+	}
+
+	// 相互参照表とトレーラー
+	xrefOffset := out.Len()
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, catalogID, xrefOffset)
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+// sortedGlyphs 使用グリフを番号順に取得
+func (d *pdfDocument) sortedGlyphs() []sfnt.GlyphIndex {
+	gids := make([]sfnt.GlyphIndex, 0, len(d.glyphs))
+	for gid := range d.glyphs {
+		gids = append(gids, gid)
+	}
+	sort.Slice(gids, func(i, j int) bool { return gids[i] < gids[j] })
+	return gids
+}
+
+// widthArray CIDフォントのW配列を生成
+func (d *pdfDocument) widthArray() string {
+	var b strings.Builder
+	for _, gid := range d.sortedGlyphs() {
+		fmt.Fprintf(&b, "%d [%.0f] ", gid, d.widths[gid])
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// toUnicodeCMap グリフ番号からUnicodeへの対応表を生成
+func (d *pdfDocument) toUnicodeCMap() string {
+	var b strings.Builder
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	b.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	b.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	b.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	gids := d.sortedGlyphs()
+	for start := 0; start < len(gids); start += 100 {
+		end := min(start+100, len(gids))
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, gid := range gids[start:end] {
+			var unicodeHex strings.Builder
+			for _, unit := range utf16.Encode([]rune{d.glyphs[gid]}) {
+				fmt.Fprintf(&unicodeHex, "%04X", unit)
+			}
+			fmt.Fprintf(&b, "<%04X> <%s>\n", uint16(gid), unicodeHex.String())
+		}
+		b.WriteString("endbfchar\n")
+	}
+
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.String()
+}
+
+// deflate zlib圧縮（FlateDecode）
+func deflate(data []byte) ([]byte, error) {
+	var b bytes.Buffer
+	zw := zlib.NewWriter(&b)
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("圧縮エラー: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("圧縮エラー: %w", err)
+	}
+	return b.Bytes(), nil
+}
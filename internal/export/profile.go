@@ -0,0 +1,190 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// ProfileFileExtension プロフィールのファイルの拡張子
+const ProfileFileExtension = ".sbprofile"
+
+// MinPassphraseLength プロフィールのファイルのパスフレーズの最低文字数
+const MinPassphraseLength = 8
+
+// プロフィールのファイルの形式（見出し・ソルト・ノンス・暗号文の順）
+const (
+	profileMagic         = "STUDYBUDDY-PROFILE-1\n"
+	profileSaltSize      = 16
+	profileKeyIterations = 600000    // PBKDF2-SHA256の繰り返し回数
+	maxProfileFileSize   = 256 << 20 // 読み込むファイルの大きさの上限
+)
+
+// maxProfileDataSize 読み込むプロフィールのデータ（展開後のJSON）の大きさの上限
+var maxProfileDataSize = 1 << 30
+
+// ErrWrongPassphrase パスフレーズが違う（またはファイルが壊れている）
+var ErrWrongPassphrase = errors.New("パスフレーズが違うか、ファイルが壊れています")
+
+// ProfileSettings プロフィールと一緒に移す設定
+// （AIの接続先やクラウドAIのAPIキー、データベースの場所のようなパソコンごとの設定は含めない）
+type ProfileSettings struct {
+	UserGrade int                   `json:"user_grade"`
+	UI        config.UIConfig       `json:"ui"`
+	Learning  config.LearningConfig `json:"learning"`
+	School    config.SchoolConfig   `json:"school"`
+}
+
+// NewProfileSettings 設定から、プロフィールと一緒に移す設定を取り出す
+func NewProfileSettings(cfg *config.Config) ProfileSettings {
+	return ProfileSettings{
+		UserGrade: cfg.UserGrade,
+		UI:        cfg.UI,
+		Learning:  cfg.Learning,
+		School:    cfg.School,
+	}
+}
+
+// ApplyTo 設定に反映（ウィンドウの大きさは読み込み先のパソコンのまま）
+func (s ProfileSettings) ApplyTo(cfg *config.Config) {
+	width, height := cfg.UI.WindowWidth, cfg.UI.WindowHeight
+	cfg.UserGrade = s.UserGrade
+	cfg.UI = s.UI
+	cfg.UI.WindowWidth, cfg.UI.WindowHeight = width, height
+	cfg.Learning = s.Learning
+	cfg.School = s.School
+}
+
+// ProfileBundle プロフィールのファイルの中身
+type ProfileBundle struct {
+	ExportedAt time.Time             `json:"exported_at"`
+	Settings   ProfileSettings       `json:"settings"`
+	Data       *database.ProfileData `json:"data"`
+}
+
+// WriteProfile プロフィールをパスフレーズで暗号化して書き出す
+// （PBKDF2-SHA256でパスフレーズから鍵を作り、gzipで圧縮したJSONをAES-256-GCMで暗号化）
+func WriteProfile(w io.Writer, bundle *ProfileBundle, passphrase string) error {
+	if len([]rune(passphrase)) < MinPassphraseLength {
+		return fmt.Errorf("パスフレーズは%d文字以上にしてください", MinPassphraseLength)
+	}
+
+	var plain bytes.Buffer
+	zw := gzip.NewWriter(&plain)
+	if err := json.NewEncoder(zw).Encode(bundle); err != nil {
+		return fmt.Errorf("プロフィール変換エラー: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("プロフィール圧縮エラー: %w", err)
+	}
+	return sealProfile(w, plain.Bytes(), passphrase)
+}
+
+// sealProfile 圧縮したプロフィールを暗号化して、見出しと一緒に書き出す
+func sealProfile(w io.Writer, plain []byte, passphrase string) error {
+	salt := make([]byte, profileSaltSize)
+	_, _ = rand.Read(salt)
+	aead, err := profileCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, _ = rand.Read(nonce)
+
+	header := append(append([]byte(profileMagic), salt...), nonce...)
+	if _, err := w.Write(header); err != nil {
+		return fmt.Errorf("ファイル書き込みエラー: %w", err)
+	}
+	if _, err := w.Write(aead.Seal(nil, nonce, plain, header)); err != nil {
+		return fmt.Errorf("ファイル書き込みエラー: %w", err)
+	}
+	return nil
+}
+
+// ReadProfile パスフレーズで暗号化されたプロフィールを読み込む
+func ReadProfile(r io.Reader, passphrase string) (*ProfileBundle, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxProfileFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("ファイル読み込みエラー: %w", err)
+	}
+	if len(data) > maxProfileFileSize {
+		return nil, fmt.Errorf("ファイルが大きすぎます")
+	}
+	if !bytes.HasPrefix(data, []byte(profileMagic)) {
+		return nil, fmt.Errorf("StudyBuddy AIのプロフィールのファイルではありません")
+	}
+
+	rest := data[len(profileMagic):]
+	if len(rest) < profileSaltSize {
+		return nil, ErrWrongPassphrase
+	}
+	salt := rest[:profileSaltSize]
+	aead, err := profileCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	headerSize := len(profileMagic) + profileSaltSize + aead.NonceSize()
+	if len(data) < headerSize {
+		return nil, ErrWrongPassphrase
+	}
+	plain, err := aead.Open(nil, data[len(profileMagic)+profileSaltSize:headerSize], data[headerSize:], data[:headerSize])
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, fmt.Errorf("プロフィール展開エラー: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	// 小さなファイルが展開すると巨大になる場合に備え、展開後の大きさも制限する
+	decompressed, err := io.ReadAll(io.LimitReader(zr, int64(maxProfileDataSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("プロフィール展開エラー: %w", err)
+	}
+	if len(decompressed) > maxProfileDataSize {
+		return nil, fmt.Errorf("プロフィールのデータが大きすぎます")
+	}
+
+	// 数値は整数と小数を区別して読み込む（データベースに元の型で保存するため）
+	decoder := json.NewDecoder(bytes.NewReader(decompressed))
+	decoder.UseNumber()
+	var bundle ProfileBundle
+	if err := decoder.Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("プロフィール解析エラー: %w", err)
+	}
+	if bundle.Data == nil {
+		return nil, fmt.Errorf("プロフィールのデータがありません")
+	}
+	return &bundle, nil
+}
+
+// profileCipher パスフレーズとソルトから暗号化の鍵を作る
+func profileCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, profileKeyIterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("鍵作成エラー: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("暗号化準備エラー: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("暗号化準備エラー: %w", err)
+	}
+	return aead, nil
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+const testPassphrase = "correct horse battery"
+
+func TestProfileRoundTrip(t *testing.T) {
+	now := time.Now()
+	db := testutil.NewDB(t)
+	user := testutil.Seed(t, db, testutil.DefaultFixture(now))
+	data, err := db.ExportProfile(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.UserGrade = 3
+	bundle := &ProfileBundle{ExportedAt: now, Settings: NewProfileSettings(cfg), Data: data}
+	var file bytes.Buffer
+	if err := WriteProfile(&file, bundle, testPassphrase); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(file.Bytes(), []byte(user.Name)) {
+		t.Error("プロフィールのファイルに名前が平文で入っている")
+	}
+
+	read, err := ReadProfile(bytes.NewReader(file.Bytes()), testPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Settings.UserGrade != 3 || !read.ExportedAt.Equal(bundle.ExportedAt) {
+		t.Errorf("読み込んだ設定 = %+v, 書き出した日時 = %v", read.Settings, read.ExportedAt)
+	}
+
+	// 別のパソコンの新しいプロフィールとして読み込む
+	other := testutil.NewDB(t)
+	if err := other.ImportProfile("imported", read.Data); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := other.GetUser("imported")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Name != user.Name {
+		t.Errorf("読み込んだ名前 = %s, want %s", imported.Name, user.Name)
+	}
+	sessions, err := other.GetRecentStudySessions("imported", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 3 {
+		t.Errorf("読み込んだセッション数 = %d, want 3", len(sessions))
+	}
+}
+
+func TestReadProfileRejectsWrongPassphrase(t *testing.T) {
+	bundle := &ProfileBundle{ExportedAt: time.Now(), Data: &database.ProfileData{UserID: "student"}}
+	var file bytes.Buffer
+	if err := WriteProfile(&file, bundle, testPassphrase); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadProfile(bytes.NewReader(file.Bytes()), "wrong passphrase"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("パスフレーズが違うときのエラー = %v", err)
+	}
+
+	// 書き換えられたファイルも読み込まない
+	tampered := bytes.Clone(file.Bytes())
+	tampered[len(tampered)-1] ^= 0xff
+	if _, err := ReadProfile(bytes.NewReader(tampered), testPassphrase); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("書き換えられたファイルのエラー = %v", err)
+	}
+
+	if _, err := ReadProfile(strings.NewReader("not a profile"), testPassphrase); err == nil {
+		t.Error("プロフィールのファイルでなければエラーになるはず")
+	}
+	if err := WriteProfile(&file, bundle, "short"); err == nil {
+		t.Error("短いパスフレーズはエラーになるはず")
+	}
+}
+
+func TestReadProfileLimitsDecompressedSize(t *testing.T) {
+	original := maxProfileDataSize
+	maxProfileDataSize = 1 << 10
+	t.Cleanup(func() { maxProfileDataSize = original })
+
+	// 圧縮すると小さいが、展開すると上限を超えるデータ
+	var plain bytes.Buffer
+	zw := gzip.NewWriter(&plain)
+	_, _ = zw.Write(bytes.Repeat([]byte(" "), maxProfileDataSize*4))
+	_ = zw.Close()
+	var file bytes.Buffer
+	if err := sealProfile(&file, plain.Bytes(), testPassphrase); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ReadProfile(bytes.NewReader(file.Bytes()), testPassphrase)
+	if err == nil || !strings.Contains(err.Error(), "大きすぎます") {
+		t.Errorf("展開すると大きすぎるデータのエラー = %v", err)
+	}
+}
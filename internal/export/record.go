@@ -0,0 +1,280 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// 学習記録表の列の内容
+const (
+	RecordFieldDate    = "date"    // 日付
+	RecordFieldSubject = "subject" // 教科
+	RecordFieldMinutes = "minutes" // 学習時間（分）
+	RecordFieldResult  = "result"  // 解いた問題の正解数
+	RecordFieldComment = "comment" // ふり返り（学習日記）
+)
+
+// StudyRecordColumn 学習記録表の列
+type StudyRecordColumn struct {
+	Field  string  // RecordField〜
+	Header string  // 見出し
+	Width  float64 // PDFの本文の幅に対する比率
+}
+
+// StudyRecordTemplate 学習記録表の様式（学校で配られる記録用紙の一般的な形）
+type StudyRecordTemplate struct {
+	Name       string // 選択肢の表示名
+	Title      string // 用紙の見出し
+	PerDay     bool   // 1日を1行にまとめる（falseなら教科ごとに1行）
+	Columns    []StudyRecordColumn
+	Signatures []string // 用紙の下の確認欄（保護者・先生のサインなど）
+}
+
+// StudyRecordTemplates 学習記録表の様式の一覧（最初が標準）
+var StudyRecordTemplates = []StudyRecordTemplate{
+	{
+		Name:  "標準（日付・教科・時間・ふり返り）",
+		Title: "家庭学習記録表",
+		Columns: []StudyRecordColumn{
+			{RecordFieldDate, "日付", 0.17},
+			{RecordFieldSubject, "教科", 0.13},
+			{RecordFieldMinutes, "時間（分）", 0.12},
+			{RecordFieldComment, "ふり返り", 0.58},
+		},
+		Signatures: []string{"保護者の確認", "先生の確認"},
+	},
+	{
+		Name:  "正解数つき（日付・教科・時間・問題・ふり返り）",
+		Title: "家庭学習記録表",
+		Columns: []StudyRecordColumn{
+			{RecordFieldDate, "日付", 0.16},
+			{RecordFieldSubject, "教科", 0.12},
+			{RecordFieldMinutes, "時間（分）", 0.11},
+			{RecordFieldResult, "問題（正解）", 0.13},
+			{RecordFieldComment, "ふり返り", 0.48},
+		},
+		Signatures: []string{"保護者の確認", "先生の確認"},
+	},
+	{
+		Name:   "1日1行（日付・学習した教科・合計時間・ふり返り）",
+		Title:  "学習の記録",
+		PerDay: true,
+		Columns: []StudyRecordColumn{
+			{RecordFieldDate, "日付", 0.17},
+			{RecordFieldSubject, "学習した教科", 0.2},
+			{RecordFieldMinutes, "合計（分）", 0.11},
+			{RecordFieldComment, "ふり返り", 0.52},
+		},
+		Signatures: []string{"保護者の確認", "先生の確認"},
+	},
+}
+
+// StudyRecordActivity 1日の1つの教科の学習
+type StudyRecordActivity struct {
+	Subject  string
+	Minutes  int
+	Problems int
+	Correct  int
+}
+
+// StudyRecordDay 学習記録表の1日分（学習しなかった日も、手書きで書き足せるように行を作る）
+type StudyRecordDay struct {
+	Date       time.Time
+	Activities []StudyRecordActivity
+	Comment    string // その日の学習日記
+}
+
+// StudyRecordSheet 学習記録表に書き込む内容
+type StudyRecordSheet struct {
+	StudentName string
+	Grade       int
+	Days        []StudyRecordDay // 古い順
+}
+
+// studyRecordValue 学習記録表のセルの値（IsNumberなら数値、0は空欄）
+type studyRecordValue struct {
+	Text     string
+	Number   int
+	IsNumber bool
+}
+
+// String 印刷する文字列
+func (v studyRecordValue) String() string {
+	if !v.IsNumber {
+		return v.Text
+	}
+	if v.Number == 0 {
+		return ""
+	}
+	return fmt.Sprint(v.Number)
+}
+
+// studyRecordRows 様式に合わせて表の行を作る（最後の行は合計）
+func studyRecordRows(template StudyRecordTemplate, sheet StudyRecordSheet) [][]studyRecordValue {
+	var rows [][]studyRecordValue
+	var total StudyRecordActivity
+
+	for _, day := range sheet.Days {
+		activities := day.Activities
+		if template.PerDay && len(activities) > 1 {
+			merged := StudyRecordActivity{}
+			var subjects []string
+			for _, activity := range activities {
+				subjects = append(subjects, activity.Subject)
+				merged.Minutes += activity.Minutes
+				merged.Problems += activity.Problems
+				merged.Correct += activity.Correct
+			}
+			merged.Subject = strings.Join(subjects, "・")
+			activities = []StudyRecordActivity{merged}
+		}
+		if len(activities) == 0 {
+			activities = []StudyRecordActivity{{}}
+		}
+
+		for i, activity := range activities {
+			total.Minutes += activity.Minutes
+			total.Problems += activity.Problems
+			total.Correct += activity.Correct
+
+			// 日付とふり返りは、その日の最初の行にだけ書く
+			row := make([]studyRecordValue, len(template.Columns))
+			for c, column := range template.Columns {
+				switch column.Field {
+				case RecordFieldDate:
+					if i == 0 {
+						row[c].Text = day.Date.Format("1月2日") + "（" + weekdayText(day.Date) + "）"
+					}
+				case RecordFieldSubject:
+					row[c].Text = activity.Subject
+				case RecordFieldMinutes:
+					row[c] = studyRecordValue{Number: activity.Minutes, IsNumber: true}
+				case RecordFieldResult:
+					if activity.Problems > 0 {
+						row[c].Text = fmt.Sprintf("%d/%d問", activity.Correct, activity.Problems)
+					}
+				case RecordFieldComment:
+					if i == 0 {
+						row[c].Text = day.Comment
+					}
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	totalRow := make([]studyRecordValue, len(template.Columns))
+	for c, column := range template.Columns {
+		switch column.Field {
+		case RecordFieldMinutes:
+			totalRow[c] = studyRecordValue{Number: total.Minutes, IsNumber: true}
+		case RecordFieldResult:
+			if total.Problems > 0 {
+				totalRow[c].Text = fmt.Sprintf("%d/%d問", total.Correct, total.Problems)
+			}
+		}
+	}
+	totalRow[0].Text = "合計"
+	return append(rows, totalRow)
+}
+
+// studyRecordHeading 用紙の見出しの下に書く、学年・氏名・期間
+func studyRecordHeading(sheet StudyRecordSheet) string {
+	heading := fmt.Sprintf("中学%d年　氏名: %s", sheet.Grade, sheet.StudentName)
+	if len(sheet.Days) > 0 {
+		from, to := sheet.Days[0].Date, sheet.Days[len(sheet.Days)-1].Date
+		heading += fmt.Sprintf("　期間: %s〜%s", from.Format("2006年1月2日"), to.Format("1月2日"))
+	}
+	return heading
+}
+
+// WriteStudyRecordPDF 学習記録表をPDFで出力（A4縦・印刷して提出する用）
+func (e *Exporter) WriteStudyRecordPDF(w io.Writer, template StudyRecordTemplate, sheet StudyRecordSheet) error {
+	if len(sheet.Days) == 0 {
+		return fmt.Errorf("出力する期間がありません")
+	}
+
+	doc := newPDFDocument(e.font)
+	doc.CenteredParagraph(template.Title, 20, true)
+	doc.Paragraph(studyRecordHeading(sheet), 10, false)
+	doc.Space(6)
+
+	widths := make([]float64, len(template.Columns))
+	header := make([]string, len(template.Columns))
+	for i, column := range template.Columns {
+		widths[i] = column.Width
+		header[i] = column.Header
+	}
+	var rows [][]string
+	for _, row := range studyRecordRows(template, sheet) {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = value.String()
+		}
+		rows = append(rows, cells)
+	}
+	doc.Table(widths, header, rows, 10)
+
+	if len(template.Signatures) > 0 {
+		doc.Space(16)
+		signatureWidths := make([]float64, len(template.Signatures))
+		blank := make([]string, len(template.Signatures))
+		for i := range template.Signatures {
+			signatureWidths[i] = 1 / float64(len(template.Signatures))
+			blank[i] = "\n\n" // サイン・押印の欄（3行分の高さ）
+		}
+		doc.Table(signatureWidths, template.Signatures, [][]string{blank}, 10)
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// WriteStudyRecordXLSX 学習記録表をExcel形式（.xlsx）で出力（学校のExcelの様式に貼り付けたり、印刷したりする用）
+func WriteStudyRecordXLSX(w io.Writer, template StudyRecordTemplate, sheet StudyRecordSheet) error {
+	if len(sheet.Days) == 0 {
+		return fmt.Errorf("出力する期間がありません")
+	}
+
+	last := xlsxColumn(len(template.Columns) - 1)
+	xs := xlsxSheet{
+		Name: "学習記録",
+		Rows: [][]xlsxCell{
+			{xlsxText(template.Title, xlsxStyleTitle)},
+			{xlsxText(studyRecordHeading(sheet), xlsxStyleNormal)},
+			{},
+		},
+		Merges: []string{"A1:" + last + "1", "A2:" + last + "2"},
+	}
+
+	header := make([]xlsxCell, len(template.Columns))
+	for i, column := range template.Columns {
+		// PDFの幅の比率を、Excelの列の幅（全体で約90文字）に換算
+		xs.Widths = append(xs.Widths, column.Width*90)
+		header[i] = xlsxText(column.Header, xlsxStyleHeader)
+	}
+	xs.Rows = append(xs.Rows, header)
+
+	for _, row := range studyRecordRows(template, sheet) {
+		cells := make([]xlsxCell, len(row))
+		for i, value := range row {
+			if value.IsNumber && value.Number != 0 {
+				cells[i] = xlsxNumber(value.Number, xlsxStyleCell)
+			} else {
+				cells[i] = xlsxText(value.String(), xlsxStyleCell)
+			}
+		}
+		xs.Rows = append(xs.Rows, cells)
+	}
+
+	if len(template.Signatures) > 0 {
+		xs.Rows = append(xs.Rows, []xlsxCell{})
+		for _, signature := range template.Signatures {
+			xs.Rows = append(xs.Rows, []xlsxCell{xlsxText(signature, xlsxStyleHeader), xlsxText("", xlsxStyleCell)})
+		}
+	}
+
+	return writeXLSX(w, xs)
+}
@@ -0,0 +1,247 @@
+package export
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 分析用スナップショットの形式
+const (
+	SnapshotFileExtension = ".sqlite"
+	snapshotFormatVersion = "1"
+	snapshotTimeLayout    = time.RFC3339 // pandasなどでそのまま日時として読める形式
+	snapshotDateLayout    = "2006-01-02"
+)
+
+// AnalysisSnapshot 分析用に書き出す学習データ
+// （書き出すのは集計に使う数値と科目・単元だけ。名前・ID・問題文・解答・メモは書き出さない）
+type AnalysisSnapshot struct {
+	Grade    int
+	Sessions []database.StudySession
+	Results  []database.ProblemResult
+	Topics   []database.TopicMastery
+	Focus    []database.SessionFocus
+}
+
+// snapshotSchema 分析用スナップショットのテーブル（セッションはIDの代わりに古い順の番号で表す）
+const snapshotSchema = `
+CREATE TABLE snapshot_info (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+CREATE TABLE sessions (
+    session_no INTEGER PRIMARY KEY,
+    subject TEXT NOT NULL,
+    session_type TEXT NOT NULL, -- app | manual | exam
+    date TEXT NOT NULL, -- "2006-01-02"形式
+    weekday INTEGER NOT NULL, -- 0:日曜〜6:土曜
+    start_hour INTEGER NOT NULL,
+    start_time TEXT NOT NULL,
+    duration_seconds INTEGER NOT NULL,
+    total_problems INTEGER NOT NULL,
+    correct_answers INTEGER NOT NULL,
+    max_combo INTEGER NOT NULL,
+    focus_score REAL, -- 集中度（0-100。記録がなければNULL）
+    pause_count INTEGER,
+    breaks_taken INTEGER
+);
+CREATE TABLE answers (
+    session_no INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    difficulty INTEGER NOT NULL,
+    is_correct INTEGER NOT NULL, -- 1:正解 0:不正解
+    time_taken_seconds INTEGER NOT NULL,
+    error_category TEXT NOT NULL,
+    answered_at TEXT NOT NULL
+);
+CREATE TABLE topic_mastery (
+    subject TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    correct_answers INTEGER NOT NULL,
+    accuracy REAL NOT NULL,
+    last_studied_date TEXT NOT NULL,
+    PRIMARY KEY (subject, topic)
+);
+CREATE TABLE daily_summary (
+    date TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    sessions INTEGER NOT NULL,
+    study_seconds INTEGER NOT NULL,
+    problems INTEGER NOT NULL,
+    correct_answers INTEGER NOT NULL,
+    PRIMARY KEY (date, subject)
+);
+`
+
+// WriteAnalysisSnapshot 学習データを、分析用のSQLiteファイルとして出力
+// （Pythonなら pandas.read_sql("SELECT * FROM answers", sqlite3.connect(path)) で読み込める）
+func WriteAnalysisSnapshot(w io.Writer, snapshot AnalysisSnapshot, now time.Time) error {
+	dir, err := os.MkdirTemp("", "studybuddy-snapshot-")
+	if err != nil {
+		return fmt.Errorf("一時ディレクトリ作成エラー: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "snapshot.sqlite")
+	if err := writeSnapshotDatabase(path, snapshot, now); err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("スナップショット読み込みエラー: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	if _, err := io.Copy(w, file); err != nil {
+		return fmt.Errorf("スナップショット書き込みエラー: %w", err)
+	}
+	return nil
+}
+
+// writeSnapshotDatabase 分析用スナップショットのデータベースを作成
+func writeSnapshotDatabase(path string, snapshot AnalysisSnapshot, now time.Time) error {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("スナップショット作成エラー: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if _, err := db.Exec(snapshotSchema); err != nil {
+		return fmt.Errorf("スナップショット作成エラー: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("スナップショット書き込みエラー: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	writers := []func(*sql.Tx, AnalysisSnapshot, time.Time) error{
+		writeSnapshotInfo,
+		writeSnapshotSessions,
+		writeSnapshotTopics,
+		writeSnapshotDaily,
+	}
+	for _, write := range writers {
+		if err := write(tx, snapshot, now); err != nil {
+			return fmt.Errorf("スナップショット書き込みエラー: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("スナップショット書き込みエラー: %w", err)
+	}
+	return nil
+}
+
+// writeSnapshotInfo 書き出した日時・形式・学年
+func writeSnapshotInfo(tx *sql.Tx, snapshot AnalysisSnapshot, now time.Time) error {
+	zone, _ := now.Zone()
+	info := [][2]string{
+		{"format_version", snapshotFormatVersion},
+		{"created_at", now.Format(snapshotTimeLayout)},
+		{"timezone", zone},
+		{"grade", fmt.Sprint(snapshot.Grade)},
+	}
+	for _, kv := range info {
+		if _, err := tx.Exec(`INSERT INTO snapshot_info (key, value) VALUES (?, ?)`, kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSnapshotSessions セッションと、セッションごとの解答（セッションは古い順に番号を付ける）
+func writeSnapshotSessions(tx *sql.Tx, snapshot AnalysisSnapshot, _ time.Time) error {
+	sessions := append([]database.StudySession(nil), snapshot.Sessions...)
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].StartTime.Before(sessions[j].StartTime) })
+
+	focus := make(map[string]database.SessionFocus, len(snapshot.Focus))
+	for _, f := range snapshot.Focus {
+		focus[f.SessionID] = f
+	}
+
+	numbers := make(map[string]int, len(sessions))
+	subjects := make(map[string]string, len(sessions))
+	for i, session := range sessions {
+		no := i + 1
+		numbers[session.ID] = no
+		subjects[session.ID] = session.Subject
+
+		var focusScore sql.NullFloat64
+		var pauses, breaks sql.NullInt64
+		if f, ok := focus[session.ID]; ok {
+			focusScore = sql.NullFloat64{Float64: f.FocusScore, Valid: true}
+			pauses = sql.NullInt64{Int64: int64(f.PauseCount), Valid: true}
+			breaks = sql.NullInt64{Int64: int64(f.BreaksTaken), Valid: true}
+		}
+		_, err := tx.Exec(`INSERT INTO sessions (session_no, subject, session_type, date, weekday, start_hour,
+			start_time, duration_seconds, total_problems, correct_answers, max_combo, focus_score, pause_count, breaks_taken)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			no, session.Subject, session.SessionType, session.StartTime.Format(snapshotDateLayout),
+			int(session.StartTime.Weekday()), session.StartTime.Hour(), session.StartTime.Format(snapshotTimeLayout),
+			session.DurationSeconds(), session.TotalProblems, session.CorrectAnswers, session.MaxCombo,
+			focusScore, pauses, breaks)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, result := range snapshot.Results {
+		no, ok := numbers[result.SessionID]
+		if !ok {
+			continue
+		}
+		correct := 0
+		if result.IsCorrect {
+			correct = 1
+		}
+		_, err := tx.Exec(`INSERT INTO answers (session_no, subject, topic, difficulty, is_correct,
+			time_taken_seconds, error_category, answered_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			no, subjects[result.SessionID], result.ProblemType, result.Difficulty, correct,
+			result.TimeTaken, result.ErrorCategory, result.CreatedAt.Format(snapshotTimeLayout))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSnapshotTopics 単元別の正答率
+func writeSnapshotTopics(tx *sql.Tx, snapshot AnalysisSnapshot, _ time.Time) error {
+	for _, topic := range snapshot.Topics {
+		accuracy := 0.0
+		if topic.Attempts > 0 {
+			accuracy = float64(topic.CorrectAnswers) / float64(topic.Attempts)
+		}
+		_, err := tx.Exec(`INSERT INTO topic_mastery (subject, topic, attempts, correct_answers, accuracy, last_studied_date)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			topic.Subject, topic.Topic, topic.Attempts, topic.CorrectAnswers, accuracy, topic.LastStudied.Format(snapshotDateLayout))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSnapshotDaily 日・科目ごとの学習時間と解答数
+func writeSnapshotDaily(tx *sql.Tx, snapshot AnalysisSnapshot, _ time.Time) error {
+	_, err := tx.Exec(`
+		INSERT INTO daily_summary (date, subject, sessions, study_seconds, problems, correct_answers)
+		SELECT date, subject, COUNT(*), SUM(duration_seconds), SUM(total_problems), SUM(correct_answers)
+		FROM sessions
+		GROUP BY date, subject
+	`)
+	return err
+}
@@ -0,0 +1,64 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// TranscriptFileExtension AIとのやりとりの記録のファイルの拡張子
+const TranscriptFileExtension = ".md"
+
+// transcriptSourceLabels AIとのやりとりの種類の表示名
+var transcriptSourceLabels = map[string]string{
+	database.TranscriptSourceCapture: "クイック質問",
+	database.TranscriptSourceAsk:     "質問する",
+}
+
+// transcriptWeekdays 日付の見出しの曜日
+var transcriptWeekdays = []string{"日", "月", "火", "水", "木", "金", "土"}
+
+// WriteTranscriptMarkdown AIとのやりとりの記録を、日ごと・学習セッションごとにまとめたMarkdownとして出力
+func WriteTranscriptMarkdown(w io.Writer, name string, transcripts []database.TutorTranscript, now time.Time) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# AIとのやりとりの記録（%s）\n\n", name)
+	fmt.Fprintf(&b, "書き出した日時: %s　件数: %d件\n", now.Format("2006年1月2日 15:04"), len(transcripts))
+
+	var day, session string
+	for _, t := range transcripts {
+		if d := t.CreatedAt.Format("2006-01-02"); d != day {
+			day, session = d, ""
+			fmt.Fprintf(&b, "\n## %s（%s）\n", t.CreatedAt.Format("2006年1月2日"), transcriptWeekdays[t.CreatedAt.Weekday()])
+		}
+		if t.SessionID != session {
+			session = t.SessionID
+			if session != "" {
+				b.WriteString("\n*― 学習セッション中の質問 ―*\n")
+			}
+		}
+
+		title := transcriptSourceLabels[t.Source]
+		if t.Subject != "" {
+			title += "・" + t.Subject
+		}
+		fmt.Fprintf(&b, "\n### %s %s\n\n", t.CreatedAt.Format("15:04"), title)
+		b.WriteString("**質問**\n\n")
+		for _, line := range strings.Split(strings.TrimSpace(t.Question), "\n") {
+			b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+		}
+		if t.Refused {
+			b.WriteString("\n**AIの返答（範囲外の質問のため答えませんでした）**\n\n")
+		} else {
+			b.WriteString("\n**AIの解説**\n\n")
+		}
+		b.WriteString(strings.TrimSpace(t.Answer) + "\n")
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("AIとのやりとりの記録の書き出しエラー: %w", err)
+	}
+	return nil
+}
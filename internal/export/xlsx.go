@@ -0,0 +1,189 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// xlsxStyle セルの書式（styles.xmlのcellXfsの番号）
+type xlsxStyle int
+
+const (
+	xlsxStyleNormal xlsxStyle = iota
+	xlsxStyleTitle            // 大きめの太字
+	xlsxStyleHeader           // 罫線・背景色つきの太字（表の見出し）
+	xlsxStyleCell             // 罫線つき・折り返しあり（表の中身）
+)
+
+// xlsxCell Excelのセル（IsNumberなら数値、それ以外は文字列）
+type xlsxCell struct {
+	Text     string
+	Number   int
+	IsNumber bool
+	Style    xlsxStyle
+}
+
+// xlsxSheet Excelに書き出す1枚のシート
+type xlsxSheet struct {
+	Name   string
+	Widths []float64 // 列の幅（文字数）
+	Rows   [][]xlsxCell
+	Merges []string // 結合するセルの範囲（例: "A1:D1"）
+}
+
+// xlsxText 文字列のセル
+func xlsxText(text string, style xlsxStyle) xlsxCell {
+	return xlsxCell{Text: text, Style: style}
+}
+
+// xlsxNumber 数値のセル
+func xlsxNumber(number int, style xlsxStyle) xlsxCell {
+	return xlsxCell{Number: number, IsNumber: true, Style: style}
+}
+
+// xlsxColumn 列番号（0始まり）を列名（A, B, …, Z, AA, …）に変換
+func xlsxColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// writeXLSX シートを1枚だけ含むExcelブック（.xlsx）を出力（文字列はセルに直接書き、共有文字列は使わない）
+func writeXLSX(w io.Writer, sheet xlsxSheet) error {
+	worksheet, err := xlsxWorksheet(sheet)
+	if err != nil {
+		return err
+	}
+
+	var workbook bytes.Buffer
+	workbook.WriteString(xml.Header)
+	workbook.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="`)
+	if err := xml.EscapeText(&workbook, []byte(sheet.Name)); err != nil {
+		return fmt.Errorf("xlsx作成エラー: %w", err)
+	}
+	workbook.WriteString(`" sheetId="1" r:id="rId1"/></sheets></workbook>`)
+
+	archive := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"[Content_Types].xml", []byte(xml.Header + xlsxContentTypes)},
+		{"_rels/.rels", []byte(xml.Header + xlsxRootRels)},
+		{"xl/workbook.xml", workbook.Bytes()},
+		{"xl/_rels/workbook.xml.rels", []byte(xml.Header + xlsxWorkbookRels)},
+		{"xl/styles.xml", []byte(xml.Header + xlsxStyles)},
+		{"xl/worksheets/sheet1.xml", worksheet},
+	}
+	for _, file := range files {
+		fw, err := archive.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("xlsx作成エラー: %w", err)
+		}
+		if _, err := fw.Write(file.data); err != nil {
+			return fmt.Errorf("xlsx書き込みエラー: %w", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("xlsx書き込みエラー: %w", err)
+	}
+	return nil
+}
+
+// xlsxWorksheet シートのXML（列の幅・セル・結合・A4縦の印刷設定）
+func xlsxWorksheet(sheet xlsxSheet) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	if len(sheet.Widths) > 0 {
+		b.WriteString("<cols>")
+		for i, width := range sheet.Widths {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%.1f" customWidth="1"/>`, i+1, i+1, width)
+		}
+		b.WriteString("</cols>")
+	}
+
+	b.WriteString("<sheetData>")
+	for r, row := range sheet.Rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := xlsxColumn(c) + strconv.Itoa(r+1)
+			if cell.IsNumber {
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%d</v></c>`, ref, cell.Style, cell.Number)
+				continue
+			}
+			fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">`, ref, cell.Style)
+			if err := xml.EscapeText(&b, []byte(cell.Text)); err != nil {
+				return nil, fmt.Errorf("xlsx作成エラー: %w", err)
+			}
+			b.WriteString("</t></is></c>")
+		}
+		b.WriteString("</row>")
+	}
+	b.WriteString("</sheetData>")
+
+	if len(sheet.Merges) > 0 {
+		fmt.Fprintf(&b, `<mergeCells count="%d">`, len(sheet.Merges))
+		for _, merge := range sheet.Merges {
+			fmt.Fprintf(&b, `<mergeCell ref="%s"/>`, merge)
+		}
+		b.WriteString("</mergeCells>")
+	}
+
+	b.WriteString(`<pageMargins left="0.6" right="0.6" top="0.7" bottom="0.7" header="0.3" footer="0.3"/>`)
+	b.WriteString(`<pageSetup paperSize="9" orientation="portrait"/>`)
+	b.WriteString("</worksheet>")
+	return b.Bytes(), nil
+}
+
+const xlsxContentTypes = `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbookRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// xlsxStyles cellXfsの順番はxlsxStyleの定数と合わせる
+const xlsxStyles = `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="3">` +
+	`<font><sz val="11"/><name val="Yu Gothic"/><family val="3"/><charset val="128"/></font>` +
+	`<font><b/><sz val="14"/><name val="Yu Gothic"/><family val="3"/><charset val="128"/></font>` +
+	`<font><b/><sz val="11"/><name val="Yu Gothic"/><family val="3"/><charset val="128"/></font>` +
+	`</fonts>` +
+	`<fills count="3">` +
+	`<fill><patternFill patternType="none"/></fill>` +
+	`<fill><patternFill patternType="gray125"/></fill>` +
+	`<fill><patternFill patternType="solid"><fgColor rgb="FFEBEBEB"/><bgColor indexed="64"/></patternFill></fill>` +
+	`</fills>` +
+	`<borders count="2">` +
+	`<border><left/><right/><top/><bottom/><diagonal/></border>` +
+	`<border><left style="thin"><color auto="1"/></left><right style="thin"><color auto="1"/></right>` +
+	`<top style="thin"><color auto="1"/></top><bottom style="thin"><color auto="1"/></bottom><diagonal/></border>` +
+	`</borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="4">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="0" fontId="2" fillId="2" borderId="1" xfId="0" applyFont="1" applyFill="1" applyBorder="1" applyAlignment="1"><alignment horizontal="center" vertical="center"/></xf>` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="1" xfId="0" applyBorder="1" applyAlignment="1"><alignment vertical="top" wrapText="1"/></xf>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="標準" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
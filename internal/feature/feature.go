@@ -0,0 +1,187 @@
+package feature
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// 機能フラグの名前（設定ファイルの features のキー）
+const (
+	Sync      = "sync"      // 複数のパソコンでの学習記録の同期
+	Classroom = "classroom" // 教室モード（先生が課題を配り、クラスの進み具合を見る）
+	RAG       = "rag"       // 教科書・ノートを参照した問題の生成
+)
+
+// 機能フラグの有効範囲
+const (
+	ScopeOff     = "off"     // 無効
+	ScopeProfile = "profile" // 一部のプロフィールだけ有効
+	ScopeAll     = "all"     // 全員で有効
+)
+
+// Flag 機能フラグ（完成前の大きな機能を、既定では無効のまま組み込むため）
+type Flag struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// Flags 機能フラグの一覧
+var Flags = []Flag{
+	{Name: Sync, Title: "学習記録の同期", Description: "家と学校など、複数のパソコンで同じ学習記録を使います。"},
+	{Name: Classroom, Title: "教室モード", Description: "先生が課題を配り、クラスの進み具合を確認します。"},
+	{Name: RAG, Title: "教科書を参照した出題", Description: "取り込んだ教科書・ノートの内容に沿って問題を作ります。"},
+}
+
+// Lookup 名前から機能フラグを取得
+func Lookup(name string) (Flag, bool) {
+	for _, flag := range Flags {
+		if flag.Name == name {
+			return flag, true
+		}
+	}
+	return Flag{}, false
+}
+
+// Status プロフィールから見た機能フラグの状態
+type Status struct {
+	Flag
+	Enabled bool   `json:"enabled"` // このプロフィールで有効か
+	Scope   string `json:"scope"`   // ScopeOff・ScopeProfile・ScopeAll
+}
+
+// Toggles 機能フラグの判定と切り替え（設定を毎回読むため、切り替えはすぐに反映される）
+type Toggles struct {
+	mu        sync.RWMutex
+	config    *config.Config
+	authorize func() error // 切り替えてよいか（nilなら確かめない）
+}
+
+// New 設定の機能フラグを使う
+func New(cfg *config.Config) *Toggles {
+	return &Toggles{config: cfg}
+}
+
+// Enabled 機能がプロフィールで有効か（設定にないフラグは無効）
+func (t *Toggles) Enabled(name, profileID string) bool {
+	if t == nil {
+		return false
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	setting := t.config.Features[name]
+	return setting.Enabled || (profileID != "" && slices.Contains(setting.Profiles, profileID))
+}
+
+// Scope 機能フラグの有効範囲
+func (t *Toggles) Scope(name string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	setting := t.config.Features[name]
+	switch {
+	case setting.Enabled:
+		return ScopeAll
+	case len(setting.Profiles) > 0:
+		return ScopeProfile
+	default:
+		return ScopeOff
+	}
+}
+
+// SetAuthorizer 切り替える前に、切り替えてよいか確かめる関数を設定（教室モードで先生だけが切り替えられるようにする）
+func (t *Toggles) SetAuthorizer(authorize func() error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.authorize = authorize
+}
+
+// SetEnabled 全員で有効・無効を切り替え（プロフィールごとの指定はそのまま）
+func (t *Toggles) SetEnabled(name string, enabled bool) error {
+	return t.update(name, func(setting *config.FeatureConfig) {
+		setting.Enabled = enabled
+	})
+}
+
+// SetProfileEnabled プロフィールだけで有効・無効を切り替え
+func (t *Toggles) SetProfileEnabled(name, profileID string, enabled bool) error {
+	return t.update(name, func(setting *config.FeatureConfig) {
+		setting.Profiles = slices.DeleteFunc(setting.Profiles, func(id string) bool { return id == profileID })
+		if enabled {
+			setting.Profiles = append(setting.Profiles, profileID)
+		}
+	})
+}
+
+// update 機能フラグの設定を書き換える（保存は呼び出し側で行う）
+func (t *Toggles) update(name string, change func(*config.FeatureConfig)) error {
+	if _, ok := Lookup(name); !ok {
+		return fmt.Errorf("未知の機能フラグ: %s", name)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.authorize != nil {
+		if err := t.authorize(); err != nil {
+			return err
+		}
+	}
+	if t.config.Features == nil {
+		t.config.Features = make(map[string]config.FeatureConfig)
+	}
+	setting := t.config.Features[name]
+	change(&setting)
+	if !setting.Enabled && len(setting.Profiles) == 0 {
+		delete(t.config.Features, name)
+	} else {
+		t.config.Features[name] = setting
+	}
+	return nil
+}
+
+// Statuses プロフィールから見たすべての機能フラグの状態（一覧の順）
+func (t *Toggles) Statuses(profileID string) []Status {
+	statuses := make([]Status, 0, len(Flags))
+	for _, flag := range Flags {
+		statuses = append(statuses, Status{
+			Flag:    flag,
+			Enabled: t.Enabled(flag.Name, profileID),
+			Scope:   t.Scope(flag.Name),
+		})
+	}
+	return statuses
+}
+
+// Unknown 設定にあるが一覧にない機能フラグの名前（なくなった機能の設定。無視される）
+func (t *Toggles) Unknown() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var names []string
+	for name := range t.config.Features {
+		if _, ok := Lookup(name); !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Summary 診断情報に載せる機能フラグの状態（1行に1つ）
+func (t *Toggles) Summary(profileID string) string {
+	var b strings.Builder
+	for _, status := range t.Statuses(profileID) {
+		state := "無効"
+		if status.Enabled {
+			state = "有効"
+		}
+		fmt.Fprintf(&b, "%s: %s（%s）\n", status.Name, state, status.Scope)
+	}
+	for _, name := range t.Unknown() {
+		fmt.Fprintf(&b, "%s: 未知のフラグ（無視）\n", name)
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package feature
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+func TestTogglesPerProfile(t *testing.T) {
+	cfg := config.Default()
+	toggles := New(cfg)
+
+	if toggles.Enabled(RAG, "user-1") {
+		t.Fatal("既定では無効のはず")
+	}
+
+	if err := toggles.SetProfileEnabled(RAG, "user-1", true); err != nil {
+		t.Fatal(err)
+	}
+	if !toggles.Enabled(RAG, "user-1") || toggles.Enabled(RAG, "user-2") {
+		t.Error("指定したプロフィールだけで有効になるはず")
+	}
+	if got := toggles.Scope(RAG); got != ScopeProfile {
+		t.Errorf("Scope = %q, want %q", got, ScopeProfile)
+	}
+
+	if err := toggles.SetEnabled(RAG, true); err != nil {
+		t.Fatal(err)
+	}
+	if !toggles.Enabled(RAG, "user-2") || toggles.Scope(RAG) != ScopeAll {
+		t.Error("全員で有効になるはず")
+	}
+
+	if err := toggles.SetEnabled(RAG, false); err != nil {
+		t.Fatal(err)
+	}
+	if !toggles.Enabled(RAG, "user-1") || toggles.Enabled(RAG, "user-2") {
+		t.Error("全員の指定を外してもプロフィールごとの指定は残るはず")
+	}
+	if err := toggles.SetProfileEnabled(RAG, "user-1", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Features[RAG]; ok {
+		t.Error("無効のフラグは設定に残さないはず")
+	}
+
+	if err := toggles.SetEnabled("unknown", true); err == nil {
+		t.Error("未知のフラグはエラーになるはず")
+	}
+}
+
+func TestAuthorizerBlocksChanges(t *testing.T) {
+	cfg := config.Default()
+	toggles := New(cfg)
+	denied := errors.New("権限がありません")
+	toggles.SetAuthorizer(func() error { return denied })
+
+	if err := toggles.SetEnabled(Classroom, true); !errors.Is(err, denied) {
+		t.Errorf("SetEnabled = %v", err)
+	}
+	if err := toggles.SetProfileEnabled(Classroom, "user-1", true); !errors.Is(err, denied) {
+		t.Errorf("SetProfileEnabled = %v", err)
+	}
+	if toggles.Enabled(Classroom, "user-1") {
+		t.Error("確かめる関数がエラーを返したら切り替えないはず")
+	}
+}
+
+func TestSummaryListsUnknownFlags(t *testing.T) {
+	cfg := config.Default()
+	cfg.Features = map[string]config.FeatureConfig{Sync: {Enabled: true}, "old_feature": {Enabled: true}}
+	summary := New(cfg).Summary("user-1")
+
+	for _, want := range []string{"sync: 有効（all）", "rag: 無効（off）", "old_feature: 未知のフラグ"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("診断情報に %q がない:\n%s", want, summary)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package feedback
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// IssueRepository 問い合わせを受け付けるGitHubのリポジトリ
+const IssueRepository = "okamyuji/studybuddy-ai"
+
+// FileExtension 問い合わせのファイルの拡張子（中身は本文・ログ・画像をまとめたzip）
+const FileExtension = ".zip"
+
+// 問い合わせの種類
+const (
+	KindBug     = "bug"     // うまく動かない
+	KindRequest = "request" // こうしてほしい
+	KindOther   = "other"   // そのほか
+)
+
+// Kinds 問い合わせの種類（画面に並べる順）
+var Kinds = []string{KindBug, KindRequest, KindOther}
+
+// KindLabels 問い合わせの種類の表示名
+var KindLabels = map[string]string{
+	KindBug:     "うまく動かない",
+	KindRequest: "こうしてほしい",
+	KindOther:   "そのほか",
+}
+
+// 添付の上限
+const (
+	MaxScreenshots     = 5
+	MaxScreenshotBytes = 10 << 20
+	maxIssueBodyRunes  = 4000 // GitHubのissueの作成画面のURLが長くなりすぎないようにする
+)
+
+// Attachment 問い合わせのファイルに入れる添付（画像・クラッシュレポート）
+type Attachment struct {
+	Name string
+	Data []byte
+}
+
+// Report 問い合わせの内容（個人情報は作る前に取り除いておく）
+type Report struct {
+	Kind         string
+	Description  string
+	Version      string
+	Diagnostics  string // 診断情報（動作の状態）
+	Logs         string // 最近のログ
+	Screenshots  []Attachment
+	CrashReports []Attachment
+	CreatedAt    time.Time
+}
+
+// Title GitHubのissueのタイトル（説明の1行目を短くしたもの）
+func (r *Report) Title() string {
+	line, _, _ := strings.Cut(strings.TrimSpace(r.Description), "\n")
+	if runes := []rune(line); len(runes) > 40 {
+		line = string(runes[:40]) + "…"
+	}
+	return fmt.Sprintf("[%s] %s", KindLabels[r.Kind], line)
+}
+
+// Summary 問い合わせの本文（種類・説明・バージョン・環境・診断情報。ログと画像は含めない）
+func (r *Report) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n%s\n\n", KindLabels[r.Kind], strings.TrimSpace(r.Description))
+	b.WriteString("## 環境\n\n")
+	fmt.Fprintf(&b, "- バージョン: %s\n", r.Version)
+	fmt.Fprintf(&b, "- OS: %s/%s %s\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+	fmt.Fprintf(&b, "- 日時: %s\n", r.CreatedAt.Format(time.RFC3339))
+	if r.Diagnostics != "" {
+		fmt.Fprintf(&b, "\n## 診断情報\n\n```\n%s\n```\n", strings.TrimSpace(r.Diagnostics))
+	}
+	return b.String()
+}
+
+// WriteZip 問い合わせの内容を1つのzipにまとめる
+// （report.md・logs.txt・crash/のクラッシュレポート・screenshots/の画像。ないものは入れない）
+func (r *Report) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	files := []Attachment{{Name: "report.md", Data: []byte(r.Summary())}}
+	if r.Logs != "" {
+		files = append(files, Attachment{Name: "logs.txt", Data: []byte(r.Logs)})
+	}
+	for _, report := range r.CrashReports {
+		files = append(files, Attachment{Name: "crash/" + report.Name, Data: report.Data})
+	}
+	for _, screenshot := range r.Screenshots {
+		files = append(files, Attachment{Name: "screenshots/" + screenshot.Name, Data: screenshot.Data})
+	}
+
+	for _, file := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: file.Name, Method: zip.Deflate, Modified: r.CreatedAt})
+		if err != nil {
+			return fmt.Errorf("問い合わせのファイル作成エラー: %w", err)
+		}
+		if _, err := fw.Write(file.Data); err != nil {
+			return fmt.Errorf("問い合わせのファイル作成エラー: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("問い合わせのファイル作成エラー: %w", err)
+	}
+	return nil
+}
+
+// IssueURL GitHubのissueの作成画面のURL（タイトルと本文を入れておく。ログと画像は保存したzipを添付してもらう）
+func (r *Report) IssueURL() string {
+	body := r.Summary()
+	if runes := []rune(body); len(runes) > maxIssueBodyRunes {
+		body = string(runes[:maxIssueBodyRunes]) + "\n…（続きは添付のファイルにあります）\n"
+	}
+	body += "\n<!-- ログと画面の画像は、アプリで保存した問い合わせのファイル（.zip）をここにドラッグして添付してください -->\n"
+
+	query := url.Values{}
+	query.Set("title", r.Title())
+	query.Set("body", body)
+	return fmt.Sprintf("https://github.com/%s/issues/new?%s", IssueRepository, query.Encode())
+}
+
+// FileName 保存する問い合わせのファイルの名前（例: studybuddy-feedback-20260102-150405.zip）
+func (r *Report) FileName() string {
+	return "studybuddy-feedback-" + r.CreatedAt.Format("20060102-150405") + FileExtension
+}
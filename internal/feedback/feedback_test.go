@@ -0,0 +1,105 @@
+package feedback
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testReport() *Report {
+	return &Report{
+		Kind:         KindBug,
+		Description:  "問題が表示されないことがあります\n数学を選んだときに起きます",
+		Version:      "1.2.3",
+		Diagnostics:  "AI: 🟢 接続中\nモデル: gemma3",
+		Logs:         `{"level":"ERROR","msg":"問題生成エラー"}`,
+		Screenshots:  []Attachment{{Name: "screen-1.png", Data: []byte("png")}},
+		CrashReports: []Attachment{{Name: "crash-20260102-150405.000.txt", Data: []byte("panic")}},
+		CreatedAt:    time.Date(2026, 1, 2, 15, 4, 5, 0, time.Local),
+	}
+}
+
+func TestReportZip(t *testing.T) {
+	report := testReport()
+	var buf bytes.Buffer
+	if err := report.WriteZip(&buf); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	var names []string
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		files[file.Name] = string(data)
+		names = append(names, file.Name)
+	}
+	want := []string{"report.md", "logs.txt", "crash/crash-20260102-150405.000.txt", "screenshots/screen-1.png"}
+	if !slices.Equal(names, want) {
+		t.Errorf("zipの中身 = %v, want %v", names, want)
+	}
+	for _, text := range []string{"うまく動かない", "数学を選んだときに起きます", "バージョン: 1.2.3", "モデル: gemma3"} {
+		if !strings.Contains(files["report.md"], text) {
+			t.Errorf("本文に %q がありません:\n%s", text, files["report.md"])
+		}
+	}
+	if report.FileName() != "studybuddy-feedback-20260102-150405.zip" {
+		t.Errorf("ファイル名 = %s", report.FileName())
+	}
+
+	// ログがなければ入れない
+	report.Logs, report.Screenshots, report.CrashReports = "", nil, nil
+	buf.Reset()
+	if err := report.WriteZip(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if zr, _ := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len())); len(zr.File) != 1 {
+		t.Errorf("本文だけのzipのファイル数 = %d", len(zr.File))
+	}
+}
+
+func TestReportIssueURL(t *testing.T) {
+	report := testReport()
+	u, err := url.Parse(report.IssueURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "github.com" || u.Path != "/"+IssueRepository+"/issues/new" {
+		t.Errorf("issueのURL = %s", u)
+	}
+	query := u.Query()
+	if query.Get("title") != "[うまく動かない] 問題が表示されないことがあります" {
+		t.Errorf("タイトル = %q", query.Get("title"))
+	}
+	if body := query.Get("body"); !strings.Contains(body, "バージョン: 1.2.3") || strings.Contains(body, "問題生成エラー") {
+		t.Errorf("本文（ログは含めない）= %q", body)
+	}
+
+	// 長い説明は切りつめる
+	report.Description = strings.Repeat("あ", maxIssueBodyRunes*2)
+	if body := []rune(mustQuery(t, report.IssueURL()).Get("body")); len(body) > maxIssueBodyRunes+200 {
+		t.Errorf("本文が長すぎます: %d文字", len(body))
+	}
+}
+
+func mustQuery(t *testing.T, rawURL string) url.Values {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Query()
+}
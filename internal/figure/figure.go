@@ -0,0 +1,271 @@
+package figure
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// 座標平面の範囲（x・yとも -PlaneRange〜PlaneRange）と画像の大きさ
+const (
+	PlaneRange = 5
+	cellSize   = 30  // 座標平面の1目盛りの幅（拡大前のピクセル）
+	margin     = 24  // 図の周りの余白（拡大前のピクセル）
+	barWidth   = 40  // 棒グラフの棒の幅（拡大前のピクセル）
+	barGap     = 24  // 棒グラフの棒の間隔（拡大前のピクセル）
+	barHeight  = 240 // 棒グラフの縦軸の長さ（拡大前のピクセル）
+	scale      = 2   // 文字を読みやすくするため、描いた図を拡大する倍率
+)
+
+// 図の色
+var (
+	white     = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	black     = color.RGBA{A: 0xff}
+	gridColor = color.RGBA{0xd0, 0xd0, 0xd0, 0xff}
+	lineColor = color.RGBA{0x1f, 0x5f, 0xbf, 0xff}
+	barColor  = color.RGBA{0x6f, 0x9f, 0xdf, 0xff}
+)
+
+// Curve 座標平面に描くグラフ y = A x² + B x + C（Aが0なら直線）
+type Curve struct {
+	A, B, C float64
+}
+
+// Figure 問題に使う図（Curvesがあれば座標平面のグラフ、なければ棒グラフ）
+// 画像の文字は半角英数字だけなので、日本語の説明はCaptionに書いて画像の下に表示する
+type Figure struct {
+	Caption string    // 図の説明（例: 「A〜Eの地域の人口（万人）」）
+	Curves  []Curve   // 座標平面に描くグラフ
+	Labels  []string  // 棒グラフの項目名（半角英数字）
+	Values  []float64 // 棒グラフの値（0以上）
+	Step    float64   // 棒グラフの縦軸の目盛りの間隔
+}
+
+// Expression グラフの式（例: "y = 2x + 1"、"y = (1/2)x²"）
+func (c Curve) Expression() string {
+	var terms []string
+	for _, term := range []struct {
+		coefficient float64
+		variable    string
+	}{{c.A, "x²"}, {c.B, "x"}, {c.C, ""}} {
+		if term.coefficient == 0 {
+			continue
+		}
+		text := formatNumber(math.Abs(term.coefficient))
+		switch {
+		case text == "1" && term.variable != "":
+			text = ""
+		case strings.Contains(text, "/") && term.variable != "":
+			text = "(" + text + ")"
+		}
+		sign := " + "
+		if term.coefficient < 0 {
+			sign = " - "
+		}
+		terms = append(terms, sign+text+term.variable)
+	}
+	if len(terms) == 0 {
+		return "y = 0"
+	}
+	expression := strings.Join(terms, "")
+	if strings.HasPrefix(expression, " - ") {
+		return "y = -" + expression[3:]
+	}
+	return "y = " + expression[3:]
+}
+
+// Describe 図の内容の文章（AIに問題を作ってもらうときに、図といっしょに渡す）
+func (f Figure) Describe() string {
+	var b strings.Builder
+	if f.Caption != "" {
+		fmt.Fprintf(&b, "図の説明: %s\n", f.Caption)
+	}
+	if len(f.Curves) > 0 {
+		fmt.Fprintf(&b, "x軸・y軸とも%dから%dまでの座標平面（1目盛りは1）に、次のグラフを描いた図:\n", -PlaneRange, PlaneRange)
+		for _, curve := range f.Curves {
+			fmt.Fprintf(&b, "- %s\n", curve.Expression())
+		}
+		return b.String()
+	}
+	fmt.Fprintf(&b, "縦軸の目盛りが%sごとの棒グラフ:\n", formatNumber(f.Step))
+	for i, label := range f.Labels {
+		fmt.Fprintf(&b, "- %s: %s\n", label, formatNumber(f.Values[i]))
+	}
+	return b.String()
+}
+
+// Render 図を白い背景の画像にする
+func Render(f Figure) *image.RGBA {
+	var img *image.RGBA
+	if len(f.Curves) > 0 {
+		img = renderPlane(f.Curves)
+	} else {
+		img = renderBars(f.Labels, f.Values, f.Step)
+	}
+	return enlarge(img)
+}
+
+// EncodePNG 図をPNG画像にする
+func EncodePNG(f Figure) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, Render(f)); err != nil {
+		return nil, fmt.Errorf("図の画像変換エラー: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderPlane 座標平面にグラフを描く
+func renderPlane(curves []Curve) *image.RGBA {
+	size := 2*margin + 2*PlaneRange*cellSize
+	img := newCanvas(size, size)
+	origin := margin + PlaneRange*cellSize
+	toPixel := func(x, y float64) (float64, float64) {
+		return float64(origin) + x*cellSize, float64(origin) - y*cellSize
+	}
+
+	for i := -PlaneRange; i <= PlaneRange; i++ {
+		p := origin + i*cellSize
+		c := gridColor
+		if i == 0 {
+			c = black
+		}
+		drawSegment(img, float64(p), float64(margin), float64(p), float64(size-margin), 1, c)
+		drawSegment(img, float64(margin), float64(p), float64(size-margin), float64(p), 1, c)
+		if i != 0 {
+			x, y := strconv.Itoa(i), strconv.Itoa(-i) // 画像のyは下向きなので、上がyの正の向き
+			drawText(img, x, p-len(x)*7/2, origin+14)
+			drawText(img, y, origin-6-len(y)*7, p+4)
+		}
+	}
+	drawText(img, "O", origin-10, origin+14)
+	drawText(img, "x", size-margin+6, origin+4)
+	drawText(img, "y", origin-3, margin-8)
+
+	// グラフは細かく区切った折れ線で描き、座標平面の外は描かない
+	const steps = 400
+	for _, curve := range curves {
+		var prevX, prevY float64
+		prevInside := false
+		for i := 0; i <= steps; i++ {
+			x := -PlaneRange + 2*PlaneRange*float64(i)/steps
+			y := curve.A*x*x + curve.B*x + curve.C
+			inside := math.Abs(y) <= PlaneRange
+			px, py := toPixel(x, y)
+			if inside && prevInside {
+				drawSegment(img, prevX, prevY, px, py, 2, lineColor)
+			}
+			prevX, prevY, prevInside = px, py, inside
+		}
+	}
+	return img
+}
+
+// renderBars 棒グラフを描く（縦軸は値の最大を含む目盛りまで）
+func renderBars(labels []string, values []float64, step float64) *image.RGBA {
+	if step <= 0 {
+		step = 1
+	}
+	top := step
+	for _, value := range values {
+		top = max(top, math.Ceil(value/step)*step)
+	}
+	axisLeft := margin + 32
+	width := axisLeft + len(labels)*(barWidth+barGap) + barGap + margin
+	height := 2*margin + barHeight + 16
+	img := newCanvas(width, height)
+	bottom := margin + barHeight
+	toPixel := func(value float64) int {
+		return bottom - int(math.Round(value/top*barHeight))
+	}
+
+	for value := 0.0; value <= top+step/2; value += step {
+		y := toPixel(value)
+		drawSegment(img, float64(axisLeft), float64(y), float64(width-margin), float64(y), 1, gridColor)
+		text := formatNumber(value)
+		drawText(img, text, axisLeft-6-len(text)*7, y+4)
+	}
+	for i, label := range labels {
+		left := axisLeft + barGap + i*(barWidth+barGap)
+		if i < len(values) {
+			fillRect(img, left, toPixel(values[i]), left+barWidth, bottom, barColor)
+		}
+		drawText(img, label, left+(barWidth-len(label)*7)/2, bottom+16)
+	}
+	drawSegment(img, float64(axisLeft), float64(margin), float64(axisLeft), float64(bottom), 1, black)
+	drawSegment(img, float64(axisLeft), float64(bottom), float64(width-margin), float64(bottom), 1, black)
+	return img
+}
+
+// newCanvas 白い背景の画像
+func newCanvas(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	return img
+}
+
+// drawSegment 2点の間に線を描く
+func drawSegment(img *image.RGBA, x0, y0, x1, y1 float64, width int, c color.RGBA) {
+	steps := max(int(math.Ceil(math.Hypot(x1-x0, y1-y0))), 1)
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x, y := int(math.Round(x0+(x1-x0)*t)), int(math.Round(y0+(y1-y0)*t))
+		fillRect(img, x-(width-1)/2, y-(width-1)/2, x+width/2+1, y+width/2+1, c)
+	}
+}
+
+// fillRect 長方形を塗る（画像の外は塗らない）
+func fillRect(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	rect := image.Rect(x0, y0, x1, y1).Intersect(img.Rect)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// drawText 半角英数字を書く（x, yは文字の左下）
+func drawText(img *image.RGBA, text string, x, y int) {
+	drawer := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(black),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
+
+// enlarge 画像を scale 倍に拡大する（画面やAIで文字を読みやすくするため）
+func enlarge(src *image.RGBA) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx()*scale, bounds.Dy()*scale))
+	for y := range dst.Rect.Dy() {
+		for x := range dst.Rect.Dx() {
+			dst.SetRGBA(x, y, src.RGBAAt(bounds.Min.X+x/scale, bounds.Min.Y+y/scale))
+		}
+	}
+	return dst
+}
+
+// formatNumber 数値を表示用の文字にする（0.5のような分数になる値は 1/2 のように書く）
+func formatNumber(value float64) string {
+	if value == math.Trunc(value) {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	for _, denominator := range []float64{2, 3, 4, 5} {
+		if numerator := value * denominator; math.Abs(numerator-math.Round(numerator)) < 1e-9 {
+			return fmt.Sprintf("%s/%s", formatNumber(math.Round(numerator)), formatNumber(denominator))
+		}
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,68 @@
+package figure
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestCurveExpression(t *testing.T) {
+	tests := []struct {
+		curve Curve
+		want  string
+	}{
+		{Curve{B: 2, C: 1}, "y = 2x + 1"},
+		{Curve{B: -1, C: -3}, "y = -x - 3"},
+		{Curve{B: -2}, "y = -2x"},
+		{Curve{A: 0.5}, "y = (1/2)x²"},
+		{Curve{A: -1, C: 4}, "y = -x² + 4"},
+		{Curve{}, "y = 0"},
+	}
+	for _, tt := range tests {
+		if got := tt.curve.Expression(); got != tt.want {
+			t.Errorf("Expression(%+v) = %q, want %q", tt.curve, got, tt.want)
+		}
+	}
+}
+
+func TestRenderPlane(t *testing.T) {
+	img := Render(Figure{Curves: []Curve{{B: 1}}})
+	size := (2*margin + 2*PlaneRange*cellSize) * scale
+	if img.Rect.Dx() != size || img.Rect.Dy() != size {
+		t.Fatalf("画像の大きさ = %v", img.Rect)
+	}
+	// y = x は原点から1目盛り右上の点 (1, 1) を通る
+	origin := (margin + PlaneRange*cellSize) * scale
+	if got := img.RGBAAt(origin+cellSize*scale, origin-cellSize*scale); got != lineColor {
+		t.Errorf("(1, 1) の色 = %v、グラフの色のはず", got)
+	}
+	// 座標平面の外は描かない
+	if got := img.RGBAAt(0, 0); got != white {
+		t.Errorf("左上の色 = %v、白のはず", got)
+	}
+}
+
+func TestRenderBars(t *testing.T) {
+	f := Figure{Labels: []string{"A", "B", "C"}, Values: []float64{10, 40, 25}, Step: 10}
+	data, err := EncodePNG(f)
+	if err != nil {
+		t.Fatalf("PNG変換エラー: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("PNG読み込みエラー: %v", err)
+	}
+	// 一番高い棒（B）の上端が縦軸の一番上の目盛りになる
+	x := (margin + 32 + barGap + (barWidth + barGap) + barWidth/2) * scale
+	if r, g, b, _ := img.At(x, (margin+2)*scale).RGBA(); uint8(r>>8) != barColor.R || uint8(g>>8) != barColor.G || uint8(b>>8) != barColor.B {
+		t.Errorf("Bの棒の上端の色 = (%d, %d, %d)", r>>8, g>>8, b>>8)
+	}
+
+	description := f.Describe()
+	for _, want := range []string{"10ごと", "A: 10", "B: 40", "C: 25"} {
+		if !strings.Contains(description, want) {
+			t.Errorf("図の説明に %q がない:\n%s", want, description)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package flashcards
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 自己採点（SM-2の評価値 0-5 のうち、ボタンで選べるもの）
+const (
+	GradeAgain = 1 // もう一度（思い出せなかった）
+	GradeHard  = 3 // 難しい（なんとか思い出せた）
+	GradeGood  = 4 // 普通
+	GradeEasy  = 5 // 簡単
+)
+
+// SM-2の計算ルール
+const (
+	initialEaseFactor = 2.5
+	minEaseFactor     = 1.3
+	passingGrade      = 3 // これ未満は覚え直し
+)
+
+// DeckNames デッキの種類ごとの名前
+var DeckNames = map[string]string{
+	ai.FlashcardVocab: "英単語",
+	ai.FlashcardKanji: "漢字",
+}
+
+// DeckKinds デッキの種類（表示順）
+var DeckKinds = []string{ai.FlashcardVocab, ai.FlashcardKanji}
+
+// DeckSubjects デッキの種類ごとの関係する科目（苦手な単元の取得用）
+var DeckSubjects = map[string]string{
+	ai.FlashcardVocab: "英語",
+	ai.FlashcardKanji: "国語",
+}
+
+// Schedule SM-2で自己採点から次の復習日を決める
+func Schedule(card *database.Flashcard, grade int, now time.Time) {
+	if card.EaseFactor == 0 {
+		card.EaseFactor = initialEaseFactor
+	}
+
+	if grade < passingGrade {
+		card.Repetitions = 0
+		card.IntervalDays = 1
+	} else {
+		switch card.Repetitions {
+		case 0:
+			card.IntervalDays = 1
+		case 1:
+			card.IntervalDays = 6
+		default:
+			card.IntervalDays = int(math.Round(float64(card.IntervalDays) * card.EaseFactor))
+		}
+		card.Repetitions++
+	}
+
+	q := float64(5 - grade)
+	card.EaseFactor = math.Max(card.EaseFactor+0.1-q*(0.08+q*0.02), minEaseFactor)
+
+	card.DueAt = now.AddDate(0, 0, card.IntervalDays)
+	card.LastReviewed = &now
+}
+
+// DeckStatus デッキのカード数
+type DeckStatus struct {
+	Deck  *database.FlashcardDeck
+	Total int
+	Due   int
+}
+
+// Manager 単語カードの管理
+type Manager struct {
+	db       *database.DB
+	aiEngine *ai.Engine
+}
+
+// NewManager 単語カード管理システムを作成
+func NewManager(db *database.DB, aiEngine *ai.Engine) *Manager {
+	return &Manager{db: db, aiEngine: aiEngine}
+}
+
+// Deck 種類を指定してデッキを取得（まだなければ作成）
+func (m *Manager) Deck(userID, kind string) (*database.FlashcardDeck, error) {
+	deck, err := m.db.GetFlashcardDeck(userID, kind)
+	if err == nil {
+		return deck, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("デッキ取得エラー: %w", err)
+	}
+
+	deck = &database.FlashcardDeck{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      DeckNames[kind],
+		Kind:      kind,
+		CreatedAt: time.Now(),
+	}
+	if err := m.db.CreateFlashcardDeck(deck); err != nil {
+		return nil, fmt.Errorf("デッキ作成エラー: %w", err)
+	}
+	return deck, nil
+}
+
+// Status すべての種類のデッキのカード数を取得
+func (m *Manager) Status(userID string) ([]DeckStatus, error) {
+	var statuses []DeckStatus
+	now := time.Now()
+	for _, kind := range DeckKinds {
+		deck, err := m.Deck(userID, kind)
+		if err != nil {
+			return nil, err
+		}
+		total, due, err := m.db.CountFlashcards(deck.ID, now)
+		if err != nil {
+			return nil, fmt.Errorf("カード数取得エラー: %w", err)
+		}
+		statuses = append(statuses, DeckStatus{Deck: deck, Total: total, Due: due})
+	}
+	return statuses, nil
+}
+
+// DueCards 復習時期になったカードを取得
+func (m *Manager) DueCards(deckID string, limit int) ([]database.Flashcard, error) {
+	cards, err := m.db.GetDueFlashcards(deckID, time.Now(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("カード取得エラー: %w", err)
+	}
+	return cards, nil
+}
+
+// Review 自己採点を記録して次の復習日を決める
+func (m *Manager) Review(card *database.Flashcard, grade int) error {
+	Schedule(card, grade, time.Now())
+	if err := m.db.UpdateFlashcardSchedule(card); err != nil {
+		return fmt.Errorf("復習記録エラー: %w", err)
+	}
+	return nil
+}
+
+// Generate 学年と苦手な単元に合わせたカードをAIで作ってデッキに追加し、追加した枚数を返す
+func (m *Manager) Generate(ctx context.Context, deck *database.FlashcardDeck, grade int, weaknesses []string, count int) (int, error) {
+	exclude, err := m.db.GetFlashcardFronts(deck.ID)
+	if err != nil {
+		return 0, fmt.Errorf("カード取得エラー: %w", err)
+	}
+
+	contents := m.aiEngine.GenerateFlashcards(ctx, ai.FlashcardRequest{
+		Kind:       deck.Kind,
+		Grade:      grade,
+		Weaknesses: weaknesses,
+		Exclude:    exclude,
+		Count:      count,
+	})
+
+	return m.AddCards(deck.ID, contents)
+}
+
+// AddCards デッキにカードを追加し、追加した枚数を返す（同じ表面のカードがあれば追加しない）
+func (m *Manager) AddCards(deckID string, contents []ai.FlashcardContent) (int, error) {
+	added := 0
+	now := time.Now()
+	for _, content := range contents {
+		created, err := m.db.CreateFlashcard(&database.Flashcard{
+			ID:         uuid.New().String(),
+			DeckID:     deckID,
+			Front:      content.Front,
+			Back:       content.Back,
+			Hint:       content.Hint,
+			EaseFactor: initialEaseFactor,
+			DueAt:      now,
+			CreatedAt:  now,
+		})
+		if err != nil {
+			return added, fmt.Errorf("カード追加エラー: %w", err)
+		}
+		if created {
+			added++
+		}
+	}
+	return added, nil
+}
@@ -0,0 +1,124 @@
+package flashcards
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 復習のたまりの分散・先送りの日数
+const (
+	BalanceDays = 7 // 期限切れのカードを分ける日数（今日を含む）
+	SnoozeDays  = 1 // 期限切れのカードを先送りする日数
+)
+
+// Balance 期限切れのカードを、今日からdays日に分けて復習できるように次の復習日を変える（変えたカードを返す）
+//
+// 長い休みのあとなどに、期限切れのカードを1日にまとめて出さないための分け方:
+//  1. 期限切れのカードを、覚えが浅い順（復習の間隔が短い順、同じなら期限の古い順）に並べる
+//  2. 1日あたりの枚数の目安を「(期限切れの枚数 + その期間にもともと予定されている枚数) ÷ days」の切り上げにする
+//  3. 並べた順に、目安に達していないいちばん早い日へ入れる（今日に入れたカードは期限を変えず、すぐに復習できる）
+//
+// 覚えが浅いカードほど早く復習でき、どの日も目安の枚数前後になる。SM-2の間隔と易しさは変えない。
+func Balance(cards []database.Flashcard, now time.Time, days int) []database.Flashcard {
+	if days < 1 {
+		return nil
+	}
+	today := startOfDay(now)
+
+	var overdue []database.Flashcard
+	load := make([]int, days) // 日ごとにもともと予定されている枚数
+	for _, card := range cards {
+		if !card.DueAt.After(now) {
+			overdue = append(overdue, card)
+			continue
+		}
+		if day := daysBetween(today, card.DueAt); day < days {
+			load[day]++
+		}
+	}
+	if len(overdue) == 0 {
+		return nil
+	}
+	sort.SliceStable(overdue, func(i, j int) bool {
+		if overdue[i].IntervalDays != overdue[j].IntervalDays {
+			return overdue[i].IntervalDays < overdue[j].IntervalDays
+		}
+		return overdue[i].DueAt.Before(overdue[j].DueAt)
+	})
+
+	total := len(overdue)
+	for _, n := range load {
+		total += n
+	}
+	target := max((total+days-1)/days, 1)
+
+	var moved []database.Flashcard
+	day := 0
+	for _, card := range overdue {
+		for day < days-1 && load[day] >= target {
+			day++
+		}
+		load[day]++
+		if day == 0 {
+			continue
+		}
+		card.DueAt = today.AddDate(0, 0, day)
+		moved = append(moved, card)
+	}
+	return moved
+}
+
+// Snooze 期限切れのカードを、days日後の0時に先送りする（変えたカードを返す）
+func Snooze(cards []database.Flashcard, now time.Time, days int) []database.Flashcard {
+	dueAt := startOfDay(now).AddDate(0, 0, days)
+	var moved []database.Flashcard
+	for _, card := range cards {
+		if !card.DueAt.After(now) {
+			card.DueAt = dueAt
+			moved = append(moved, card)
+		}
+	}
+	return moved
+}
+
+// BalanceDeck デッキの期限切れのカードを、今日からBalanceDays日に分ける（先の日に回した枚数を返す）
+func (m *Manager) BalanceDeck(deckID string) (int, error) {
+	return m.reschedule(deckID, func(cards []database.Flashcard, now time.Time) []database.Flashcard {
+		return Balance(cards, now, BalanceDays)
+	})
+}
+
+// SnoozeDeck デッキの期限切れのカードをSnoozeDays日後に先送りする（先送りした枚数を返す）
+func (m *Manager) SnoozeDeck(deckID string) (int, error) {
+	return m.reschedule(deckID, func(cards []database.Flashcard, now time.Time) []database.Flashcard {
+		return Snooze(cards, now, SnoozeDays)
+	})
+}
+
+// reschedule デッキのカードの次の復習日を決め直して保存
+func (m *Manager) reschedule(deckID string, plan func([]database.Flashcard, time.Time) []database.Flashcard) (int, error) {
+	cards, err := m.db.GetFlashcards(deckID)
+	if err != nil {
+		return 0, fmt.Errorf("カード取得エラー: %w", err)
+	}
+	moved := plan(cards, time.Now())
+	if err := m.db.RescheduleFlashcards(moved); err != nil {
+		return 0, fmt.Errorf("復習日変更エラー: %w", err)
+	}
+	return len(moved), nil
+}
+
+// startOfDay 指定日時の日の0時
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween 0時のtodayから見て、tが何日後か
+func daysBetween(today, t time.Time) int {
+	// 夏時間で1日が23・25時間の日があっても日数がずれないよう、四捨五入する
+	return int(math.Round(startOfDay(t.In(today.Location())).Sub(today).Hours() / 24))
+}
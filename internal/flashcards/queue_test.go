@@ -0,0 +1,119 @@
+package flashcards
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+func TestBalanceSpreadsOverdueCards(t *testing.T) {
+	now := time.Date(2026, 10, 16, 18, 0, 0, 0, time.Local)
+	today := startOfDay(now)
+
+	// 休みのあとで200枚が期限切れ、3日後にもともと10枚の予定がある
+	var cards []database.Flashcard
+	for i := range 200 {
+		cards = append(cards, database.Flashcard{
+			ID:           fmt.Sprintf("overdue-%d", i),
+			IntervalDays: 1 + i%30,
+			DueAt:        now.AddDate(0, 0, -1-i%20),
+		})
+	}
+	for i := range 10 {
+		cards = append(cards, database.Flashcard{ID: fmt.Sprintf("later-%d", i), IntervalDays: 6, DueAt: today.AddDate(0, 0, 3).Add(9 * time.Hour)})
+	}
+
+	moved := Balance(cards, now, BalanceDays)
+
+	perDay := make(map[int]int)
+	perDay[3] = 10
+	movedIDs := make(map[string]bool)
+	for _, card := range moved {
+		movedIDs[card.ID] = true
+		day := daysBetween(today, card.DueAt)
+		if day < 1 || day >= BalanceDays || !card.DueAt.Equal(today.AddDate(0, 0, day)) {
+			t.Fatalf("%s の復習日 = %v", card.ID, card.DueAt)
+		}
+		perDay[day]++
+	}
+	perDay[0] = 200 - len(moved)
+
+	target := (200 + 10 + BalanceDays - 1) / BalanceDays // 30枚
+	for day := range BalanceDays {
+		if perDay[day] > target {
+			t.Errorf("%d日後の枚数 = %d（目安 %d枚）", day, perDay[day], target)
+		}
+	}
+	if perDay[0] != target {
+		t.Errorf("今日の枚数 = %d, want %d", perDay[0], target)
+	}
+
+	// 覚えが浅い（間隔が短い）カードは今日に残る
+	for _, card := range cards[:200] {
+		if card.IntervalDays == 1 && movedIDs[card.ID] {
+			t.Errorf("間隔1日のカード %s は今日復習するはず", card.ID)
+		}
+	}
+}
+
+func TestBalanceKeepsSmallQueue(t *testing.T) {
+	now := time.Date(2026, 10, 16, 18, 0, 0, 0, time.Local)
+	cards := []database.Flashcard{
+		{ID: "a", DueAt: now.Add(-time.Hour)},
+		{ID: "b", DueAt: now.AddDate(0, 0, 2)},
+	}
+	if moved := Balance(cards, now, BalanceDays); len(moved) != 0 {
+		t.Errorf("少ないときは先に回さないはず: %+v", moved)
+	}
+}
+
+func TestSnooze(t *testing.T) {
+	now := time.Date(2026, 10, 16, 18, 0, 0, 0, time.Local)
+	cards := []database.Flashcard{
+		{ID: "a", DueAt: now.AddDate(0, 0, -3)},
+		{ID: "b", DueAt: now.Add(time.Hour)},
+	}
+	moved := Snooze(cards, now, SnoozeDays)
+	if len(moved) != 1 || moved[0].ID != "a" || !moved[0].DueAt.Equal(time.Date(2026, 10, 17, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("先送りしたカード = %+v", moved)
+	}
+}
+
+func TestBalanceDeckSavesDueDates(t *testing.T) {
+	db := testutil.NewDB(t)
+	user := testutil.Seed(t, db, testutil.DefaultFixture(time.Now()))
+	manager := NewManager(db, nil)
+	deck, err := manager.Deck(user.ID, ai.FlashcardVocab)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 40 {
+		card := &database.Flashcard{
+			ID:         fmt.Sprintf("card-%d", i),
+			DeckID:     deck.ID,
+			Front:      fmt.Sprintf("word%d", i),
+			EaseFactor: initialEaseFactor,
+			DueAt:      time.Now().AddDate(0, 0, -10),
+			CreatedAt:  time.Now(),
+		}
+		if _, err := db.CreateFlashcard(card); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	moved, err := manager.BalanceDeck(deck.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total, due, err := db.CountFlashcards(deck.ID, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 40 || due != 40-moved || due != 6 {
+		t.Errorf("分けたあとの枚数: 全%d枚・今日%d枚（先に回した %d枚）", total, due, moved)
+	}
+}
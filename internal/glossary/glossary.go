@@ -0,0 +1,86 @@
+package glossary
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Term 用語集の1項目
+type Term struct {
+	Term       string `json:"term"`
+	Subject    string `json:"subject"`
+	Grade      int    `json:"grade"` // 習う学年
+	Topic      string `json:"topic"` // 関係する単元（学習指導要領の単元名）
+	Definition string `json:"definition"`
+}
+
+//go:embed glossary.json
+var glossaryJSON []byte
+
+// Glossary 用語集
+type Glossary struct {
+	terms []Term // 長い用語から順（「比例定数」を「比例」より先に見つける）
+}
+
+// Load 埋め込みの用語集を読み込む
+func Load() (*Glossary, error) {
+	var terms []Term
+	if err := json.Unmarshal(glossaryJSON, &terms); err != nil {
+		return nil, fmt.Errorf("用語集読み込みエラー: %w", err)
+	}
+	sort.SliceStable(terms, func(i, j int) bool {
+		return len(terms[i].Term) > len(terms[j].Term)
+	})
+	return &Glossary{terms: terms}, nil
+}
+
+// Find 文章に出てくる科目の用語を出てきた順に探す（同じ用語は1回だけ、重なる用語は長いほうを優先）
+func (g *Glossary) Find(subject, text string) []Term {
+	type match struct {
+		pos  int
+		term Term
+	}
+	var matches []match
+	covered := make([]bool, len(text))
+
+	for _, term := range g.terms {
+		if subject != "" && term.Subject != subject {
+			continue
+		}
+		for offset := 0; offset < len(text); {
+			i := strings.Index(text[offset:], term.Term)
+			if i < 0 {
+				break
+			}
+			start, end := offset+i, offset+i+len(term.Term)
+			if !anyCovered(covered[start:end]) {
+				for j := start; j < end; j++ {
+					covered[j] = true
+				}
+				matches = append(matches, match{pos: start, term: term})
+				break
+			}
+			offset = end
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].pos < matches[j].pos })
+	found := make([]Term, len(matches))
+	for i, m := range matches {
+		found[i] = m.term
+	}
+	return found
+}
+
+// anyCovered すでに別の用語として見つけた部分を含むか
+func anyCovered(covered []bool) bool {
+	for _, c := range covered {
+		if c {
+			return true
+		}
+	}
+	return false
+}
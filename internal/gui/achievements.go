@@ -0,0 +1,101 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/achievement"
+	"github.com/okamyuji/studybuddy-ai/internal/export"
+)
+
+// checkAchievements 学習記録から実績を判定（notifyがtrueなら新しい実績をダイアログで知らせる）
+func (m *MainApp) checkAchievements(level int, notify bool) {
+	userID := m.currentUser.ID
+	stats := achievement.Stats{Level: level}
+
+	totalProblems, err := m.db.CountProblemResults(userID)
+	if err != nil {
+		slog.Error("解答数取得エラー", "error", err)
+		return
+	}
+	stats.TotalProblems = totalProblems
+
+	if streak, err := m.progressManager.GetStudyStreak(userID); err == nil {
+		stats.CurrentStreak = streak.CurrentStreak
+		stats.LongestStreak = streak.LongestStreak
+	}
+
+	unlocked, err := m.achievements.Check(userID, stats)
+	if err != nil {
+		slog.Error("実績判定エラー", "error", err)
+	}
+	for _, a := range unlocked {
+		slog.Info("🏆 実績達成", "title", a.Title)
+		if notify {
+			m.showAchievementUnlocked(a)
+		}
+	}
+}
+
+// showAchievementUnlocked 実績の達成を知らせ、賞状の保存をすすめる
+func (m *MainApp) showAchievementUnlocked(a achievement.Achievement) {
+	content := widget.NewLabel(fmt.Sprintf("%s「%s」を達成しました！\n%s\n\n賞状をPDFで保存して印刷できます。", a.Emoji, a.Title, a.Description))
+	content.Wrapping = fyne.TextWrapWord
+
+	confirm := dialog.NewCustomConfirm("🏆 実績を達成しました", "賞状を保存", "あとで", content, func(save bool) {
+		if save {
+			m.saveCertificate(achievement.Earned{Achievement: a, EarnedAt: time.Now()})
+		}
+	}, m.window)
+	confirm.Resize(fyne.NewSize(400, 240))
+	confirm.Show()
+}
+
+// saveCertificate 実績の賞状をPDFで保存
+func (m *MainApp) saveCertificate(earned achievement.Earned) {
+	certificate := export.Certificate{
+		StudentName: m.currentUser.Name,
+		Title:       earned.Title,
+		Body:        earned.Description,
+		Date:        earned.EarnedAt,
+	}
+	m.savePDF(fmt.Sprintf("certificate_%s.pdf", earned.ID), func(w io.Writer, exporter *export.Exporter) error {
+		return exporter.WriteCertificatePDF(w, certificate)
+	})
+}
+
+// createAchievementsCard 実績一覧カードを作成（達成済みの実績は賞状を保存できる）
+func (m *MainApp) createAchievementsCard() *widget.Card {
+	earned, err := m.achievements.Earned(m.currentUser.ID)
+	if err != nil {
+		slog.Error("実績取得エラー", "error", err)
+	}
+	earnedByID := make(map[string]achievement.Earned)
+	for _, e := range earned {
+		earnedByID[e.ID] = e
+	}
+
+	list := container.NewVBox()
+	for _, a := range achievement.Achievements {
+		e, done := earnedByID[a.ID]
+		if !done {
+			list.Add(widget.NewLabel(fmt.Sprintf("🔒 %s（未達成）", a.Title)))
+			continue
+		}
+
+		printBtn := widget.NewButton("🖨 賞状", func() {
+			m.saveCertificate(e)
+		})
+		label := widget.NewLabel(fmt.Sprintf("%s %s（%s）", a.Emoji, a.Title, e.EarnedAt.Format("2006/01/02")))
+		list.Add(container.NewBorder(nil, nil, nil, printBtn, label))
+	}
+
+	return widget.NewCard("🏆 実績", fmt.Sprintf("%d / %d 達成", len(earned), len(achievement.Achievements)), list)
+}
@@ -0,0 +1,167 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// contextLengthOptions コンテキスト長の選択肢
+var contextLengthOptions = []int{2048, 4096, 8192, 16384, config.MaxContextLength}
+
+// keepAliveOptions 生成後にモデルをメモリに残す時間の選択肢
+var keepAliveOptions = []struct {
+	value string
+	label string
+}{
+	{"0s", "すぐに解放（メモリを節約）"},
+	{"5m", "5分"},
+	{"30m", "30分"},
+	{"1h", "1時間"},
+	{"-1m", "ずっと残す（次の問題が速い）"},
+}
+
+// 最大トークン数のスライダーの範囲
+const (
+	minMaxTokensSetting  = 256
+	maxMaxTokensSetting  = 8192
+	maxTokensSettingStep = 256
+)
+
+// createGenerationSettings AI設定の「詳細設定」（生成の温度・トップP・最大トークン数・コンテキスト長・モデルの保持時間）
+func (m *MainApp) createGenerationSettings() fyne.CanvasObject {
+	apply := func() {
+		m.aiEngine.SetGenerationOptions(m.config.AI)
+		m.saveConfig()
+	}
+
+	temperatureLabel := widget.NewLabel("")
+	temperatureSlider := widget.NewSlider(0, 1)
+	temperatureSlider.Step = 0.05
+	temperatureSlider.OnChanged = func(value float64) {
+		temperatureLabel.SetText(fmt.Sprintf("%.2f", value))
+	}
+	temperatureSlider.OnChangeEnded = func(value float64) {
+		m.config.AI.Temperature = value
+		apply()
+	}
+
+	topPLabel := widget.NewLabel("")
+	topPSlider := widget.NewSlider(0.05, 1)
+	topPSlider.Step = 0.05
+	topPSlider.OnChanged = func(value float64) {
+		topPLabel.SetText(fmt.Sprintf("%.2f", value))
+	}
+	topPSlider.OnChangeEnded = func(value float64) {
+		m.config.AI.TopP = value
+		apply()
+	}
+
+	maxTokensLabel := widget.NewLabel("")
+	maxTokensSlider := widget.NewSlider(minMaxTokensSetting, maxMaxTokensSetting)
+	maxTokensSlider.Step = maxTokensSettingStep
+	maxTokensSlider.OnChanged = func(value float64) {
+		maxTokensLabel.SetText(strconv.Itoa(int(value)))
+	}
+	maxTokensSlider.OnChangeEnded = func(value float64) {
+		m.config.AI.MaxTokens = int(value)
+		apply()
+	}
+
+	var contextLabels []string
+	for _, length := range contextLengthOptions {
+		contextLabels = append(contextLabels, strconv.Itoa(length))
+	}
+	contextSelect := widget.NewSelect(contextLabels, nil)
+
+	var keepAliveLabels []string
+	for _, option := range keepAliveOptions {
+		keepAliveLabels = append(keepAliveLabels, option.label)
+	}
+	keepAliveSelect := widget.NewSelect(keepAliveLabels, nil)
+
+	fallbackEntry := widget.NewEntry()
+	fallbackEntry.SetPlaceHolder("例: gemma2:2b（複数はカンマ区切り。Enterで保存）")
+	fallbackEntry.OnSubmitted = func(text string) {
+		m.config.AI.FallbackModels = parseModelList(text)
+		fallbackEntry.SetText(strings.Join(m.config.AI.FallbackModels, ", "))
+		apply()
+	}
+
+	// 設定の値を画面に反映（選択肢にない値は、選択を空にして設定ファイルの値のまま使う）
+	load := func() {
+		current := m.config.AI
+		temperatureSlider.SetValue(current.Temperature)
+		temperatureLabel.SetText(fmt.Sprintf("%.2f", current.Temperature))
+		topPSlider.SetValue(current.TopP)
+		topPLabel.SetText(fmt.Sprintf("%.2f", current.TopP))
+		maxTokensSlider.SetValue(float64(current.MaxTokens))
+		maxTokensLabel.SetText(strconv.Itoa(current.MaxTokens))
+
+		contextSelect.ClearSelected()
+		contextSelect.SetSelected(strconv.Itoa(current.ContextLength))
+		keepAliveSelect.ClearSelected()
+		for _, option := range keepAliveOptions {
+			if option.value == current.KeepAlive {
+				keepAliveSelect.SetSelected(option.label)
+			}
+		}
+		fallbackEntry.SetText(strings.Join(current.FallbackModels, ", "))
+	}
+	load()
+
+	// 画面に反映してから変更を受け付ける
+	contextSelect.OnChanged = func(string) {
+		if i := contextSelect.SelectedIndex(); i >= 0 {
+			m.config.AI.ContextLength = contextLengthOptions[i]
+			apply()
+		}
+	}
+	keepAliveSelect.OnChanged = func(string) {
+		if i := keepAliveSelect.SelectedIndex(); i >= 0 {
+			m.config.AI.KeepAlive = keepAliveOptions[i].value
+			apply()
+		}
+	}
+
+	resetBtn := widget.NewButton("初期値に戻す", func() {
+		defaults := config.Default().AI
+		m.config.AI.Temperature = defaults.Temperature
+		m.config.AI.TopP = defaults.TopP
+		m.config.AI.MaxTokens = defaults.MaxTokens
+		m.config.AI.ContextLength = defaults.ContextLength
+		m.config.AI.KeepAlive = defaults.KeepAlive
+		m.config.AI.FallbackModels = defaults.FallbackModels
+		load()
+		apply()
+	})
+
+	note := widget.NewLabel("ローカルのAI（Ollama）の生成に使います。温度を上げると問題の言い回しが多様になり、下げると安定します。コンテキスト長を大きくすると長い資料を扱えますが、メモリを多く使います。予備のモデルは、使っているモデルで生成に続けて失敗したときに順に使います。")
+	note.Wrapping = fyne.TextWrapWord
+
+	form := widget.NewForm(
+		widget.NewFormItem("温度", container.NewBorder(nil, nil, nil, temperatureLabel, temperatureSlider)),
+		widget.NewFormItem("トップP", container.NewBorder(nil, nil, nil, topPLabel, topPSlider)),
+		widget.NewFormItem("最大トークン数", container.NewBorder(nil, nil, nil, maxTokensLabel, maxTokensSlider)),
+		widget.NewFormItem("コンテキスト長", contextSelect),
+		widget.NewFormItem("モデルの保持時間", keepAliveSelect),
+		widget.NewFormItem("予備のモデル", fallbackEntry),
+	)
+
+	return widget.NewAccordion(widget.NewAccordionItem("詳細設定",
+		container.NewVBox(note, form, container.NewHBox(resetBtn))))
+}
+
+// parseModelList カンマ・読点・空白で区切ったモデル名の一覧
+func parseModelList(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '、' || unicode.IsSpace(r)
+	})
+}
@@ -0,0 +1,198 @@
+package gui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// askImageTimeout 写真の読み取りを待つ時間（画像対応モデルは読み込みに時間がかかる）
+const askImageTimeout = 3 * time.Minute
+
+// AskView 質問する画面（教科書やプリントの写真から問題を読み取り、AIが手順に分けて解説する）
+type AskView struct {
+	container     *fyne.Container
+	subjectSelect *widget.Select
+	photo         *fyne.Container // 選んだ写真の表示
+	question      *widget.Entry
+	status        *widget.Label
+	result        *widget.RichText
+	photoBtn      *widget.Button
+	explainBtn    *widget.Button
+	saveBtn       *widget.Button
+	explanation   *ai.CaptureExplanation // 表示中の解説（問題を書き換えたらnil）
+}
+
+// createAskView 質問する画面を作成
+func (m *MainApp) createAskView() *AskView {
+	view := &AskView{photo: container.NewStack()}
+
+	view.subjectSelect = widget.NewSelect(append([]string{captureSubjectAuto}, m.config.OrderedSubjects()...), nil)
+	view.subjectSelect.SetSelected(captureSubjectAuto)
+
+	view.question = widget.NewMultiLineEntry()
+	view.question.SetPlaceHolder("写真を選ぶと、読み取った問題がここに入ります（直接入力もできます）")
+	view.question.Wrapping = fyne.TextWrapWord
+	view.question.SetMinRowsVisible(5)
+	view.question.OnChanged = func(string) {
+		// 問題を書き換えたら、前の解説は保存しない
+		view.explanation = nil
+		view.saveBtn.Disable()
+		view.saveBtn.SetText("💾 問題バンクに保存")
+	}
+
+	view.status = widget.NewLabel(fmt.Sprintf("写真は画像対応モデル（%s）で、このパソコンの中だけで読み取ります。", m.aiEngine.VisionModel()))
+	view.status.Wrapping = fyne.TextWrapWord
+
+	view.result = widget.NewRichTextFromMarkdown("")
+	view.result.Wrapping = fyne.TextWrapWord
+
+	view.photoBtn = widget.NewButton("📷 写真を選ぶ", m.chooseAskPhoto)
+	view.explainBtn = widget.NewButton("🤖 手順を追って解説してもらう", m.explainAskQuestion)
+	view.explainBtn.Importance = widget.HighImportance
+	view.saveBtn = widget.NewButton("💾 問題バンクに保存", m.saveAskQuestion)
+	view.saveBtn.Disable()
+	historyBtn := widget.NewButton("📜 これまでの質問を見る", m.showTranscripts)
+
+	view.container = container.NewVBox(
+		widget.NewCard("❓ 質問する", "教科書やプリントの写真から問題を読み取り、AIが手順に分けて解説します", container.NewVBox(
+			widget.NewForm(widget.NewFormItem("教科", view.subjectSelect)),
+			view.photoBtn,
+			view.photo,
+			view.status,
+			view.question,
+			container.NewGridWithColumns(2, view.explainBtn, view.saveBtn),
+			historyBtn,
+		)),
+		view.result,
+	)
+	return view
+}
+
+// chooseAskPhoto 問題の写真を選んでもらい、画像対応モデルで文字を読み取る
+func (m *MainApp) chooseAskPhoto() {
+	view := m.askView
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("ファイルの選択に失敗しました: %v", err))
+			return
+		}
+		if reader == nil {
+			return // キャンセル
+		}
+		data, err := io.ReadAll(io.LimitReader(reader, ai.MaxImageBytes+1))
+		_ = reader.Close()
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("写真を読み込めませんでした: %v", err))
+			return
+		}
+
+		img := canvas.NewImageFromReader(bytes.NewReader(data), reader.URI().Name())
+		img.FillMode = canvas.ImageFillContain
+		img.SetMinSize(fyne.NewSize(320, 240))
+		view.photo.Objects = []fyne.CanvasObject{img}
+		view.photo.Refresh()
+
+		view.photoBtn.Disable()
+		view.explainBtn.Disable()
+		view.status.SetText("📷 写真の文字を読み取っています...（初めて使うときは時間がかかります）")
+		m.goSafe("写真の読み取り", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), askImageTimeout)
+			defer cancel()
+			text, err := m.aiEngine.ReadProblemImage(ctx, data)
+			fyne.Do(func() {
+				view.photoBtn.Enable()
+				view.explainBtn.Enable()
+				if err != nil {
+					slog.Error("写真の読み取りエラー", "error", err)
+					view.status.SetText(askPhotoErrorMessage(err, m.aiEngine.VisionModel()))
+					return
+				}
+				view.question.SetText(text)
+				view.result.ParseMarkdown("")
+				view.status.SetText("読み取った問題がちがっていたら、直してから解説してもらいましょう。")
+			})
+		}, func() {
+			view.photoBtn.Enable()
+			view.explainBtn.Enable()
+		})
+	}, m.window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg"}))
+	openDialog.Show()
+}
+
+// askPhotoErrorMessage 写真を読み取れなかったときの案内
+func askPhotoErrorMessage(err error, model string) string {
+	switch {
+	case errors.Is(err, ai.ErrImageFormat), errors.Is(err, ai.ErrImageTooLarge):
+		return err.Error()
+	case errors.Is(err, ai.ErrImageUnreadable):
+		return "写真から問題の文字を読み取れませんでした。明るい場所で、問題だけが大きく写るように撮り直すか、問題を入力してください。"
+	}
+	return fmt.Sprintf("写真を読み取れませんでした。Ollamaが起動しているか、画像対応モデルを「ollama pull %s」で入れてあるか確かめてください。問題を入力して質問することもできます。", model)
+}
+
+// explainAskQuestion 読み取った問題をAIに手順を追って解説してもらう
+func (m *MainApp) explainAskQuestion() {
+	view := m.askView
+	if strings.TrimSpace(view.question.Text) == "" {
+		view.result.ParseMarkdown("写真を選ぶか、問題を入力してください。")
+		return
+	}
+	req := ai.CaptureRequest{
+		Question:   view.question.Text,
+		Grade:      m.currentUser.Grade,
+		Boundaries: m.config.Parent.Boundaries,
+		StepByStep: true,
+	}
+	if view.subjectSelect.Selected != captureSubjectAuto {
+		req.Subject = view.subjectSelect.Selected
+	}
+
+	transcript := m.newTranscript(database.TranscriptSourceAsk, req)
+	view.explainBtn.Disable()
+	view.saveBtn.Disable()
+	view.saveBtn.SetText("💾 問題バンクに保存")
+	view.result.ParseMarkdown("**AIが解説を作っています...**")
+
+	m.goSafe("解説の作成", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		e := m.explainQuestion(ctx, req, transcript)
+		fyne.Do(func() {
+			view.explanation = e
+			view.explainBtn.Enable()
+			if e.Refused == nil {
+				view.saveBtn.Enable() // 範囲外の質問は問題バンクに保存しない
+			}
+			view.result.ParseMarkdown(captureMarkdown(e))
+		})
+	}, func() {
+		view.explainBtn.Enable()
+		view.result.ParseMarkdown("")
+	})
+}
+
+// saveAskQuestion 読み取った問題と解説を問題バンクに保存
+func (m *MainApp) saveAskQuestion() {
+	view := m.askView
+	m.saveQuestionInBackground(view.saveBtn, view.subjectSelect.Selected, view.question.Text, view.explanation, func(err error) {
+		m.ShowErrorDialog("エラー", fmt.Sprintf("保存できませんでした: %v", err))
+	})
+}
@@ -0,0 +1,118 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 保護者ダッシュボードに表示する範囲外の質問の期間と件数
+const (
+	boundaryHitDays  = 30
+	boundaryHitLimit = 20
+)
+
+// boundaryQuestionRunes 範囲外の質問を記録するときに残す文字数
+const boundaryQuestionRunes = 100
+
+// recordBoundaryHit クイック質問で範囲外として答えなかった質問を、保護者が確認できるように記録
+func (m *MainApp) recordBoundaryHit(question string, hit *ai.BoundaryHit) {
+	if len([]rune(question)) > boundaryQuestionRunes {
+		question = string([]rune(question)[:boundaryQuestionRunes]) + "…"
+	}
+	record := &database.BoundaryHit{
+		ID:        uuid.New().String(),
+		UserID:    m.currentUser.ID,
+		Stage:     hit.Stage,
+		Category:  hit.Category,
+		Detail:    hit.Detail,
+		Question:  question,
+		CreatedAt: time.Now(),
+	}
+	slog.Info("🚧 クイック質問の範囲外の質問", "stage", hit.Stage, "category", hit.Category)
+	if err := m.db.CreateBoundaryHit(record); err != nil {
+		slog.Error("範囲外の質問の記録エラー", "error", err)
+	}
+}
+
+// createBoundaryCard 保護者ダッシュボードの、クイック質問で聞ける範囲の設定と範囲外の質問の記録
+func (m *MainApp) createBoundaryCard(w fyne.Window) *widget.Card {
+	boundaries := m.config.Parent.Boundaries
+
+	enableCheck := widget.NewCheck("クイック質問で聞ける内容を勉強に限る", nil)
+	enableCheck.SetChecked(boundaries.Enabled)
+	personalCheck := widget.NewCheck("恋愛・友だち関係・お金などの個人的な相談には答えない", nil)
+	personalCheck.SetChecked(boundaries.BlockPersonal)
+	subjectCheck := widget.NewCheckGroup(config.Subjects, nil)
+	subjectCheck.Horizontal = true
+	subjectCheck.SetSelected(boundaries.Subjects)
+
+	saveBtn := widget.NewButton("範囲を保存", func() {
+		var subjects []string
+		for _, subject := range config.Subjects {
+			if slices.Contains(subjectCheck.Selected, subject) {
+				subjects = append(subjects, subject)
+			}
+		}
+		m.config.Parent.Boundaries = config.TutorBoundaries{
+			Enabled:       enableCheck.Checked,
+			Subjects:      subjects,
+			BlockPersonal: personalCheck.Checked,
+		}
+		m.saveConfig()
+		dialog.ShowInformation("クイック質問の範囲", "クイック質問で聞ける範囲を保存しました。", w)
+	})
+
+	note := widget.NewLabel("質問をAIに送る前と、AIの解説を表示する前に確かめ、範囲外の質問には答えずに下に記録します。教科を1つも選ばなければすべての教科を聞けます。つらい気持ちを書いた質問には、設定にかかわらず相談先を案内します。")
+	note.Wrapping = fyne.TextWrapWord
+
+	return widget.NewCard("🚧 クイック質問の範囲", "", container.NewVBox(
+		note,
+		enableCheck,
+		personalCheck,
+		widget.NewForm(widget.NewFormItem("聞ける教科", subjectCheck)),
+		saveBtn,
+		widget.NewSeparator(),
+		widget.NewLabel(fmt.Sprintf("最近%d日の範囲外の質問:", boundaryHitDays)),
+		m.boundaryHitList(),
+	))
+}
+
+// boundaryHitList 最近の範囲外の質問の一覧
+func (m *MainApp) boundaryHitList() fyne.CanvasObject {
+	hits, err := m.db.GetBoundaryHits(m.currentUser.ID, time.Now().AddDate(0, 0, -boundaryHitDays), boundaryHitLimit)
+	if err != nil {
+		slog.Error("範囲外の質問の取得エラー", "error", err)
+		return widget.NewLabel("記録を読み込めませんでした。")
+	}
+	if len(hits) == 0 {
+		return widget.NewLabel("ありません。")
+	}
+
+	list := container.NewVBox()
+	for _, hit := range hits {
+		stage := "質問"
+		if hit.Stage == ai.BoundaryStageAnswer {
+			stage = "AIの解説"
+		}
+		text := widget.NewLabel(fmt.Sprintf("%s　%s（%sで判断: %s）\n%s",
+			hit.CreatedAt.Format("01/02 15:04"), ai.BoundaryLabels[hit.Category], stage, hit.Detail, hit.Question))
+		text.Wrapping = fyne.TextWrapWord
+		if hit.Category == ai.BoundaryCrisis {
+			text.Importance = widget.DangerImportance
+		}
+		list.Add(text)
+	}
+	return list
+}
@@ -0,0 +1,217 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// captureShortcut クイック質問を開くショートカット（Ctrl+Shift+K、macOSはCmd+Shift+K）
+// Fyneのショートカットはウィンドウごとのため、アプリのウィンドウを選択しているときだけ使える
+// ほかのアプリを使っているときは、タスクトレイの「クイック質問」から開く（showCaptureFromTray）
+var captureShortcut = &desktop.CustomShortcut{
+	KeyName:  fyne.KeyK,
+	Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift,
+}
+
+// captureSubjectAuto クイック質問で科目をAIに判断させる
+const captureSubjectAuto = "おまかせ"
+
+// registerCaptureShortcut クイック質問のショートカットをメインウィンドウに登録
+func (m *MainApp) registerCaptureShortcut() {
+	m.window.Canvas().AddShortcut(captureShortcut, func(fyne.Shortcut) {
+		m.showCaptureWindow()
+	})
+}
+
+// showCaptureWindow 宿題などで見つけた問題を貼り付けて解説してもらう小さなウィンドウを表示
+func (m *MainApp) showCaptureWindow() {
+	// 模擬テスト中は、本番のテストと同じように解説を聞けない
+	if m.exam != nil {
+		m.ShowInfoDialog("クイック質問", "模擬テスト中はクイック質問を使えません。提出してから質問しましょう。")
+		return
+	}
+	if m.captureWindow != nil {
+		m.captureWindow.RequestFocus()
+		return
+	}
+
+	w := m.app.NewWindow("📋 クイック質問")
+	m.captureWindow = w
+	w.SetOnClosed(func() { m.captureWindow = nil })
+
+	subjectSelect := widget.NewSelect(append([]string{captureSubjectAuto}, m.config.OrderedSubjects()...), nil)
+	subjectSelect.SetSelected(captureSubjectAuto)
+
+	question := widget.NewMultiLineEntry()
+	question.SetPlaceHolder("わからなかった問題を貼り付けてください")
+	question.Wrapping = fyne.TextWrapWord
+	question.SetMinRowsVisible(5)
+	// コピーしてから開いたときは、クリップボードの文章を貼り付けておく
+	if text := strings.TrimSpace(m.app.Clipboard().Content()); text != "" {
+		question.SetText(text)
+	}
+
+	result := widget.NewRichTextFromMarkdown("")
+	result.Wrapping = fyne.TextWrapWord
+
+	var explanation *ai.CaptureExplanation
+	var explainBtn, saveBtn *widget.Button
+
+	saveBtn = widget.NewButton("💾 問題バンクに保存", func() {
+		m.saveQuestionInBackground(saveBtn, subjectSelect.Selected, question.Text, explanation, func(err error) {
+			result.ParseMarkdown(fmt.Sprintf("保存できませんでした: %v", err))
+		})
+	})
+	saveBtn.Disable()
+
+	explainBtn = widget.NewButton("🤖 AIに解説してもらう", func() {
+		if strings.TrimSpace(question.Text) == "" {
+			result.ParseMarkdown("問題を入力してください。")
+			return
+		}
+		req := ai.CaptureRequest{Question: question.Text, Grade: m.currentUser.Grade, Boundaries: m.config.Parent.Boundaries}
+		if subjectSelect.Selected != captureSubjectAuto {
+			req.Subject = subjectSelect.Selected
+		}
+
+		transcript := m.newTranscript(database.TranscriptSourceCapture, req)
+		explainBtn.Disable()
+		saveBtn.Disable()
+		saveBtn.SetText("💾 問題バンクに保存")
+		result.ParseMarkdown("**AIが解説を作っています...**")
+
+		m.goSafe("解説の作成", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+			defer cancel()
+
+			e := m.explainQuestion(ctx, req, transcript)
+			fyne.Do(func() {
+				explanation = e
+				explainBtn.Enable()
+				if e.Refused == nil {
+					saveBtn.Enable() // 範囲外の質問は問題バンクに保存しない
+				}
+				result.ParseMarkdown(captureMarkdown(e))
+			})
+		}, func() {
+			explainBtn.Enable()
+			result.ParseMarkdown("")
+		})
+	})
+	explainBtn.Importance = widget.HighImportance
+
+	question.OnChanged = func(string) {
+		// 問題を書き換えたら、前の解説は保存しない
+		explanation = nil
+		saveBtn.Disable()
+		saveBtn.SetText("💾 問題バンクに保存")
+	}
+
+	content := container.NewBorder(
+		container.NewVBox(
+			widget.NewForm(widget.NewFormItem("教科", subjectSelect)),
+			question,
+			container.NewGridWithColumns(2, explainBtn, saveBtn),
+		),
+		nil, nil, nil,
+		container.NewVScroll(result),
+	)
+	w.SetContent(container.NewPadded(content))
+	w.Resize(fyne.NewSize(480, 520))
+	w.Show()
+}
+
+// explainQuestion 問題の解説を作成（範囲外の質問は保護者が確認できるように記録し、やりとりを記録に残す）
+func (m *MainApp) explainQuestion(ctx context.Context, req ai.CaptureRequest, transcript *database.TutorTranscript) *ai.CaptureExplanation {
+	e := m.aiEngine.ExplainCapturedQuestion(ctx, req)
+	if e.Refused != nil {
+		m.recordBoundaryHit(req.Question, e.Refused)
+	}
+	m.saveTranscript(transcript, e)
+	return e
+}
+
+// captureMarkdown クイック質問の解説の表示用テキスト
+func captureMarkdown(e *ai.CaptureExplanation) string {
+	if e.Offline || e.Refused != nil {
+		return e.Explanation
+	}
+	var parts []string
+	if e.Title != "" {
+		parts = append(parts, "## "+e.Title)
+	}
+	if e.Topic != "" {
+		parts = append(parts, "**単元:** "+e.Topic)
+	}
+	if e.Answer != "" {
+		parts = append(parts, "**答え:** "+e.Answer)
+	}
+	if e.Explanation != "" {
+		parts = append(parts, e.Explanation)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// saveQuestionInBackground 問題と解説をバックグラウンドで問題バンクに保存し、結果を保存ボタンに表示
+// （subjectが「おまかせ」なら科目なしで保存する。保存できなかったときはshowErrorで知らせ、もう一度押せるようにする）
+func (m *MainApp) saveQuestionInBackground(saveBtn *widget.Button, subject, question string, explanation *ai.CaptureExplanation, showError func(error)) {
+	if subject == captureSubjectAuto {
+		subject = ""
+	}
+	saveBtn.Disable()
+	m.goSafe("問題の保存", func() {
+		duplicate, err := m.saveCapturedQuestion(subject, question, explanation)
+		fyne.Do(func() {
+			if err != nil {
+				slog.Error("問題保存エラー", "error", err)
+				showError(err)
+				saveBtn.Enable()
+				return
+			}
+			if duplicate != nil {
+				saveBtn.SetText("✅ 同じような問題を保存済みです")
+				return
+			}
+			saveBtn.SetText("✅ 保存しました")
+		})
+	}, saveBtn.Enable)
+}
+
+// saveCapturedQuestion クイック質問の問題と解説を「captured」タグで問題バンクに保存
+// （ほぼ同じ問題がすでに保存されていれば保存せず、その問題を返す）
+func (m *MainApp) saveCapturedQuestion(subject, question string, explanation *ai.CaptureExplanation) (*database.BankProblem, error) {
+	problem := &database.BankProblem{
+		ID:        uuid.New().String(),
+		UserID:    m.currentUser.ID,
+		Subject:   subject,
+		Question:  strings.TrimSpace(question),
+		Tag:       database.BankTagCaptured,
+		CreatedAt: time.Now(),
+	}
+	if explanation != nil && !explanation.Offline {
+		problem.Title = explanation.Title
+		problem.Answer = explanation.Answer
+		problem.Explanation = explanation.Explanation
+		problem.Topic = explanation.Topic
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if duplicate := m.findDuplicateBankProblem(ctx, problem); duplicate != nil {
+		slog.Info("同じような問題が問題バンクにあるため保存しません", "duplicate", duplicate.ID)
+		return duplicate, nil
+	}
+	return nil, m.db.CreateBankProblem(problem)
+}
@@ -0,0 +1,313 @@
+package gui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// BarChart 棒グラフ（値の推移の表示用）
+type BarChart struct {
+	widget.BaseWidget
+
+	labels   []string  // 各棒の下に表示するラベル
+	values   []float64 // 棒の値
+	maxValue float64   // グラフの上端の値（0なら最大値に合わせる）
+	unit     string    // 値の後ろに付ける単位（"点"など）
+}
+
+// NewBarChart 棒グラフを作成
+func NewBarChart(maxValue float64, unit string) *BarChart {
+	c := &BarChart{maxValue: maxValue, unit: unit}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetData 表示するデータを更新
+func (c *BarChart) SetData(labels []string, values []float64) {
+	c.labels = labels
+	c.values = values
+	c.Refresh()
+}
+
+// CreateRenderer レンダラーを作成
+func (c *BarChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &barChartRenderer{
+		chart:    c,
+		baseline: canvas.NewLine(theme.Color(theme.ColorNameSeparator)),
+		empty:    canvas.NewText("まだ記録がありません", theme.Color(theme.ColorNamePlaceHolder)),
+	}
+	r.empty.Alignment = fyne.TextAlignCenter
+	r.Refresh()
+	return r
+}
+
+// barChartRenderer 棒グラフの描画
+type barChartRenderer struct {
+	chart *BarChart
+
+	baseline *canvas.Line
+	empty    *canvas.Text
+	bars     []*canvas.Rectangle
+	values   []*canvas.Text
+	labels   []*canvas.Text
+}
+
+// MinSize 最小サイズ
+func (r *barChartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(240, 160)
+}
+
+// Layout 棒・値・ラベルを配置
+func (r *barChartRenderer) Layout(size fyne.Size) {
+	textSize := theme.CaptionTextSize()
+	top := textSize * 1.6
+	bottom := size.Height - textSize*1.8
+	r.baseline.Position1 = fyne.NewPos(0, bottom)
+	r.baseline.Position2 = fyne.NewPos(size.Width, bottom)
+
+	r.empty.Move(fyne.NewPos(0, size.Height/2-textSize))
+	r.empty.Resize(fyne.NewSize(size.Width, textSize*2))
+
+	if len(r.bars) == 0 {
+		return
+	}
+
+	maxValue := r.chart.scaleMax()
+	slot := size.Width / float32(len(r.bars))
+	barWidth := slot * 0.6
+	for i, bar := range r.bars {
+		height := (bottom - top) * float32(r.chart.values[i]/maxValue)
+		x := slot*float32(i) + (slot-barWidth)/2
+
+		bar.Move(fyne.NewPos(x, bottom-height))
+		bar.Resize(fyne.NewSize(barWidth, height))
+
+		r.values[i].Move(fyne.NewPos(slot*float32(i), bottom-height-textSize*1.5))
+		r.values[i].Resize(fyne.NewSize(slot, textSize*1.4))
+		r.labels[i].Move(fyne.NewPos(slot*float32(i), bottom+textSize*0.3))
+		r.labels[i].Resize(fyne.NewSize(slot, textSize*1.4))
+	}
+}
+
+// Refresh データに合わせて描画オブジェクトを作り直す
+func (r *barChartRenderer) Refresh() {
+	c := r.chart
+	r.bars, r.values, r.labels = nil, nil, nil
+
+	barColor := theme.Color(theme.ColorNamePrimary)
+	textColor := theme.Color(theme.ColorNameForeground)
+	for i, value := range c.values {
+		r.bars = append(r.bars, canvas.NewRectangle(barColor))
+
+		valueText := canvas.NewText(fmt.Sprintf("%.0f%s", value, c.unit), textColor)
+		valueText.TextSize = theme.CaptionTextSize()
+		valueText.Alignment = fyne.TextAlignCenter
+		r.values = append(r.values, valueText)
+
+		label := ""
+		if i < len(c.labels) {
+			label = c.labels[i]
+		}
+		labelText := canvas.NewText(label, textColor)
+		labelText.TextSize = theme.CaptionTextSize()
+		labelText.Alignment = fyne.TextAlignCenter
+		r.labels = append(r.labels, labelText)
+	}
+
+	r.baseline.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	r.empty.Color = theme.Color(theme.ColorNamePlaceHolder)
+	setVisible(r.empty, len(c.values) == 0)
+
+	r.Layout(c.Size())
+	canvas.Refresh(c)
+}
+
+// Objects 描画オブジェクト一覧
+func (r *barChartRenderer) Objects() []fyne.CanvasObject {
+	objects := []fyne.CanvasObject{r.baseline, r.empty}
+	for i := range r.bars {
+		objects = append(objects, r.bars[i], r.values[i], r.labels[i])
+	}
+	return objects
+}
+
+// Destroy 破棄処理
+func (r *barChartRenderer) Destroy() {}
+
+// scaleMax グラフの上端の値
+func (c *BarChart) scaleMax() float64 {
+	maxValue := c.maxValue
+	for _, value := range c.values {
+		maxValue = max(maxValue, value)
+	}
+	if maxValue <= 0 {
+		return 1
+	}
+	return maxValue
+}
+
+// LineChart 折れ線グラフ（割合などの推移の表示用）
+type LineChart struct {
+	widget.BaseWidget
+
+	labels   []string  // 各点の下に表示するラベル
+	values   []float64 // 点の値
+	maxValue float64   // グラフの上端の値（0なら最大値に合わせる）
+	unit     string    // 値の後ろに付ける単位（"%"など）
+}
+
+// NewLineChart 折れ線グラフを作成
+func NewLineChart(maxValue float64, unit string) *LineChart {
+	c := &LineChart{maxValue: maxValue, unit: unit}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetData 表示するデータを更新
+func (c *LineChart) SetData(labels []string, values []float64) {
+	c.labels = labels
+	c.values = values
+	c.Refresh()
+}
+
+// CreateRenderer レンダラーを作成
+func (c *LineChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &lineChartRenderer{
+		chart:    c,
+		baseline: canvas.NewLine(theme.Color(theme.ColorNameSeparator)),
+		topline:  canvas.NewLine(theme.Color(theme.ColorNameSeparator)),
+		empty:    canvas.NewText("まだ記録がありません", theme.Color(theme.ColorNamePlaceHolder)),
+	}
+	r.empty.Alignment = fyne.TextAlignCenter
+	r.Refresh()
+	return r
+}
+
+// lineChartRenderer 折れ線グラフの描画
+type lineChartRenderer struct {
+	chart *LineChart
+
+	baseline *canvas.Line
+	topline  *canvas.Line
+	empty    *canvas.Text
+	segments []*canvas.Line
+	dots     []*canvas.Circle
+	values   []*canvas.Text
+	labels   []*canvas.Text
+}
+
+// MinSize 最小サイズ
+func (r *lineChartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(240, 160)
+}
+
+// Layout 点・線・値・ラベルを配置
+func (r *lineChartRenderer) Layout(size fyne.Size) {
+	textSize := theme.CaptionTextSize()
+	top := textSize * 1.6
+	bottom := size.Height - textSize*1.8
+	r.baseline.Position1 = fyne.NewPos(0, bottom)
+	r.baseline.Position2 = fyne.NewPos(size.Width, bottom)
+	r.topline.Position1 = fyne.NewPos(0, top)
+	r.topline.Position2 = fyne.NewPos(size.Width, top)
+
+	r.empty.Move(fyne.NewPos(0, size.Height/2-textSize))
+	r.empty.Resize(fyne.NewSize(size.Width, textSize*2))
+
+	if len(r.dots) == 0 {
+		return
+	}
+
+	maxValue := r.chart.scaleMax()
+	slot := size.Width / float32(len(r.dots))
+	radius := float32(3)
+	points := make([]fyne.Position, len(r.dots))
+	for i, dot := range r.dots {
+		points[i] = fyne.NewPos(slot*float32(i)+slot/2, bottom-(bottom-top)*float32(r.chart.values[i]/maxValue))
+
+		dot.Move(points[i].SubtractXY(radius, radius))
+		dot.Resize(fyne.NewSquareSize(radius * 2))
+
+		r.values[i].Move(fyne.NewPos(slot*float32(i), points[i].Y-textSize*1.6))
+		r.values[i].Resize(fyne.NewSize(slot, textSize*1.4))
+		r.labels[i].Move(fyne.NewPos(slot*float32(i), bottom+textSize*0.3))
+		r.labels[i].Resize(fyne.NewSize(slot, textSize*1.4))
+	}
+	for i, segment := range r.segments {
+		segment.Position1 = points[i]
+		segment.Position2 = points[i+1]
+	}
+}
+
+// Refresh データに合わせて描画オブジェクトを作り直す
+func (r *lineChartRenderer) Refresh() {
+	c := r.chart
+	r.segments, r.dots, r.values, r.labels = nil, nil, nil, nil
+
+	lineColor := theme.Color(theme.ColorNamePrimary)
+	textColor := theme.Color(theme.ColorNameForeground)
+	// 点が多いときは値とラベルを間引いて重ならないようにする
+	step := max(len(c.values)/8, 1)
+	for i, value := range c.values {
+		if i > 0 {
+			segment := canvas.NewLine(lineColor)
+			segment.StrokeWidth = 2
+			r.segments = append(r.segments, segment)
+		}
+		r.dots = append(r.dots, canvas.NewCircle(lineColor))
+
+		valueText := canvas.NewText("", textColor)
+		labelText := canvas.NewText("", textColor)
+		if i%step == 0 || i == len(c.values)-1 {
+			valueText.Text = fmt.Sprintf("%.0f%s", value, c.unit)
+			if i < len(c.labels) {
+				labelText.Text = c.labels[i]
+			}
+		}
+		for _, text := range []*canvas.Text{valueText, labelText} {
+			text.TextSize = theme.CaptionTextSize()
+			text.Alignment = fyne.TextAlignCenter
+		}
+		r.values = append(r.values, valueText)
+		r.labels = append(r.labels, labelText)
+	}
+
+	r.baseline.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	r.topline.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	r.empty.Color = theme.Color(theme.ColorNamePlaceHolder)
+	setVisible(r.empty, len(c.values) == 0)
+
+	r.Layout(c.Size())
+	canvas.Refresh(c)
+}
+
+// Objects 描画オブジェクト一覧
+func (r *lineChartRenderer) Objects() []fyne.CanvasObject {
+	objects := []fyne.CanvasObject{r.baseline, r.topline, r.empty}
+	for _, segment := range r.segments {
+		objects = append(objects, segment)
+	}
+	for i := range r.dots {
+		objects = append(objects, r.dots[i], r.values[i], r.labels[i])
+	}
+	return objects
+}
+
+// Destroy 破棄処理
+func (r *lineChartRenderer) Destroy() {}
+
+// scaleMax グラフの上端の値
+func (c *LineChart) scaleMax() float64 {
+	maxValue := c.maxValue
+	for _, value := range c.values {
+		maxValue = max(maxValue, value)
+	}
+	if maxValue <= 0 {
+		return 1
+	}
+	return maxValue
+}
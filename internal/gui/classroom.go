@@ -0,0 +1,406 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/export"
+)
+
+// 教室モードの役割の表示名
+var classroomRoleLabels = map[string]string{
+	database.RoleTeacher:   "先生",
+	database.RoleAssistant: "補助の先生",
+	database.RoleStudent:   "生徒",
+}
+
+// classroomRoles 役割を選ぶときの順
+var classroomRoles = []string{database.RoleTeacher, database.RoleAssistant, database.RoleStudent}
+
+// assignmentDays 教室タブに出す課題の期間（今日から何日先まで）
+const assignmentDays = 7
+
+// 課題の取り組み表の期間
+var attendancePeriods = map[string]int{
+	"この1週間": 7,
+	"この4週間": 28,
+}
+
+// attendancePeriodOptions 課題の取り組み表の期間の選択肢
+var attendancePeriodOptions = []string{"この1週間", "この4週間"}
+
+// assignmentProblemCounts 課題の問題数の選択肢
+var assignmentProblemCounts = []string{"5", "10", "15", "20"}
+
+// ClassroomView 教室モードの画面（役割によって見えるカードが変わる）
+type ClassroomView struct {
+	container *fyne.Container
+}
+
+// authorizeClassroom 今のプロフィールが役割でできる操作か確かめる（先生が決まるまでは制限しない）
+func (m *MainApp) authorizeClassroom(capability string) error {
+	if m.currentUser == nil {
+		return nil
+	}
+	return m.db.AuthorizeClassroom(m.currentUser.ID, capability)
+}
+
+// createClassroomView 教室モードの画面を作成
+func (m *MainApp) createClassroomView() *ClassroomView {
+	view := &ClassroomView{container: container.NewVBox()}
+	m.refreshClassroom(view)
+	return view
+}
+
+// refreshClassroom 今のプロフィールの役割で、教室モードの画面を作り直す
+func (m *MainApp) refreshClassroom(view *ClassroomView) {
+	c, err := m.db.Classroom(m.currentUser.ID)
+	if err != nil {
+		slog.Error("教室モードの役割取得エラー", "error", err)
+		view.container.Objects = []fyne.CanvasObject{widget.NewLabel("教室モードの情報を読み込めませんでした")}
+		view.container.Refresh()
+		return
+	}
+
+	objects := []fyne.CanvasObject{m.createClassroomRoleCard(view, c)}
+	if c.Can(database.CapCreateAssignments) {
+		objects = append(objects, m.createAssignmentFormCard(view, c))
+	}
+	objects = append(objects, m.createAssignmentListCard(c))
+	if c.Can(database.CapViewAllData) {
+		objects = append(objects, m.createAttendanceCard(c), m.createClassroomMembersCard(view, c))
+	}
+	view.container.Objects = objects
+	view.container.Refresh()
+}
+
+// createClassroomRoleCard 自分の役割（先生がまだいなければ、先生として教室を始めるボタン）
+func (m *MainApp) createClassroomRoleCard(view *ClassroomView, c *database.Classroom) *widget.Card {
+	content := container.NewVBox(widget.NewLabel(fmt.Sprintf("あなたの役割: %s", classroomRoleLabels[c.Role()])))
+
+	hasTeacher, err := m.db.HasTeacher()
+	if err != nil {
+		slog.Error("教室モードの先生の確認エラー", "error", err)
+	}
+	if err == nil && !hasTeacher {
+		note := widget.NewLabel("まだ先生が決まっていません。先生が決まると、課題を作る・クラス全員の記録を見る・機能フラグを切り替えるといった操作は、役割で許された人だけができるようになります。")
+		note.Wrapping = fyne.TextWrapWord
+		startBtn := widget.NewButton("🏫 先生として教室を始める", func() {
+			dialog.ShowConfirm("先生として教室を始める", fmt.Sprintf("%sさんを先生にします。ほかのメンバーの役割は、あとで先生が変えられます。", m.currentUser.Name), func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := c.BecomeTeacher(); err != nil {
+					slog.Error("先生の登録エラー", "error", err)
+					m.ShowErrorDialog("エラー", fmt.Sprintf("先生になれませんでした: %v", err))
+					return
+				}
+				slog.Info("🏫 教室モードの先生を登録しました", "user_id", m.currentUser.ID)
+				m.refreshClassroom(view)
+			}, m.window)
+		})
+		startBtn.Importance = widget.HighImportance
+		content.Add(note)
+		content.Add(startBtn)
+	}
+	return widget.NewCard("教室", "", content)
+}
+
+// createAssignmentFormCard 課題を作って生徒に配るフォーム（先生・補助の先生だけ）
+func (m *MainApp) createAssignmentFormCard(view *ClassroomView, c *database.Classroom) *widget.Card {
+	members, err := c.Members()
+	if err != nil {
+		slog.Error("教室のメンバー取得エラー", "error", err)
+	}
+	var studentOptions []string
+	studentIDs := make(map[string]string) // 選択肢の表示 → プロフィールID
+	for _, member := range members {
+		if member.Role != database.RoleStudent {
+			continue
+		}
+		option := fmt.Sprintf("%s（中%d）", member.User.Name, member.User.Grade)
+		studentOptions = append(studentOptions, option)
+		studentIDs[option] = member.User.ID
+	}
+	if len(studentOptions) == 0 {
+		return widget.NewCard("課題を配る", "", widget.NewLabel("生徒の役割のメンバーがいません。プロフィールを作ってから課題を配ってください。"))
+	}
+
+	students := widget.NewCheckGroup(studentOptions, nil)
+	students.SetSelected(studentOptions)
+
+	topicEntry := widget.NewSelectEntry(nil)
+	topicEntry.SetPlaceHolder("空欄なら科目全体")
+	subjectSelect := widget.NewSelect(config.Subjects, func(subject string) {
+		topicEntry.SetOptions(m.aiEngine.CurriculumTopics(m.currentUser.Grade, subject))
+	})
+	subjectSelect.SetSelected(config.Subjects[0])
+
+	countSelect := widget.NewSelect(assignmentProblemCounts, nil)
+	countSelect.SetSelected(assignmentProblemCounts[1])
+	dueEntry := widget.NewEntry()
+	dueEntry.SetText(time.Now().Format(time.DateOnly))
+	noteEntry := widget.NewEntry()
+	noteEntry.SetPlaceHolder("例: 教科書p.42の例題を見てから")
+
+	form := widget.NewForm(
+		widget.NewFormItem("科目", subjectSelect),
+		widget.NewFormItem("単元", topicEntry),
+		widget.NewFormItem("問題数", countSelect),
+		widget.NewFormItem("取り組む日", dueEntry),
+		widget.NewFormItem("メモ", noteEntry),
+		widget.NewFormItem("配る生徒", students),
+	)
+	form.SubmitText = "課題を配る"
+	form.OnSubmit = func() {
+		count, _ := strconv.Atoi(countSelect.Selected)
+		assignment := &database.Assignment{
+			ID:           uuid.New().String(),
+			Subject:      subjectSelect.Selected,
+			Topic:        strings.TrimSpace(topicEntry.Text),
+			ProblemCount: count,
+			DueDate:      strings.TrimSpace(dueEntry.Text),
+			Note:         strings.TrimSpace(noteEntry.Text),
+			CreatedAt:    time.Now(),
+		}
+		for _, option := range students.Selected {
+			assignment.StudentIDs = append(assignment.StudentIDs, studentIDs[option])
+		}
+		if err := c.CreateAssignment(assignment); err != nil {
+			if !errors.Is(err, database.ErrPermissionDenied) {
+				slog.Error("課題の作成エラー", "error", err)
+			}
+			m.ShowErrorDialog("課題を配る", fmt.Sprintf("課題を配れませんでした: %v", err))
+			return
+		}
+		slog.Info("📝 課題を配りました", "subject", assignment.Subject, "students", len(assignment.StudentIDs))
+		m.ShowInfoDialog("課題を配る", fmt.Sprintf("%d人に課題を配りました。", len(assignment.StudentIDs)))
+		m.refreshClassroom(view)
+	}
+	return widget.NewCard("課題を配る", "", form)
+}
+
+// createAssignmentListCard 今日から1週間の課題（生徒には自分に配られた課題だけ）
+func (m *MainApp) createAssignmentListCard(c *database.Classroom) *widget.Card {
+	from := time.Now()
+	assignments, err := c.Assignments(from.Format(time.DateOnly), from.AddDate(0, 0, assignmentDays).Format(time.DateOnly))
+	if err != nil {
+		slog.Error("課題の取得エラー", "error", err)
+		return widget.NewCard("課題", "", widget.NewLabel("課題を読み込めませんでした"))
+	}
+	if len(assignments) == 0 {
+		return widget.NewCard("課題", "今日から1週間", widget.NewLabel("課題はありません"))
+	}
+
+	list := container.NewVBox()
+	for _, assignment := range assignments {
+		text := fmt.Sprintf("%s　%s", assignment.DueDate, assignment.Subject)
+		if assignment.Topic != "" {
+			text += "・" + assignment.Topic
+		}
+		text += fmt.Sprintf("　%d問", assignment.ProblemCount)
+		if c.Can(database.CapViewAllData) {
+			text += fmt.Sprintf("（%d人）", len(assignment.StudentIDs))
+		}
+		if assignment.Note != "" {
+			text += "\n" + assignment.Note
+		}
+		label := widget.NewLabel(text)
+		label.Wrapping = fyne.TextWrapWord
+
+		if c.Role() != database.RoleStudent {
+			list.Add(label)
+			continue
+		}
+		list.Add(container.NewBorder(nil, nil, nil, widget.NewButton("取り組む", func() {
+			m.startSubject(assignment.Subject)
+		}), label))
+	}
+	return widget.NewCard("課題", "今日から1週間", list)
+}
+
+// createClassroomMembersCard メンバーと役割の一覧（先生は役割を変えられる）
+func (m *MainApp) createClassroomMembersCard(view *ClassroomView, c *database.Classroom) *widget.Card {
+	members, err := c.Members()
+	if err != nil {
+		slog.Error("教室のメンバー取得エラー", "error", err)
+		return widget.NewCard("メンバー", "", widget.NewLabel("メンバーを読み込めませんでした"))
+	}
+
+	options := make([]string, len(classroomRoles))
+	for i, role := range classroomRoles {
+		options[i] = classroomRoleLabels[role]
+	}
+
+	list := container.NewVBox()
+	for _, member := range members {
+		name := widget.NewLabel(fmt.Sprintf("%s（中%d）", member.User.Name, member.User.Grade))
+		recordBtn := widget.NewButton("今週の記録", func() {
+			m.showMemberWeek(c, member.User)
+		})
+		if !c.Can(database.CapManageRoles) {
+			list.Add(container.NewBorder(nil, nil, nil, container.NewHBox(widget.NewLabel(classroomRoleLabels[member.Role]), recordBtn), name))
+			continue
+		}
+
+		current := classroomRoleLabels[member.Role]
+		roleSelect := widget.NewSelect(options, nil)
+		roleSelect.SetSelected(current)
+		roleSelect.OnChanged = func(selected string) {
+			if selected == current {
+				return
+			}
+			role := classroomRoles[slices.Index(options, selected)]
+			if err := c.SetRole(member.User.ID, role); err != nil {
+				slog.Error("役割の変更エラー", "user_id", member.User.ID, "error", err)
+				m.ShowErrorDialog("役割の変更", fmt.Sprintf("役割を変えられませんでした: %v", err))
+				roleSelect.SetSelected(current)
+				return
+			}
+			slog.Info("教室モードの役割を変えました", "user_id", member.User.ID, "role", role)
+			m.refreshClassroom(view)
+		}
+		list.Add(container.NewBorder(nil, nil, nil, container.NewHBox(roleSelect, recordBtn), name))
+	}
+	return widget.NewCard("メンバー", "先生・補助の先生は、クラス全員の課題と学習記録を見られます", list)
+}
+
+// showMemberWeek メンバーの直近1週間の学習記録（役割で見られないときはエラー）
+func (m *MainApp) showMemberWeek(c *database.Classroom, user database.User) {
+	to := time.Now()
+	sessions, err := c.StudySessions(user.ID, to.AddDate(0, 0, -7), to)
+	if err != nil {
+		if !errors.Is(err, database.ErrPermissionDenied) {
+			slog.Error("メンバーの学習記録取得エラー", "user_id", user.ID, "error", err)
+		}
+		m.ShowErrorDialog("学習記録", fmt.Sprintf("学習記録を読み込めませんでした: %v", err))
+		return
+	}
+
+	seconds, problems, correct := 0, 0, 0
+	for _, session := range sessions {
+		seconds += session.DurationSeconds()
+		problems += session.TotalProblems
+		correct += session.CorrectAnswers
+	}
+	m.ShowInfoDialog(fmt.Sprintf("%sさんの今週の記録", user.Name),
+		fmt.Sprintf("学習 %d回・%d分\n解いた問題 %d問（正解 %d問）", len(sessions), seconds/60, problems, correct))
+}
+
+// attendanceGrid 今日までの期間の課題の取り組み表
+func (m *MainApp) attendanceGrid(c *database.Classroom, days int) (*export.AttendanceGrid, error) {
+	today := time.Now()
+	from := today.AddDate(0, 0, -(days - 1))
+	progress, err := c.Participation(from.Format(time.DateOnly), today.Format(time.DateOnly))
+	if err != nil {
+		return nil, err
+	}
+	members, err := c.Members()
+	if err != nil {
+		return nil, err
+	}
+	var students []database.User
+	for _, member := range members {
+		if member.Role == database.RoleStudent {
+			students = append(students, member.User)
+		}
+	}
+	return export.NewAttendanceGrid(from, days, students, progress), nil
+}
+
+// createAttendanceCard 課題の取り組み表（生徒ごと・日ごとに、その日の課題を終えたか）
+func (m *MainApp) createAttendanceCard(c *database.Classroom) *widget.Card {
+	table := container.NewVBox()
+	periodSelect := widget.NewSelect(attendancePeriodOptions, func(selected string) {
+		grid, err := m.attendanceGrid(c, attendancePeriods[selected])
+		if err != nil {
+			slog.Error("課題の取り組み表の作成エラー", "error", err)
+			table.Objects = []fyne.CanvasObject{widget.NewLabel("課題の取り組み状況を読み込めませんでした")}
+			table.Refresh()
+			return
+		}
+		table.Objects = []fyne.CanvasObject{container.NewHScroll(attendanceTable(grid))}
+		table.Refresh()
+	})
+	periodSelect.SetSelected(attendancePeriodOptions[0])
+
+	legend := widget.NewLabel(fmt.Sprintf("%s 課題をすべて終えた　%s 途中まで　%s 取り組まなかった　%s 課題なし",
+		export.AttendanceDone, export.AttendancePartial, export.AttendanceAbsent, export.AttendanceNone))
+	legend.Wrapping = fyne.TextWrapWord
+
+	exportBtn := widget.NewButton("📊 Excelに書き出す", func() {
+		m.exportAttendance(c, attendancePeriods[periodSelect.Selected])
+	})
+
+	return widget.NewCard("課題の取り組み表", "取り組む日に、課題の科目（単元）の問題を問題数まで解いたか",
+		container.NewVBox(container.NewHBox(periodSelect, exportBtn), legend, table))
+}
+
+// attendanceTable 課題の取り組み表を、名前・日付・終えた日数の表にする
+func attendanceTable(grid *export.AttendanceGrid) fyne.CanvasObject {
+	if len(grid.Students) == 0 {
+		return widget.NewLabel("生徒の役割のメンバーがいません")
+	}
+	table := container.NewGridWithColumns(len(grid.Dates) + 2)
+	table.Add(widget.NewLabelWithStyle("名前", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	for _, date := range grid.Dates {
+		day, _ := time.Parse(time.DateOnly, date)
+		table.Add(widget.NewLabelWithStyle(day.Format("1/2"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+	}
+	table.Add(widget.NewLabelWithStyle("終えた日", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+
+	for _, student := range grid.Students {
+		table.Add(widget.NewLabel(student.Name))
+		for _, date := range grid.Dates {
+			table.Add(widget.NewLabelWithStyle(grid.Mark(student.ID, date), fyne.TextAlignCenter, fyne.TextStyle{}))
+		}
+		done, assigned := grid.Completion(student.ID)
+		table.Add(widget.NewLabelWithStyle(fmt.Sprintf("%d/%d日", done, assigned), fyne.TextAlignCenter, fyne.TextStyle{}))
+	}
+	return table
+}
+
+// exportAttendance 課題の取り組み表をExcel形式で書き出す
+func (m *MainApp) exportAttendance(c *database.Classroom, days int) {
+	grid, err := m.attendanceGrid(c, days)
+	if err != nil {
+		if !errors.Is(err, database.ErrPermissionDenied) {
+			slog.Error("課題の取り組み表の作成エラー", "error", err)
+		}
+		m.ShowErrorDialog("エラー", fmt.Sprintf("課題の取り組み状況を読み込めませんでした: %v", err))
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("保存先の選択に失敗しました: %v", err))
+			return
+		}
+		if writer == nil {
+			return // キャンセル
+		}
+		defer func() { _ = writer.Close() }()
+
+		if err := export.WriteAttendanceXLSX(writer, grid); err != nil {
+			slog.Error("Excel出力エラー", "error", err)
+			m.ShowErrorDialog("エラー", fmt.Sprintf("Excelのファイルの作成に失敗しました: %v", err))
+			return
+		}
+		m.ShowInfoDialog("保存完了", fmt.Sprintf("%s に保存しました。", writer.URI().Name()))
+	}, m.window)
+	saveDialog.SetFileName(fmt.Sprintf("課題の取り組み表_%s.xlsx", time.Now().Format("20060102")))
+	saveDialog.Show()
+}
@@ -0,0 +1,177 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// cloudProviderLabels クラウドAIの提供元の表示名
+var cloudProviderLabels = []struct {
+	provider string
+	label    string
+}{
+	{config.CloudProviderNone, "使わない"},
+	{config.CloudProviderOpenAI, "OpenAI"},
+	{config.CloudProviderGemini, "Google Gemini"},
+}
+
+// cloudDataWarning クラウドAIを有効にする前に保護者に確認してもらう内容
+const cloudDataWarning = `クラウドAIは、このパソコンでローカルのAI（Ollama）が使えないときだけ使われます。
+
+有効にすると、問題・解説・レポートを作るために、学年・科目・単元・苦手な分野・お子さまの解答や学習記録の要約が、選んだ会社（OpenAIまたはGoogle）のサーバーに送信されます。氏名は送信しません。
+
+送信された内容の扱いは各社の利用規約に従います。APIの利用料金はAPIキーのアカウントに請求されます。`
+
+// createCloudAISettings クラウドAI（保護者向け）の設定カードを作成
+func (m *MainApp) createCloudAISettings() *widget.Card {
+	cloud := m.config.AI.Cloud
+
+	var labels []string
+	selected := ""
+	for _, p := range cloudProviderLabels {
+		labels = append(labels, p.label)
+		if p.provider == cloud.Provider {
+			selected = p.label
+		}
+	}
+	providerSelect := widget.NewSelect(labels, nil)
+	providerSelect.SetSelected(selected)
+
+	apiKeyEntry := widget.NewPasswordEntry()
+	apiKeyEntry.SetPlaceHolder("保護者のAPIキー")
+	apiKeyEntry.SetText(cloud.APIKey)
+
+	modelEntry := widget.NewEntry()
+	modelEntry.SetPlaceHolder("空欄なら既定のモデル")
+	modelEntry.SetText(cloud.Model)
+
+	budgetEntry := widget.NewEntry()
+	budgetEntry.SetText(strconv.Itoa(cloud.MonthlyTokens))
+	dailyRequestsEntry := widget.NewEntry()
+	dailyRequestsEntry.SetText(strconv.Itoa(cloud.DailyRequests))
+	dailyTokensEntry := widget.NewEntry()
+	dailyTokensEntry.SetText(strconv.Itoa(cloud.DailyTokens))
+
+	warning := widget.NewLabel(cloudDataWarning)
+	warning.Wrapping = fyne.TextWrapWord
+	consentCheck := widget.NewCheck("上記の内容を理解し、保護者として送信に同意します", nil)
+	consentCheck.SetChecked(cloud.Consent)
+
+	monthMeter := newUsageMeter()
+	todayRequestsMeter := newUsageMeter()
+	todayTokensMeter := newUsageMeter()
+	refreshUsage := func() {
+		usage, err := m.aiEngine.CloudUsage()
+		if err != nil {
+			slog.Error("クラウドAI使用量取得エラー", "error", err)
+			return
+		}
+		setUsageMeter(monthMeter, usage.MonthTokens, usage.MonthlyTokenLimit, "トークン")
+		setUsageMeter(todayRequestsMeter, usage.TodayRequests, usage.DailyRequestLimit, "回")
+		setUsageMeter(todayTokensMeter, usage.TodayTokens, usage.DailyTokenLimit, "トークン")
+	}
+	refreshUsage()
+
+	saveBtn := widget.NewButton("保存", func() {
+		updated := m.config.AI.Cloud
+		updated.Provider = cloudProviderLabels[max(providerSelect.SelectedIndex(), 0)].provider
+		updated.APIKey = strings.TrimSpace(apiKeyEntry.Text)
+		updated.Model = strings.TrimSpace(modelEntry.Text)
+
+		limits := []struct {
+			entry *widget.Entry
+			max   int
+			label string
+			value *int
+		}{
+			{budgetEntry, config.MaxCloudMonthlyTokens, "1か月の上限", &updated.MonthlyTokens},
+			{dailyRequestsEntry, config.MaxCloudDailyRequests, "1日の回数", &updated.DailyRequests},
+			{dailyTokensEntry, config.MaxCloudDailyTokens, "1日の上限", &updated.DailyTokens},
+		}
+		for _, limit := range limits {
+			value, err := strconv.Atoi(strings.TrimSpace(limit.entry.Text))
+			if err != nil || value < 0 || value > limit.max {
+				m.ShowErrorDialog("クラウドAI", fmt.Sprintf("%sは0〜%dで入力してください", limit.label, limit.max))
+				return
+			}
+			*limit.value = value
+		}
+
+		if updated.Provider != config.CloudProviderNone {
+			if updated.APIKey == "" {
+				m.ShowErrorDialog("クラウドAI", "APIキーを入力してください")
+				return
+			}
+			if !consentCheck.Checked {
+				m.ShowErrorDialog("クラウドAI", "クラウドAIを使うには、保護者の同意が必要です")
+				return
+			}
+		}
+		if consentCheck.Checked && !updated.Consent {
+			updated.ConsentedAt = time.Now()
+		}
+		updated.Consent = consentCheck.Checked
+
+		m.config.AI.Cloud = updated
+		m.aiEngine.SetCloudConfig(updated)
+		if err := config.Save(m.config); err != nil {
+			slog.Error("設定保存エラー", "error", err)
+		}
+		refreshUsage()
+
+		if updated.Enabled() {
+			m.ShowInfoDialog("クラウドAI", "ローカルのAIが使えないときに、クラウドAIを使います。")
+		} else {
+			m.ShowInfoDialog("クラウドAI", "クラウドAIは使いません。")
+		}
+	})
+
+	return widget.NewCard("☁️ クラウドAI（保護者向け）", "ローカルのAIを動かせないパソコン向けの、任意の設定です",
+		container.NewVBox(
+			warning,
+			widget.NewForm(
+				widget.NewFormItem("提供元", providerSelect),
+				widget.NewFormItem("APIキー", apiKeyEntry),
+				widget.NewFormItem("モデル", modelEntry),
+				widget.NewFormItem("1か月の上限", container.NewBorder(nil, nil, nil, widget.NewLabel("トークン（家庭全体）"), budgetEntry)),
+				widget.NewFormItem("1日の回数", container.NewBorder(nil, nil, nil, widget.NewLabel("回（1人あたり、0は無制限）"), dailyRequestsEntry)),
+				widget.NewFormItem("1日の上限", container.NewBorder(nil, nil, nil, widget.NewLabel("トークン（1人あたり、0は無制限）"), dailyTokensEntry)),
+			),
+			consentCheck,
+			saveBtn,
+			widget.NewLabel("使用量（上限に達すると、内蔵問題などのオフラインの機能で学習を続けます）:"),
+			widget.NewForm(
+				widget.NewFormItem("今月", monthMeter),
+				widget.NewFormItem("今日の回数", todayRequestsMeter),
+				widget.NewFormItem("今日のトークン", todayTokensMeter),
+			),
+		),
+	)
+}
+
+// newUsageMeter 使用量と上限を表示するバーを作成
+func newUsageMeter() *widget.ProgressBar {
+	meter := widget.NewProgressBar()
+	meter.Max = 1
+	return meter
+}
+
+// setUsageMeter 使用量をバーに反映（上限0は無制限として使用量だけ表示）
+func setUsageMeter(meter *widget.ProgressBar, used, limit int, unit string) {
+	if limit <= 0 {
+		meter.TextFormatter = func() string { return fmt.Sprintf("%d %s（無制限）", used, unit) }
+		meter.SetValue(0)
+		return
+	}
+	meter.TextFormatter = func() string { return fmt.Sprintf("%d / %d %s", used, limit, unit) }
+	meter.SetValue(min(float64(used)/float64(limit), 1))
+}
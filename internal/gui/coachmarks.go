@@ -0,0 +1,112 @@
+package gui
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ヒントのID（表示済みかどうかを設定ファイルに記録）
+const (
+	coachMarkStudy     = "study"      // 初めての学習セッション
+	coachMarkFeedback  = "feedback"   // 初めての解答
+	coachMarkReview    = "review"     // 初めての「昨日の復習」
+	coachMarkManualLog = "manual_log" // 何回か学習したあと
+	coachMarkReport    = "report"     // レポートを作れるだけ問題を解いたあと
+)
+
+// ヒントを出す目安となる利用状況
+const (
+	manualLogTipSessions = 3  // この回数以上学習したらアプリ外の学習の記録を紹介
+	reportTipProblems    = 20 // この問題数以上解いたら学習レポートを紹介
+)
+
+// coachMark 機能を紹介するヒント
+type coachMark struct {
+	title   string
+	message string
+}
+
+// coachMarks ヒントの一覧
+var coachMarks = map[string]coachMark{
+	coachMarkStudy: {
+		title:   "🎒 学習画面の使い方",
+		message: "問題を読んで、正しいと思う選択肢を押してください。\n25分ごとに休憩をおすすめします。少し手を止めたいときは「一時停止」を使いましょう。",
+	},
+	coachMarkFeedback: {
+		title:   "💬 解説を読んでみよう",
+		message: "答えると解説が表示されます。間違えた問題こそ解説を読むと力がつきます。\n続けて正解するとコンボになり、経験値が増えます。",
+	},
+	coachMarkReview: {
+		title:   "📝 昨日の復習",
+		message: "前回の学習の大事なポイントをまとめました。\n「クイズで確認」で、覚えているか1問ずつ確かめてみましょう。",
+	},
+	coachMarkManualLog: {
+		title:   "📒 アプリ外の学習も記録できます",
+		message: "塾や紙のドリルで勉強したときは、ホーム画面の「塾・ドリルの学習を記録」から記録すると、経験値や連続記録に反映されます。",
+	},
+	coachMarkReport: {
+		title:   "📄 学習レポートを作ってみよう",
+		message: "たくさん問題を解いたので、正解率や学習時間の推移をグラフで見られます。\n「学習レポートをPDFで保存」で、家族や先生に見せるレポートも作れます。",
+	},
+}
+
+// showCoachMark まだ表示していないヒントを表示（ヒントが無効な場合や別のヒントを表示中は何もしない）
+func (m *MainApp) showCoachMark(id string) {
+	mark, exists := coachMarks[id]
+	if !exists || !m.config.UI.CoachMarks || m.config.CoachMarkSeen(id) || m.coachMarkShowing {
+		return
+	}
+	m.coachMarkShowing = true
+
+	message := widget.NewLabel(mark.message)
+	message.Wrapping = fyne.TextWrapWord
+	disable := widget.NewCheck("今後ヒントを表示しない", nil)
+
+	tip := dialog.NewCustom(mark.title, "わかった", container.NewVBox(message, disable), m.window)
+	tip.SetOnClosed(func() {
+		m.coachMarkShowing = false
+		m.config.MarkCoachMarkSeen(id)
+		if disable.Checked {
+			m.config.UI.CoachMarks = false
+		}
+		m.saveConfig()
+	})
+	tip.Resize(fyne.NewSize(420, 240))
+	tip.Show()
+}
+
+// showStartupCoachMarks 起動時の利用状況に応じたヒントを表示
+func (m *MainApp) showStartupCoachMarks() {
+	if m.dashboard.reviewCard != nil {
+		m.showCoachMark(coachMarkReview)
+		return
+	}
+
+	sessions, err := m.db.GetRecentStudySessions(m.currentUser.ID, manualLogTipSessions)
+	if err != nil {
+		slog.Error("セッション取得エラー", "error", err)
+		return
+	}
+	if len(sessions) >= manualLogTipSessions {
+		m.showCoachMark(coachMarkManualLog)
+	}
+}
+
+// showReportCoachMark 学習レポートを作れるだけ問題を解いていれば、レポートのヒントを表示
+func (m *MainApp) showReportCoachMark() {
+	if !m.config.UI.CoachMarks || m.config.CoachMarkSeen(coachMarkReport) {
+		return
+	}
+	total, err := m.db.CountProblemResults(m.currentUser.ID)
+	if err != nil {
+		slog.Error("解答数取得エラー", "error", err)
+		return
+	}
+	if total >= reportTipProblems {
+		m.showCoachMark(coachMarkReport)
+	}
+}
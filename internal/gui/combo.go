@@ -0,0 +1,143 @@
+package gui
+
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/xp"
+)
+
+// comboSegments コンボメーターの目盛りの数（これ以上の連続正解は満タン表示）
+const comboSegments = 10
+
+var (
+	comboWarmColor = color.NRGBA{R: 0xf5, G: 0xa6, B: 0x23, A: 0xff} // ×1.2以上
+	comboHotColor  = color.NRGBA{R: 0xe8, G: 0x4a, B: 0x2f, A: 0xff} // ×1.5以上
+	comboMaxColor  = color.NRGBA{R: 0xc0, G: 0x1f, B: 0x8e, A: 0xff} // ×2.0
+)
+
+// ComboMeter 連続正解数と経験値の倍率を表示するメーター
+type ComboMeter struct {
+	widget.BaseWidget
+
+	combo int
+}
+
+// NewComboMeter コンボメーターを作成
+func NewComboMeter() *ComboMeter {
+	c := &ComboMeter{}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetCombo 連続正解数を更新
+func (c *ComboMeter) SetCombo(combo int) {
+	c.combo = combo
+	c.Refresh()
+}
+
+// CreateRenderer レンダラーを作成
+func (c *ComboMeter) CreateRenderer() fyne.WidgetRenderer {
+	r := &comboRenderer{
+		meter: c,
+		label: canvas.NewText("", theme.Color(theme.ColorNameForeground)),
+	}
+	r.label.TextStyle = fyne.TextStyle{Bold: true}
+	r.objects = append(r.objects, r.label)
+	for range comboSegments {
+		segment := canvas.NewRectangle(color.Transparent)
+		segment.CornerRadius = 2
+		r.segments = append(r.segments, segment)
+		r.objects = append(r.objects, segment)
+	}
+	r.Refresh()
+	return r
+}
+
+// comboRenderer コンボメーターの描画
+type comboRenderer struct {
+	meter *ComboMeter
+
+	label    *canvas.Text
+	segments []*canvas.Rectangle
+	objects  []fyne.CanvasObject
+}
+
+// MinSize 最小サイズ
+func (r *comboRenderer) MinSize() fyne.Size {
+	labelSize := fyne.MeasureText("🔥 10コンボ ×2.0", theme.TextSize(), r.label.TextStyle)
+	return fyne.NewSize(labelSize.Width+comboSegments*10, max(labelSize.Height, 20))
+}
+
+// Layout ラベルの右に目盛りを並べる
+func (r *comboRenderer) Layout(size fyne.Size) {
+	labelSize := fyne.MeasureText("🔥 10コンボ ×2.0", theme.TextSize(), r.label.TextStyle)
+	r.label.Move(fyne.NewPos(0, (size.Height-labelSize.Height)/2))
+	r.label.Resize(labelSize)
+
+	left := labelSize.Width + theme.Padding()
+	slot := (size.Width - left) / comboSegments
+	height := min(size.Height*0.6, 14)
+	for i, segment := range r.segments {
+		segment.Move(fyne.NewPos(left+slot*float32(i), (size.Height-height)/2))
+		segment.Resize(fyne.NewSize(slot*0.8, height))
+	}
+}
+
+// Refresh 連続正解数に合わせて色と表示を更新
+func (r *comboRenderer) Refresh() {
+	combo := r.meter.combo
+	multiplier := xp.ComboMultiplier(combo)
+
+	switch {
+	case combo == 0:
+		r.label.Text = "コンボなし"
+	case multiplier > 1:
+		r.label.Text = fmt.Sprintf("🔥 %dコンボ ×%.1f", combo, multiplier)
+	default:
+		r.label.Text = fmt.Sprintf("%dコンボ", combo)
+	}
+	r.label.Color = theme.Color(theme.ColorNameForeground)
+
+	lit := comboColor(multiplier)
+	unlit := theme.Color(theme.ColorNameDisabled)
+	for i, segment := range r.segments {
+		if i < combo {
+			segment.FillColor = lit
+		} else {
+			segment.FillColor = unlit
+		}
+	}
+
+	r.Layout(r.meter.Size())
+	for _, obj := range r.objects {
+		obj.Refresh()
+	}
+}
+
+// Objects 描画オブジェクト一覧
+func (r *comboRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+// Destroy 破棄処理
+func (r *comboRenderer) Destroy() {}
+
+// comboColor 倍率に応じた目盛りの色
+func comboColor(multiplier float64) color.Color {
+	switch {
+	case multiplier >= 2:
+		return comboMaxColor
+	case multiplier >= 1.5:
+		return comboHotColor
+	case multiplier > 1:
+		return comboWarmColor
+	default:
+		return theme.Color(theme.ColorNamePrimary)
+	}
+}
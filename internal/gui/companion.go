@@ -0,0 +1,125 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/server"
+)
+
+// startAPIServer 設定で有効なら連携アプリ向けのAPIサーバーを起動（制限モードでは使わない）
+func (m *MainApp) startAPIServer() {
+	if !m.config.Server.Enabled || m.config.Kiosk {
+		return
+	}
+	if err := m.apiServer.Start(); err != nil {
+		slog.Error("APIサーバー起動エラー", "error", err)
+	}
+}
+
+// createCompanionCard 連携アプリのカードを作成（スマートフォンのアプリなどから、このパソコンの問題・進み具合を使う）
+func (m *MainApp) createCompanionCard() *widget.Card {
+	description := widget.NewLabel("連携アプリから問題を解いたり、進み具合を見たりできます。接続できるのはこのパソコンの中からだけで、トークンが必要です。")
+	description.Wrapping = fyne.TextWrapWord
+
+	status := widget.NewLabel("")
+	token := widget.NewLabel("")
+	refresh := func() {
+		if addr := m.apiServer.Addr(); addr != "" {
+			status.SetText(fmt.Sprintf("http://%s/api/%s で待ち受けています", addr, server.APIVersion))
+		} else {
+			status.SetText("停止しています")
+		}
+		token.SetText(maskToken(m.config.Server.Token))
+	}
+
+	enableCheck := widget.NewCheck("連携アプリを使う", func(enabled bool) {
+		if enabled == m.config.Server.Enabled {
+			return
+		}
+		if enabled && m.config.Server.Token == "" {
+			newToken, err := server.NewToken()
+			if err != nil {
+				m.ShowErrorDialog("エラー", err.Error())
+				return
+			}
+			m.config.Server.Token = newToken
+		}
+		m.config.Server.Enabled = enabled
+		m.saveConfig()
+
+		var err error
+		if enabled {
+			err = m.apiServer.Start()
+		} else {
+			err = m.apiServer.Stop()
+		}
+		if err != nil {
+			m.ShowErrorDialog("エラー", err.Error())
+		}
+		refresh()
+	})
+	enableCheck.SetChecked(m.config.Server.Enabled)
+
+	copyBtn := widget.NewButton("📋 トークンをコピー", func() {
+		if m.config.Server.Token == "" {
+			m.ShowInfoDialog("連携アプリ", "連携アプリを使うと、トークンが作成されます。")
+			return
+		}
+		m.app.Clipboard().SetContent(m.config.Server.Token)
+		m.ShowInfoDialog("連携アプリ", "トークンをコピーしました。連携アプリの設定に貼り付けてください。")
+	})
+
+	regenerateBtn := widget.NewButton("🔄 トークンを作り直す", func() {
+		dialog.ShowConfirm("トークンを作り直す", "今のトークンを使っている連携アプリは、新しいトークンを設定するまで接続できなくなります。作り直しますか？", func(ok bool) {
+			if !ok {
+				return
+			}
+			newToken, err := server.NewToken()
+			if err != nil {
+				m.ShowErrorDialog("エラー", err.Error())
+				return
+			}
+			// トークンは起動したときに読み込むため、動いていれば起動し直す
+			running := m.apiServer.Addr() != ""
+			if running {
+				if err := m.apiServer.Stop(); err != nil {
+					slog.Error("APIサーバー停止エラー", "error", err)
+				}
+			}
+			m.config.Server.Token = newToken
+			m.saveConfig()
+			if running {
+				if err := m.apiServer.Start(); err != nil {
+					m.ShowErrorDialog("エラー", err.Error())
+				}
+			}
+			refresh()
+		}, m.window)
+	})
+
+	refresh()
+	return widget.NewCard("📱 連携アプリ", "", container.NewVBox(
+		description,
+		enableCheck,
+		status,
+		container.NewBorder(nil, nil, widget.NewLabel("トークン:"), nil, token),
+		container.NewGridWithColumns(2, copyBtn, regenerateBtn),
+	))
+}
+
+// maskToken 画面に出すトークン（のぞき見されないよう先頭だけ）
+func maskToken(token string) string {
+	if token == "" {
+		return "（未作成）"
+	}
+	if len(token) <= 8 {
+		return token
+	}
+	return token[:8] + "…"
+}
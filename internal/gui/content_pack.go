@@ -0,0 +1,232 @@
+package gui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/curriculum"
+	"github.com/okamyuji/studybuddy-ai/internal/flashcards"
+	"github.com/okamyuji/studybuddy-ai/internal/pack"
+)
+
+// 内容パックに含める内容（書き出すときに選ぶ）
+const (
+	packContentCurriculum = "編集した学習範囲の単元"
+	packContentProblems   = "読み込んだ内容パックの問題"
+	packContentFlashcards = "単語カード"
+)
+
+// createContentPackCard 設定画面の、先生が生徒に配る内容パックの書き出し・読み込み
+func (m *MainApp) createContentPackCard() *widget.Card {
+	description := widget.NewLabel("学習範囲の単元・AIを使えないときに出題する問題・単語カードを1つのファイル（" + pack.FileExtension + "）にまとめて、生徒に配れます。\n読み込むと、まだ持っていない単元・問題・カードだけを加えます。")
+	description.Wrapping = fyne.TextWrapWord
+
+	exportBtn := widget.NewButton("📦 内容パックを書き出す", m.exportContentPack)
+	importBtn := widget.NewButton("📥 内容パックを読み込む", m.importContentPack)
+
+	return widget.NewCard("内容パック", "",
+		container.NewVBox(description, container.NewHBox(exportBtn, importBtn)))
+}
+
+// exportContentPack パックの名前と含める内容を決めてもらい、内容パックを書き出す
+func (m *MainApp) exportContentPack() {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("例: 2学期 期末テスト対策")
+	nameEntry.Validator = func(value string) error {
+		if value == "" {
+			return fmt.Errorf("名前を入力してください")
+		}
+		return nil
+	}
+	authorEntry := widget.NewEntry()
+	authorEntry.SetPlaceHolder("例: 2年1組 担任")
+	descriptionEntry := widget.NewMultiLineEntry()
+	contents := widget.NewCheckGroup([]string{packContentCurriculum, packContentProblems, packContentFlashcards}, nil)
+	contents.SetSelected(contents.Options)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("名前", nameEntry),
+		widget.NewFormItem("作成者", authorEntry),
+		widget.NewFormItem("説明", descriptionEntry),
+		widget.NewFormItem("含める内容", contents),
+	}
+	form := dialog.NewForm("📦 内容パックを書き出す", "保存先を選ぶ", "キャンセル", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		p, err := m.buildContentPack(pack.Manifest{
+			Name:        nameEntry.Text,
+			Author:      authorEntry.Text,
+			Description: descriptionEntry.Text,
+			CreatedAt:   time.Now(),
+		}, contents.Selected)
+		if err != nil {
+			slog.Error("内容パックの作成エラー", "error", err)
+			m.ShowErrorDialog("エラー", fmt.Sprintf("内容パックを作成できませんでした: %v", err))
+			return
+		}
+		if p.TopicCount() == 0 && len(p.Problems) == 0 && p.CardCount() == 0 {
+			m.ShowInfoDialog("内容パック", "書き出す内容がありません。学習範囲の単元を編集するか、単語カードを作ってから書き出してください。")
+			return
+		}
+
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				m.ShowErrorDialog("エラー", fmt.Sprintf("保存先の選択に失敗しました: %v", err))
+				return
+			}
+			if writer == nil {
+				return // キャンセル
+			}
+			defer func() { _ = writer.Close() }()
+
+			if err := pack.Write(writer, p); err != nil {
+				slog.Error("内容パックの書き出しエラー", "error", err)
+				m.ShowErrorDialog("エラー", fmt.Sprintf("内容パックのファイルの作成に失敗しました: %v", err))
+				return
+			}
+			m.ShowInfoDialog("保存完了", fmt.Sprintf("%s に保存しました（単元 %d個・問題 %d問・単語カード %d枚）。\n生徒のパソコンの「設定」→「内容パックを読み込む」から開いてください。",
+				writer.URI().Name(), p.TopicCount(), len(p.Problems), p.CardCount()))
+		}, m.window)
+		saveDialog.SetFileName("studybuddy" + pack.FileExtension)
+		saveDialog.Show()
+	}, m.window)
+	form.Resize(fyne.NewSize(480, 420))
+	form.Show()
+}
+
+// buildContentPack 選んだ内容で内容パックを作る
+func (m *MainApp) buildContentPack(manifest pack.Manifest, selected []string) (*pack.Pack, error) {
+	p := &pack.Pack{Manifest: manifest}
+	if slices.Contains(selected, packContentCurriculum) {
+		p.Curriculum = m.aiEngine.Curriculum().Customized()
+	}
+	if slices.Contains(selected, packContentProblems) {
+		p.Problems = m.aiEngine.OfflineProblems()
+	}
+	if slices.Contains(selected, packContentFlashcards) {
+		for _, kind := range flashcards.DeckKinds {
+			deck, err := m.flashcards.Deck(m.currentUser.ID, kind)
+			if err != nil {
+				return nil, err
+			}
+			cards, err := m.db.GetFlashcards(deck.ID)
+			if err != nil {
+				return nil, fmt.Errorf("カード取得エラー: %w", err)
+			}
+			if len(cards) == 0 {
+				continue
+			}
+			packDeck := pack.Deck{Kind: kind}
+			for _, card := range cards {
+				packDeck.Cards = append(packDeck.Cards, pack.Card{Front: card.Front, Back: card.Back, Hint: card.Hint})
+			}
+			p.Decks = append(p.Decks, packDeck)
+		}
+	}
+	return p, nil
+}
+
+// importContentPack 内容パックのファイルを選んでもらい、中身を確認してから読み込む
+func (m *MainApp) importContentPack() {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("ファイルの選択に失敗しました: %v", err))
+			return
+		}
+		if reader == nil {
+			return // キャンセル
+		}
+		data, err := io.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("内容パックを読み込めませんでした: %v", err))
+			return
+		}
+		p, err := pack.Read(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			slog.Error("内容パックの読み込みエラー", "error", err)
+			m.ShowErrorDialog("エラー", fmt.Sprintf("内容パックを開けませんでした: %v", err))
+			return
+		}
+		m.confirmContentPackImport(p)
+	}, m.window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{pack.FileExtension}))
+	openDialog.Show()
+}
+
+// confirmContentPackImport 内容パックの中身を見せて、読み込むか確認する
+func (m *MainApp) confirmContentPackImport(p *pack.Pack) {
+	message := fmt.Sprintf("「%s」", p.Manifest.Name)
+	if p.Manifest.Author != "" {
+		message += fmt.Sprintf("（作成: %s）", p.Manifest.Author)
+	}
+	if p.Manifest.Description != "" {
+		message += "\n" + p.Manifest.Description
+	}
+	message += fmt.Sprintf("\n\n学習範囲の単元 %d個・問題 %d問・単語カード %d枚が入っています。\n読み込みますか？", p.TopicCount(), len(p.Problems), p.CardCount())
+
+	dialog.ShowConfirm("📥 内容パックを読み込む", message, func(ok bool) {
+		if !ok {
+			return
+		}
+		summary, err := m.installContentPack(p)
+		if err != nil {
+			slog.Error("内容パックの読み込みエラー", "error", err)
+			m.ShowErrorDialog("エラー", fmt.Sprintf("内容パックの読み込みに失敗しました: %v", err))
+			return
+		}
+		slog.Info("📦 内容パックを読み込みました", "name", p.Manifest.Name)
+		m.ShowInfoDialog("読み込み完了", summary)
+	}, m.window)
+}
+
+// installContentPack 内容パックの単元・問題・カードのうち、まだ持っていないものを加える
+func (m *MainApp) installContentPack(p *pack.Pack) (string, error) {
+	c := m.aiEngine.Curriculum()
+	topics := c.Merge(p.Curriculum)
+	if topics > 0 {
+		if err := curriculum.Save(curriculum.Path(), c); err != nil {
+			return "", err
+		}
+		m.aiEngine.SetCurriculum(c)
+	}
+
+	problems, added := pack.MergeProblems(m.aiEngine.OfflineProblems(), p.Problems)
+	if added > 0 {
+		if err := pack.SaveProblems(pack.ProblemsPath(), problems); err != nil {
+			return "", err
+		}
+		m.aiEngine.SetOfflineProblems(problems)
+	}
+
+	cards := 0
+	for _, packDeck := range p.Decks {
+		deck, err := m.flashcards.Deck(m.currentUser.ID, packDeck.Kind)
+		if err != nil {
+			return "", err
+		}
+		contents := make([]ai.FlashcardContent, len(packDeck.Cards))
+		for i, card := range packDeck.Cards {
+			contents[i] = ai.FlashcardContent{Front: card.Front, Back: card.Back, Hint: card.Hint}
+		}
+		n, err := m.flashcards.AddCards(deck.ID, contents)
+		cards += n
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("学習範囲の単元 %d個・問題 %d問・単語カード %d枚を加えました（すでにあるものは加えていません）。\n問題は、AIを使えないときに内蔵の問題より先に出題します。", topics, added, cards), nil
+}
@@ -0,0 +1,31 @@
+package gui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/okamyuji/studybuddy-ai/internal/crash"
+)
+
+// goSafe パニックしてもアプリを終了させないgoroutineで処理を行う
+// （パニックしたらクラッシュレポートを保存し、onPanicで画面を元に戻してからお知らせを表示する）
+func (m *MainApp) goSafe(task string, fn func(), onPanic func()) {
+	crash.Go(task, fn, func(path string) {
+		fyne.Do(func() {
+			if onPanic != nil {
+				onPanic()
+			}
+			m.showCrashDialog(task, path)
+		})
+	})
+}
+
+// showCrashDialog 処理の途中で予期しないエラーが起きたことを知らせる
+func (m *MainApp) showCrashDialog(task, path string) {
+	message := fmt.Sprintf("%sの途中で予期しないエラーが起きました。\nごめんなさい。もう一度試してみてください。アプリはこのまま使えます。", task)
+	if path != "" {
+		message += fmt.Sprintf("\n\n詳しい記録を保存しました（問い合わせのときに送ってください）:\n%s", path)
+	}
+	m.ShowErrorDialog("😵 うまくいきませんでした", message)
+}
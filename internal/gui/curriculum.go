@@ -0,0 +1,92 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/curriculum"
+)
+
+// curriculumGradeLabels 学習範囲を編集する学年の表示名
+var curriculumGradeLabels = []string{"中1", "中2", "中3"}
+
+// createCurriculumCard 保護者ダッシュボードの、学年・科目ごとの学習範囲（単元）の編集
+func (m *MainApp) createCurriculumCard(w fyne.Window) *widget.Card {
+	gradeSelect := widget.NewSelect(curriculumGradeLabels, nil)
+	subjectSelect := widget.NewSelect(config.Subjects, nil)
+	topicsEntry := widget.NewMultiLineEntry()
+	topicsEntry.SetPlaceHolder("1行に1つずつ単元を入力（例: 高校範囲：数と式、私立中対策：図形の応用）")
+	topicsEntry.SetMinRowsVisible(8)
+
+	selected := func() (int, string) {
+		return slices.Index(curriculumGradeLabels, gradeSelect.Selected) + 1, subjectSelect.Selected
+	}
+	showTopics := func() {
+		grade, subject := selected()
+		if grade == 0 || subject == "" {
+			return
+		}
+		topicsEntry.SetText(strings.Join(m.aiEngine.Curriculum().Topics(grade, subject), "\n"))
+	}
+	gradeSelect.OnChanged = func(string) { showTopics() }
+	subjectSelect.OnChanged = func(string) { showTopics() }
+
+	// 編集した学習範囲をファイルに保存し、次の問題の生成から使う
+	apply := func(grade int, subject string, topics []string) bool {
+		c := m.aiEngine.Curriculum()
+		c.Set(grade, subject, topics)
+		if err := curriculum.Save(curriculum.Path(), c); err != nil {
+			slog.Error("学習範囲保存エラー", "error", err)
+			dialog.ShowError(fmt.Errorf("学習範囲を保存できませんでした: %w", err), w)
+			return false
+		}
+		m.aiEngine.SetCurriculum(c)
+		return true
+	}
+
+	saveBtn := widget.NewButton("💾 単元を保存", func() {
+		grade, subject := selected()
+		var topics []string
+		for _, line := range strings.Split(topicsEntry.Text, "\n") {
+			if topic := strings.TrimSpace(line); topic != "" {
+				topics = append(topics, topic)
+			}
+		}
+		if apply(grade, subject, topics) {
+			showTopics()
+			dialog.ShowInformation("学習範囲", fmt.Sprintf("%s %sの単元を保存しました。次の問題から使います。", gradeSelect.Selected, subject), w)
+		}
+	})
+	resetBtn := widget.NewButton("↩️ 標準の単元に戻す", func() {
+		grade, subject := selected()
+		dialog.ShowConfirm("学習範囲", fmt.Sprintf("%s %sの単元を、学習指導要領に沿った標準の単元に戻しますか？", gradeSelect.Selected, subject), func(ok bool) {
+			if ok && apply(grade, subject, curriculum.Default().Topics(grade, subject)) {
+				showTopics()
+			}
+		}, w)
+	})
+
+	gradeSelect.SetSelected(curriculumGradeLabels[min(max(m.currentUser.Grade, 1), 3)-1])
+	subjectSelect.SetSelected(config.Subjects[0])
+
+	note := widget.NewLabel(fmt.Sprintf("AIが問題を作るときの学習範囲と、模擬テスト・出題の計画で選べる単元です。高校範囲や私立中対策など、教科書にない単元も加えられます。内容は %s に保存し、直接編集することもできます（全プロフィール共通）。", curriculum.Path()))
+	note.Wrapping = fyne.TextWrapWord
+
+	return widget.NewCard("📚 学習範囲（単元）", "", container.NewVBox(
+		note,
+		widget.NewForm(
+			widget.NewFormItem("学年", gradeSelect),
+			widget.NewFormItem("教科", subjectSelect),
+		),
+		topicsEntry,
+		container.NewHBox(saveBtn, resetBtn),
+	))
+}
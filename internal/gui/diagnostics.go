@@ -0,0 +1,109 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/feature"
+)
+
+// featureScopeLabels 機能フラグの有効範囲の表示名
+var featureScopeLabels = map[string]string{
+	feature.ScopeOff:     "無効",
+	feature.ScopeProfile: "このプロフィールだけ",
+	feature.ScopeAll:     "全員",
+}
+
+// createDiagnosticsCard 診断情報のカードを作成（動作の状態と機能フラグを確認し、問い合わせのときにコピーして送る。AIの文章の読みやすさも調べられる）
+func (m *MainApp) createDiagnosticsCard() *widget.Card {
+	description := widget.NewLabel("開発中の機能は、機能フラグで試しに使えます。うまく動かないときは、診断情報をコピーして問い合わせに添えてください。")
+	description.Wrapping = fyne.TextWrapWord
+
+	form := widget.NewForm()
+	for _, flag := range feature.Flags {
+		form.Append(flag.Title, m.featureScopeSelect(flag))
+	}
+
+	copyBtn := widget.NewButton("📋 診断情報をコピー", func() {
+		m.app.Clipboard().SetContent(m.diagnosticsText())
+		m.ShowInfoDialog("診断情報", "診断情報をコピーしました。")
+	})
+
+	readabilityBtn := widget.NewButton("📝 AIの文章の読みやすさ", m.showReadabilityReport)
+
+	return widget.NewCard("診断情報", "", container.NewVBox(description, form, container.NewHBox(copyBtn, readabilityBtn)))
+}
+
+// featureScopeSelect 機能フラグの有効範囲を切り替える選択肢（切り替えるとすぐに保存する）
+func (m *MainApp) featureScopeSelect(flag feature.Flag) *widget.Select {
+	options := []string{
+		featureScopeLabels[feature.ScopeOff],
+		featureScopeLabels[feature.ScopeProfile],
+		featureScopeLabels[feature.ScopeAll],
+	}
+	current := m.featureScope(flag.Name)
+
+	var scopeSelect *widget.Select
+	scopeSelect = widget.NewSelect(options, func(selected string) {
+		if selected == featureScopeLabels[current] {
+			return
+		}
+		// 全員で有効から変えるときは全員の指定を外し、ほかのプロフィールの指定はそのまま残す
+		var err error
+		if current == feature.ScopeAll {
+			err = m.features.SetEnabled(flag.Name, false)
+		}
+		switch {
+		case err != nil:
+		case selected == featureScopeLabels[feature.ScopeOff]:
+			err = m.features.SetProfileEnabled(flag.Name, m.currentUser.ID, false)
+		case selected == featureScopeLabels[feature.ScopeProfile]:
+			err = m.features.SetProfileEnabled(flag.Name, m.currentUser.ID, true)
+		case selected == featureScopeLabels[feature.ScopeAll]:
+			err = m.features.SetEnabled(flag.Name, true)
+		}
+		if err != nil {
+			slog.Error("機能フラグ切り替えエラー", "flag", flag.Name, "error", err)
+			m.ShowErrorDialog("機能フラグ", fmt.Sprintf("切り替えられませんでした: %v", err))
+			scopeSelect.SetSelected(featureScopeLabels[current])
+			return
+		}
+		current = m.featureScope(flag.Name)
+		slog.Info("機能フラグを切り替えました", "flag", flag.Name, "scope", current)
+		m.saveConfig()
+	})
+	scopeSelect.SetSelected(featureScopeLabels[current])
+	return scopeSelect
+}
+
+// featureScope このプロフィールから見た機能フラグの有効範囲（ほかのプロフィールだけで有効なら無効と表示）
+func (m *MainApp) featureScope(name string) string {
+	scope := m.features.Scope(name)
+	if scope == feature.ScopeProfile && !m.features.Enabled(name, m.currentUser.ID) {
+		return feature.ScopeOff
+	}
+	return scope
+}
+
+// diagnosticsText 診断情報（問い合わせに添える動作の状態。名前・学習内容は含めない）
+func (m *MainApp) diagnosticsText() string {
+	health := m.aiEngine.Health()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "データベース: %s\n", m.db.Driver())
+	fmt.Fprintf(&b, "AI: %s %s\n", health.Status.Icon(), health.Status.Label())
+	if health.Version != "" {
+		fmt.Fprintf(&b, "Ollama: %s\n", health.Version)
+	}
+	fmt.Fprintf(&b, "モデル: %s\n", m.aiEngine.GetCurrentModel())
+	fmt.Fprintf(&b, "ログの出力レベル: %s\n", m.config.Logging.Level)
+	fmt.Fprintf(&b, "制限モード: %t\n", m.config.Kiosk)
+	b.WriteString("\n機能フラグ:\n")
+	b.WriteString(m.features.Summary(m.currentUser.ID))
+	return b.String()
+}
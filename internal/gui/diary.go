@@ -0,0 +1,335 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/export"
+)
+
+// diaryDays 学習日記で選べる日数（今日からさかのぼる）
+const diaryDays = 14
+
+// diaryTopicLimit 下書きに含める1科目あたりの単元数
+const diaryTopicLimit = 3
+
+// diaryExportPeriods 学習日記を書き出す期間（日数）
+var diaryExportPeriods = []struct {
+	label string
+	days  int
+}{
+	{"1週間", 7},
+	{"2週間", 14},
+	{"1か月", 30},
+}
+
+// DiaryView 学習日記画面
+type DiaryView struct {
+	container    *fyne.Container
+	daySelect    *widget.Select
+	days         []time.Time // daySelectの選択肢の日付（新しい順）
+	entry        *widget.Entry
+	status       *widget.Label
+	draftBtn     *widget.Button
+	periodSelect *widget.Select
+	recordSelect *widget.Select // 学習記録表の様式
+	generation   int            // 日を選び直した回数（古い下書きの結果を表示しないため）
+	edited       bool           // 表示してから生徒が書き直した（保存前の文章を読み込み直しや下書きで消さないため）
+}
+
+// createDiaryView 学習日記画面を作成
+func (m *MainApp) createDiaryView() *DiaryView {
+	view := &DiaryView{}
+
+	view.daySelect = widget.NewSelect(nil, func(string) {
+		m.loadDiaryDay()
+	})
+
+	view.entry = widget.NewMultiLineEntry()
+	view.entry.Wrapping = fyne.TextWrapWord
+	view.entry.SetMinRowsVisible(6)
+	view.entry.SetPlaceHolder("今日の学習をふり返って書きましょう")
+	view.entry.OnChanged = func(string) { view.edited = true }
+
+	view.status = widget.NewLabel("")
+	view.status.Wrapping = fyne.TextWrapWord
+
+	view.draftBtn = widget.NewButton("✨ 記録から下書きを作る", func() {
+		m.draftDiary(m.selectedDiaryDay())
+	})
+	saveBtn := widget.NewButton("💾 保存", m.saveDiary)
+	saveBtn.Importance = widget.HighImportance
+
+	var periodLabels []string
+	for _, period := range diaryExportPeriods {
+		periodLabels = append(periodLabels, period.label)
+	}
+	view.periodSelect = widget.NewSelect(periodLabels, nil)
+	view.periodSelect.SetSelectedIndex(0)
+	exportBtn := widget.NewButton("📄 日記をPDFで書き出す", m.exportDiary)
+
+	var templateNames []string
+	for _, template := range export.StudyRecordTemplates {
+		templateNames = append(templateNames, template.Name)
+	}
+	view.recordSelect = widget.NewSelect(templateNames, nil)
+	view.recordSelect.SetSelectedIndex(0)
+	recordPDFBtn := widget.NewButton("🧾 記録表をPDFで書き出す", m.exportStudyRecordPDF)
+	recordExcelBtn := widget.NewButton("📊 記録表をExcelで書き出す", m.exportStudyRecordXLSX)
+
+	view.container = container.NewVBox(
+		widget.NewCard("📔 学習日記", "アプリの学習記録からAIが下書きを作ります。自分の言葉に直して保存しましょう",
+			container.NewVBox(
+				container.NewBorder(nil, nil, widget.NewLabel("日付:"), nil, view.daySelect),
+				view.entry,
+				view.status,
+				container.NewHBox(view.draftBtn, saveBtn),
+			),
+		),
+		widget.NewCard("学習記録として提出", "保存した日記をまとめてPDFにしたり、日付・教科・時間・ふり返りを学習記録表の様式に書き込んだりします",
+			container.NewVBox(
+				container.NewHBox(widget.NewLabel("期間:"), view.periodSelect, exportBtn),
+				container.NewBorder(nil, nil, widget.NewLabel("記録表の様式:"), nil, view.recordSelect),
+				container.NewHBox(recordPDFBtn, recordExcelBtn),
+			),
+		),
+	)
+	return view
+}
+
+// refreshDiary 日付の選択肢を今日から作り直し、選んでいる日の日記を読み込む
+func (m *MainApp) refreshDiary() {
+	view := m.diaryView
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if len(view.days) > 0 && view.days[0].Equal(today) && view.edited {
+		return // 保存していない文章を残す
+	}
+	selected := max(view.daySelect.SelectedIndex(), 0) // 今日から何日前か
+
+	view.days = view.days[:0]
+	var labels []string
+	for i := range diaryDays {
+		day := today.AddDate(0, 0, -i)
+		view.days = append(view.days, day)
+		label := fmt.Sprintf("%s（%s）", day.Format("1月2日"), weekdayNames[day.Weekday()])
+		if i == 0 {
+			label += " 今日"
+		}
+		labels = append(labels, label)
+	}
+	view.daySelect.Options = labels
+	view.daySelect.SetSelectedIndex(selected) // 選んだ日の日記を読み込む
+}
+
+// selectedDiaryDay 選んでいる日（0時）
+func (m *MainApp) selectedDiaryDay() time.Time {
+	view := m.diaryView
+	if i := view.daySelect.SelectedIndex(); i >= 0 && i < len(view.days) {
+		return view.days[i]
+	}
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// loadDiaryDay 選んだ日の日記を表示（まだ書いていない日は、学習の記録があればAIが下書きを作る）
+func (m *MainApp) loadDiaryDay() {
+	view := m.diaryView
+	view.generation++
+	day := m.selectedDiaryDay()
+
+	entry, err := m.db.GetDiaryEntry(m.currentUser.ID, day.Format("2006-01-02"))
+	if err != nil {
+		slog.Error("学習日記取得エラー", "error", err)
+	}
+	if entry != nil {
+		m.setDiaryText(entry.Content)
+		view.status.SetText(fmt.Sprintf("%sに保存しました。", entry.UpdatedAt.Format("1月2日 15:04")))
+		return
+	}
+
+	m.setDiaryText("")
+	view.status.SetText("")
+	m.draftDiary(day)
+}
+
+// setDiaryText 日記の欄に文章を表示（生徒が書き直した文章ではない）
+func (m *MainApp) setDiaryText(text string) {
+	m.diaryView.entry.SetText(text)
+	m.diaryView.edited = false
+}
+
+// draftDiary その日の学習の記録から、AIに日記の下書きを作ってもらう
+func (m *MainApp) draftDiary(day time.Time) {
+	view := m.diaryView
+	req, err := m.diaryRequest(day)
+	if err != nil {
+		slog.Error("学習記録取得エラー", "error", err)
+		view.status.SetText("学習の記録を読み込めませんでした。")
+		return
+	}
+	if len(req.Activities) == 0 {
+		view.status.SetText("この日は学習の記録がありません。自由に書いて保存できます。")
+		return
+	}
+
+	view.generation++
+	generation := view.generation
+	view.edited = false
+	view.draftBtn.Disable()
+	view.status.SetText("✨ 学習の記録から下書きを作っています...")
+
+	m.goSafe("下書きの作成", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		draft := m.aiEngine.GenerateDiaryDraft(ctx, req)
+
+		fyne.Do(func() {
+			view.draftBtn.Enable()
+			if view.generation != generation || view.edited {
+				return // 日を選び直した・作成中に書き始めた
+			}
+			m.setDiaryText(draft)
+			view.status.SetText("下書きです。自分の言葉に直して「保存」してください。")
+		})
+	}, func() {
+		view.draftBtn.Enable()
+		view.status.SetText("")
+	})
+}
+
+// diaryRequest その日の学習の記録を科目ごとにまとめる（科目は学習した順）
+func (m *MainApp) diaryRequest(day time.Time) (ai.DiaryRequest, error) {
+	req := ai.DiaryRequest{Grade: m.currentUser.Grade, Date: day}
+	end := day.AddDate(0, 0, 1)
+
+	sessions, err := m.db.GetStudySessionsBetween(m.currentUser.ID, day, end)
+	if err != nil {
+		return req, err
+	}
+	results, err := m.db.GetProblemResultsBetween(m.currentUser.ID, day, end)
+	if err != nil {
+		return req, err
+	}
+
+	index := make(map[string]int)
+	activity := func(subject string) *ai.DiaryActivity {
+		i, exists := index[subject]
+		if !exists {
+			i = len(req.Activities)
+			index[subject] = i
+			req.Activities = append(req.Activities, ai.DiaryActivity{Subject: subject})
+		}
+		return &req.Activities[i]
+	}
+
+	sessionSubjects := make(map[string]string)
+	for _, session := range sessions {
+		sessionSubjects[session.ID] = session.Subject
+		a := activity(session.Subject)
+		if session.EndTime != nil {
+			a.Minutes += int(session.EndTime.Sub(session.StartTime).Minutes())
+		}
+		if session.SessionType == database.SessionTypeManual && session.Note != "" {
+			a.Notes = append(a.Notes, session.Note)
+		}
+	}
+
+	topicCounts := make(map[string]map[string]int)
+	for _, result := range results {
+		subject, exists := sessionSubjects[result.SessionID]
+		if !exists {
+			continue // 前の日に始めたセッション
+		}
+		a := activity(subject)
+		a.Problems++
+		if result.IsCorrect {
+			a.Correct++
+		}
+		if result.ProblemType != "" {
+			if topicCounts[subject] == nil {
+				topicCounts[subject] = make(map[string]int)
+			}
+			topicCounts[subject][result.ProblemType]++
+		}
+	}
+
+	for i := range req.Activities {
+		a := &req.Activities[i]
+		counts := topicCounts[a.Subject]
+		for topic := range counts {
+			a.Topics = append(a.Topics, topic)
+		}
+		slices.SortFunc(a.Topics, func(x, y string) int {
+			if counts[x] != counts[y] {
+				return counts[y] - counts[x]
+			}
+			return strings.Compare(x, y)
+		})
+		a.Topics = a.Topics[:min(len(a.Topics), diaryTopicLimit)]
+	}
+
+	// 記録だけ始めて何もしなかったセッションは除く
+	req.Activities = slices.DeleteFunc(req.Activities, func(a ai.DiaryActivity) bool {
+		return a.Minutes == 0 && a.Problems == 0
+	})
+	return req, nil
+}
+
+// saveDiary 選んでいる日の日記を保存
+func (m *MainApp) saveDiary() {
+	view := m.diaryView
+	content := strings.TrimSpace(view.entry.Text)
+	if content == "" {
+		m.ShowInfoDialog("学習日記", "日記が空です。")
+		return
+	}
+
+	entry := &database.DiaryEntry{
+		UserID:    m.currentUser.ID,
+		Date:      m.selectedDiaryDay().Format("2006-01-02"),
+		Content:   content,
+		UpdatedAt: time.Now(),
+	}
+	if err := m.db.SaveDiaryEntry(entry); err != nil {
+		slog.Error("学習日記保存エラー", "error", err)
+		m.ShowErrorDialog("エラー", fmt.Sprintf("日記の保存に失敗しました: %v", err))
+		return
+	}
+	view.generation++ // 作成中の下書きで上書きしない
+	view.edited = false
+	view.status.SetText(fmt.Sprintf("%sに保存しました。", entry.UpdatedAt.Format("1月2日 15:04")))
+}
+
+// exportDiary 選んだ期間の保存した日記をPDFで書き出す
+func (m *MainApp) exportDiary() {
+	days := diaryExportPeriods[max(m.diaryView.periodSelect.SelectedIndex(), 0)].days
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today.AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+
+	entries, err := m.db.GetDiaryEntries(m.currentUser.ID, from, today.Format("2006-01-02"))
+	if err != nil {
+		m.ShowErrorDialog("エラー", fmt.Sprintf("日記を読み込めませんでした: %v", err))
+		return
+	}
+	if len(entries) == 0 {
+		m.ShowInfoDialog("学習日記", "この期間に保存した日記がありません。")
+		return
+	}
+
+	m.savePDF(fmt.Sprintf("学習記録_%s.pdf", today.Format("20060102")), func(w io.Writer, exporter *export.Exporter) error {
+		return exporter.WriteDiaryPDF(w, m.currentUser.Name, entries)
+	})
+}
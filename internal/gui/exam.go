@@ -0,0 +1,537 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+	"github.com/okamyuji/studybuddy-ai/internal/xp"
+)
+
+// examGenerateAttempts 模擬テストの問題1問あたりの生成の試行回数
+const examGenerateAttempts = 2
+
+// examWatermarkLines 模擬テストの結果に重ねる透かしの行数
+const examWatermarkLines = 8
+
+// examWatermarkColor 透かしの色（結果が読めるよう薄い灰色）
+var examWatermarkColor = color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0x38}
+
+// examView 模擬テスト画面（実施中はタブを隠して他の画面に移れないようにする）
+type examView struct {
+	container *fyne.Container
+
+	subject   string
+	problems  []*ai.Problem       // 正解と解説は提出まで封印している
+	key       *progress.AnswerKey // 封印した正解と解説
+	answers   []int               // 選んだ選択肢（未解答は-1）
+	timeSpent []time.Duration     // 問題ごとに表示していた時間
+	index     int                 // 表示中の問題
+	shownAt   time.Time           // 表示中の問題を表示した時刻
+	started   time.Time
+	deadline  time.Time
+	timeLimit time.Duration
+	session   *database.StudySession
+	stop      chan struct{}
+	submitted time.Time // 提出した時刻（提出前はゼロ）
+
+	timerLabel    *widget.Label
+	positionLabel *widget.Label
+	problemText   *widget.RichText
+	options       *fyne.Container
+	navigation    *fyne.Container
+	prevBtn       *widget.Button
+	nextBtn       *widget.Button
+}
+
+// showExamSetup 模擬テストの科目・単元・出題数・制限時間を選ぶダイアログを表示
+func (m *MainApp) showExamSetup() {
+	if m.exam != nil {
+		return
+	}
+
+	topicGroup := widget.NewCheckGroup(nil, nil)
+	subjectSelect := widget.NewSelect(m.config.OrderedSubjects(), func(subject string) {
+		topicGroup.Options = m.aiEngine.CurriculumTopics(m.currentUser.Grade, subject)
+		selected := m.config.ExamTopicsFor(subject)
+		if len(selected) == 0 {
+			selected = topicGroup.Options
+		}
+		topicGroup.SetSelected(selected)
+		topicGroup.Refresh()
+	})
+
+	exam := m.config.Learning.Exam
+	countLabel := widget.NewLabel(fmt.Sprintf("%d問", exam.ProblemCount))
+	countSlider := widget.NewSlider(config.MinExamProblems, config.MaxExamProblems)
+	countSlider.SetValue(float64(exam.ProblemCount))
+	countSlider.OnChanged = func(value float64) {
+		countLabel.SetText(fmt.Sprintf("%d問", int(value)))
+	}
+	timeLabel := widget.NewLabel(fmt.Sprintf("%d分", exam.TimeLimit))
+	timeSlider := widget.NewSlider(config.MinExamTimeLimit, config.MaxExamTimeLimit)
+	timeSlider.Step = 5
+	timeSlider.SetValue(float64(exam.TimeLimit))
+	timeSlider.OnChanged = func(value float64) {
+		timeLabel.SetText(fmt.Sprintf("%d分", int(value)))
+	}
+
+	subjectSelect.SetSelected(m.config.OrderedSubjects()[0])
+	topicScroll := container.NewVScroll(topicGroup)
+	topicScroll.SetMinSize(fyne.NewSize(0, 160))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("科目", subjectSelect),
+		widget.NewFormItem("出題する単元", topicScroll),
+		widget.NewFormItem("出題数", container.NewBorder(nil, nil, nil, countLabel, countSlider)),
+		widget.NewFormItem("制限時間", container.NewBorder(nil, nil, nil, timeLabel, timeSlider)),
+	}
+	form := dialog.NewForm("📝 模擬テスト", "問題を作成", "キャンセル", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		subject := subjectSelect.Selected
+		topics := topicGroup.Selected
+		if len(topics) == 0 {
+			m.ShowErrorDialog("模擬テスト", "出題する単元を1つ以上選んでください。")
+			return
+		}
+
+		// 全単元を選んだ場合は未設定として保存（学年が変わっても全範囲から出題）
+		if len(topics) == len(topicGroup.Options) {
+			m.config.SetExamTopics(subject, nil)
+		} else {
+			m.config.SetExamTopics(subject, topics)
+		}
+		m.config.Learning.Exam.ProblemCount = int(countSlider.Value)
+		m.config.Learning.Exam.TimeLimit = int(timeSlider.Value)
+		m.saveConfig()
+
+		m.prepareExam(subject, topics, int(countSlider.Value), time.Duration(timeSlider.Value)*time.Minute)
+	}, m.window)
+	form.Resize(fyne.NewSize(480, 520))
+	form.Show()
+}
+
+// prepareExam 模擬テストの問題をまとめて作成してから開始
+func (m *MainApp) prepareExam(subject string, topics []string, count int, timeLimit time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	bar := widget.NewProgressBar()
+	bar.Max = float64(count)
+	status := widget.NewLabel(fmt.Sprintf("問題を作成しています（0/%d）", count))
+	waiting := dialog.NewCustom("📝 模擬テストの準備中", "中止", container.NewVBox(status, bar), m.window)
+	waiting.SetOnClosed(cancel)
+	waiting.Show()
+
+	grade := m.currentUser.Grade
+	difficulty := m.config.DifficultyFor(subject)
+	m.goSafe("模擬テストの準備", func() {
+		var problems []*ai.Problem
+		recentHashes := m.recentProblemHashes(subject)
+		for i := 0; i < count && ctx.Err() == nil; i++ {
+			topic := topics[i%len(topics)]
+			studyContext := ai.StudyContext{
+				UserID:       m.currentUser.ID,
+				Subject:      subject,
+				Grade:        grade,
+				Difficulty:   difficulty,
+				Emotion:      "neutral",
+				Topic:        topic,
+				RecentHashes: recentHashes,
+			}
+
+			for attempt := 0; attempt < examGenerateAttempts; attempt++ {
+				problemCtx, problemCancel := context.WithTimeout(ctx, 30*time.Second)
+				problem, err := m.aiEngine.GeneratePersonalizedProblem(problemCtx, studyContext)
+				problemCancel()
+				if err != nil {
+					slog.Error("模擬テストの問題生成エラー", "topic", topic, "error", err)
+					continue
+				}
+				// 単元別の採点のため、出題を指定した単元で分類する
+				problem.ProblemType = topic
+				problems = append(problems, problem)
+				recentHashes = append(recentHashes, ai.SimilarityHash(problem.Description))
+				break
+			}
+
+			fyne.Do(func() {
+				bar.SetValue(float64(i + 1))
+				status.SetText(fmt.Sprintf("問題を作成しています（%d/%d）", i+1, count))
+			})
+		}
+
+		cancelled := ctx.Err() != nil
+		fyne.Do(func() {
+			if cancelled {
+				return
+			}
+			waiting.Hide()
+			if len(problems) == 0 {
+				m.ShowErrorDialog("模擬テスト", "問題を作成できませんでした。もう一度試してください。")
+				return
+			}
+			m.startExam(subject, problems, timeLimit)
+		})
+	}, func() {
+		waiting.Hide()
+	})
+}
+
+// startExam 模擬テストを開始（タブを隠し、制限時間のカウントダウンを始める）
+func (m *MainApp) startExam(subject string, problems []*ai.Problem, timeLimit time.Duration) {
+	// 学習中のセッションは終了しておく
+	m.studyView.finishSession(m)
+
+	now := time.Now()
+	session := &database.StudySession{
+		ID:          uuid.New().String(),
+		UserID:      m.currentUser.ID,
+		Subject:     subject,
+		StartTime:   now,
+		SessionType: database.SessionTypeExam,
+		Note:        fmt.Sprintf("%d問・制限時間%d分", len(problems), int(timeLimit.Minutes())),
+		CreatedAt:   now,
+	}
+	for _, problem := range problems {
+		problem.ShuffleOptions()
+	}
+	// 本番のテストと同じように、提出するまで正解と解説は見られないようにする
+	key, err := progress.SealAnswers(problems)
+	if err != nil {
+		slog.Error("模擬テストの封印エラー", "error", err)
+		m.ShowErrorDialog("模擬テスト", fmt.Sprintf("模擬テストを開始できませんでした: %v", err))
+		return
+	}
+	if err := m.db.CreateStudySession(session); err != nil {
+		m.ShowErrorDialog("模擬テスト", fmt.Sprintf("模擬テストを開始できませんでした: %v", err))
+		return
+	}
+	// テスト中はクイック質問で解説を聞けないようにする
+	if m.captureWindow != nil {
+		m.captureWindow.Close()
+	}
+
+	exam := &examView{
+		subject:   subject,
+		problems:  problems,
+		key:       key,
+		answers:   make([]int, len(problems)),
+		timeSpent: make([]time.Duration, len(problems)),
+		shownAt:   now,
+		started:   now,
+		deadline:  now.Add(timeLimit),
+		timeLimit: timeLimit,
+		session:   session,
+		stop:      make(chan struct{}),
+	}
+	for i := range exam.answers {
+		exam.answers[i] = -1
+	}
+	m.exam = exam
+
+	exam.timerLabel = widget.NewLabelWithStyle("", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true})
+	exam.positionLabel = widget.NewLabel("")
+	exam.problemText = widget.NewRichText()
+	exam.problemText.Wrapping = fyne.TextWrapWord
+	exam.options = container.NewVBox()
+	exam.navigation = container.NewGridWrap(fyne.NewSize(48, 36))
+	exam.prevBtn = widget.NewButton("◀ 前の問題", func() { m.showExamProblem(exam.index - 1) })
+	exam.nextBtn = widget.NewButton("次の問題 ▶", func() { m.showExamProblem(exam.index + 1) })
+	submitBtn := widget.NewButton("✅ 提出する", func() { m.confirmExamSubmit() })
+	submitBtn.Importance = widget.HighImportance
+
+	header := container.NewBorder(nil, nil,
+		widget.NewLabelWithStyle(fmt.Sprintf("📝 %sの模擬テスト", subject), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		exam.timerLabel, exam.positionLabel)
+	footer := container.NewVBox(
+		container.NewGridWithColumns(3, exam.prevBtn, exam.nextBtn, submitBtn),
+		widget.NewLabel("問題番号を押すと、その問題に移動できます（✔は解答済み）"),
+		exam.navigation,
+	)
+	exam.container = container.NewBorder(header, footer, nil, nil,
+		container.NewVScroll(container.NewVBox(exam.problemText, exam.options)))
+
+	m.window.SetContent(exam.container)
+	m.showExamProblem(0)
+	m.updateExamTimer()
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-exam.stop:
+				return
+			case <-ticker.C:
+				fyne.Do(func() {
+					if m.exam == exam {
+						m.updateExamTimer()
+					}
+				})
+			}
+		}
+	}()
+}
+
+// updateExamTimer 残り時間を更新し、時間切れなら自動で提出
+func (m *MainApp) updateExamTimer() {
+	remaining := time.Until(m.exam.deadline)
+	if remaining <= 0 {
+		m.submitExam()
+		m.ShowInfoDialog("⏰ 時間切れ", "制限時間になったので、ここまでの解答で採点しました。")
+		return
+	}
+	m.exam.timerLabel.SetText(fmt.Sprintf("⏱ 残り %s", formatClock(remaining)))
+	if remaining <= time.Minute {
+		m.exam.timerLabel.Importance = widget.DangerImportance
+		m.exam.timerLabel.Refresh()
+	}
+}
+
+// showExamProblem 指定した番号の問題を表示
+func (m *MainApp) showExamProblem(index int) {
+	exam := m.exam
+	if index < 0 || index >= len(exam.problems) {
+		return
+	}
+
+	// 表示していた問題の時間を記録
+	now := time.Now()
+	exam.timeSpent[exam.index] += now.Sub(exam.shownAt)
+	exam.index = index
+	exam.shownAt = now
+
+	problem := exam.problems[index]
+	exam.positionLabel.SetText(fmt.Sprintf("問%d / %d", index+1, len(exam.problems)))
+	exam.problemText.ParseMarkdown(fmt.Sprintf("## 問%d %s\n\n**%s**", index+1, problem.Title, problem.Description))
+
+	exam.options.RemoveAll()
+	if problem.Audio != "" {
+		exam.options.Add(m.newListeningControls(problem.Audio, false))
+	}
+	if problem.Figure != nil {
+		exam.options.Add(newFigureView(problem.Figure))
+	}
+	exam.options.Add(newOptionButtons(problem.Options, exam.answers[index], func(option int) {
+		exam.answers[index] = option
+		m.showExamProblem(index)
+	}))
+
+	exam.navigation.RemoveAll()
+	for i := range exam.problems {
+		label := fmt.Sprintf("%d", i+1)
+		if exam.answers[i] >= 0 {
+			label += "✔"
+		}
+		btn := widget.NewButton(label, func() { m.showExamProblem(i) })
+		if i == index {
+			btn.Importance = widget.HighImportance
+		}
+		exam.navigation.Add(btn)
+	}
+
+	if index == 0 {
+		exam.prevBtn.Disable()
+	} else {
+		exam.prevBtn.Enable()
+	}
+	if index == len(exam.problems)-1 {
+		exam.nextBtn.Disable()
+	} else {
+		exam.nextBtn.Enable()
+	}
+}
+
+// confirmExamSubmit 未解答の問題があれば確認してから提出
+func (m *MainApp) confirmExamSubmit() {
+	unanswered := 0
+	for _, answer := range m.exam.answers {
+		if answer < 0 {
+			unanswered++
+		}
+	}
+	if unanswered == 0 {
+		m.submitExam()
+		return
+	}
+
+	dialog.ShowConfirm("提出の確認",
+		fmt.Sprintf("まだ解答していない問題が%d問あります。提出しますか？", unanswered),
+		func(submit bool) {
+			if submit && m.exam != nil {
+				m.submitExam()
+			}
+		}, m.window)
+}
+
+// submitExam 模擬テストを採点して結果を表示
+func (m *MainApp) submitExam() {
+	exam := m.exam
+	report := m.finishExam()
+	if report == nil {
+		return
+	}
+	m.window.SetContent(m.examReportView(exam, report))
+}
+
+// finishExam 模擬テストの解答を保存して採点（提出済みならnil）
+func (m *MainApp) finishExam() *progress.ExamReport {
+	exam := m.exam
+	if exam == nil || !exam.submitted.IsZero() {
+		return nil
+	}
+	now := time.Now()
+	exam.submitted = now
+	close(exam.stop)
+	m.exam = nil
+
+	exam.timeSpent[exam.index] += now.Sub(exam.shownAt)
+	if err := exam.key.Open(exam.problems); err != nil {
+		slog.Error("模擬テストの正解の復号エラー", "error", err)
+	}
+
+	var results []database.ProblemResult
+	for i, problem := range exam.problems {
+		answer := exam.answers[i]
+		result := database.ProblemResult{
+			ID:              uuid.New().String(),
+			SessionID:       exam.session.ID,
+			ProblemType:     problem.ProblemType,
+			Difficulty:      problem.Difficulty,
+			IsCorrect:       answer == problem.CorrectAnswer,
+			TimeTaken:       int(exam.timeSpent[i].Seconds()),
+			EmotionAtAnswer: "neutral",
+			UserAnswer:      progress.ExamUnanswered,
+			CreatedAt:       exam.started.Add(time.Duration(i) * time.Millisecond), // 出題順に並べるため
+			AnswerPosition:  answer,
+			OptionCount:     len(problem.Options),
+		}
+		recordProblemContent(&result, problem)
+		m.recordProblemQuality(&result, problem)
+		if answer >= 0 {
+			result.UserAnswer = problem.Options[answer]
+		}
+		results = append(results, result)
+		exam.session.TotalProblems++
+		if result.IsCorrect {
+			exam.session.CorrectAnswers++
+		}
+	}
+
+	// 全問の結果・単元別の統計・経験値は、それぞれまとめて保存する
+	var records []database.AnswerRecord
+	var answers []xp.Answer
+	for i := range results {
+		record := database.AnswerRecord{Result: &results[i], AnsweredAt: now}
+		if exam.answers[i] >= 0 {
+			record.Subject = exam.subject
+			answers = append(answers, xp.Answer{IsCorrect: results[i].IsCorrect, Difficulty: results[i].Difficulty, TimeTaken: results[i].TimeTaken})
+		}
+		records = append(records, record)
+	}
+	if err := m.db.RecordAnswers(m.currentUser.ID, records); err != nil {
+		slog.Error("結果保存エラー", "error", err)
+	}
+	if _, err := m.xpService.GrantAnswers(m.currentUser.ID, answers); err != nil {
+		slog.Error("経験値付与エラー", "error", err)
+	}
+
+	exam.session.EndTime = &now
+	if err := m.db.UpdateStudySession(exam.session); err != nil {
+		slog.Error("セッション終了処理エラー", "error", err)
+	}
+
+	report := progress.BuildExamReport(exam.subject, results, exam.timeLimit, now.Sub(exam.started))
+	slog.Info("📝 模擬テスト採点", "subject", exam.subject, "score", report.Score, "correct", report.CorrectAnswers, "total", report.TotalProblems)
+	return report
+}
+
+// examReportView 模擬テストの採点結果画面を作成
+func (m *MainApp) examReportView(exam *examView, report *progress.ExamReport) fyne.CanvasObject {
+	score := widget.NewRichTextFromMarkdown(fmt.Sprintf("# %d点", report.Score))
+	summary := widget.NewLabel(fmt.Sprintf("正解 %d / %d問（未解答 %d問）　かかった時間 %s / %s",
+		report.CorrectAnswers, report.TotalProblems, report.Unanswered,
+		formatClock(report.TimeUsed), formatClock(report.TimeLimit)))
+
+	topics := container.NewVBox()
+	for _, topic := range report.ByTopic {
+		bar := widget.NewProgressBar()
+		bar.SetValue(topic.AccuracyRate)
+		bar.TextFormatter = func() string {
+			return fmt.Sprintf("%d/%d問", topic.CorrectAnswers, topic.TotalProblems)
+		}
+		topics.Add(container.NewBorder(nil, nil, widget.NewLabel(topic.Topic), nil, bar))
+	}
+
+	mistakes := container.NewVBox()
+	for i, problem := range exam.problems {
+		answer := exam.answers[i]
+		if answer == problem.CorrectAnswer {
+			continue
+		}
+		yourAnswer := progress.ExamUnanswered
+		if answer >= 0 {
+			yourAnswer = problem.Options[answer]
+		}
+		description := problem.Description
+		if problem.Audio != "" {
+			description += "\n読み上げた英文: " + problem.Audio
+		}
+		if problem.Figure != nil {
+			description += "\n図: " + problem.Figure.Caption
+		}
+		text := widget.NewLabel(fmt.Sprintf("問%d %s\nあなたの解答: %s　正解: %s\n%s",
+			i+1, description, yourAnswer, problem.Options[problem.CorrectAnswer], problem.Explanation))
+		text.Wrapping = fyne.TextWrapWord
+		mistakes.Add(text)
+		mistakes.Add(widget.NewSeparator())
+	}
+	if len(mistakes.Objects) == 0 {
+		mistakes.Add(widget.NewLabel("全問正解です！すばらしい！"))
+	}
+
+	backBtn := widget.NewButton("ホームに戻る", func() {
+		m.window.SetContent(m.content)
+	})
+	backBtn.Importance = widget.HighImportance
+
+	content := container.NewVBox(
+		widget.NewCard(fmt.Sprintf("📝 %sの模擬テスト 結果", report.Subject), "", container.NewVBox(score, summary)),
+		widget.NewCard("単元別の正解数", "正解率の低い単元から表示", topics),
+		widget.NewCard("間違えた問題の見直し", "", mistakes),
+	)
+	watermark := examWatermark(m.currentUser.Name, exam.submitted, exam.session.ID)
+	return container.NewBorder(nil, backBtn, nil, nil, container.NewStack(container.NewVScroll(content), watermark))
+}
+
+// examWatermark 模擬テストの結果に重ねる透かし（名前・提出日時・テストの番号で、結果の画面を撮っても出どころがわかる）
+func examWatermark(name string, submitted time.Time, sessionID string) fyne.CanvasObject {
+	text := fmt.Sprintf("模擬テスト %s %s #%s", name, submitted.Format("2006/01/02 15:04"), sessionID[:8])
+	lines := container.NewVBox()
+	for i := range examWatermarkLines {
+		line := canvas.NewText(text, examWatermarkColor)
+		line.TextSize = 20
+		line.TextStyle = fyne.TextStyle{Bold: true}
+		line.Alignment = []fyne.TextAlign{fyne.TextAlignLeading, fyne.TextAlignCenter, fyne.TextAlignTrailing}[i%3]
+		lines.Add(line)
+		lines.Add(layout.NewSpacer())
+	}
+	return lines
+}
@@ -0,0 +1,195 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// フィードバックのタブ名
+const (
+	feedbackTabExplanation = "📖 解説"
+	feedbackTabCalculation = "🧮 計算過程"
+	feedbackTabTips        = "💡 コツ"
+)
+
+// verbosityLabels 説明の詳しさの表示名
+var verbosityLabels = map[string]string{
+	config.VerbosityConcise:  "簡潔",
+	config.VerbosityNormal:   "普通",
+	config.VerbosityDetailed: "詳しい",
+}
+
+// readingLevelLabels 解説の表現の表示名
+var readingLevelLabels = map[string]string{
+	config.ReadingLevelEasy:   "中1向けのやさしい表現",
+	config.ReadingLevelStrict: "受験向けの厳密な表現",
+}
+
+// strongSubjects 関連付け説明のたとえに使う得意な科目（関連付け説明を使わないときは空）
+func (m *MainApp) strongSubjects(userID string) []string {
+	if !m.config.AI.Analogies {
+		return nil
+	}
+	strengths, err := m.progressManager.StrongSubjects(userID, time.Now())
+	if err != nil {
+		slog.Error("得意な科目の取得エラー", "error", err)
+		return nil
+	}
+	var subjects []string
+	for _, strength := range strengths {
+		subjects = append(subjects, strength.Subject)
+	}
+	return subjects
+}
+
+// updateFeedbackPaneSize 解説欄の大きさ（幅は解説欄、高さは画面に見えている範囲）をAIに伝え、解説の長さの目安にする
+func (s *StudyView) updateFeedbackPaneSize(mainApp *MainApp) {
+	mainApp.aiEngine.SetPaneSize(s.feedbackCard.Size().Width, s.scroll.Size().Height)
+}
+
+// newFeedbackTabs フィードバックを「解説・計算過程・コツ」のタブに分けて表示（前回選んだタブを開く）
+func (s *StudyView) newFeedbackTabs(problem *ai.Problem, correctAnswer string, feedback *ai.FeedbackResponse) fyne.CanvasObject {
+	explanation := feedback.Explanation
+	if explanation == "" {
+		explanation = problem.Explanation
+	}
+	var tabs []*container.TabItem
+	analogy := ""
+	if feedback.Analogy != "" {
+		analogy = "**🔗 たとえると:** " + feedback.Analogy
+	}
+	tabs = append(tabs, container.NewTabItem(feedbackTabExplanation, newFeedbackText(
+		fmt.Sprintf("**正解:** %s", correctAnswer),
+		explanation,
+		analogy,
+	)))
+
+	if feedback.Calculation != "" {
+		tabs = append(tabs, container.NewTabItem(feedbackTabCalculation, newFeedbackText(
+			formatCalculation(feedback.Calculation),
+		)))
+	}
+
+	if feedback.TipOfDay != "" || feedback.NextSteps != "" {
+		var tips []string
+		if feedback.TipOfDay != "" {
+			tips = append(tips, "**コツ:** "+feedback.TipOfDay)
+		}
+		if feedback.NextSteps != "" {
+			tips = append(tips, "**次のステップ:** "+feedback.NextSteps)
+		}
+		tabs = append(tabs, container.NewTabItem(feedbackTabTips, newFeedbackText(tips...)))
+	}
+
+	appTabs := container.NewAppTabs(tabs...)
+	for _, tab := range tabs {
+		if tab.Text == s.feedbackTab {
+			appTabs.Select(tab)
+		}
+	}
+	appTabs.OnSelected = func(tab *container.TabItem) {
+		s.feedbackTab = tab.Text
+	}
+
+	header := []string{"**" + feedback.Message + "**"}
+	if feedback.Encouragement != "" {
+		header = append(header, feedback.Encouragement)
+	}
+	return container.NewVBox(newFeedbackText(header...), appTabs)
+}
+
+// newFeedbackText 段落をまとめた折り返し付きのテキストを作成（空の段落は省略）
+func newFeedbackText(paragraphs ...string) *widget.RichText {
+	var nonEmpty []string
+	for _, p := range paragraphs {
+		if strings.TrimSpace(p) != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	text := widget.NewRichTextFromMarkdown(strings.Join(nonEmpty, "\n\n"))
+	text.Wrapping = fyne.TextWrapWord
+	return text
+}
+
+// formatCalculation 「,」区切りの計算過程を1行ずつの番号付きリストにする
+func formatCalculation(calculation string) string {
+	steps := strings.FieldsFunc(calculation, func(r rune) bool {
+		return r == ',' || r == '\n'
+	})
+	var lines []string
+	for _, step := range steps {
+		if step = strings.TrimSpace(step); step != "" {
+			lines = append(lines, fmt.Sprintf("%d. %s", len(lines)+1, step))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// newSolutionWalkthrough 数学の問題を間違えたときに、解き方を1ステップずつ表示する欄を作成
+func (s *StudyView) newSolutionWalkthrough(problem ai.Problem, userAnswer string, mainApp *MainApp) fyne.CanvasObject {
+	walkthrough := container.NewVBox()
+
+	var startBtn *widget.Button
+	startBtn = widget.NewButton("🪜 解き方を1ステップずつ見る", func() {
+		startBtn.Disable()
+		startBtn.SetText("🤖 ステップを作成中...")
+
+		mainApp.goSafe("解き方のステップの作成", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			solution := mainApp.aiEngine.GenerateSolutionSteps(ctx, problem, userAnswer)
+			fyne.Do(func() {
+				walkthrough.Remove(startBtn)
+				if len(solution) == 0 {
+					walkthrough.Add(widget.NewLabel("ステップを作成できませんでした。解説タブを見てみましょう。"))
+					return
+				}
+				walkthrough.Add(newSolutionSteps(solution))
+			})
+		}, func() {
+			startBtn.Enable()
+			startBtn.SetText("🪜 解き方を1ステップずつ見る")
+		})
+	})
+
+	walkthrough.Add(startBtn)
+	return walkthrough
+}
+
+// newSolutionSteps ステップを1つずつ表示し、「次のステップ」ボタンで次を表示するカードを作成
+func newSolutionSteps(solution []string) fyne.CanvasObject {
+	steps := container.NewVBox()
+	shown := 0
+	progress := widget.NewLabel("")
+	var nextBtn *widget.Button
+	next := func() {
+		step := widget.NewRichTextFromMarkdown(fmt.Sprintf("**ステップ%d.** %s", shown+1, solution[shown]))
+		step.Wrapping = fyne.TextWrapWord
+		steps.Add(step)
+		shown++
+		progress.SetText(fmt.Sprintf("%d / %d", shown, len(solution)))
+		if shown == len(solution) {
+			nextBtn.SetText("✅ 最後まで確認しました")
+			nextBtn.Disable()
+		}
+	}
+	nextBtn = widget.NewButton("次のステップ ▶", next)
+	nextBtn.Importance = widget.HighImportance
+
+	next()
+	return widget.NewCard("🪜 解き方", "", container.NewVBox(
+		steps,
+		container.NewBorder(nil, nil, nil, progress, nextBtn),
+	))
+}
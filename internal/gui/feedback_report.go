@@ -0,0 +1,244 @@
+package gui
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/crash"
+	"github.com/okamyuji/studybuddy-ai/internal/feedback"
+	"github.com/okamyuji/studybuddy-ai/internal/logging"
+	"github.com/okamyuji/studybuddy-ai/internal/privacy"
+)
+
+// 問い合わせに添える記録
+const (
+	feedbackLogLines     = 200 // 最近のログの行数
+	feedbackCrashReports = 3   // クラッシュレポートの数（新しいものから）
+)
+
+// showFeedbackForm 「フィードバックを送る」: 説明・画面の画像・最近のログ・バージョンを1つのファイルにまとめて保存し、
+// GitHubの問い合わせの画面を入力済みで開けるようにする（自動では送信しない。名前などの個人情報は取り除く）
+func (m *MainApp) showFeedbackForm() {
+	// フォームを開く前の画面を撮っておく
+	var screen *feedback.Attachment
+	if data, err := m.captureScreen(); err != nil {
+		slog.Warn("画面の画像を撮れませんでした", "error", err)
+	} else {
+		screen = &feedback.Attachment{Name: "screen.png", Data: data}
+	}
+
+	labels := make([]string, len(feedback.Kinds))
+	for i, kind := range feedback.Kinds {
+		labels[i] = feedback.KindLabels[kind]
+	}
+	kindRadio := widget.NewRadioGroup(labels, nil)
+	kindRadio.Horizontal = true
+	kindRadio.Required = true
+	kindRadio.SetSelected(labels[0])
+
+	description := widget.NewMultiLineEntry()
+	description.Wrapping = fyne.TextWrapWord
+	description.SetMinRowsVisible(5)
+	description.SetPlaceHolder("どんなときに、どうなりましたか？（例: 数学を選ぶと「問題を作成中」のまま進まない）")
+
+	screenCheck := widget.NewCheck("今の画面の画像を添える", nil)
+	if screen != nil {
+		screenCheck.SetChecked(true)
+	} else {
+		screenCheck.Disable()
+	}
+	logsCheck := widget.NewCheck("最近のログと診断情報を添える", nil)
+	logsCheck.SetChecked(true)
+
+	var images []feedback.Attachment
+	imagesLabel := widget.NewLabel("")
+	addImageBtn := widget.NewButton("🖼 画像を追加", func() {
+		m.chooseFeedbackImage(len(images), func(image feedback.Attachment) {
+			images = append(images, image)
+			imagesLabel.SetText(fmt.Sprintf("追加した画像: %d枚", len(images)))
+		})
+	})
+
+	build := func() *feedback.Report {
+		report := &feedback.Report{Version: crash.Version(), CreatedAt: time.Now()}
+		for _, kind := range feedback.Kinds {
+			if feedback.KindLabels[kind] == kindRadio.Selected {
+				report.Kind = kind
+			}
+		}
+		redactor := m.feedbackRedactor()
+		report.Description = redactor.Redact(description.Text)
+		if screenCheck.Checked && screen != nil {
+			report.Screenshots = append(report.Screenshots, *screen)
+		}
+		report.Screenshots = append(report.Screenshots, images...)
+		if logsCheck.Checked {
+			report.Diagnostics = redactor.Redact(m.diagnosticsText())
+			logs, err := logging.Tail(config.GetLogDir(), feedbackLogLines)
+			if err != nil {
+				slog.Error("ログの読み込みエラー", "error", err)
+			}
+			report.Logs = redactor.Redact(logs)
+			for _, path := range crash.RecentReports(feedbackCrashReports) {
+				if data, err := os.ReadFile(path); err == nil {
+					report.CrashReports = append(report.CrashReports, feedback.Attachment{
+						Name: filepath.Base(path), Data: []byte(redactor.Redact(string(data))),
+					})
+				}
+			}
+		}
+		return report
+	}
+
+	note := widget.NewLabel("ファイルはこのパソコンに保存するだけで、自動では送信しません。名前・メールアドレス・電話番号・APIキーなどは取り除きます。" +
+		"保存したら、GitHubの問い合わせの画面に添付するか、メールで送ってください。")
+	note.Wrapping = fyne.TextWrapWord
+
+	previewBtn := widget.NewButton("🔍 送る内容を確認", func() {
+		m.showFeedbackPreview(build())
+	})
+	saveBtn := widget.NewButton("💾 ファイルに保存", func() {
+		if strings.TrimSpace(description.Text) == "" {
+			m.ShowInfoDialog("フィードバックを送る", "どんなときに、どうなったかを書いてください。")
+			return
+		}
+		m.saveFeedback(build())
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	content := container.NewVBox(
+		kindRadio,
+		description,
+		screenCheck,
+		logsCheck,
+		container.NewBorder(nil, nil, addImageBtn, nil, imagesLabel),
+		note,
+		container.NewHBox(previewBtn, saveBtn),
+	)
+	popup := dialog.NewCustom("✉️ フィードバックを送る", "閉じる", container.NewVScroll(content), m.window)
+	popup.Resize(fyne.NewSize(560, 560))
+	popup.Show()
+}
+
+// captureScreen アプリの画面の画像（PNG）
+func (m *MainApp) captureScreen() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, m.window.Canvas().Capture()); err != nil {
+		return nil, fmt.Errorf("画面の画像の作成エラー: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// chooseFeedbackImage 問い合わせに添える画像（PNG・JPEG）を選んでもらう
+func (m *MainApp) chooseFeedbackImage(count int, onChosen func(feedback.Attachment)) {
+	if count >= feedback.MaxScreenshots {
+		m.ShowInfoDialog("フィードバックを送る", fmt.Sprintf("画像は%d枚まで添えられます。", feedback.MaxScreenshots))
+		return
+	}
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("ファイルの選択に失敗しました: %v", err))
+			return
+		}
+		if reader == nil {
+			return // キャンセル
+		}
+		defer func() { _ = reader.Close() }()
+		data, err := io.ReadAll(io.LimitReader(reader, feedback.MaxScreenshotBytes+1))
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("画像を読み込めませんでした: %v", err))
+			return
+		}
+		if len(data) > feedback.MaxScreenshotBytes {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("画像が大きすぎます（%dMBまで）", feedback.MaxScreenshotBytes>>20))
+			return
+		}
+		onChosen(feedback.Attachment{Name: fmt.Sprintf("image-%d%s", count+1, reader.URI().Extension()), Data: data})
+	}, m.window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg"}))
+	openDialog.Show()
+}
+
+// feedbackRedactor 問い合わせから取り除く個人情報（このパソコンのアカウント・生徒の名前とID・APIキーなどの秘密の値）
+func (m *MainApp) feedbackRedactor() *privacy.Redactor {
+	redactor := privacy.NewRedactor()
+	redactor.SetLocalAccount()
+	redactor.Set("api_key", m.config.AI.Cloud.APIKey, privacy.RedactedSecret)
+	redactor.Set("server_token", m.config.Server.Token, privacy.RedactedSecret)
+	redactor.Set("database_url", m.config.Database.URL, privacy.RedactedSecret)
+	if m.currentUser != nil {
+		redactor.Set("name", m.currentUser.Name, privacy.RedactedAccount)
+		redactor.Set("user_id", m.currentUser.ID, privacy.RedactedAccount)
+	}
+	return redactor
+}
+
+// showFeedbackPreview 問い合わせのファイルに入れる内容（本文とログ）を表示
+func (m *MainApp) showFeedbackPreview(report *feedback.Report) {
+	var b strings.Builder
+	b.WriteString(report.Summary())
+	fmt.Fprintf(&b, "\n## 添付\n\n- 画像: %d枚\n- クラッシュレポート: %d件\n", len(report.Screenshots), len(report.CrashReports))
+	if report.Logs != "" {
+		fmt.Fprintf(&b, "\n## 最近のログ\n\n%s\n", report.Logs)
+	}
+
+	text := widget.NewLabel(b.String())
+	text.Wrapping = fyne.TextWrapWord
+	popup := dialog.NewCustom("🔍 送る内容", "閉じる", container.NewVScroll(text), m.window)
+	popup.Resize(fyne.NewSize(600, 520))
+	popup.Show()
+}
+
+// saveFeedback 問い合わせのファイルを保存し、GitHubの問い合わせの画面を開くかたずねる
+func (m *MainApp) saveFeedback(report *feedback.Report) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("保存先の選択に失敗しました: %v", err))
+			return
+		}
+		if writer == nil {
+			return // キャンセル
+		}
+		err = report.WriteZip(writer)
+		_ = writer.Close()
+		if err != nil {
+			slog.Error("問い合わせのファイルの保存エラー", "error", err)
+			m.ShowErrorDialog("エラー", fmt.Sprintf("ファイルを保存できませんでした: %v", err))
+			return
+		}
+		slog.Info("✉️ 問い合わせのファイルを保存しました", "file", writer.URI().Name())
+
+		dialog.ShowConfirm("保存しました", fmt.Sprintf(
+			"%s に保存しました。\nGitHubの問い合わせの画面を開きますか？（説明は入力済みです。保存したファイルを添付してください）",
+			writer.URI().Name()), func(open bool) {
+			if !open {
+				return
+			}
+			u, err := url.Parse(report.IssueURL())
+			if err == nil {
+				err = m.app.OpenURL(u)
+			}
+			if err != nil {
+				slog.Error("問い合わせの画面を開けませんでした", "error", err)
+				m.ShowInfoDialog("フィードバックを送る", "ブラウザーを開けませんでした。保存したファイルをメールで送ってください。")
+			}
+		}, m.window)
+	}, m.window)
+	saveDialog.SetFileName(report.FileName())
+	saveDialog.Show()
+}
@@ -0,0 +1,23 @@
+package gui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/figure"
+)
+
+// figureHeight 問題の図を表示する高さ
+const figureHeight = 300
+
+// newFigureView 図表の読み取り問題の図と、その説明
+func newFigureView(f *figure.Figure) fyne.CanvasObject {
+	img := canvas.NewImageFromImage(figure.Render(*f))
+	img.FillMode = canvas.ImageFillContain
+	img.SetMinSize(fyne.NewSize(figureHeight*4/3, figureHeight))
+	caption := widget.NewLabel("図: " + f.Caption)
+	caption.Wrapping = fyne.TextWrapWord
+	return container.NewVBox(img, caption)
+}
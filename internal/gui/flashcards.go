@@ -0,0 +1,327 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/export"
+	"github.com/okamyuji/studybuddy-ai/internal/flashcards"
+)
+
+// 単語カードの出題・作成枚数
+const (
+	flashcardReviewLimit   = 20  // 1回の復習で出題する最大枚数
+	flashcardGenerateCount = 10  // AIで1回に作る枚数
+	ankiMissedProblemLimit = 200 // Ankiに書き出す間違えた問題の最大数（新しい順）
+)
+
+// flashcardGrades 自己採点のボタン
+var flashcardGrades = []struct {
+	label string
+	grade int
+}{
+	{"🔁 もう一度", flashcards.GradeAgain},
+	{"😣 難しい", flashcards.GradeHard},
+	{"🙂 普通", flashcards.GradeGood},
+	{"😄 簡単", flashcards.GradeEasy},
+}
+
+// FlashcardView 単語カード画面
+type FlashcardView struct {
+	container *fyne.Container
+	decks     *fyne.Container // デッキ一覧
+	review    *fyne.Container // 復習中のカード
+
+	generating bool // AIでカードを作成中
+}
+
+// createFlashcardView 単語カード画面を作成
+func (m *MainApp) createFlashcardView() *FlashcardView {
+	view := &FlashcardView{
+		decks:  container.NewVBox(),
+		review: container.NewVBox(),
+	}
+	view.container = container.NewVBox(view.review, view.decks)
+	return view
+}
+
+// refreshFlashcardDecks デッキ一覧を更新
+func (m *MainApp) refreshFlashcardDecks() {
+	view := m.flashcardView
+	view.decks.RemoveAll()
+
+	statuses, err := m.flashcards.Status(m.currentUser.ID)
+	if err != nil {
+		slog.Error("単語カード取得エラー", "error", err)
+		view.decks.Add(widget.NewLabel("単語カードを読み込めませんでした"))
+		return
+	}
+
+	for _, status := range statuses {
+		view.decks.Add(m.createFlashcardDeckCard(status))
+	}
+
+	includeMissed := widget.NewCheck("間違えた問題も含める", nil)
+	includeMissed.SetChecked(true)
+	exportBtn := widget.NewButton("📤 Anki形式（.apkg）で書き出す", func() {
+		m.exportAnki(statuses, includeMissed.Checked)
+	})
+	view.decks.Add(widget.NewCard("📱 スマホで復習", "Ankiアプリに読み込むと、スマホでも単語カードを復習できます",
+		container.NewVBox(includeMissed, exportBtn)))
+}
+
+// exportAnki 単語カード（と間違えた問題）をAnki形式で保存
+func (m *MainApp) exportAnki(statuses []flashcards.DeckStatus, includeMissed bool) {
+	var decks []export.AnkiDeck
+	for _, status := range statuses {
+		cards, err := m.db.GetFlashcards(status.Deck.ID)
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("単語カードを読み込めませんでした: %v", err))
+			return
+		}
+		if len(cards) > 0 {
+			decks = append(decks, export.FlashcardAnkiDeck(status.Deck.Name, cards))
+		}
+	}
+	if includeMissed {
+		results, err := m.db.GetMistakes(m.currentUser.ID, database.MistakeFilter{}, ankiMissedProblemLimit)
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("間違えた問題を読み込めませんでした: %v", err))
+			return
+		}
+		if deck := export.MissedProblemsAnkiDeck(results); len(deck.Cards) > 0 {
+			decks = append(decks, deck)
+		}
+	}
+	if len(decks) == 0 {
+		m.ShowInfoDialog("Anki形式で書き出す", "書き出すカードがありません。")
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("保存先の選択に失敗しました: %v", err))
+			return
+		}
+		if writer == nil {
+			return // キャンセル
+		}
+		defer func() { _ = writer.Close() }()
+
+		if err := export.WriteAnkiPackage(writer, decks); err != nil {
+			slog.Error("Anki出力エラー", "error", err)
+			m.ShowErrorDialog("エラー", fmt.Sprintf("Anki形式のファイルの作成に失敗しました: %v", err))
+			return
+		}
+
+		m.ShowInfoDialog("保存完了", fmt.Sprintf("%s に保存しました。\nAnkiの「読み込む」から開いてください。", writer.URI().Name()))
+	}, m.window)
+	saveDialog.SetFileName("studybuddy.apkg")
+	saveDialog.Show()
+}
+
+// createFlashcardDeckCard デッキのカード数と操作ボタンのカードを作成
+func (m *MainApp) createFlashcardDeckCard(status flashcards.DeckStatus) *widget.Card {
+	deck := status.Deck
+	subtitle := fmt.Sprintf("全%d枚・今日の復習 %d枚", status.Total, status.Due)
+	if status.Total == 0 {
+		subtitle = "まだカードがありません。AIでカードを作ってみましょう"
+	}
+
+	reviewBtn := widget.NewButton("📖 復習する", func() {
+		m.startFlashcardReview(deck)
+	})
+	reviewBtn.Importance = widget.HighImportance
+	if status.Due == 0 {
+		reviewBtn.Disable()
+	}
+
+	generateBtn := widget.NewButton("✨ AIでカードを作る", nil)
+	generateBtn.OnTapped = func() {
+		m.generateFlashcards(deck, generateBtn)
+	}
+	if m.flashcardView.generating {
+		generateBtn.Disable()
+	}
+
+	content := container.NewVBox(container.NewGridWithColumns(2, reviewBtn, generateBtn))
+	if queue := m.createFlashcardQueueButtons(status); queue != nil {
+		content.Add(queue)
+	}
+	return widget.NewCard("🃏 "+deck.Name, subtitle, content)
+}
+
+// createFlashcardQueueButtons 期限切れのカードを分ける・先送りするボタン（復習するカードがなければnil）
+func (m *MainApp) createFlashcardQueueButtons(status flashcards.DeckStatus) fyne.CanvasObject {
+	if status.Due == 0 {
+		return nil
+	}
+	deck := status.Deck
+	snoozeBtn := widget.NewButton("😴 あしたに回す", func() {
+		dialog.ShowConfirm("あしたに回す", fmt.Sprintf("今日の復習%d枚を、あしたに回しますか？", status.Due), func(ok bool) {
+			if !ok {
+				return
+			}
+			moved, err := m.flashcards.SnoozeDeck(deck.ID)
+			if err != nil {
+				m.ShowErrorDialog("単語カード", fmt.Sprintf("復習日を変えられませんでした: %v", err))
+				return
+			}
+			m.refreshFlashcardDecks()
+			m.ShowInfoDialog("単語カード", fmt.Sprintf("%d枚をあしたに回しました。", moved))
+		}, m.window)
+	})
+	if status.Due <= flashcardReviewLimit {
+		return snoozeBtn
+	}
+
+	// 長い休みのあとなどに復習がたまったときは、覚えが浅いカードから1週間に分ける
+	balanceBtn := widget.NewButton(fmt.Sprintf("📅 %d日に分ける", flashcards.BalanceDays), func() {
+		moved, err := m.flashcards.BalanceDeck(deck.ID)
+		if err != nil {
+			m.ShowErrorDialog("単語カード", fmt.Sprintf("復習日を変えられませんでした: %v", err))
+			return
+		}
+		m.refreshFlashcardDecks()
+		m.ShowInfoDialog("単語カード", fmt.Sprintf("たまっていた%d枚のうち%d枚を、これからの%d日に分けました。覚えが浅いカードから先に復習します。",
+			status.Due, moved, flashcards.BalanceDays))
+	})
+	balanceBtn.Importance = widget.HighImportance
+	return container.NewGridWithColumns(2, balanceBtn, snoozeBtn)
+}
+
+// generateFlashcards 学年と苦手な単元に合わせたカードをAIで作成（バックグラウンドで実行）
+func (m *MainApp) generateFlashcards(deck *database.FlashcardDeck, button *widget.Button) {
+	view := m.flashcardView
+	view.generating = true
+	button.SetText("作成中...")
+	button.Disable()
+
+	grade := m.currentUser.Grade
+	weaknesses := m.flashcardWeaknesses(deck.Kind)
+
+	m.goSafe("単語カードの作成", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		added, err := m.flashcards.Generate(ctx, deck, grade, weaknesses, flashcardGenerateCount)
+		fyne.Do(func() {
+			view.generating = false
+			m.refreshFlashcardDecks()
+			if err != nil {
+				m.ShowErrorDialog("単語カード", fmt.Sprintf("カードを作成できませんでした: %v", err))
+				return
+			}
+			if added == 0 {
+				m.ShowInfoDialog("単語カード", "新しいカードは作れませんでした。しばらくしてからもう一度試してください。")
+				return
+			}
+			m.ShowInfoDialog("単語カード", fmt.Sprintf("「%s」に%d枚のカードを追加しました！", deck.Name, added))
+		})
+	}, func() {
+		view.generating = false
+		m.refreshFlashcardDecks()
+	})
+}
+
+// flashcardWeaknesses デッキに関係する科目の苦手な単元
+func (m *MainApp) flashcardWeaknesses(kind string) []string {
+	topics, err := m.progressManager.GetTopicMastery(m.currentUser.ID)
+	if err != nil {
+		slog.Error("単元別習熟度取得エラー", "error", err)
+		return nil
+	}
+
+	var weaknesses []string
+	for _, topic := range topics[flashcards.DeckSubjects[kind]] {
+		if topic.Status == "weak" {
+			weaknesses = append(weaknesses, topic.Topic)
+		}
+	}
+	return weaknesses
+}
+
+// startFlashcardReview 復習時期になったカードの復習を開始
+func (m *MainApp) startFlashcardReview(deck *database.FlashcardDeck) {
+	cards, err := m.flashcards.DueCards(deck.ID, flashcardReviewLimit)
+	if err != nil {
+		m.ShowErrorDialog("単語カード", fmt.Sprintf("カードを読み込めませんでした: %v", err))
+		return
+	}
+	m.flashcardView.decks.Hide()
+	m.showFlashcard(deck, cards, 0)
+}
+
+// showFlashcard カードの表面を表示し、めくったら裏面と自己採点ボタンを表示
+func (m *MainApp) showFlashcard(deck *database.FlashcardDeck, cards []database.Flashcard, index int) {
+	view := m.flashcardView
+	view.review.RemoveAll()
+
+	if index >= len(cards) {
+		m.finishFlashcardReview()
+		if len(cards) > 0 {
+			m.ShowInfoDialog("単語カード", fmt.Sprintf("%d枚の復習が終わりました。よくがんばりました！", len(cards)))
+		}
+		return
+	}
+	card := cards[index]
+
+	front := widget.NewLabelWithStyle(card.Front, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	front.SizeName = theme.SizeNameHeadingText
+	back := widget.NewLabelWithStyle(card.Back, fyne.TextAlignCenter, fyne.TextStyle{})
+	back.Wrapping = fyne.TextWrapWord
+	back.Hide()
+	hint := widget.NewLabel("")
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Hide()
+	if card.Hint != "" {
+		hint.SetText("💡 " + card.Hint)
+	}
+
+	grades := container.NewGridWithColumns(len(flashcardGrades))
+	for _, g := range flashcardGrades {
+		grade := g.grade
+		grades.Add(widget.NewButton(g.label, func() {
+			if err := m.flashcards.Review(&card, grade); err != nil {
+				slog.Error("単語カード記録エラー", "error", err)
+			}
+			m.showFlashcard(deck, cards, index+1)
+		}))
+	}
+	grades.Hide()
+
+	var flipBtn *widget.Button
+	flipBtn = widget.NewButton("🔄 めくる", func() {
+		flipBtn.Hide()
+		back.Show()
+		if card.Hint != "" {
+			hint.Show()
+		}
+		grades.Show()
+	})
+	flipBtn.Importance = widget.HighImportance
+
+	quitBtn := widget.NewButton("復習をやめる", m.finishFlashcardReview)
+
+	title := fmt.Sprintf("🃏 %s（%d / %d）", deck.Name, index+1, len(cards))
+	content := container.NewVBox(front, flipBtn, back, hint, grades)
+	view.review.Add(widget.NewCard(title, "思い出してからカードをめくり、どれくらい覚えていたか選んでください", content))
+	view.review.Add(quitBtn)
+}
+
+// finishFlashcardReview 復習を終えてデッキ一覧に戻る
+func (m *MainApp) finishFlashcardReview() {
+	view := m.flashcardView
+	view.review.RemoveAll()
+	m.refreshFlashcardDecks()
+	view.decks.Show()
+}
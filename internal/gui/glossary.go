@@ -0,0 +1,75 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/glossary"
+)
+
+// showGlossaryTerms 問題文に出てくる用語のボタンを問題の下に並べる
+func (s *StudyView) showGlossaryTerms(problem *ai.Problem, mainApp *MainApp) {
+	s.glossaryTerms.RemoveAll()
+	if mainApp.glossary == nil || s.currentSession == nil {
+		return
+	}
+
+	terms := mainApp.glossary.Find(s.currentSession.Subject, problem.Title+"\n"+problem.Description)
+	if len(terms) == 0 {
+		return
+	}
+
+	s.glossaryTerms.Add(widget.NewLabel("📘 用語:"))
+	for _, term := range terms {
+		btn := widget.NewButton(term.Term, func() {
+			mainApp.showGlossaryTerm(term)
+		})
+		btn.Importance = widget.LowImportance
+		s.glossaryTerms.Add(btn)
+	}
+}
+
+// showGlossaryTerm 用語の意味を表示し、関係する単元を練習できるようにする
+func (m *MainApp) showGlossaryTerm(term glossary.Term) {
+	definition := widget.NewLabel(term.Definition)
+	definition.Wrapping = fyne.TextWrapWord
+	topic := widget.NewLabel(fmt.Sprintf("単元: %s（中%d）", term.Topic, term.Grade))
+
+	content := container.NewVBox(definition, topic)
+	var popup *dialog.CustomDialog
+	if slices.Contains(m.aiEngine.CurriculumTopics(m.currentUser.Grade, term.Subject), term.Topic) {
+		practiceBtn := widget.NewButton(fmt.Sprintf("✏️ 「%s」を練習する", term.Topic), func() {
+			popup.Hide()
+			m.practiceTopic(term.Subject, term.Topic)
+		})
+		practiceBtn.Importance = widget.HighImportance
+		content.Add(practiceBtn)
+	}
+
+	popup = dialog.NewCustom("📘 "+term.Term, "閉じる", content, m.window)
+	popup.Resize(fyne.NewSize(400, 220))
+	popup.Show()
+}
+
+// practiceTopic 単元を指定して学習セッションを開始
+func (m *MainApp) practiceTopic(subject, topic string) {
+	s := m.studyView
+	if s.isGenerating {
+		return
+	}
+	m.content.Select(m.studyTab)
+
+	// 科目・単元の選択の変更イベント（出題の計画を立て直す）を起こさずに選択を合わせる
+	s.subjectSelect.Selected = subject
+	s.subjectSelect.Refresh()
+	s.showTopicOptions(m, subject, topic)
+	s.plan = m.planSession(subject)
+	s.plan.Topics = []string{topic}
+	s.startStudySession(subject, m)
+}
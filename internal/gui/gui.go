@@ -3,9 +3,12 @@ package gui
 import (
 	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"math/rand"
 	"os"
 	"time"
+	"unicode/utf8"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -14,9 +17,23 @@ import (
 	"fyne.io/fyne/v2/widget"
 	"github.com/google/uuid"
 
-	"studybuddy-ai/internal/ai"
-	"studybuddy-ai/internal/config"
-	"studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/achievement"
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/calendar"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/export"
+	"github.com/okamyuji/studybuddy-ai/internal/feature"
+	"github.com/okamyuji/studybuddy-ai/internal/flashcards"
+	"github.com/okamyuji/studybuddy-ai/internal/glossary"
+	"github.com/okamyuji/studybuddy-ai/internal/parent"
+	"github.com/okamyuji/studybuddy-ai/internal/pet"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+	"github.com/okamyuji/studybuddy-ai/internal/schedule"
+	"github.com/okamyuji/studybuddy-ai/internal/server"
+	"github.com/okamyuji/studybuddy-ai/internal/speech"
+	apptheme "github.com/okamyuji/studybuddy-ai/internal/theme"
+	"github.com/okamyuji/studybuddy-ai/internal/xp"
 )
 
 // MainApp メインアプリケーション
@@ -27,19 +44,46 @@ type MainApp struct {
 	aiEngine *ai.Engine
 	config   *config.Config
 
+	progressManager *progress.Manager
+	petManager      *pet.Manager
+	xpService       *xp.Service
+	achievements    *achievement.Manager
+	planner         *schedule.Planner
+	calendar        *calendar.Calendar
+	glossary        *glossary.Glossary // 読み込めなかった場合はnil
+	flashcards      *flashcards.Manager
+	features        *feature.Toggles
+	apiServer       *server.Server
+	parentGate      *parent.Gate
+	parentWindow    fyne.Window     // 開いている保護者ダッシュボード
+	speaker         *speech.Speaker // リスニング問題の英文の読み上げ
+
 	// UI コンポーネント
-	content      *container.AppTabs
-	dashboard    *DashboardView
-	studyView    *StudyView
-	progressView *ProgressView
-	settingsView *SettingsView
+	content       *container.AppTabs
+	dashboard     *DashboardView
+	studyView     *StudyView
+	progressView  *ProgressView
+	scheduleView  *ScheduleView
+	flashcardView *FlashcardView
+	mistakeView   *MistakeView
+	diaryView     *DiaryView
+	askView       *AskView // 制限モードではnil
+	settingsView  *SettingsView
+	classroomView *ClassroomView // 教室モードが無効ならnil
 
 	// タブアイテム参照
 	studyTab    *container.TabItem
 	progressTab *container.TabItem
+	mistakeTab  *container.TabItem
+	diaryTab    *container.TabItem
 
 	// アプリケーション状態
-	currentUser *database.User
+	currentUser      *database.User
+	coachMarkShowing bool           // 使い方のヒントを表示中
+	exam             *examView      // 実施中の模擬テスト
+	captureWindow    fyne.Window    // 表示中のクイック質問ウィンドウ
+	healthBtn        *widget.Button // ツールバーのAIの状態
+	hasTray          bool           // タスクトレイのメニューを登録した
 }
 
 // DashboardView ダッシュボード画面
@@ -47,7 +91,11 @@ type DashboardView struct {
 	container   *fyne.Container
 	welcomeCard *widget.Card
 	statsCard   *widget.Card
+	reviewCard  *widget.Card // 前日までの復習カードがある場合のみ
 	petCard     *widget.Card
+	tipsCard    *widget.Card // ペット有効時のみ（無効時はpetCardに表示）
+	petWidget   *PetWidget   // ペット有効時のみ
+	petMessage  *widget.Label
 	quickAction *fyne.Container
 }
 
@@ -55,19 +103,55 @@ type DashboardView struct {
 type StudyView struct {
 	container        *fyne.Container
 	subjectSelect    *widget.Select
+	topicSelect      *widget.Select // 練習する単元（おまかせなら出題の計画で選ぶ）
+	goalSelect       *widget.Select // 1回の学習で解く目標の問題数
 	problemCard      *widget.Card
 	problemText      *widget.RichText // 問題文表示用（アクセシブル・高コントラスト）
+	glossaryTerms    *fyne.Container  // 問題文に出てくる用語のボタン
 	optionsContainer *fyne.Container
+	scroll           *container.Scroll // 問題・選択肢・フィードバックのスクロール領域
 	feedbackCard     *widget.Card
 	feedbackText     *widget.RichText // フィードバック表示用（アクセシブル・高コントラスト）
+	feedbackTab      string           // 最後に選んだフィードバックのタブ（次の問題でも同じタブを開く）
 
 	// 学習状態
 	currentSession *database.StudySession
 	currentProblem *ai.Problem
+	plan           *progress.SessionPlan // 出題の計画（難易度は解答に合わせて幅の中で上下する）
+	topic          string                // 出題中の単元（空なら学年の学習範囲全体）
 	startTime      time.Time
 	timerLabel     *widget.Label
+	goalLabel      *widget.Label // 目標の問題数までの進み具合
 	progressBar    *widget.ProgressBar
 	isGenerating   bool // 問題生成中フラグ
+
+	cancelGeneration context.CancelFunc // 生成中の問題のリクエストを取り消す
+	generation       int                // 問題を作るたびに増やす（キャンセルした生成の結果を表示しないため）
+
+	// ポモドーロタイマーと集中度
+	focus        *focusTracker
+	pauseBtn     *widget.Button
+	endBtn       *widget.Button // 学習を終えてまとめを見る
+	pausedNotice *widget.Label  // 一時停止中に問題の代わりに表示する
+
+	comboMeter *ComboMeter // 連続正解数と経験値の倍率
+	energyBox  *fyne.Container
+	energyText *widget.Label // 元気（長時間の連続学習で経験値が減る）。元気のしくみを使うときのみ表示
+
+	sessionProblems []*ai.Problem // セッション中に出題した問題（練習プリント用）
+
+	// ペットの反応
+	petWidget          *PetWidget // ペット有効時のみ
+	petAction          *pet.PetAction
+	consecutiveCorrect int
+
+	// 当てずっぽう解答の検出（検出中は経験値を付与しない）
+	guessing         progress.GuessingDetector
+	guessingNotified bool
+
+	// 計算メモ（保存を選んでいれば解答といっしょに保存する）
+	sketchPad    *SketchPad
+	sketchAttach *widget.Check
 }
 
 // ProgressView 進捗画面
@@ -76,14 +160,17 @@ type ProgressView struct {
 	overallProgress *widget.Card
 	subjectProgress *fyne.Container
 	recentSessions  *widget.List
+	weeklyReport    *widget.Card // 毎週月曜日に表示
 }
 
 // SettingsView 設定画面
 type SettingsView struct {
 	container     *fyne.Container
 	aiSettings    *widget.Card
+	cloudSettings *widget.Card
 	uiSettings    *widget.Card
 	learnSettings *widget.Card
+	modelSelect   *widget.Select // 使用するAIモデル
 }
 
 // NewMainApp メインアプリケーションを作成
@@ -92,36 +179,70 @@ func NewMainApp(app fyne.App, db *database.DB, aiEngine *ai.Engine, cfg *config.
 	w.Resize(fyne.NewSize(float32(cfg.UI.WindowWidth), float32(cfg.UI.WindowHeight)))
 	w.CenterOnScreen()
 
+	cal := calendar.New(cfg)
 	mainApp := &MainApp{
 		app:      app,
 		window:   w,
 		db:       db,
 		aiEngine: aiEngine,
 		config:   cfg,
+
+		progressManager: progress.NewManager(db, aiEngine, cal),
+		petManager:      pet.NewManager(db),
+		xpService:       xp.NewService(db),
+		achievements:    achievement.NewManager(db),
+		planner:         schedule.NewPlanner(db, cal),
+		calendar:        cal,
+		flashcards:      flashcards.NewManager(db, aiEngine),
+		features:        feature.New(cfg),
+		apiServer:       server.New(db, aiEngine, cfg, defaultUserID),
+		parentGate:      parent.NewGate(&cfg.Parent),
+		speaker:         speech.New(),
 	}
 
-	// ウィンドウクローズイベントハンドラー設定
-	w.SetCloseIntercept(func() {
-		log.Println("🪟 メインウィンドウ終了要求")
+	// 教室モードで先生が決まったら、機能フラグは先生だけが切り替えられる
+	mainApp.features.SetAuthorizer(func() error {
+		return mainApp.authorizeClassroom(database.CapManageFlags)
+	})
 
-		// リソースクリーンアップ実行
-		if err := mainApp.Close(); err != nil {
-			log.Printf("GUI終了エラー: %v", err)
-		}
+	// 経験値を獲得したときの処理
+	mainApp.xpService.OnAward(mainApp.onXPAward)
 
-		// アプリケーション全体の適切な終了処理
-		mainApp.app.Quit()
+	// AIの状態が変わったらツールバーの表示を更新
+	aiEngine.OnHealthChange(mainApp.onHealthChange)
 
-		// プロセス確実終了（最後の手段）
-		go func() {
-			time.Sleep(3 * time.Second)
-			log.Println("⚠️ 強制終了実行")
-			os.Exit(0)
-		}()
+	// 問題文の用語集
+	if g, err := glossary.Load(); err != nil {
+		slog.Error("用語集読み込みエラー", "error", err)
+	} else {
+		mainApp.glossary = g
+	}
+
+	// ウィンドウクローズイベントハンドラー設定（設定によりタスクトレイに入れる）
+	w.SetCloseIntercept(func() {
+		slog.Info("🪟 メインウィンドウ終了要求")
+		if mainApp.hideToTray() {
+			return
+		}
+		mainApp.quit()
 	})
+	mainApp.setupTray()
+
+	// 制限モードでは、プロフィールコードでサインインしてから画面を作る
+	if cfg.Kiosk {
+		mainApp.showSignIn()
+		return mainApp
+	}
+
+	// 初回起動では、はじめての設定を終えてから画面を作る
+	if cfg.FirstRun {
+		mainApp.showSetupWizard()
+		return mainApp
+	}
 
 	// ユーザー初期化
-	mainApp.initializeUser()
+	mainApp.initializeUser(defaultUserID)
+	mainApp.startAPIServer()
 
 	// UI初期化
 	mainApp.createUI()
@@ -129,9 +250,29 @@ func NewMainApp(app fyne.App, db *database.DB, aiEngine *ai.Engine, cfg *config.
 	return mainApp
 }
 
-// initializeUser ユーザーを初期化
-func (m *MainApp) initializeUser() {
-	userID := "default-user"
+// quit リソースを片づけてアプリケーションを終了
+func (m *MainApp) quit() {
+	// リソースクリーンアップ実行
+	if err := m.Close(); err != nil {
+		slog.Error("GUI終了エラー", "error", err)
+	}
+
+	// アプリケーション全体の適切な終了処理
+	m.app.Quit()
+
+	// プロセス確実終了（最後の手段）
+	go func() {
+		time.Sleep(3 * time.Second)
+		slog.Info("⚠️ 強制終了実行")
+		os.Exit(0)
+	}()
+}
+
+// defaultUserID 制限モード以外で使うプロフィール
+const defaultUserID = "default-user"
+
+// initializeUser ユーザーを初期化（存在しなければ作成）
+func (m *MainApp) initializeUser(userID string) {
 	user, err := m.db.GetUser(userID)
 
 	if err != nil {
@@ -144,17 +285,35 @@ func (m *MainApp) initializeUser() {
 		}
 
 		if err := m.db.CreateUser(user); err != nil {
-			log.Printf("ユーザー作成エラー: %v", err)
+			slog.Error("ユーザー作成エラー", "error", err)
 		}
 
 			// バーチャルペット機能を削除しました
 	}
 
 	m.currentUser = user
+	m.aiEngine.SetProfile(user.ID, user.Name)
+
+	// バーチャルペット（設定で有効な場合のみ）
+	if m.config.Learning.PetEnabled {
+		if _, err := m.petManager.EnsurePet(userID, m.config.Learning.PetSpecies); err != nil {
+			slog.Error("ペット初期化エラー", "error", err)
+		}
+	}
+
+	// 経験値の導入前の学習記録を換算
+	if err := m.xpService.Backfill(userID); err != nil {
+		slog.Error("経験値換算エラー", "error", err)
+	}
+
+	// 起動時点で条件を満たしている実績を記録（通知は実績カードで確認）
+	if level, err := m.xpService.Progress(userID); err == nil {
+		m.checkAchievements(level.Level, false)
+	}
 
 	// 最終ログイン更新
 	if err := m.db.UpdateUserLastLogin(userID); err != nil {
-		log.Printf("ログイン時刻更新エラー: %v", err)
+		slog.Error("ログイン時刻更新エラー", "error", err)
 	}
 }
 
@@ -164,20 +323,61 @@ func (m *MainApp) createUI() {
 	m.dashboard = m.createDashboard()
 	m.studyView = m.createStudyView()
 	m.progressView = m.createProgressView()
+	m.scheduleView = m.createScheduleView()
+	m.flashcardView = m.createFlashcardView()
+	m.mistakeView = m.createMistakeView()
+	m.diaryView = m.createDiaryView()
 	m.settingsView = m.createSettingsView()
+	m.refreshScheduleView()
+	m.refreshFlashcardDecks()
+	m.loadMistakeView()
 
 	// タブ作成
 	m.studyTab = container.NewTabItemWithIcon("学習", theme.DocumentIcon(), m.studyView.container)
-	m.progressTab = container.NewTabItemWithIcon("進捗", theme.InfoIcon(), m.progressView.container)
+	m.progressTab = container.NewTabItemWithIcon("進捗", theme.InfoIcon(), container.NewVScroll(m.progressView.container))
+	m.mistakeTab = container.NewTabItemWithIcon("間違いノート", theme.ErrorIcon(), container.NewVScroll(m.mistakeView.container))
+	m.diaryTab = container.NewTabItemWithIcon("日記", theme.DocumentCreateIcon(), container.NewVScroll(m.diaryView.container))
 
 	m.content = container.NewAppTabs(
-		container.NewTabItemWithIcon("ホーム", theme.HomeIcon(), m.dashboard.container),
+		container.NewTabItemWithIcon("ホーム", theme.HomeIcon(), container.NewVScroll(m.dashboard.container)),
 		m.studyTab,
 		m.progressTab,
-		container.NewTabItemWithIcon("設定", theme.SettingsIcon(), m.settingsView.container),
+		m.mistakeTab,
+		container.NewTabItemWithIcon("計画", theme.CalendarIcon(), container.NewVScroll(m.scheduleView.container)),
+		container.NewTabItemWithIcon("単語カード", theme.GridIcon(), container.NewVScroll(m.flashcardView.container)),
+		m.diaryTab,
 	)
+	// 教室モードでは、制限モードでも生徒が課題を確認できるようにする
+	if m.features.Enabled(feature.Classroom, m.currentUser.ID) {
+		m.classroomView = m.createClassroomView()
+		m.content.Append(container.NewTabItemWithIcon("教室", theme.AccountIcon(), container.NewVScroll(m.classroomView.container)))
+	}
+	// 制限モードでは外部の問題の取り込みと設定の変更をさせない
+	if !m.config.Kiosk {
+		m.askView = m.createAskView()
+		m.content.Append(container.NewTabItemWithIcon("質問する", theme.QuestionIcon(), container.NewVScroll(m.askView.container)))
+		m.content.Append(container.NewTabItemWithIcon("設定", theme.SettingsIcon(), container.NewVScroll(m.settingsView.container)))
+	}
 
-	m.window.SetContent(m.content)
+	// レポートを作れるだけ学習していれば、進捗タブを開いたときにレポートを紹介
+	m.content.OnSelected = func(tab *container.TabItem) {
+		if tab == m.progressTab {
+			m.showReportCoachMark()
+		}
+		if tab == m.mistakeTab {
+			m.refreshMistakes() // 学習中に増えた間違いを反映
+		}
+		if tab == m.diaryTab {
+			m.refreshDiary() // 学習中に増えた記録を反映
+		}
+	}
+
+	m.window.SetContent(container.NewBorder(m.createToolbar(), nil, nil, nil, m.content))
+	if !m.config.Kiosk {
+		m.registerCaptureShortcut()
+	}
+	m.showStartupCoachMarks()
+	m.recoverSessions()
 }
 
 // createDashboard ダッシュボード画面を作成
@@ -188,37 +388,146 @@ func (m *MainApp) createDashboard() *DashboardView {
 	dashboard.welcomeCard = widget.NewCard(
 		fmt.Sprintf("こんにちは、%sさん！", m.currentUser.Name),
 		"今日も一緒に学習しましょう",
-		widget.NewLabel("StudyBuddy AIがあなたの学習をサポートします。\n好きな科目から始めてみませんか？"),
+		container.NewVBox(
+			widget.NewLabel("StudyBuddy AIがあなたの学習をサポートします。\n好きな科目から始めてみませんか？"),
+			widget.NewLabel(m.nextSlotReminder()),
+		),
 	)
 
 	// 統計カード
 	dashboard.statsCard = m.createStatsCard()
 
-	// ペットカード（機能削除）
-	dashboard.petCard = widget.NewCard("学習のこつ", "", 
-		widget.NewLabel("毎日少しずつでも続けることが\n大切です。頑張りましょう！"))
-
-	// クイックアクション
-	dashboard.quickAction = container.NewGridWithColumns(2,
-		widget.NewButton("学習開始", func() {
-			m.content.Select(m.studyTab) // 学習タブに移動
-		}),
-		widget.NewButton("今日の進捗", func() {
-			m.content.Select(m.progressTab) // 進捗タブに移動
-		}),
-	)
+	// ペットカード（ペット無効時は学習のこつを表示）
+	dashboard.petCard = m.createPetCard(dashboard)
+	if dashboard.petWidget != nil {
+		dashboard.tipsCard = m.createTipsCard()
+	}
+
+	// クイックアクション（好きな科目を優先表示）
+	dashboard.quickAction = m.createQuickActions()
+
+	// 昨日の復習（前回のセッションの要点とクイズ）
+	dashboard.reviewCard = m.createReviewCard()
 
 	// レイアウト
-	dashboard.container = container.NewVBox(
-		dashboard.welcomeCard,
+	dashboard.container = container.NewVBox(dashboard.welcomeCard)
+	if dashboard.reviewCard != nil {
+		dashboard.container.Add(dashboard.reviewCard)
+	}
+	dashboard.container.Add(container.NewGridWithColumns(2,
+		dashboard.statsCard,
+		dashboard.petCard,
+	))
+	dashboard.container.Add(dashboard.quickAction)
+	if dashboard.tipsCard != nil {
+		dashboard.container.Add(dashboard.tipsCard)
+	}
+
+	return dashboard
+}
+
+// createQuickActions クイックアクション（好きな科目の順番で、学習ボタンと今日のウォームアップを表示）
+func (m *MainApp) createQuickActions() *fyne.Container {
+	subjects := m.config.OrderedSubjects()
+	warmupSubject := pickWarmupSubject(subjects, time.Now())
+	favoriteButtons := container.NewGridWithColumns(3)
+	for _, subject := range subjects[:3] {
+		favoriteButtons.Add(widget.NewButton(fmt.Sprintf("%sを学習", subject), func() {
+			m.startSubject(subject)
+		}))
+	}
+
+	warmupBtn := widget.NewButton(fmt.Sprintf("🔥 今日のウォームアップ: %s", warmupSubject), func() {
+		m.startSubject(warmupSubject)
+	})
+	warmupBtn.Importance = widget.HighImportance
+
+	manualLogBtn := widget.NewButtonWithIcon("塾・ドリルの学習を記録", theme.DocumentCreateIcon(), func() {
+		m.showManualLogDialog()
+	})
+
+	examBtn := widget.NewButton("📝 模擬テストに挑戦", func() {
+		m.showExamSetup()
+	})
+
+	captureBtn := widget.NewButton("📋 クイック質問（Ctrl+Shift+K）", func() {
+		m.showCaptureWindow()
+	})
+	// 制限モードでは外から問題を取り込ませず、代わりにサインアウトを置く
+	if m.config.Kiosk {
+		captureBtn = widget.NewButton("🚪 サインアウト", func() {
+			m.signOut()
+		})
+	}
+
+	return container.NewVBox(
+		warmupBtn,
+		favoriteButtons,
+		container.NewGridWithColumns(3, manualLogBtn, examBtn, captureBtn),
 		container.NewGridWithColumns(2,
-			dashboard.statsCard,
-			dashboard.petCard,
+			widget.NewButton("学習開始", func() {
+				m.content.Select(m.studyTab) // 学習タブに移動
+			}),
+			widget.NewButton("今日の進捗", func() {
+				m.content.Select(m.progressTab) // 進捗タブに移動
+			}),
 		),
-		dashboard.quickAction,
 	)
+}
 
-	return dashboard
+// nextSlotReminder 時間割に合わせた次の学習予定のお知らせ
+func (m *MainApp) nextSlotReminder() string {
+	slot, err := m.planner.NextSlot(m.currentUser.ID, time.Now(), m.config.Learning.StudyGoalTime, m.config.OrderedSubjects())
+	if err != nil {
+		slog.Error("学習予定取得エラー", "error", err)
+		return ""
+	}
+	if slot == nil {
+		return "⏰ 「計画」タブで時間割を登録すると、学習時間を提案します。"
+	}
+
+	day := "今日"
+	if !sameDay(slot.Start, time.Now()) {
+		day = slot.Start.Format("01/02")
+	}
+	reminder := fmt.Sprintf("⏰ 次の学習予定: %s %s〜%s %s", day, slot.Start.Format("15:04"), slot.End.Format("15:04"), slot.Subject)
+	if exam := m.calendar.Day(slot.Start).Exam; exam != "" {
+		reminder += fmt.Sprintf("（%s期間中）", exam)
+	}
+	return reminder
+}
+
+// sameDay 同じ日付かどうか
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// startSubject 学習タブに移動して科目の学習を開始
+func (m *MainApp) startSubject(subject string) {
+	m.content.Select(m.studyTab)
+	if !m.studyView.isGenerating {
+		m.studyView.subjectSelect.SetSelected(subject)
+	}
+}
+
+// pickWarmupSubject 好きな科目ほど選ばれやすい重み付きで今日のウォームアップ科目を決定（1日の間は固定）
+func pickWarmupSubject(subjects []string, now time.Time) string {
+	totalWeight := 0
+	for i := range subjects {
+		totalWeight += len(subjects) - i
+	}
+
+	rng := rand.New(rand.NewSource(int64(now.Year()*1000 + now.YearDay())))
+	pick := rng.Intn(totalWeight)
+	for i, subject := range subjects {
+		pick -= len(subjects) - i
+		if pick < 0 {
+			return subject
+		}
+	}
+	return subjects[0]
 }
 
 // createStatsCard 統計カードを作成
@@ -226,7 +535,7 @@ func (m *MainApp) createStatsCard() *widget.Card {
 	// 最近のセッション取得
 	sessions, err := m.db.GetRecentStudySessions(m.currentUser.ID, 7)
 	if err != nil {
-		log.Printf("セッション取得エラー: %v", err)
+		slog.Error("セッション取得エラー", "error", err)
 		return widget.NewCard("今週の学習", "", widget.NewLabel("データを読み込み中..."))
 	}
 
@@ -252,11 +561,87 @@ func (m *MainApp) createStatsCard() *widget.Card {
 		"学習セッション: %d回\n解答した問題: %d問\n正解率: %.1f%%",
 		len(sessions), totalProblems, accuracyRate,
 	)
+	if level, err := m.xpService.Progress(m.currentUser.ID); err == nil {
+		statsText += fmt.Sprintf("\nレベル: %d（次のレベルまで %d XP）", level.Level, level.ToNext)
+	}
 
 	return widget.NewCard("今週の学習", "", widget.NewLabel(statsText))
 }
 
-// createPetCard機能を削除しました
+// createPetCard ペットカードを作成（待機アニメーション付き）
+func (m *MainApp) createPetCard(dashboard *DashboardView) *widget.Card {
+	virtualPet, err := m.db.GetVirtualPet(m.currentUser.ID)
+	if !m.config.Learning.PetEnabled || err != nil {
+		return m.createTipsCard()
+	}
+
+	dashboard.petWidget = NewPetWidget(virtualPet.Species, virtualPet.Evolution)
+	dashboard.petWidget.StartIdle()
+
+	message, err := m.petManager.GetDailyMessage(m.currentUser.ID)
+	if err != nil {
+		slog.Error("ペットメッセージ取得エラー", "error", err)
+	}
+	dashboard.petMessage = widget.NewLabel(message)
+	dashboard.petMessage.Wrapping = fyne.TextWrapWord
+
+	playBtn := widget.NewButton("あそぶ", func() {
+		action, err := m.petManager.PlayWithPet(m.currentUser.ID)
+		if err != nil {
+			slog.Error("ペットと遊ぶエラー", "error", err)
+			return
+		}
+		dashboard.petMessage.SetText(action.Message)
+		dashboard.petWidget.Play(action.Type)
+	})
+
+	return widget.NewCard(virtualPet.Name, fmt.Sprintf("レベル %d", virtualPet.Level),
+		container.NewBorder(nil, playBtn, nil, nil,
+			container.NewGridWithColumns(2, dashboard.petWidget, dashboard.petMessage)))
+}
+
+// onXPAward 経験値を獲得したときの処理（実績の判定・レベルアップの通知）
+func (m *MainApp) onXPAward(award *xp.Award) {
+	m.checkAchievements(award.After.Level, true)
+
+	if !award.LeveledUp() {
+		return
+	}
+	slog.Info("🎉 レベルアップ", "from", award.Before.Level, "to", award.After.Level, "total_xp", award.After.Total)
+	m.ShowInfoDialog("🎉 レベルアップ！",
+		fmt.Sprintf("レベル%dになりました！\n次のレベルまであと %d XP です。", award.After.Level, award.After.ToNext))
+}
+
+// feedPet 回答結果をペットに反映し、ダッシュボードのペットも反応させる
+func (m *MainApp) feedPet(result pet.StudyResult) *pet.PetAction {
+	if !m.config.Learning.PetEnabled {
+		return nil
+	}
+
+	action, err := m.petManager.FeedPet(m.currentUser.ID, result)
+	if err != nil {
+		slog.Error("ペット更新エラー", "error", err)
+		return nil
+	}
+
+	// 進化・レベルアップを見た目に反映
+	if virtualPet, err := m.db.GetVirtualPet(m.currentUser.ID); err == nil {
+		if m.studyView.petWidget != nil {
+			m.studyView.petWidget.SetPet(virtualPet.Species, virtualPet.Evolution)
+		}
+		if m.dashboard.petWidget != nil {
+			m.dashboard.petWidget.SetPet(virtualPet.Species, virtualPet.Evolution)
+			m.dashboard.petCard.SetSubTitle(fmt.Sprintf("レベル %d", virtualPet.Level))
+		}
+	}
+
+	if m.dashboard.petWidget != nil {
+		m.dashboard.petMessage.SetText(action.Message)
+		m.dashboard.petWidget.Play(action.Type)
+	}
+
+	return action
+}
 
 // createStudyView 学習画面を作成
 func (m *MainApp) createStudyView() *StudyView {
@@ -264,23 +649,25 @@ func (m *MainApp) createStudyView() *StudyView {
 
 	// 科目選択
 	study.subjectSelect = widget.NewSelect(
-		[]string{"数学", "英語", "国語", "理科", "社会"},
+		m.config.OrderedSubjects(),
 		func(subject string) {
 			// 問題生成中は選択を無視
-			if study.isGenerating {
+			if study.isGenerating || subject == "" {
 				return
 			}
-			study.startStudySession(subject, m)
+			study.showTopicOptions(m, subject, "")
+			m.beginStudySession(subject)
 		},
 	)
 	study.subjectSelect.PlaceHolder = "学習する科目を選択してください"
+	study.topicSelect = m.newStudyTopicSelect(study)
+	study.goalSelect = m.newSessionGoalSelect()
 
 	// 問題表示（アクセシブル・高コントラスト・ユニバーサルデザイン対応）
 	study.problemText = widget.NewRichTextFromMarkdown("**AI接続中です。しばらくお待ちください...**\n\nOllamaモデルの読み込みには最大3分かかる場合があります。")
 	study.problemText.Wrapping = fyne.TextWrapWord
-	// 高コントラスト・読みやすさ重視の設定
-	study.problemText.Resize(fyne.NewSize(500, 250))
 	study.problemCard = widget.NewCard("📖 問題", "", study.problemText)
+	study.glossaryTerms = container.NewHBox()
 
 	// 選択肢コンテナ
 	study.optionsContainer = container.NewVBox()
@@ -288,23 +675,65 @@ func (m *MainApp) createStudyView() *StudyView {
 	// フィードバック（アクセシブル・高コントラスト表示）
 	study.feedbackText = widget.NewRichTextFromMarkdown("解答後にフィードバックが表示されます")
 	study.feedbackText.Wrapping = fyne.TextWrapWord
-	// 横幅制限（画面見切れ対策）
-	study.feedbackText.Resize(fyne.NewSize(350, 180))
 	study.feedbackCard = widget.NewCard("💭 フィードバック", "", study.feedbackText)
 
+	// 回答に反応するペット
+	if virtualPet, err := m.db.GetVirtualPet(m.currentUser.ID); m.config.Learning.PetEnabled && err == nil {
+		study.petWidget = NewPetWidget(virtualPet.Species, virtualPet.Evolution)
+	}
+
 	// ステータス表示（感情分析機能削除）
 	study.timerLabel = widget.NewLabel("00:00")
 	study.progressBar = widget.NewProgressBar()
+	study.goalLabel = widget.NewLabel("")
+	study.pauseBtn = widget.NewButton("⏸ 一時停止", func() {
+		study.togglePause(m)
+	})
+	study.pauseBtn.Disable()
+	study.endBtn = widget.NewButton("⏹ 終わる", func() {
+		study.endStudy(m)
+	})
+	study.endBtn.Disable()
+	study.pausedNotice = widget.NewLabelWithStyle("⏸ 一時停止中です。「▶ 再開」を押すと続きから解けます。",
+		fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	study.pausedNotice.Hide()
+	study.comboMeter = NewComboMeter()
+	study.energyText = widget.NewLabel("")
+	study.energyBox = container.NewHBox(study.energyText, widget.NewButtonWithIcon("", theme.QuestionIcon(), func() {
+		m.ShowInfoDialog("元気のしくみ", xp.EnergyRules)
+	}))
+	study.energyBox.Hide()
+
+	// 練習プリント出力
+	printBtn := widget.NewButton("📄 練習プリント", func() {
+		if len(study.sessionProblems) == 0 {
+			m.ShowInfoDialog("練習プリント", "まだ出題された問題がありません。")
+			return
+		}
+		title := fmt.Sprintf("%sの練習プリント", study.currentSession.Subject)
+		problems := append([]*ai.Problem{}, study.sessionProblems...)
+		m.savePDF("practice.pdf", func(w io.Writer, exporter *export.Exporter) error {
+			return exporter.WriteProblemSetPDF(w, title, problems)
+		})
+	})
 
 	statusContainer := container.NewHBox(
 		study.timerLabel,
 		study.progressBar,
+		study.goalLabel,
+		study.comboMeter,
+		study.energyBox,
+		study.pauseBtn,
+		study.endBtn,
+		printBtn,
 	)
 
 	// 左側: 問題と選択肢
 	leftPanel := container.NewVBox(
 		study.problemCard,
+		container.NewHScroll(study.glossaryTerms),
 		study.optionsContainer,
+		study.newSketchCard(),
 	)
 
 	// 右側: フィードバック
@@ -319,11 +748,21 @@ func (m *MainApp) createStudyView() *StudyView {
 		rightPanel,
 	)
 
-	// 全体レイアウト
-	study.container = container.NewVBox(
-		widget.NewCard("科目選択", "", study.subjectSelect),
-		statusContainer,
-		mainContent,
+	// 全体レイアウト（長い問題文や解説は折り返してスクロールで読む）
+	study.scroll = container.NewVScroll(mainContent)
+	study.container = container.NewBorder(
+		container.NewVBox(
+			widget.NewCard("科目選択", "", container.NewVBox(
+				study.subjectSelect,
+				widget.NewForm(
+					widget.NewFormItem("単元", study.topicSelect),
+					widget.NewFormItem("今日の目標（問）", study.goalSelect),
+				),
+			)),
+			statusContainer,
+		),
+		nil, nil, nil,
+		container.NewStack(study.scroll, container.NewCenter(study.pausedNotice)),
 	)
 
 	return study
@@ -331,6 +770,9 @@ func (m *MainApp) createStudyView() *StudyView {
 
 // startStudySession 学習セッションを開始
 func (s *StudyView) startStudySession(subject string, mainApp *MainApp) {
+	// 前のセッションを終了
+	s.finishSession(mainApp)
+
 	// 新しいセッション作成
 	session := &database.StudySession{
 		ID:        uuid.New().String(),
@@ -341,17 +783,31 @@ func (s *StudyView) startStudySession(subject string, mainApp *MainApp) {
 	}
 
 	if err := mainApp.db.CreateStudySession(session); err != nil {
-		log.Printf("セッション作成エラー: %v", err)
+		slog.Error("セッション作成エラー", "error", err)
 		return
 	}
+	s.runStudySession(session, mainApp)
+}
 
+// runStudySession 学習セッションで問題を出し始める（新しいセッションと、途中で終わったセッションの再開で使う）
+func (s *StudyView) runStudySession(session *database.StudySession, mainApp *MainApp) {
+	subject := session.Subject
 	s.currentSession = session
 	s.startTime = time.Now()
+	s.sessionProblems = nil
+	s.guessing.Reset()
+	s.guessingNotified = false
+	s.consecutiveCorrect = 0
+	s.comboMeter.SetCombo(0)
+	s.updateEnergy(mainApp, time.Now())
+	s.startFocusTracking(mainApp)
+	s.focus.pausedSession = time.Duration(session.PausedSeconds) * time.Second // 再開したセッションはこれまでの一時停止を引き継ぐ
+	mainApp.showCoachMark(coachMarkStudy)
 
 	// 学習進捗取得
 	progress, err := mainApp.db.GetLearningProgress(mainApp.currentUser.ID, subject)
 	if err != nil {
-		log.Printf("進捗取得エラー: %v", err)
+		slog.Error("進捗取得エラー", "error", err)
 		progress = &database.LearningProgress{
 			UserID:  mainApp.currentUser.ID,
 			Subject: subject,
@@ -359,16 +815,12 @@ func (s *StudyView) startStudySession(subject string, mainApp *MainApp) {
 	}
 
 	// AI用の学習コンテキスト構築
-	studyContext := ai.StudyContext{
-		UserID:     mainApp.currentUser.ID,
-		Subject:    subject,
-		Grade:      mainApp.currentUser.Grade,
-		Difficulty: mainApp.config.Learning.DifficultyLevel,
-		Emotion:    "neutral",
-		Progress:   calculateProgress(progress),
-		Strengths:  []string{}, // TODO: 実際の強み分析
-		Weaknesses: []string{}, // TODO: 実際の弱み分析
+	if s.plan == nil || s.plan.Subject != subject {
+		s.plan = mainApp.planSession(subject)
 	}
+	s.updateGoalProgress()
+	studyContext := s.nextStudyContext(mainApp)
+	studyContext.Progress = calculateProgress(progress)
 
 	// 初期状態をAI準備完了状態に更新
 	s.problemCard.SetTitle("📚 準備完了")
@@ -382,9 +834,13 @@ func (s *StudyView) startStudySession(subject string, mainApp *MainApp) {
 
 // generateNewProblem 新しい問題を生成
 func (s *StudyView) generateNewProblem(studyContext ai.StudyContext, mainApp *MainApp) {
+	// 前の問題の読み上げを止める
+	mainApp.speaker.Stop()
+
 	// 生成中フラグを設定（教科選択をブロック）
 	s.isGenerating = true
 	s.subjectSelect.Disable()
+	s.topicSelect.Disable()
 	
 	// UI最初化（選択肢クリア）
 	s.optionsContainer.RemoveAll()
@@ -400,69 +856,184 @@ func (s *StudyView) generateNewProblem(studyContext ai.StudyContext, mainApp *Ma
 	s.problemCard.SetTitle("🔄 問題生成中")
 	s.problemText.ParseMarkdown("**AI が問題を作成しています...**\n\n教科選択は生成完了までお待ちください。")
 
-	go func() {
-		// タイムアウトを8秒に大幅短縮（応答速度大幅改善）
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	s.updateFeedbackPaneSize(mainApp)
+	sessionHashes := make([]string, 0, len(s.sessionProblems))
+	for _, problem := range s.sessionProblems {
+		sessionHashes = append(sessionHashes, ai.SimilarityHash(problem.Description))
+	}
+
+	// タイムアウトを8秒に大幅短縮（応答速度大幅改善）
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	s.generation++
+	generation := s.generation
+	s.cancelGeneration = cancel
+	s.optionsContainer.Add(s.newGenerationCancelButtons(studyContext, mainApp))
+
+	mainApp.goSafe("問題の生成", func() {
 		defer cancel()
 
-		problem, err := mainApp.aiEngine.GeneratePersonalizedProblem(ctx, studyContext)
-		if err != nil {
-			log.Printf("問題生成エラー: %v", err)
-			// エラー時の確実な表示更新（メインスレッドで実行）
+		// 最近1週間とこのセッションで出題した問題と、ほぼ同じ問題を避ける
+		studyContext.RecentHashes = append(mainApp.recentProblemHashes(studyContext.Subject), sessionHashes...)
+
+		// 出題に関係する最近の間違いを、具体例としてAIに伝える
+		retrieveCtx, retrieveCancel := context.WithTimeout(ctx, 5*time.Second)
+		studyContext.RecentMistakes = mainApp.relevantMistakes(retrieveCtx, studyContext.Subject, studyContext.Topic)
+		retrieveCancel()
+
+		// 生成中の問題文と生成の速さを、届いた分から表示する
+		streamCtx := ai.WithStreamCallback(ctx, func(progress ai.StreamProgress) {
+			title, description := ai.ProblemPreview(progress.Text)
 			fyne.Do(func() {
-				// エラー時も教科選択を再有効化
-				s.isGenerating = false
-				s.subjectSelect.Enable()
-				s.problemCard.SetTitle("⚠️ エラー")
-				s.problemText.ParseMarkdown("**問題の生成に失敗しました。もう一度試してください。**")
-				s.problemText.Refresh()
-				s.problemCard.Refresh()
-				s.container.Refresh() // コンテナ全体も更新
+				if s.generation == generation {
+					s.showGenerationProgress(title, description, progress)
+				}
 			})
+		})
+
+		problem, err := mainApp.aiEngine.GeneratePersonalizedProblem(streamCtx, studyContext)
+		if err != nil {
+			slog.Error("問題生成エラー", "error", err)
+			// エラー時の確実な表示更新（メインスレッドで実行）
+			fyne.Do(func() { s.showGenerationFailed(generation) })
 			return
 		}
 
 		// UIを更新（メインスレッドで実行）
 		fyne.Do(func() {
+			if s.generation != generation {
+				return // キャンセル済み
+			}
 			// 生成完了、教科選択を再有効化
+			s.cancelGeneration = nil
 			s.isGenerating = false
 			s.subjectSelect.Enable()
+			s.topicSelect.Enable()
 			s.displayProblem(problem, mainApp)
 		})
-	}()
+	}, func() {
+		s.showGenerationFailed(generation)
+	})
+}
+
+// showGenerationFailed 問題の生成に失敗したことを表示し、教科を選び直せるようにする
+func (s *StudyView) showGenerationFailed(generation int) {
+	if s.generation != generation {
+		return // キャンセル済み
+	}
+	s.cancelGeneration = nil
+	// エラー時も教科選択を再有効化
+	s.isGenerating = false
+	s.subjectSelect.Enable()
+	s.topicSelect.Enable()
+	s.problemCard.SetTitle("⚠️ エラー")
+	s.problemCard.SetSubTitle("")
+	s.problemText.ParseMarkdown("**問題の生成に失敗しました。もう一度試してください。**")
+	s.problemText.Refresh()
+	s.problemCard.Refresh()
+	s.container.Refresh() // コンテナ全体も更新
+}
+
+// newGenerationCancelButtons 生成中の問題をキャンセルするボタン（内蔵問題ですぐに始めることもできる）
+func (s *StudyView) newGenerationCancelButtons(studyContext ai.StudyContext, mainApp *MainApp) fyne.CanvasObject {
+	cancelBtn := widget.NewButtonWithIcon("キャンセル", theme.CancelIcon(), func() {
+		s.cancelProblemGeneration(studyContext, mainApp)
+	})
+	offlineBtn := widget.NewButton("📦 内蔵問題ですぐに始める", func() {
+		// リクエストを取り消すと、AIの代わりに内蔵問題が作られて表示される
+		if s.cancelGeneration != nil {
+			s.cancelGeneration()
+		}
+	})
+	return container.NewHBox(cancelBtn, offlineBtn)
+}
+
+// cancelProblemGeneration 生成中の問題のリクエストを取り消し、科目を選び直せるようにする
+func (s *StudyView) cancelProblemGeneration(studyContext ai.StudyContext, mainApp *MainApp) {
+	if !s.isGenerating {
+		return
+	}
+	s.generation++
+	if s.cancelGeneration != nil {
+		s.cancelGeneration()
+		s.cancelGeneration = nil
+	}
+	s.isGenerating = false
+	s.subjectSelect.Enable()
+	s.topicSelect.Enable()
+
+	s.problemCard.SetTitle("⏹ キャンセルしました")
+	s.problemCard.SetSubTitle("")
+	s.problemText.ParseMarkdown("**問題の作成をやめました。** 科目を選び直すか、もう一度作成してください。")
+	s.feedbackText.ParseMarkdown("")
+
+	retryBtn := widget.NewButton("🔄 もう一度作る", func() {
+		s.generateNewProblem(studyContext, mainApp)
+	})
+	retryBtn.Importance = widget.HighImportance
+	s.optionsContainer.RemoveAll()
+	s.optionsContainer.Add(retryBtn)
+}
+
+// showGenerationProgress 生成中の問題のタイトル・問題文と生成の速さを表示（選択肢と正解は問題を確認してから表示）
+func (s *StudyView) showGenerationProgress(title, description string, progress ai.StreamProgress) {
+	if !s.isGenerating {
+		return
+	}
+	status := fmt.Sprintf("✍️ 作成中… %dトークン（%.1fトークン/秒）", progress.Tokens, progress.TokensPerSecond())
+	if progress.Done {
+		status = "🔍 問題を確認しています..."
+	}
+	s.problemCard.SetSubTitle(status)
+	if title != "" || description != "" {
+		s.problemText.ParseMarkdown(fmt.Sprintf("## %s\n\n**%s**", title, description))
+	}
 }
 
 // displayProblem 問題を表示
 func (s *StudyView) displayProblem(problem *ai.Problem, mainApp *MainApp) {
+	problem.ShuffleOptions()
 	s.currentProblem = problem
+	s.sessionProblems = append(s.sessionProblems, problem)
+	s.recordProblemShown()
 
 	// 問題表示の確実な更新（数学記号対応・高コントラスト）
 	s.problemCard.SetTitle(fmt.Sprintf("📚 %s", problem.Title))
+	if s.topic != "" {
+		s.problemCard.SetSubTitle(fmt.Sprintf("単元: %s", s.topic))
+	} else {
+		s.problemCard.SetSubTitle("")
+	}
 	// 問題文をマークダウンで太字表示（アクセシブル）
 	s.problemText.ParseMarkdown(fmt.Sprintf("## %s\n\n**%s**", problem.Title, problem.Description))
 	// 複数回のRefreshで確実な更新
 	s.problemText.Refresh()
 	s.problemCard.Refresh()
 	s.container.Refresh()
-	// ログで確認
-	descPreview := problem.Description
-	if len(problem.Description) > 50 {
-		descPreview = problem.Description[:50] + "..."
-	}
-	log.Printf("問題表示更新: タイトル=%s, 内容=%s", problem.Title, descPreview)
+	slog.Debug("問題表示更新", "title", problem.Title)
 
 	// 選択肢ボタン（アクセシブル・色弱対応・ユニバーサルデザイン）
+	s.showGlossaryTerms(problem, mainApp)
+	s.sketchPad.Clear()
+
 	s.optionsContainer.RemoveAll()
-	for i, option := range problem.Options {
-		optionIndex := i // クロージャ用にコピー
-		// 色に依存しないボタンデザイン（アクセシブル）
-		btn := widget.NewButton(fmt.Sprintf("%d. %s", i+1, option), func() {
-			s.handleAnswer(optionIndex, mainApp)
-		})
-		// 色強調を使わず、テキストで区別（WCAG準拠）
-		btn.Importance = widget.LowImportance // デフォルトのコントラストで読みやすく
-		s.optionsContainer.Add(btn)
+	if mainApp.config.UI.ReadAloud {
+		text := problemReadAloudText(problem)
+		s.optionsContainer.Add(mainApp.newReadAloudButton("問題を読み上げる", text))
+		if problem.Audio == "" {
+			mainApp.readAloud(text) // リスニング問題は英文の音声を先に流す
+		}
 	}
+	s.optionsContainer.Add(mainApp.newTranslateButton(problem, false))
+	if problem.Audio != "" {
+		s.optionsContainer.Add(mainApp.newListeningControls(problem.Audio, true))
+	}
+	if problem.Figure != nil {
+		s.optionsContainer.Add(newFigureView(problem.Figure))
+	}
+	s.optionsContainer.Add(newOptionButtons(problem.Options, -1, func(index int) {
+		s.handleAnswer(index, mainApp)
+	}))
+	s.scroll.ScrollToTop()
 
 	// フィードバックの確実なクリア
 	s.feedbackCard.SetTitle("💭 フィードバック")
@@ -471,7 +1042,47 @@ func (s *StudyView) displayProblem(problem *ai.Problem, mainApp *MainApp) {
 	s.feedbackCard.Refresh()
 
 	s.optionsContainer.Refresh()
-	log.Printf("問題表示完了: タイトル=%s, 説明文字数=%d", problem.Title, len(problem.Description))
+	slog.Debug("問題表示完了", "title", problem.Title, "description_length", len(problem.Description))
+}
+
+// optionShortLength この文字数以下の選択肢だけなら2列に詰めて並べる
+const optionShortLength = 16
+
+// newOptionButtons 選択肢ボタンを作成（短い選択肢は2列、長い選択肢は折り返して表示）
+func newOptionButtons(options []string, selected int, onSelect func(index int)) fyne.CanvasObject {
+	short := true
+	for _, option := range options {
+		if utf8.RuneCountInString(option) > optionShortLength {
+			short = false
+		}
+	}
+
+	var buttons *fyne.Container
+	if short {
+		buttons = container.NewGridWithColumns(2)
+	} else {
+		buttons = container.NewVBox()
+	}
+	for i, option := range options {
+		text := fmt.Sprintf("%d. %s", i+1, option)
+		btn := widget.NewButton("", func() { onSelect(i) })
+		// 色強調を使わず、テキストで区別（WCAG準拠）
+		btn.Importance = widget.LowImportance
+		if i == selected {
+			btn.Importance = widget.HighImportance
+		}
+
+		if short {
+			btn.SetText(text)
+			buttons.Add(btn)
+			continue
+		}
+		// ボタンは文字を折り返せないため、折り返すラベルを重ねてボタン全体を押せるようにする
+		label := widget.NewLabel(text)
+		label.Wrapping = fyne.TextWrapWord
+		buttons.Add(container.NewStack(btn, label))
+	}
+	return buttons
 }
 
 // handleAnswer 回答処理
@@ -483,6 +1094,8 @@ func (s *StudyView) handleAnswer(selectedIndex int, mainApp *MainApp) {
 	endTime := time.Now()
 	timeTaken := int(endTime.Sub(s.startTime).Seconds())
 	isCorrect := selectedIndex == s.currentProblem.CorrectAnswer
+	answerSeconds := s.recordAnswer()
+	isGuessing := answerSeconds >= 0 && s.guessing.Record(answerSeconds, isCorrect)
 
 	// 問題結果を保存
 	result := &database.ProblemResult{
@@ -493,28 +1106,119 @@ func (s *StudyView) handleAnswer(selectedIndex int, mainApp *MainApp) {
 		IsCorrect:       isCorrect,
 		TimeTaken:       timeTaken,
 		EmotionAtAnswer: "neutral", // 感情分析機能を削除
-		ProblemContent:  s.currentProblem.Description,
 		UserAnswer:      s.currentProblem.Options[selectedIndex],
-		CorrectAnswer:   s.currentProblem.Options[s.currentProblem.CorrectAnswer],
 		CreatedAt:       time.Now(),
+		AnswerPosition:  selectedIndex,
+		OptionCount:     len(s.currentProblem.Options),
 	}
-
-	if err := mainApp.db.CreateProblemResult(result); err != nil {
-		log.Printf("結果保存エラー: %v", err)
+	recordProblemContent(result, s.currentProblem)
+	mainApp.recordProblemQuality(result, s.currentProblem)
+
+	// 解答結果と単元別の統計は1回の書き込みで保存
+	record := database.AnswerRecord{Result: result, Subject: s.currentSession.Subject, AnsweredAt: endTime}
+	if err := mainApp.db.RecordAnswers(mainApp.currentUser.ID, []database.AnswerRecord{record}); err != nil {
+		slog.Error("結果保存エラー", "error", err)
+	} else {
+		s.saveSketch(result.ID, mainApp)
 	}
 
 	// セッション統計更新
 	s.currentSession.TotalProblems++
 	if isCorrect {
 		s.currentSession.CorrectAnswers++
+		s.consecutiveCorrect++
+	} else {
+		s.consecutiveCorrect = 0
+	}
+	s.currentSession.MaxCombo = max(s.currentSession.MaxCombo, s.consecutiveCorrect)
+	s.comboMeter.SetCombo(s.consecutiveCorrect)
+	s.plan.Record(isCorrect)
+
+	// 経験値を付与し、ペットにも同じ学習結果を反映
+	studyResult := pet.StudyResult{
+		IsCorrect:          isCorrect,
+		Difficulty:         s.currentProblem.Difficulty,
+		TimeTaken:          timeTaken,
+		ConsecutiveCorrect: s.consecutiveCorrect,
+		SessionDuration:    int(endTime.Sub(s.currentSession.StartTime).Seconds()),
+		EnergyPercent:      s.updateEnergy(mainApp, endTime),
+	}
+	if isGuessing {
+		// 問題を読まずに答えている間は経験値を止め、ゆっくり読むよう促す
+		s.petAction = nil
+		s.suggestSlowDown(mainApp)
+	} else {
+		s.guessingNotified = false
+		if _, err := mainApp.xpService.GrantAnswer(mainApp.currentUser.ID, studyResult.Answer()); err != nil {
+			slog.Error("経験値付与エラー", "error", err)
+		}
+		s.petAction = mainApp.feedPet(studyResult)
 	}
 
+	if f := s.focus; f != nil {
+		// 途中でアプリが落ちても、一時停止していた時間を学習時間に含めないよう毎回保存する
+		s.currentSession.PausedSeconds = int(f.pausedDuration(endTime).Seconds())
+	}
 	if err := mainApp.db.UpdateStudySession(s.currentSession); err != nil {
-		log.Printf("セッション更新エラー: %v", err)
+		slog.Error("セッション更新エラー", "error", err)
+	}
+	s.checkPlanFinished(mainApp)
+
+	// 解答後は選択肢を1行にたたみ、フィードバックを見やすくする
+	s.optionsContainer.RemoveAll()
+	s.optionsContainer.Add(widget.NewLabel(fmt.Sprintf("あなたの解答: %d. %s", selectedIndex+1, result.UserAnswer)))
+	s.optionsContainer.Add(mainApp.newTranslateButton(s.currentProblem, true)) // 解答後は解説も訳す
+	if s.currentProblem.Audio != "" {
+		s.optionsContainer.Add(mainApp.newListeningScript(s.currentProblem.Audio))
+	}
+	if s.currentProblem.Figure != nil {
+		s.optionsContainer.Add(newFigureView(s.currentProblem.Figure)) // 解説で図を見直せるように残す
 	}
 
-	// フィードバック表示
-	s.showFeedback(result, mainApp)
+	// フィードバック表示（何問かごとに、その前に答えを選んだ理由を聞く）
+	if mainApp.rationaleDue(s.currentSession.TotalProblems, isGuessing) {
+		s.askRationale(result, mainApp)
+	} else {
+		s.showFeedback(result, mainApp)
+	}
+	mainApp.showCoachMark(coachMarkFeedback)
+}
+
+// updateEnergy 元気の表示を更新し、解答の経験値の割合（%）を返す（元気のしくみを使わないときは0）
+func (s *StudyView) updateEnergy(mainApp *MainApp, now time.Time) int {
+	if !mainApp.config.Learning.EnergyEnabled {
+		s.energyBox.Hide()
+		return 0
+	}
+	energy, err := mainApp.xpService.Energy(mainApp.currentUser.ID, now)
+	if err != nil {
+		slog.Error("元気の計算エラー", "error", err)
+		s.energyBox.Hide()
+		return 0
+	}
+	s.energyText.SetText(energy.Summary())
+	s.energyBox.Show()
+	return energy.Percent
+}
+
+// suggestSlowDown 当てずっぽうの連続解答に対して、問題をよく読むよう優しく声をかける（検出ごとに1回）
+func (s *StudyView) suggestSlowDown(mainApp *MainApp) {
+	if s.guessingNotified {
+		return
+	}
+	s.guessingNotified = true
+	slog.Info("🐢 短時間・低正解率の連続解答を検出（経験値を一時停止）")
+
+	subject := s.currentSession.Subject
+	mainApp.goSafe("声かけの作成", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		message := mainApp.aiEngine.GenerateSlowDownMessage(ctx, subject, mainApp.currentUser.Grade)
+		fyne.Do(func() {
+			mainApp.ShowInfoDialog("🐢 ゆっくりいこう", message+"\n\n（じっくり解くと、また経験値がたまるようになります）")
+		})
+	}, nil)
 }
 
 // showFeedback フィードバックを表示
@@ -526,6 +1230,7 @@ func (s *StudyView) showFeedback(result *database.ProblemResult, mainApp *MainAp
 		IsCorrect:  result.IsCorrect,
 		TimeTaken:  result.TimeTaken,
 		Emotion:    result.EmotionAtAnswer,
+		Rationale:  result.Rationale,
 		StudyContext: ai.StudyContext{
 			UserID:  mainApp.currentUser.ID,
 			Subject: s.currentSession.Subject,
@@ -533,43 +1238,56 @@ func (s *StudyView) showFeedback(result *database.ProblemResult, mainApp *MainAp
 		},
 	}
 
-	go func() {
+	problem := *s.currentProblem
+	session := s.currentSession
+	s.updateFeedbackPaneSize(mainApp)
+
+	mainApp.goSafe("フィードバックの作成", func() {
 		// フィードバック生成のタイムアウトを5秒に大幅短縮
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
+		feedbackReq.StudyContext.Strengths = mainApp.strongSubjects(feedbackReq.StudyContext.UserID)
 		feedback, err := mainApp.aiEngine.GenerateFeedback(ctx, feedbackReq)
 		if err != nil {
-			log.Printf("フィードバック生成エラー: %v", err)
+			slog.Error("フィードバック生成エラー", "error", err)
 			fyne.Do(func() {
 				s.showSimpleFeedback(result)
 			})
 			return
 		}
+		mainApp.saveStudyTip(feedbackReq.StudyContext.Subject, feedback.TipOfDay)
 
 		// UIを更新（メインスレッドで実行）
 		fyne.Do(func() {
+			if s.currentSession != session {
+				return // フィードバックを作っている間に学習を終えた
+			}
 			// 次の問題ボタン追加
 			nextBtn := widget.NewButton("次の問題", func() {
-				s.generateNewProblem(ai.StudyContext{
-					UserID:     mainApp.currentUser.ID,
-					Subject:    s.currentSession.Subject,
-					Grade:      mainApp.currentUser.Grade,
-					Difficulty: mainApp.config.Learning.DifficultyLevel,
-					Emotion:    "neutral",
-				}, mainApp)
+				s.generateNewProblem(s.nextStudyContext(mainApp), mainApp)
 			})
 			nextBtn.Importance = widget.HighImportance
 
 			// フィードバック表示（幅制限付き）
 			s.feedbackCard.SetTitle("フィードバック")
-			feedbackContent := container.NewVBox(
-				widget.NewRichTextFromMarkdown(fmt.Sprintf("**結果:** %s\n\n**説明:** %s", feedback.Message, feedback.Explanation)),
-				nextBtn,
-			)
+			feedbackContent := container.NewVBox(s.newFeedbackTabs(&problem, result.CorrectAnswer, feedback))
+			// 数学で間違えたときは、解き方を1ステップずつ確認できるようにする
+			if !result.IsCorrect && (feedbackReq.StudyContext.Subject == "数学" || feedbackReq.StudyContext.Subject == "算数") {
+				feedbackContent.Add(s.newSolutionWalkthrough(problem, result.UserAnswer, mainApp))
+			}
+			if mainApp.config.UI.ReadAloud {
+				text := feedbackReadAloudText(&problem, result.CorrectAnswer, feedback)
+				feedbackContent.Add(mainApp.newReadAloudButton("フィードバックを読み上げる", text))
+				mainApp.readAloud(text)
+			}
+			feedbackContent.Add(s.petReaction())
+			feedbackContent.Add(nextBtn)
 			s.feedbackCard.SetContent(feedbackContent)
 		})
-	}()
+	}, func() {
+		s.showSimpleFeedback(result)
+	})
 }
 
 // showSimpleFeedback シンプルなフィードバックを表示
@@ -582,7 +1300,7 @@ func (s *StudyView) showSimpleFeedback(result *database.ProblemResult) {
 	// 次の問題ボタン追加
 	nextBtn := widget.NewButton("次の問題", func() {
 		// 次の問題を生成（簡易版）
-		log.Println("次の問題を生成中...")
+		slog.Info("次の問題を生成中...")
 	})
 	nextBtn.Importance = widget.HighImportance
 
@@ -590,11 +1308,26 @@ func (s *StudyView) showSimpleFeedback(result *database.ProblemResult) {
 	feedbackContent := container.NewVBox(
 		widget.NewLabel(message),
 		widget.NewLabel(fmt.Sprintf("正解: %s", result.CorrectAnswer)),
+		s.petReaction(),
 		nextBtn,
 	)
 	s.feedbackCard.SetContent(feedbackContent)
 }
 
+// petReaction 回答へのペットの反応（アニメーションとメッセージ）を作成
+func (s *StudyView) petReaction() fyne.CanvasObject {
+	if s.petWidget == nil || s.petAction == nil {
+		return container.NewVBox()
+	}
+
+	message := widget.NewLabel(s.petAction.Message)
+	message.Wrapping = fyne.TextWrapWord
+	s.petWidget.Play(s.petAction.Type)
+
+	return container.NewBorder(nil, nil,
+		container.NewGridWrap(fyne.NewSize(100, 100), s.petWidget), nil, message)
+}
+
 // createProgressView 進捗画面を作成
 func (m *MainApp) createProgressView() *ProgressView {
 	progress := &ProgressView{}
@@ -613,6 +1346,15 @@ func (m *MainApp) createProgressView() *ProgressView {
 	for i, session := range sessions {
 		sessionNames[i] = fmt.Sprintf("%s - %s",
 			session.Subject, session.StartTime.Format("01/02 15:04"))
+		if session.IsManual() {
+			sessionNames[i] += fmt.Sprintf("（📝 %s %d分）", session.Note, session.DurationSeconds()/60)
+		}
+		if session.IsExam() && session.TotalProblems > 0 {
+			sessionNames[i] += fmt.Sprintf("（模擬テスト %d点 %s）", session.CorrectAnswers*100/session.TotalProblems, session.Note)
+		}
+		if session.MaxCombo >= 3 {
+			sessionNames[i] += fmt.Sprintf("　🔥最大%dコンボ", session.MaxCombo)
+		}
 	}
 
 	progress.recentSessions = widget.NewList(
@@ -623,38 +1365,273 @@ func (m *MainApp) createProgressView() *ProgressView {
 		},
 	)
 
+	// 学習レポートのPDF出力
+	reportBtn := widget.NewButton("📄 学習レポートをPDFで保存", func() {
+		analysis, err := m.progressManager.AnalyzeProgress(m.currentUser.ID)
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("学習分析に失敗しました: %v", err))
+			return
+		}
+		m.savePDF("report.pdf", func(w io.Writer, exporter *export.Exporter) error {
+			return exporter.WriteAnalysisPDF(w, m.currentUser.Name, analysis)
+		})
+	})
+
 	progress.container = container.NewVBox(
 		progress.overallProgress,
+		m.createTrendCard(),
+		m.createStudyCalendarCard(),
+		m.createFocusCard(),
+		m.createTopicHeatmapCard(),
+		m.createAchievementsCard(),
 		widget.NewCard("最近の学習セッション", "", progress.recentSessions),
+		container.NewGridWithColumns(2, reportBtn, widget.NewButton("📊 あなたの利用統計", m.showUsageStats)),
 	)
 
+	// 週間レポート（月曜日のみ）
+	if time.Now().Weekday() == time.Monday {
+		progress.weeklyReport = widget.NewCard("📝 先週のレポート", "", widget.NewLabel("レポートを作成中..."))
+		progress.container.Objects = append([]fyne.CanvasObject{progress.weeklyReport}, progress.container.Objects...)
+		m.loadWeeklyReport(progress.weeklyReport)
+	}
+
 	return progress
 }
 
+// createFocusCard 直近のセッションの集中度の推移カードを作成
+func (m *MainApp) createFocusCard() *widget.Card {
+	chart := NewBarChart(100, "点")
+	summary := widget.NewLabel("学習セッションで問題を解くと集中度が記録されます")
+	summary.Wrapping = fyne.TextWrapWord
+
+	analysis, err := m.progressManager.AnalyzeFocus(m.currentUser.ID, 30)
+	if err != nil {
+		slog.Error("集中度分析エラー", "error", err)
+	} else if analysis.SessionCount > 0 {
+		trend := analysis.Trend[max(len(analysis.Trend)-maxFocusChartSessions, 0):]
+		labels := make([]string, len(trend))
+		scores := make([]float64, len(trend))
+		for i, point := range trend {
+			labels[i] = point.Time.Format("1/2")
+			scores[i] = point.Score
+		}
+		chart.SetData(labels, scores)
+
+		text := fmt.Sprintf("直近30日の平均: %.0f点", analysis.AverageScore)
+		for _, period := range progress.TimesOfDay {
+			if score, exists := analysis.ByTimeOfDay[period]; exists {
+				text += fmt.Sprintf("　%s %.0f点", period, score)
+			}
+		}
+		if analysis.LowFocusTimeOfDay != "" {
+			text += fmt.Sprintf("\n%sは集中度が下がりやすいようです。難しい問題は別の時間帯に回してみましょう。", analysis.LowFocusTimeOfDay)
+		}
+		summary.SetText(text)
+	}
+
+	return widget.NewCard("🎯 集中度の推移", "休憩の取り方・一時停止・解答ペースから計算", container.NewVBox(chart, summary))
+}
+
+// loadWeeklyReport 週間レポートを生成してカードに表示
+func (m *MainApp) loadWeeklyReport(card *widget.Card) {
+	m.goSafe("週間レポートの作成", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		report, err := m.progressManager.GenerateWeeklyReport(ctx, m.currentUser.ID, m.currentUser.Grade)
+		if err != nil {
+			slog.Error("週間レポート生成エラー", "error", err)
+			fyne.Do(func() {
+				card.SetContent(widget.NewLabel("レポートを作成できませんでした。"))
+			})
+			return
+		}
+
+		fyne.Do(func() {
+			card.SetSubTitle(fmt.Sprintf("%s 〜 %s",
+				report.WeekStart.Format("01/02"), report.WeekEnd.AddDate(0, 0, -1).Format("01/02")))
+			pdfBtn := widget.NewButton("PDFで保存", func() {
+				m.savePDF("weekly-report.pdf", func(w io.Writer, exporter *export.Exporter) error {
+					return exporter.WriteWeeklyReportPDF(w, m.currentUser.Name, report)
+				})
+			})
+			card.SetContent(container.NewVBox(
+				widget.NewRichTextFromMarkdown(formatWeeklyReport(report)),
+				pdfBtn,
+			))
+		})
+	}, func() {
+		card.SetContent(widget.NewLabel("レポートを作成できませんでした。"))
+	})
+}
+
+// formatWeeklyReport 週間レポートをマークダウンに変換
+func formatWeeklyReport(report *progress.WeeklyReport) string {
+	text := fmt.Sprintf("**学習日数:** %d日　**学習時間:** %d分　**解答数:** %d問　**正解率:** %.1f%%",
+		report.StudyDays, report.TotalStudyTime/60, report.TotalProblems, report.AccuracyRate*100)
+	if report.ManualTime > 0 {
+		text += fmt.Sprintf("\n\n（学習時間のうち塾・ドリルなどアプリ外の学習: %d分）", report.ManualTime/60)
+	}
+	if report.FocusNote != "" {
+		text += fmt.Sprintf("\n\n**集中度:** %s", report.FocusNote)
+	}
+
+	if report.Summary != nil {
+		text += fmt.Sprintf("\n\n**まとめ:** %s\n\n**よくできたこと:** %s\n\n**課題:** %s\n\n**アドバイス:** %s",
+			report.Summary.Summary, report.Summary.Strengths, report.Summary.Weaknesses, report.Summary.Advice)
+	}
+
+	return text
+}
+
 // createSettingsView 設定画面を作成
 func (m *MainApp) createSettingsView() *SettingsView {
 	settings := &SettingsView{}
 
 	// AI設定
-	aiModelSelect := widget.NewSelect(
-		[]string{"dsasai/llama3-elyza-jp-8b", "7shi/ezo-gemma-2-jpn:2b-instruct-q8_0 ", "hf.co/mmnga/cyberagent-DeepSeek-R1-Distill-Qwen-14B-Japanese-gguf"},
-		func(model string) {
-			m.config.AI.Model = model
-			_ = config.Save(m.config)
-		},
-	)
-	aiModelSelect.SetSelected(m.config.AI.Model)
+	var modelOptions []string
+	for _, model := range ai.RecommendedModels {
+		modelOptions = append(modelOptions, model.Name)
+	}
+	settings.modelSelect = widget.NewSelect(modelOptions, func(model string) {
+		if model != m.config.AI.Model {
+			m.setActiveModel(model)
+		}
+	})
+	settings.setModelOption(m.config.AI.Model)
+	m.refreshModelOptions(settings)
+	modelManagerBtn := widget.NewButton("🧩 モデルの管理", m.showModelManager)
+
+	// 説明の詳しさ（解説欄の大きさとあわせて、生成する文章の長さを決める）
+	var verbosityOptions []string
+	for _, verbosity := range config.Verbosities {
+		verbosityOptions = append(verbosityOptions, verbosityLabels[verbosity])
+	}
+	verbositySelect := widget.NewRadioGroup(verbosityOptions, nil)
+	verbositySelect.Horizontal = true
+	verbositySelect.SetSelected(verbosityLabels[m.config.AI.Verbosity])
+	verbositySelect.OnChanged = func(label string) {
+		for verbosity, l := range verbosityLabels {
+			if l == label {
+				m.config.AI.Verbosity = verbosity
+				m.aiEngine.SetVerbosity(verbosity)
+				_ = config.Save(m.config)
+			}
+		}
+	}
+
+	// 解説の表現（AIのフィードバックの言葉づかいを学年や目的に合わせる）
+	var readingLevelOptions []string
+	for _, level := range config.ReadingLevels {
+		readingLevelOptions = append(readingLevelOptions, readingLevelLabels[level])
+	}
+	readingLevelSelect := widget.NewRadioGroup(readingLevelOptions, nil)
+	readingLevelSelect.Horizontal = true
+	readingLevelSelect.SetSelected(readingLevelLabels[m.config.AI.ReadingLevel])
+	readingLevelSelect.OnChanged = func(label string) {
+		for level, l := range readingLevelLabels {
+			if l == label {
+				m.config.AI.ReadingLevel = level
+				m.aiEngine.SetReadingLevel(level)
+				_ = config.Save(m.config)
+			}
+		}
+	}
+
+	// 関連付け説明（抽象的な考え方を得意な科目や身近な例にたとえる）
+	analogyCheck := widget.NewCheck("関連付け説明（得意な科目や身近な例にたとえて説明する）", func(checked bool) {
+		m.config.AI.Analogies = checked
+		m.aiEngine.SetAnalogies(checked)
+		_ = config.Save(m.config)
+	})
+	analogyCheck.SetChecked(m.config.AI.Analogies)
 
 	settings.aiSettings = widget.NewCard("AI設定", "",
 		container.NewVBox(
 			widget.NewLabel("使用するAIモデル:"),
-			aiModelSelect,
+			container.NewBorder(nil, nil, nil, modelManagerBtn, settings.modelSelect),
+			widget.NewLabel("説明の詳しさ:"),
+			verbositySelect,
+			widget.NewLabel("解説の表現:"),
+			readingLevelSelect,
+			analogyCheck,
+			m.createGenerationSettings(),
 		),
 	)
 
-	// UI設定（ダークモード削除）
+	// クラウドAI（保護者向け）
+	settings.cloudSettings = m.createCloudAISettings()
+
+	// UI設定（テーマ切り替え）
+	themeLabels := make([]string, len(apptheme.Variants))
+	for i, variant := range apptheme.Variants {
+		themeLabels[i] = apptheme.VariantLabels[variant]
+	}
+	themeSelect := widget.NewSelect(themeLabels, nil)
+	themeSelect.SetSelected(apptheme.VariantLabels[m.config.ThemeName()])
+	themeSelect.OnChanged = func(string) {
+		m.applyTheme(apptheme.Variants[themeSelect.SelectedIndex()])
+	}
+
+	// 文字の大きさ（離したときに画面全体へ反映）
+	fontSizeLabel := widget.NewLabel(fmt.Sprintf("%dpt", m.config.UI.FontSize))
+	fontSizeSlider := widget.NewSlider(config.MinFontSize, config.MaxFontSize)
+	fontSizeSlider.SetValue(float64(m.config.UI.FontSize))
+	fontSizeSlider.OnChanged = func(value float64) {
+		fontSizeLabel.SetText(fmt.Sprintf("%dpt", int(value)))
+	}
+	fontSizeSlider.OnChangeEnded = func(value float64) {
+		m.applyFontSize(int(value))
+	}
+
+	// 使い方のヒント
+	coachMarksCheck := widget.NewCheck("機能を初めて使うときにヒントを表示", func(checked bool) {
+		m.config.UI.CoachMarks = checked
+		_ = config.Save(m.config)
+	})
+	coachMarksCheck.SetChecked(m.config.UI.CoachMarks)
+	replayCoachMarksBtn := widget.NewButton("ヒントをもう一度見る", func() {
+		m.config.ResetCoachMarks()
+		_ = config.Save(m.config)
+		coachMarksCheck.SetChecked(true)
+		m.ShowInfoDialog("ヒント", "これまでに表示したヒントを、もう一度それぞれの機能を使うときに表示します。")
+	})
+
+	// ウィンドウを閉じたときにタスクトレイに入れる（タスクトレイが使えるときのみ）
+	trayCheck := widget.NewCheck("ウィンドウを閉じてもタスクトレイで動かし続ける", func(checked bool) {
+		m.config.UI.MinimizeToTray = checked
+		m.saveConfig()
+	})
+	trayCheck.SetChecked(m.config.UI.MinimizeToTray)
+	if !m.hasTray {
+		trayCheck.Hide()
+	}
+
+	// 問題文とフィードバックの読み上げ（日本語の音声）
+	readAloudCheck := widget.NewCheck("問題文とフィードバックを読み上げる", func(checked bool) {
+		m.config.UI.ReadAloud = checked
+		m.saveConfig()
+	})
+	readAloudCheck.SetChecked(m.config.UI.ReadAloud)
+	readAloudNote := widget.NewLabel("この端末では日本語の読み上げ機能が見つかりません（Linuxはespeak-ngを入れると使えます）")
+	readAloudNote.Wrapping = fyne.TextWrapWord
+	if m.speaker.Available(speech.Japanese) {
+		readAloudNote.Hide()
+	}
+
 	settings.uiSettings = widget.NewCard("表示設定", "",
-		widget.NewLabel("現在利用可能な表示設定はありません。"))
+		container.NewVBox(
+			widget.NewLabel("テーマ:"),
+			themeSelect,
+			widget.NewLabel("文字の大きさ:"),
+			container.NewBorder(nil, nil, widget.NewLabel("あ"), fontSizeLabel, fontSizeSlider),
+			coachMarksCheck,
+			replayCoachMarksBtn,
+			trayCheck,
+			readAloudCheck,
+			readAloudNote,
+		),
+	)
 
 	// 学習設定
 	difficultySlider := widget.NewSlider(1, 5)
@@ -664,22 +1641,137 @@ func (m *MainApp) createSettingsView() *SettingsView {
 		_ = config.Save(m.config)
 	}
 
+	// 科目別難易度（未設定の科目は基本難易度を使用）
+	subjectDifficulty := container.NewVBox()
+	for _, subject := range config.Subjects {
+		valueLabel := widget.NewLabel(fmt.Sprintf("%d", m.config.DifficultyFor(subject)))
+		slider := widget.NewSlider(1, 5)
+		slider.SetValue(float64(m.config.DifficultyFor(subject)))
+		slider.OnChanged = func(value float64) {
+			m.config.SetSubjectDifficulty(subject, int(value))
+			valueLabel.SetText(fmt.Sprintf("%d", int(value)))
+			_ = config.Save(m.config)
+		}
+		subjectDifficulty.Add(container.NewBorder(nil, nil, widget.NewLabel(subject), valueLabel, slider))
+	}
+
+	// 科目の順番（好きな科目順。Fyneのリストはドラッグで並べ替えられないため、▲▼ボタンで1つずつ動かす）
+	subjectOrder := container.NewVBox()
+	var refreshSubjectOrder func()
+	refreshSubjectOrder = func() {
+		subjectOrder.RemoveAll()
+		ordered := m.config.OrderedSubjects()
+		for i, subject := range ordered {
+			upBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+				m.config.MoveSubjectPref(subject, -1)
+				m.applySubjectOrder()
+				refreshSubjectOrder()
+			})
+			downBtn := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() {
+				m.config.MoveSubjectPref(subject, 1)
+				m.applySubjectOrder()
+				refreshSubjectOrder()
+			})
+			if i == 0 {
+				upBtn.Disable()
+			}
+			if i == len(ordered)-1 {
+				downBtn.Disable()
+			}
+			subjectOrder.Add(container.NewBorder(nil, nil,
+				widget.NewLabel(fmt.Sprintf("%d. %s", i+1, subject)), container.NewHBox(upBtn, downBtn)))
+		}
+		subjectOrder.Refresh()
+	}
+	refreshSubjectOrder()
+
+	// 元気（長時間の詰め込みで経験値が減り、休憩で回復する）
+	energyCheck := widget.NewCheck("元気のしくみを使う（続けて60分をこえると経験値が減り、休憩で回復）", func(checked bool) {
+		m.config.Learning.EnergyEnabled = checked
+		m.saveConfig()
+	})
+	energyCheck.SetChecked(m.config.Learning.EnergyEnabled)
+	energyRulesBtn := widget.NewButton("元気のしくみとは？", func() {
+		m.ShowInfoDialog("元気のしくみ", xp.EnergyRules)
+	})
+
+	// 出題の計画（学習を始める前に単元・難易度の幅・予定の時間を確認する）
+	previewCheck := widget.NewCheck("学習を始める前に出題の計画を確認する", func(checked bool) {
+		m.config.Learning.SessionPreview = checked
+		m.saveConfig()
+	})
+	previewCheck.SetChecked(m.config.Learning.SessionPreview)
+
 	settings.learnSettings = widget.NewCard("学習設定", "",
 		container.NewVBox(
-			widget.NewLabel("難易度レベル:"),
+			widget.NewLabel("基本難易度レベル:"),
 			difficultySlider,
+			widget.NewLabel("科目別難易度:"),
+			subjectDifficulty,
+			widget.NewLabel("科目の順番（好きな順）:"),
+			subjectOrder,
+			container.NewBorder(nil, nil, nil, energyRulesBtn, energyCheck),
+			previewCheck,
+			container.NewBorder(nil, nil, widget.NewLabel("答えを選んだ理由を聞く:"), nil, m.newRationaleSelect()),
 		),
 	)
 
 	settings.container = container.NewVBox(
 		settings.aiSettings,
+		settings.cloudSettings,
 		settings.uiSettings,
 		settings.learnSettings,
+		m.createProfileTransferCard(),
+		m.createContentPackCard(),
+		m.createAnalysisSnapshotCard(),
+		m.createReminderCard(),
+		m.createCompanionCard(),
+		m.createLogSettingsCard(),
+		m.createDiagnosticsCard(),
 	)
 
 	return settings
 }
 
+// applyTheme テーマを切り替えて保存
+func (m *MainApp) applyTheme(variant string) {
+	m.config.SetTheme(variant)
+	m.refreshTheme()
+}
+
+// applyFontSize フォントサイズを変更して保存
+func (m *MainApp) applyFontSize(size int) {
+	if size == m.config.UI.FontSize {
+		return
+	}
+	m.config.SetFontSize(size)
+	m.refreshTheme()
+}
+
+// refreshTheme 現在のテーマ・フォントサイズ設定を画面全体に反映して保存
+func (m *MainApp) refreshTheme() {
+	m.app.Settings().SetTheme(apptheme.NewJapaneseThemeWithSettings(m.config.ThemeName(), m.config.UI.FontSize))
+	if err := config.Save(m.config); err != nil {
+		slog.Error("設定保存エラー", "error", err)
+	}
+}
+
+// applySubjectOrder 科目の並び順を保存して、科目選択・ホームのクイックアクション・学習プランに反映
+func (m *MainApp) applySubjectOrder() {
+	if err := config.Save(m.config); err != nil {
+		slog.Error("設定保存エラー", "error", err)
+	}
+	if m.studyView != nil {
+		m.studyView.subjectSelect.Options = m.config.OrderedSubjects()
+		m.studyView.subjectSelect.Refresh()
+	}
+	if m.dashboard != nil {
+		m.dashboard.quickAction.Objects = m.createQuickActions().Objects
+		m.dashboard.quickAction.Refresh()
+	}
+	m.refreshScheduleView()
+}
+
 // Show アプリケーションを表示
 func (m *MainApp) Show() {
 	m.window.ShowAndRun()
@@ -687,20 +1779,21 @@ func (m *MainApp) Show() {
 
 // Close GUIシステムを適切にクローズ
 func (m *MainApp) Close() error {
-	log.Println("🪟 GUIリソースのクリーンアップ開始")
+	slog.Info("🪟 GUIリソースのクリーンアップ開始")
 
-	// 進行中の学習セッションを終了
-	if m.studyView != nil && m.studyView.currentSession != nil {
-		endTime := time.Now()
-		m.studyView.currentSession.EndTime = &endTime
-		if err := m.db.UpdateStudySession(m.studyView.currentSession); err != nil {
-			log.Printf("セッション終了処理エラー: %v", err)
-		}
+	m.endActivity()
+
+	// 連携アプリのセッションを終了
+	if err := m.apiServer.Stop(); err != nil {
+		slog.Error("APIサーバー停止エラー", "error", err)
 	}
 
 	// 設定保存
-	if err := config.Save(m.config); err != nil {
-		log.Printf("設定保存エラー: %v", err)
+	m.saveConfig()
+
+	// 保護者ダッシュボードも閉じる
+	if m.parentWindow != nil {
+		m.parentWindow.Close()
 	}
 
 	// ウィンドウを隠す
@@ -708,10 +1801,29 @@ func (m *MainApp) Close() error {
 		m.window.Hide()
 	}
 
-	log.Println("✅ GUIリソースのクリーンアップ完了")
+	slog.Info("✅ GUIリソースのクリーンアップ完了")
 	return nil
 }
 
+// endActivity 進行中の学習セッションと模擬テストを終了し、ペットのアニメーションを止める
+func (m *MainApp) endActivity() {
+	// 進行中の学習セッションを終了
+	if m.studyView != nil {
+		m.studyView.finishSession(m)
+	}
+
+	// 実施中の模擬テストはここまでの解答で採点
+	m.finishExam()
+
+	// ペットのアニメーションを停止
+	if m.dashboard != nil && m.dashboard.petWidget != nil {
+		m.dashboard.petWidget.Stop()
+	}
+	if m.studyView != nil && m.studyView.petWidget != nil {
+		m.studyView.petWidget.Stop()
+	}
+}
+
 // calculateProgress 進捗率を計算
 func calculateProgress(progress *database.LearningProgress) float64 {
 	if progress.TotalProblems == 0 {
@@ -742,8 +1854,15 @@ func (m *MainApp) calculateOverallProgress() string {
 	totalAllProblems := 0
 	totalAllCorrect := 0
 	totalSessions := len(sessions)
+	totalSeconds := 0
+	manualSeconds := 0
 
 	for _, session := range sessions {
+		totalSeconds += session.DurationSeconds()
+		if session.IsManual() {
+			manualSeconds += session.DurationSeconds()
+		}
+
 		stats := subjectStats[session.Subject]
 		stats.totalProblems += session.TotalProblems
 		stats.correctAnswers += session.CorrectAnswers
@@ -760,8 +1879,8 @@ func (m *MainApp) calculateOverallProgress() string {
 	}
 
 	return fmt.Sprintf(
-		"学習セッション: %d回\n解答した問題: %d問\n全体正解率: %.1f%%\n学習科目数: %d科目",
-		totalSessions, totalAllProblems, overallAccuracy, len(subjectStats),
+		"学習セッション: %d回\n解答した問題: %d問\n全体正解率: %.1f%%\n学習科目数: %d科目\n学習時間: %d分（うちアプリ外 %d分）",
+		totalSessions, totalAllProblems, overallAccuracy, len(subjectStats), totalSeconds/60, manualSeconds/60,
 	)
 }
 
@@ -817,6 +1936,35 @@ func (m *MainApp) createSubjectProgress() *fyne.Container {
 	return subjectCards
 }
 
+// savePDF 保存先を選択してPDFを書き出す
+func (m *MainApp) savePDF(fileName string, write func(w io.Writer, exporter *export.Exporter) error) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("保存先の選択に失敗しました: %v", err))
+			return
+		}
+		if writer == nil {
+			return // キャンセル
+		}
+		defer func() { _ = writer.Close() }()
+
+		exporter, err := export.NewExporter()
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("PDF出力の準備に失敗しました: %v", err))
+			return
+		}
+		if err := write(writer, exporter); err != nil {
+			slog.Error("PDF出力エラー", "error", err)
+			m.ShowErrorDialog("エラー", fmt.Sprintf("PDFの作成に失敗しました: %v", err))
+			return
+		}
+
+		m.ShowInfoDialog("保存完了", fmt.Sprintf("%s に保存しました。", writer.URI().Name()))
+	}, m.window)
+	saveDialog.SetFileName(fileName)
+	saveDialog.Show()
+}
+
 // ShowErrorDialog エラーダイアログを表示
 func (m *MainApp) ShowErrorDialog(title, message string) {
 	dialog.ShowError(
@@ -0,0 +1,92 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+)
+
+// healthCheckTimeout 「今すぐ確認」の時間制限
+const healthCheckTimeout = 10 * time.Second
+
+// createToolbar 画面上部のツールバー（右端にAIの状態を表示）
+func (m *MainApp) createToolbar() fyne.CanvasObject {
+	m.healthBtn = widget.NewButton("", m.showHealthDetails)
+	m.healthBtn.Importance = widget.LowImportance
+	m.showHealth(m.aiEngine.Health())
+	// 制限モードの共用パソコンには保護者ダッシュボードを置かない
+	if m.config.Kiosk {
+		return container.NewHBox(layout.NewSpacer(), m.healthBtn)
+	}
+	parentBtn := widget.NewButton("👪 保護者", m.openParentDashboard)
+	parentBtn.Importance = widget.LowImportance
+	feedbackBtn := widget.NewButton("✉️ フィードバックを送る", m.showFeedbackForm)
+	feedbackBtn.Importance = widget.LowImportance
+	return container.NewHBox(layout.NewSpacer(), feedbackBtn, parentBtn, m.healthBtn)
+}
+
+// showHealth AIの状態の表示を更新
+func (m *MainApp) showHealth(health ai.Health) {
+	if m.healthBtn == nil {
+		return
+	}
+	m.healthBtn.SetText(fmt.Sprintf("%s %s", health.Status.Icon(), health.Status.Label()))
+}
+
+// onHealthChange AIの状態が変わったときの処理（AIエンジンのゴルーチンから呼ばれる）
+func (m *MainApp) onHealthChange(health ai.Health) {
+	fyne.Do(func() { m.showHealth(health) })
+}
+
+// showHealthDetails AIの状態の詳しい説明を表示（今すぐ確かめ直せる）
+func (m *MainApp) showHealthDetails() {
+	details := widget.NewLabel(healthDetailText(m.aiEngine.Health(), m.aiEngine.GetCurrentModel()))
+	details.Wrapping = fyne.TextWrapWord
+
+	var checkBtn *widget.Button
+	checkBtn = widget.NewButton("🔄 今すぐ確認", func() {
+		checkBtn.Disable()
+		details.SetText("確認しています...")
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+			defer cancel()
+			health := m.aiEngine.CheckHealth(ctx)
+
+			fyne.Do(func() {
+				checkBtn.Enable()
+				details.SetText(healthDetailText(health, m.aiEngine.GetCurrentModel()))
+			})
+		}()
+	})
+
+	content := container.NewVBox(details, container.NewHBox(checkBtn))
+	popup := dialog.NewCustom("AIの状態", "閉じる", content, m.window)
+	popup.Resize(fyne.NewSize(440, 260))
+	popup.Show()
+}
+
+// healthDetailText AIの状態の詳しい説明
+func healthDetailText(health ai.Health, model string) string {
+	text := fmt.Sprintf("%s %s\nモデル: %s", health.Status.Icon(), health.Status.Label(), model)
+	if health.Version != "" {
+		text += fmt.Sprintf("\nOllama: バージョン %s", health.Version)
+	}
+	if health.Detail != "" {
+		text += "\n" + health.Detail
+	}
+	if !health.CheckedAt.IsZero() {
+		text += fmt.Sprintf("\n最終確認: %s", health.CheckedAt.Format("15:04:05"))
+	}
+	if health.Status != ai.HealthOnline {
+		text += fmt.Sprintf("\n\n%d秒ごとに自動で確かめ、つながると元に戻ります。", int(ai.HealthCheckInterval.Seconds()))
+	}
+	return text
+}
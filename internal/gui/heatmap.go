@@ -0,0 +1,101 @@
+package gui
+
+import (
+	"fmt"
+	"image/color"
+	"log/slog"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+)
+
+// heatCellSize ヒートマップのマス1つの大きさ
+var heatCellSize = fyne.NewSize(120, 56)
+
+var (
+	heatLowColor  = color.NRGBA{R: 0xe5, G: 0x73, B: 0x73, A: 0xff} // 習熟度0
+	heatMidColor  = color.NRGBA{R: 0xff, G: 0xd5, B: 0x4f, A: 0xff} // 習熟度0.5
+	heatHighColor = color.NRGBA{R: 0x81, G: 0xc7, B: 0x84, A: 0xff} // 習熟度1
+)
+
+// heatColor 値（0-1）に応じて赤→黄→緑の色を返す
+func heatColor(value float64) color.Color {
+	value = min(max(value, 0), 1)
+	if value < 0.5 {
+		return blendColor(heatLowColor, heatMidColor, value*2)
+	}
+	return blendColor(heatMidColor, heatHighColor, (value-0.5)*2)
+}
+
+// blendColor 2色を割合tで混ぜる
+func blendColor(from, to color.NRGBA, t float64) color.NRGBA {
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*t)
+	}
+	return color.NRGBA{R: mix(from.R, to.R), G: mix(from.G, to.G), B: mix(from.B, to.B), A: 0xff}
+}
+
+// newHeatCell 色付きのマスにラベルを重ねたヒートマップのマスを作成
+func newHeatCell(title, detail string, value float64) fyne.CanvasObject {
+	background := canvas.NewRectangle(heatColor(value))
+	background.CornerRadius = 4
+
+	titleText := canvas.NewText(title, color.Black)
+	titleText.TextStyle = fyne.TextStyle{Bold: true}
+	titleText.Alignment = fyne.TextAlignCenter
+	detailText := canvas.NewText(detail, color.Black)
+	detailText.Alignment = fyne.TextAlignCenter
+
+	return container.NewStack(background, container.NewVBox(layout.NewSpacer(), titleText, detailText, layout.NewSpacer()))
+}
+
+// createTopicHeatmapCard 科目ごとの単元別習熟度のヒートマップカードを作成
+func (m *MainApp) createTopicHeatmapCard() *widget.Card {
+	topics, err := m.progressManager.GetTopicMastery(m.currentUser.ID)
+	if err != nil {
+		slog.Error("単元別習熟度取得エラー", "error", err)
+	}
+
+	rows := container.NewVBox()
+	for _, subject := range m.config.OrderedSubjects() {
+		subjectTopics := topics[subject]
+		if len(subjectTopics) == 0 {
+			continue
+		}
+		// 苦手な単元から順に並べる
+		sort.Slice(subjectTopics, func(i, j int) bool {
+			return subjectTopics[i].Mastery < subjectTopics[j].Mastery
+		})
+
+		cells := container.NewGridWrap(heatCellSize)
+		for _, topic := range subjectTopics {
+			cells.Add(newHeatCell(topic.Topic, topicHeatDetail(topic), topic.Mastery))
+		}
+		rows.Add(widget.NewLabelWithStyle(subject, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		rows.Add(cells)
+	}
+
+	if len(rows.Objects) == 0 {
+		rows.Add(widget.NewLabel("問題を解くと単元ごとの習熟度が表示されます"))
+	}
+
+	return widget.NewCard("🧩 単元別の習熟度", "赤: 苦手　黄: 練習中　緑: 得意（しばらく解いていない単元は薄れていきます）", rows)
+}
+
+// topicHeatDetail ヒートマップのマスに表示する単元の詳細
+func topicHeatDetail(topic progress.TopicMastery) string {
+	label := fmt.Sprintf("%.0f%%", topic.Mastery*100)
+	switch topic.Status {
+	case "strong":
+		label += " 得意"
+	case "weak":
+		label += " 苦手"
+	}
+	return fmt.Sprintf("%s（%d問）", label, topic.Attempts)
+}
@@ -0,0 +1,77 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// profileCodePattern 制限モードでサインインに使うプロフィールコード（英数字とハイフンで4〜32文字）
+var profileCodePattern = regexp.MustCompile(`^[A-Za-z0-9-]{4,32}$`)
+
+// showSignIn 制限モードのサインイン画面を表示（プロフィールコードだけでサインイン）
+func (m *MainApp) showSignIn() {
+	codeEntry := widget.NewEntry()
+	codeEntry.SetPlaceHolder("例: 2A-15")
+	message := widget.NewLabel("")
+
+	signIn := func() {
+		code := strings.TrimSpace(codeEntry.Text)
+		if !profileCodePattern.MatchString(code) {
+			message.SetText("プロフィールコードは英数字とハイフンで4〜32文字です。")
+			return
+		}
+		if _, err := m.db.GetUser(code); err != nil {
+			dialog.ShowConfirm("新しいプロフィール",
+				fmt.Sprintf("プロフィールコード「%s」はまだありません。\n新しく作りますか？", code),
+				func(ok bool) {
+					if ok {
+						m.signIn(code)
+					}
+				}, m.window)
+			return
+		}
+		m.signIn(code)
+	}
+	codeEntry.OnSubmitted = func(string) { signIn() }
+	signInBtn := widget.NewButton("はじめる", signIn)
+	signInBtn.Importance = widget.HighImportance
+
+	m.window.SetContent(container.NewCenter(
+		widget.NewCard("🏫 StudyBuddy AI", "自分のプロフィールコードを入力してください",
+			container.NewVBox(codeEntry, signInBtn, message)),
+	))
+	m.window.Canvas().Focus(codeEntry)
+}
+
+// signIn プロフィールを読み込んで画面を作成
+func (m *MainApp) signIn(userID string) {
+	m.initializeUser(userID)
+	m.createUI()
+}
+
+// signOut 学習を終わらせてサインイン画面に戻る（次の生徒が使えるようにする）
+func (m *MainApp) signOut() {
+	m.endActivity()
+	m.studyView = nil
+	m.dashboard = nil
+	m.currentUser = nil
+	m.showSignIn()
+}
+
+// saveConfig 設定を保存（制限モードでは共用パソコンの設定を変えないため保存しない）
+func (m *MainApp) saveConfig() {
+	if m.config.Kiosk {
+		return
+	}
+	if err := config.Save(m.config); err != nil {
+		slog.Error("設定保存エラー", "error", err)
+	}
+}
@@ -0,0 +1,63 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/speech"
+)
+
+// newListeningControls リスニング問題の英文を聞くボタン（autoplayなら表示してすぐに1回読み上げる）
+// 端末に読み上げ機能がなければ、代わりに英文を表示して読んで答えられるようにする
+func (m *MainApp) newListeningControls(audio string, autoplay bool) fyne.CanvasObject {
+	if !m.speaker.Available(speech.English) {
+		label := widget.NewLabel("🔇 この端末では音声を再生できないため、英文を表示します。\n" + audio)
+		label.Wrapping = fyne.TextWrapWord
+		return label
+	}
+
+	status := widget.NewLabel("")
+	plays := 0
+	play := func(speed speech.Speed) {
+		plays++
+		current := plays
+		status.SetText(fmt.Sprintf("▶ 再生中…（%d回目）", current))
+		m.goSafe("英文の読み上げ", func() {
+			err := m.speaker.Speak(context.Background(), speech.English, audio, speed)
+			fyne.Do(func() {
+				if current != plays {
+					return // 読み上げ中にもう一度押された
+				}
+				if err != nil {
+					slog.Error("英文の読み上げエラー", "error", err)
+					status.SetText("⚠️ 音声を再生できませんでした")
+					return
+				}
+				status.SetText(fmt.Sprintf("%d回聞きました", current))
+			})
+		}, nil)
+	}
+
+	listenBtn := widget.NewButton("🔊 聞く", func() { play(speech.Normal) })
+	listenBtn.Importance = widget.HighImportance
+	slowBtn := widget.NewButton("🐢 ゆっくり聞く", func() { play(speech.Slow) })
+	if autoplay {
+		play(speech.Normal)
+	}
+	return container.NewHBox(listenBtn, slowBtn, status)
+}
+
+// newListeningScript 解答後に表示する、読み上げた英文（聞き直すこともできる）
+func (m *MainApp) newListeningScript(audio string) fyne.CanvasObject {
+	script := widget.NewLabel("読み上げた英文: " + audio)
+	script.Wrapping = fyne.TextWrapWord
+	if !m.speaker.Available(speech.English) {
+		return script
+	}
+	return container.NewVBox(script, m.newListeningControls(audio, false))
+}
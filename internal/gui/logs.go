@@ -0,0 +1,69 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/logging"
+)
+
+// logLevelLabels ログの出力レベルの表示名
+var logLevelLabels = map[string]string{
+	config.LogLevelDebug: "詳細（問題の表示なども記録）",
+	config.LogLevelInfo:  "標準",
+	config.LogLevelWarn:  "警告とエラーのみ",
+	config.LogLevelError: "エラーのみ",
+}
+
+// createLogSettingsCard ログのカードを作成（うまく動かないときに、ログを開いて原因を調べるため）
+func (m *MainApp) createLogSettingsCard() *widget.Card {
+	description := widget.NewLabel("うまく動かないときは、ログを開いて内容を確認するか、問い合わせのときに送ってください。")
+	description.Wrapping = fyne.TextWrapWord
+
+	options := make([]string, len(config.LogLevels))
+	for i, level := range config.LogLevels {
+		options[i] = logLevelLabels[level]
+	}
+	levelSelect := widget.NewSelect(options, func(selected string) {
+		for level, label := range logLevelLabels {
+			if label == selected && level != m.config.Logging.Level {
+				m.config.Logging.Level = level
+				logging.SetLevel(level)
+				m.saveConfig()
+			}
+		}
+	})
+	levelSelect.SetSelected(logLevelLabels[m.config.Logging.Level])
+
+	openBtn := widget.NewButton("📂 ログを開く", m.openLogDir)
+
+	return widget.NewCard("ログ", "", container.NewVBox(
+		description,
+		container.NewBorder(nil, nil, widget.NewLabel("記録する内容:"), openBtn, levelSelect),
+	))
+}
+
+// openLogDir ログのフォルダーをファイルマネージャーで開く
+func (m *MainApp) openLogDir() {
+	dir := config.GetLogDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		m.ShowErrorDialog("エラー", fmt.Sprintf("ログのフォルダーを作成できませんでした: %v", err))
+		return
+	}
+	u, err := url.Parse(storage.NewFileURI(dir).String())
+	if err == nil {
+		err = m.app.OpenURL(u)
+	}
+	if err != nil {
+		slog.Error("ログのフォルダーを開けませんでした", "error", err)
+		m.ShowInfoDialog("ログ", fmt.Sprintf("ログは次のフォルダーにあります:\n%s", dir))
+	}
+}
@@ -0,0 +1,111 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// manualStudyKinds アプリ外の学習の種類
+var manualStudyKinds = []string{"塾", "紙のドリル", "学校の宿題", "その他"}
+
+// manualLogDays 記録できる日付（今日から遡る日数）
+var manualLogDays = []string{"今日", "昨日", "2日前", "3日前"}
+
+// maxManualMinutes 1回の記録で入力できる最大時間（分）
+const maxManualMinutes = 600
+
+// showManualLogDialog アプリ外の学習（塾・紙のドリルなど）を記録するダイアログを表示
+func (m *MainApp) showManualLogDialog() {
+	subjectSelect := widget.NewSelect(m.config.OrderedSubjects(), nil)
+	subjectSelect.SetSelectedIndex(0)
+
+	kindSelect := widget.NewSelect(manualStudyKinds, nil)
+	kindSelect.SetSelectedIndex(0)
+
+	daySelect := widget.NewSelect(manualLogDays, nil)
+	daySelect.SetSelectedIndex(0)
+
+	minutesEntry := widget.NewEntry()
+	minutesEntry.SetPlaceHolder("30")
+	minutesEntry.Validator = func(value string) error {
+		minutes, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || minutes < 1 || minutes > maxManualMinutes {
+			return fmt.Errorf("1〜%d分で入力してください", maxManualMinutes)
+		}
+		return nil
+	}
+
+	memoEntry := widget.NewEntry()
+	memoEntry.SetPlaceHolder("例: 一次関数のプリント")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("科目", subjectSelect),
+		widget.NewFormItem("種類", kindSelect),
+		widget.NewFormItem("日付", daySelect),
+		widget.NewFormItem("学習時間（分）", minutesEntry),
+		widget.NewFormItem("メモ", memoEntry),
+	}
+
+	form := dialog.NewForm("📝 アプリ外の学習を記録", "記録する", "キャンセル", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		minutes, _ := strconv.Atoi(strings.TrimSpace(minutesEntry.Text))
+		note := kindSelect.Selected
+		if memo := strings.TrimSpace(memoEntry.Text); memo != "" {
+			note += "：" + memo
+		}
+
+		if err := m.logManualStudy(subjectSelect.Selected, note, daySelect.SelectedIndex(), minutes); err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("学習記録の保存に失敗しました: %v", err))
+			return
+		}
+		m.ShowInfoDialog("記録しました", fmt.Sprintf("%sの学習（%d分）を記録しました。\nよく頑張りました！", subjectSelect.Selected, minutes))
+	}, m.window)
+	form.Resize(fyne.NewSize(420, 360))
+	form.Show()
+}
+
+// logManualStudy アプリ外の学習を手動記録セッションとして保存
+func (m *MainApp) logManualStudy(subject, note string, daysAgo, minutes int) error {
+	// 過去の日付は夕方の学習として記録（今日の分は現在時刻で終了）
+	end := time.Now()
+	if daysAgo > 0 {
+		day := end.AddDate(0, 0, -daysAgo)
+		end = time.Date(day.Year(), day.Month(), day.Day(), 19, 0, 0, 0, day.Location())
+	}
+	start := end.Add(-time.Duration(minutes) * time.Minute)
+
+	session := &database.StudySession{
+		ID:             uuid.New().String(),
+		UserID:         m.currentUser.ID,
+		Subject:        subject,
+		StartTime:      start,
+		EndTime:        &end,
+		AverageEmotion: "neutral",
+		SessionType:    database.SessionTypeManual,
+		Note:           note,
+		CreatedAt:      time.Now(),
+	}
+	if err := m.db.CreateStudySession(session); err != nil {
+		return err
+	}
+
+	slog.Info("📝 手動学習記録", "subject", subject, "minutes", minutes)
+
+	if _, err := m.xpService.GrantManualStudy(m.currentUser.ID, minutes); err != nil {
+		slog.Error("経験値付与エラー", "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package gui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// mistakeNotebookLimit 間違いノートに表示する最大件数（新しい順）
+const mistakeNotebookLimit = 100
+
+// 間違いノートの絞り込みの「すべて」
+const mistakeFilterAll = "すべて"
+
+// mistakePeriods 間違いノートの期間の絞り込み（日数、0はすべて）
+var mistakePeriods = []struct {
+	label string
+	days  int
+}{
+	{mistakeFilterAll, 0},
+	{"今日", 1},
+	{"1週間", 7},
+	{"1か月", 30},
+}
+
+// MistakeView 間違いノート画面
+type MistakeView struct {
+	container     *fyne.Container
+	subjectSelect *widget.Select
+	periodSelect  *widget.Select
+	typeSelect    *widget.Select
+	groupCheck    *widget.Check
+	list          *fyne.Container
+	generation    int // 一覧を読み込み直した回数（古い読み込みの結果を表示しないため）
+}
+
+// recordProblemContent 間違いノートで出し直せるよう、問題の内容（と作ったモデル）を解答結果に記録
+func recordProblemContent(result *database.ProblemResult, problem *ai.Problem) {
+	result.ProblemTitle = problem.Title
+	result.ProblemContent = problem.Description
+	result.Explanation = problem.Explanation
+	result.CorrectAnswer = problem.Options[problem.CorrectAnswer]
+	result.SimilarityHash = ai.SimilarityHash(problem.Description)
+	result.Model = problem.Model
+	if options, err := json.Marshal(problem.Options); err == nil {
+		result.ProblemOptions = string(options)
+	}
+}
+
+// recordProblemQuality 同じ問題のこれまでの正答率（今回の解答を含む）も使って、問題の品質スコアを解答結果に記録
+func (m *MainApp) recordProblemQuality(result *database.ProblemResult, problem *ai.Problem) {
+	attempts, correct, err := m.db.GetSimilarProblemAccuracy(result.SimilarityHash)
+	if err != nil {
+		slog.Error("正答率取得エラー", "error", err)
+	}
+	attempts++
+	if result.IsCorrect {
+		correct++
+	}
+	result.QualityScore = ai.QualityScore(problem, attempts, correct)
+}
+
+// recentProblemHashes 最近1週間に出題した問題の類似ハッシュ（ほぼ同じ問題を出さないため）
+func (m *MainApp) recentProblemHashes(subject string) []string {
+	hashes, err := m.db.GetRecentSimilarityHashes(m.currentUser.ID, subject, time.Now().Add(-ai.DuplicateWindow))
+	if err != nil {
+		slog.Error("類似ハッシュ取得エラー", "error", err)
+	}
+	return hashes
+}
+
+// problemFromMistake 記録した問題を出し直す（選択肢を記録していない古い結果は、自分の解答と正解の2択にする）
+func problemFromMistake(mistake database.Mistake) *ai.Problem {
+	var options []string
+	if mistake.ProblemOptions != "" {
+		if err := json.Unmarshal([]byte(mistake.ProblemOptions), &options); err != nil {
+			slog.Error("選択肢読み込みエラー", "error", err)
+		}
+	}
+	if !slices.Contains(options, mistake.CorrectAnswer) {
+		options = []string{mistake.CorrectAnswer}
+		if mistake.UserAnswer != "" && mistake.UserAnswer != mistake.CorrectAnswer {
+			options = append(options, mistake.UserAnswer)
+			slices.Sort(options)
+		}
+	}
+
+	title := mistake.ProblemTitle
+	if title == "" {
+		title = mistake.ProblemType
+	}
+	return &ai.Problem{
+		Title:         title,
+		Description:   mistake.ProblemContent,
+		Options:       options,
+		CorrectAnswer: slices.Index(options, mistake.CorrectAnswer),
+		Explanation:   mistake.Explanation,
+		Difficulty:    mistake.Difficulty,
+		ProblemType:   mistake.ProblemType,
+	}
+}
+
+// createMistakeView 間違いノート画面を作成
+func (m *MainApp) createMistakeView() *MistakeView {
+	view := &MistakeView{list: container.NewVBox()}
+
+	view.subjectSelect = widget.NewSelect(append([]string{mistakeFilterAll}, m.config.OrderedSubjects()...), func(string) {
+		m.refreshMistakeTypes()
+		m.refreshMistakes()
+	})
+	var periods []string
+	for _, period := range mistakePeriods {
+		periods = append(periods, period.label)
+	}
+	view.periodSelect = widget.NewSelect(periods, func(string) { m.refreshMistakes() })
+	view.typeSelect = widget.NewSelect([]string{mistakeFilterAll}, func(string) { m.refreshMistakes() })
+
+	filters := container.NewGridWithColumns(3,
+		widget.NewForm(widget.NewFormItem("科目", view.subjectSelect)),
+		widget.NewForm(widget.NewFormItem("期間", view.periodSelect)),
+		widget.NewForm(widget.NewFormItem("単元", view.typeSelect)),
+	)
+	view.groupCheck = widget.NewCheck("🧩 似た問題ごとにまとめる", func(bool) { m.refreshMistakes() })
+	view.container = container.NewVBox(
+		widget.NewCard("📕 間違いノート", "間違えた問題をもう一度解いて、苦手をなくしましょう",
+			container.NewVBox(filters, view.groupCheck)),
+		view.list,
+	)
+	return view
+}
+
+// loadMistakeView 絞り込みを初期状態にして間違いノートを読み込む
+func (m *MainApp) loadMistakeView() {
+	view := m.mistakeView
+	view.subjectSelect.Selected = mistakeFilterAll
+	view.periodSelect.Selected = mistakeFilterAll
+	view.subjectSelect.Refresh()
+	view.periodSelect.Refresh()
+	m.refreshMistakeTypes()
+	m.refreshMistakes()
+}
+
+// mistakeSubjectFilter 選択中の科目（「すべて」なら空）
+func (v *MistakeView) mistakeSubjectFilter() string {
+	if v.subjectSelect.Selected == mistakeFilterAll {
+		return ""
+	}
+	return v.subjectSelect.Selected
+}
+
+// refreshMistakeTypes 科目に合わせて単元の選択肢を更新
+func (m *MainApp) refreshMistakeTypes() {
+	view := m.mistakeView
+	types, err := m.db.GetMistakeProblemTypes(m.currentUser.ID, view.mistakeSubjectFilter())
+	if err != nil {
+		slog.Error("単元一覧取得エラー", "error", err)
+	}
+	view.typeSelect.Options = append([]string{mistakeFilterAll}, types...)
+	if !slices.Contains(view.typeSelect.Options, view.typeSelect.Selected) {
+		view.typeSelect.Selected = mistakeFilterAll
+	}
+	view.typeSelect.Refresh()
+}
+
+// refreshMistakes 絞り込み条件に合わせて間違えた問題の一覧を更新
+func (m *MainApp) refreshMistakes() {
+	view := m.mistakeView
+	view.list.RemoveAll()
+	view.generation++
+
+	filter := database.MistakeFilter{Subject: view.mistakeSubjectFilter()}
+	if view.typeSelect.Selected != mistakeFilterAll {
+		filter.ProblemType = view.typeSelect.Selected
+	}
+	for _, period := range mistakePeriods {
+		if period.label == view.periodSelect.Selected && period.days > 0 {
+			now := time.Now()
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+			filter.Since = today.AddDate(0, 0, 1-period.days)
+		}
+	}
+
+	mistakes, err := m.db.GetMistakes(m.currentUser.ID, filter, mistakeNotebookLimit)
+	if err != nil {
+		slog.Error("間違えた問題取得エラー", "error", err)
+		view.list.Add(widget.NewLabel("間違えた問題を読み込めませんでした"))
+		return
+	}
+	if len(mistakes) == 0 {
+		view.list.Add(widget.NewLabel("条件に合う間違えた問題はありません。"))
+		return
+	}
+	if view.groupCheck.Checked {
+		m.showMistakeGroups(mistakes, view.generation)
+		return
+	}
+
+	for _, mistake := range mistakes {
+		view.list.Add(m.createMistakeCard(mistake))
+	}
+}
+
+// createMistakeCard 間違えた問題1件のカードを作成
+func (m *MainApp) createMistakeCard(mistake database.Mistake) *widget.Card {
+	question := widget.NewLabel(mistake.ProblemContent)
+	question.Wrapping = fyne.TextWrapWord
+	answers := widget.NewRichTextFromMarkdown(fmt.Sprintf("❌ あなたの解答: %s\n\n✅ 正解: %s", mistake.UserAnswer, mistake.CorrectAnswer))
+	answers.Wrapping = fyne.TextWrapWord
+
+	retryBtn := widget.NewButton("🔁 もう一度解く", func() {
+		problem := problemFromMistake(mistake)
+		m.showProblemDialog("🔁 "+problem.Title, problem)
+	})
+	var variantBtn, similarBtn *widget.Button
+	variantBtn = widget.NewButton("🧪 類題に挑戦", func() {
+		m.showMistakeVariant(mistake, variantBtn)
+	})
+	similarBtn = widget.NewButton("🔍 似た間違い", func() {
+		m.showSimilarMistakes(mistake, similarBtn)
+	})
+
+	subtitle := fmt.Sprintf("%s・%s", mistake.Subject, mistake.CreatedAt.Format("2006/01/02 15:04"))
+	if mistake.ProblemType != "" {
+		subtitle = fmt.Sprintf("%s・%s", subtitle, mistake.ProblemType)
+	}
+	title := mistake.ProblemTitle
+	if title == "" {
+		title = "問題"
+	}
+	content := container.NewVBox(question, answers,
+		container.NewGridWithColumns(3, retryBtn, variantBtn, similarBtn))
+	if mistake.HasSketch {
+		content.Add(widget.NewButton("✏️ 解いたときの計算メモ", func() {
+			m.showProblemSketch(mistake.ID)
+		}))
+	}
+	return widget.NewCard(title, subtitle, content)
+}
+
+// showMistakeVariant 間違えた問題と同じ考え方の類題をAIに作ってもらい出題
+func (m *MainApp) showMistakeVariant(mistake database.Mistake, btn *widget.Button) {
+	original := problemFromMistake(mistake)
+	studyContext := ai.StudyContext{
+		Subject:    mistake.Subject,
+		Grade:      m.currentUser.Grade,
+		Difficulty: max(mistake.Difficulty, m.config.DifficultyFor(mistake.Subject)),
+		Topic:      mistake.ProblemType,
+	}
+
+	btn.Disable()
+	btn.SetText("🤖 類題を作成中...")
+	m.goSafe("類題の作成", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		variant, err := m.aiEngine.GenerateVariant(ctx, *original, studyContext)
+		fyne.Do(func() {
+			btn.Enable()
+			btn.SetText("🧪 類題に挑戦")
+			if err != nil {
+				slog.Error("類題生成エラー", "error", err)
+				m.ShowErrorDialog("類題エラー", fmt.Sprintf("類題を作成できませんでした: %v", err))
+				return
+			}
+			m.showProblemDialog("🧪 類題: "+variant.Title, variant)
+		})
+	}, func() {
+		btn.Enable()
+		btn.SetText("🧪 類題に挑戦")
+	})
+}
+
+// showProblemDialog 問題をダイアログで出題し、選んだ答えの正誤と解説を表示
+func (m *MainApp) showProblemDialog(title string, problem *ai.Problem) {
+	problem.ShuffleOptions()
+	question := widget.NewLabel(problem.Description)
+	question.Wrapping = fyne.TextWrapWord
+	result := widget.NewLabel("")
+	result.Wrapping = fyne.TextWrapWord
+	options := container.NewVBox()
+
+	choose := func(index int) {
+		options.RemoveAll()
+		options.Add(widget.NewLabel(fmt.Sprintf("あなたの解答: %s", problem.Options[index])))
+		if index == problem.CorrectAnswer {
+			result.SetText("🎉 正解です！今度はばっちりですね。")
+		} else {
+			result.SetText(fmt.Sprintf("📚 もう少し！正解は「%s」です。", problem.Options[problem.CorrectAnswer]))
+		}
+		if problem.Explanation != "" {
+			result.SetText(result.Text + "\n\n" + problem.Explanation)
+		}
+	}
+	options.Add(newOptionButtons(problem.Options, -1, choose))
+
+	content := container.NewVBox(question, options, result)
+	popup := dialog.NewCustom(title, "閉じる", container.NewVScroll(content), m.window)
+	popup.Resize(fyne.NewSize(520, 420))
+	popup.Show()
+}
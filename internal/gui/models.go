@@ -0,0 +1,383 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// モデルの管理の時間設定
+const (
+	modelListTimeout     = 10 * time.Second
+	modelTestTimeout     = 3 * time.Minute // 初めて使うモデルは読み込みに時間がかかる
+	pullProgressInterval = 200 * time.Millisecond
+	modelMetricsDays     = 30 // 生成の速さを集計する日数
+)
+
+// modelManager モデルの管理ダイアログ
+type modelManager struct {
+	installed   *fyne.Container
+	recommended *fyne.Container
+	metrics     *fyne.Container
+	status      *widget.Label
+	progress    *widget.ProgressBar
+	cancelBtn   *widget.Button
+	cancelPull  context.CancelFunc // ダウンロード中のみ
+	offline     bool               // 一覧を読み込めなかった（状態の欄に接続できないことを表示中）
+}
+
+// setModelOption 使用するAIモデルの選択肢を選ぶ（同じモデルの選択肢は名前を置き換え、なければ加える）
+func (s *SettingsView) setModelOption(model string) {
+	i := slices.IndexFunc(s.modelSelect.Options, func(option string) bool { return ai.SameModel(option, model) })
+	if i < 0 {
+		s.modelSelect.Options = append(s.modelSelect.Options, model)
+	} else {
+		s.modelSelect.Options[i] = model
+	}
+	s.modelSelect.SetSelected(model)
+}
+
+// refreshModelOptions インストール済みのモデルを、使用するAIモデルの選択肢に加える
+func (m *MainApp) refreshModelOptions(settings *SettingsView) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), modelListTimeout)
+		defer cancel()
+		models, err := m.aiEngine.GetAvailableModels(ctx)
+		if err != nil {
+			slog.Error("モデル一覧取得エラー", "error", err)
+			return
+		}
+
+		fyne.Do(func() {
+			for _, model := range models {
+				if !slices.ContainsFunc(settings.modelSelect.Options, func(option string) bool { return ai.SameModel(option, model) }) {
+					settings.modelSelect.Options = append(settings.modelSelect.Options, model)
+				}
+			}
+			settings.modelSelect.Refresh()
+		})
+	}()
+}
+
+// setActiveModel 問題の生成などに使うモデルを切り替えて保存
+func (m *MainApp) setActiveModel(model string) {
+	m.config.UpdateAIModel(model)
+	m.aiEngine.SetModel(model)
+	m.saveConfig()
+	if m.settingsView != nil {
+		m.settingsView.setModelOption(model)
+	}
+}
+
+// showModelManager モデルの管理ダイアログを表示（一覧・ダウンロード・削除・切り替え）
+func (m *MainApp) showModelManager() {
+	mm := &modelManager{
+		installed:   container.NewVBox(),
+		recommended: container.NewVBox(),
+		metrics:     container.NewVBox(),
+		status:      widget.NewLabel(""),
+		progress:    widget.NewProgressBar(),
+	}
+	mm.status.Wrapping = fyne.TextWrapWord
+	mm.progress.Hide()
+	mm.cancelBtn = widget.NewButton("ダウンロードを中止", func() {
+		if mm.cancelPull != nil {
+			mm.cancelPull()
+		}
+	})
+	mm.cancelBtn.Hide()
+
+	customEntry := widget.NewEntry()
+	customEntry.SetPlaceHolder("モデル名（例: gemma2:2b）")
+	customBtn := widget.NewButton("⬇ ダウンロード", func() {
+		if name := strings.TrimSpace(customEntry.Text); name != "" {
+			m.pullModel(mm, name)
+		}
+	})
+	refreshBtn := widget.NewButton("🔄 一覧を更新", func() { m.refreshModelManager(mm) })
+
+	content := container.NewVBox(
+		widget.NewCard("インストール済みのモデル", "「使う」で問題の作成に使うモデルを切り替え、応答するか確かめます", mm.installed),
+		widget.NewCard("おすすめの日本語モデル", "", container.NewVBox(
+			mm.recommended,
+			container.NewBorder(nil, nil, widget.NewLabel("ほかのモデル:"), customBtn, customEntry),
+		)),
+		widget.NewCard("生成の速さ", fmt.Sprintf("直近%d日にこのパソコンで生成したときの平均（遅いときは小さいモデルを試してください）", modelMetricsDays), mm.metrics),
+		mm.status,
+		mm.progress,
+		container.NewHBox(refreshBtn, mm.cancelBtn),
+	)
+
+	popup := dialog.NewCustom("🧩 モデルの管理", "閉じる", container.NewVScroll(content), m.window)
+	popup.SetOnClosed(func() {
+		if mm.cancelPull != nil {
+			mm.cancelPull()
+		}
+	})
+	popup.Resize(fyne.NewSize(640, 560))
+	popup.Show()
+
+	m.refreshModelManager(mm)
+}
+
+// refreshModelManager インストール済みのモデルを読み込み直して一覧を作り直す
+func (m *MainApp) refreshModelManager(mm *modelManager) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), modelListTimeout)
+		defer cancel()
+		models, err := m.aiEngine.ListModels(ctx)
+
+		fyne.Do(func() {
+			if err != nil {
+				slog.Error("モデル一覧取得エラー", "error", err)
+				mm.status.SetText(fmt.Sprintf("Ollamaに接続できません。Ollamaを起動してから「一覧を更新」を押してください。（%v）", err))
+			} else if mm.offline {
+				mm.status.SetText("")
+			}
+			mm.offline = err != nil
+			m.showInstalledModels(mm, models)
+			m.showRecommendedModels(mm, models)
+			m.showModelMetrics(mm)
+		})
+	}()
+}
+
+// showModelMetrics モデルごとの平均の生成時間と、1秒あたりのトークン数の行を作る
+func (m *MainApp) showModelMetrics(mm *modelManager) {
+	mm.metrics.RemoveAll()
+	summaries, err := m.db.GetAIMetricSummaries(time.Now().AddDate(0, 0, -modelMetricsDays))
+	if err != nil {
+		slog.Error("AIの計測読み込みエラー", "error", err)
+	}
+	if len(summaries) == 0 {
+		mm.metrics.Add(widget.NewLabel("まだ記録がありません。問題を作ると、モデルごとの速さが表示されます。"))
+	}
+
+	for _, summary := range summaries {
+		name := summary.Model
+		if ai.SameModel(summary.Model, m.config.AI.Model) {
+			name += "（使用中）"
+		}
+		mm.metrics.Add(container.NewVBox(
+			widget.NewLabelWithStyle(name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			widget.NewLabel(modelMetricsText(summary)),
+		))
+	}
+	mm.metrics.Refresh()
+}
+
+// modelMetricsText 生成の速さの説明（例: 「12回・平均 4.2秒・18.5トークン/秒」）
+func modelMetricsText(summary database.AIMetricSummary) string {
+	text := fmt.Sprintf("%d回・平均 %.1f秒", summary.Calls, summary.AverageLatency().Seconds())
+	if tps := summary.TokensPerSecond(); tps > 0 {
+		text += fmt.Sprintf("・%.1fトークン/秒", tps)
+	}
+	if summary.Calls > 0 {
+		text += fmt.Sprintf("・1回あたり約%dトークン", summary.OutputTokens/summary.Calls)
+	}
+	return text
+}
+
+// showInstalledModels インストール済みのモデルの行を作る
+func (m *MainApp) showInstalledModels(mm *modelManager, models []ai.ModelInfo) {
+	mm.installed.RemoveAll()
+	if len(models) == 0 {
+		mm.installed.Add(widget.NewLabel("インストール済みのモデルはありません。下のおすすめのモデルをダウンロードしてください。"))
+	}
+
+	for _, model := range models {
+		active := ai.SameModel(model.Name, m.config.AI.Model)
+		name := model.Name
+		if active {
+			name = "✅ " + name + "（使用中）"
+		}
+		var details []string
+		for _, detail := range []string{model.ParameterSize, model.Quantization, formatModelSize(model.Size)} {
+			if detail != "" {
+				details = append(details, detail)
+			}
+		}
+		if !model.ModifiedAt.IsZero() {
+			details = append(details, model.ModifiedAt.Format("2006年1月2日"))
+		}
+
+		useBtn := widget.NewButton("使う", func() { m.switchModel(mm, model.Name) })
+		deleteBtn := widget.NewButton("削除", func() { m.deleteModel(mm, model.Name) })
+		if active {
+			useBtn.SetText("接続テスト")
+			deleteBtn.Disable() // 使用中のモデルは削除しない
+		}
+
+		label := widget.NewLabelWithStyle(name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		mm.installed.Add(container.NewBorder(nil, nil, nil, container.NewHBox(useBtn, deleteBtn),
+			container.NewVBox(label, widget.NewLabel(strings.Join(details, "・")))))
+	}
+	mm.installed.Refresh()
+}
+
+// showRecommendedModels まだインストールしていないおすすめのモデルの行を作る
+func (m *MainApp) showRecommendedModels(mm *modelManager, installed []ai.ModelInfo) {
+	mm.recommended.RemoveAll()
+	for _, model := range ai.RecommendedModels {
+		if slices.ContainsFunc(installed, func(info ai.ModelInfo) bool { return ai.SameModel(info.Name, model.Name) }) {
+			continue
+		}
+		description := widget.NewLabel(model.Description)
+		description.Wrapping = fyne.TextWrapWord
+		pullBtn := widget.NewButton("⬇ ダウンロード", func() { m.pullModel(mm, model.Name) })
+		mm.recommended.Add(container.NewBorder(nil, nil, nil, pullBtn, container.NewVBox(
+			widget.NewLabelWithStyle(model.Name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			description,
+		)))
+	}
+	if len(mm.recommended.Objects) == 0 {
+		mm.recommended.Add(widget.NewLabel("おすすめのモデルはすべてインストール済みです。"))
+	}
+	mm.recommended.Refresh()
+}
+
+// pullModel モデルをダウンロードし、進み具合を表示する（終わったら使うか確認する）
+func (m *MainApp) pullModel(mm *modelManager, model string) {
+	if mm.cancelPull != nil {
+		m.ShowInfoDialog("モデルの管理", "ほかのモデルをダウンロードしています。終わってからもう一度お試しください。")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mm.cancelPull = cancel
+	mm.status.SetText(fmt.Sprintf("%s をダウンロードしています...", model))
+	mm.progress.SetValue(0)
+	mm.progress.Show()
+	mm.cancelBtn.Show()
+
+	go func() {
+		var last time.Time
+		err := m.aiEngine.PullModel(ctx, model, func(progress ai.PullProgress) {
+			// 届くたびに画面を更新すると重くなるため、間隔をあける
+			if now := time.Now(); now.Sub(last) >= pullProgressInterval {
+				last = now
+				fyne.Do(func() {
+					mm.progress.SetValue(progress.Fraction())
+					mm.status.SetText(pullStatusText(model, progress))
+				})
+			}
+		})
+		canceled := ctx.Err() != nil
+		cancel()
+
+		fyne.Do(func() {
+			mm.cancelPull = nil
+			mm.progress.Hide()
+			mm.cancelBtn.Hide()
+			switch {
+			case canceled:
+				mm.status.SetText(fmt.Sprintf("%s のダウンロードを中止しました。", model))
+			case err != nil:
+				slog.Error("モデルのダウンロードエラー", "error", err)
+				mm.status.SetText(fmt.Sprintf("%s をダウンロードできませんでした: %v", model, err))
+			default:
+				mm.status.SetText(fmt.Sprintf("✅ %s をダウンロードしました。", model))
+				dialog.ShowConfirm("モデルの管理", fmt.Sprintf("%s をダウンロードしました。\nこのモデルを使いますか？", model), func(ok bool) {
+					if ok {
+						m.switchModel(mm, model)
+					}
+				}, m.window)
+			}
+			m.refreshModelManager(mm)
+			if m.settingsView != nil {
+				m.refreshModelOptions(m.settingsView)
+			}
+		})
+	}()
+}
+
+// pullStatusText ダウンロードの途中経過の表示
+func pullStatusText(model string, progress ai.PullProgress) string {
+	if progress.Total > 0 {
+		return fmt.Sprintf("%s をダウンロードしています... %s / %s（%.0f%%）",
+			model, formatModelSize(progress.Completed), formatModelSize(progress.Total), progress.Fraction()*100)
+	}
+	return fmt.Sprintf("%s: %s", model, progress.Status)
+}
+
+// deleteModel 確認してからモデルを削除する
+func (m *MainApp) deleteModel(mm *modelManager, model string) {
+	message := fmt.Sprintf("%s を削除しますか？\nもう一度使うときは、ダウンロードし直す必要があります。", model)
+	dialog.ShowConfirm("モデルの削除", message, func(ok bool) {
+		if !ok {
+			return
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), modelListTimeout)
+			defer cancel()
+			err := m.aiEngine.DeleteModel(ctx, model)
+
+			fyne.Do(func() {
+				if err != nil {
+					slog.Error("モデルの削除エラー", "error", err)
+					m.ShowErrorDialog("エラー", fmt.Sprintf("モデルを削除できませんでした: %v", err))
+					return
+				}
+				mm.status.SetText(fmt.Sprintf("%s を削除しました。", model))
+				m.refreshModelManager(mm)
+			})
+		}()
+	}, m.window)
+}
+
+// switchModel モデルを切り替えて応答するか確かめる（応答しなければ前のモデルに戻すか確認する）
+func (m *MainApp) switchModel(mm *modelManager, model string) {
+	previous := m.config.AI.Model
+	m.setActiveModel(model)
+	mm.status.SetText(fmt.Sprintf("%s の接続テスト中です（初めて使うモデルは読み込みに数分かかることがあります）...", model))
+	m.refreshModelManager(mm)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), modelTestTimeout)
+		defer cancel()
+		err := m.aiEngine.TestModel(ctx)
+
+		fyne.Do(func() {
+			if err == nil {
+				mm.status.SetText(fmt.Sprintf("✅ %s に切り替えました。問題の作成に使います。", model))
+				return
+			}
+			slog.Error("モデルの接続テストエラー", "error", err)
+			mm.status.SetText(fmt.Sprintf("%s から応答がありません: %v", model, err))
+			if ai.SameModel(previous, model) {
+				return
+			}
+			message := fmt.Sprintf("%s から応答を確認できませんでした。\n%v\n\n前のモデル（%s）に戻しますか？", model, err, previous)
+			dialog.ShowConfirm("接続テスト", message, func(revert bool) {
+				if revert {
+					m.setActiveModel(previous)
+					mm.status.SetText(fmt.Sprintf("%s に戻しました。", previous))
+					m.refreshModelManager(mm)
+				}
+			}, m.window)
+		})
+	}()
+}
+
+// formatModelSize バイト数を読みやすい大きさで表示
+func formatModelSize(size int64) string {
+	switch {
+	case size <= 0:
+		return ""
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1fGB", float64(size)/(1<<30))
+	default:
+		return fmt.Sprintf("%.0fMB", float64(size)/(1<<20))
+	}
+}
@@ -0,0 +1,246 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/parent"
+)
+
+// parentRecommendations 保護者ダッシュボードに出すAIのおすすめの数
+const parentRecommendations = 5
+
+// openParentDashboard 保護者ダッシュボードを開く（PINがなければ先に設定し、あればPINを確かめる）
+func (m *MainApp) openParentDashboard() {
+	if m.parentWindow != nil {
+		m.parentWindow.RequestFocus()
+		return
+	}
+	if !parent.HasPIN(&m.config.Parent) {
+		m.showSetParentPIN(m.window, m.showParentDashboard)
+		return
+	}
+
+	pinEntry := widget.NewPasswordEntry()
+	items := []*widget.FormItem{widget.NewFormItem("PIN", pinEntry)}
+	form := dialog.NewForm("👪 保護者ダッシュボード", "開く", "キャンセル", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		if err := m.parentGate.Unlock(pinEntry.Text, time.Now()); err != nil {
+			var locked *parent.LockedError
+			if !errors.Is(err, parent.ErrWrongPIN) && !errors.As(err, &locked) {
+				slog.Error("PIN確認エラー", "error", err)
+			}
+			m.ShowErrorDialog("保護者ダッシュボード", err.Error())
+			return
+		}
+		m.showParentDashboard()
+	}, m.window)
+	form.Resize(fyne.NewSize(360, 180))
+	form.Show()
+	m.window.Canvas().Focus(pinEntry)
+}
+
+// showSetParentPIN 保護者ダッシュボードのPINを設定（設定できたらdoneを呼ぶ）
+func (m *MainApp) showSetParentPIN(owner fyne.Window, done func()) {
+	pinEntry := widget.NewPasswordEntry()
+	pinEntry.Validator = parent.ValidatePIN
+	confirmEntry := widget.NewPasswordEntry()
+	confirmEntry.Validator = func(value string) error {
+		if value != pinEntry.Text {
+			return fmt.Errorf("PINが一致しません")
+		}
+		return nil
+	}
+	note := widget.NewLabel("保護者ダッシュボードを開くときに使います。お子さんに見られないように入力してください。")
+	note.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("PIN（数字）", pinEntry),
+		widget.NewFormItem("もう一度", confirmEntry),
+		widget.NewFormItem("", note),
+	}
+	form := dialog.NewForm("🔑 保護者のPINを設定", "設定", "キャンセル", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		if err := parent.SetPIN(&m.config.Parent, pinEntry.Text); err != nil {
+			m.ShowErrorDialog("エラー", err.Error())
+			return
+		}
+		m.saveConfig()
+		slog.Info("👪 保護者のPINを設定しました")
+		done()
+	}, owner)
+	form.Resize(fyne.NewSize(420, 280))
+	form.Show()
+}
+
+// showParentDashboard 子どもの画面とは別のウィンドウで保護者ダッシュボードを表示
+func (m *MainApp) showParentDashboard() {
+	w := m.app.NewWindow("👪 保護者ダッシュボード")
+	m.parentWindow = w
+	w.SetOnClosed(func() { m.parentWindow = nil })
+
+	goalBox := container.NewVBox()
+	refreshGoal := func() { m.fillGoalProgress(goalBox) }
+	refreshGoal()
+
+	recommendations := container.NewVBox(widget.NewLabel("分析しています..."))
+	m.loadParentRecommendations(recommendations)
+
+	weeklyReport := widget.NewCard("📝 先週のまとめ", "", widget.NewLabel("レポートを作成中..."))
+	m.loadWeeklyReport(weeklyReport)
+
+	changePINBtn := widget.NewButton("🔑 PINを変更", func() {
+		m.showSetParentPIN(w, func() {
+			dialog.ShowInformation("保護者ダッシュボード", "PINを変更しました。", w)
+		})
+	})
+
+	content := container.NewVBox(
+		widget.NewCard("🎯 今週の目標", "", goalBox),
+		m.createWeeklyGoalCard(w, refreshGoal),
+		m.createTrendCard(),
+		widget.NewCard("💡 AIのおすすめ", "", recommendations),
+		weeklyReport,
+		m.createCurriculumCard(w),
+		m.createBoundaryCard(w),
+		m.createTranscriptCard(w),
+		changePINBtn,
+	)
+	w.SetContent(container.NewVScroll(content))
+	w.Resize(fyne.NewSize(720, 820))
+	w.Show()
+}
+
+// fillGoalProgress 今週の目標の進み具合を表示
+func (m *MainApp) fillGoalProgress(box *fyne.Container) {
+	box.RemoveAll()
+	goal := m.config.Parent.WeeklyGoal
+	progress, err := parent.WeekProgress(m.db, m.currentUser.ID, goal, time.Now())
+	if err != nil {
+		slog.Error("今週の目標の集計エラー", "error", err)
+		box.Add(widget.NewLabel("今週の学習記録を集計できませんでした。"))
+		return
+	}
+
+	box.Add(widget.NewLabel(fmt.Sprintf("%s〜　学習時間 %d分・学習した日 %d日・解いた問題 %d問",
+		progress.WeekStart.Format("01/02"), progress.Minutes, progress.Days, progress.Problems)))
+	items := progress.Items()
+	if len(items) == 0 {
+		box.Add(widget.NewLabel("目標はまだ決めていません。下の「1週間の目標」で決められます。"))
+		return
+	}
+	for _, item := range items {
+		bar := widget.NewProgressBar()
+		bar.SetValue(item.Ratio())
+		label := widget.NewLabel(fmt.Sprintf("%s %d / %d%s", item.Label, item.Done, item.Target, item.Unit))
+		box.Add(container.NewBorder(nil, nil, label, nil, bar))
+	}
+	if progress.Achieved() {
+		box.Add(widget.NewLabel("🎉 今週の目標を達成しました。たくさんほめてあげてください。"))
+	}
+}
+
+// createWeeklyGoalCard 1週間の目標を決めるカード（保存すると今週の目標の表示を更新する）
+func (m *MainApp) createWeeklyGoalCard(w fyne.Window, onSaved func()) *widget.Card {
+	goal := m.config.Parent.WeeklyGoal
+	minutesEntry := newGoalEntry(goal.Minutes, config.MaxWeeklyGoalMinutes)
+	daysEntry := newGoalEntry(goal.Days, config.MaxWeeklyGoalDays)
+	problemsEntry := newGoalEntry(goal.Problems, config.MaxWeeklyGoalProblems)
+
+	form := widget.NewForm(
+		widget.NewFormItem("学習時間（分）", minutesEntry),
+		widget.NewFormItem("学習した日（日）", daysEntry),
+		widget.NewFormItem("解いた問題（問）", problemsEntry),
+	)
+	saveBtn := widget.NewButton("目標を保存", func() {
+		for _, entry := range []*widget.Entry{minutesEntry, daysEntry, problemsEntry} {
+			if err := entry.Validate(); err != nil {
+				dialog.ShowInformation("1週間の目標", err.Error(), w)
+				return
+			}
+		}
+		m.config.Parent.WeeklyGoal = config.WeeklyGoal{
+			Minutes:  goalValue(minutesEntry.Text),
+			Days:     goalValue(daysEntry.Text),
+			Problems: goalValue(problemsEntry.Text),
+		}
+		m.saveConfig()
+		onSaved()
+	})
+
+	note := widget.NewLabel("月曜日から日曜日までの目標です。0または空にした項目は目標にしません。学習時間には塾やドリルなどアプリ外の学習の記録も含めます。")
+	note.Wrapping = fyne.TextWrapWord
+	return widget.NewCard("📅 1週間の目標", "", container.NewVBox(note, form, saveBtn))
+}
+
+// newGoalEntry 目標の数を入力する欄（0〜maxValue）
+func newGoalEntry(value, maxValue int) *widget.Entry {
+	entry := widget.NewEntry()
+	if value > 0 {
+		entry.SetText(strconv.Itoa(value))
+	}
+	entry.SetPlaceHolder("目標なし")
+	entry.Validator = func(text string) error {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(text)
+		if err != nil || n < 0 || n > maxValue {
+			return fmt.Errorf("0〜%dの数で入力してください", maxValue)
+		}
+		return nil
+	}
+	return entry
+}
+
+// goalValue 入力した目標の数（空なら0）
+func goalValue(text string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(text))
+	return n
+}
+
+// loadParentRecommendations 学習の分析からAIのおすすめを表示
+func (m *MainApp) loadParentRecommendations(box *fyne.Container) {
+	userID := m.currentUser.ID
+	m.goSafe("保護者向けのおすすめの作成", func() {
+		analysis, err := m.progressManager.AnalyzeProgress(userID)
+		fyne.Do(func() {
+			box.RemoveAll()
+			if err != nil {
+				slog.Error("進捗分析エラー", "error", err)
+				box.Add(widget.NewLabel("学習の分析ができませんでした。"))
+				return
+			}
+			if len(analysis.Recommendations) == 0 {
+				box.Add(widget.NewLabel("今のところ、特に気になるところはありません。"))
+				return
+			}
+			for i, recommendation := range analysis.Recommendations {
+				if i >= parentRecommendations {
+					break
+				}
+				text := widget.NewLabel(fmt.Sprintf("・%s\n%s", recommendation.Title, recommendation.Description))
+				text.Wrapping = fyne.TextWrapWord
+				box.Add(text)
+			}
+		})
+	}, func() {
+		box.RemoveAll()
+		box.Add(widget.NewLabel("学習の分析ができませんでした。"))
+	})
+}
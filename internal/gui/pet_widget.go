@@ -0,0 +1,357 @@
+package gui
+
+import (
+	"image/color"
+	"math"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/widget"
+)
+
+// petPalette ペットの種類ごとの配色
+type petPalette struct {
+	body   color.Color
+	belly  color.Color
+	accent color.Color // 耳・角など
+}
+
+var petPalettes = map[string]petPalette{
+	"cat":     {body: color.NRGBA{R: 0xf2, G: 0xa6, B: 0x5a, A: 0xff}, belly: color.NRGBA{R: 0xfd, G: 0xe8, B: 0xcf, A: 0xff}, accent: color.NRGBA{R: 0xd9, G: 0x7b, B: 0x29, A: 0xff}},
+	"dog":     {body: color.NRGBA{R: 0xb8, G: 0x86, B: 0x5b, A: 0xff}, belly: color.NRGBA{R: 0xf3, G: 0xe0, B: 0xc8, A: 0xff}, accent: color.NRGBA{R: 0x7a, G: 0x52, B: 0x33, A: 0xff}},
+	"dragon":  {body: color.NRGBA{R: 0x5c, G: 0xb8, B: 0x6e, A: 0xff}, belly: color.NRGBA{R: 0xe4, G: 0xf4, B: 0xb8, A: 0xff}, accent: color.NRGBA{R: 0xf0, G: 0xc0, B: 0x40, A: 0xff}},
+	"unicorn": {body: color.NRGBA{R: 0xf6, G: 0xf0, B: 0xfa, A: 0xff}, belly: color.NRGBA{R: 0xfb, G: 0xd3, B: 0xe9, A: 0xff}, accent: color.NRGBA{R: 0xf5, G: 0xc5, B: 0x42, A: 0xff}},
+}
+
+var (
+	petInkColor     = color.NRGBA{R: 0x33, G: 0x2b, B: 0x2b, A: 0xff}
+	petCheekColor   = color.NRGBA{R: 0xff, G: 0x8a, B: 0x9a, A: 0x99}
+	petShadowColor  = color.NRGBA{A: 0x33}
+	petEffectColor  = color.NRGBA{R: 0xf0, G: 0xa0, B: 0x00, A: 0xff}
+	petEvolveColor  = color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	petCapColor     = color.NRGBA{R: 0x2c, G: 0x3e, B: 0x70, A: 0xff}
+	petScarfColor   = color.NRGBA{R: 0xe0, G: 0x4f, B: 0x5f, A: 0xff}
+	petBlinkEvery   = 4 * time.Second
+	petIdleDuration = 1600 * time.Millisecond
+)
+
+// PetWidget ベクター描画のアニメーションするペット
+type PetWidget struct {
+	widget.BaseWidget
+
+	species   string
+	evolution string // "basic" | "intermediate" | "advanced"
+
+	// アニメーション状態（アニメーションのコールバックから更新）
+	offsetX float32
+	offsetY float32
+	scale   float32
+	blink   bool
+	flash   bool   // 進化時の発光
+	effect  string // 頭上に表示する文字（"LEVEL UP!"など）
+
+	idle    *fyne.Animation
+	blinker *fyne.Animation
+	action  *fyne.Animation
+}
+
+// NewPetWidget ペットウィジェットを作成
+func NewPetWidget(species, evolution string) *PetWidget {
+	p := &PetWidget{species: species, evolution: evolution, scale: 1}
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+// SetPet ペットの種類と進化段階を更新
+func (p *PetWidget) SetPet(species, evolution string) {
+	p.species = species
+	p.evolution = evolution
+	p.Refresh()
+}
+
+// StartIdle 待機アニメーション（呼吸・まばたき）を開始
+func (p *PetWidget) StartIdle() {
+	if p.idle != nil {
+		return
+	}
+
+	p.idle = fyne.NewAnimation(petIdleDuration, func(progress float32) {
+		if p.action != nil {
+			return
+		}
+		p.offsetY = -3 * progress
+		p.Refresh()
+	})
+	p.idle.AutoReverse = true
+	p.idle.RepeatCount = fyne.AnimationRepeatForever
+	p.idle.Curve = fyne.AnimationEaseInOut
+	p.idle.Start()
+
+	p.blinker = fyne.NewAnimation(petBlinkEvery, func(progress float32) {
+		closed := progress > 0.96
+		if closed != p.blink {
+			p.blink = closed
+			p.Refresh()
+		}
+	})
+	p.blinker.RepeatCount = fyne.AnimationRepeatForever
+	p.blinker.Curve = fyne.AnimationLinear
+	p.blinker.Start()
+}
+
+// Stop すべてのアニメーションを停止
+func (p *PetWidget) Stop() {
+	for _, anim := range []*fyne.Animation{p.idle, p.blinker, p.action} {
+		if anim != nil {
+			anim.Stop()
+		}
+	}
+	p.idle, p.blinker, p.action = nil, nil, nil
+}
+
+// Play ペットのアクション（pet.PetAction.Type）に応じたアニメーションを再生
+func (p *PetWidget) Play(actionType string) {
+	if p.action != nil {
+		p.action.Stop()
+	}
+
+	duration := 800 * time.Millisecond
+	var tick func(progress float32)
+
+	switch actionType {
+	case "level_up":
+		// 大きくジャンプ
+		duration = 1200 * time.Millisecond
+		p.effect = "LEVEL UP!"
+		tick = func(progress float32) {
+			p.offsetY = -22 * float32(math.Abs(math.Sin(float64(progress)*math.Pi*2)))
+		}
+	case "evolution":
+		// 光りながら大きくなる
+		duration = 2 * time.Second
+		p.effect = "しんか!"
+		tick = func(progress float32) {
+			p.scale = 1 + 0.2*float32(math.Sin(float64(progress)*math.Pi))
+			p.flash = int(progress*10)%2 == 0 && progress < 0.9
+		}
+	case "encouraging", "sad", "wait":
+		// ゆっくり左右に揺れて励ます
+		duration = 1200 * time.Millisecond
+		p.effect = "ファイト!"
+		tick = func(progress float32) {
+			p.offsetX = 6 * float32(math.Sin(float64(progress)*math.Pi*4))
+		}
+	default: // "happy", "play"
+		// 小さく2回跳ねる
+		p.effect = "♪"
+		tick = func(progress float32) {
+			p.offsetY = -12 * float32(math.Abs(math.Sin(float64(progress)*math.Pi*2)))
+		}
+	}
+
+	var anim *fyne.Animation
+	anim = fyne.NewAnimation(duration, func(progress float32) {
+		tick(progress)
+		if progress >= 1 && p.action == anim {
+			// 終了時に元の姿勢に戻す
+			p.action = nil
+			p.offsetX, p.offsetY, p.scale = 0, 0, 1
+			p.flash = false
+			p.effect = ""
+		}
+		p.Refresh()
+	})
+	anim.Curve = fyne.AnimationLinear
+	p.action = anim
+	anim.Start()
+}
+
+// CreateRenderer レンダラーを作成
+func (p *PetWidget) CreateRenderer() fyne.WidgetRenderer {
+	r := &petRenderer{
+		pet:        p,
+		shadow:     canvas.NewCircle(petShadowColor),
+		body:       canvas.NewCircle(color.Transparent),
+		belly:      canvas.NewCircle(color.Transparent),
+		head:       canvas.NewCircle(color.Transparent),
+		leftEar:    canvas.NewCircle(color.Transparent),
+		rightEar:   canvas.NewCircle(color.Transparent),
+		leftEye:    canvas.NewCircle(petInkColor),
+		rightEye:   canvas.NewCircle(petInkColor),
+		leftLid:    canvas.NewLine(petInkColor),
+		rightLid:   canvas.NewLine(petInkColor),
+		leftCheek:  canvas.NewCircle(petCheekColor),
+		rightCheek: canvas.NewCircle(petCheekColor),
+		mouthL:     canvas.NewLine(petInkColor),
+		mouthR:     canvas.NewLine(petInkColor),
+		horn:       canvas.NewLine(color.Transparent),
+		hornL:      canvas.NewLine(color.Transparent),
+		hornR:      canvas.NewLine(color.Transparent),
+		scarf:      canvas.NewRectangle(petScarfColor),
+		capTop:     canvas.NewRectangle(petCapColor),
+		capBase:    canvas.NewRectangle(petCapColor),
+		effect:     canvas.NewText("", petEffectColor),
+	}
+	r.effect.TextStyle = fyne.TextStyle{Bold: true}
+	r.effect.Alignment = fyne.TextAlignCenter
+	r.objects = []fyne.CanvasObject{
+		r.shadow, r.leftEar, r.rightEar, r.hornL, r.hornR, r.horn,
+		r.body, r.belly, r.scarf, r.head,
+		r.leftEye, r.rightEye, r.leftLid, r.rightLid, r.leftCheek, r.rightCheek, r.mouthL, r.mouthR,
+		r.capBase, r.capTop, r.effect,
+	}
+	r.Refresh()
+	return r
+}
+
+// petRenderer ペットの描画
+type petRenderer struct {
+	pet *PetWidget
+
+	shadow, body, belly, head         *canvas.Circle
+	leftEar, rightEar                 *canvas.Circle
+	leftEye, rightEye                 *canvas.Circle
+	leftCheek, rightCheek             *canvas.Circle
+	leftLid, rightLid, mouthL, mouthR *canvas.Line
+	horn, hornL, hornR                *canvas.Line
+	scarf, capTop, capBase            *canvas.Rectangle
+	effect                            *canvas.Text
+	objects                           []fyne.CanvasObject
+}
+
+// MinSize 最小サイズ
+func (r *petRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(120, 120)
+}
+
+// Layout 現在のアニメーション状態に合わせて各パーツを配置
+func (r *petRenderer) Layout(size fyne.Size) {
+	p := r.pet
+	unit := min(size.Width, size.Height)
+	radius := unit * 0.22 * p.scale
+
+	cx := size.Width/2 + p.offsetX
+	ground := size.Height/2 + unit*0.4
+	bodyY := size.Height/2 + unit*0.14 + p.offsetY
+	headY := bodyY - radius*1.05
+	headR := radius * 0.85
+
+	// 影はジャンプ中に小さくなる
+	shadowW := radius * (1.1 + p.offsetY/60)
+	placeEllipse(r.shadow, size.Width/2, ground, shadowW, radius*0.18)
+
+	placeEllipse(r.body, cx, bodyY, radius, radius*0.9)
+	placeEllipse(r.belly, cx, bodyY+radius*0.15, radius*0.6, radius*0.55)
+	placeEllipse(r.head, cx, headY, headR, headR*0.92)
+
+	earR := headR * 0.32
+	placeEllipse(r.leftEar, cx-headR*0.62, headY-headR*0.72, earR, earR*1.2)
+	placeEllipse(r.rightEar, cx+headR*0.62, headY-headR*0.72, earR, earR*1.2)
+
+	// 目（まばたき中は線で表示）
+	eyeR := headR * 0.11
+	eyeY := headY - headR*0.05
+	placeEllipse(r.leftEye, cx-headR*0.35, eyeY, eyeR, eyeR)
+	placeEllipse(r.rightEye, cx+headR*0.35, eyeY, eyeR, eyeR)
+	placeLine(r.leftLid, cx-headR*0.35-eyeR, eyeY, cx-headR*0.35+eyeR, eyeY)
+	placeLine(r.rightLid, cx+headR*0.35-eyeR, eyeY, cx+headR*0.35+eyeR, eyeY)
+
+	placeEllipse(r.leftCheek, cx-headR*0.58, headY+headR*0.25, headR*0.13, headR*0.09)
+	placeEllipse(r.rightCheek, cx+headR*0.58, headY+headR*0.25, headR*0.13, headR*0.09)
+
+	mouthY := headY + headR*0.3
+	placeLine(r.mouthL, cx-headR*0.16, mouthY, cx, mouthY+headR*0.1)
+	placeLine(r.mouthR, cx, mouthY+headR*0.1, cx+headR*0.16, mouthY)
+
+	// ユニコーンの角・ドラゴンの角
+	placeLine(r.horn, cx, headY-headR*0.85, cx+headR*0.1, headY-headR*1.55)
+	placeLine(r.hornL, cx-headR*0.4, headY-headR*0.8, cx-headR*0.55, headY-headR*1.25)
+	placeLine(r.hornR, cx+headR*0.4, headY-headR*0.8, cx+headR*0.55, headY-headR*1.25)
+
+	// 進化段階の飾り（マフラー・博士帽）
+	r.scarf.Move(fyne.NewPos(cx-radius*0.7, headY+headR*0.8))
+	r.scarf.Resize(fyne.NewSize(radius*1.4, radius*0.18))
+	r.capBase.Move(fyne.NewPos(cx-headR*0.75, headY-headR*1.05))
+	r.capBase.Resize(fyne.NewSize(headR*1.5, headR*0.14))
+	r.capTop.Move(fyne.NewPos(cx-headR*0.4, headY-headR*1.35))
+	r.capTop.Resize(fyne.NewSize(headR*0.8, headR*0.32))
+
+	r.effect.TextSize = max(unit*0.1, 10)
+	r.effect.Move(fyne.NewPos(0, 0))
+	r.effect.Resize(fyne.NewSize(size.Width, r.effect.TextSize*1.4))
+}
+
+// Refresh 配色・表示パーツを更新して再配置
+func (r *petRenderer) Refresh() {
+	p := r.pet
+	palette, exists := petPalettes[p.species]
+	if !exists {
+		palette = petPalettes["cat"]
+	}
+
+	body := palette.body
+	if p.flash {
+		body = petEvolveColor
+	}
+	r.body.FillColor = body
+	r.head.FillColor = body
+	r.belly.FillColor = palette.belly
+	r.leftEar.FillColor = palette.accent
+	r.rightEar.FillColor = palette.accent
+
+	r.horn.StrokeColor = palette.accent
+	r.hornL.StrokeColor = palette.accent
+	r.hornR.StrokeColor = palette.accent
+	setVisible(r.horn, p.species == "unicorn")
+	setVisible(r.hornL, p.species == "dragon")
+	setVisible(r.hornR, p.species == "dragon")
+	r.horn.StrokeWidth, r.hornL.StrokeWidth, r.hornR.StrokeWidth = 4, 4, 4
+	r.mouthL.StrokeWidth, r.mouthR.StrokeWidth = 2, 2
+	r.leftLid.StrokeWidth, r.rightLid.StrokeWidth = 2, 2
+
+	setVisible(r.leftEye, !p.blink)
+	setVisible(r.rightEye, !p.blink)
+	setVisible(r.leftLid, p.blink)
+	setVisible(r.rightLid, p.blink)
+
+	setVisible(r.scarf, p.evolution == "intermediate" || p.evolution == "advanced")
+	setVisible(r.capBase, p.evolution == "advanced")
+	setVisible(r.capTop, p.evolution == "advanced")
+
+	r.effect.Text = p.effect
+
+	r.Layout(p.Size())
+	for _, obj := range r.objects {
+		obj.Refresh()
+	}
+}
+
+// Objects 描画オブジェクト一覧
+func (r *petRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+// Destroy 破棄処理
+func (r *petRenderer) Destroy() {}
+
+// placeEllipse 中心と半径で円（楕円）を配置
+func placeEllipse(c *canvas.Circle, cx, cy, rx, ry float32) {
+	c.Move(fyne.NewPos(cx-rx, cy-ry))
+	c.Resize(fyne.NewSize(rx*2, ry*2))
+}
+
+// placeLine 始点と終点で線を配置
+func placeLine(l *canvas.Line, x1, y1, x2, y2 float32) {
+	l.Position1 = fyne.NewPos(x1, y1)
+	l.Position2 = fyne.NewPos(x2, y2)
+}
+
+// setVisible 表示・非表示を切り替え
+func setVisible(obj fyne.CanvasObject, visible bool) {
+	if visible {
+		obj.Show()
+	} else {
+		obj.Hide()
+	}
+}
@@ -0,0 +1,313 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+)
+
+// ポモドーロの時間設定
+const (
+	focusBlock = 25 * time.Minute // 集中時間
+	breakBlock = 5 * time.Minute  // 休憩時間
+
+	inactivityTimeout = 15 * time.Minute // 操作のないままこの時間がたったら学習を終える
+
+	maxFocusChartSessions = 10 // 集中度グラフに表示するセッション数
+)
+
+// focusTracker 学習セッション中のポモドーロタイマーと集中度の記録
+type focusTracker struct {
+	blockStart     time.Time     // 現在の集中ブロックの開始時刻
+	pausedAt       time.Time     // 一時停止中の場合の停止時刻
+	pausedTotal    time.Duration // 現在の集中ブロックで一時停止していた時間
+	pausedSession  time.Duration // セッション全体で一時停止していた時間（学習時間に含めない）
+	lastActivity   time.Time     // 最後に問題を表示・解答・再開した時刻
+	breakEnds      time.Time     // 休憩中の場合の終了予定時刻
+	breakPrompted  bool          // 現在の集中ブロックで休憩を提案済みか
+	problemShownAt time.Time     // 表示中の問題を出した時刻
+
+	pauseCount      int
+	breaksSuggested int
+	breaksTaken     int
+	answerTimes     []int // 各問題の解答時間（秒）
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// newFocusTracker 集中度の記録を開始
+func newFocusTracker() *focusTracker {
+	now := time.Now()
+	return &focusTracker{
+		blockStart:   now,
+		lastActivity: now,
+		stop:         make(chan struct{}),
+	}
+}
+
+// isPaused 一時停止中かどうか
+func (f *focusTracker) isPaused() bool {
+	return !f.pausedAt.IsZero()
+}
+
+// onBreak 休憩中かどうか
+func (f *focusTracker) onBreak() bool {
+	return !f.breakEnds.IsZero()
+}
+
+// focusElapsed 現在の集中ブロックの経過時間（一時停止中の時間を除く）
+func (f *focusTracker) focusElapsed(now time.Time) time.Duration {
+	if f.isPaused() {
+		now = f.pausedAt
+	}
+	return now.Sub(f.blockStart) - f.pausedTotal
+}
+
+// pausedDuration セッション全体で一時停止していた時間（一時停止中ならnowまでを含める）
+func (f *focusTracker) pausedDuration(now time.Time) time.Duration {
+	if f.isPaused() {
+		return f.pausedSession + now.Sub(f.pausedAt)
+	}
+	return f.pausedSession
+}
+
+// inactive 操作のないまま学習を終える時間がたったか（一時停止中・休憩中は数えない）
+func (f *focusTracker) inactive(now time.Time) bool {
+	return !f.isPaused() && !f.onBreak() && now.Sub(f.lastActivity) >= inactivityTimeout
+}
+
+// startBlock 新しい集中ブロックを開始
+func (f *focusTracker) startBlock(now time.Time) {
+	f.blockStart = now
+	f.pausedTotal = 0
+	f.breakEnds = time.Time{}
+	f.breakPrompted = false
+	f.lastActivity = now
+}
+
+// result 集中度の計算に使う記録を取得
+func (f *focusTracker) result() progress.FocusInput {
+	return progress.FocusInput{
+		PauseCount:      f.pauseCount,
+		BreaksSuggested: f.breaksSuggested,
+		BreaksTaken:     f.breaksTaken,
+		AnswerTimes:     f.answerTimes,
+	}
+}
+
+// halt タイマーを停止
+func (f *focusTracker) halt() {
+	f.stopOnce.Do(func() { close(f.stop) })
+}
+
+// startFocusTracking ポモドーロタイマーを開始
+func (s *StudyView) startFocusTracking(mainApp *MainApp) {
+	tracker := newFocusTracker()
+	s.focus = tracker
+	s.pauseBtn.SetText("⏸ 一時停止")
+	s.pauseBtn.Enable()
+	s.endBtn.Enable()
+	s.showPaused(false)
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-tracker.stop:
+				return
+			case <-ticker.C:
+				fyne.Do(func() {
+					if s.focus == tracker {
+						s.updateTimer(mainApp)
+					}
+				})
+			}
+		}
+	}()
+	s.updateTimer(mainApp)
+}
+
+// updateTimer タイマー表示を更新し、集中時間が終わったら休憩を提案
+func (s *StudyView) updateTimer(mainApp *MainApp) {
+	f := s.focus
+	now := time.Now()
+	if f.inactive(now) {
+		s.endInactiveSession(mainApp, now)
+		return
+	}
+
+	switch {
+	case f.isPaused():
+		s.timerLabel.SetText(fmt.Sprintf("⏸ 一時停止中 %s", formatClock(f.focusElapsed(now))))
+
+	case f.onBreak():
+		remaining := f.breakEnds.Sub(now)
+		if remaining <= 0 {
+			f.startBlock(now)
+			mainApp.ShowInfoDialog("🍅 休憩終了", "休憩おわり！次の25分も集中していこう。")
+			s.updateTimer(mainApp)
+			return
+		}
+		s.timerLabel.SetText(fmt.Sprintf("☕ 休憩中 %s", formatClock(remaining)))
+		s.progressBar.SetValue(1 - remaining.Seconds()/breakBlock.Seconds())
+
+	default:
+		elapsed := f.focusElapsed(now)
+		s.timerLabel.SetText(fmt.Sprintf("🍅 %s / %s", formatClock(elapsed), formatClock(focusBlock)))
+		s.progressBar.SetValue(min(elapsed.Seconds()/focusBlock.Seconds(), 1))
+
+		if elapsed >= focusBlock && !f.breakPrompted {
+			f.breakPrompted = true
+			f.breaksSuggested++
+			s.suggestBreak(mainApp)
+		}
+	}
+}
+
+// suggestBreak 休憩を提案するダイアログを表示
+func (s *StudyView) suggestBreak(mainApp *MainApp) {
+	f := s.focus
+	confirm := dialog.NewConfirm("☕ 休憩しませんか？",
+		fmt.Sprintf("%d分間よく集中できました！\n%d分休憩すると、次の学習の集中力が上がります。",
+			int(focusBlock.Minutes()), int(breakBlock.Minutes())),
+		func(takeBreak bool) {
+			if s.focus != f {
+				return
+			}
+			now := time.Now()
+			if takeBreak {
+				f.breaksTaken++
+				f.breakEnds = now.Add(breakBlock)
+			} else {
+				f.startBlock(now)
+			}
+			s.updateTimer(mainApp)
+		}, mainApp.window)
+	confirm.SetConfirmText("休憩する")
+	confirm.SetDismissText("続ける")
+	confirm.Show()
+}
+
+// togglePause タイマーの一時停止・再開
+func (s *StudyView) togglePause(mainApp *MainApp) {
+	f := s.focus
+	if f == nil || f.onBreak() {
+		return
+	}
+
+	now := time.Now()
+	if f.isPaused() {
+		f.pausedTotal += now.Sub(f.pausedAt)
+		f.pausedSession += now.Sub(f.pausedAt)
+		// 一時停止していた時間は解答時間に含めない
+		if !f.problemShownAt.IsZero() {
+			f.problemShownAt = f.problemShownAt.Add(now.Sub(f.pausedAt))
+		}
+		f.pausedAt = time.Time{}
+		f.lastActivity = now
+		s.pauseBtn.SetText("⏸ 一時停止")
+	} else {
+		f.pausedAt = now
+		f.pauseCount++
+		s.pauseBtn.SetText("▶ 再開")
+	}
+	s.showPaused(f.isPaused())
+	s.updateTimer(mainApp)
+}
+
+// showPaused 一時停止中は問題を隠す（タイマーを止めたまま解けないようにする）
+func (s *StudyView) showPaused(paused bool) {
+	if paused {
+		s.scroll.Hide()
+		s.pausedNotice.Show()
+		return
+	}
+	s.pausedNotice.Hide()
+	s.scroll.Show()
+}
+
+// recordProblemShown 問題を表示した時刻を記録
+func (s *StudyView) recordProblemShown() {
+	if s.focus != nil {
+		s.focus.problemShownAt = time.Now()
+		s.focus.lastActivity = s.focus.problemShownAt
+	}
+}
+
+// recordAnswer 問題ごとの解答時間を記録して返す（記録できない場合は-1）
+func (s *StudyView) recordAnswer() int {
+	f := s.focus
+	if f == nil || f.problemShownAt.IsZero() {
+		return -1
+	}
+	seconds := int(time.Since(f.problemShownAt).Seconds())
+	f.answerTimes = append(f.answerTimes, seconds)
+	f.problemShownAt = time.Time{}
+	f.lastActivity = time.Now()
+	return seconds
+}
+
+// finishSession 学習セッションを終了し、集中度を保存
+func (s *StudyView) finishSession(mainApp *MainApp) {
+	if s.currentSession == nil {
+		return
+	}
+
+	endTime := time.Now()
+	s.currentSession.EndTime = &endTime
+	if f := s.focus; f != nil {
+		s.currentSession.PausedSeconds = int(f.pausedDuration(endTime).Seconds())
+	}
+	if err := mainApp.db.UpdateStudySession(s.currentSession); err != nil {
+		slog.Error("セッション終了処理エラー", "error", err)
+	}
+
+	// 解説から翌日の復習カードを作成
+	mainApp.saveReviewCards(s.currentSession, s.sessionProblems)
+
+	if f := s.focus; f != nil {
+		f.halt()
+		s.focus = nil
+		s.pauseBtn.SetText("⏸ 一時停止")
+		s.pauseBtn.Disable()
+		s.endBtn.Disable()
+		s.showPaused(false)
+
+		// 問題を解いていないセッションは集中度を記録しない
+		if len(f.answerTimes) > 0 {
+			score, answerTimeCV := progress.ComputeFocusScore(f.result())
+			focus := &database.SessionFocus{
+				SessionID:       s.currentSession.ID,
+				UserID:          s.currentSession.UserID,
+				PauseCount:      f.pauseCount,
+				BreaksSuggested: f.breaksSuggested,
+				BreaksTaken:     f.breaksTaken,
+				AnswerCount:     len(f.answerTimes),
+				AnswerTimeCV:    answerTimeCV,
+				FocusScore:      score,
+				StartedAt:       s.currentSession.StartTime,
+			}
+			if err := mainApp.db.UpsertSessionFocus(focus); err != nil {
+				slog.Error("集中度保存エラー", "error", err)
+			} else {
+				slog.Info("🎯 集中度", "score", score, "pauses", f.pauseCount, "breaks_taken", f.breaksTaken, "breaks_suggested", f.breaksSuggested)
+			}
+		}
+	}
+}
+
+// formatClock 経過時間を「mm:ss」形式に変換
+func formatClock(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,168 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/export"
+)
+
+// createProfileTransferCard プロフィールの書き出し・読み込みのカードを作成（別のパソコンへ移すため）
+func (m *MainApp) createProfileTransferCard() *widget.Card {
+	description := widget.NewLabel("学習の記録・設定・問題バンク・ペットを、パスフレーズで暗号化した1つのファイルにまとめます。\n別のパソコンで読み込むと、このプロフィールをそのまま続けられます。")
+	description.Wrapping = fyne.TextWrapWord
+
+	exportBtn := widget.NewButton("📤 プロファイルを書き出す", m.exportProfile)
+	importBtn := widget.NewButton("📥 プロファイルを読み込む", m.importProfile)
+
+	return widget.NewCard("プロフィールの移行", "",
+		container.NewVBox(description, container.NewHBox(exportBtn, importBtn)))
+}
+
+// exportProfile パスフレーズを決めてもらい、プロフィールを暗号化したファイルに書き出す
+func (m *MainApp) exportProfile() {
+	passphraseEntry := widget.NewPasswordEntry()
+	passphraseEntry.Validator = func(value string) error {
+		if len([]rune(value)) < export.MinPassphraseLength {
+			return fmt.Errorf("%d文字以上で入力してください", export.MinPassphraseLength)
+		}
+		return nil
+	}
+	confirmEntry := widget.NewPasswordEntry()
+	confirmEntry.Validator = func(value string) error {
+		if value != passphraseEntry.Text {
+			return fmt.Errorf("パスフレーズが一致しません")
+		}
+		return nil
+	}
+	note := widget.NewLabel("読み込むときに同じパスフレーズが必要です。忘れると読み込めません。")
+	note.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("パスフレーズ", passphraseEntry),
+		widget.NewFormItem("もう一度", confirmEntry),
+		widget.NewFormItem("", note),
+	}
+	form := dialog.NewForm("📤 プロファイルを書き出す", "保存先を選ぶ", "キャンセル", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		passphrase := passphraseEntry.Text
+
+		data, err := m.db.ExportProfile(m.currentUser.ID)
+		if err != nil {
+			slog.Error("プロフィール書き出しエラー", "error", err)
+			m.ShowErrorDialog("エラー", fmt.Sprintf("プロフィールを読み込めませんでした: %v", err))
+			return
+		}
+		bundle := &export.ProfileBundle{
+			ExportedAt: time.Now(),
+			Settings:   export.NewProfileSettings(m.config),
+			Data:       data,
+		}
+
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				m.ShowErrorDialog("エラー", fmt.Sprintf("保存先の選択に失敗しました: %v", err))
+				return
+			}
+			if writer == nil {
+				return // キャンセル
+			}
+
+			// 鍵の作成に時間がかかるため、画面を止めないようにする
+			go func() {
+				defer func() { _ = writer.Close() }()
+				err := export.WriteProfile(writer, bundle, passphrase)
+				fyne.Do(func() {
+					if err != nil {
+						slog.Error("プロフィール書き出しエラー", "error", err)
+						m.ShowErrorDialog("エラー", fmt.Sprintf("プロフィールのファイルの作成に失敗しました: %v", err))
+						return
+					}
+					m.ShowInfoDialog("保存完了", fmt.Sprintf("%s に保存しました。\n別のパソコンの「設定」→「プロファイルを読み込む」から開いてください。", writer.URI().Name()))
+				})
+			}()
+		}, m.window)
+		saveDialog.SetFileName("studybuddy" + export.ProfileFileExtension)
+		saveDialog.Show()
+	}, m.window)
+	form.Resize(fyne.NewSize(420, 280))
+	form.Show()
+}
+
+// importProfile プロフィールのファイルを選んでもらい、パスフレーズで開いて今のプロフィールと置き換える
+func (m *MainApp) importProfile() {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("ファイルの選択に失敗しました: %v", err))
+			return
+		}
+		if reader == nil {
+			return // キャンセル
+		}
+
+		passphraseEntry := widget.NewPasswordEntry()
+		items := []*widget.FormItem{widget.NewFormItem("パスフレーズ", passphraseEntry)}
+		form := dialog.NewForm("📥 プロファイルを読み込む", "開く", "キャンセル", items, func(confirmed bool) {
+			if !confirmed {
+				_ = reader.Close()
+				return
+			}
+			passphrase := passphraseEntry.Text
+			go func() {
+				defer func() { _ = reader.Close() }()
+				bundle, err := export.ReadProfile(reader, passphrase)
+				fyne.Do(func() {
+					if err != nil {
+						if !errors.Is(err, export.ErrWrongPassphrase) {
+							slog.Error("プロフィール読み込みエラー", "error", err)
+						}
+						m.ShowErrorDialog("エラー", fmt.Sprintf("プロフィールを開けませんでした: %v", err))
+						return
+					}
+					m.confirmProfileImport(bundle)
+				})
+			}()
+		}, m.window)
+		form.Resize(fyne.NewSize(400, 160))
+		form.Show()
+	}, m.window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{export.ProfileFileExtension}))
+	openDialog.Show()
+}
+
+// confirmProfileImport 今のプロフィールが置き換わることを確認してから読み込む
+func (m *MainApp) confirmProfileImport(bundle *export.ProfileBundle) {
+	message := fmt.Sprintf("%sに書き出したプロフィールを読み込みます。\n今のプロフィールの学習の記録と設定は、読み込んだ内容に置き換わります。\nよろしいですか？",
+		bundle.ExportedAt.Format("2006年01月02日 15:04"))
+	dialog.ShowConfirm("📥 プロファイルを読み込む", message, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		userID := m.currentUser.ID
+		m.endActivity()
+		if err := m.db.ImportProfile(userID, bundle.Data); err != nil {
+			slog.Error("プロフィール読み込みエラー", "error", err)
+			m.ShowErrorDialog("エラー", fmt.Sprintf("プロフィールの読み込みに失敗しました: %v", err))
+			return
+		}
+		bundle.Settings.ApplyTo(m.config)
+		m.refreshTheme()
+
+		// 読み込んだプロフィールで画面を作り直す
+		m.studyView = nil
+		m.dashboard = nil
+		m.signIn(userID)
+		m.ShowInfoDialog("読み込み完了", "プロフィールを読み込みました。続きから学習できます。")
+	}, m.window)
+}
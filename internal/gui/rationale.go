@@ -0,0 +1,96 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 答えを選んだ理由を聞く間隔の選択肢（問）
+var rationaleIntervals = []int{0, 3, 5, 10}
+
+// rationaleDue 答えを選んだ理由を聞く問題か（設定の問題数ごと。当てずっぽうの解答が続いているときは聞かない）
+func (m *MainApp) rationaleDue(answered int, guessing bool) bool {
+	every := m.config.Learning.RationaleEvery
+	return every > 0 && !guessing && answered > 0 && answered%every == 0
+}
+
+// askRationale フィードバックの前に「どうしてその答えを選んだ？」と聞き、理由に合わせたフィードバックを作る
+// （理由は解答の記録に保存する。スキップしたときはふつうのフィードバック）
+func (s *StudyView) askRationale(result *database.ProblemResult, mainApp *MainApp) {
+	entry := widget.NewMultiLineEntry()
+	entry.Wrapping = fyne.TextWrapWord
+	entry.SetMinRowsVisible(2)
+	entry.SetPlaceHolder("例: マイナスの数は、数字が大きいほど小さいから")
+	entry.OnChanged = func(text string) {
+		if utf8.RuneCountInString(text) > ai.MaxRationaleRunes {
+			entry.SetText(string([]rune(text)[:ai.MaxRationaleRunes]))
+		}
+	}
+
+	session := s.currentSession
+	answered := false
+	submit := func(rationale string) {
+		if answered || s.currentSession != session {
+			return
+		}
+		answered = true
+		if rationale = strings.TrimSpace(rationale); rationale != "" {
+			result.Rationale = rationale
+			if err := mainApp.db.UpdateProblemResultRationale(result.ID, rationale); err != nil {
+				slog.Error("答えを選んだ理由の保存エラー", "error", err)
+			}
+			label := widget.NewLabel("あなたの理由: " + rationale)
+			label.Wrapping = fyne.TextWrapWord
+			s.optionsContainer.Add(label)
+		}
+		s.feedbackCard.SetTitle("💭 フィードバック")
+		s.feedbackText.ParseMarkdown("フィードバックを作成しています...")
+		s.feedbackCard.SetContent(s.feedbackText)
+		s.showFeedback(result, mainApp)
+	}
+
+	sendBtn := widget.NewButton("理由を送る", func() { submit(entry.Text) })
+	sendBtn.Importance = widget.HighImportance
+	skipBtn := widget.NewButton("スキップ", func() { submit("") })
+
+	prompt := widget.NewLabel("正解かどうかを見る前に、その答えを選んだ理由をひとことで書いてみよう。理由に合わせて解説します。")
+	prompt.Wrapping = fyne.TextWrapWord
+	s.feedbackCard.SetTitle("🤔 どうしてその答えを選んだ？")
+	s.feedbackCard.SetContent(container.NewVBox(prompt, entry, container.NewHBox(sendBtn, skipBtn)))
+	mainApp.window.Canvas().Focus(entry)
+}
+
+// newRationaleSelect 設定画面の、答えを選んだ理由を聞く間隔を選ぶ欄
+func (m *MainApp) newRationaleSelect() *widget.Select {
+	labels := make([]string, len(rationaleIntervals))
+	for i, every := range rationaleIntervals {
+		labels[i] = rationaleIntervalLabel(every)
+	}
+	rationaleSelect := widget.NewSelect(labels, func(value string) {
+		for _, every := range rationaleIntervals {
+			if rationaleIntervalLabel(every) == value && m.config.Learning.RationaleEvery != every {
+				m.config.Learning.RationaleEvery = every
+				m.saveConfig()
+			}
+		}
+	})
+	rationaleSelect.Selected = rationaleIntervalLabel(m.config.Learning.RationaleEvery)
+	return rationaleSelect
+}
+
+// rationaleIntervalLabel 理由を聞く間隔の表示（例: 「5問ごと」）
+func rationaleIntervalLabel(every int) string {
+	if every <= 0 {
+		return "聞かない"
+	}
+	return fmt.Sprintf("%d問ごと", every)
+}
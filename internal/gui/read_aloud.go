@@ -0,0 +1,49 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/speech"
+)
+
+// readAloud 設定で読み上げを使うとき、文章を日本語の音声で読み上げる（読み上げ中の音声は止める）
+func (m *MainApp) readAloud(text string) {
+	if !m.config.UI.ReadAloud || strings.TrimSpace(text) == "" {
+		return
+	}
+	m.goSafe("読み上げ", func() {
+		if err := m.speaker.Speak(context.Background(), speech.Japanese, speech.Readable(text), speech.Normal); err != nil {
+			slog.Error("読み上げエラー", "error", err)
+		}
+	}, nil)
+}
+
+// newReadAloudButton 文章をもう一度読み上げるボタン
+func (m *MainApp) newReadAloudButton(label, text string) *widget.Button {
+	return widget.NewButton("🔈 "+label, func() { m.readAloud(text) })
+}
+
+// problemReadAloudText 問題のタイトル・問題文・選択肢を読み上げる文章
+func problemReadAloudText(problem *ai.Problem) string {
+	parts := []string{problem.Title + "。", problem.Description}
+	for i, option := range problem.Options {
+		parts = append(parts, fmt.Sprintf("%d、%s。", i+1, option))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// feedbackReadAloudText フィードバックのメッセージ・正解・解説を読み上げる文章
+func feedbackReadAloudText(problem *ai.Problem, correctAnswer string, feedback *ai.FeedbackResponse) string {
+	explanation := feedback.Explanation
+	if explanation == "" {
+		explanation = problem.Explanation
+	}
+	parts := []string{feedback.Message, "正解は、" + correctAnswer + "。", explanation, feedback.Encouragement}
+	return strings.Join(parts, "\n")
+}
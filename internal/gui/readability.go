@@ -0,0 +1,94 @@
+package gui
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/readability"
+)
+
+// 文章の読みやすさを調べるAIの問題
+const (
+	readabilityDays    = 30 // 期間（日数）
+	readabilitySamples = 50 // モデルごとの最大件数（新しい順）
+)
+
+// modelReadability モデルごとの、作った問題の文章の読みやすさ
+type modelReadability struct {
+	model string
+	stats readability.Stats
+}
+
+// showReadabilityReport 最近AIが作った問題の文章の、1文の長さと漢字の学年をモデルごとに表示
+// （お子さんの学年に合った日本語を書くモデルを選べるようにする）
+func (m *MainApp) showReadabilityReport() {
+	outputs, err := m.db.GetRecentModelOutputs(time.Now().AddDate(0, 0, -readabilityDays), readabilitySamples)
+	if err != nil {
+		slog.Error("AIが作った問題の取得エラー", "error", err)
+		m.ShowErrorDialog("エラー", fmt.Sprintf("AIが作った問題を読み込めませんでした: %v", err))
+		return
+	}
+	if len(outputs) == 0 {
+		m.ShowInfoDialog("AIの文章の読みやすさ", fmt.Sprintf("最近%d日間にAIが作った問題がありません。AIで問題を作ると、モデルごとの文章の読みやすさが表示されます。", readabilityDays))
+		return
+	}
+
+	byModel := make(map[string]*modelReadability)
+	var reports []*modelReadability
+	for _, output := range outputs {
+		report := byModel[output.Model]
+		if report == nil {
+			report = &modelReadability{model: output.Model}
+			byModel[output.Model] = report
+			reports = append(reports, report)
+		}
+		report.stats.Add(readability.Analyze(output.Description + "\n" + output.Explanation))
+	}
+	// 漢字の学年が低く、1文が短いモデルから並べる
+	slices.SortStableFunc(reports, func(a, b *modelReadability) int {
+		return cmp.Or(
+			cmp.Compare(a.stats.KanjiLevel(), b.stats.KanjiLevel()),
+			cmp.Compare(a.stats.JuniorHighRatio(), b.stats.JuniorHighRatio()),
+			cmp.Compare(a.stats.AverageSentenceLength(), b.stats.AverageSentenceLength()),
+		)
+	})
+
+	description := widget.NewLabel(fmt.Sprintf("最近%d日間にAIが作った問題文と解説（モデルごとに新しい%d問まで）の文章を調べました。"+
+		"「漢字の学年」は、文章の漢字の9割を習い終える学年です。小学校で習わない漢字が多いモデルや1文が長いモデルは、"+
+		"中%dのお子さんには読みにくいことがあります。上にあるモデルほど読みやすい文章を書いています。",
+		readabilityDays, readabilitySamples, m.currentUser.Grade))
+	description.Wrapping = fyne.TextWrapWord
+
+	list := container.NewVBox()
+	for _, report := range reports {
+		name := report.model
+		if ai.SameModel(report.model, m.config.AI.Model) {
+			name += "（使用中）"
+		}
+		list.Add(container.NewVBox(
+			widget.NewLabelWithStyle(name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			widget.NewLabel(readabilityText(report.stats)),
+		))
+	}
+
+	popup := dialog.NewCustom("📝 AIの文章の読みやすさ", "閉じる",
+		container.NewBorder(description, nil, nil, nil, container.NewVScroll(list)), m.window)
+	popup.Resize(fyne.NewSize(560, 480))
+	popup.Show()
+}
+
+// readabilityText 文章の読みやすさの説明（例: 「12問・1文 平均32文字・漢字 28%・漢字の学年 小5・小学校で習わない漢字 4%」）
+func readabilityText(stats readability.Stats) string {
+	return fmt.Sprintf("%d問・1文 平均%.0f文字・漢字 %.0f%%・漢字の学年 %s・小学校で習わない漢字 %.0f%%",
+		stats.Texts, stats.AverageSentenceLength(), stats.KanjiRatio()*100,
+		readability.LevelLabel(stats.KanjiLevel()), stats.JuniorHighRatio()*100)
+}
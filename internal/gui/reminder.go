@@ -0,0 +1,165 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/reminder"
+	"github.com/okamyuji/studybuddy-ai/internal/schedule"
+)
+
+// weekdayLabels 学習リマインドの曜日の表示名（0:日曜〜6:土曜）
+var weekdayLabels = []string{"日", "月", "火", "水", "木", "金", "土"}
+
+// RunReminders ctxが取り消されるまで、設定した時刻に学習リマインドを通知する（制限モードでは通知しない）
+func (m *MainApp) RunReminders(ctx context.Context) {
+	if m.config.Kiosk {
+		return
+	}
+	scheduler := reminder.NewScheduler(
+		func() config.ReminderConfig { return m.config.Reminder },
+		m.reminderPlan,
+		m.reminderStatus,
+		func(notification reminder.Notification) {
+			m.app.SendNotification(fyne.NewNotification(notification.Title, notification.Content))
+		},
+	)
+	scheduler.Run(ctx, reminder.CheckInterval)
+}
+
+// reminderPlan 学習リマインドに使う、その日の学習計画（プロフィールが選ばれていなければnil）
+func (m *MainApp) reminderPlan(day time.Time) (*schedule.DayPlan, error) {
+	if m.currentUser == nil {
+		return nil, nil
+	}
+	return m.planner.PlanDay(m.currentUser.ID, day, m.config.Learning.StudyGoalTime, m.config.OrderedSubjects())
+}
+
+// reminderStatus 今日もう学習したかと、連続で学習した日数
+func (m *MainApp) reminderStatus(now time.Time) (reminder.Status, error) {
+	if m.currentUser == nil {
+		return reminder.Status{}, fmt.Errorf("プロフィールが選ばれていません")
+	}
+	streak, err := m.progressManager.GetStudyStreak(m.currentUser.ID)
+	if err != nil {
+		return reminder.Status{}, fmt.Errorf("連続学習の取得エラー: %w", err)
+	}
+	return reminder.Status{
+		StudiedToday: streak.LastStudyDate.Format("2006-01-02") == now.Format("2006-01-02"),
+		Streak:       streak.CurrentStreak,
+	}, nil
+}
+
+// createReminderCard 学習リマインドの通知を設定するカード
+func (m *MainApp) createReminderCard() *widget.Card {
+	description := widget.NewLabel("学習計画のコマの始まり（または決めた時刻）にデスクトップへ通知します。その日にもう学習していれば通知しません。例外日・祝日・長期休みは学習の時間を通知しません。")
+	description.Wrapping = fyne.TextWrapWord
+
+	cfg := m.config.Reminder
+	enableCheck := widget.NewCheck("学習リマインドを使う", nil)
+	enableCheck.SetChecked(cfg.Enabled)
+
+	timesEntry := widget.NewEntry()
+	timesEntry.SetText(strings.Join(cfg.Times, ", "))
+	timesEntry.SetPlaceHolder("19:00, 21:00")
+	timesEntry.Validator = func(text string) error {
+		_, err := parseReminderTimes(text)
+		return err
+	}
+
+	// 学習計画に合わせるときは、決めた時刻は使わない
+	followCheck := widget.NewCheck("学習計画のコマに合わせて通知する（時間割・部活動・例外日から計画）", func(follow bool) {
+		if follow {
+			timesEntry.Disable()
+		} else {
+			timesEntry.Enable()
+		}
+	})
+	followCheck.SetChecked(cfg.FollowPlan)
+
+	weekdayCheck := widget.NewCheckGroup(weekdayLabels, nil)
+	weekdayCheck.Horizontal = true
+	for _, weekday := range cfg.Weekdays {
+		weekdayCheck.Selected = append(weekdayCheck.Selected, weekdayLabels[weekday])
+	}
+	weekdayCheck.Refresh()
+
+	streakCheck := widget.NewCheck("連続学習が途切れそうなときに知らせる（毎日）", nil)
+	streakCheck.SetChecked(cfg.StreakAlert)
+	streakEntry := widget.NewEntry()
+	streakEntry.SetText(cfg.StreakAlertTime)
+	streakEntry.Validator = func(text string) error {
+		if _, err := time.Parse(config.ReminderTimeLayout, strings.TrimSpace(text)); err != nil {
+			return fmt.Errorf("「20:30」のように入力してください")
+		}
+		return nil
+	}
+
+	saveBtn := widget.NewButton("保存", func() {
+		for _, entry := range []*widget.Entry{timesEntry, streakEntry} {
+			if err := entry.Validate(); err != nil {
+				m.ShowErrorDialog("学習リマインド", err.Error())
+				return
+			}
+		}
+		times, _ := parseReminderTimes(timesEntry.Text)
+		var weekdays []int
+		for i, label := range weekdayLabels {
+			if slices.Contains(weekdayCheck.Selected, label) {
+				weekdays = append(weekdays, i)
+			}
+		}
+		m.config.Reminder = config.ReminderConfig{
+			Enabled:         enableCheck.Checked,
+			FollowPlan:      followCheck.Checked,
+			Times:           times,
+			Weekdays:        weekdays,
+			StreakAlert:     streakCheck.Checked,
+			StreakAlertTime: strings.TrimSpace(streakEntry.Text),
+		}
+		m.saveConfig()
+		m.ShowInfoDialog("学習リマインド", "学習リマインドの設定を保存しました。")
+	})
+	testBtn := widget.NewButton("🔔 通知を試す", func() {
+		m.app.SendNotification(fyne.NewNotification("📚 学習の時間です", "学習リマインドはこのように表示されます。"))
+	})
+
+	return widget.NewCard("🔔 学習リマインド", "", container.NewVBox(
+		description,
+		enableCheck,
+		followCheck,
+		widget.NewForm(
+			widget.NewFormItem("時刻", timesEntry),
+			widget.NewFormItem("曜日", weekdayCheck),
+		),
+		streakCheck,
+		widget.NewForm(widget.NewFormItem("知らせる時刻", streakEntry)),
+		container.NewGridWithColumns(2, saveBtn, testBtn),
+	))
+}
+
+// parseReminderTimes 「,」区切りの時刻（"19:00, 21:00"）を読み取る
+func parseReminderTimes(text string) ([]string, error) {
+	var times []string
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '、' || r == ' ' }) {
+		t, err := time.Parse(config.ReminderTimeLayout, field)
+		if err != nil {
+			return nil, fmt.Errorf("時刻は「19:00, 21:00」のように入力してください")
+		}
+		if clock := t.Format(config.ReminderTimeLayout); !slices.Contains(times, clock) {
+			times = append(times, clock)
+		}
+	}
+	if len(times) > config.MaxReminderTimes {
+		return nil, fmt.Errorf("時刻は%d件までです", config.MaxReminderTimes)
+	}
+	return times, nil
+}
@@ -0,0 +1,128 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// saveReviewCards セッションで解いた問題の解説から翌日の復習カードを作成（バックグラウンドで実行）
+func (m *MainApp) saveReviewCards(session *database.StudySession, problems []*ai.Problem) {
+	if len(problems) == 0 {
+		return
+	}
+	sessionProblems := make([]ai.Problem, len(problems))
+	for i, problem := range problems {
+		sessionProblems[i] = *problem
+	}
+	grade := m.currentUser.Grade
+
+	m.goSafe("復習カードの作成", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		takeaways := m.aiEngine.GenerateTakeaways(ctx, session.Subject, grade, sessionProblems)
+		for _, takeaway := range takeaways {
+			card := &database.ReviewCard{
+				ID:        uuid.New().String(),
+				UserID:    session.UserID,
+				SessionID: session.ID,
+				Subject:   session.Subject,
+				Takeaway:  takeaway.Point,
+				Question:  takeaway.Question,
+				Answer:    takeaway.Answer,
+				CreatedAt: time.Now(),
+			}
+			if err := m.db.CreateReviewCard(card); err != nil {
+				slog.Error("復習カード保存エラー", "error", err)
+				return
+			}
+		}
+		slog.Info("📝 復習カードを作成しました", "count", len(takeaways), "subject", session.Subject)
+	}, nil)
+}
+
+// createReviewCard 前日までのセッションの復習カードを表示する「昨日の復習」カードを作成（復習するカードがなければnil）
+func (m *MainApp) createReviewCard() *widget.Card {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	cards, err := m.db.GetPendingReviewCards(m.currentUser.ID, today, 3)
+	if err != nil {
+		slog.Error("復習カード取得エラー", "error", err)
+		return nil
+	}
+	if len(cards) == 0 {
+		return nil
+	}
+
+	points := container.NewVBox()
+	for _, card := range cards {
+		label := widget.NewLabel(fmt.Sprintf("・[%s] %s", card.Subject, card.Takeaway))
+		label.Wrapping = fyne.TextWrapWord
+		points.Add(label)
+	}
+
+	var reviewCard *widget.Card
+	quizBtn := widget.NewButton("✏️ 1問ずつクイズで確認", func() {
+		m.showReviewQuiz(cards, func() {
+			reviewCard.SetSubTitle("復習が終わりました。よくがんばりました！")
+			reviewCard.SetContent(points)
+		})
+	})
+	quizBtn.Importance = widget.HighImportance
+
+	reviewCard = widget.NewCard("📝 昨日の復習", "前回の学習の大事なポイント", container.NewVBox(points, quizBtn))
+	return reviewCard
+}
+
+// showReviewQuiz 復習カードの問いを1問ずつ出題し、覚えていたかどうかを記録
+func (m *MainApp) showReviewQuiz(cards []database.ReviewCard, onFinished func()) {
+	if len(cards) == 0 {
+		onFinished()
+		return
+	}
+	card := cards[0]
+
+	question := widget.NewLabel(fmt.Sprintf("Q. %s", card.Question))
+	question.Wrapping = fyne.TextWrapWord
+	answer := widget.NewLabel(fmt.Sprintf("A. %s\n\n💡 %s", card.Answer, card.Takeaway))
+	answer.Wrapping = fyne.TextWrapWord
+	answer.Hide()
+
+	var quiz *dialog.CustomDialog
+	record := func(remembered bool) {
+		if err := m.db.MarkReviewCard(card.ID, remembered, time.Now()); err != nil {
+			slog.Error("復習結果保存エラー", "error", err)
+		}
+		quiz.Hide()
+		m.showReviewQuiz(cards[1:], onFinished)
+	}
+
+	rememberedBtn := widget.NewButton("✅ 覚えてた", func() { record(true) })
+	forgotBtn := widget.NewButton("🔁 忘れてた", func() { record(false) })
+	judge := container.NewGridWithColumns(2, rememberedBtn, forgotBtn)
+	judge.Hide()
+
+	var revealBtn *widget.Button
+	revealBtn = widget.NewButton("答えを見る", func() {
+		revealBtn.Hide()
+		answer.Show()
+		judge.Show()
+	})
+	revealBtn.Importance = widget.HighImportance
+
+	content := container.NewVBox(question, revealBtn, answer, judge)
+	quiz = dialog.NewCustom(fmt.Sprintf("昨日の復習（%s）", card.Subject), "閉じる", content, m.window)
+	quiz.Resize(fyne.NewSize(420, 280))
+	quiz.Show()
+}
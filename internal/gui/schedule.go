@@ -0,0 +1,352 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/schedule"
+)
+
+// weekdayNames 曜日の表示名（time.Weekdayの順）
+var weekdayNames = []string{"日", "月", "火", "水", "木", "金", "土"}
+
+// timetableKinds 予定の種類（表示名と保存値）
+var timetableKinds = []struct {
+	label string
+	value string
+}{
+	{"授業", "school"},
+	{"部活動", "club"},
+	{"塾", "juku"},
+	{"その他", "other"},
+}
+
+// ScheduleView 学習計画画面
+type ScheduleView struct {
+	container      *fyne.Container
+	planCard       *widget.Card
+	timetableList  *fyne.Container
+	exceptionList  *fyne.Container
+	timetableCard  *widget.Card
+	exceptionsCard *widget.Card
+	schoolCard     *widget.Card
+}
+
+// createScheduleView 学習計画画面を作成
+func (m *MainApp) createScheduleView() *ScheduleView {
+	view := &ScheduleView{
+		timetableList: container.NewVBox(),
+		exceptionList: container.NewVBox(),
+	}
+
+	view.planCard = widget.NewCard("📅 今週の学習プラン", "時間割の空き時間に合わせて提案します", widget.NewLabel(""))
+
+	// 時間割・部活動の登録
+	weekdaySelect := widget.NewSelect(weekdayNames, nil)
+	weekdaySelect.SetSelectedIndex(int(time.Monday))
+
+	kindLabels := make([]string, len(timetableKinds))
+	for i, kind := range timetableKinds {
+		kindLabels[i] = kind.label
+	}
+	kindSelect := widget.NewSelect(kindLabels, nil)
+	kindSelect.SetSelectedIndex(0)
+
+	startEntry := widget.NewEntry()
+	startEntry.SetPlaceHolder("08:30")
+	startEntry.Validator = schedule.ValidateClock
+	endEntry := widget.NewEntry()
+	endEntry.SetPlaceHolder("15:30")
+	endEntry.Validator = schedule.ValidateClock
+	labelEntry := widget.NewEntry()
+	labelEntry.SetPlaceHolder("例: 授業、サッカー部")
+
+	addEntryBtn := widget.NewButtonWithIcon("追加", theme.ContentAddIcon(), func() {
+		if startEntry.Validate() != nil || endEntry.Validate() != nil {
+			m.ShowErrorDialog("入力エラー", "時刻は「08:30」の形式で入力してください。")
+			return
+		}
+		if err := schedule.ValidateRange(startEntry.Text, endEntry.Text); err != nil {
+			m.ShowErrorDialog("入力エラー", err.Error())
+			return
+		}
+
+		label := strings.TrimSpace(labelEntry.Text)
+		if label == "" {
+			label = kindSelect.Selected
+		}
+
+		entry := &database.TimetableEntry{
+			ID:        uuid.New().String(),
+			UserID:    m.currentUser.ID,
+			Weekday:   weekdaySelect.SelectedIndex(),
+			StartTime: startEntry.Text,
+			EndTime:   endEntry.Text,
+			Label:     label,
+			Kind:      timetableKinds[kindSelect.SelectedIndex()].value,
+			CreatedAt: time.Now(),
+		}
+		if err := m.db.CreateTimetableEntry(entry); err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("時間割の保存に失敗しました: %v", err))
+			return
+		}
+
+		labelEntry.SetText("")
+		m.refreshScheduleView()
+	})
+
+	view.timetableCard = widget.NewCard("時間割・部活動", "毎週の予定を登録してください",
+		container.NewVBox(
+			container.NewGridWithColumns(2,
+				widget.NewLabel("曜日"), weekdaySelect,
+				widget.NewLabel("種類"), kindSelect,
+				widget.NewLabel("開始時刻"), startEntry,
+				widget.NewLabel("終了時刻"), endEntry,
+				widget.NewLabel("名前"), labelEntry,
+			),
+			addEntryBtn,
+			widget.NewSeparator(),
+			view.timetableList,
+		),
+	)
+
+	// 例外日（休校日・行事など）の登録
+	dateEntry := widget.NewEntry()
+	dateEntry.SetPlaceHolder("2006-01-02")
+	dateEntry.Validator = func(value string) error {
+		_, err := time.Parse("2006-01-02", value)
+		return err
+	}
+	noteEntry := widget.NewEntry()
+	noteEntry.SetPlaceHolder("例: 運動会の振替休日")
+
+	addExceptionBtn := widget.NewButtonWithIcon("追加", theme.ContentAddIcon(), func() {
+		if dateEntry.Validate() != nil {
+			m.ShowErrorDialog("入力エラー", "日付は「2006-01-02」の形式で入力してください。")
+			return
+		}
+
+		exception := &database.ScheduleException{
+			UserID: m.currentUser.ID,
+			Date:   dateEntry.Text,
+			Note:   strings.TrimSpace(noteEntry.Text),
+		}
+		if err := m.db.UpsertScheduleException(exception); err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("例外日の保存に失敗しました: %v", err))
+			return
+		}
+
+		dateEntry.SetText("")
+		noteEntry.SetText("")
+		m.refreshScheduleView()
+	})
+
+	view.exceptionsCard = widget.NewCard("休みの日・例外日", "この日は時間割を使わずに計画します",
+		container.NewVBox(
+			container.NewGridWithColumns(2,
+				widget.NewLabel("日付"), dateEntry,
+				widget.NewLabel("メモ"), noteEntry,
+			),
+			addExceptionBtn,
+			widget.NewSeparator(),
+			view.exceptionList,
+		),
+	)
+
+	view.schoolCard = widget.NewCard("学校の年間予定", "長期休み・テスト期間（月-日で入力）", m.createSchoolCalendarEditor())
+
+	view.container = container.NewVBox(
+		view.planCard,
+		view.timetableCard,
+		view.exceptionsCard,
+	)
+	// 学校の年間予定は設定ファイルに保存するため、制限モードでは表示しない
+	if !m.config.Kiosk {
+		view.container.Add(view.schoolCard)
+	}
+
+	return view
+}
+
+// refreshScheduleView 時間割・例外日・学習プランの表示を更新
+func (m *MainApp) refreshScheduleView() {
+	view := m.scheduleView
+	if view == nil {
+		return
+	}
+
+	entries, err := m.db.GetTimetableEntries(m.currentUser.ID)
+	if err != nil {
+		slog.Error("時間割取得エラー", "error", err)
+	}
+	view.timetableList.RemoveAll()
+	if len(entries) == 0 {
+		view.timetableList.Add(widget.NewLabel("まだ予定が登録されていません。"))
+	}
+	for _, entry := range entries {
+		deleteBtn := m.newDeleteButton(func() {
+			if err := m.db.DeleteTimetableEntry(m.currentUser.ID, entry.ID); err != nil {
+				slog.Error("時間割削除エラー", "error", err)
+			}
+			m.refreshScheduleView()
+		})
+		view.timetableList.Add(container.NewBorder(nil, nil, nil, deleteBtn,
+			widget.NewLabel(fmt.Sprintf("%s曜 %s〜%s %s", weekdayNames[entry.Weekday], entry.StartTime, entry.EndTime, entry.Label))))
+	}
+	view.timetableList.Refresh()
+
+	exceptions, err := m.db.GetScheduleExceptions(m.currentUser.ID)
+	if err != nil {
+		slog.Error("例外日取得エラー", "error", err)
+	}
+	view.exceptionList.RemoveAll()
+	for _, exception := range exceptions {
+		deleteBtn := m.newDeleteButton(func() {
+			if err := m.db.DeleteScheduleException(m.currentUser.ID, exception.Date); err != nil {
+				slog.Error("例外日削除エラー", "error", err)
+			}
+			m.refreshScheduleView()
+		})
+		view.exceptionList.Add(container.NewBorder(nil, nil, nil, deleteBtn,
+			widget.NewLabel(fmt.Sprintf("%s %s", exception.Date, exception.Note))))
+	}
+	view.exceptionList.Refresh()
+
+	plans := schedule.ProposeWeek(entries, exceptions, m.calendar, time.Now(), m.config.Learning.StudyGoalTime, m.config.OrderedSubjects())
+	view.planCard.SetContent(widget.NewRichTextFromMarkdown(formatWeekPlan(plans)))
+}
+
+// newDeleteButton 削除ボタンを作成（制限モードではデータを削除させないためnil）
+func (m *MainApp) newDeleteButton(onDelete func()) fyne.CanvasObject {
+	if m.config.Kiosk {
+		return nil
+	}
+	return widget.NewButtonWithIcon("", theme.DeleteIcon(), onDelete)
+}
+
+// formatWeekPlan 1週間の学習プランをマークダウンに変換
+func formatWeekPlan(plans []*schedule.DayPlan) string {
+	var b strings.Builder
+	for _, plan := range plans {
+		fmt.Fprintf(&b, "**%s（%s）**", plan.Date.Format("01/02"), weekdayNames[plan.Date.Weekday()])
+		if plan.IsHoliday {
+			fmt.Fprintf(&b, "　🏖️ %s", plan.HolidayNote)
+		}
+		if plan.ExamNote != "" {
+			fmt.Fprintf(&b, "　📝 %s", plan.ExamNote)
+		}
+		b.WriteString("\n\n")
+
+		if len(plan.Slots) == 0 {
+			b.WriteString("- 学習できる時間がありません\n\n")
+			continue
+		}
+		for _, slot := range plan.Slots {
+			fmt.Fprintf(&b, "- %s〜%s %s\n", slot.Start.Format("15:04"), slot.End.Format("15:04"), slot.Subject)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// createSchoolCalendarEditor 長期休み・テスト期間の編集欄を作成
+func (m *MainApp) createSchoolCalendarEditor() fyne.CanvasObject {
+	periods := container.NewVBox()
+
+	var refresh func()
+	refresh = func() {
+		periods.RemoveAll()
+		periods.Add(widget.NewLabel("長期休み:"))
+		for i := range m.config.School.Breaks {
+			periods.Add(m.periodRow(&m.config.School.Breaks[i], func() {
+				m.config.School.Breaks = slices.Delete(m.config.School.Breaks, i, i+1)
+				m.saveSchoolCalendar()
+				refresh()
+			}))
+		}
+		periods.Add(widget.NewLabel("テスト期間:"))
+		for i := range m.config.School.ExamWeeks {
+			periods.Add(m.periodRow(&m.config.School.ExamWeeks[i], func() {
+				m.config.School.ExamWeeks = slices.Delete(m.config.School.ExamWeeks, i, i+1)
+				m.saveSchoolCalendar()
+				refresh()
+			}))
+		}
+		periods.Refresh()
+	}
+	refresh()
+
+	addBreakBtn := widget.NewButtonWithIcon("長期休みを追加", theme.ContentAddIcon(), func() {
+		m.config.School.Breaks = append(m.config.School.Breaks, config.Period{Name: "休み", Start: "01-01", End: "01-01"})
+		m.saveSchoolCalendar()
+		refresh()
+	})
+	addExamBtn := widget.NewButtonWithIcon("テスト期間を追加", theme.ContentAddIcon(), func() {
+		m.config.School.ExamWeeks = append(m.config.School.ExamWeeks, config.Period{Name: "テスト", Start: "01-01", End: "01-01"})
+		m.saveSchoolCalendar()
+		refresh()
+	})
+	resetBtn := widget.NewButton("一般的な予定に戻す", func() {
+		m.config.School = config.DefaultSchool()
+		m.saveSchoolCalendar()
+		refresh()
+	})
+
+	return container.NewVBox(
+		periods,
+		container.NewGridWithColumns(3, addBreakBtn, addExamBtn, resetBtn),
+	)
+}
+
+// periodRow 期間1件分の編集行を作成（入力が正しい場合のみ保存）
+func (m *MainApp) periodRow(period *config.Period, onDelete func()) fyne.CanvasObject {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(period.Name)
+	startEntry := widget.NewEntry()
+	startEntry.SetText(period.Start)
+	endEntry := widget.NewEntry()
+	endEntry.SetText(period.End)
+
+	validateDay := func(value string) error {
+		_, err := time.Parse("01-02", value)
+		return err
+	}
+	startEntry.Validator = validateDay
+	endEntry.Validator = validateDay
+
+	onChanged := func(string) {
+		if startEntry.Validate() != nil || endEntry.Validate() != nil {
+			return
+		}
+		period.Name = strings.TrimSpace(nameEntry.Text)
+		period.Start = startEntry.Text
+		period.End = endEntry.Text
+		m.saveSchoolCalendar()
+	}
+	nameEntry.OnChanged = onChanged
+	startEntry.OnChanged = onChanged
+	endEntry.OnChanged = onChanged
+
+	deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), onDelete)
+	return container.NewBorder(nil, nil, nil, deleteBtn,
+		container.NewGridWithColumns(3, nameEntry, startEntry, endEntry))
+}
+
+// saveSchoolCalendar 学校の年間予定を保存して学習プランに反映
+func (m *MainApp) saveSchoolCalendar() {
+	if err := config.Save(m.config); err != nil {
+		slog.Error("設定保存エラー", "error", err)
+	}
+	m.refreshScheduleView()
+}
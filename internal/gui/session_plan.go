@@ -0,0 +1,155 @@
+package gui
+
+import (
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+)
+
+// 出題の計画で選べる単元
+const (
+	planTopicRecommended = "おすすめの単元"
+	planTopicAll         = "学年の学習範囲全体"
+)
+
+// planProblemCounts 出題の計画・今日の目標で選べる問題数
+var planProblemCounts = []string{"5", "10", "15", "20", "30"}
+
+// planSession 科目の出題の計画を立てる（記録を読めなければ設定の難易度だけで立てる。問題数は今日の目標）
+func (m *MainApp) planSession(subject string) *progress.SessionPlan {
+	difficulty := m.config.DifficultyFor(subject)
+	plan, err := m.progressManager.PlanSession(m.currentUser.ID, subject, difficulty, time.Now())
+	if err != nil {
+		slog.Error("出題の計画の作成エラー", "error", err)
+		plan = progress.BuildSessionPlan(subject, difficulty, nil, nil)
+	}
+	plan.Problems = m.sessionGoal()
+	return plan
+}
+
+// beginStudySession 科目を選んだときに出題の計画を見せてから学習セッションを開始
+func (m *MainApp) beginStudySession(subject string) {
+	s := m.studyView
+	plan := m.planSession(subject)
+	if !m.config.Learning.SessionPreview {
+		s.plan = plan
+		s.startStudySession(subject, m)
+		return
+	}
+	m.showSessionPlan(plan, func() {
+		s.plan = plan
+		m.setSessionGoal(plan.Problems) // 計画で変えた問題数を今日の目標にする
+		s.startStudySession(subject, m)
+	}, func() {
+		// やめたときは学習中の科目に選択を戻す（変更イベントは起こさない）
+		s.subjectSelect.Selected = ""
+		if s.currentSession != nil {
+			s.subjectSelect.Selected = s.currentSession.Subject
+		}
+		s.subjectSelect.Refresh()
+		s.showTopicOptions(m, s.subjectSelect.Selected, "")
+	})
+}
+
+// showSessionPlan 出題の計画（単元・難易度の幅・予定の時間）とその理由を表示し、難易度・問題数・単元を調整できるようにする
+func (m *MainApp) showSessionPlan(plan *progress.SessionPlan, onStart, onCancel func()) {
+	recommended := slices.Clone(plan.Topics)
+	minDifficulty, maxDifficulty := plan.MinDifficulty, plan.MaxDifficulty
+
+	summary := widget.NewLabel(plan.Summary())
+	summary.Wrapping = fyne.TextWrapWord
+	refresh := func() { summary.SetText(plan.Summary()) }
+
+	difficultySelect := widget.NewSelect([]string{"1", "2", "3", "4", "5"}, func(value string) {
+		difficulty, _ := strconv.Atoi(value)
+		plan.MinDifficulty, plan.MaxDifficulty = minDifficulty, maxDifficulty
+		plan.SetDifficulty(difficulty)
+		refresh()
+	})
+	difficultySelect.SetSelected(strconv.Itoa(plan.Difficulty))
+
+	problemsSelect := widget.NewSelect(planProblemCounts, func(value string) {
+		plan.Problems, _ = strconv.Atoi(value)
+		refresh()
+	})
+	problemsSelect.SetSelected(strconv.Itoa(plan.Problems))
+
+	topicOptions := []string{planTopicAll}
+	if len(recommended) > 0 {
+		topicOptions = append([]string{planTopicRecommended}, topicOptions...)
+	}
+	topicOptions = append(topicOptions, m.aiEngine.CurriculumTopics(m.currentUser.Grade, plan.Subject)...)
+	topicSelect := widget.NewSelect(topicOptions, func(value string) {
+		switch value {
+		case planTopicRecommended:
+			plan.Topics = slices.Clone(recommended)
+		case planTopicAll:
+			plan.Topics = nil
+		default:
+			plan.Topics = []string{value}
+		}
+		refresh()
+	})
+	topicSelect.SetSelected(topicOptions[0])
+
+	reasons := widget.NewLabel("・" + strings.Join(plan.Reasons, "\n・"))
+	reasons.Wrapping = fyne.TextWrapWord
+
+	previewCheck := widget.NewCheck("学習を始める前にこの画面を表示する", func(checked bool) {
+		m.config.Learning.SessionPreview = checked
+		m.saveConfig()
+	})
+	previewCheck.SetChecked(m.config.Learning.SessionPreview)
+
+	content := container.NewVBox(
+		summary,
+		widget.NewForm(
+			widget.NewFormItem("最初の難易度", difficultySelect),
+			widget.NewFormItem("今日の目標（問）", problemsSelect),
+			widget.NewFormItem("単元", topicSelect),
+		),
+		widget.NewCard("この計画にした理由", "", reasons),
+		previewCheck,
+	)
+	confirm := dialog.NewCustomConfirm("🗺 今日の学習の計画", "この内容で始める", "やめる", content, func(start bool) {
+		if start {
+			onStart()
+		} else {
+			onCancel()
+		}
+	}, m.window)
+	confirm.Resize(fyne.NewSize(520, 560))
+	confirm.Show()
+}
+
+// nextStudyContext 出題の計画から次の問題の学習コンテキストを作る（出題する単元をs.topicに記録する）
+func (s *StudyView) nextStudyContext(mainApp *MainApp) ai.StudyContext {
+	s.topic = s.plan.TopicFor(len(s.sessionProblems))
+	return ai.StudyContext{
+		UserID:     mainApp.currentUser.ID,
+		Subject:    s.currentSession.Subject,
+		Grade:      mainApp.currentUser.Grade,
+		Difficulty: s.plan.Difficulty,
+		Emotion:    "neutral",
+		Topic:      s.topic,
+	}
+}
+
+// checkPlanFinished 目標の問題数を解き終えたら、ここまでのまとめを見せる（そのまま続けて解ける）
+func (s *StudyView) checkPlanFinished(mainApp *MainApp) {
+	s.updateGoalProgress()
+	if s.currentSession.TotalProblems != s.plan.Problems {
+		return
+	}
+	mainApp.showSessionSummary(s.currentSession.ID, s.plan.Problems)
+}
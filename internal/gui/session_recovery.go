@@ -0,0 +1,70 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2/dialog"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// recoverSessions 起動時に、アプリが途中で落ちて終了していない学習セッションを解いたところまでで終了する
+// 今日の学習で問題を解いていれば「前回の続きから再開」できるようにする
+func (m *MainApp) recoverSessions() {
+	sessions, err := m.progressManager.RecoverOpenSessions(m.currentUser.ID)
+	if err != nil {
+		slog.Error("途中で終わった学習セッションの復元エラー", "error", err)
+		return
+	}
+	if len(sessions) == 0 {
+		return
+	}
+	slog.Info("🔄 途中で終わった学習セッションを終了", "count", len(sessions))
+
+	now := time.Now()
+	var last *database.StudySession
+	for i := range sessions {
+		session := &sessions[i]
+		if session.SessionType == database.SessionTypeApp && session.TotalProblems > 0 && sameDay(session.StartTime, now) {
+			last = session
+		}
+	}
+	if last == nil || last.TotalProblems >= m.sessionGoal() {
+		return // 今日の目標を解き終えていれば再開をすすめない
+	}
+
+	confirm := dialog.NewConfirm("🔄 前回の学習",
+		fmt.Sprintf("前回の%sの学習が途中で終わっていました（%d問中%d問正解）。\n解いたところまでは記録してあります。続きから学習しますか？",
+			last.Subject, last.TotalProblems, last.CorrectAnswers),
+		func(resume bool) {
+			if resume {
+				m.resumeStudySession(last)
+			}
+		}, m.window)
+	confirm.SetConfirmText("前回の続きから再開")
+	confirm.SetDismissText("閉じる")
+	confirm.Show()
+}
+
+// resumeStudySession 途中で終わった学習セッションを、解いた問題数・正解数を引き継いで再開する
+func (m *MainApp) resumeStudySession(session *database.StudySession) {
+	s := m.studyView
+	if s == nil || s.isGenerating || s.currentSession != nil {
+		return // すでに学習を始めていれば再開しない
+	}
+	if err := m.progressManager.ResumeSession(session, time.Now()); err != nil {
+		slog.Error("学習セッションの再開エラー", "error", err)
+		m.ShowErrorDialog("エラー", fmt.Sprintf("前回の学習を再開できませんでした: %v", err))
+		return
+	}
+
+	m.content.Select(m.studyTab)
+	// 科目の選択を合わせる（変更イベントは起こさない）
+	s.subjectSelect.Selected = session.Subject
+	s.subjectSelect.Refresh()
+	s.showTopicOptions(m, session.Subject, "")
+	s.plan = m.planSession(session.Subject)
+	s.runStudySession(session, m)
+}
@@ -0,0 +1,190 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+)
+
+// newSessionGoalSelect 科目選択の下の、1回の学習で解く目標の問題数（「今日は10問」）を選ぶ欄
+func (m *MainApp) newSessionGoalSelect() *widget.Select {
+	goalSelect := widget.NewSelect(planProblemCounts, func(value string) {
+		if goal, err := strconv.Atoi(value); err == nil {
+			m.setSessionGoal(goal)
+		}
+	})
+	goalSelect.Selected = strconv.Itoa(m.sessionGoal())
+	return goalSelect
+}
+
+// sessionGoal 1回の学習の目標の問題数（決めていなければ出題の計画のおすすめの問題数）
+func (m *MainApp) sessionGoal() int {
+	if goal := m.config.Learning.SessionGoal; goal > 0 {
+		return goal
+	}
+	return progress.DefaultPlanProblems
+}
+
+// setSessionGoal 目標の問題数を保存し、学習中のセッションの計画と表示にも反映する
+func (m *MainApp) setSessionGoal(goal int) {
+	if m.config.Learning.SessionGoal != goal {
+		m.config.Learning.SessionGoal = goal
+		m.saveConfig()
+	}
+
+	s := m.studyView
+	if s == nil {
+		return
+	}
+	s.goalSelect.Selected = strconv.Itoa(goal)
+	s.goalSelect.Refresh()
+	if s.plan != nil {
+		s.plan.Problems = goal
+	}
+	s.updateGoalProgress()
+}
+
+// updateGoalProgress 目標の問題数までの進み具合を表示（例: 「🎯 3/10問」）
+func (s *StudyView) updateGoalProgress() {
+	if s.currentSession == nil || s.plan == nil {
+		s.goalLabel.SetText("")
+		return
+	}
+	s.goalLabel.SetText(fmt.Sprintf("🎯 %d/%d問", s.currentSession.TotalProblems, s.plan.Problems))
+}
+
+// showSessionSummary セッションのまとめ（正解率・学習時間・単元・獲得した経験値）を表示
+// 目標の問題数を解き終えたとき（goal > 0）は、学習を終えるか続けて解くかを選べる
+func (m *MainApp) showSessionSummary(sessionID string, goal int) {
+	summary, err := m.progressManager.GenerateSessionSummary(sessionID)
+	if err != nil {
+		slog.Error("セッションのまとめの作成エラー", "error", err)
+		m.ShowErrorDialog("エラー", fmt.Sprintf("学習のまとめを作れませんでした: %v", err))
+		return
+	}
+
+	text := widget.NewRichTextFromMarkdown(sessionSummaryText(summary, goal))
+	text.Wrapping = fyne.TextWrapWord
+	content := container.NewVScroll(text)
+
+	if goal <= 0 {
+		popup := dialog.NewCustom("📋 今回の学習のまとめ", "閉じる", content, m.window)
+		popup.Resize(fyne.NewSize(460, 480))
+		popup.Show()
+		return
+	}
+	confirm := dialog.NewCustomConfirm("📋 今回の学習のまとめ", "学習を終える", "続けて解く", content, func(end bool) {
+		if end {
+			m.studyView.endSession(m)
+		}
+	}, m.window)
+	confirm.Resize(fyne.NewSize(460, 480))
+	confirm.Show()
+}
+
+// sessionSummaryText セッションのまとめの文章（Markdown）
+func sessionSummaryText(summary *progress.SessionSummary, goal int) string {
+	var b strings.Builder
+	if goal > 0 {
+		fmt.Fprintf(&b, "## 🎉 今日の目標の%d問を解き終えました！\n\n", goal)
+	}
+	fmt.Fprintf(&b, "**%s** %d問中%d問正解（%.0f%%）\n\n", summary.Subject,
+		summary.ProblemsAttempted, summary.CorrectAnswers, summary.AccuracyRate*100)
+	fmt.Fprintf(&b, "- 学習時間: %d分%d秒\n", summary.Duration/60, summary.Duration%60)
+	if summary.ProblemsAttempted > 0 {
+		fmt.Fprintf(&b, "- 1問あたり: 平均%.0f秒\n", summary.AverageTime)
+	}
+	if summary.MaxCombo > 1 {
+		fmt.Fprintf(&b, "- 最大コンボ: %d問連続正解\n", summary.MaxCombo)
+	}
+	fmt.Fprintf(&b, "- ペットの経験値: +%d\n", summary.XPGained)
+
+	if len(summary.Topics) > 0 {
+		b.WriteString("\n### 解いた単元\n\n")
+		for _, topic := range summary.Topics {
+			fmt.Fprintf(&b, "- %s: %d問中%d問正解\n", topic.Topic, topic.Attempted, topic.Correct)
+		}
+	}
+	if len(summary.Improvements) > 0 {
+		b.WriteString("\n### 👍 よくできた単元\n\n- " + strings.Join(summary.Improvements, "\n- ") + "\n")
+	}
+	if len(summary.Challenges) > 0 {
+		b.WriteString("\n### 📝 もう一度復習したい単元\n\n- " + strings.Join(summary.Challenges, "\n- ") + "\n")
+	}
+	return b.String()
+}
+
+// endStudy 「終わる」ボタン: 学習セッションを終え、解いた問題があればまとめを表示
+func (s *StudyView) endStudy(mainApp *MainApp) {
+	session := s.currentSession
+	if session == nil {
+		return
+	}
+	if session.TotalProblems == 0 {
+		s.endSession(mainApp)
+		return
+	}
+	dialog.ShowConfirm("⏹ 学習を終える", fmt.Sprintf("%sの学習を終えて、今回のまとめを見ますか？", session.Subject), func(end bool) {
+		if !end || s.currentSession != session {
+			return
+		}
+		s.endSession(mainApp)
+		mainApp.showSessionSummary(session.ID, 0)
+	}, mainApp.window)
+}
+
+// endInactiveSession 操作のないまま時間がたった学習セッションを終える（操作のなかった時間は学習時間に含めない）
+func (s *StudyView) endInactiveSession(mainApp *MainApp, now time.Time) {
+	f := s.focus
+	f.pausedSession += now.Sub(f.lastActivity)
+	slog.Info("⏹ 操作がないため学習セッションを終了", "idle", now.Sub(f.lastActivity).Round(time.Second))
+	s.endSession(mainApp)
+	mainApp.ShowInfoDialog("⏹ 学習を終えました", fmt.Sprintf(
+		"%d分間操作がなかったので、学習を終えました。操作のなかった時間は学習時間に含めていません。\nまた科目を選ぶと、新しく学習を始められます。",
+		int(inactivityTimeout.Minutes())))
+}
+
+// endSession 学習セッションを終え、科目を選ぶ前の画面に戻す（作成中の問題は取り消す）
+func (s *StudyView) endSession(mainApp *MainApp) {
+	if s.isGenerating {
+		s.generation++ // 作成中の問題は表示しない
+		if s.cancelGeneration != nil {
+			s.cancelGeneration()
+			s.cancelGeneration = nil
+		}
+		s.isGenerating = false
+		s.subjectSelect.Enable()
+	}
+	s.finishSession(mainApp)
+	s.currentSession = nil
+	s.currentProblem = nil
+	s.sessionProblems = nil
+	s.plan = nil
+
+	// 科目・単元の選択を戻す（変更イベントは起こさない）
+	s.subjectSelect.Selected = ""
+	s.subjectSelect.Refresh()
+	s.showTopicOptions(mainApp, "", "")
+	s.updateGoalProgress()
+	s.timerLabel.SetText("00:00")
+	s.progressBar.SetValue(0)
+	s.comboMeter.SetCombo(0)
+
+	s.problemCard.SetTitle("📚 おつかれさまでした")
+	s.problemCard.SetSubTitle("")
+	s.problemText.ParseMarkdown("**科目を選択すると、新しい学習を始められます**")
+	s.glossaryTerms.RemoveAll()
+	s.optionsContainer.RemoveAll()
+	s.feedbackCard.SetTitle("💭 フィードバック")
+	s.feedbackText.ParseMarkdown("解答後にフィードバックが表示されます")
+	s.feedbackCard.SetContent(s.feedbackText)
+}
@@ -0,0 +1,464 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+)
+
+// はじめての設定のステップ
+const (
+	setupStepOllama  = iota // Ollamaが動いているか
+	setupStepModel          // 使うモデルを選ぶ（なければダウンロード）
+	setupStepTest           // モデルが日本語で応答するか試す
+	setupStepProfile        // 学年とペット
+	setupStepCount
+)
+
+// setupTitles ステップの見出し
+var setupTitles = [setupStepCount]string{
+	"AI（Ollama）の確認",
+	"AIモデルの準備",
+	"AIモデルのテスト",
+	"学年とペット",
+}
+
+// petSpeciesLabels ペットの種類の表示名
+var petSpeciesLabels = map[string]string{
+	"cat":     "🐱 ねこ（ミケ）",
+	"dog":     "🐶 いぬ（ポチ）",
+	"dragon":  "🐲 ドラゴン（リュウ）",
+	"unicorn": "🦄 ユニコーン（ユニ）",
+}
+
+// petSpeciesOrder ペットの種類の表示順
+var petSpeciesOrder = []string{"cat", "dog", "dragon", "unicorn"}
+
+// ollamaCheckTimeout Ollamaが動いているかの確認の時間制限
+const ollamaCheckTimeout = 5 * time.Second
+
+// setupWizard はじめての設定の状態
+type setupWizard struct {
+	step       int
+	generation int // ステップを表示し直すたびに増やし、古い非同期の結果を捨てる
+
+	ollamaOK bool
+	version  string
+	checked  bool // Ollamaの確認が終わった
+
+	installed  []ai.ModelInfo
+	model      string // 使うモデル
+	cancelPull context.CancelFunc
+
+	testDone bool
+	testErr  error
+
+	grade   int
+	species string
+}
+
+// showSetupWizard はじめての設定を表示（終わると学習画面を作る）
+func (m *MainApp) showSetupWizard() {
+	w := &setupWizard{
+		model:   m.config.AI.Model,
+		grade:   m.config.UserGrade,
+		species: m.config.Learning.PetSpecies,
+	}
+	m.checkOllama(w)
+}
+
+// showSetupStep 今のステップの画面を表示
+func (m *MainApp) showSetupStep(w *setupWizard) {
+	w.generation++
+
+	var body fyne.CanvasObject
+	switch w.step {
+	case setupStepOllama:
+		body = m.setupOllamaStep(w)
+	case setupStepModel:
+		body = m.setupModelStep(w)
+	case setupStepTest:
+		body = m.setupTestStep(w)
+	default:
+		body = m.setupProfileStep(w)
+	}
+
+	backBtn := widget.NewButton("戻る", func() {
+		m.cancelSetupPull(w)
+		w.step = m.nextSetupStep(w, -1)
+		m.showSetupStep(w)
+	})
+	if w.step == setupStepOllama {
+		backBtn.Disable()
+	}
+
+	nextLabel := "次へ"
+	switch {
+	case w.step == setupStepProfile:
+		nextLabel = "はじめる"
+	case w.step == setupStepOllama && w.checked && !w.ollamaOK:
+		nextLabel = "AIを使わずに続ける"
+	}
+	nextBtn := widget.NewButton(nextLabel, func() {
+		m.cancelSetupPull(w)
+		if w.step == setupStepProfile {
+			m.finishSetup(w)
+			return
+		}
+		w.step = m.nextSetupStep(w, 1)
+		m.showSetupStep(w)
+	})
+	nextBtn.Importance = widget.HighImportance
+	switch w.step {
+	case setupStepOllama:
+		if !w.checked {
+			nextBtn.Disable()
+		}
+	case setupStepModel:
+		if !m.setupModelInstalled(w) {
+			nextBtn.Disable()
+		}
+	}
+
+	header := widget.NewLabelWithStyle(
+		fmt.Sprintf("ステップ %d / %d: %s", w.step+1, setupStepCount, setupTitles[w.step]),
+		fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	card := widget.NewCard("🎓 StudyBuddy AI へようこそ！", "はじめに、AIと学習の設定をしましょう",
+		container.NewBorder(header, container.NewHBox(backBtn, layout.NewSpacer(), nextBtn), nil, nil, body))
+
+	m.window.SetContent(container.NewPadded(card))
+}
+
+// nextSetupStep 次（directionが-1なら前）のステップ。Ollamaが使えなければモデルのステップを飛ばす
+func (m *MainApp) nextSetupStep(w *setupWizard, direction int) int {
+	step := w.step + direction
+	for !w.ollamaOK && (step == setupStepModel || step == setupStepTest) {
+		step += direction
+	}
+	return min(max(step, setupStepOllama), setupStepProfile)
+}
+
+// setupOllamaStep Ollamaが動いているかを表示
+func (m *MainApp) setupOllamaStep(w *setupWizard) fyne.CanvasObject {
+	status := widget.NewLabel("Ollamaが動いているか確認しています...")
+	status.Wrapping = fyne.TextWrapWord
+	retryBtn := widget.NewButton("🔄 もう一度確認", func() { m.checkOllama(w) })
+
+	if !w.checked {
+		return container.NewVBox(status, widget.NewProgressBarInfinite())
+	}
+	if w.ollamaOK {
+		status.SetText(fmt.Sprintf("✅ Ollama（バージョン %s）が動いています。\n%s", w.version, m.config.AI.OllamaURL))
+		return container.NewVBox(status)
+	}
+
+	status.SetText(fmt.Sprintf("⚠️ Ollamaに接続できません（%s）。", m.config.AI.OllamaURL))
+	help := widget.NewRichTextFromMarkdown(`
+StudyBuddy AIは、パソコンの中で動くAI（Ollama）を使って問題や解説を作ります。
+
+1. https://ollama.com から Ollama をダウンロードしてインストール
+2. Ollama を起動（ターミナルなら ` + "`ollama serve`" + `）
+3. 「もう一度確認」を押す
+
+AIを使わなくても、用意してある問題で学習できます（あとで設定からAIを使えるようにできます）。
+`)
+	help.Wrapping = fyne.TextWrapWord
+	return container.NewVBox(status, help, retryBtn)
+}
+
+// checkOllama Ollamaが動いているか確かめて、最初のステップを表示し直す
+func (m *MainApp) checkOllama(w *setupWizard) {
+	w.checked = false
+	m.showSetupStep(w)
+	generation := w.generation
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), ollamaCheckTimeout)
+		defer cancel()
+		version, err := m.aiEngine.OllamaVersion(ctx)
+		if err != nil {
+			slog.Error("Ollama確認エラー", "error", err)
+		}
+
+		fyne.Do(func() {
+			if w.generation != generation {
+				return
+			}
+			w.checked = true
+			w.ollamaOK = err == nil
+			w.version = version
+			m.showSetupStep(w)
+		})
+	}()
+}
+
+// setupModelStep 使うモデルを選ぶ画面（インストールしていなければダウンロードできる）
+func (m *MainApp) setupModelStep(w *setupWizard) fyne.CanvasObject {
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	progress := widget.NewProgressBar()
+	progress.Hide()
+
+	// おすすめのモデルのあとに、ほかのインストール済みのモデルを並べる
+	var names, labels []string
+	for _, model := range ai.RecommendedModels {
+		label := fmt.Sprintf("%s: %s", model.Name, model.Description)
+		if m.setupInstalled(w, model.Name) {
+			label += "（インストール済み）"
+		}
+		names = append(names, model.Name)
+		labels = append(labels, label)
+	}
+	for _, model := range w.installed {
+		if !slices.ContainsFunc(names, func(name string) bool { return ai.SameModel(name, model.Name) }) {
+			names = append(names, model.Name)
+			labels = append(labels, fmt.Sprintf("%s（インストール済み・%s）", model.Name, formatModelSize(model.Size)))
+		}
+	}
+
+	downloadBtn := widget.NewButton("⬇ このモデルをダウンロード", nil)
+	downloadBtn.Hide()
+	choices := widget.NewRadioGroup(labels, nil)
+	for i, name := range names {
+		if ai.SameModel(name, w.model) {
+			choices.Selected = labels[i]
+		}
+	}
+	choices.OnChanged = func(label string) {
+		i := slices.Index(labels, label)
+		if i < 0 {
+			return
+		}
+		m.cancelSetupPull(w)
+		w.model = names[i]
+		w.testDone = false
+		m.showSetupStep(w)
+	}
+	if !m.setupModelInstalled(w) {
+		status.SetText(fmt.Sprintf("%s はまだインストールされていません。ダウンロードには数分〜数十分かかります。", w.model))
+		downloadBtn.OnTapped = func() { m.pullSetupModel(w, downloadBtn, status, progress) }
+		downloadBtn.Show()
+	}
+
+	generation := w.generation
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), modelListTimeout)
+		defer cancel()
+		models, err := m.aiEngine.ListModels(ctx)
+		if err != nil {
+			slog.Error("モデル一覧取得エラー", "error", err)
+			return
+		}
+
+		fyne.Do(func() {
+			// ダウンロード中は表示し直さない（途中経過の表示が消えるため）
+			if w.generation != generation || w.cancelPull != nil || slices.Equal(modelNames(models), modelNames(w.installed)) {
+				return
+			}
+			w.installed = models
+			// 選んでいたモデルがなければ、インストール済みのおすすめのモデルを選ぶ
+			if !m.setupModelInstalled(w) {
+				for _, model := range ai.RecommendedModels {
+					if m.setupInstalled(w, model.Name) {
+						w.model = model.Name
+						break
+					}
+				}
+			}
+			m.showSetupStep(w)
+		})
+	}()
+
+	return container.NewVBox(
+		widget.NewLabel("問題や解説を作るAIモデルを選んでください。"),
+		choices,
+		downloadBtn,
+		status,
+		progress,
+	)
+}
+
+// pullSetupModel 選んだモデルをダウンロード（終わるとモデルの一覧を読み込み直す）
+func (m *MainApp) pullSetupModel(w *setupWizard, downloadBtn *widget.Button, status *widget.Label, progress *widget.ProgressBar) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancelPull = cancel
+	model := w.model
+	generation := w.generation
+	downloadBtn.Disable()
+	status.SetText(fmt.Sprintf("%s をダウンロードしています...", model))
+	progress.SetValue(0)
+	progress.Show()
+
+	go func() {
+		var last time.Time
+		err := m.aiEngine.PullModel(ctx, model, func(p ai.PullProgress) {
+			if now := time.Now(); now.Sub(last) >= pullProgressInterval {
+				last = now
+				fyne.Do(func() {
+					if w.generation == generation {
+						progress.SetValue(p.Fraction())
+						status.SetText(pullStatusText(model, p))
+					}
+				})
+			}
+		})
+		canceled := ctx.Err() != nil
+		cancel()
+
+		fyne.Do(func() {
+			if w.generation != generation {
+				return // ほかのステップに移った（ダウンロードは中止済み）
+			}
+			w.cancelPull = nil
+			if canceled {
+				return
+			}
+			if err != nil {
+				slog.Error("モデルのダウンロードエラー", "error", err)
+				progress.Hide()
+				downloadBtn.Enable()
+				status.SetText(fmt.Sprintf("%s をダウンロードできませんでした: %v", model, err))
+				return
+			}
+			// インストール済みになったので、一覧を読み込み直す
+			w.installed = append(w.installed, ai.ModelInfo{Name: model})
+			m.showSetupStep(w)
+		})
+	}()
+}
+
+// cancelSetupPull ダウンロード中なら中止する
+func (m *MainApp) cancelSetupPull(w *setupWizard) {
+	if w.cancelPull != nil {
+		w.cancelPull()
+		w.cancelPull = nil
+	}
+}
+
+// setupInstalled モデルがインストール済みか
+func (m *MainApp) setupInstalled(w *setupWizard, model string) bool {
+	return slices.ContainsFunc(w.installed, func(info ai.ModelInfo) bool { return ai.SameModel(info.Name, model) })
+}
+
+// setupModelInstalled 選んだモデルがインストール済みか
+func (m *MainApp) setupModelInstalled(w *setupWizard) bool {
+	return m.setupInstalled(w, w.model)
+}
+
+// modelNames モデル名の一覧
+func modelNames(models []ai.ModelInfo) []string {
+	names := make([]string, len(models))
+	for i, model := range models {
+		names[i] = model.Name
+	}
+	return names
+}
+
+// setupTestStep 選んだモデルが日本語で応答するか試す画面
+func (m *MainApp) setupTestStep(w *setupWizard) fyne.CanvasObject {
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	retryBtn := widget.NewButton("🔄 もう一度試す", func() {
+		w.testDone = false
+		m.showSetupStep(w)
+	})
+
+	if w.testDone {
+		if w.testErr != nil {
+			status.SetText(fmt.Sprintf("⚠️ %s から応答がありませんでした: %v\n「戻る」でほかのモデルを選ぶか、このまま続けることもできます（AIを使えないときは用意してある問題で学習します）。", w.model, w.testErr))
+			return container.NewVBox(status, retryBtn)
+		}
+		status.SetText(fmt.Sprintf("✅ %s が日本語で応答しました。", w.model))
+		return container.NewVBox(status)
+	}
+
+	status.SetText(fmt.Sprintf("%s に話しかけています...（初めて使うモデルは読み込みに数分かかることがあります）", w.model))
+	m.aiEngine.SetModel(w.model)
+	generation := w.generation
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), modelTestTimeout)
+		defer cancel()
+		err := m.aiEngine.TestModel(ctx)
+
+		fyne.Do(func() {
+			if w.generation != generation {
+				return
+			}
+			w.testDone = true
+			w.testErr = err
+			m.showSetupStep(w)
+		})
+	}()
+	return container.NewVBox(status, widget.NewProgressBarInfinite())
+}
+
+// setupProfileStep 学年とペットを選ぶ画面
+func (m *MainApp) setupProfileStep(w *setupWizard) fyne.CanvasObject {
+	gradeLabels := []string{"中学1年生", "中学2年生", "中学3年生"}
+	gradeSelect := widget.NewRadioGroup(gradeLabels, func(label string) {
+		if i := slices.Index(gradeLabels, label); i >= 0 {
+			w.grade = i + 1
+		}
+	})
+	gradeSelect.Horizontal = true
+	gradeSelect.SetSelected(gradeLabels[min(max(w.grade, 1), 3)-1])
+
+	content := container.NewVBox(widget.NewLabel("何年生ですか？"), gradeSelect)
+	if !m.config.Learning.PetEnabled {
+		return content
+	}
+
+	preview := NewPetWidget(w.species, "basic")
+	speciesLabels := make([]string, len(petSpeciesOrder))
+	for i, species := range petSpeciesOrder {
+		speciesLabels[i] = petSpeciesLabels[species]
+	}
+	speciesSelect := widget.NewRadioGroup(speciesLabels, func(label string) {
+		if i := slices.Index(speciesLabels, label); i >= 0 {
+			w.species = petSpeciesOrder[i]
+			preview.SetPet(w.species, "basic")
+		}
+	})
+	if i := slices.Index(petSpeciesOrder, w.species); i >= 0 {
+		speciesSelect.SetSelected(speciesLabels[i])
+	}
+
+	content.Add(widget.NewSeparator())
+	content.Add(widget.NewLabel("いっしょに学習するペットを選んでください。正解するとペットが成長します。"))
+	content.Add(container.NewBorder(nil, nil, nil,
+		container.NewGridWrap(fyne.NewSize(100, 100), preview), speciesSelect))
+	return content
+}
+
+// finishSetup 選んだ設定を保存して学習画面を作る（アプリの再起動はいらない）
+func (m *MainApp) finishSetup(w *setupWizard) {
+	m.config.FirstRun = false
+	m.config.UserGrade = w.grade
+	m.config.SetPetSpecies(w.species)
+	if w.ollamaOK && m.setupModelInstalled(w) {
+		m.config.UpdateAIModel(w.model)
+	}
+	m.aiEngine.SetModel(m.config.AI.Model)
+	m.saveConfig()
+
+	// すでにプロフィールがあれば学年だけ合わせる（ペットは育てたものをそのまま使う）
+	if user, err := m.db.GetUser(defaultUserID); err == nil && user.Grade != w.grade {
+		if err := m.db.UpdateUserGrade(user.ID, w.grade); err != nil {
+			slog.Error("学年更新エラー", "error", err)
+		}
+	}
+
+	m.initializeUser(defaultUserID)
+	m.startAPIServer()
+	m.createUI()
+	slog.Info("StudyBuddy AI 初期設定完了", "grade", w.grade)
+}
@@ -0,0 +1,289 @@
+package gui
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// similarMistakeLimit 「似た間違い」に表示する最大件数
+const similarMistakeLimit = 5
+
+// 問題生成で参考にする最近の間違い
+const (
+	mistakeRetrievalDays = 30 // 期間（日数）
+	mistakeRetrievalScan = 30 // 探す最大件数（新しい順）
+)
+
+// bankDuplicateScanLimit 問題バンクに保存するとき、同じ問題がないか調べる最大件数（新しい順）
+const bankDuplicateScanLimit = 200
+
+// showSimilarMistakes 過去の間違いから、埋め込みベクトルが似ている問題を探して表示
+func (m *MainApp) showSimilarMistakes(mistake database.Mistake, btn *widget.Button) {
+	btn.Disable()
+	btn.SetText("🔍 探しています...")
+	m.goSafe("似た間違いの検索", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		similar, err := m.findSimilarMistakes(ctx, mistake)
+		fyne.Do(func() {
+			btn.Enable()
+			btn.SetText("🔍 似た間違い")
+			if err != nil {
+				slog.Error("似た間違いの検索エラー", "error", err)
+				m.ShowErrorDialog("似た間違い", fmt.Sprintf("似た問題を探せませんでした: %v", err))
+				return
+			}
+			if len(similar) == 0 {
+				m.ShowInfoDialog("似た間違い", "この問題に似た間違いは見つかりませんでした。")
+				return
+			}
+
+			list := container.NewVBox()
+			for _, other := range similar {
+				list.Add(m.createMistakeCard(other))
+			}
+			popup := dialog.NewCustom("🔍 似た間違い", "閉じる", container.NewVScroll(list), m.window)
+			popup.Resize(fyne.NewSize(560, 480))
+			popup.Show()
+		})
+	}, func() {
+		btn.Enable()
+		btn.SetText("🔍 似た間違い")
+	})
+}
+
+// findSimilarMistakes 過去の間違いから、埋め込みベクトルが似ている問題を似ている順に探す
+func (m *MainApp) findSimilarMistakes(ctx context.Context, mistake database.Mistake) ([]database.Mistake, error) {
+	query, err := m.aiEngine.Embed(ctx, mistake.ProblemContent)
+	if err != nil {
+		return nil, err
+	}
+	mistakes, err := m.db.GetMistakes(m.currentUser.ID, database.MistakeFilter{}, mistakeNotebookLimit)
+	if err != nil {
+		return nil, fmt.Errorf("間違えた問題取得エラー: %w", err)
+	}
+
+	var candidates []database.Mistake
+	var embeddings [][]float64
+	for _, other := range mistakes {
+		if other.ID == mistake.ID || other.ProblemContent == mistake.ProblemContent || other.ProblemContent == "" {
+			continue
+		}
+		embedding, err := m.aiEngine.Embed(ctx, other.ProblemContent)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, other)
+		embeddings = append(embeddings, embedding)
+	}
+
+	var similar []database.Mistake
+	for _, i := range ai.NearestEmbeddings(query, embeddings, ai.SimilarThreshold, similarMistakeLimit) {
+		similar = append(similar, candidates[i])
+	}
+	return similar, nil
+}
+
+// showMistakeGroups 間違えた問題を似た問題ごとにまとめて表示（AIに接続できないときは単元ごと）
+func (m *MainApp) showMistakeGroups(mistakes []database.Mistake, generation int) {
+	view := m.mistakeView
+	view.list.Add(widget.NewLabel("🧩 似た問題ごとにまとめています..."))
+
+	m.goSafe("間違えた問題のまとめ", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		groups, err := m.clusterMistakes(ctx, mistakes)
+		note := ""
+		if err != nil {
+			slog.Error("間違えた問題のまとめエラー", "error", err)
+			groups = groupMistakesByType(mistakes)
+			note = "AIに接続できないため、単元ごとにまとめました。"
+		}
+
+		fyne.Do(func() {
+			if view.generation != generation {
+				return // 絞り込みが変わった
+			}
+			view.list.RemoveAll()
+			if note != "" {
+				view.list.Add(widget.NewLabel(note))
+			}
+			for _, group := range groups {
+				header := widget.NewLabelWithStyle(fmt.Sprintf("🧩 %s（%d問）", mistakeGroupLabel(group), len(group)),
+					fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+				view.list.Add(header)
+				for _, mistake := range group {
+					view.list.Add(m.createMistakeCard(mistake))
+				}
+			}
+		})
+	}, nil)
+}
+
+// clusterMistakes 埋め込みベクトルで、間違えた問題を似た問題ごとにまとめる（問題の多いまとまりから順）
+func (m *MainApp) clusterMistakes(ctx context.Context, mistakes []database.Mistake) ([][]database.Mistake, error) {
+	embeddings := make([][]float64, len(mistakes))
+	for i, mistake := range mistakes {
+		// 問題文を記録していない古い結果は、タイトルと正解で比べる
+		embedding, err := m.aiEngine.Embed(ctx, cmp.Or(mistake.ProblemContent, mistake.ProblemTitle+" "+mistake.CorrectAnswer))
+		if err != nil {
+			return nil, err
+		}
+		embeddings[i] = embedding
+	}
+
+	var groups [][]database.Mistake
+	for _, cluster := range ai.ClusterEmbeddings(embeddings, ai.SimilarThreshold) {
+		group := make([]database.Mistake, 0, len(cluster))
+		for _, i := range cluster {
+			group = append(group, mistakes[i])
+		}
+		groups = append(groups, group)
+	}
+	sortMistakeGroups(groups)
+	return groups, nil
+}
+
+// groupMistakesByType 間違えた問題を単元ごとにまとめる（問題の多いまとまりから順）
+func groupMistakesByType(mistakes []database.Mistake) [][]database.Mistake {
+	index := make(map[string]int)
+	var groups [][]database.Mistake
+	for _, mistake := range mistakes {
+		i, exists := index[mistake.ProblemType]
+		if !exists {
+			i = len(groups)
+			index[mistake.ProblemType] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], mistake)
+	}
+	sortMistakeGroups(groups)
+	return groups
+}
+
+// sortMistakeGroups まとまりを問題の多い順に並べる（同じ数なら元の順）
+func sortMistakeGroups(groups [][]database.Mistake) {
+	slices.SortStableFunc(groups, func(a, b []database.Mistake) int { return cmp.Compare(len(b), len(a)) })
+}
+
+// mistakeGroupLabel まとまりの見出し（いちばん多い単元。単元がなければ最初の問題のタイトル）
+func mistakeGroupLabel(group []database.Mistake) string {
+	counts := make(map[string]int)
+	label, best := "", 0
+	for _, mistake := range group {
+		if mistake.ProblemType == "" {
+			continue
+		}
+		counts[mistake.ProblemType]++
+		if counts[mistake.ProblemType] > best {
+			label, best = mistake.ProblemType, counts[mistake.ProblemType]
+		}
+	}
+	if label == "" && len(group) > 0 {
+		label = group[0].ProblemTitle
+	}
+	if label == "" {
+		label = "その他"
+	}
+	return label
+}
+
+// findDuplicateBankProblem 問題バンクに、ほぼ同じ問題がすでに保存されていれば返す
+// （埋め込みベクトルで比べ、AIに接続できないときは問題文の類似ハッシュで比べる）
+func (m *MainApp) findDuplicateBankProblem(ctx context.Context, problem *database.BankProblem) *database.BankProblem {
+	existing, err := m.db.GetBankProblems(problem.UserID, problem.Tag, bankDuplicateScanLimit)
+	if err != nil {
+		slog.Error("問題バンク取得エラー", "error", err)
+		return nil
+	}
+
+	hash := ai.SimilarityHash(problem.Question)
+	embedding, embedErr := m.aiEngine.Embed(ctx, problem.Question)
+	for i := range existing {
+		other := &existing[i]
+		if embedErr == nil {
+			if otherEmbedding, err := m.aiEngine.Embed(ctx, other.Question); err == nil {
+				if ai.CosineSimilarity(embedding, otherEmbedding) >= ai.DuplicateThreshold {
+					return other
+				}
+				continue
+			}
+		}
+		if ai.IsNearDuplicate(hash, []string{ai.SimilarityHash(other.Question)}) {
+			return other
+		}
+	}
+	return nil
+}
+
+// relevantMistakes 出題に関係する最近の間違いを探す（単元を指定したときは、その単元に関係する間違いだけ）
+func (m *MainApp) relevantMistakes(ctx context.Context, subject, topic string) []ai.MistakeExample {
+	filter := database.MistakeFilter{Subject: subject, Since: time.Now().AddDate(0, 0, -mistakeRetrievalDays)}
+	mistakes, err := m.db.GetMistakes(m.currentUser.ID, filter, mistakeRetrievalScan)
+	if err != nil {
+		slog.Error("間違えた問題取得エラー", "error", err)
+		return nil
+	}
+	mistakes = slices.DeleteFunc(mistakes, func(mistake database.Mistake) bool { return mistake.ProblemContent == "" })
+	if topic != "" {
+		mistakes = m.rankMistakesByTopic(ctx, topic, mistakes)
+	}
+
+	examples := make([]ai.MistakeExample, 0, ai.MaxMistakeExamples)
+	for _, mistake := range mistakes[:min(len(mistakes), ai.MaxMistakeExamples)] {
+		examples = append(examples, ai.MistakeExample{
+			ProblemType:   mistake.ProblemType,
+			Description:   mistake.ProblemContent,
+			UserAnswer:    mistake.UserAnswer,
+			CorrectAnswer: mistake.CorrectAnswer,
+		})
+	}
+	return examples
+}
+
+// rankMistakesByTopic 単元に関係する間違いを関係の強い順に並べる
+// （同じ単元の間違いが先で、次に埋め込みベクトルが単元名に近い間違い。AIに接続できないときは同じ単元だけ）
+func (m *MainApp) rankMistakesByTopic(ctx context.Context, topic string, mistakes []database.Mistake) []database.Mistake {
+	var ranked, others []database.Mistake
+	for _, mistake := range mistakes {
+		if mistake.ProblemType == topic {
+			ranked = append(ranked, mistake)
+		} else {
+			others = append(others, mistake)
+		}
+	}
+	if len(ranked) >= ai.MaxMistakeExamples || len(others) == 0 {
+		return ranked
+	}
+
+	query, err := m.aiEngine.Embed(ctx, topic)
+	if err != nil {
+		return ranked
+	}
+	embeddings := make([][]float64, 0, len(others))
+	for _, mistake := range others {
+		embedding, err := m.aiEngine.Embed(ctx, mistake.ProblemContent)
+		if err != nil {
+			return ranked
+		}
+		embeddings = append(embeddings, embedding)
+	}
+	for _, i := range ai.NearestEmbeddings(query, embeddings, ai.RelevantThreshold, ai.MaxMistakeExamples-len(ranked)) {
+		ranked = append(ranked, others[i])
+	}
+	return ranked
+}
@@ -0,0 +1,170 @@
+package gui
+
+import (
+	"bytes"
+	"image/color"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/sketch"
+)
+
+// sketchPadHeight 計算メモの手書きエリアの高さ
+const sketchPadHeight = 240
+
+// SketchPad 計算メモの手書きエリア（マウスやペンでドラッグして線を描く）
+type SketchPad struct {
+	widget.BaseWidget
+
+	strokes []sketch.Stroke
+	drawing bool            // 線を描いている途中
+	lines   *fyne.Container // 描いた線（canvas.Line）
+	paper   *canvas.Rectangle
+}
+
+// NewSketchPad 計算メモの手書きエリアを作成
+func NewSketchPad() *SketchPad {
+	pad := &SketchPad{
+		lines: container.NewWithoutLayout(),
+		paper: canvas.NewRectangle(color.White),
+	}
+	pad.paper.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	pad.paper.StrokeWidth = 1
+	pad.ExtendBaseWidget(pad)
+	return pad
+}
+
+// CreateRenderer 白い紙の上に描いた線を重ねて表示
+func (p *SketchPad) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewStack(p.paper, p.lines))
+}
+
+// MinSize 計算を書ける広さ
+func (p *SketchPad) MinSize() fyne.Size {
+	return fyne.NewSize(320, sketchPadHeight)
+}
+
+// Dragged ドラッグした分だけ線をのばす
+func (p *SketchPad) Dragged(event *fyne.DragEvent) {
+	point := sketch.Point{X: event.Position.X, Y: event.Position.Y}
+	if !p.drawing {
+		p.drawing = true
+		start := event.Position.Subtract(event.Dragged)
+		p.strokes = append(p.strokes, sketch.Stroke{{X: start.X, Y: start.Y}})
+	}
+	stroke := &p.strokes[len(p.strokes)-1]
+	last := (*stroke)[len(*stroke)-1]
+	*stroke = append(*stroke, point)
+	p.addLine(last, point)
+}
+
+// DragEnd ペンを離したら線を1本終える
+func (p *SketchPad) DragEnd() {
+	p.drawing = false
+}
+
+// Undo 最後に描いた線を1本消す
+func (p *SketchPad) Undo() {
+	if len(p.strokes) == 0 {
+		return
+	}
+	p.strokes = p.strokes[:len(p.strokes)-1]
+	p.redraw()
+}
+
+// Clear 描いた線をすべて消す
+func (p *SketchPad) Clear() {
+	p.strokes = nil
+	p.drawing = false
+	p.redraw()
+}
+
+// Empty まだ何も描いていないかどうか
+func (p *SketchPad) Empty() bool {
+	return len(p.strokes) == 0
+}
+
+// PNG 描いた線をPNG画像にする
+func (p *SketchPad) PNG() ([]byte, error) {
+	size := p.Size()
+	return sketch.EncodePNG(p.strokes, size.Width, size.Height)
+}
+
+// addLine 2点の間の線を表示に加える
+func (p *SketchPad) addLine(from, to sketch.Point) {
+	line := canvas.NewLine(color.Black)
+	line.StrokeWidth = sketch.LineWidth
+	line.Position1 = fyne.NewPos(from.X, from.Y)
+	line.Position2 = fyne.NewPos(to.X, to.Y)
+	p.lines.Add(line)
+}
+
+// redraw 線の表示を作り直す
+func (p *SketchPad) redraw() {
+	p.lines.RemoveAll()
+	for _, stroke := range p.strokes {
+		for i := 1; i < len(stroke); i++ {
+			p.addLine(stroke[i-1], stroke[i])
+		}
+	}
+	p.lines.Refresh()
+}
+
+// newSketchCard 学習画面の計算メモ（開いたときだけ手書きエリアを表示し、解答といっしょに保存するか選べる）
+func (s *StudyView) newSketchCard() fyne.CanvasObject {
+	s.sketchPad = NewSketchPad()
+	s.sketchAttach = widget.NewCheck("解答といっしょに保存する（間違いノートで見直せます）", nil)
+	s.sketchAttach.SetChecked(true)
+
+	undoBtn := widget.NewButtonWithIcon("1つ戻す", theme.ContentUndoIcon(), s.sketchPad.Undo)
+	clearBtn := widget.NewButtonWithIcon("消す", theme.DeleteIcon(), s.sketchPad.Clear)
+	pad := container.NewVBox(s.sketchPad, container.NewHBox(undoBtn, clearBtn, s.sketchAttach))
+	pad.Hide()
+
+	var toggleBtn *widget.Button
+	toggleBtn = widget.NewButton("✏️ 計算メモを開く", func() {
+		if pad.Visible() {
+			pad.Hide()
+			toggleBtn.SetText("✏️ 計算メモを開く")
+			return
+		}
+		pad.Show()
+		toggleBtn.SetText("✏️ 計算メモを閉じる")
+	})
+	return container.NewVBox(toggleBtn, pad)
+}
+
+// saveSketch 計算メモを描いていて保存を選んでいれば、解答結果といっしょに保存
+func (s *StudyView) saveSketch(resultID string, mainApp *MainApp) {
+	if s.sketchPad == nil || s.sketchPad.Empty() || !s.sketchAttach.Checked {
+		return
+	}
+	image, err := s.sketchPad.PNG()
+	if err != nil {
+		slog.Error("計算メモの画像変換エラー", "error", err)
+		return
+	}
+	if err := mainApp.db.SaveProblemSketch(resultID, image); err != nil {
+		slog.Error("計算メモ保存エラー", "error", err)
+	}
+}
+
+// showProblemSketch 解答といっしょに保存した計算メモを表示
+func (m *MainApp) showProblemSketch(resultID string) {
+	data, err := m.db.GetProblemSketch(resultID)
+	if err != nil || data == nil {
+		slog.Error("計算メモ取得エラー", "error", err)
+		m.ShowErrorDialog("計算メモ", "計算メモを読み込めませんでした。")
+		return
+	}
+	img := canvas.NewImageFromReader(bytes.NewReader(data), "sketch.png")
+	img.FillMode = canvas.ImageFillContain
+	img.SetMinSize(fyne.NewSize(480, sketchPadHeight*1.5))
+	dialog.NewCustom("✏️ 計算メモ", "閉じる", img, m.window).Show()
+}
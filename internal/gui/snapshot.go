@@ -0,0 +1,91 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/export"
+)
+
+// createAnalysisSnapshotCard 分析用データの書き出しのカードを作成（保護者・研究者がPythonなどで分析するため）
+func (m *MainApp) createAnalysisSnapshotCard() *widget.Card {
+	description := widget.NewLabel("学習時間・正答率・単元ごとの記録を、分析用のSQLiteファイルに書き出します。\n名前・問題文・解答・メモは含みません。アプリのデータベースはそのままです。")
+	description.Wrapping = fyne.TextWrapWord
+
+	exportBtn := widget.NewButton("📊 分析用のデータを書き出す", m.exportAnalysisSnapshot)
+
+	return widget.NewCard("分析用のデータ", "", container.NewVBox(description, container.NewHBox(exportBtn)))
+}
+
+// exportAnalysisSnapshot これまでの学習データを集めて、分析用のSQLiteファイルに保存
+func (m *MainApp) exportAnalysisSnapshot() {
+	now := time.Now()
+	snapshot, err := m.loadAnalysisSnapshot(now)
+	if err != nil {
+		slog.Error("分析用データ読み込みエラー", "error", err)
+		m.ShowErrorDialog("エラー", fmt.Sprintf("学習データを読み込めませんでした: %v", err))
+		return
+	}
+	if len(snapshot.Sessions) == 0 {
+		m.ShowInfoDialog("分析用のデータを書き出す", "まだ学習の記録がありません。")
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("保存先の選択に失敗しました: %v", err))
+			return
+		}
+		if writer == nil {
+			return // キャンセル
+		}
+		defer func() { _ = writer.Close() }()
+
+		if err := export.WriteAnalysisSnapshot(writer, snapshot, now); err != nil {
+			slog.Error("分析用データ書き出しエラー", "error", err)
+			m.ShowErrorDialog("エラー", fmt.Sprintf("分析用のファイルの作成に失敗しました: %v", err))
+			return
+		}
+
+		m.ShowInfoDialog("保存完了", fmt.Sprintf("%s に保存しました。\nPythonでは sqlite3 と pandas.read_sql で読み込めます。", writer.URI().Name()))
+	}, m.window)
+	saveDialog.SetFileName(fmt.Sprintf("studybuddy_analysis_%s%s", now.Format("20060102"), export.SnapshotFileExtension))
+	saveDialog.Show()
+}
+
+// loadAnalysisSnapshot 今のプロフィールのこれまでの学習データ
+func (m *MainApp) loadAnalysisSnapshot(now time.Time) (export.AnalysisSnapshot, error) {
+	userID := m.currentUser.ID
+	until := now.AddDate(0, 0, 1)
+
+	sessions, err := m.db.GetStudySessionsBetween(userID, time.Time{}, until)
+	if err != nil {
+		return export.AnalysisSnapshot{}, err
+	}
+	results, err := m.db.GetProblemResultsBetween(userID, time.Time{}, until)
+	if err != nil {
+		return export.AnalysisSnapshot{}, err
+	}
+	topics, err := m.db.GetTopicMastery(userID)
+	if err != nil {
+		return export.AnalysisSnapshot{}, err
+	}
+	focus, err := m.db.GetSessionFocusSince(userID, time.Time{})
+	if err != nil {
+		return export.AnalysisSnapshot{}, err
+	}
+
+	return export.AnalysisSnapshot{
+		Grade:    m.currentUser.Grade,
+		Sessions: sessions,
+		Results:  results,
+		Topics:   topics,
+		Focus:    focus,
+	}, nil
+}
@@ -0,0 +1,210 @@
+package gui
+
+import (
+	"fmt"
+	"image/color"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// studyCalendarWeeks 学習カレンダーに表示する週数
+const studyCalendarWeeks = 26
+
+// studyCalendarLevels 学習時間（分）の色分けの境目（これ未満なら1段目、…）
+var studyCalendarLevels = []float64{15, 30, 60}
+
+// studyCalendarColors 学習した日の色（学習時間の少ない順）
+var studyCalendarColors = []color.NRGBA{
+	{R: 0x9b, G: 0xe9, B: 0xa8, A: 0xff},
+	{R: 0x40, G: 0xc4, B: 0x63, A: 0xff},
+	{R: 0x30, G: 0xa1, B: 0x4e, A: 0xff},
+	{R: 0x21, G: 0x6e, B: 0x39, A: 0xff},
+}
+
+// StudyCalendar 学習した日と学習時間を色の濃さで表すカレンダー（列が週、行が曜日）
+type StudyCalendar struct {
+	widget.BaseWidget
+
+	start   time.Time // 最初の日（日曜日）
+	minutes []float64 // startからの日ごとの学習時間（分）
+}
+
+// NewStudyCalendar 学習カレンダーを作成
+func NewStudyCalendar() *StudyCalendar {
+	c := &StudyCalendar{}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetData 表示するデータを更新（startは日曜日）
+func (c *StudyCalendar) SetData(start time.Time, minutes []float64) {
+	c.start = start
+	c.minutes = minutes
+	c.Refresh()
+}
+
+// CreateRenderer レンダラーを作成
+func (c *StudyCalendar) CreateRenderer() fyne.WidgetRenderer {
+	r := &studyCalendarRenderer{calendar: c}
+	for _, name := range []string{"月", "水", "金"} {
+		r.weekdays = append(r.weekdays, newCaptionText(name))
+	}
+	r.Refresh()
+	return r
+}
+
+// studyCalendarRenderer 学習カレンダーの描画
+type studyCalendarRenderer struct {
+	calendar *StudyCalendar
+
+	cells    []*canvas.Rectangle
+	months   []*canvas.Text
+	monthCol []int // 月のラベルを表示する列
+	weekdays []*canvas.Text
+}
+
+// cellSize マス1つの大きさ（間隔を含む）
+func (r *studyCalendarRenderer) cellSize() float32 {
+	return theme.CaptionTextSize() * 1.3
+}
+
+// MinSize 最小サイズ
+func (r *studyCalendarRenderer) MinSize() fyne.Size {
+	cell := r.cellSize()
+	return fyne.NewSize(cell*2+cell*studyCalendarWeeks, cell*8)
+}
+
+// Layout 曜日・月のラベルとマスを配置
+func (r *studyCalendarRenderer) Layout(_ fyne.Size) {
+	cell := r.cellSize()
+	left := cell * 2
+	top := cell
+
+	for i, text := range r.weekdays {
+		text.Move(fyne.NewPos(0, top+cell*float32(i*2+1)))
+	}
+	for i, text := range r.months {
+		text.Move(fyne.NewPos(left+cell*float32(r.monthCol[i]), 0))
+	}
+	for i, rect := range r.cells {
+		week, weekday := i/7, i%7
+		rect.Move(fyne.NewPos(left+cell*float32(week), top+cell*float32(weekday)))
+		rect.Resize(fyne.NewSquareSize(cell * 0.8))
+	}
+}
+
+// Refresh データに合わせてマスの色と月のラベルを更新
+func (r *studyCalendarRenderer) Refresh() {
+	c := r.calendar
+	r.cells, r.months, r.monthCol = nil, nil, nil
+
+	empty := theme.Color(theme.ColorNameInputBackground)
+	today := time.Now()
+	for i := range c.minutes {
+		day := c.start.AddDate(0, 0, i)
+		if day.After(today) {
+			break
+		}
+		rect := canvas.NewRectangle(studyCalendarColor(c.minutes[i], empty))
+		rect.CornerRadius = 2
+		r.cells = append(r.cells, rect)
+
+		// 月が変わった週の先頭に月を表示
+		if i == 0 || (day.Weekday() == time.Sunday && day.Day() <= 7) {
+			r.months = append(r.months, newCaptionText(fmt.Sprintf("%d月", day.Month())))
+			r.monthCol = append(r.monthCol, i/7)
+		}
+	}
+	for _, text := range r.weekdays {
+		text.Color = theme.Color(theme.ColorNameForeground)
+	}
+
+	r.Layout(c.Size())
+	canvas.Refresh(c)
+}
+
+// Objects 描画オブジェクト一覧
+func (r *studyCalendarRenderer) Objects() []fyne.CanvasObject {
+	var objects []fyne.CanvasObject
+	for _, text := range r.weekdays {
+		objects = append(objects, text)
+	}
+	for _, text := range r.months {
+		objects = append(objects, text)
+	}
+	for _, rect := range r.cells {
+		objects = append(objects, rect)
+	}
+	return objects
+}
+
+// Destroy 破棄処理
+func (r *studyCalendarRenderer) Destroy() {}
+
+// studyCalendarColor 学習時間に応じたマスの色
+func studyCalendarColor(minutes float64, empty color.Color) color.Color {
+	if minutes <= 0 {
+		return empty
+	}
+	for i, level := range studyCalendarLevels {
+		if minutes < level {
+			return studyCalendarColors[i]
+		}
+	}
+	return studyCalendarColors[len(studyCalendarColors)-1]
+}
+
+// newCaptionText 小さい文字のテキストを作成
+func newCaptionText(text string) *canvas.Text {
+	t := canvas.NewText(text, theme.Color(theme.ColorNameForeground))
+	t.TextSize = theme.CaptionTextSize()
+	return t
+}
+
+// createStudyCalendarCard 学習した日のカレンダーと連続記録のカードを作成
+func (m *MainApp) createStudyCalendarCard() *widget.Card {
+	studyCalendar := NewStudyCalendar()
+	subtitle := ""
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// 今週の日曜日から数えてstudyCalendarWeeks週分
+	start := today.AddDate(0, 0, -int(today.Weekday())-(studyCalendarWeeks-1)*7)
+	days := int(today.Sub(start).Hours()/24) + 1
+
+	points, err := m.progressManager.GetTrendPoints(m.currentUser.ID, "", days, 1)
+	if err != nil {
+		slog.Error("学習カレンダー取得エラー", "error", err)
+	} else {
+		minutes := make([]float64, len(points))
+		studyDays := 0
+		for i, point := range points {
+			minutes[i] = point.StudyMinutes
+			if point.StudyMinutes > 0 {
+				studyDays++
+			}
+		}
+		studyCalendar.SetData(start, minutes)
+		subtitle = fmt.Sprintf("直近%d週間で%d日学習", studyCalendarWeeks, studyDays)
+	}
+
+	if streak, err := m.progressManager.GetStudyStreak(m.currentUser.ID); err == nil {
+		subtitle += fmt.Sprintf("　🔥 連続%d日（最長%d日）", streak.CurrentStreak, streak.LongestStreak)
+	}
+
+	legend := container.NewHBox(widget.NewLabel("少ない"))
+	for _, c := range studyCalendarColors {
+		swatch := canvas.NewRectangle(c)
+		swatch.SetMinSize(fyne.NewSquareSize(12))
+		legend.Add(container.NewCenter(swatch))
+	}
+	legend.Add(widget.NewLabel("多い"))
+
+	return widget.NewCard("📅 学習カレンダー", subtitle, container.NewVBox(container.NewHScroll(studyCalendar), legend))
+}
@@ -0,0 +1,101 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+
+	"github.com/okamyuji/studybuddy-ai/internal/export"
+)
+
+// studyRecordSheet 学習日記タブで選んだ期間の学習記録表の内容（学習の記録と保存した日記から作る）
+func (m *MainApp) studyRecordSheet() (export.StudyRecordSheet, error) {
+	days := diaryExportPeriods[max(m.diaryView.periodSelect.SelectedIndex(), 0)].days
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today.AddDate(0, 0, -(days - 1))
+
+	sheet := export.StudyRecordSheet{StudentName: m.currentUser.Name, Grade: m.currentUser.Grade}
+	entries, err := m.db.GetDiaryEntries(m.currentUser.ID, from.Format("2006-01-02"), today.Format("2006-01-02"))
+	if err != nil {
+		return sheet, err
+	}
+	comments := make(map[string]string)
+	for _, entry := range entries {
+		comments[entry.Date] = entry.Content
+	}
+
+	for day := from; !day.After(today); day = day.AddDate(0, 0, 1) {
+		req, err := m.diaryRequest(day)
+		if err != nil {
+			return sheet, err
+		}
+		record := export.StudyRecordDay{Date: day, Comment: comments[day.Format("2006-01-02")]}
+		for _, activity := range req.Activities {
+			record.Activities = append(record.Activities, export.StudyRecordActivity{
+				Subject:  activity.Subject,
+				Minutes:  activity.Minutes,
+				Problems: activity.Problems,
+				Correct:  activity.Correct,
+			})
+		}
+		sheet.Days = append(sheet.Days, record)
+	}
+	return sheet, nil
+}
+
+// selectedStudyRecordTemplate 選んでいる学習記録表の様式
+func (m *MainApp) selectedStudyRecordTemplate() export.StudyRecordTemplate {
+	return export.StudyRecordTemplates[max(m.diaryView.recordSelect.SelectedIndex(), 0)]
+}
+
+// exportStudyRecordPDF 学習記録表をPDFで書き出す
+func (m *MainApp) exportStudyRecordPDF() {
+	sheet, err := m.studyRecordSheet()
+	if err != nil {
+		slog.Error("学習記録取得エラー", "error", err)
+		m.ShowErrorDialog("エラー", fmt.Sprintf("学習の記録を読み込めませんでした: %v", err))
+		return
+	}
+	template := m.selectedStudyRecordTemplate()
+
+	m.savePDF(fmt.Sprintf("学習記録表_%s.pdf", time.Now().Format("20060102")), func(w io.Writer, exporter *export.Exporter) error {
+		return exporter.WriteStudyRecordPDF(w, template, sheet)
+	})
+}
+
+// exportStudyRecordXLSX 学習記録表をExcel形式で書き出す
+func (m *MainApp) exportStudyRecordXLSX() {
+	sheet, err := m.studyRecordSheet()
+	if err != nil {
+		slog.Error("学習記録取得エラー", "error", err)
+		m.ShowErrorDialog("エラー", fmt.Sprintf("学習の記録を読み込めませんでした: %v", err))
+		return
+	}
+	template := m.selectedStudyRecordTemplate()
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			m.ShowErrorDialog("エラー", fmt.Sprintf("保存先の選択に失敗しました: %v", err))
+			return
+		}
+		if writer == nil {
+			return // キャンセル
+		}
+		defer func() { _ = writer.Close() }()
+
+		if err := export.WriteStudyRecordXLSX(writer, template, sheet); err != nil {
+			slog.Error("Excel出力エラー", "error", err)
+			m.ShowErrorDialog("エラー", fmt.Sprintf("Excelのファイルの作成に失敗しました: %v", err))
+			return
+		}
+
+		m.ShowInfoDialog("保存完了", fmt.Sprintf("%s に保存しました。", writer.URI().Name()))
+	}, m.window)
+	saveDialog.SetFileName(fmt.Sprintf("学習記録表_%s.xlsx", time.Now().Format("20060102")))
+	saveDialog.Show()
+}
@@ -0,0 +1,50 @@
+package gui
+
+import (
+	"slices"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+// studyTopicAuto 単元を選ばないとき（出題の計画でおすすめの単元から出題する）
+const studyTopicAuto = "おまかせ（おすすめの単元）"
+
+// newStudyTopicSelect 科目選択の下の、練習する単元を選ぶ欄（科目を選ぶまでは選べない）
+func (m *MainApp) newStudyTopicSelect(study *StudyView) *widget.Select {
+	topicSelect := widget.NewSelect([]string{studyTopicAuto}, func(topic string) {
+		subject := study.subjectSelect.Selected
+		// 問題生成中・科目を選ぶ前は選択を無視
+		if study.isGenerating || subject == "" || topic == "" {
+			return
+		}
+		if topic == studyTopicAuto {
+			m.beginStudySession(subject)
+			return
+		}
+		m.practiceTopic(subject, topic)
+	})
+	topicSelect.Selected = studyTopicAuto
+	topicSelect.Disable()
+	return topicSelect
+}
+
+// showTopicOptions 単元の選択肢を科目の学習範囲に合わせ、選択を変える（変更イベントは起こさない）
+func (s *StudyView) showTopicOptions(mainApp *MainApp, subject, topic string) {
+	if subject == "" {
+		s.topicSelect.Options = []string{studyTopicAuto}
+		s.topicSelect.Selected = studyTopicAuto
+		s.topicSelect.Disable()
+		return
+	}
+
+	topics := mainApp.aiEngine.CurriculumTopics(mainApp.currentUser.Grade, subject)
+	s.topicSelect.Options = append([]string{studyTopicAuto}, topics...)
+	s.topicSelect.Selected = studyTopicAuto
+	if slices.Contains(topics, topic) {
+		s.topicSelect.Selected = topic
+	}
+	s.topicSelect.Refresh()
+	if !s.isGenerating {
+		s.topicSelect.Enable()
+	}
+}
@@ -0,0 +1,82 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// dashboardTipLimit ホーム画面で順番に表示するコツの数（新しい順）
+const dashboardTipLimit = 10
+
+// defaultStudyTips まだフィードバックのコツがないときに表示するコツ
+var defaultStudyTips = []string{
+	"毎日少しずつでも続けることが大切です。頑張りましょう！",
+	"間違えた問題は、解説を読んでから時間を置いてもう一度解くと定着しやすくなります。",
+	"理解したことを自分の言葉で説明してみると、記憶に残りやすくなります。",
+}
+
+// saveStudyTip フィードバックで受け取ったコツをホーム画面用に保存
+func (m *MainApp) saveStudyTip(subject, tip string) {
+	tip = strings.TrimSpace(tip)
+	if tip == "" {
+		return
+	}
+	err := m.db.SaveStudyTip(&database.StudyTip{
+		ID:        uuid.New().String(),
+		UserID:    m.currentUser.ID,
+		Subject:   subject,
+		Tip:       tip,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		slog.Error("学習のコツ保存エラー", "error", err)
+	}
+}
+
+// createTipsCard 最近のフィードバックのコツを1つずつ表示するカードを作成
+func (m *MainApp) createTipsCard() *widget.Card {
+	tips, err := m.db.GetRecentStudyTips(m.currentUser.ID, dashboardTipLimit)
+	if err != nil {
+		slog.Error("学習のコツ取得エラー", "error", err)
+	}
+
+	var texts, subjects []string
+	for _, tip := range tips {
+		texts = append(texts, tip.Tip)
+		subjects = append(subjects, fmt.Sprintf("%sのフィードバックより", tip.Subject))
+	}
+	if len(texts) == 0 {
+		texts = defaultStudyTips
+		subjects = make([]string, len(texts))
+	}
+
+	tipLabel := widget.NewLabel("")
+	tipLabel.Wrapping = fyne.TextWrapWord
+	var card *widget.Card
+	index := 0
+	show := func() {
+		tipLabel.SetText(texts[index])
+		card.SetSubTitle(subjects[index])
+	}
+
+	nextBtn := widget.NewButton("次のコツ ▶", func() {
+		index = (index + 1) % len(texts)
+		show()
+	})
+	if len(texts) == 1 {
+		nextBtn.Hide()
+	}
+
+	card = widget.NewCard("💡 学習のこつ", "", container.NewBorder(nil, nextBtn, nil, nil, tipLabel))
+	show()
+	return card
+}
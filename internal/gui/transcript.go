@@ -0,0 +1,178 @@
+package gui
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/export"
+)
+
+// transcriptDayOptions 保護者が選べる、AIとのやりとりの記録を残す日数（0は記録しない）
+var transcriptDayOptions = []int{0, 30, 90, 180, config.MaxTranscriptDays}
+
+// transcriptDaysLabel 記録を残す日数の表示名
+func transcriptDaysLabel(days int) string {
+	if days == 0 {
+		return "記録しない"
+	}
+	return fmt.Sprintf("%d日", days)
+}
+
+// newTranscript AIに質問するときのやりとりの記録を用意（記録しない設定ならnil）
+func (m *MainApp) newTranscript(source string, req ai.CaptureRequest) *database.TutorTranscript {
+	if m.config.Parent.TranscriptDays <= 0 {
+		return nil
+	}
+	transcript := &database.TutorTranscript{
+		ID:        uuid.New().String(),
+		UserID:    m.currentUser.ID,
+		Source:    source,
+		Subject:   req.Subject,
+		Question:  strings.TrimSpace(req.Question),
+		CreatedAt: time.Now(),
+	}
+	if m.studyView != nil && m.studyView.currentSession != nil {
+		transcript.SessionID = m.studyView.currentSession.ID
+	}
+	return transcript
+}
+
+// saveTranscript 表示した解説をやりとりの記録に加えて保存（AIを使えなかったときは残さない）
+func (m *MainApp) saveTranscript(transcript *database.TutorTranscript, e *ai.CaptureExplanation) {
+	if transcript == nil || e.Offline {
+		return
+	}
+	if transcript.Subject == "" {
+		transcript.Subject = e.Subject
+	}
+	transcript.Answer = captureMarkdown(e)
+	transcript.Refused = e.Refused != nil
+	if err := m.db.CreateTutorTranscript(transcript); err != nil {
+		slog.Error("AIとのやりとりの記録エラー", "error", err)
+	}
+}
+
+// loadTranscripts 保存期間内のAIとのやりとりの記録をMarkdownにする（記録がなければ空）
+func (m *MainApp) loadTranscripts(now time.Time) (string, int, error) {
+	transcripts, err := m.db.GetTutorTranscripts(m.currentUser.ID, now.AddDate(0, 0, -m.config.Parent.TranscriptDays))
+	if err != nil || len(transcripts) == 0 {
+		return "", 0, err
+	}
+	var buf bytes.Buffer
+	if err := export.WriteTranscriptMarkdown(&buf, m.currentUser.Name, transcripts, now); err != nil {
+		return "", 0, err
+	}
+	return buf.String(), len(transcripts), nil
+}
+
+// showTranscripts これまでのAIとのやりとりを見直すウィンドウを表示
+func (m *MainApp) showTranscripts() {
+	now := time.Now()
+	markdown, count, err := m.loadTranscripts(now)
+	if err != nil {
+		slog.Error("AIとのやりとりの記録の取得エラー", "error", err)
+		m.ShowErrorDialog("エラー", fmt.Sprintf("記録を読み込めませんでした: %v", err))
+		return
+	}
+	if count == 0 {
+		message := "まだ記録がありません。クイック質問や「質問する」でAIに聞いたことが、ここに残ります。"
+		if m.config.Parent.TranscriptDays == 0 {
+			message = "AIとのやりとりは記録しない設定になっています。"
+		}
+		m.ShowInfoDialog("これまでの質問", message)
+		return
+	}
+
+	text := widget.NewRichTextFromMarkdown(markdown)
+	text.Wrapping = fyne.TextWrapWord
+
+	w := m.app.NewWindow("📜 これまでの質問")
+	exportBtn := widget.NewButton("📝 Markdownで書き出す", func() { m.exportTranscripts(w) })
+	w.SetContent(container.NewBorder(nil, container.NewHBox(exportBtn), nil, nil, container.NewVScroll(text)))
+	w.Resize(fyne.NewSize(640, 600))
+	w.Show()
+}
+
+// exportTranscripts 保存期間内のAIとのやりとりの記録をMarkdownファイルに保存
+func (m *MainApp) exportTranscripts(w fyne.Window) {
+	now := time.Now()
+	markdown, count, err := m.loadTranscripts(now)
+	if err != nil {
+		slog.Error("AIとのやりとりの記録の取得エラー", "error", err)
+		dialog.ShowError(fmt.Errorf("記録を読み込めませんでした: %w", err), w)
+		return
+	}
+	if count == 0 {
+		dialog.ShowInformation("AIとのやりとりの記録", "書き出す記録がありません。", w)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("保存先の選択に失敗しました: %w", err), w)
+			return
+		}
+		if writer == nil {
+			return // キャンセル
+		}
+		defer func() { _ = writer.Close() }()
+
+		if _, err := writer.Write([]byte(markdown)); err != nil {
+			slog.Error("AIとのやりとりの記録の書き出しエラー", "error", err)
+			dialog.ShowError(fmt.Errorf("ファイルの作成に失敗しました: %w", err), w)
+			return
+		}
+		dialog.ShowInformation("保存完了", fmt.Sprintf("%d件の記録を %s に保存しました。", count, writer.URI().Name()), w)
+	}, w)
+	saveDialog.SetFileName(fmt.Sprintf("studybuddy_transcript_%s%s", now.Format("20060102"), export.TranscriptFileExtension))
+	saveDialog.Show()
+}
+
+// createTranscriptCard 保護者ダッシュボードの、AIとのやりとりの記録の保存期間と書き出し
+func (m *MainApp) createTranscriptCard(w fyne.Window) *widget.Card {
+	options := slices.Clone(transcriptDayOptions)
+	if !slices.Contains(options, m.config.Parent.TranscriptDays) {
+		options = append(options, m.config.Parent.TranscriptDays) // 設定ファイルで決めた日数
+		slices.Sort(options)
+	}
+	labels := make([]string, len(options))
+	for i, days := range options {
+		labels[i] = transcriptDaysLabel(days)
+	}
+	daysSelect := widget.NewSelect(labels, func(selected string) {
+		days := options[slices.Index(labels, selected)]
+		if days == m.config.Parent.TranscriptDays {
+			return
+		}
+		m.config.Parent.TranscriptDays = days
+		m.saveConfig()
+		// 短くした保存期間より古い記録はすぐに削除
+		if _, err := m.db.PruneTutorTranscripts(time.Now().AddDate(0, 0, -days)); err != nil {
+			slog.Error("AIとのやりとりの記録の整理エラー", "error", err)
+		}
+	})
+	daysSelect.SetSelected(transcriptDaysLabel(m.config.Parent.TranscriptDays))
+
+	note := widget.NewLabel("クイック質問と「質問する」での質問とAIの解説を残し、生徒は「質問する」タブであとから見直せます。保存期間を過ぎた記録は削除します（全プロフィール共通）。")
+	note.Wrapping = fyne.TextWrapWord
+	exportBtn := widget.NewButton("📝 Markdownで書き出す", func() { m.exportTranscripts(w) })
+
+	return widget.NewCard("📜 AIとのやりとりの記録", "", container.NewVBox(
+		note,
+		widget.NewForm(widget.NewFormItem("保存期間", daysSelect)),
+		container.NewHBox(exportBtn),
+	))
+}
@@ -0,0 +1,83 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+)
+
+// newTranslateButton 問題を英語（英文だけの問題なら日本語）に訳して、原文と並べて見るボタン
+// 解答前は答えがわからないよう、解説は訳さない
+func (m *MainApp) newTranslateButton(problem *ai.Problem, withExplanation bool) *widget.Button {
+	source := *problem
+	if !withExplanation {
+		source.Explanation = ""
+	}
+	language := ai.TranslationLanguage(source.Title + source.Description)
+	label := fmt.Sprintf("🌐 %sで見る", language)
+
+	var btn *widget.Button
+	btn = widget.NewButton(label, func() {
+		btn.Disable()
+		btn.SetText("🌐 翻訳しています...")
+		m.goSafe("問題の翻訳", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+			defer cancel()
+
+			translation, err := m.aiEngine.TranslateProblem(ctx, source, language)
+			fyne.Do(func() {
+				btn.Enable()
+				btn.SetText(label)
+				if err != nil {
+					slog.Error("問題の翻訳エラー", "error", err)
+					m.ShowErrorDialog("翻訳", fmt.Sprintf("翻訳できませんでした。AIに接続できるか確かめてください。\n%v", err))
+					return
+				}
+				m.showTranslation(source, translation)
+			})
+		}, func() {
+			btn.Enable()
+			btn.SetText(label)
+		})
+	})
+	btn.Importance = widget.LowImportance
+	return btn
+}
+
+// showTranslation 原文と翻訳を左右に並べて表示
+func (m *MainApp) showTranslation(problem ai.Problem, translation *ai.Translation) {
+	original := ai.LanguageJapanese
+	if translation.Language == ai.LanguageJapanese {
+		original = ai.LanguageEnglish
+	}
+
+	content := container.NewGridWithColumns(2,
+		widget.NewCard("原文（"+original+"）", "", translationColumn(problem.Title, problem.Description, problem.Options, problem.Explanation)),
+		widget.NewCard("翻訳（"+translation.Language+"）", "", translationColumn(translation.Title, translation.Description, translation.Options, translation.Explanation)),
+	)
+	popup := dialog.NewCustom("🌐 翻訳", "閉じる", container.NewVScroll(content), m.window)
+	popup.Resize(fyne.NewSize(760, 520))
+	popup.Show()
+}
+
+// translationColumn 翻訳の画面の片側（タイトル・問題文・選択肢・解説）
+func translationColumn(title, description string, options []string, explanation string) fyne.CanvasObject {
+	text := fmt.Sprintf("## %s\n\n%s\n\n", title, description)
+	for i, option := range options {
+		text += fmt.Sprintf("%d. %s\n\n", i+1, option)
+	}
+	if explanation != "" {
+		text += "---\n\n" + explanation
+	}
+	rich := widget.NewRichTextFromMarkdown(text)
+	rich.Wrapping = fyne.TextWrapWord
+	return rich
+}
@@ -0,0 +1,99 @@
+package gui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+// setupTray タスクトレイ（macOSはメニューバー）に「学習を始める・クイック質問・今日の進捗・終了」のメニューを登録（制限モードでは使わない）
+func (m *MainApp) setupTray() {
+	desk, ok := m.app.(desktop.App)
+	if !ok || m.config.Kiosk {
+		return
+	}
+
+	quitItem := fyne.NewMenuItem("終了", m.quit)
+	quitItem.IsQuit = true
+	desk.SetSystemTrayMenu(fyne.NewMenu("StudyBuddy AI",
+		fyne.NewMenuItem("学習を始める", m.showStudyFromTray),
+		fyne.NewMenuItem("クイック質問", m.showCaptureFromTray),
+		fyne.NewMenuItem("今日の進捗", m.showTodayFromTray),
+		fyne.NewMenuItemSeparator(),
+		quitItem,
+	))
+	m.hasTray = true
+}
+
+// hideToTray 設定でタスクトレイに入れるなら、ウィンドウを隠してtrueを返す
+func (m *MainApp) hideToTray() bool {
+	if !m.hasTray || !m.config.UI.MinimizeToTray {
+		return false
+	}
+	m.window.Hide()
+	slog.Info("🪟 タスクトレイに入れました")
+	return true
+}
+
+// showStudyFromTray ウィンドウを表示して学習画面を開く
+func (m *MainApp) showStudyFromTray() {
+	m.window.Show()
+	m.window.RequestFocus()
+	if m.content != nil {
+		m.content.Select(m.studyTab)
+	}
+}
+
+// showCaptureFromTray クイック質問のウィンドウだけを開く（ほかのアプリを使っているときの入り口）
+// まだプロフィールを選んでいなければ、メインウィンドウを表示する
+func (m *MainApp) showCaptureFromTray() {
+	if m.currentUser == nil || m.content == nil {
+		m.window.Show()
+		m.window.RequestFocus()
+		return
+	}
+	m.showCaptureWindow()
+}
+
+// showTodayFromTray ウィンドウを表示して今日の学習時間・問題数・連続学習を知らせる
+func (m *MainApp) showTodayFromTray() {
+	m.window.Show()
+	m.window.RequestFocus()
+	if m.currentUser == nil {
+		return
+	}
+	text, err := m.todaySummary(time.Now())
+	if err != nil {
+		slog.Error("今日の進捗の集計エラー", "error", err)
+		m.ShowErrorDialog("今日の進捗", "今日の学習記録を集計できませんでした。")
+		return
+	}
+	m.ShowInfoDialog("📊 今日の進捗", text)
+}
+
+// todaySummary 今日の学習時間・問題数・正解数と連続学習の日数
+func (m *MainApp) todaySummary(now time.Time) (string, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	sessions, err := m.db.GetStudySessionsBetween(m.currentUser.ID, today, today.AddDate(0, 0, 1))
+	if err != nil {
+		return "", fmt.Errorf("セッション取得エラー: %w", err)
+	}
+	seconds, problems, correct := 0, 0, 0
+	for i := range sessions {
+		seconds += sessions[i].DurationSeconds()
+		problems += sessions[i].TotalProblems
+		correct += sessions[i].CorrectAnswers
+	}
+	if len(sessions) == 0 {
+		return "今日はまだ学習していません。「学習を始める」から始めましょう。", nil
+	}
+
+	text := fmt.Sprintf("学習時間: %d分\n解いた問題: %d問（正解 %d問）", seconds/60, problems, correct)
+	if streak, err := m.progressManager.GetStudyStreak(m.currentUser.ID); err == nil && streak.CurrentStreak > 0 {
+		text += fmt.Sprintf("\n🔥 連続%d日", streak.CurrentStreak)
+	}
+	return text, nil
+}
@@ -0,0 +1,90 @@
+package gui
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// trendRanges 学習の推移の表示期間（表示名 → 日数）
+var trendRanges = []struct {
+	label      string
+	days       int
+	bucketDays int // 1本の棒・1つの点にまとめる日数
+}{
+	{"7日", 7, 1},
+	{"30日", 30, 7},
+	{"90日", 90, 7},
+}
+
+// allSubjectsLabel 科目の絞り込みで全科目を表す項目
+const allSubjectsLabel = "すべての科目"
+
+// createTrendCard 正解率と学習時間の推移グラフのカードを作成（科目と期間で絞り込める）
+func (m *MainApp) createTrendCard() *widget.Card {
+	accuracyChart := NewLineChart(100, "%")
+	minutesChart := NewBarChart(0, "分")
+
+	subjectSelect := widget.NewSelect(append([]string{allSubjectsLabel}, m.config.OrderedSubjects()...), nil)
+	rangeLabels := make([]string, len(trendRanges))
+	for i, r := range trendRanges {
+		rangeLabels[i] = r.label
+	}
+	rangeRadio := widget.NewRadioGroup(rangeLabels, nil)
+	rangeRadio.Horizontal = true
+	rangeRadio.Required = true
+
+	update := func() {
+		subject := subjectSelect.Selected
+		if subject == allSubjectsLabel {
+			subject = ""
+		}
+		selected := trendRanges[0]
+		for _, r := range trendRanges {
+			if r.label == rangeRadio.Selected {
+				selected = r
+			}
+		}
+
+		points, err := m.progressManager.GetTrendPoints(m.currentUser.ID, subject, selected.days, selected.bucketDays)
+		if err != nil {
+			slog.Error("学習推移取得エラー", "error", err)
+			return
+		}
+
+		var accuracyLabels []string
+		var accuracies []float64
+		minutesLabels := make([]string, len(points))
+		minutes := make([]float64, len(points))
+		for i, point := range points {
+			label := point.Start.Format("1/2")
+			if selected.bucketDays > 1 {
+				label += "〜"
+			}
+			minutesLabels[i] = label
+			minutes[i] = point.StudyMinutes
+			// 問題を解いていない区間は正解率の線に含めない
+			if point.TotalProblems > 0 {
+				accuracyLabels = append(accuracyLabels, label)
+				accuracies = append(accuracies, point.AccuracyRate*100)
+			}
+		}
+		accuracyChart.SetData(accuracyLabels, accuracies)
+		minutesChart.SetData(minutesLabels, minutes)
+	}
+
+	subjectSelect.OnChanged = func(string) { update() }
+	rangeRadio.OnChanged = func(string) { update() }
+	subjectSelect.SetSelected(allSubjectsLabel)
+	rangeRadio.SetSelected(trendRanges[1].label)
+
+	content := container.NewVBox(
+		container.NewHBox(subjectSelect, rangeRadio),
+		widget.NewLabel("正解率"),
+		accuracyChart,
+		widget.NewLabel("学習時間（分）"),
+		minutesChart,
+	)
+	return widget.NewCard("📈 学習の推移", "", content)
+}
@@ -0,0 +1,89 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+)
+
+// showUsageStats あなたの利用統計を表示（このパソコンの記録だけから計算する）
+func (m *MainApp) showUsageStats() {
+	stats, err := m.progressManager.UsageStats(m.currentUser.ID, time.Now())
+	if err != nil {
+		m.ShowErrorDialog("エラー", fmt.Sprintf("利用統計を集計できませんでした: %v", err))
+		return
+	}
+
+	privacy := widget.NewLabel("🔒 この統計はこのパソコンの学習記録だけから計算しています。どこにも送信していません。")
+	privacy.Wrapping = fyne.TextWrapWord
+	summary := widget.NewLabel(usageSummaryText(stats))
+	summary.Wrapping = fyne.TextWrapWord
+	weeks := widget.NewLabel(usageWeeksText(stats))
+	features := widget.NewLabel(usageFeaturesText(stats))
+
+	content := container.NewVBox(
+		privacy,
+		widget.NewCard("学習のようす", "", summary),
+		widget.NewCard(fmt.Sprintf("週ごとの学習（直近%d週間）", progress.UsageWeeks), "", weeks),
+		widget.NewCard("よく使う機能", "", features),
+	)
+	popup := dialog.NewCustom("📊 あなたの利用統計", "閉じる", container.NewVScroll(content), m.window)
+	popup.Resize(fyne.NewSize(520, 600))
+	popup.Show()
+}
+
+// usageSummaryText 週あたりの回数・平均の長さ・よく学習する科目
+func usageSummaryText(stats *progress.UsageStats) string {
+	if stats.TotalSessions == 0 {
+		return fmt.Sprintf("直近%d週間は、アプリでの学習がまだありません。", progress.UsageWeeks)
+	}
+	lines := []string{
+		fmt.Sprintf("学習セッション: %d回（1週間あたり %.1f回）", stats.TotalSessions, stats.SessionsPerWeek),
+		fmt.Sprintf("1回の学習の長さ: 平均 %s", formatUsageDuration(stats.AverageSessionSeconds)),
+	}
+	if stats.FavoriteSubject != "" {
+		lines = append(lines, fmt.Sprintf("いちばん学習している科目: %s（%s）", stats.FavoriteSubject, formatUsageDuration(stats.FavoriteSubjectSeconds)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// usageWeeksText 週ごとの学習セッションの回数（新しい週が下）
+func usageWeeksText(stats *progress.UsageStats) string {
+	var b strings.Builder
+	for i, week := range stats.Weeks {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s〜　%s %d回", week.Start.Format("01/02"), strings.Repeat("■", week.Sessions), week.Sessions)
+	}
+	return b.String()
+}
+
+// usageFeaturesText 機能ごとの利用回数
+func usageFeaturesText(stats *progress.UsageStats) string {
+	features := stats.Features
+	return strings.Join([]string{
+		fmt.Sprintf("模擬テスト: %d回", features.Exams),
+		fmt.Sprintf("アプリ外の学習の記録: %d回", features.ManualLogs),
+		fmt.Sprintf("昨日の復習: %d問", features.ReviewQuizzes),
+		fmt.Sprintf("単語カードの復習: %d枚", features.FlashcardReviews),
+		fmt.Sprintf("学習日記: %d日", features.DiaryEntries),
+		fmt.Sprintf("問題バンク: %d問", features.BankProblems),
+	}, "\n")
+}
+
+// formatUsageDuration 学習時間の表示（1時間未満は分だけ）
+func formatUsageDuration(seconds int) string {
+	minutes := seconds / 60
+	if minutes < 60 {
+		return fmt.Sprintf("%d分", minutes)
+	}
+	return fmt.Sprintf("%d時間%d分", minutes/60, minutes%60)
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// level 今のログの出力レベル（設定画面から変えるとすぐに反映する）
+var level slog.LevelVar
+
+// Setup ログをdirのファイル（JSON形式・大きくなったら切り替え）と標準エラー出力に出す
+// （log/slogの既定のロガーにするため、logパッケージの出力もファイルに残る。ファイルは終了まで開いたまま）
+func Setup(dir, levelName string) error {
+	file, err := openRotatingFile(dir, logFileName, maxLogFileSize, maxLogBackups)
+	if err != nil {
+		return fmt.Errorf("ログファイル作成エラー: %w", err)
+	}
+	SetLevel(levelName)
+
+	options := &slog.HandlerOptions{Level: &level}
+	slog.SetDefault(slog.New(fanoutHandler{
+		slog.NewJSONHandler(file, options),
+		slog.NewTextHandler(os.Stderr, options),
+	}))
+	return nil
+}
+
+// SetLevel ログの出力レベルを変える（"debug" | "info" | "warn" | "error"。それ以外ならinfo）
+func SetLevel(levelName string) {
+	level.Set(ParseLevel(levelName))
+}
+
+// ParseLevel 設定のログの出力レベル
+func ParseLevel(levelName string) slog.Level {
+	switch levelName {
+	case config.LogLevelDebug:
+		return slog.LevelDebug
+	case config.LogLevelWarn:
+		return slog.LevelWarn
+	case config.LogLevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// fanoutHandler 複数の出力先に同じログを出す
+type fanoutHandler []slog.Handler
+
+func (h fanoutHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	for _, handler := range h {
+		if handler.Enabled(ctx, l) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h fanoutHandler) Handle(ctx context.Context, record slog.Record) error {
+	var firstErr error
+	for _, handler := range h {
+		if !handler.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, record.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (h fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(fanoutHandler, len(h))
+	for i, handler := range h {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (h fanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make(fanoutHandler, len(h))
+	for i, handler := range h {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return handlers
+}
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ログファイルの切り替え
+const (
+	logFileName    = "studybuddy.log"
+	maxLogFileSize = 5 * 1024 * 1024 // これを超えたら新しいファイルにする
+	maxLogBackups  = 5               // 残しておく古いファイル（studybuddy.log.1 〜 .5）
+)
+
+// rotatingFile 大きくなったら新しいファイルに切り替えるログファイル
+type rotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	size    int64
+	maxSize int64
+	backups int
+}
+
+// openRotatingFile dirのログファイルを追記で開く（dirがなければ作る）
+func openRotatingFile(dir, name string, maxSize int64, backups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	r := &rotatingFile{path: filepath.Join(dir, name), maxSize: maxSize, backups: backups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open ログファイルを追記で開く
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Write ログを書き込む（書くと大きさを超えるなら、先に新しいファイルに切り替える）
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, fmt.Errorf("ログファイル切り替えエラー: %w", err)
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate 今のファイルを .1 に、.1 を .2 に…とずらし、最も古いファイルを消して新しいファイルを開く
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	_ = os.Remove(r.backupPath(r.backups))
+	for i := r.backups - 1; i >= 1; i-- {
+		if err := os.Rename(r.backupPath(i), r.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if r.backups > 0 {
+		if err := os.Rename(r.path, r.backupPath(1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// backupPath i番目に新しい古いファイルのパス
+func (r *rotatingFile) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", r.path, i)
+}
+
+// Close ログファイルを閉じる
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFileKeepsBackups(t *testing.T) {
+	dir := t.TempDir()
+	file, err := openRotatingFile(dir, "test.log", 10, 2)
+	if err != nil {
+		t.Fatalf("ログファイル作成エラー: %v", err)
+	}
+	t.Cleanup(func() { _ = file.Close() })
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("書き込みエラー: %v", err)
+		}
+	}
+
+	want := map[string]string{
+		"test.log":   "fourth\n",
+		"test.log.1": "third\n",
+		"test.log.2": "second\n",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s 読み込みエラー: %v", name, err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", name, data, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "test.log.3")); !os.IsNotExist(err) {
+		t.Error("残す数を超えた古いファイルは消すはず")
+	}
+}
+
+func TestRotatingFileAppends(t *testing.T) {
+	dir := t.TempDir()
+	for _, line := range []string{"a\n", "b\n"} {
+		file, err := openRotatingFile(dir, "test.log", 1024, 1)
+		if err != nil {
+			t.Fatalf("ログファイル作成エラー: %v", err)
+		}
+		_, _ = file.Write([]byte(line))
+		_ = file.Close()
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "test.log"))
+	if !strings.HasPrefix(string(data), "a\nb\n") {
+		t.Errorf("起動し直しても追記するはず: %q", data)
+	}
+}
+
+func TestTail(t *testing.T) {
+	dir := t.TempDir()
+	if text, err := Tail(dir, 3); err != nil || text != "" {
+		t.Errorf("ログがないとき = %q, %v", text, err)
+	}
+
+	path := filepath.Join(dir, logFileName)
+	if err := os.WriteFile(path+".1", []byte("a\nb\nc\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("d\ne\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if text, err := Tail(dir, 3); err != nil || text != "c\nd\ne" {
+		t.Errorf("最後の3行 = %q, %v, want 1つ前のファイルから補う", text, err)
+	}
+	if text, _ := Tail(dir, 1); text != "e" {
+		t.Errorf("最後の1行 = %q", text)
+	}
+}
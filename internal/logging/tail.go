@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Tail ログのフォルダーの最新のログの最後のn行（今のファイルが短ければ1つ前のファイルから補う。ログがなければ空）
+func Tail(dir string, n int) (string, error) {
+	path := filepath.Join(dir, logFileName)
+	var lines []string
+	for _, name := range []string{path + ".1", path} { // 古い順
+		data, err := os.ReadFile(name)
+		if os.IsNotExist(err) || len(data) == 0 {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, strings.Split(strings.TrimRight(string(data), "\n"), "\n")...)
+		if len(lines) > n {
+			lines = lines[len(lines)-n:]
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
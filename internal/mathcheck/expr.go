@@ -0,0 +1,355 @@
+package mathcheck
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxDegree 扱える多項式の次数（二次方程式まで）
+const maxDegree = 2
+
+// poly 1文字の変数についての多項式（c[i]がi次の係数）
+type poly [maxDegree + 1]float64
+
+// constant 定数の多項式
+func constant(v float64) poly {
+	return poly{v}
+}
+
+// degree 多項式の次数（0の多項式は0次）
+func (p poly) degree() int {
+	for i := maxDegree; i > 0; i-- {
+		if p[i] != 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// at 変数に値を代入したときの多項式の値
+func (p poly) at(x float64) float64 {
+	return p[0] + p[1]*x + p[2]*x*x
+}
+
+func (p poly) add(q poly) poly {
+	for i := range p {
+		p[i] += q[i]
+	}
+	return p
+}
+
+func (p poly) scale(k float64) poly {
+	for i := range p {
+		p[i] *= k
+	}
+	return p
+}
+
+func (p poly) mul(q poly) (poly, error) {
+	if p.degree()+q.degree() > maxDegree {
+		return poly{}, fmt.Errorf("%d次を超える式には対応していません", maxDegree)
+	}
+	var r poly
+	for i := 0; i <= p.degree(); i++ {
+		for j := 0; j <= q.degree(); j++ {
+			r[i+j] += p[i] * q[j]
+		}
+	}
+	return r, nil
+}
+
+func (p poly) div(q poly) (poly, error) {
+	if q.degree() > 0 {
+		return poly{}, fmt.Errorf("文字でわる式には対応していません")
+	}
+	if q[0] == 0 {
+		return poly{}, fmt.Errorf("0でわっています")
+	}
+	return p.scale(1 / q[0]), nil
+}
+
+func (p poly) pow(n int) (poly, error) {
+	// 数の累乗は一度に計算する（「2^999999999」のような大きな指数でも止まらない）
+	if p.degree() == 0 {
+		v := math.Pow(p[0], float64(n))
+		if math.IsInf(v, 0) {
+			return poly{}, fmt.Errorf("計算結果が大きすぎます")
+		}
+		return constant(v), nil
+	}
+	if n > maxDegree {
+		return poly{}, fmt.Errorf("%d次を超える式には対応していません", maxDegree)
+	}
+	r := constant(1)
+	for range n {
+		var err error
+		if r, err = r.mul(p); err != nil {
+			return poly{}, err
+		}
+	}
+	return r, nil
+}
+
+// normalize 全角文字や数学記号を計算用の記号にそろえる
+func normalize(s string) string {
+	replacer := strings.NewReplacer(
+		"×", "*", "÷", "/", "−", "-", "－", "-", "＋", "+", "＝", "=",
+		"（", "(", "）", ")", "²", "^2", "³", "^3", "．", ".", "＾", "^",
+	)
+	s = replacer.Replace(s)
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '０' && r <= '９':
+			return '0' + (r - '０')
+		case r >= 'ａ' && r <= 'ｚ':
+			return 'a' + (r - 'ａ')
+		case r >= 'Ａ' && r <= 'Ｚ':
+			return 'A' + (r - 'Ａ')
+		case r == '　':
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// parser 四則演算・累乗・かっこ・1文字の変数を含む式の構文解析
+type parser struct {
+	src      []rune
+	pos      int
+	variable rune // 式に出てきた変数（まだ出てきていなければ0）
+}
+
+// parseExpression 式を多項式として解析（変数は1種類まで）
+func parseExpression(expr string, variable rune) (poly, rune, error) {
+	p := &parser{src: []rune(normalize(expr)), variable: variable}
+	result, err := p.parseSum()
+	if err != nil {
+		return poly{}, 0, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.src) {
+		return poly{}, 0, fmt.Errorf("式を解析できません: %q", string(p.src[p.pos:]))
+	}
+	return result, p.variable, nil
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() rune {
+	p.skipSpaces()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// parseSum 項の和と差
+func (p *parser) parseSum() (poly, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return poly{}, err
+	}
+	for {
+		switch p.peek() {
+		case '+':
+			p.pos++
+			right, err := p.parseProduct()
+			if err != nil {
+				return poly{}, err
+			}
+			left = left.add(right)
+		case '-':
+			p.pos++
+			right, err := p.parseProduct()
+			if err != nil {
+				return poly{}, err
+			}
+			left = left.add(right.scale(-1))
+		default:
+			return left, nil
+		}
+	}
+}
+
+// parseProduct 因数の積と商（「2x」「3(x+1)」のような記号の省略にも対応）
+func (p *parser) parseProduct() (poly, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return poly{}, err
+	}
+	for {
+		c := p.peek()
+		switch {
+		case c == '*' || c == '/':
+			p.pos++
+			right, err := p.parseUnary()
+			if err != nil {
+				return poly{}, err
+			}
+			if c == '*' {
+				left, err = left.mul(right)
+			} else {
+				left, err = left.div(right)
+			}
+			if err != nil {
+				return poly{}, err
+			}
+		case c == '(' || isVariable(c):
+			right, err := p.parsePower()
+			if err != nil {
+				return poly{}, err
+			}
+			if left, err = left.mul(right); err != nil {
+				return poly{}, err
+			}
+		default:
+			return left, nil
+		}
+	}
+}
+
+// parseUnary 符号
+func (p *parser) parseUnary() (poly, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		v, err := p.parseUnary()
+		return v.scale(-1), err
+	case '+':
+		p.pos++
+		return p.parseUnary()
+	}
+	return p.parsePower()
+}
+
+// parsePower 累乗（指数は0以上の整数のみ）
+func (p *parser) parsePower() (poly, error) {
+	base, err := p.parseAtom()
+	if err != nil {
+		return poly{}, err
+	}
+	if p.peek() != '^' {
+		return base, nil
+	}
+	p.pos++
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return poly{}, err
+	}
+	n := exponent[0]
+	if exponent.degree() > 0 || n < 0 || n != math.Trunc(n) {
+		return poly{}, fmt.Errorf("指数は0以上の整数である必要があります")
+	}
+	if n > math.MaxInt32 {
+		return poly{}, fmt.Errorf("指数が大きすぎます")
+	}
+	return base.pow(int(n))
+}
+
+// parseAtom 数・変数・かっこ
+func (p *parser) parseAtom() (poly, error) {
+	c := p.peek()
+	switch {
+	case c == '(':
+		p.pos++
+		v, err := p.parseSum()
+		if err != nil {
+			return poly{}, err
+		}
+		if p.peek() != ')' {
+			return poly{}, fmt.Errorf("かっこが閉じていません")
+		}
+		p.pos++
+		return v, nil
+	case isVariable(c):
+		if p.variable != 0 && p.variable != c {
+			return poly{}, fmt.Errorf("文字が2種類以上あります: %c, %c", p.variable, c)
+		}
+		p.variable = c
+		p.pos++
+		return poly{0, 1}, nil
+	case c >= '0' && c <= '9' || c == '.':
+		start := p.pos
+		for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		v, err := strconv.ParseFloat(string(p.src[start:p.pos]), 64)
+		if err != nil {
+			return poly{}, fmt.Errorf("数を読み取れません: %w", err)
+		}
+		return constant(v), nil
+	case c == 0:
+		return poly{}, fmt.Errorf("式が途中で終わっています")
+	}
+	return poly{}, fmt.Errorf("式に使えない文字です: %c", c)
+}
+
+// isVariable 変数として扱う文字（半角英字1文字）
+func isVariable(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// Eval 数の式を計算（四則演算・累乗・かっこ。×÷や全角数字も可）
+func Eval(expr string) (float64, error) {
+	v, variable, err := parseExpression(expr, 0)
+	if err != nil {
+		return 0, err
+	}
+	if variable != 0 {
+		return 0, fmt.Errorf("文字を含む式は計算できません: %c", variable)
+	}
+	return v[0], nil
+}
+
+// Solve 1文字の一次方程式・二次方程式を解く（実数解を小さい順に返す）
+func Solve(equation string) (variable rune, roots []float64, err error) {
+	left, right, found := strings.Cut(normalize(equation), "=")
+	if !found || strings.Contains(right, "=") {
+		return 0, nil, fmt.Errorf("方程式には「=」が1つ必要です")
+	}
+	l, variable, err := parseExpression(left, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	r, variable, err := parseExpression(right, variable)
+	if err != nil {
+		return 0, nil, err
+	}
+	if variable == 0 {
+		return 0, nil, fmt.Errorf("方程式に文字がありません")
+	}
+
+	p := l.add(r.scale(-1))
+	switch p.degree() {
+	case 1:
+		return variable, []float64{-p[0] / p[1]}, nil
+	case 2:
+		a, b, c := p[2], p[1], p[0]
+		d := b*b - 4*a*c
+		if d < -epsilon {
+			return variable, nil, nil
+		}
+		if math.Abs(d) <= epsilon {
+			return variable, []float64{-b / (2 * a)}, nil
+		}
+		x1 := (-b - math.Sqrt(d)) / (2 * a)
+		x2 := (-b + math.Sqrt(d)) / (2 * a)
+		return variable, []float64{min(x1, x2), max(x1, x2)}, nil
+	}
+	return variable, nil, fmt.Errorf("文字が消えてしまう方程式は解けません")
+}
+
+// TriangleRemainingAngle 三角形の2つの角から残りの角を求める
+func TriangleRemainingAngle(a, b float64) (float64, error) {
+	if a <= 0 || b <= 0 || a+b >= 180 {
+		return 0, fmt.Errorf("三角形の角として成り立ちません: %g度, %g度", a, b)
+	}
+	return 180 - a - b, nil
+}
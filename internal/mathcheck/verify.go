@@ -0,0 +1,347 @@
+package mathcheck
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// epsilon 計算結果を同じ値とみなす誤差
+const epsilon = 1e-6
+
+// Verdict 答えの検証結果
+type Verdict int
+
+const (
+	// Unverifiable 対応していない形式の問題で、計算による確認ができない
+	Unverifiable Verdict = iota
+	// Correct 計算した答えと一致
+	Correct
+	// Incorrect 計算した答えと一致しない
+	Incorrect
+)
+
+// Result 答えの検証結果と、計算で求めた答え
+type Result struct {
+	Verdict  Verdict
+	Expected string // 計算で求めた答え（検証できたときのみ）
+}
+
+var (
+	// numberPattern 答えに含まれる数（負の数・小数・分数）
+	numberPattern = regexp.MustCompile(`-?\d+(?:\.\d+)?(?:/\d+(?:\.\d+)?)?`)
+	// equationPattern 問題文中の方程式の候補（1行のうち、半角の数・文字・記号だけでできた部分）
+	equationPattern = regexp.MustCompile(`[0-9a-zA-Z+\-*/^(). \t]+=[0-9a-zA-Z+\-*/^(). \t]+`)
+	// expressionWithVariablePattern 問題文中の、文字を含むかもしれない式の候補
+	expressionWithVariablePattern = regexp.MustCompile(`[0-9a-zA-Z+\-*/^(). \t]+`)
+	// expressionPattern 問題文中の計算式の候補
+	expressionPattern = regexp.MustCompile(`[0-9+\-*/^(). \t]+`)
+	// askedValuePattern 「7 + 8 = ?」のように計算の答えを問う部分
+	askedValuePattern = regexp.MustCompile(`=\s*[?？]`)
+	// anglePattern 問題文中の角度
+	anglePattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*(?:度|°)`)
+	// equalAnglesPattern 「角A=角C=45度」のように等しい2つの角を1つの値で表した部分
+	equalAnglesPattern = regexp.MustCompile(`角[A-Z]\s*=\s*角[A-Z]\s*=\s*(\d+(?:\.\d+)?)\s*(?:度|°)`)
+)
+
+// VerifyAnswer 問題文から計算で答えを求め、正解とされた選択肢と一致するか調べる
+// （方程式・計算問題・三角形の角の問題に対応。それ以外はUnverifiable）
+func VerifyAnswer(description, answer string) Result {
+	description = normalize(description)
+	answer = normalize(answer)
+	if strings.ContainsAny(answer, "√±") || strings.ContainsAny(description, "√") {
+		return Result{Verdict: Unverifiable}
+	}
+
+	if strings.Contains(description, "三角形") && strings.Contains(strings.ReplaceAll(description, "三角形", ""), "角") {
+		if expected, ok := triangleAngle(description); ok {
+			return compare([]float64{expected}, answer)
+		}
+		return Result{Verdict: Unverifiable}
+	}
+
+	if roots, ok := equationRoots(description); ok {
+		return compare(roots, answer)
+	}
+
+	if strings.Contains(description, "計算") || askedValuePattern.MatchString(description) {
+		if value, ok := arithmetic(description); ok {
+			return compare([]float64{value}, answer)
+		}
+	}
+
+	return Result{Verdict: Unverifiable}
+}
+
+// equationRoots 問題文にある1文字の方程式を解く（文字を含む式がちょうど1つのときだけ）
+// 「x + 5 = 12 のとき、2x の値」のように文字を含む式の値を問う問題では、解を代入した値を返す
+func equationRoots(description string) ([]float64, bool) {
+	var candidates [][]int
+	for _, loc := range equationPattern.FindAllStringIndex(description, -1) {
+		if strings.IndexFunc(description[loc[0]:loc[1]], isVariable) >= 0 {
+			candidates = append(candidates, loc)
+		}
+	}
+	// 「y = 2x + 3 で x = 4 のとき」のような、式が複数ある問題は対象外
+	if len(candidates) != 1 {
+		return nil, false
+	}
+	equation := description[candidates[0][0]:candidates[0][1]]
+	if isSubstitution(equation) {
+		return nil, false
+	}
+
+	variable, roots, err := Solve(equation)
+	if err != nil || len(roots) == 0 {
+		return nil, false
+	}
+	roots, ok := selectRoots(description, roots)
+	if !ok {
+		return nil, false
+	}
+
+	asked, ok := askedExpression(description[:candidates[0][0]]+"。"+description[candidates[0][1]:], variable)
+	if !ok {
+		return nil, false
+	}
+	values := make([]float64, len(roots))
+	for i, root := range roots {
+		values[i] = asked.at(root)
+	}
+	slices.Sort(values)
+	return slices.CompactFunc(values, func(a, b float64) bool { return math.Abs(a-b) <= epsilon }), true
+}
+
+// askedExpression 方程式以外の部分から、値を求める文字の式を探す（見つからなければ文字そのもの）
+// 求める式が読み取れないときや、別の文字の値を問う問題はfalse
+func askedExpression(rest string, variable rune) (poly, bool) {
+	asked := poly{0, 1}
+	found := false
+	for _, candidate := range expressionWithVariablePattern.FindAllString(rest, -1) {
+		if strings.IndexFunc(candidate, isVariable) < 0 {
+			continue
+		}
+		p, v, err := parseExpression(candidate, variable)
+		if err != nil || v != variable || found {
+			return poly{}, false
+		}
+		asked, found = p, true
+	}
+	return asked, true
+}
+
+// selectRoots 「小さい方」「正の解」のように解の1つを答える問題では、その解を選ぶ
+func selectRoots(description string, roots []float64) ([]float64, bool) {
+	if len(roots) < 2 {
+		return roots, true
+	}
+	pick := func(keep func(float64) bool) ([]float64, bool) {
+		var selected []float64
+		for _, root := range roots {
+			if keep(root) {
+				selected = append(selected, root)
+			}
+		}
+		return selected, len(selected) == 1
+	}
+	switch {
+	case strings.Contains(description, "小さい方"):
+		return roots[:1], true
+	case strings.Contains(description, "大きい方"):
+		return roots[len(roots)-1:], true
+	case strings.Contains(description, "正の解"):
+		return pick(func(root float64) bool { return root > 0 })
+	case strings.Contains(description, "負の解"):
+		return pick(func(root float64) bool { return root < 0 })
+	}
+	return roots, true
+}
+
+// isSubstitution 「x = 4」のような、文字に値を代入するだけの式か
+func isSubstitution(equation string) bool {
+	left, right, _ := strings.Cut(equation, "=")
+	l, _, errL := parseExpression(left, 0)
+	r, _, errR := parseExpression(right, 0)
+	if errL != nil || errR != nil {
+		return false
+	}
+	return l == (poly{0, 1}) && r.degree() == 0 || r == (poly{0, 1}) && l.degree() == 0
+}
+
+// arithmetic 問題文にある数の計算式を計算（文字を含む問題や、式が複数ある問題は対象外）
+func arithmetic(description string) (float64, bool) {
+	for _, r := range description {
+		if isVariable(r) {
+			return 0, false
+		}
+	}
+
+	var values []float64
+	for _, candidate := range expressionPattern.FindAllString(description, -1) {
+		candidate = strings.TrimSpace(candidate)
+		if !strings.ContainsAny(strings.TrimLeft(candidate, "-("), "+-*/^") {
+			continue
+		}
+		value, err := Eval(candidate)
+		if err != nil {
+			continue
+		}
+		values = append(values, value)
+	}
+	if len(values) != 1 {
+		return 0, false
+	}
+	return values[0], true
+}
+
+// triangleAngle 三角形の角の問題で、求める角の大きさを計算
+func triangleAngle(description string) (float64, bool) {
+	// 外角や多角形の問題は対象外
+	if strings.Contains(description, "外角") || strings.Contains(description, "角形の内角") {
+		return 0, false
+	}
+
+	// 「角A=角C=45度」のような二等辺三角形の底角
+	if m := equalAnglesPattern.FindStringSubmatch(description); m != nil {
+		base, _ := strconv.ParseFloat(m[1], 64)
+		if base <= 0 || base >= 90 {
+			return 0, false
+		}
+		return 180 - 2*base, true
+	}
+
+	var angles []float64
+	var positions []int // 問題文中で角度が書かれている位置
+	for _, m := range anglePattern.FindAllStringSubmatchIndex(description, -1) {
+		angle, _ := strconv.ParseFloat(description[m[2]:m[3]], 64)
+		if angle == 180 {
+			continue // 「内角の和は180度」のような説明
+		}
+		angles = append(angles, angle)
+		positions = append(positions, m[0])
+	}
+
+	switch {
+	case strings.Contains(description, "二等辺三角形") && len(angles) == 1:
+		given := lastAngleName(description[:positions[0]])
+		switch askedAngleName(description, positions[0]) {
+		case "頂角":
+			return 180 - 2*angles[0], given == "底角" && angles[0] < 90
+		case "底角":
+			return (180 - angles[0]) / 2, given == "頂角" && angles[0] < 180
+		}
+	case strings.Contains(description, "直角三角形") && len(angles) == 1 && angles[0] != 90:
+		return 90 - angles[0], angles[0] < 90
+	case len(angles) == 2:
+		remaining, err := TriangleRemainingAngle(angles[0], angles[1])
+		return remaining, err == nil
+	}
+	return 0, false
+}
+
+// angleNames 二等辺三角形の角の呼び方
+var angleNames = []string{"頂角", "底角"}
+
+// lastAngleName 文中で最後に出てくる角の呼び方（なければ空文字列）
+func lastAngleName(text string) string {
+	name, last := "", -1
+	for _, n := range angleNames {
+		if i := strings.LastIndex(text, n); i > last {
+			name, last = n, i
+		}
+	}
+	return name
+}
+
+// firstAngleName 文中で最初に出てくる角の呼び方（なければ空文字列）
+func firstAngleName(text string) string {
+	name, first := "", len(text)
+	for _, n := range angleNames {
+		if i := strings.Index(text, n); i >= 0 && i < first {
+			name, first = n, i
+		}
+	}
+	return name
+}
+
+// askedAngleName 求める角の呼び方を、「を求め」の直前か「とき」のあとの位置から判断する
+// （どちらもなければ、与えられた角度よりあとに出てくる角）
+func askedAngleName(description string, anglePos int) string {
+	if i := strings.Index(description, "を求め"); i >= 0 {
+		return lastAngleName(description[:i])
+	}
+	if i := strings.LastIndex(description, "とき"); i >= 0 {
+		return firstAngleName(description[i:])
+	}
+	return firstAngleName(description[anglePos:])
+}
+
+// compare 計算した答えと選択肢に書かれた数が一致するか調べる
+func compare(expected []float64, answer string) Result {
+	result := Result{Expected: formatValues(expected)}
+
+	type number struct {
+		value     float64
+		tolerance float64 // 小数で書かれた答えは、四捨五入した桁までの誤差を認める
+	}
+	var numbers []number
+	for _, s := range numberPattern.FindAllString(answer, -1) {
+		value, err := Eval(s)
+		if err != nil {
+			return Result{Verdict: Unverifiable}
+		}
+		tolerance := epsilon
+		if _, decimals, found := strings.Cut(s, "."); found && !strings.Contains(s, "/") {
+			tolerance = 0.5 * math.Pow(10, -float64(len(decimals)))
+		}
+		numbers = append(numbers, number{value, tolerance})
+	}
+	if len(numbers) == 0 {
+		return Result{Verdict: Unverifiable}
+	}
+
+	slices.SortFunc(numbers, func(a, b number) int { return cmp.Compare(a.value, b.value) })
+	numbers = slices.CompactFunc(numbers, func(a, b number) bool { return math.Abs(a.value-b.value) <= epsilon })
+	if len(numbers) == 1 && len(expected) > 1 {
+		// 解の1つだけを答える問題（どちらの解かは問題文から判断できない）
+		for _, v := range expected {
+			if math.Abs(numbers[0].value-v) <= numbers[0].tolerance+epsilon {
+				return Result{Verdict: Unverifiable}
+			}
+		}
+		result.Verdict = Incorrect
+		return result
+	}
+	if len(numbers) != len(expected) {
+		result.Verdict = Incorrect
+		return result
+	}
+	for i, n := range numbers {
+		if math.Abs(n.value-expected[i]) > n.tolerance+epsilon {
+			result.Verdict = Incorrect
+			return result
+		}
+	}
+	result.Verdict = Correct
+	return result
+}
+
+// formatValues 計算した答えを表示用の文字列にする
+func formatValues(values []float64) string {
+	texts := make([]string, len(values))
+	for i, v := range values {
+		if math.Abs(v-math.Round(v)) <= epsilon {
+			texts[i] = fmt.Sprintf("%d", int(math.Round(v)))
+		} else {
+			texts[i] = strconv.FormatFloat(v, 'f', -1, 64)
+			if len(texts[i]) > 8 {
+				texts[i] = strconv.FormatFloat(v, 'f', 4, 64)
+			}
+		}
+	}
+	return strings.Join(texts, ", ")
+}
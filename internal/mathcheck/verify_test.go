@@ -0,0 +1,72 @@
+package mathcheck
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyAnswer(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		answer      string
+		want        Verdict
+	}{
+		// 三角形の角
+		{"頂角から底角", "頂角が40度の二等辺三角形の底角を求めよ。", "70度", Correct},
+		{"頂角から底角（誤り）", "頂角が40度の二等辺三角形の底角を求めよ。", "40度", Incorrect},
+		{"底角から頂角", "二等辺三角形で、底角が70度のとき頂角を求めよ。", "40度", Correct},
+		{"底角から頂角（誤り）", "二等辺三角形で、底角が70度のとき頂角を求めよ。", "55度", Incorrect},
+		{"求める角が先に書かれている", "二等辺三角形の頂角を求めよ。ただし底角は50度とする。", "80度", Correct},
+		{"何度ですかで問う", "二等辺三角形の頂角が100度です。1つの底角は何度ですか。", "40度", Correct},
+		{"等しい2つの角", "三角形ABCで角A=角C=45度のとき、角Bの大きさは？", "90度", Correct},
+		{"直角三角形", "直角三角形の1つの鋭角が30度のとき、もう1つの鋭角は？", "60度", Correct},
+		{"2つの角から残りの角", "三角形の2つの角が50度と60度のとき、残りの角を求めよ。", "70度", Correct},
+		{"2つの角から残りの角（誤り）", "三角形の2つの角が50度と60度のとき、残りの角を求めよ。", "80度", Incorrect},
+		{"外角は対象外", "三角形の外角が120度のとき、となりの内角は？", "60度", Unverifiable},
+
+		// 方程式
+		{"一次方程式", "方程式 2x + 3 = 7 を解きなさい。", "x = 2", Correct},
+		{"一次方程式（誤り）", "方程式 2x + 3 = 7 を解きなさい。", "x = 5", Incorrect},
+		{"二次方程式", "x^2 - 5x + 6 = 0 を解きなさい。", "x = 2, 3", Correct},
+		{"二次方程式の小さい方の解", "x^2 - 5x + 6 = 0 の小さい方の解を求めよ。", "x = 3", Incorrect},
+		{"文字の式の値", "x + 5 = 12 のとき、2x の値を求めよ。", "14", Correct},
+		{"文字の式の値（誤り）", "x + 5 = 12 のとき、2x の値を求めよ。", "7", Incorrect},
+		{"別の文字の値", "x + 5 = 12 のとき、y の値を求めよ。", "7", Unverifiable},
+		{"代入するだけの式は対象外", "y = 2x + 3 で x = 4 のとき、y の値は？", "11", Unverifiable},
+
+		// 計算
+		{"計算", "次の計算をしなさい。(3 + 5) × 2", "16", Correct},
+		{"計算（誤り）", "次の計算をしなさい。(3 + 5) × 2", "13", Incorrect},
+		{"答えを問う式", "7 + 8 = ?", "15", Correct},
+		{"小数の四捨五入", "次の計算をしなさい。10 ÷ 3", "3.33", Correct},
+		{"計算式でない問題", "日本の首都はどこですか。", "東京", Unverifiable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := VerifyAnswer(tt.description, tt.answer)
+			if got.Verdict != tt.want {
+				t.Errorf("VerifyAnswer(%q, %q) = %+v, want %v", tt.description, tt.answer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalLargeExponent(t *testing.T) {
+	if v, err := Eval("2^10"); err != nil || v != 1024 {
+		t.Errorf("Eval(2^10) = %v, %v", v, err)
+	}
+	// 大きな指数でも止まらずにエラーか結果を返す
+	if _, err := Eval("2^999999999"); err == nil || !strings.Contains(err.Error(), "大きすぎ") {
+		t.Errorf("Eval(2^999999999) のエラー = %v", err)
+	}
+	if v, err := Eval("1^999999999"); err != nil || v != 1 {
+		t.Errorf("Eval(1^999999999) = %v, %v", v, err)
+	}
+	if _, err := Eval("2^99999999999"); err == nil {
+		t.Error("大きすぎる指数はエラーになるはず")
+	}
+	if _, _, err := Solve("x^999999999 = 1"); err == nil {
+		t.Error("3次以上の方程式はエラーになるはず")
+	}
+}
@@ -0,0 +1,233 @@
+package pack
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/curriculum"
+)
+
+// FileExtension 内容パックのファイルの拡張子（中身はJSONファイルをまとめたzip）
+const FileExtension = ".sbpack"
+
+// FormatVersion 内容パックの形式の版（これより新しい版のパックは読み込まない）
+const FormatVersion = 1
+
+// 内容パックのzipに入れるファイル（manifest.json以外は、含める内容があるときだけ入れる）
+const (
+	manifestFile   = "manifest.json"
+	curriculumFile = "curriculum.json"
+	problemsFile   = "problems.json"
+	flashcardsFile = "flashcards.json"
+)
+
+// 読み込む内容パックの大きさの上限
+const (
+	maxFileSize  = 64 << 20 // ファイル全体
+	maxEntrySize = 32 << 20 // zipの中の1つのファイル（展開後）
+)
+
+// ErrUnsupportedVersion このアプリより新しい版の内容パック
+var ErrUnsupportedVersion = errors.New("このアプリより新しい形式の内容パックです。アプリを更新してから読み込んでください")
+
+// Manifest 内容パックの説明
+type Manifest struct {
+	Format      int       `json:"format"`
+	Name        string    `json:"name"`
+	Author      string    `json:"author,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Card 単語カードの1枚（表面・裏面・例文などのヒント）
+type Card struct {
+	Front string `json:"front"`
+	Back  string `json:"back"`
+	Hint  string `json:"hint,omitempty"`
+}
+
+// Deck 単語カードのデッキ（種類はai.FlashcardVocabかai.FlashcardKanji）
+type Deck struct {
+	Kind  string `json:"kind"`
+	Cards []Card `json:"cards"`
+}
+
+// Pack 先生が生徒に配る内容パック（学習範囲・AIを使えないときの問題・単語カード）
+type Pack struct {
+	Manifest   Manifest
+	Curriculum curriculum.Curriculum // 加える学習範囲の単元（nilなら含めない）
+	Problems   []ai.OfflineProblem
+	Decks      []Deck
+}
+
+// Validate 内容パックの妥当性チェック
+func (p *Pack) Validate() error {
+	if strings.TrimSpace(p.Manifest.Name) == "" {
+		return errors.New("内容パックの名前がありません")
+	}
+	if err := p.Curriculum.Validate(); err != nil {
+		return fmt.Errorf("学習範囲: %w", err)
+	}
+	for i, problem := range p.Problems {
+		if err := problem.Validate(); err != nil {
+			return fmt.Errorf("%d問目: %w", i+1, err)
+		}
+	}
+	for _, deck := range p.Decks {
+		if deck.Kind != ai.FlashcardVocab && deck.Kind != ai.FlashcardKanji {
+			return fmt.Errorf("無効な単語カードの種類: %s", deck.Kind)
+		}
+		for i, card := range deck.Cards {
+			if strings.TrimSpace(card.Front) == "" || strings.TrimSpace(card.Back) == "" {
+				return fmt.Errorf("単語カード（%s）の%d枚目に表面か裏面がありません", deck.Kind, i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// CardCount 単語カードの枚数
+func (p *Pack) CardCount() int {
+	count := 0
+	for _, deck := range p.Decks {
+		count += len(deck.Cards)
+	}
+	return count
+}
+
+// TopicCount 学習範囲の単元の数
+func (p *Pack) TopicCount() int {
+	count := 0
+	for _, subjects := range p.Curriculum {
+		for _, topics := range subjects {
+			count += len(topics)
+		}
+	}
+	return count
+}
+
+// Write 内容パックをzipに書き出す（中のJSONファイルは、先生が展開して直接編集することもできる）
+func Write(w io.Writer, p *Pack) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	manifest := p.Manifest
+	manifest.Format = FormatVersion
+
+	zw := zip.NewWriter(w)
+	entries := []struct {
+		name    string
+		value   any
+		include bool
+	}{
+		{manifestFile, manifest, true},
+		{curriculumFile, p.Curriculum, len(p.Curriculum) > 0},
+		{problemsFile, p.Problems, len(p.Problems) > 0},
+		{flashcardsFile, p.Decks, len(p.Decks) > 0},
+	}
+	for _, entry := range entries {
+		if !entry.include {
+			continue
+		}
+		data, err := json.MarshalIndent(entry.value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("内容パックのデータ変換エラー: %w", err)
+		}
+		fw, err := zw.Create(entry.name)
+		if err != nil {
+			return fmt.Errorf("内容パックの書き出しエラー: %w", err)
+		}
+		if _, err := fw.Write(data); err != nil {
+			return fmt.Errorf("内容パックの書き出しエラー: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("内容パックの書き出しエラー: %w", err)
+	}
+	return nil
+}
+
+// Read 内容パックを読み込んで検証する
+func Read(r io.ReaderAt, size int64) (*Pack, error) {
+	if size > maxFileSize {
+		return nil, fmt.Errorf("内容パックが大きすぎます（%dMBまで）", maxFileSize>>20)
+	}
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("内容パックの形式が正しくありません: %w", err)
+	}
+
+	p := &Pack{}
+	targets := map[string]any{
+		manifestFile:   &p.Manifest,
+		curriculumFile: &p.Curriculum,
+		problemsFile:   &p.Problems,
+		flashcardsFile: &p.Decks,
+	}
+	found := make(map[string]bool)
+	for _, f := range zr.File {
+		target, ok := targets[f.Name]
+		if !ok {
+			continue // 知らないファイル（説明のREADMEなど）は読み飛ばす
+		}
+		if err := readEntry(f, target); err != nil {
+			return nil, err
+		}
+		found[f.Name] = true
+	}
+
+	if !found[manifestFile] {
+		return nil, errors.New("内容パックの形式が正しくありません: manifest.json がありません")
+	}
+	if p.Manifest.Format > FormatVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("内容パックの内容が正しくありません: %w", err)
+	}
+	return p, nil
+}
+
+// readEntry zipの中のJSONファイルを読み込む
+func readEntry(f *zip.File, target any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("内容パックの読み込みエラー（%s）: %w", f.Name, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxEntrySize+1))
+	if err != nil {
+		return fmt.Errorf("内容パックの読み込みエラー（%s）: %w", f.Name, err)
+	}
+	if len(data) > maxEntrySize {
+		return fmt.Errorf("内容パックの %s が大きすぎます", f.Name)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("内容パックの解析エラー（%s）: %w", f.Name, err)
+	}
+	return nil
+}
+
+// MergeProblems 配られた問題を加える（同じ学年・科目・問題文の問題は加えない）。加えた問題の数を返す
+func MergeProblems(existing, added []ai.OfflineProblem) ([]ai.OfflineProblem, int) {
+	merged := slices.Clone(existing)
+	count := 0
+	for _, problem := range added {
+		duplicate := slices.ContainsFunc(merged, func(p ai.OfflineProblem) bool {
+			return p.Grade == problem.Grade && p.Subject == problem.Subject && p.Description == problem.Description
+		})
+		if !duplicate {
+			merged = append(merged, problem)
+			count++
+		}
+	}
+	return merged, count
+}
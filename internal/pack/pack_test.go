@@ -0,0 +1,108 @@
+package pack
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/curriculum"
+)
+
+// testPack テスト用の内容パック
+func testPack() *Pack {
+	return &Pack{
+		Manifest: Manifest{Name: "2学期 期末対策", Author: "山田先生", CreatedAt: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)},
+		Curriculum: curriculum.Curriculum{
+			2: {"数学": {"私立中対策：規則性"}},
+		},
+		Problems: []ai.OfflineProblem{{
+			Grade: 2, Subject: "数学", Topic: "連立方程式", Title: "連立方程式の解",
+			Description: "x + y = 5、x - y = 1 のとき、xの値を選んでください。",
+			Options:     []string{"3", "2", "4", "1"}, CorrectAnswer: 0,
+			Explanation: "2つの式をたすと 2x = 6 なので x = 3 です。", Difficulty: 2,
+		}},
+		Decks: []Deck{{Kind: ai.FlashcardVocab, Cards: []Card{{Front: "library", Back: "図書館", Hint: "I study in the library."}}}},
+	}
+}
+
+func TestWriteAndRead(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testPack()); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Manifest.Name != "2学期 期末対策" || p.Manifest.Format != FormatVersion {
+		t.Errorf("説明 = %+v", p.Manifest)
+	}
+	if p.TopicCount() != 1 || len(p.Problems) != 1 || p.CardCount() != 1 {
+		t.Errorf("単元 %d・問題 %d・カード %d", p.TopicCount(), len(p.Problems), p.CardCount())
+	}
+	if p.Problems[0].Options[0] != "3" || p.Decks[0].Cards[0].Hint != "I study in the library." {
+		t.Errorf("中身 = %+v, %+v", p.Problems[0], p.Decks[0])
+	}
+}
+
+func TestReadRejectsInvalidPack(t *testing.T) {
+	write := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, content := range files {
+			fw, _ := zw.Create(name)
+			_, _ = fw.Write([]byte(content))
+		}
+		_ = zw.Close()
+		return buf.Bytes()
+	}
+
+	for name, data := range map[string][]byte{
+		"zipでない":  []byte("not a zip"),
+		"説明なし":    write(map[string]string{problemsFile: "[]"}),
+		"名前なし":    write(map[string]string{manifestFile: `{"format": 1}`}),
+		"問題の正解":   write(map[string]string{manifestFile: `{"format": 1, "name": "a"}`, problemsFile: `[{"grade": 1, "subject": "数学", "title": "t", "description": "d", "options": ["1", "2"], "correct_answer": 5, "difficulty": 1}]`}),
+		"カードの種類":  write(map[string]string{manifestFile: `{"format": 1, "name": "a"}`, flashcardsFile: `[{"kind": "math", "cards": []}]`}),
+		"学習範囲の学年": write(map[string]string{manifestFile: `{"format": 1, "name": "a"}`, curriculumFile: `{"5": {"数学": ["数列"]}}`}),
+	} {
+		if _, err := Read(bytes.NewReader(data), int64(len(data))); err == nil {
+			t.Errorf("%s: エラーになりません", name)
+		}
+	}
+
+	data := write(map[string]string{manifestFile: `{"format": 2, "name": "a"}`})
+	if _, err := Read(bytes.NewReader(data), int64(len(data))); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("新しい版のパック: %v", err)
+	}
+}
+
+func TestMergeAndSaveProblems(t *testing.T) {
+	problems := testPack().Problems
+	merged, added := MergeProblems(problems, append(testPack().Problems, ai.OfflineProblem{
+		Grade: 1, Subject: "英語", Title: "be動詞", Description: "I ___ a student. に入る語を選んでください。",
+		Options: []string{"am", "is", "are", "be"}, Difficulty: 1,
+	}))
+	if added != 1 || len(merged) != 2 {
+		t.Fatalf("加えた問題 %d・全体 %d", added, len(merged))
+	}
+
+	path := filepath.Join(t.TempDir(), ProblemsFileName)
+	if loaded, err := LoadProblems(path); err != nil || loaded != nil {
+		t.Fatalf("ファイルがないとき = %v, %v", loaded, err)
+	}
+	if err := SaveProblems(path, merged); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadProblems(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 || loaded[1].Title != "be動詞" {
+		t.Errorf("読み込んだ問題 = %+v", loaded)
+	}
+}
@@ -0,0 +1,57 @@
+package pack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// ProblemsFileName 読み込んだ内容パックの問題を保存するファイル名（アプリケーションデータディレクトリに置く）
+const ProblemsFileName = "pack_problems.json"
+
+// ProblemsPath 読み込んだ内容パックの問題のファイルのパス
+func ProblemsPath() string {
+	return filepath.Join(config.GetAppDir(), ProblemsFileName)
+}
+
+// LoadProblems 読み込んだ内容パックの問題を読み込む（ファイルがなければnil）
+func LoadProblems(path string) ([]ai.OfflineProblem, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("内容パックの問題ファイル読み込みエラー: %w", err)
+	}
+
+	var problems []ai.OfflineProblem
+	if err := json.Unmarshal(data, &problems); err != nil {
+		return nil, fmt.Errorf("内容パックの問題ファイル解析エラー: %w", err)
+	}
+	for i, problem := range problems {
+		if err := problem.Validate(); err != nil {
+			return nil, fmt.Errorf("内容パックの問題ファイルの%d問目が正しくありません: %w", i+1, err)
+		}
+	}
+	return problems, nil
+}
+
+// SaveProblems 読み込んだ内容パックの問題をファイルに保存
+func SaveProblems(path string, problems []ai.OfflineProblem) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("内容パックの問題ディレクトリ作成エラー: %w", err)
+	}
+	data, err := json.MarshalIndent(problems, "", "  ")
+	if err != nil {
+		return fmt.Errorf("内容パックの問題データ変換エラー: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("内容パックの問題ファイル保存エラー: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package parent
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// GoalProgress 今週の目標の進み具合
+type GoalProgress struct {
+	Goal      config.WeeklyGoal
+	WeekStart time.Time // 月曜日0時
+	Minutes   int       // 学習時間（アプリ外の学習を含む）
+	Days      int
+	Problems  int
+}
+
+// GoalItem 目標の1項目の進み具合
+type GoalItem struct {
+	Label  string
+	Unit   string
+	Done   int
+	Target int
+}
+
+// Ratio 目標に対する割合（0〜1。目標がなければ0）
+func (i GoalItem) Ratio() float64 {
+	if i.Target <= 0 {
+		return 0
+	}
+	return min(float64(i.Done)/float64(i.Target), 1)
+}
+
+// Items 目標を決めた項目の進み具合（学習時間・日数・問題数の順）
+func (p *GoalProgress) Items() []GoalItem {
+	var items []GoalItem
+	if p.Goal.Minutes > 0 {
+		items = append(items, GoalItem{Label: "学習時間", Unit: "分", Done: p.Minutes, Target: p.Goal.Minutes})
+	}
+	if p.Goal.Days > 0 {
+		items = append(items, GoalItem{Label: "学習した日", Unit: "日", Done: p.Days, Target: p.Goal.Days})
+	}
+	if p.Goal.Problems > 0 {
+		items = append(items, GoalItem{Label: "解いた問題", Unit: "問", Done: p.Problems, Target: p.Goal.Problems})
+	}
+	return items
+}
+
+// Achieved 目標を決めたすべての項目を達成したかどうか（目標がなければfalse）
+func (p *GoalProgress) Achieved() bool {
+	items := p.Items()
+	for _, item := range items {
+		if item.Done < item.Target {
+			return false
+		}
+	}
+	return len(items) > 0
+}
+
+// WeekProgress nowを含む週の目標の進み具合
+func WeekProgress(db *database.DB, userID string, goal config.WeeklyGoal, now time.Time) (*GoalProgress, error) {
+	start := WeekStart(now)
+	sessions, err := db.GetStudySessionsBetween(userID, start, start.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, fmt.Errorf("セッション取得エラー: %w", err)
+	}
+	return SummarizeWeek(sessions, goal, start), nil
+}
+
+// SummarizeWeek 週の学習セッションから目標の進み具合を集計
+func SummarizeWeek(sessions []database.StudySession, goal config.WeeklyGoal, weekStart time.Time) *GoalProgress {
+	progress := &GoalProgress{Goal: goal, WeekStart: weekStart}
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	days := make(map[string]bool)
+	seconds := 0
+	for i := range sessions {
+		session := &sessions[i]
+		if session.StartTime.Before(weekStart) || !session.StartTime.Before(weekEnd) {
+			continue
+		}
+		seconds += session.DurationSeconds()
+		progress.Problems += session.TotalProblems
+		days[session.StartTime.Format("2006-01-02")] = true
+	}
+	progress.Minutes = seconds / 60
+	progress.Days = len(days)
+	return progress
+}
+
+// WeekStart 指定日の週の月曜日0時
+func WeekStart(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	date := t.AddDate(0, 0, -daysSinceMonday)
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, t.Location())
+}
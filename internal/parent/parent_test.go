@@ -0,0 +1,81 @@
+package parent
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+func TestGateUnlock(t *testing.T) {
+	cfg := &config.ParentConfig{}
+	gate := NewGate(cfg)
+	now := time.Now()
+
+	if err := gate.Unlock("1234", now); !errors.Is(err, ErrNoPIN) {
+		t.Fatalf("PINなし: %v", err)
+	}
+	if err := SetPIN(cfg, "12a4"); err == nil {
+		t.Error("数字以外のPINはエラーになるはず")
+	}
+	if err := SetPIN(cfg, "2468"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PINHash == "" || cfg.PINHash == "2468" {
+		t.Errorf("PINのハッシュ = %q", cfg.PINHash)
+	}
+	if err := gate.Unlock("2468", now); err != nil {
+		t.Errorf("正しいPIN: %v", err)
+	}
+
+	for i := 1; i < maxFailures; i++ {
+		if err := gate.Unlock("0000", now); !errors.Is(err, ErrWrongPIN) {
+			t.Fatalf("%d回目のまちがい: %v", i, err)
+		}
+	}
+	var locked *LockedError
+	if err := gate.Unlock("0000", now); !errors.As(err, &locked) {
+		t.Fatalf("%d回まちがえるとロックされるはず: %v", maxFailures, err)
+	}
+	if err := gate.Unlock("2468", now.Add(time.Minute)); !errors.As(err, &locked) {
+		t.Errorf("ロック中は正しいPINでも入れないはず: %v", err)
+	}
+	if err := gate.Unlock("2468", now.Add(lockDuration)); err != nil {
+		t.Errorf("ロックが解けたあとの正しいPIN: %v", err)
+	}
+}
+
+func TestSummarizeWeek(t *testing.T) {
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)
+	session := func(start time.Time, minutes, problems int) database.StudySession {
+		end := start.Add(time.Duration(minutes) * time.Minute)
+		return database.StudySession{StartTime: start, EndTime: &end, TotalProblems: problems}
+	}
+	sessions := []database.StudySession{
+		session(monday.Add(-2*time.Hour), 30, 10), // 先週の日曜日
+		session(monday.Add(17*time.Hour), 20, 8),
+		session(monday.Add(19*time.Hour), 25, 5),
+		session(monday.AddDate(0, 0, 2).Add(18*time.Hour), 45, 12),
+	}
+
+	progress := SummarizeWeek(sessions, config.WeeklyGoal{Minutes: 90, Days: 2}, monday)
+	if progress.Minutes != 90 || progress.Days != 2 || progress.Problems != 25 {
+		t.Errorf("今週の集計 = %+v", progress)
+	}
+	if items := progress.Items(); len(items) != 2 || items[0].Ratio() != 1 {
+		t.Errorf("目標の項目 = %+v", items)
+	}
+	if !progress.Achieved() {
+		t.Error("目標を達成しているはず")
+	}
+
+	progress.Goal.Problems = 30
+	if progress.Achieved() {
+		t.Error("問題数の目標は未達成のはず")
+	}
+	if got := WeekStart(monday.AddDate(0, 0, 6).Add(23 * time.Hour)); !got.Equal(monday) {
+		t.Errorf("WeekStart(日曜日) = %v", got)
+	}
+}
@@ -0,0 +1,133 @@
+package parent
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// PINのルールと、まちがえたときのロック
+const (
+	MinPINLength  = 4
+	MaxPINLength  = 8
+	pinSaltBytes  = 16
+	pinIterations = 100_000
+	maxFailures   = 5               // 続けてまちがえるとロックする回数
+	lockDuration  = 5 * time.Minute // ロックする時間
+)
+
+// ErrWrongPIN PINがちがう
+var ErrWrongPIN = errors.New("PINがちがいます")
+
+// ErrNoPIN PINがまだ設定されていない
+var ErrNoPIN = errors.New("PINが設定されていません")
+
+// LockedError 続けてまちがえたため、しばらく入力できない
+type LockedError struct {
+	Remaining time.Duration
+}
+
+func (e *LockedError) Error() string {
+	minutes := int((e.Remaining + time.Minute - 1) / time.Minute)
+	return fmt.Sprintf("PINを続けてまちがえたため、あと%d分は入力できません", minutes)
+}
+
+// ValidatePIN PINの形式を確かめる（4〜8桁の数字）
+func ValidatePIN(pin string) error {
+	if len(pin) < MinPINLength || len(pin) > MaxPINLength {
+		return fmt.Errorf("PINは%d〜%d桁の数字にしてください", MinPINLength, MaxPINLength)
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("PINは%d〜%d桁の数字にしてください", MinPINLength, MaxPINLength)
+		}
+	}
+	return nil
+}
+
+// HasPIN PINが設定されているかどうか
+func HasPIN(cfg *config.ParentConfig) bool {
+	return cfg.PINHash != "" && cfg.PINSalt != ""
+}
+
+// SetPIN PINを設定（設定ファイルにはソルトとハッシュだけを保存する）
+func SetPIN(cfg *config.ParentConfig, pin string) error {
+	if err := ValidatePIN(pin); err != nil {
+		return err
+	}
+	salt := make([]byte, pinSaltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("PIN設定エラー: %w", err)
+	}
+	hash, err := hashPIN(pin, salt)
+	if err != nil {
+		return err
+	}
+	cfg.PINSalt = hex.EncodeToString(salt)
+	cfg.PINHash = hash
+	return nil
+}
+
+// hashPIN PINとソルトからハッシュを作る
+func hashPIN(pin string, salt []byte) (string, error) {
+	key, err := pbkdf2.Key(sha256.New, pin, salt, pinIterations, sha256.Size)
+	if err != nil {
+		return "", fmt.Errorf("PINのハッシュ作成エラー: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// Gate 保護者ダッシュボードの入り口（PINを続けてまちがえると、しばらく入力できなくする）
+type Gate struct {
+	config *config.ParentConfig
+
+	mu          sync.Mutex
+	failures    int
+	lockedUntil time.Time
+}
+
+// NewGate 保護者ダッシュボードの入り口を作成
+func NewGate(cfg *config.ParentConfig) *Gate {
+	return &Gate{config: cfg}
+}
+
+// Unlock PINを確かめる（合っていればnil）
+func (g *Gate) Unlock(pin string, now time.Time) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if now.Before(g.lockedUntil) {
+		return &LockedError{Remaining: g.lockedUntil.Sub(now)}
+	}
+	if !HasPIN(g.config) {
+		return ErrNoPIN
+	}
+
+	salt, err := hex.DecodeString(g.config.PINSalt)
+	if err != nil {
+		return fmt.Errorf("PINの設定が壊れています: %w", err)
+	}
+	hash, err := hashPIN(pin, salt)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(g.config.PINHash)) != 1 {
+		g.failures++
+		if g.failures >= maxFailures {
+			g.failures = 0
+			g.lockedUntil = now.Add(lockDuration)
+			return &LockedError{Remaining: lockDuration}
+		}
+		return ErrWrongPIN
+	}
+	g.failures = 0
+	return nil
+}
@@ -2,11 +2,11 @@ package pet
 
 import (
 	"fmt"
-	"math"
 	"math/rand"
 	"time"
 
-	"studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/xp"
 )
 
 // Manager バーチャルペット管理システム
@@ -22,6 +22,7 @@ type StudyResult struct {
 	ConsecutiveCorrect int     `json:"consecutive_correct"`
 	SubjectProgress    float64 `json:"subject_progress"`
 	SessionDuration    int     `json:"session_duration"`   // 秒
+	EnergyPercent      int     `json:"energy_percent"`     // 元気による経験値の割合（%）。0なら使わない
 }
 
 // PetAction ペットのアクション
@@ -58,6 +59,41 @@ func NewManager(db *database.DB) *Manager {
 	return &Manager{db: db}
 }
 
+// EnsurePet ペットを取得（まだいない場合は指定の種類で作成）
+func (m *Manager) EnsurePet(userID, species string) (*database.VirtualPet, error) {
+	if pet, err := m.db.GetVirtualPet(userID); err == nil {
+		return pet, nil
+	}
+
+	names := map[string]string{
+		"cat":     "ミケ",
+		"dog":     "ポチ",
+		"dragon":  "リュウ",
+		"unicorn": "ユニ",
+	}
+	name, exists := names[species]
+	if !exists {
+		species, name = "cat", names["cat"]
+	}
+
+	pet := &database.VirtualPet{
+		UserID:       userID,
+		Name:         name,
+		Species:      species,
+		Level:        1,
+		Health:       100,
+		Happiness:    100,
+		Intelligence: 50,
+		Evolution:    "basic",
+		CreatedAt:    time.Now(),
+	}
+	if err := m.db.CreateVirtualPet(pet); err != nil {
+		return nil, fmt.Errorf("ペット作成エラー: %w", err)
+	}
+
+	return pet, nil
+}
+
 // FeedPet 学習結果に基づいてペットに経験値を与える
 func (m *Manager) FeedPet(userID string, result StudyResult) (*PetAction, error) {
 	pet, err := m.db.GetVirtualPet(userID)
@@ -65,8 +101,8 @@ func (m *Manager) FeedPet(userID string, result StudyResult) (*PetAction, error)
 		return nil, fmt.Errorf("ペット取得エラー: %w", err)
 	}
 
-	// 経験値と幸福度の計算
-	expGain := m.calculateExperience(result)
+	// 経験値（ユーザーと同じ獲得ルール）と幸福度の計算
+	expGain := xp.ForAnswer(result.Answer())
 	happinessGain := m.calculateHappiness(result)
 	healthChange := m.calculateHealthChange(result)
 
@@ -99,36 +135,16 @@ func (m *Manager) FeedPet(userID string, result StudyResult) (*PetAction, error)
 	return m.generateFeedbackAction(pet, result), nil
 }
 
-// calculateExperience 経験値を計算
-func (m *Manager) calculateExperience(result StudyResult) int {
-	baseExp := 10
-
-	// 正解ボーナス
-	if result.IsCorrect {
-		baseExp += 15
-	} else {
-		baseExp += 5 // 間違いでも少し経験値
-	}
-
-	// 難易度ボーナス
-	baseExp += result.Difficulty * 3
-
-	// 連続正解ボーナス
-	if result.ConsecutiveCorrect > 1 {
-		baseExp += int(math.Min(float64(result.ConsecutiveCorrect*2), 20))
-	}
-
-	// 時間ボーナス（適切な時間で回答した場合）
-	if result.TimeTaken > 30 && result.TimeTaken < 300 { // 30秒～5分
-		baseExp += 5
+// Answer 経験値の計算に使う解答結果に変換
+func (r StudyResult) Answer() xp.Answer {
+	return xp.Answer{
+		IsCorrect:          r.IsCorrect,
+		Difficulty:         r.Difficulty,
+		TimeTaken:          r.TimeTaken,
+		ConsecutiveCorrect: r.ConsecutiveCorrect,
+		SessionDuration:    r.SessionDuration,
+		EnergyPercent:      r.EnergyPercent,
 	}
-
-	// 学習継続ボーナス（長時間の学習セッション）
-	if result.SessionDuration > 600 { // 10分以上
-		baseExp += 10
-	}
-
-	return baseExp
 }
 
 // calculateHappiness 幸福度の変化を計算
@@ -174,7 +190,7 @@ func (m *Manager) calculateHealthChange(result StudyResult) int {
 
 // checkLevelUp レベルアップをチェック
 func (m *Manager) checkLevelUp(pet *database.VirtualPet) *PetAction {
-	requiredExp := m.getRequiredExp(pet.Level)
+	requiredExp := xp.RequiredForLevel(pet.Level)
 	
 	if pet.Experience >= requiredExp {
 		pet.Level++
@@ -237,12 +253,6 @@ func (m *Manager) generateFeedbackAction(pet *database.VirtualPet, result StudyR
 	}
 }
 
-// getRequiredExp レベルアップに必要な経験値を計算
-func (m *Manager) getRequiredExp(level int) int {
-	// レベルが上がるほど必要経験値が増加
-	return 100 + (level-1)*50
-}
-
 // getEvolutionRequirements 進化の要件を取得
 func (m *Manager) getEvolutionRequirements(species, currentStage string) *EvolutionInfo {
 	evolutionMap := map[string]map[string]*EvolutionInfo{
@@ -404,7 +414,7 @@ func (m *Manager) GetPetStats(userID string) (*PetStats, error) {
 		return nil, fmt.Errorf("ペット取得エラー: %w", err)
 	}
 
-	nextLevelExp := m.getRequiredExp(pet.Level)
+	nextLevelExp := xp.RequiredForLevel(pet.Level)
 	expToNext := nextLevelExp - pet.Experience
 
 	// ペットの年齢（日数）
@@ -0,0 +1,114 @@
+package privacy
+
+import (
+	"os"
+	"os/user"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StudentPseudonym AIに送る文章で生徒の名前の代わりに使う仮名
+const StudentPseudonym = "ヒカル"
+
+// 個人情報を取り除いた部分の置き換え文字列
+const (
+	RedactedAccount = "[ユーザー]"
+	RedactedPath    = "[フォルダ]"
+	RedactedSecret  = "[秘密の値]"
+	RedactedEmail   = "[メールアドレス]"
+	RedactedPhone   = "[電話番号]"
+)
+
+var (
+	// emailPattern メールアドレス
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// phonePattern 日本の電話番号（固定電話・携帯電話）
+	phonePattern = regexp.MustCompile(`0\d{1,4}-\d{1,4}-\d{3,4}|0[789]0\d{8}`)
+)
+
+// rule 取り除く値と置き換え文字列
+type rule struct {
+	value       string
+	replacement string
+	restore     bool // AIの応答では置き換え文字列を元の値に戻す（仮名）
+}
+
+// Redactor AIに送る文章から生徒の名前や設定に含まれる個人を特定できる値を取り除く
+type Redactor struct {
+	mu    sync.RWMutex
+	rules map[string]rule // 値の種類（"name"など）ごとの規則
+}
+
+// NewRedactor 個人情報の除去を作成
+func NewRedactor() *Redactor {
+	return &Redactor{rules: make(map[string]rule)}
+}
+
+// Set 値の種類ごとに、取り除く値と置き換え文字列を設定（空の値は削除）
+func (r *Redactor) Set(kind, value, replacement string) {
+	r.set(kind, rule{value: strings.TrimSpace(value), replacement: replacement})
+}
+
+// SetPseudonym 値を仮名に置き換える（AIの応答に出てきた仮名は元の値に戻す）
+func (r *Redactor) SetPseudonym(kind, value, pseudonym string) {
+	r.set(kind, rule{value: strings.TrimSpace(value), replacement: pseudonym, restore: true})
+}
+
+func (r *Redactor) set(kind string, rule rule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rule.value == "" {
+		delete(r.rules, kind)
+		return
+	}
+	r.rules[kind] = rule
+}
+
+// SetLocalAccount このパソコンのユーザー名とホームフォルダを取り除く値に設定
+func (r *Redactor) SetLocalAccount() {
+	if home, err := os.UserHomeDir(); err == nil && home != "/" {
+		r.Set("home", home, RedactedPath)
+	}
+	if account, err := user.Current(); err == nil {
+		r.Set("account", account.Username, RedactedAccount)
+		r.Set("account_name", account.Name, RedactedAccount)
+	}
+}
+
+// sortedRules 長い値から順の規則（「山田太郎」を「山田」より先に置き換える）
+func (r *Redactor) sortedRules() []rule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rules := make([]rule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if len(rules[i].value) != len(rules[j].value) {
+			return len(rules[i].value) > len(rules[j].value)
+		}
+		return rules[i].value < rules[j].value
+	})
+	return rules
+}
+
+// Redact 文章から個人を特定できる値を取り除く（メールアドレスと電話番号も取り除く）
+func (r *Redactor) Redact(text string) string {
+	for _, rule := range r.sortedRules() {
+		text = strings.ReplaceAll(text, rule.value, rule.replacement)
+	}
+	text = emailPattern.ReplaceAllString(text, RedactedEmail)
+	return phonePattern.ReplaceAllString(text, RedactedPhone)
+}
+
+// Restore AIの応答に出てきた仮名を元の値に戻す
+func (r *Redactor) Restore(text string) string {
+	for _, rule := range r.sortedRules() {
+		if rule.restore {
+			text = strings.ReplaceAll(text, rule.replacement, rule.value)
+		}
+	}
+	return text
+}
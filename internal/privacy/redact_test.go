@@ -0,0 +1,84 @@
+package privacy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactReplacesNameWithPseudonym(t *testing.T) {
+	r := NewRedactor()
+	r.SetPseudonym("name", "山田太郎", StudentPseudonym)
+
+	got := r.Redact("山田太郎さんが解いた問題です。山田太郎さんは正解しました。")
+	if strings.Contains(got, "山田太郎") {
+		t.Fatalf("名前が残っています: %q", got)
+	}
+	if want := "ヒカルさんが解いた問題です。ヒカルさんは正解しました。"; got != want {
+		t.Errorf("Redact() = %q, want %q", got, want)
+	}
+}
+
+func TestRedactLongestValueFirst(t *testing.T) {
+	r := NewRedactor()
+	r.SetPseudonym("name", "山田", StudentPseudonym)
+	r.Set("account_name", "山田太郎", RedactedAccount)
+
+	if got, want := r.Redact("山田太郎と山田"), "[ユーザー]とヒカル"; got != want {
+		t.Errorf("Redact() = %q, want %q", got, want)
+	}
+}
+
+func TestRedactConfigValues(t *testing.T) {
+	r := NewRedactor()
+	r.Set("home", "/home/taro", RedactedPath)
+	r.Set("api_key", "sk-secret-123", RedactedSecret)
+
+	got := r.Redact("保存先: /home/taro/.studybuddy-ai/studybuddy.db キー: sk-secret-123")
+	for _, leaked := range []string{"/home/taro", "sk-secret-123"} {
+		if strings.Contains(got, leaked) {
+			t.Errorf("%q が残っています: %q", leaked, got)
+		}
+	}
+}
+
+func TestRedactEmailAndPhone(t *testing.T) {
+	r := NewRedactor()
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"連絡先は taro.yamada@example.com です", "連絡先は [メールアドレス] です"},
+		{"電話: 03-1234-5678", "電話: [電話番号]"},
+		{"携帯 09012345678 まで", "携帯 [電話番号] まで"},
+		{"2x + 3 = 11 を解く", "2x + 3 = 11 を解く"},
+	}
+	for _, tt := range tests {
+		if got := r.Redact(tt.in); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRestoreOnlyPseudonyms(t *testing.T) {
+	r := NewRedactor()
+	r.SetPseudonym("name", "花子", StudentPseudonym)
+	r.Set("api_key", "sk-secret-123", RedactedSecret)
+
+	got := r.Restore("ヒカルさん、よく頑張りました！[秘密の値]")
+	if want := "花子さん、よく頑張りました！[秘密の値]"; got != want {
+		t.Errorf("Restore() = %q, want %q", got, want)
+	}
+}
+
+func TestSetEmptyValueRemovesRule(t *testing.T) {
+	r := NewRedactor()
+	r.SetPseudonym("name", "花子", StudentPseudonym)
+	r.SetPseudonym("name", "", StudentPseudonym)
+
+	if got := r.Redact("花子"); got != "花子" {
+		t.Errorf("Redact() = %q, want 花子", got)
+	}
+	if got := r.Redact("何もない文章"); got != "何もない文章" {
+		t.Errorf("空の値で文章が変わりました: %q", got)
+	}
+}
@@ -0,0 +1,88 @@
+package progress
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+)
+
+// AnswerKey 模擬テストの正解と解説の封印（提出するまで、正解の選択肢と解説を平文でメモリに置かない）
+type AnswerKey struct {
+	aead   cipher.AEAD
+	sealed [][]byte // 問題ごとに暗号化した正解と解説（先頭はnonce）
+}
+
+// sealedAnswer 封印する問題の正解と解説
+type sealedAnswer struct {
+	CorrectAnswer int    `json:"correct_answer"`
+	Explanation   string `json:"explanation"`
+}
+
+// SealAnswers 問題の正解と解説を、テストごとの使い捨ての鍵で暗号化して問題から取り除く
+// （封印した問題のCorrectAnswerは-1、Explanationは空になる）
+func SealAnswers(problems []*ai.Problem) (*AnswerKey, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("模擬テストの鍵生成エラー: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("模擬テストの暗号化エラー: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("模擬テストの暗号化エラー: %w", err)
+	}
+
+	answerKey := &AnswerKey{aead: aead, sealed: make([][]byte, len(problems))}
+	for i, problem := range problems {
+		plain, err := json.Marshal(sealedAnswer{CorrectAnswer: problem.CorrectAnswer, Explanation: problem.Explanation})
+		if err != nil {
+			return nil, fmt.Errorf("模擬テストの暗号化エラー: %w", err)
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, fmt.Errorf("模擬テストの暗号化エラー: %w", err)
+		}
+		answerKey.sealed[i] = aead.Seal(nonce, nonce, plain, sealedIndex(i))
+	}
+	for _, problem := range problems {
+		problem.CorrectAnswer = -1
+		problem.Explanation = ""
+	}
+	return answerKey, nil
+}
+
+// Open 封印した正解と解説を問題に戻す（提出してから、封印したときと同じ順番の問題で呼ぶ）
+func (k *AnswerKey) Open(problems []*ai.Problem) error {
+	if len(problems) != len(k.sealed) {
+		return errors.New("封印した模擬テストと問題の数が違います")
+	}
+	size := k.aead.NonceSize()
+	answers := make([]sealedAnswer, len(problems))
+	for i, sealed := range k.sealed {
+		plain, err := k.aead.Open(nil, sealed[:size], sealed[size:], sealedIndex(i))
+		if err != nil {
+			return fmt.Errorf("模擬テストの正解の復号エラー: %w", err)
+		}
+		if err := json.Unmarshal(plain, &answers[i]); err != nil {
+			return fmt.Errorf("模擬テストの正解の解析エラー: %w", err)
+		}
+	}
+	for i, problem := range problems {
+		problem.CorrectAnswer = answers[i].CorrectAnswer
+		problem.Explanation = answers[i].Explanation
+	}
+	return nil
+}
+
+// sealedIndex 暗号化した正解を別の問題に入れ替えられないよう、問題の番号を認証に含める
+func sealedIndex(i int) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(i))
+}
@@ -0,0 +1,35 @@
+package progress_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+)
+
+func TestSealAnswers(t *testing.T) {
+	problems := []*ai.Problem{
+		{Title: "一次方程式", Options: []string{"x = 3", "x = -3"}, CorrectAnswer: 1, Explanation: "移項すると x = -3 です。"},
+		{Title: "比例", Options: []string{"y = 2x", "y = x + 2"}, CorrectAnswer: 0, Explanation: "原点を通る直線です。"},
+	}
+	key, err := progress.SealAnswers(problems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, problem := range problems {
+		if problem.CorrectAnswer != -1 || problem.Explanation != "" {
+			t.Errorf("封印した問題に正解・解説が残っています: %+v", problem)
+		}
+	}
+
+	if err := key.Open(problems[:1]); err == nil {
+		t.Error("問題の数が違うのに復号できました")
+	}
+	if err := key.Open(problems); err != nil {
+		t.Fatal(err)
+	}
+	if problems[0].CorrectAnswer != 1 || problems[1].CorrectAnswer != 0 || !strings.Contains(problems[0].Explanation, "移項") {
+		t.Errorf("復号した問題 = %+v, %+v", problems[0], problems[1])
+	}
+}
@@ -0,0 +1,79 @@
+package progress
+
+import (
+	"sort"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// ExamTopicResult 模擬テストの単元別の結果
+type ExamTopicResult struct {
+	Topic          string  `json:"topic"`
+	TotalProblems  int     `json:"total_problems"`
+	CorrectAnswers int     `json:"correct_answers"`
+	AccuracyRate   float64 `json:"accuracy_rate"`
+}
+
+// ExamReport 模擬テストの採点結果
+type ExamReport struct {
+	Subject        string            `json:"subject"`
+	TotalProblems  int               `json:"total_problems"`
+	CorrectAnswers int               `json:"correct_answers"`
+	Unanswered     int               `json:"unanswered"`
+	Score          int               `json:"score"` // 100点満点
+	TimeLimit      time.Duration     `json:"time_limit"`
+	TimeUsed       time.Duration     `json:"time_used"`
+	ByTopic        []ExamTopicResult `json:"by_topic"` // 正解率の低い順
+}
+
+// ExamUnanswered 模擬テストで解答しなかった問題の解答欄
+const ExamUnanswered = "（未解答）"
+
+// BuildExamReport 模擬テストの解答結果を採点
+func BuildExamReport(subject string, results []database.ProblemResult, timeLimit, timeUsed time.Duration) *ExamReport {
+	report := &ExamReport{
+		Subject:       subject,
+		TotalProblems: len(results),
+		TimeLimit:     timeLimit,
+		TimeUsed:      min(timeUsed, timeLimit),
+	}
+
+	topics := make(map[string]*ExamTopicResult)
+	for _, result := range results {
+		topic := result.ProblemType
+		if topic == "" {
+			topic = "その他"
+		}
+		t, exists := topics[topic]
+		if !exists {
+			t = &ExamTopicResult{Topic: topic}
+			topics[topic] = t
+		}
+		t.TotalProblems++
+
+		switch {
+		case result.IsCorrect:
+			report.CorrectAnswers++
+			t.CorrectAnswers++
+		case result.UserAnswer == ExamUnanswered:
+			report.Unanswered++
+		}
+	}
+
+	if report.TotalProblems > 0 {
+		report.Score = report.CorrectAnswers * 100 / report.TotalProblems
+	}
+	for _, t := range topics {
+		t.AccuracyRate = float64(t.CorrectAnswers) / float64(t.TotalProblems)
+		report.ByTopic = append(report.ByTopic, *t)
+	}
+	sort.Slice(report.ByTopic, func(i, j int) bool {
+		if report.ByTopic[i].AccuracyRate != report.ByTopic[j].AccuracyRate {
+			return report.ByTopic[i].AccuracyRate < report.ByTopic[j].AccuracyRate
+		}
+		return report.ByTopic[i].Topic < report.ByTopic[j].Topic
+	})
+
+	return report
+}
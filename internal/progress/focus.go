@@ -0,0 +1,167 @@
+package progress
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 集中度スコアの減点ルール
+const (
+	pausePenalty        = 8.0  // 一時停止1回あたりの減点
+	maxPausePenalty     = 30.0 // 一時停止による減点の上限
+	maxBreakPenalty     = 25.0 // 休憩を全く取らなかった場合の減点
+	normalAnswerTimeCV  = 0.4  // この変動係数までは解答ペースが安定しているとみなす
+	answerTimeCVPenalty = 50.0 // 変動係数の超過1.0あたりの減点
+	maxAnswerPenalty    = 45.0 // 解答ペースのばらつきによる減点の上限
+	lowFocusGap         = 10.0 // 全体平均よりこれ以上低い時間帯を「集中度が低い」とみなす
+)
+
+// TimesOfDay 時間帯の表示順
+var TimesOfDay = []string{"朝", "昼", "夕方", "夜"}
+
+// FocusInput 集中度の計算に使うセッション中の記録
+type FocusInput struct {
+	PauseCount      int
+	BreaksSuggested int
+	BreaksTaken     int
+	AnswerTimes     []int // 各問題の解答時間（秒）
+}
+
+// FocusAnalysis 集中度の分析結果
+type FocusAnalysis struct {
+	SessionCount      int                `json:"session_count"`
+	AverageScore      float64            `json:"average_score"`
+	ByTimeOfDay       map[string]float64 `json:"by_time_of_day"` // "朝" | "昼" | "夕方" | "夜"
+	LowFocusTimeOfDay string             `json:"low_focus_time_of_day"`
+	Trend             []FocusPoint       `json:"trend"`
+}
+
+// FocusPoint 集中度の推移の1点
+type FocusPoint struct {
+	Time  time.Time `json:"time"`
+	Score float64   `json:"score"`
+}
+
+// ComputeFocusScore 休憩の取り方・一時停止回数・解答時間のばらつきから集中度（0-100）を計算
+func ComputeFocusScore(input FocusInput) (score float64, answerTimeCV float64) {
+	score = 100
+
+	// 一時停止が多いほど集中が途切れている
+	score -= math.Min(float64(input.PauseCount)*pausePenalty, maxPausePenalty)
+
+	// 提案した休憩を取れているか
+	if input.BreaksSuggested > 0 {
+		adherence := math.Min(float64(input.BreaksTaken)/float64(input.BreaksSuggested), 1)
+		score -= (1 - adherence) * maxBreakPenalty
+	}
+
+	// 解答時間のばらつき（変動係数）が大きいほど集中が安定していない
+	answerTimeCV = coefficientOfVariation(input.AnswerTimes)
+	if answerTimeCV > normalAnswerTimeCV {
+		score -= math.Min((answerTimeCV-normalAnswerTimeCV)*answerTimeCVPenalty, maxAnswerPenalty)
+	}
+
+	return math.Max(score, 0), answerTimeCV
+}
+
+// coefficientOfVariation 変動係数（標準偏差/平均）を計算。2件未満は0
+func coefficientOfVariation(values []int) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += float64(v)
+	}
+	mean /= float64(len(values))
+	if mean == 0 {
+		return 0
+	}
+
+	variance := 0.0
+	for _, v := range values {
+		variance += math.Pow(float64(v)-mean, 2)
+	}
+	variance /= float64(len(values))
+
+	return math.Sqrt(variance) / mean
+}
+
+// TimeOfDay 時刻の時間帯名を取得
+func TimeOfDay(t time.Time) string {
+	switch hour := t.Hour(); {
+	case hour >= 5 && hour < 11:
+		return "朝"
+	case hour >= 11 && hour < 16:
+		return "昼"
+	case hour >= 16 && hour < 19:
+		return "夕方"
+	default:
+		return "夜"
+	}
+}
+
+// AnalyzeFocus 指定日数分のセッション集中度を分析
+func (m *Manager) AnalyzeFocus(userID string, days int) (*FocusAnalysis, error) {
+	focuses, err := m.db.GetSessionFocusSince(userID, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, fmt.Errorf("集中度取得エラー: %w", err)
+	}
+
+	return analyzeFocus(focuses), nil
+}
+
+// analyzeFocus セッション集中度の一覧を集計
+func analyzeFocus(focuses []database.SessionFocus) *FocusAnalysis {
+	analysis := &FocusAnalysis{
+		SessionCount: len(focuses),
+		ByTimeOfDay:  make(map[string]float64),
+	}
+	if len(focuses) == 0 {
+		return analysis
+	}
+
+	total := 0.0
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, focus := range focuses {
+		total += focus.FocusScore
+		period := TimeOfDay(focus.StartedAt)
+		sums[period] += focus.FocusScore
+		counts[period]++
+		analysis.Trend = append(analysis.Trend, FocusPoint{Time: focus.StartedAt, Score: focus.FocusScore})
+	}
+	analysis.AverageScore = total / float64(len(focuses))
+
+	// 時間帯別の平均と、平均より明らかに低い時間帯
+	lowest := analysis.AverageScore - lowFocusGap
+	for _, period := range TimesOfDay {
+		if counts[period] == 0 {
+			continue
+		}
+		average := sums[period] / float64(counts[period])
+		analysis.ByTimeOfDay[period] = average
+		if counts[period] >= 2 && average < lowest {
+			lowest = average
+			analysis.LowFocusTimeOfDay = period
+		}
+	}
+
+	return analysis
+}
+
+// FocusNote 集中度の一言まとめ（AIへのプロンプト・レポート用）
+func (a *FocusAnalysis) FocusNote() string {
+	if a == nil || a.SessionCount == 0 {
+		return ""
+	}
+	note := fmt.Sprintf("平均集中度%.0f点", a.AverageScore)
+	if a.LowFocusTimeOfDay != "" {
+		note += fmt.Sprintf("（%sの集中度が低い: %.0f点）", a.LowFocusTimeOfDay, a.ByTimeOfDay[a.LowFocusTimeOfDay])
+	}
+	return note
+}
@@ -0,0 +1,53 @@
+package progress
+
+// 当てずっぽう解答（ボタン連打）の判定基準
+const (
+	guessingWindow      = 5   // 判定に使う直近の解答数
+	guessingMaxSeconds  = 3   // これ未満の解答時間を「読まずに解答」とみなす（秒）
+	guessingMaxAccuracy = 0.3 // 直近の正解率がこれ未満なら当てずっぽうとみなす
+)
+
+// guessSample 判定用の解答記録
+type guessSample struct {
+	seconds int
+	correct bool
+}
+
+// GuessingDetector 短時間・低正解率の連続解答（当てずっぽう）を検出
+type GuessingDetector struct {
+	recent []guessSample
+}
+
+// Record 解答を記録し、当てずっぽうの状態かどうかを返す
+func (d *GuessingDetector) Record(seconds int, correct bool) bool {
+	d.recent = append(d.recent, guessSample{seconds: seconds, correct: correct})
+	if len(d.recent) > guessingWindow {
+		d.recent = d.recent[len(d.recent)-guessingWindow:]
+	}
+	return d.IsGuessing()
+}
+
+// IsGuessing 直近の解答がほぼすべて短時間かつ正解率が低いか
+func (d *GuessingDetector) IsGuessing() bool {
+	if len(d.recent) < guessingWindow {
+		return false
+	}
+
+	fast, correct := 0, 0
+	for _, sample := range d.recent {
+		if sample.seconds < guessingMaxSeconds {
+			fast++
+		}
+		if sample.correct {
+			correct++
+		}
+	}
+
+	// 1問だけじっくり解いていても、残りが連打なら当てずっぽうとみなす
+	return fast >= guessingWindow-1 && float64(correct)/float64(len(d.recent)) < guessingMaxAccuracy
+}
+
+// Reset 記録を消去（新しいセッションの開始時）
+func (d *GuessingDetector) Reset() {
+	d.recent = nil
+}
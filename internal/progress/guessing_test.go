@@ -0,0 +1,68 @@
+package progress_test
+
+import (
+	"testing"
+
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+)
+
+// guessAnswer 判定に渡す解答（解答時間と正誤）
+type guessAnswer struct {
+	seconds int
+	correct bool
+}
+
+// repeatAnswer 同じ解答をn回並べる
+func repeatAnswer(a guessAnswer, n int) []guessAnswer {
+	answers := make([]guessAnswer, n)
+	for i := range answers {
+		answers[i] = a
+	}
+	return answers
+}
+
+func TestGuessingDetector(t *testing.T) {
+	fastWrong := guessAnswer{seconds: 1, correct: false}
+	fastCorrect := guessAnswer{seconds: 1, correct: true}
+	slowWrong := guessAnswer{seconds: 3, correct: false}
+	tests := []struct {
+		name    string
+		answers []guessAnswer
+		want    bool
+	}{
+		{"短時間で不正解が続く", repeatAnswer(fastWrong, 5), true},
+		{"4問では判定しない", repeatAnswer(fastWrong, 4), false},
+		{"短時間でも正解が続く", repeatAnswer(fastCorrect, 5), false},
+		{"正解が1問なら正解率20%", append(repeatAnswer(fastWrong, 4), fastCorrect), true},
+		{"正解が2問なら正解率40%", append(repeatAnswer(fastWrong, 3), fastCorrect, fastCorrect), false},
+		{"1問だけじっくり解いた", append(repeatAnswer(fastWrong, 4), slowWrong), true},
+		{"2問じっくり解いた", append(repeatAnswer(fastWrong, 3), slowWrong, slowWrong), false},
+		{"3秒は短時間とみなさない", repeatAnswer(slowWrong, 5), false},
+		{"直近の5問で判定する", append(repeatAnswer(fastCorrect, 5), repeatAnswer(fastWrong, 5)...), true},
+		{"連打のあとに正解が続けば解除", append(repeatAnswer(fastWrong, 5), repeatAnswer(fastCorrect, 3)...), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d progress.GuessingDetector
+			got := false
+			for _, a := range tt.answers {
+				got = d.Record(a.seconds, a.correct)
+			}
+			if got != tt.want || d.IsGuessing() != got {
+				t.Errorf("Record() = %v, IsGuessing() = %v, want %v", got, d.IsGuessing(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGuessingDetectorReset(t *testing.T) {
+	var d progress.GuessingDetector
+	for range 5 {
+		d.Record(1, false)
+	}
+	d.Reset()
+	// 新しいセッションでは、また5問解くまで判定しない
+	if d.Record(1, false) {
+		t.Error("リセットのあとの1問目で当てずっぽうと判定された")
+	}
+}
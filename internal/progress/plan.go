@@ -0,0 +1,181 @@
+package progress
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 学習セッションの計画のルール
+const (
+	DefaultPlanProblems  = 10
+	planTopics           = 3                   // 優先して出題する単元の数
+	planHistory          = 30 * 24 * time.Hour // 正解率・解答時間を見る期間
+	defaultAnswerSeconds = 90                  // 解答の記録がないときの1問あたりの時間
+	minAnswerSeconds     = 30
+	maxAnswerSeconds     = 300
+	planRaiseStreak      = 3 // この数だけ続けて正解すると難易度を1つ上げる
+	planLowerStreak      = 2 // この数だけ続けてまちがえると難易度を1つ下げる
+	planHighAccuracy     = 0.8
+	planLowAccuracy      = 0.5
+)
+
+// SessionPlan 学習セッションの出題の計画（始める前に見せて、生徒が調整できる）
+type SessionPlan struct {
+	Subject       string   `json:"subject"`
+	Topics        []string `json:"topics"` // 順に出題する単元（空なら学年の学習範囲全体）
+	MinDifficulty int      `json:"min_difficulty"`
+	MaxDifficulty int      `json:"max_difficulty"`
+	Difficulty    int      `json:"difficulty"` // 今の難易度（解答に合わせて幅の中で上下する）
+	Problems      int      `json:"problems"`
+	AnswerSeconds int      `json:"answer_seconds"` // 1問あたりの見込みの時間
+	Reasons       []string `json:"reasons"`        // この計画にした理由
+
+	correctStreak int
+	wrongStreak   int
+}
+
+// ExpectedMinutes 予定の問題をすべて解くのにかかる見込みの時間（分）
+func (p *SessionPlan) ExpectedMinutes() int {
+	return max((p.Problems*p.AnswerSeconds+59)/60, 1)
+}
+
+// TopicFor n問目（0から）に出題する単元（単元を決めていなければ空）
+func (p *SessionPlan) TopicFor(n int) string {
+	if len(p.Topics) == 0 {
+		return ""
+	}
+	return p.Topics[n%len(p.Topics)]
+}
+
+// SetDifficulty 最初の難易度を変える（幅もその難易度を含むように広げる）
+func (p *SessionPlan) SetDifficulty(difficulty int) {
+	p.Difficulty = clampDifficulty(difficulty)
+	p.MinDifficulty = min(p.MinDifficulty, p.Difficulty)
+	p.MaxDifficulty = max(p.MaxDifficulty, p.Difficulty)
+}
+
+// Record 解答の結果で次の問題の難易度を決める（幅の中で、続けて正解すると上げ、続けてまちがえると下げる）
+func (p *SessionPlan) Record(isCorrect bool) {
+	if isCorrect {
+		p.correctStreak++
+		p.wrongStreak = 0
+		if p.correctStreak >= planRaiseStreak && p.Difficulty < p.MaxDifficulty {
+			p.Difficulty++
+			p.correctStreak = 0
+		}
+		return
+	}
+	p.wrongStreak++
+	p.correctStreak = 0
+	if p.wrongStreak >= planLowerStreak && p.Difficulty > p.MinDifficulty {
+		p.Difficulty--
+		p.wrongStreak = 0
+	}
+}
+
+// Summary 計画の説明（科目・問題数と時間・難易度・単元）
+func (p *SessionPlan) Summary() string {
+	topics := "学年の学習範囲全体"
+	if len(p.Topics) > 0 {
+		topics = strings.Join(p.Topics, "・")
+	}
+	difficulty := fmt.Sprintf("%d", p.Difficulty)
+	if p.MinDifficulty != p.MaxDifficulty {
+		difficulty = fmt.Sprintf("%d〜%d（最初は%d）", p.MinDifficulty, p.MaxDifficulty, p.Difficulty)
+	}
+	return fmt.Sprintf("科目: %s\n予定: %d問（約%d分）\n難易度: %s\n単元: %s",
+		p.Subject, p.Problems, p.ExpectedMinutes(), difficulty, topics)
+}
+
+// PlanSession 最近の学習記録と単元別の習熟度から、学習セッションの計画を立てる
+func (m *Manager) PlanSession(userID, subject string, difficulty int, now time.Time) (*SessionPlan, error) {
+	sessions, err := m.db.GetStudySessionsBetween(userID, now.Add(-planHistory), now.Add(time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("セッション取得エラー: %w", err)
+	}
+	stats, err := m.db.GetTopicMastery(userID)
+	if err != nil {
+		return nil, fmt.Errorf("単元別習熟度取得エラー: %w", err)
+	}
+	var topics []TopicMastery
+	for _, s := range stats {
+		if s.Subject == subject {
+			topics = append(topics, ComputeTopicMastery(s, now))
+		}
+	}
+	return BuildSessionPlan(subject, difficulty, sessions, topics), nil
+}
+
+// BuildSessionPlan 科目の設定の難易度・最近のセッション・単元別の習熟度から計画を立てる
+func BuildSessionPlan(subject string, difficulty int, sessions []database.StudySession, topics []TopicMastery) *SessionPlan {
+	plan := &SessionPlan{
+		Subject:       subject,
+		Difficulty:    clampDifficulty(difficulty),
+		Problems:      DefaultPlanProblems,
+		AnswerSeconds: defaultAnswerSeconds,
+	}
+
+	// 最近の正解率で難易度の幅を決める
+	problems, correct, seconds := 0, 0, 0
+	for i := range sessions {
+		session := &sessions[i]
+		if session.Subject != subject || session.IsManual() || session.TotalProblems == 0 {
+			continue
+		}
+		problems += session.TotalProblems
+		correct += session.CorrectAnswers
+		seconds += session.DurationSeconds()
+	}
+	plan.MinDifficulty = clampDifficulty(plan.Difficulty - 1)
+	plan.MaxDifficulty = clampDifficulty(plan.Difficulty + 1)
+	if problems > 0 {
+		accuracy := float64(correct) / float64(problems)
+		switch {
+		case accuracy >= planHighAccuracy:
+			plan.MinDifficulty = plan.Difficulty
+			plan.Reasons = append(plan.Reasons, fmt.Sprintf("最近の正解率が%.0f%%と高いので、難しい問題にも挑戦します", accuracy*100))
+		case accuracy < planLowAccuracy:
+			plan.MaxDifficulty = plan.Difficulty
+			plan.Reasons = append(plan.Reasons, fmt.Sprintf("最近の正解率が%.0f%%なので、解ける問題を増やしながら進めます", accuracy*100))
+		default:
+			plan.Reasons = append(plan.Reasons, fmt.Sprintf("最近の正解率は%.0f%%です。解答に合わせて難易度を上げ下げします", accuracy*100))
+		}
+		if seconds > 0 {
+			plan.AnswerSeconds = min(max(seconds/problems, minAnswerSeconds), maxAnswerSeconds)
+			plan.Reasons = append(plan.Reasons, fmt.Sprintf("最近は1問に平均%d秒かけています", plan.AnswerSeconds))
+		}
+	} else {
+		plan.Reasons = append(plan.Reasons, "最近この科目の記録がないので、設定の難易度を中心に出題します")
+	}
+	plan.Reasons = append(plan.Reasons, fmt.Sprintf("%d問続けて正解すると難易度を1つ上げ、%d問続けてまちがえると1つ下げます", planRaiseStreak, planLowerStreak))
+
+	// 苦手な単元・習熟度の低い単元を優先する
+	var candidates []TopicMastery
+	for _, topic := range topics {
+		if topic.Topic != "" && topic.Status != "strong" {
+			candidates = append(candidates, topic)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if (candidates[i].Status == "weak") != (candidates[j].Status == "weak") {
+			return candidates[i].Status == "weak"
+		}
+		return candidates[i].Mastery < candidates[j].Mastery
+	})
+	for i := 0; i < len(candidates) && i < planTopics; i++ {
+		plan.Topics = append(plan.Topics, candidates[i].Topic)
+	}
+	if len(plan.Topics) > 0 {
+		plan.Reasons = append(plan.Reasons, "習熟度の低い単元を優先して出題します")
+	}
+	return plan
+}
+
+// clampDifficulty 難易度を1〜5におさめる
+func clampDifficulty(difficulty int) int {
+	return min(max(difficulty, 1), 5)
+}
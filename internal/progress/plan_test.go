@@ -0,0 +1,78 @@
+package progress_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+)
+
+func TestBuildSessionPlan(t *testing.T) {
+	start := time.Date(2026, 10, 14, 17, 0, 0, 0, time.Local)
+	end := start.Add(20 * time.Minute)
+	sessions := []database.StudySession{
+		{Subject: "数学", StartTime: start, EndTime: &end, TotalProblems: 10, CorrectAnswers: 9},
+		{Subject: "英語", StartTime: start, EndTime: &end, TotalProblems: 10, CorrectAnswers: 2},
+	}
+	topics := []progress.TopicMastery{
+		{Topic: "一次関数", Status: "learning", Mastery: 0.5},
+		{Topic: "確率", Status: "weak", Mastery: 0.3},
+		{Topic: "連立方程式", Status: "strong", Mastery: 0.9},
+		{Topic: "式の計算", Status: "learning", Mastery: 0.6},
+		{Topic: "図形の性質と合同", Status: "learning", Mastery: 0.7},
+	}
+
+	plan := progress.BuildSessionPlan("数学", 3, sessions, topics)
+	if plan.MinDifficulty != 3 || plan.MaxDifficulty != 4 || plan.Difficulty != 3 {
+		t.Errorf("正解率が高いときの難易度の幅 = %d〜%d（最初は%d）", plan.MinDifficulty, plan.MaxDifficulty, plan.Difficulty)
+	}
+	if strings.Join(plan.Topics, "|") != "確率|一次関数|式の計算" {
+		t.Errorf("単元 = %q", plan.Topics)
+	}
+	if plan.AnswerSeconds != 120 || plan.ExpectedMinutes() != 20 {
+		t.Errorf("1問 %d秒・予定 %d分", plan.AnswerSeconds, plan.ExpectedMinutes())
+	}
+	if plan.TopicFor(4) != "一次関数" {
+		t.Errorf("5問目の単元 = %q", plan.TopicFor(4))
+	}
+
+	english := progress.BuildSessionPlan("英語", 1, sessions, nil)
+	if english.MinDifficulty != 1 || english.MaxDifficulty != 1 || len(english.Topics) != 0 {
+		t.Errorf("英語の計画 = %+v", english)
+	}
+	if !strings.Contains(english.Summary(), "単元: 学年の学習範囲全体") {
+		t.Errorf("単元を決めていないときの説明:\n%s", english.Summary())
+	}
+}
+
+func TestSessionPlanRecord(t *testing.T) {
+	plan := progress.BuildSessionPlan("理科", 3, nil, nil)
+	if plan.MinDifficulty != 2 || plan.MaxDifficulty != 4 {
+		t.Fatalf("記録がないときの幅 = %d〜%d", plan.MinDifficulty, plan.MaxDifficulty)
+	}
+
+	for range 3 {
+		plan.Record(true)
+	}
+	if plan.Difficulty != 4 {
+		t.Errorf("3問続けて正解したあとの難易度 = %d", plan.Difficulty)
+	}
+	for range 3 {
+		plan.Record(true)
+	}
+	if plan.Difficulty != 4 {
+		t.Errorf("幅をこえて上がらないはず: %d", plan.Difficulty)
+	}
+	plan.Record(false)
+	plan.Record(false)
+	if plan.Difficulty != 3 {
+		t.Errorf("2問続けてまちがえたあとの難易度 = %d", plan.Difficulty)
+	}
+
+	plan.SetDifficulty(5)
+	if plan.Difficulty != 5 || plan.MaxDifficulty != 5 {
+		t.Errorf("難易度を変えたあとの幅 = %d〜%d（最初は%d）", plan.MinDifficulty, plan.MaxDifficulty, plan.Difficulty)
+	}
+}
@@ -0,0 +1,101 @@
+package progress
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 選択肢の位置の偏りの判定
+const (
+	positionBiasDays       = 30
+	positionBiasMinAnswers = 20  // これより少ない解答では判定しない
+	positionBiasMinRatio   = 1.5 // どの位置も同じように選んだときの何倍以上選んでいれば偏りとみなすか
+	positionBiasMinZ       = 2.0 // 偶然の偏りとみなさない大きさ（標準偏差の何倍か）
+)
+
+// PositionBias 選択肢の何番目を選んだかの集計（選択肢は毎回並べ替えているので、ふつうはどの位置も同じくらいになる）
+type PositionBias struct {
+	Answers  int
+	Chosen   []int     // 位置ごとに選んだ回数（0から）
+	Expected []float64 // どの位置も同じように選んだときの回数
+	Biased   int       // 偏って選んでいる位置（0から。偏りがなければ-1）
+}
+
+// Share 位置を選んだ割合（0〜1）
+func (b PositionBias) Share(position int) float64 {
+	if b.Answers == 0 || position < 0 || position >= len(b.Chosen) {
+		return 0
+	}
+	return float64(b.Chosen[position]) / float64(b.Answers)
+}
+
+// ExpectedShare どの位置も同じように選んだときに、位置を選ぶ割合（0〜1）
+func (b PositionBias) ExpectedShare(position int) float64 {
+	if b.Answers == 0 || position < 0 || position >= len(b.Expected) {
+		return 0
+	}
+	return b.Expected[position] / float64(b.Answers)
+}
+
+// Description 偏りの説明（偏りがなければ空）
+func (b PositionBias) Description() string {
+	if b.Biased < 0 {
+		return ""
+	}
+	return fmt.Sprintf("選択肢の%d番目を選ぶことが多いようです（%.0f%%。どの位置も同じように選ぶと%.0f%%）",
+		b.Biased+1, b.Share(b.Biased)*100, b.ExpectedShare(b.Biased)*100)
+}
+
+// AnalyzePositionBias 選択肢の位置ごとの解答数から、特定の位置ばかり選んでいないかを調べる
+func AnalyzePositionBias(counts []database.AnswerPositionCount) PositionBias {
+	bias := PositionBias{Biased: -1}
+	positions := 0
+	for _, count := range counts {
+		positions = max(positions, count.OptionCount)
+	}
+	bias.Chosen = make([]int, positions)
+	bias.Expected = make([]float64, positions)
+	variance := make([]float64, positions)
+
+	for _, count := range counts {
+		if count.Position < 0 || count.Position >= count.OptionCount {
+			continue
+		}
+		bias.Answers += count.Answers
+		bias.Chosen[count.Position] += count.Answers
+		// 選択肢がk個の問題では、どの位置も1/kの確率で選ぶ
+		p := 1 / float64(count.OptionCount)
+		for position := range count.OptionCount {
+			bias.Expected[position] += float64(count.Answers) * p
+			variance[position] += float64(count.Answers) * p * (1 - p)
+		}
+	}
+	if bias.Answers < positionBiasMinAnswers {
+		return bias
+	}
+
+	bestZ := 0.0
+	for position, chosen := range bias.Chosen {
+		expected := bias.Expected[position]
+		if expected == 0 || variance[position] == 0 || float64(chosen) < positionBiasMinRatio*expected {
+			continue
+		}
+		z := (float64(chosen) - expected) / math.Sqrt(variance[position])
+		if z >= positionBiasMinZ && z > bestZ {
+			bias.Biased, bestZ = position, z
+		}
+	}
+	return bias
+}
+
+// GetPositionBias 最近の解答で、選択肢の位置の偏りを調べる
+func (m *Manager) GetPositionBias(userID string, now time.Time) (PositionBias, error) {
+	counts, err := m.db.GetAnswerPositionCounts(userID, now.AddDate(0, 0, -positionBiasDays))
+	if err != nil {
+		return PositionBias{Biased: -1}, fmt.Errorf("選択肢の位置の取得エラー: %w", err)
+	}
+	return AnalyzePositionBias(counts), nil
+}
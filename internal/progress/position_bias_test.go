@@ -0,0 +1,103 @@
+package progress_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/calendar"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+func TestAnalyzePositionBias(t *testing.T) {
+	// 4択で1番目ばかり選ぶ
+	bias := progress.AnalyzePositionBias([]database.AnswerPositionCount{
+		{OptionCount: 4, Position: 0, Answers: 18},
+		{OptionCount: 4, Position: 1, Answers: 4},
+		{OptionCount: 4, Position: 2, Answers: 5},
+		{OptionCount: 4, Position: 3, Answers: 3},
+	})
+	if bias.Answers != 30 || bias.Biased != 0 {
+		t.Fatalf("偏り = %+v, want 1番目", bias)
+	}
+	if bias.Share(0) != 0.6 || bias.ExpectedShare(0) != 0.25 {
+		t.Errorf("1番目の割合 = %.2f（均等なら%.2f）", bias.Share(0), bias.ExpectedShare(0))
+	}
+	if bias.Description() == "" {
+		t.Error("偏りの説明がありません")
+	}
+
+	// 選択肢の数がちがう問題がまざっていても、均等に選んでいれば偏りなし
+	even := progress.AnalyzePositionBias([]database.AnswerPositionCount{
+		{OptionCount: 2, Position: 0, Answers: 10},
+		{OptionCount: 2, Position: 1, Answers: 10},
+		{OptionCount: 4, Position: 0, Answers: 6},
+		{OptionCount: 4, Position: 1, Answers: 5},
+		{OptionCount: 4, Position: 2, Answers: 5},
+		{OptionCount: 4, Position: 3, Answers: 4},
+	})
+	if even.Biased != -1 || even.Description() != "" {
+		t.Errorf("均等な選び方の偏り = %+v", even)
+	}
+	if even.ExpectedShare(3) != 0.125 {
+		t.Errorf("4番目を選ぶ割合の期待値 = %.3f, want 0.125（4択の問題だけにある）", even.ExpectedShare(3))
+	}
+
+	// 解答が少なければ判定しない
+	if few := progress.AnalyzePositionBias([]database.AnswerPositionCount{{OptionCount: 4, Position: 0, Answers: 8}}); few.Biased != -1 {
+		t.Errorf("8問だけで偏りと判定しました: %+v", few)
+	}
+}
+
+func TestPositionBiasRecommendation(t *testing.T) {
+	db := testutil.NewDB(t)
+	now := time.Now()
+	user := testutil.Seed(t, db, testutil.Fixture{User: database.User{ID: "user-bias", Grade: 1}})
+	session := testutil.SeedSession(t, db, user.ID, "session-bias", testutil.SessionFixture{Subject: "数学", Start: now.Add(-time.Hour)})
+	for i := range 24 {
+		position := 0
+		if i%4 == 0 {
+			position = i / 4 % 4
+		}
+		err := db.CreateProblemResult(&database.ProblemResult{
+			ID: fmt.Sprintf("bias-%d", i), SessionID: session.ID, ProblemType: "正負の数", Difficulty: 2,
+			IsCorrect: position == 1, TimeTaken: 30, CreatedAt: now.Add(-time.Duration(30-i) * time.Minute),
+			AnswerPosition: position, OptionCount: 4,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 位置を記録していない解答は数えない
+	if err := db.CreateProblemResult(&database.ProblemResult{
+		ID: "bias-unrecorded", SessionID: session.ID, ProblemType: "正負の数", Difficulty: 2, CreatedAt: now,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := progress.NewManager(db, nil, calendar.New(nil))
+	bias, err := manager.GetPositionBias(user.ID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bias.Answers != 24 || bias.Biased != 0 {
+		t.Fatalf("偏り = %+v", bias)
+	}
+
+	analysis, err := manager.AnalyzeProgress(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patterns := analysis.WeaknessAnalysis.ErrorPatterns; len(patterns) != 1 || patterns[0].Type != progress.ErrorPatternPositionBias {
+		t.Errorf("エラーパターン = %+v", patterns)
+	}
+	found := false
+	for _, recommendation := range analysis.Recommendations {
+		found = found || recommendation.Type == "answer_habit"
+	}
+	if !found {
+		t.Errorf("選択肢の位置の偏りのおすすめがありません: %+v", analysis.Recommendations)
+	}
+}
@@ -1,18 +1,24 @@
 package progress
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
 	"sort"
 	"time"
 
-	"studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/calendar"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/xp"
 )
 
 // Manager 学習進捗管理システム
 type Manager struct {
-	db *database.DB
+	db       *database.DB
+	aiEngine *ai.Engine
+	calendar *calendar.Calendar
 }
 
 // LearningAnalysis 学習分析結果
@@ -24,12 +30,15 @@ type LearningAnalysis struct {
 	StrengthAnalysis *StrengthAnalysis         `json:"strength_analysis"`
 	Recommendations  []Recommendation          `json:"recommendations"`
 	StudyStreak      *StudyStreakInfo          `json:"study_streak"`
+	FocusAnalysis    *FocusAnalysis            `json:"focus_analysis"`
+	TopicMastery     map[string][]TopicMastery `json:"topic_mastery"` // 科目 → 単元別習熟度
 	LastUpdated      time.Time                 `json:"last_updated"`
 }
 
 // OverallProgress 全体進捗
 type OverallProgress struct {
-	TotalStudyTime   int     `json:"total_study_time"`   // 秒
+	TotalStudyTime   int     `json:"total_study_time"`   // 秒（手動記録を含む）
+	ManualStudyTime  int     `json:"manual_study_time"`  // 秒（アプリ外の学習）
 	TotalProblems    int     `json:"total_problems"`
 	TotalCorrect     int     `json:"total_correct"`
 	AccuracyRate     float64 `json:"accuracy_rate"`
@@ -80,6 +89,9 @@ type WeaknessItem struct {
 	Improvement   float64 `json:"improvement"`   // 改善度（%）
 }
 
+// ErrorPatternPositionBias 選択肢の特定の位置ばかり選ぶエラーパターン
+const ErrorPatternPositionBias = "position_bias"
+
 // ErrorPattern エラーパターン
 type ErrorPattern struct {
 	Type         string    `json:"type"`
@@ -139,11 +151,51 @@ type SessionSummary struct {
 	DominantEmotion  string    `json:"dominant_emotion"`
 	Improvements     []string  `json:"improvements"`
 	Challenges       []string  `json:"challenges"`
+	Topics           []SessionTopic `json:"topics"`    // 解いた単元（解いた順）
+	MaxCombo         int       `json:"max_combo"`
+	XPGained         int       `json:"xp_gained"`        // セッション中に獲得した経験値
+}
+
+// SessionTopic セッションで解いた単元ごとの結果
+type SessionTopic struct {
+	Topic     string `json:"topic"`
+	Attempted int    `json:"attempted"`
+	Correct   int    `json:"correct"`
+}
+
+// WeeklyReport 週間レポート
+type WeeklyReport struct {
+	UserID         string                         `json:"user_id"`
+	WeekStart      time.Time                      `json:"week_start"`
+	WeekEnd        time.Time                      `json:"week_end"`
+	TotalSessions  int                            `json:"total_sessions"`
+	TotalProblems  int                            `json:"total_problems"`
+	TotalCorrect   int                            `json:"total_correct"`
+	AccuracyRate   float64                        `json:"accuracy_rate"`
+	TotalStudyTime int                            `json:"total_study_time"` // 秒（手動記録を含む）
+	ManualTime     int                            `json:"manual_time"`      // 秒（アプリ外の学習）
+	FocusNote      string                         `json:"focus_note"`       // 集中度のまとめ（記録がなければ空）
+	StudyDays      int                            `json:"study_days"`
+	SubjectStats   map[string]*WeeklySubjectStats `json:"subject_stats"`
+	Strengths      []string                       `json:"strengths"`
+	Weaknesses     []string                       `json:"weaknesses"`
+	Summary        *ai.WeeklySummary              `json:"summary"`
+	GeneratedAt    time.Time                      `json:"generated_at"`
+}
+
+// WeeklySubjectStats 週間の科目別統計
+type WeeklySubjectStats struct {
+	Subject        string  `json:"subject"`
+	Sessions       int     `json:"sessions"`
+	TotalProblems  int     `json:"total_problems"`
+	CorrectAnswers int     `json:"correct_answers"`
+	AccuracyRate   float64 `json:"accuracy_rate"`
+	StudyTime      int     `json:"study_time"` // 秒
 }
 
 // NewManager プログレス管理システムを作成
-func NewManager(db *database.DB) *Manager {
-	return &Manager{db: db}
+func NewManager(db *database.DB, aiEngine *ai.Engine, cal *calendar.Calendar) *Manager {
+	return &Manager{db: db, aiEngine: aiEngine, calendar: cal}
 }
 
 // UpdateProgress 学習セッション後の進捗更新
@@ -311,6 +363,15 @@ func (m *Manager) AnalyzeProgress(userID string) (*LearningAnalysis, error) {
 		analysis.SubjectProgress[subject] = subjectAnalysis
 	}
 
+	// 単元別習熟度（科目別の得意・苦手な単元にも反映）
+	topicMastery, err := m.GetTopicMastery(userID)
+	if err == nil {
+		analysis.TopicMastery = topicMastery
+		for subject, subjectAnalysis := range analysis.SubjectProgress {
+			applyTopicAreas(subjectAnalysis, topicMastery[subject])
+		}
+	}
+
 	// 弱点分析
 	weaknessAnalysis, err := m.analyzeWeaknesses(userID)
 	if err == nil {
@@ -323,6 +384,12 @@ func (m *Manager) AnalyzeProgress(userID string) (*LearningAnalysis, error) {
 		analysis.StrengthAnalysis = strengthAnalysis
 	}
 
+	// 集中度分析（直近30日）
+	focusAnalysis, err := m.AnalyzeFocus(userID, 30)
+	if err == nil {
+		analysis.FocusAnalysis = focusAnalysis
+	}
+
 	// 推奨事項生成
 	analysis.Recommendations = m.generateRecommendations(analysis)
 
@@ -341,8 +408,6 @@ func (m *Manager) calculateOverallProgress(userID string, subjects []string) (*O
 
 	totalProblems := 0
 	totalCorrect := 0
-	totalStudyTime := 0
-	studyDays := make(map[string]bool)
 
 	for _, subject := range subjects {
 		subjectProgress, err := m.db.GetLearningProgress(userID, subject)
@@ -352,13 +417,15 @@ func (m *Manager) calculateOverallProgress(userID string, subjects []string) (*O
 
 		totalProblems += subjectProgress.TotalProblems
 		totalCorrect += subjectProgress.CorrectAnswers
-		totalStudyTime += subjectProgress.TotalStudyTime
+	}
 
-		if subjectProgress.LastStudyDate != nil {
-			dateKey := subjectProgress.LastStudyDate.Format("2006-01-02")
-			studyDays[dateKey] = true
-		}
+	// 学習時間・学習日数はセッション記録から集計（塾・ドリルなどの手動記録を含む）
+	totals, err := m.db.GetStudyTotals(userID)
+	if err != nil {
+		return nil, fmt.Errorf("学習セッション集計エラー: %w", err)
 	}
+	totalStudyTime := totals.StudySeconds
+	progress.ManualStudyTime = totals.ManualSeconds
 
 	// 精度計算
 	if totalProblems > 0 {
@@ -368,11 +435,15 @@ func (m *Manager) calculateOverallProgress(userID string, subjects []string) (*O
 	progress.TotalProblems = totalProblems
 	progress.TotalCorrect = totalCorrect
 	progress.TotalStudyTime = totalStudyTime
-	progress.StudyDaysCount = len(studyDays)
+	progress.StudyDaysCount = totals.StudyDays
 
-	// レベル計算（経験値ベース）
-	progress.ExperiencePoints = totalCorrect * 10
-	progress.CurrentLevel = 1 + (progress.ExperiencePoints / 1000)
+	// レベル計算（経験値の獲得履歴から）
+	totalXP, err := m.db.GetTotalXP(userID)
+	if err != nil {
+		return nil, fmt.Errorf("経験値取得エラー: %w", err)
+	}
+	progress.ExperiencePoints = totalXP
+	progress.CurrentLevel = xp.ProgressFor(totalXP).Level
 
 	// 平均セッション時間
 	if progress.StudyDaysCount > 0 {
@@ -545,6 +616,18 @@ func (m *Manager) analyzeWeaknesses(userID string) (*WeaknessAnalysis, error) {
 		analysis.RecommendedFocus = []string{analysis.TopWeaknesses[0].Subject}
 	}
 
+	// 答えを考えずに、決まった位置の選択肢を選んでいないか
+	now := time.Now()
+	if bias, err := m.GetPositionBias(userID, now); err == nil && bias.Biased >= 0 {
+		analysis.ErrorPatterns = append(analysis.ErrorPatterns, ErrorPattern{
+			Type:         ErrorPatternPositionBias,
+			Description:  bias.Description(),
+			Frequency:    bias.Chosen[bias.Biased],
+			LastOccurred: now,
+			IsActive:     true,
+		})
+	}
+
 	return analysis, nil
 }
 
@@ -652,6 +735,27 @@ func (m *Manager) generateRecommendations(analysis *LearningAnalysis) []Recommen
 		recommendations = append(recommendations, rec)
 	}
 
+	// 選択肢の位置の偏りに基づく推奨
+	if analysis.WeaknessAnalysis != nil {
+		for _, pattern := range analysis.WeaknessAnalysis.ErrorPatterns {
+			if pattern.Type != ErrorPatternPositionBias || !pattern.IsActive {
+				continue
+			}
+			recommendations = append(recommendations, Recommendation{
+				Type:        "answer_habit",
+				Title:       "選択肢を選ぶ位置に偏りがあります",
+				Description: pattern.Description + "。選択肢の順番は毎回変わるので、答えを考えてから選びましょう。",
+				Priority:    "medium",
+				Actions: []string{
+					"選択肢を見る前に、自分で答えを考える",
+					"すべての選択肢を最後まで読んでから選ぶ",
+					"迷ったら、ちがう選択肢を消してしぼる",
+				},
+				ExpectedEffect: "当てずっぽうの解答が減り、正解率の向上が期待できます",
+			})
+		}
+	}
+
 	// 学習時間に基づく推奨
 	if analysis.OverallProgress != nil && analysis.OverallProgress.AverageSessionTime < 900 { // 15分未満
 		rec := Recommendation{
@@ -669,6 +773,24 @@ func (m *Manager) generateRecommendations(analysis *LearningAnalysis) []Recommen
 		recommendations = append(recommendations, rec)
 	}
 
+	// 集中度に基づく推奨
+	if focus := analysis.FocusAnalysis; focus != nil && focus.LowFocusTimeOfDay != "" {
+		rec := Recommendation{
+			Type:  "focus_time",
+			Title: fmt.Sprintf("%sの集中度が低いようです", focus.LowFocusTimeOfDay),
+			Description: fmt.Sprintf("%sの集中度は%.0f点で、全体平均（%.0f点）より低めです。学習する時間帯を工夫してみましょう。",
+				focus.LowFocusTimeOfDay, focus.ByTimeOfDay[focus.LowFocusTimeOfDay], focus.AverageScore),
+			Priority: "medium",
+			Actions: []string{
+				"集中しやすい時間帯に難しい科目を回す",
+				fmt.Sprintf("%sは復習や暗記など軽めの学習にする", focus.LowFocusTimeOfDay),
+				"25分ごとの休憩をきちんと取る",
+			},
+			ExpectedEffect: "同じ学習時間でも理解度の向上が期待できます",
+		}
+		recommendations = append(recommendations, rec)
+	}
+
 	// 学習継続に基づく推奨
 	if analysis.StudyStreak != nil && analysis.StudyStreak.CurrentStreak < 3 {
 		rec := Recommendation{
@@ -691,7 +813,9 @@ func (m *Manager) generateRecommendations(analysis *LearningAnalysis) []Recommen
 
 // calculateStudyStreak 学習継続情報を計算
 func (m *Manager) calculateStudyStreak(userID string) (*StudyStreakInfo, error) {
-	sessions, err := m.db.GetRecentStudySessions(userID, 30)
+	// 100日連続などの長い記録も判定できるよう、過去1年分のセッションから計算
+	now := time.Now()
+	sessions, err := m.db.GetStudySessionsBetween(userID, now.AddDate(-1, 0, 0), now.AddDate(0, 0, 1))
 	if err != nil {
 		return nil, err
 	}
@@ -719,32 +843,31 @@ func (m *Manager) calculateStudyStreak(userID string) (*StudyStreakInfo, error)
 	}
 	sort.Strings(dates)
 
-	// 継続日数の計算
-	today := time.Now().Format("2006-01-02")
-	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
-
-	if studyDates[today] || studyDates[yesterday] {
-		// 今日または昨日学習している場合、継続中
-		for i := len(dates) - 1; i >= 0; i-- {
-			date, _ := time.Parse("2006-01-02", dates[i])
-			expectedDate := time.Now().AddDate(0, 0, -currentStreak)
-			if date.Format("2006-01-02") == expectedDate.Format("2006-01-02") {
-				currentStreak++
-			} else {
-				break
-			}
+	// 継続日数の計算（祝日・長期休みは学習しなくても途切れない）
+	cursor := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if !studyDates[cursor.Format("2006-01-02")] {
+		// 今日まだ学習していなくても、昨日まで続いていれば継続中
+		cursor = cursor.AddDate(0, 0, -1)
+	}
+	oldest, _ := time.ParseInLocation("2006-01-02", dates[0], now.Location())
+	var streakStart time.Time
+	for !cursor.Before(oldest) {
+		if studyDates[cursor.Format("2006-01-02")] {
+			currentStreak++
+			streakStart = cursor
+		} else if !m.calendar.Day(cursor).IsGraceDay() {
+			break
 		}
+		cursor = cursor.AddDate(0, 0, -1)
 	}
 
 	// 最長継続日数の計算
 	for i := 0; i < len(dates); i++ {
-		tempStreak = 1
+		tempStreak++
 		if i > 0 {
-			prevDate, _ := time.Parse("2006-01-02", dates[i-1])
-			currentDate, _ := time.Parse("2006-01-02", dates[i])
-			if currentDate.Sub(prevDate).Hours() == 24 {
-				tempStreak++
-			} else {
+			prevDate, _ := time.ParseInLocation("2006-01-02", dates[i-1], now.Location())
+			currentDate, _ := time.ParseInLocation("2006-01-02", dates[i], now.Location())
+			if !m.onlyGraceDaysBetween(prevDate, currentDate) {
 				tempStreak = 1
 			}
 		}
@@ -778,68 +901,287 @@ func (m *Manager) calculateStudyStreak(userID string) (*StudyStreakInfo, error)
 	}
 
 	if len(sessions) > 0 {
-		streakInfo.LastStudyDate = sessions[0].StartTime
+		streakInfo.LastStudyDate = sessions[len(sessions)-1].StartTime
 		if currentStreak > 0 {
-			streakInfo.StreakStartDate = time.Now().AddDate(0, 0, -currentStreak+1)
+			streakInfo.StreakStartDate = streakStart
 		}
 	}
 
 	return streakInfo, nil
 }
 
-// GenerateSessionSummary セッション要約を生成
+// GetStudyStreak 学習継続情報を取得
+func (m *Manager) GetStudyStreak(userID string) (*StudyStreakInfo, error) {
+	return m.calculateStudyStreak(userID)
+}
+
+// onlyGraceDaysBetween 2つの学習日の間が祝日・長期休みだけかどうか（連続とみなせるか）
+func (m *Manager) onlyGraceDaysBetween(from, to time.Time) bool {
+	for day := from.AddDate(0, 0, 1); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !m.calendar.Day(day).IsGraceDay() {
+			return false
+		}
+	}
+	return true
+}
+
+// セッション要約で得意・苦手とみなす単元の正解率
+const (
+	summaryGoodAccuracy = 0.8
+	summaryPoorAccuracy = 0.5
+)
+
+// GenerateSessionSummary セッション要約を生成（正解率・学習時間・単元ごとの結果・獲得した経験値）
 func (m *Manager) GenerateSessionSummary(sessionID string) (*SessionSummary, error) {
-	// セッション情報を取得
-	// 実際の実装では、セッションIDを使用してデータベースから詳細情報を取得
-	
-	// プレースホルダーの実装
-	summary := &SessionSummary{
-		SessionID: sessionID,
-		// 他のフィールドは実際のデータベースクエリで取得
+	session, err := m.db.GetStudySession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("セッション取得エラー: %w", err)
+	}
+	if session == nil {
+		return nil, fmt.Errorf("セッションが見つかりません: %s", sessionID)
 	}
 
+	results, err := m.db.GetProblemResultsBySession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("解答結果取得エラー: %w", err)
+	}
+
+	// 終了していないセッションは最後の解答までを学習時間にする
+	end := session.StartTime
+	if session.EndTime != nil {
+		end = *session.EndTime
+	} else if len(results) > 0 {
+		end = results[len(results)-1].CreatedAt
+	}
+
+	xpGained, err := m.db.GetXPBetween(session.UserID, session.StartTime, end)
+	if err != nil {
+		return nil, fmt.Errorf("経験値取得エラー: %w", err)
+	}
+
+	summary := SummarizeSession(session, results, end)
+	summary.XPGained = xpGained
 	return summary, nil
 }
 
-// GetProgressTrend 進捗トレンドを取得
+// SummarizeSession セッションと解答結果から要約を作る（endまでを学習時間にする。経験値は含まない）
+func SummarizeSession(session *database.StudySession, results []database.ProblemResult, end time.Time) *SessionSummary {
+	summary := &SessionSummary{
+		SessionID:         session.ID,
+		Subject:           session.Subject,
+		StartTime:         session.StartTime,
+		Duration:          max(int(end.Sub(session.StartTime).Seconds()), 0),
+		ProblemsAttempted: len(results),
+		MaxCombo:          session.MaxCombo,
+	}
+
+	topicIndex := make(map[string]int)
+	emotions := make(map[string]int)
+	totalTime := 0
+	for _, result := range results {
+		if result.IsCorrect {
+			summary.CorrectAnswers++
+		}
+		totalTime += result.TimeTaken
+		if result.EmotionAtAnswer != "" {
+			emotions[result.EmotionAtAnswer]++
+		}
+
+		topic := result.ProblemType
+		if topic == "" {
+			topic = "その他"
+		}
+		i, ok := topicIndex[topic]
+		if !ok {
+			i = len(summary.Topics)
+			topicIndex[topic] = i
+			summary.Topics = append(summary.Topics, SessionTopic{Topic: topic})
+		}
+		summary.Topics[i].Attempted++
+		if result.IsCorrect {
+			summary.Topics[i].Correct++
+		}
+	}
+
+	if len(results) > 0 {
+		summary.AccuracyRate = float64(summary.CorrectAnswers) / float64(len(results))
+		summary.AverageTime = float64(totalTime) / float64(len(results))
+	}
+
+	// 一番多かった気持ち（同じ数なら名前順で先のもの）
+	for emotion, count := range emotions {
+		best := emotions[summary.DominantEmotion]
+		if count > best || (count == best && emotion < summary.DominantEmotion) {
+			summary.DominantEmotion = emotion
+		}
+	}
+
+	for _, topic := range summary.Topics {
+		accuracy := float64(topic.Correct) / float64(topic.Attempted)
+		switch {
+		case accuracy >= summaryGoodAccuracy:
+			summary.Improvements = append(summary.Improvements, fmt.Sprintf("%s（%d問中%d問正解）", topic.Topic, topic.Attempted, topic.Correct))
+		case accuracy < summaryPoorAccuracy:
+			summary.Challenges = append(summary.Challenges, fmt.Sprintf("%s（%d問中%d問正解）", topic.Topic, topic.Attempted, topic.Correct))
+		}
+	}
+
+	return summary
+}
+
+// GetProgressTrend 進捗トレンドを取得（直近days日間の、問題を解いた日ごとの正解率）
 func (m *Manager) GetProgressTrend(userID string, subject string, days int) ([]float64, error) {
-	sessions, err := m.db.GetRecentStudySessions(userID, days)
+	points, err := m.GetTrendPoints(userID, subject, days, 1)
 	if err != nil {
 		return nil, err
 	}
 
-	// 日付別精度の計算
-	dailyAccuracy := make(map[string][]float64)
-	
+	var trend []float64
+	for _, point := range points {
+		if point.TotalProblems > 0 {
+			trend = append(trend, point.AccuracyRate)
+		}
+	}
+
+	return trend, nil
+}
+
+// GenerateWeeklyReport 先週（月曜〜日曜）の週間レポートを生成
+func (m *Manager) GenerateWeeklyReport(ctx context.Context, userID string, grade int) (*WeeklyReport, error) {
+	weekEnd := weekStartOf(time.Now())
+	weekStart := weekEnd.AddDate(0, 0, -7)
+
+	sessions, err := m.db.GetStudySessionsBetween(userID, weekStart, weekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("セッション取得エラー: %w", err)
+	}
+
+	results, err := m.db.GetProblemResultsBetween(userID, weekStart, weekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("解答結果取得エラー: %w", err)
+	}
+
+	report := &WeeklyReport{
+		UserID:        userID,
+		WeekStart:     weekStart,
+		WeekEnd:       weekEnd,
+		TotalSessions: len(sessions),
+		SubjectStats:  make(map[string]*WeeklySubjectStats),
+		GeneratedAt:   time.Now(),
+	}
+
+	// 科目別・日別の集計
+	studyDays := make(map[string]bool)
 	for _, session := range sessions {
-		if subject == "" || session.Subject == subject {
-			if session.TotalProblems > 0 {
-				accuracy := float64(session.CorrectAnswers) / float64(session.TotalProblems)
-				dateKey := session.StartTime.Format("2006-01-02")
-				dailyAccuracy[dateKey] = append(dailyAccuracy[dateKey], accuracy)
-			}
+		stats, exists := report.SubjectStats[session.Subject]
+		if !exists {
+			stats = &WeeklySubjectStats{Subject: session.Subject}
+			report.SubjectStats[session.Subject] = stats
+		}
+
+		duration := session.DurationSeconds()
+		if session.IsManual() {
+			report.ManualTime += duration
+		}
+
+		stats.Sessions++
+		stats.TotalProblems += session.TotalProblems
+		stats.CorrectAnswers += session.CorrectAnswers
+		stats.StudyTime += duration
+
+		report.TotalProblems += session.TotalProblems
+		report.TotalCorrect += session.CorrectAnswers
+		report.TotalStudyTime += duration
+		studyDays[session.StartTime.Format("2006-01-02")] = true
+	}
+	report.StudyDays = len(studyDays)
+
+	for _, stats := range report.SubjectStats {
+		if stats.TotalProblems > 0 {
+			stats.AccuracyRate = float64(stats.CorrectAnswers) / float64(stats.TotalProblems)
 		}
 	}
+	if report.TotalProblems > 0 {
+		report.AccuracyRate = float64(report.TotalCorrect) / float64(report.TotalProblems)
+	}
 
-	// 日付順にソートして平均を計算
-	var trend []float64
-	var dates []string
-	for date := range dailyAccuracy {
-		dates = append(dates, date)
+	// 問題タイプ別の強み・弱み（updateStrengthWeaknessと同じ基準）
+	typeStats := make(map[string]struct {
+		total   int
+		correct int
+	})
+	for _, result := range results {
+		stats := typeStats[result.ProblemType]
+		stats.total++
+		if result.IsCorrect {
+			stats.correct++
+		}
+		typeStats[result.ProblemType] = stats
 	}
-	sort.Strings(dates)
+	for problemType, stats := range typeStats {
+		if problemType == "" || stats.total < 3 {
+			continue
+		}
+		accuracy := float64(stats.correct) / float64(stats.total)
+		if accuracy >= 0.8 {
+			report.Strengths = append(report.Strengths, problemType)
+		} else if accuracy < 0.6 {
+			report.Weaknesses = append(report.Weaknesses, problemType)
+		}
+	}
+	sort.Strings(report.Strengths)
+	sort.Strings(report.Weaknesses)
 
-	for _, date := range dates {
-		accuracies := dailyAccuracy[date]
-		sum := 0.0
-		for _, acc := range accuracies {
-			sum += acc
+	// 週内の集中度
+	if focuses, err := m.db.GetSessionFocusSince(userID, weekStart); err == nil {
+		var weekFocuses []database.SessionFocus
+		for _, focus := range focuses {
+			if focus.StartedAt.Before(weekEnd) {
+				weekFocuses = append(weekFocuses, focus)
+			}
+		}
+		report.FocusNote = analyzeFocus(weekFocuses).FocusNote()
+	}
+
+	// AIによる自然言語の要約
+	if m.aiEngine != nil {
+		summaryReq := ai.WeeklySummaryRequest{
+			Grade:         grade,
+			StudyDays:     report.StudyDays,
+			StudyMinutes:  report.TotalStudyTime / 60,
+			ManualMinutes: report.ManualTime / 60,
+			FocusNote:     report.FocusNote,
+			TotalProblems: report.TotalProblems,
+			AccuracyRate:  report.AccuracyRate,
+			Strengths:     report.Strengths,
+			Weaknesses:    report.Weaknesses,
 		}
-		avgAccuracy := sum / float64(len(accuracies))
-		trend = append(trend, avgAccuracy)
+		for _, subject := range []string{"数学", "英語", "国語", "理科", "社会"} {
+			if stats, exists := report.SubjectStats[subject]; exists {
+				summaryReq.SubjectResults = append(summaryReq.SubjectResults, ai.SessionInfo{
+					Subject:       subject,
+					AccuracyRate:  stats.AccuracyRate,
+					ProblemsCount: stats.TotalProblems,
+					StudyTime:     stats.StudyTime,
+				})
+			}
+		}
+
+		summary, err := m.aiEngine.GenerateWeeklySummary(ctx, summaryReq)
+		if err != nil {
+			return nil, fmt.Errorf("週間レポート要約生成エラー: %w", err)
+		}
+		report.Summary = summary
 	}
 
-	return trend, nil
+	return report, nil
+}
+
+// weekStartOf 指定日の週の月曜日0時を取得
+func weekStartOf(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	date := t.AddDate(0, 0, -daysSinceMonday)
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, t.Location())
 }
 
 // Close プログレス管理システムをクリーンアップ
@@ -0,0 +1,83 @@
+package progress_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/calendar"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+// benchResults ベンチマークに使う解答の数（数年間毎日学習した生徒を想定）
+const benchResults = 100_000
+
+// BenchmarkAnalytics 10万問の学習履歴で、進捗タブとホーム画面の集計を計測
+// （go test ./internal/progress -run ^$ -bench Analytics -cpuprofile cpu.out で重い処理を調べられる）
+func BenchmarkAnalytics(b *testing.B) {
+	if testing.Short() {
+		b.Skip("学習履歴の生成に時間がかかるため -short では省略")
+	}
+
+	db := testutil.NewDB(b)
+	now := time.Now()
+	user := testutil.Seed(b, db, testutil.Fixture{User: database.User{ID: "bench-user", Grade: 2}})
+	testutil.GenerateHistory(b, db, user.ID, benchResults, now)
+
+	cfg := config.Default()
+	manager := progress.NewManager(db, nil, calendar.New(cfg))
+
+	b.Run("AnalyzeProgress", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := manager.AnalyzeProgress(user.ID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("GetStudyStreak", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := manager.GetStudyStreak(user.ID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("GetTrendPoints", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := manager.GetTrendPoints(user.ID, "数学", 90, 7); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("GetTopicMastery", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := manager.GetTopicMastery(user.ID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	// ホーム画面・日記・元気の表示で使う問い合わせ
+	b.Run("GetRecentStudySessions", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := db.GetRecentStudySessions(user.ID, 7); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("GetTotalXP", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := db.GetTotalXP(user.ID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("GetProblemResultsBetween", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := db.GetProblemResultsBetween(user.ID, now.AddDate(0, 0, -7), now); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
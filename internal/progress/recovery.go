@@ -0,0 +1,63 @@
+package progress
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// RecoverOpenSessions アプリが途中で落ちて終了していない学習セッションを、保存済みの解答から集計し直して終了する
+// （終了時刻は最後の解答の時刻。解答がなければ開始時刻）。終了したセッションを開始順に返す
+func (m *Manager) RecoverOpenSessions(userID string) ([]database.StudySession, error) {
+	sessions, err := m.db.GetOpenStudySessions(userID)
+	if err != nil {
+		return nil, fmt.Errorf("終了していないセッションの取得エラー: %w", err)
+	}
+
+	for i := range sessions {
+		session := &sessions[i]
+		results, err := m.db.GetProblemResultsBySession(session.ID)
+		if err != nil {
+			return nil, fmt.Errorf("解答結果取得エラー: %w", err)
+		}
+		RebuildSessionStats(session, results)
+		if err := m.db.UpdateStudySession(session); err != nil {
+			return nil, fmt.Errorf("セッション終了エラー: %w", err)
+		}
+	}
+	return sessions, nil
+}
+
+// RebuildSessionStats 解答結果（解答順）からセッションの問題数・正解数・最大コンボと終了時刻を計算し直す
+// （解答の保存のあとセッションを更新する前に落ちても、解答の記録に合わせる）
+func RebuildSessionStats(session *database.StudySession, results []database.ProblemResult) {
+	session.TotalProblems, session.CorrectAnswers, session.MaxCombo = len(results), 0, 0
+	end, combo := session.StartTime, 0
+	for _, result := range results {
+		if result.IsCorrect {
+			session.CorrectAnswers++
+			combo++
+		} else {
+			combo = 0
+		}
+		session.MaxCombo = max(session.MaxCombo, combo)
+		if result.CreatedAt.After(end) {
+			end = result.CreatedAt
+		}
+	}
+	session.EndTime = &end
+}
+
+// ResumeSession 途中で終わったセッションを再開できるよう、終了していない状態に戻す
+// （落ちてから再開するまでの時間は一時停止していた時間として学習時間に含めない）
+func (m *Manager) ResumeSession(session *database.StudySession, now time.Time) error {
+	if session.EndTime != nil && now.After(*session.EndTime) {
+		session.PausedSeconds += int(now.Sub(*session.EndTime).Seconds())
+	}
+	session.EndTime = nil
+	if err := m.db.UpdateStudySession(session); err != nil {
+		return fmt.Errorf("セッション再開エラー: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package progress_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/calendar"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+func TestRecoverOpenSessions(t *testing.T) {
+	db := testutil.NewDB(t)
+	now := time.Now()
+	user := testutil.Seed(t, db, testutil.Fixture{User: database.User{ID: "user-recover", Grade: 2}})
+	start := now.Add(-2 * time.Hour)
+	testutil.SeedSession(t, db, user.ID, "session-finished", testutil.SessionFixture{
+		Subject: "英語", Start: start.Add(-24 * time.Hour), Minutes: 10,
+		Results: []testutil.ResultFixture{{ProblemType: "不定詞", Difficulty: 2, Correct: true}},
+	})
+	crashed := testutil.SeedSession(t, db, user.ID, "session-crashed", testutil.SessionFixture{
+		Subject: "数学",
+		Start:   start,
+		Results: []testutil.ResultFixture{
+			{ProblemType: "一次関数", Difficulty: 2, Correct: true, TimeTaken: 30},
+			{ProblemType: "一次関数", Difficulty: 2, Correct: true, TimeTaken: 30},
+			{ProblemType: "一次関数", Difficulty: 2, Correct: false, TimeTaken: 30},
+			{ProblemType: "連立方程式", Difficulty: 3, Correct: true, TimeTaken: 30},
+		},
+	})
+	// 3問目の解答を保存したあと、セッションを更新する前に落ちた
+	crashed.EndTime, crashed.TotalProblems, crashed.CorrectAnswers, crashed.MaxCombo = nil, 2, 2, 2
+	crashed.PausedSeconds = 20
+	if err := db.UpdateStudySession(crashed); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := progress.NewManager(db, nil, calendar.New(nil))
+	recovered, err := manager.RecoverOpenSessions(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recovered) != 1 || recovered[0].ID != crashed.ID {
+		t.Fatalf("終了したセッション = %+v", recovered)
+	}
+	session, err := db.GetStudySession(crashed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.EndTime == nil || !session.EndTime.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("終了時刻 = %v, want 最後の解答の時刻 %v", session.EndTime, start.Add(2*time.Minute))
+	}
+	if session.TotalProblems != 4 || session.CorrectAnswers != 3 || session.MaxCombo != 2 {
+		t.Errorf("集計し直した統計 = %d問中%d問正解・最大%dコンボ, want 4問中3問・最大2コンボ",
+			session.TotalProblems, session.CorrectAnswers, session.MaxCombo)
+	}
+	if session.DurationSeconds() != 100 {
+		t.Errorf("学習時間 = %d秒, want 100秒（一時停止の20秒を除く）", session.DurationSeconds())
+	}
+	if again, err := manager.RecoverOpenSessions(user.ID); err != nil || len(again) != 0 {
+		t.Errorf("2回目に終了したセッション = %+v, %v", again, err)
+	}
+
+	// 再開すると、落ちていた時間は一時停止していた時間になる
+	if err := manager.ResumeSession(session, start.Add(32*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if session, err = db.GetStudySession(crashed.ID); err != nil {
+		t.Fatal(err)
+	}
+	if session.EndTime != nil || session.PausedSeconds != 20+30*60 {
+		t.Errorf("再開したセッション = 終了時刻%v・一時停止%d秒", session.EndTime, session.PausedSeconds)
+	}
+}
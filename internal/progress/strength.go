@@ -0,0 +1,57 @@
+package progress
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 得意な科目とみなす最近の学習
+const (
+	strengthHistory     = 30 * 24 * time.Hour
+	strengthMinProblems = 10
+	strengthAccuracy    = 0.8
+)
+
+// StrongSubjects 最近30日の学習で正解率の高い科目（正解率の高い順。関連付け説明のたとえに使う）
+func (m *Manager) StrongSubjects(userID string, now time.Time) ([]StrengthItem, error) {
+	sessions, err := m.db.GetStudySessionsBetween(userID, now.Add(-strengthHistory), now.Add(time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("セッション取得エラー: %w", err)
+	}
+	return SummarizeStrengths(sessions), nil
+}
+
+// SummarizeStrengths 学習セッションから、問題数が十分で正解率の高い科目を選ぶ
+func SummarizeStrengths(sessions []database.StudySession) []StrengthItem {
+	totals := make(map[string][2]int) // 科目 → [問題数, 正解数]
+	for i := range sessions {
+		session := &sessions[i]
+		if session.IsManual() || session.TotalProblems == 0 {
+			continue
+		}
+		total := totals[session.Subject]
+		total[0] += session.TotalProblems
+		total[1] += session.CorrectAnswers
+		totals[session.Subject] = total
+	}
+
+	var strengths []StrengthItem
+	for subject, total := range totals {
+		if total[0] < strengthMinProblems {
+			continue
+		}
+		if accuracy := float64(total[1]) / float64(total[0]); accuracy >= strengthAccuracy {
+			strengths = append(strengths, StrengthItem{Subject: subject, ProblemType: subject + "_general", AccuracyRate: accuracy})
+		}
+	}
+	sort.Slice(strengths, func(i, j int) bool {
+		if strengths[i].AccuracyRate != strengths[j].AccuracyRate {
+			return strengths[i].AccuracyRate > strengths[j].AccuracyRate
+		}
+		return strengths[i].Subject < strengths[j].Subject
+	})
+	return strengths
+}
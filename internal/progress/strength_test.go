@@ -0,0 +1,23 @@
+package progress_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+)
+
+func TestSummarizeStrengths(t *testing.T) {
+	start := time.Date(2026, 10, 14, 17, 0, 0, 0, time.Local)
+	sessions := []database.StudySession{
+		{Subject: "理科", StartTime: start, TotalProblems: 12, CorrectAnswers: 11},
+		{Subject: "社会", StartTime: start, TotalProblems: 10, CorrectAnswers: 8},
+		{Subject: "数学", StartTime: start, TotalProblems: 20, CorrectAnswers: 10},
+		{Subject: "英語", StartTime: start, TotalProblems: 5, CorrectAnswers: 5}, // 問題数が少ない
+	}
+	strengths := progress.SummarizeStrengths(sessions)
+	if len(strengths) != 2 || strengths[0].Subject != "理科" || strengths[1].Subject != "社会" {
+		t.Errorf("得意な科目 = %+v", strengths)
+	}
+}
@@ -0,0 +1,67 @@
+package progress_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/calendar"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+func TestGenerateSessionSummary(t *testing.T) {
+	db := testutil.NewDB(t)
+	now := time.Now()
+	user := testutil.Seed(t, db, testutil.Fixture{User: database.User{ID: "user-summary", Grade: 2}})
+	start := now.Add(-time.Hour)
+	session := testutil.SeedSession(t, db, user.ID, "session-summary", testutil.SessionFixture{
+		Subject: "数学",
+		Start:   start,
+		Results: []testutil.ResultFixture{
+			{ProblemType: "一次関数", Difficulty: 2, Correct: true, TimeTaken: 40},
+			{ProblemType: "連立方程式", Difficulty: 3, Correct: false, TimeTaken: 120},
+			{ProblemType: "一次関数", Difficulty: 2, Correct: true, TimeTaken: 50},
+			{ProblemType: "連立方程式", Difficulty: 3, Correct: false, TimeTaken: 90},
+		},
+	})
+
+	// セッション中の経験値だけを数える
+	for i, event := range []struct {
+		amount int
+		at     time.Time
+	}{{12, start.Add(time.Minute)}, {8, start.Add(5 * time.Minute)}, {100, start.Add(-time.Hour)}} {
+		err := db.CreateXPEvent(&database.XPEvent{
+			ID: fmt.Sprintf("xp-%d", i), UserID: user.ID, Source: "answer", Amount: event.amount, CreatedAt: event.at,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	summary, err := progress.NewManager(db, nil, calendar.New(nil)).GenerateSessionSummary(session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Subject != "数学" || summary.ProblemsAttempted != 4 || summary.CorrectAnswers != 2 || summary.AccuracyRate != 0.5 {
+		t.Errorf("正解数 = %+v", summary)
+	}
+	if summary.Duration != 300 || summary.AverageTime != 75 {
+		t.Errorf("学習時間 = %d秒・平均%.0f秒, want 300秒・平均75秒", summary.Duration, summary.AverageTime)
+	}
+	if summary.XPGained != 20 {
+		t.Errorf("獲得した経験値 = %d, want 20", summary.XPGained)
+	}
+	want := []progress.SessionTopic{{Topic: "一次関数", Attempted: 2, Correct: 2}, {Topic: "連立方程式", Attempted: 2, Correct: 0}}
+	if len(summary.Topics) != len(want) || summary.Topics[0] != want[0] || summary.Topics[1] != want[1] {
+		t.Errorf("単元 = %+v, want %+v", summary.Topics, want)
+	}
+	if len(summary.Improvements) != 1 || len(summary.Challenges) != 1 {
+		t.Errorf("できた単元 = %v・苦手な単元 = %v", summary.Improvements, summary.Challenges)
+	}
+
+	if _, err := progress.NewManager(db, nil, calendar.New(nil)).GenerateSessionSummary("missing"); err == nil {
+		t.Error("存在しないセッションの要約を作りました")
+	}
+}
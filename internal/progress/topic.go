@@ -0,0 +1,93 @@
+package progress
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 単元別習熟度の計算ルール
+const (
+	topicConfidentAttempts = 5    // この問題数に達するまでは習熟度を控えめに見積もる
+	topicForgetDays        = 30.0 // 最後の学習からこの日数で習熟度が半分になる（忘却の目安）
+	topicStrongMastery     = 0.75 // これ以上を「得意」とみなす
+	topicWeakMastery       = 0.45 // これ未満を「苦手」とみなす
+)
+
+// TopicMastery 単元別の習熟度
+type TopicMastery struct {
+	Subject      string    `json:"subject"`
+	Topic        string    `json:"topic"`
+	Attempts     int       `json:"attempts"`
+	AccuracyRate float64   `json:"accuracy_rate"`
+	LastStudied  time.Time `json:"last_studied"`
+	Mastery      float64   `json:"mastery"` // 0-1（正解率×問題数の確かさ×記憶の新しさ）
+	Status       string    `json:"status"`  // "strong" | "learning" | "weak"
+}
+
+// ComputeTopicMastery 解答統計から単元の習熟度を計算
+func ComputeTopicMastery(stats database.TopicMastery, now time.Time) TopicMastery {
+	topic := TopicMastery{
+		Subject:     stats.Subject,
+		Topic:       stats.Topic,
+		Attempts:    stats.Attempts,
+		LastStudied: stats.LastStudied,
+	}
+	if stats.Attempts == 0 {
+		topic.Status = "learning"
+		return topic
+	}
+
+	topic.AccuracyRate = float64(stats.CorrectAnswers) / float64(stats.Attempts)
+
+	// 解いた問題が少ないうちは正解率を割り引く
+	confidence := math.Min(float64(stats.Attempts)/topicConfidentAttempts, 1)
+	// しばらく解いていない単元は少しずつ習熟度が下がる
+	days := math.Max(now.Sub(stats.LastStudied).Hours()/24, 0)
+	recency := math.Pow(0.5, days/topicForgetDays)
+
+	topic.Mastery = topic.AccuracyRate * confidence * recency
+	switch {
+	case topic.Mastery >= topicStrongMastery:
+		topic.Status = "strong"
+	case topic.Mastery < topicWeakMastery && stats.Attempts >= 3:
+		topic.Status = "weak"
+	default:
+		topic.Status = "learning"
+	}
+
+	return topic
+}
+
+// GetTopicMastery 科目ごとの単元別習熟度を取得
+func (m *Manager) GetTopicMastery(userID string) (map[string][]TopicMastery, error) {
+	stats, err := m.db.GetTopicMastery(userID)
+	if err != nil {
+		return nil, fmt.Errorf("単元別習熟度取得エラー: %w", err)
+	}
+
+	now := time.Now()
+	topics := make(map[string][]TopicMastery)
+	for _, s := range stats {
+		topics[s.Subject] = append(topics[s.Subject], ComputeTopicMastery(s, now))
+	}
+
+	return topics, nil
+}
+
+// applyTopicAreas 単元別習熟度から科目の得意・苦手な単元を設定（記録済みの値がない場合のみ）
+func applyTopicAreas(analysis *SubjectAnalysis, topics []TopicMastery) {
+	if len(analysis.StrengthAreas) > 0 || len(analysis.WeaknessAreas) > 0 {
+		return
+	}
+	for _, topic := range topics {
+		switch topic.Status {
+		case "strong":
+			analysis.StrengthAreas = append(analysis.StrengthAreas, topic.Topic)
+		case "weak":
+			analysis.WeaknessAreas = append(analysis.WeaknessAreas, topic.Topic)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package progress
+
+import (
+	"fmt"
+	"time"
+)
+
+// TrendPoint 学習の推移の1区間（1日または1週間）の集計
+type TrendPoint struct {
+	Start          time.Time `json:"start"`
+	TotalProblems  int       `json:"total_problems"`
+	CorrectAnswers int       `json:"correct_answers"`
+	AccuracyRate   float64   `json:"accuracy_rate"` // 問題を解いていない区間は0
+	StudyMinutes   float64   `json:"study_minutes"`
+}
+
+// GetTrendPoints 直近days日間の学習の推移をbucketDays日ごとに集計（subjectが空なら全科目、古い順）
+func (m *Manager) GetTrendPoints(userID, subject string, days, bucketDays int) ([]TrendPoint, error) {
+	bucketDays = max(bucketDays, 1)
+	now := time.Now()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	buckets := (max(days, 1) + bucketDays - 1) / bucketDays
+	start := end.AddDate(0, 0, -buckets*bucketDays)
+
+	sessions, err := m.db.GetStudySessionsBetween(userID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("セッション取得エラー: %w", err)
+	}
+
+	points := make([]TrendPoint, buckets)
+	for i := range points {
+		points[i].Start = start.AddDate(0, 0, i*bucketDays)
+	}
+	for _, session := range sessions {
+		if subject != "" && session.Subject != subject {
+			continue
+		}
+		index := int(session.StartTime.Sub(start).Hours()/24) / bucketDays
+		if index < 0 || index >= buckets {
+			continue
+		}
+		points[index].TotalProblems += session.TotalProblems
+		points[index].CorrectAnswers += session.CorrectAnswers
+		points[index].StudyMinutes += float64(session.DurationSeconds()) / 60
+	}
+	for i := range points {
+		if points[i].TotalProblems > 0 {
+			points[i].AccuracyRate = float64(points[i].CorrectAnswers) / float64(points[i].TotalProblems)
+		}
+	}
+
+	return points, nil
+}
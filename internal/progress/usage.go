@@ -0,0 +1,98 @@
+package progress
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// UsageWeeks 利用統計で週ごとのセッション数を数える週の数（今週を含む）
+const UsageWeeks = 8
+
+// UsageStats アプリの利用統計（このパソコンの記録だけから計算し、どこにも送らない）
+type UsageStats struct {
+	Weeks                  []WeekUsage           `json:"weeks"` // 古い順
+	TotalSessions          int                   `json:"total_sessions"`
+	SessionsPerWeek        float64               `json:"sessions_per_week"`
+	AverageSessionSeconds  int                   `json:"average_session_seconds"`
+	FavoriteSubject        string                `json:"favorite_subject"` // 学習時間がいちばん長い科目（学習していなければ空）
+	FavoriteSubjectSeconds int                   `json:"favorite_subject_seconds"`
+	Features               database.FeatureUsage `json:"features"`
+}
+
+// WeekUsage 1週間のアプリでの学習セッション
+type WeekUsage struct {
+	Start    time.Time `json:"start"` // 月曜日0時
+	Sessions int       `json:"sessions"`
+	Seconds  int       `json:"seconds"`
+}
+
+// UsageStats 直近UsageWeeks週間の利用統計を集計
+func (m *Manager) UsageStats(userID string, now time.Time) (*UsageStats, error) {
+	start := weekStartOf(now).AddDate(0, 0, -7*(UsageWeeks-1))
+	sessions, err := m.db.GetStudySessionsBetween(userID, start, now.Add(time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("セッション取得エラー: %w", err)
+	}
+	stats := SummarizeUsage(sessions, now)
+
+	stats.Features, err = m.db.GetFeatureUsage(userID)
+	if err != nil {
+		return nil, fmt.Errorf("機能の利用回数取得エラー: %w", err)
+	}
+	return stats, nil
+}
+
+// SummarizeUsage 学習セッションから週ごとの回数・平均の長さ・よく学習する科目を集計
+// （アプリ外の学習の手動記録は数えない。模擬テストはアプリでの学習として数える）
+func SummarizeUsage(sessions []database.StudySession, now time.Time) *UsageStats {
+	stats := &UsageStats{Weeks: make([]WeekUsage, UsageWeeks)}
+	start := weekStartOf(now).AddDate(0, 0, -7*(UsageWeeks-1))
+	for i := range stats.Weeks {
+		stats.Weeks[i].Start = start.AddDate(0, 0, 7*i)
+	}
+
+	subjectSeconds := make(map[string]int)
+	finished, finishedSeconds := 0, 0
+	for i := range sessions {
+		session := &sessions[i]
+		if session.IsManual() || session.StartTime.Before(start) || session.StartTime.After(now) {
+			continue
+		}
+		seconds := session.DurationSeconds()
+		week := int(weekStartOf(session.StartTime).Sub(start).Hours()/24) / 7
+		if week >= 0 && week < UsageWeeks {
+			stats.Weeks[week].Sessions++
+			stats.Weeks[week].Seconds += seconds
+		}
+		stats.TotalSessions++
+		subjectSeconds[session.Subject] += seconds
+		if session.EndTime != nil {
+			finished++
+			finishedSeconds += seconds
+		}
+	}
+
+	stats.SessionsPerWeek = float64(stats.TotalSessions) / UsageWeeks
+	if finished > 0 {
+		stats.AverageSessionSeconds = finishedSeconds / finished
+	}
+
+	subjects := make([]string, 0, len(subjectSeconds))
+	for subject := range subjectSeconds {
+		subjects = append(subjects, subject)
+	}
+	sort.Slice(subjects, func(i, j int) bool {
+		if subjectSeconds[subjects[i]] != subjectSeconds[subjects[j]] {
+			return subjectSeconds[subjects[i]] > subjectSeconds[subjects[j]]
+		}
+		return subjects[i] < subjects[j]
+	})
+	if len(subjects) > 0 && subjectSeconds[subjects[0]] > 0 {
+		stats.FavoriteSubject = subjects[0]
+		stats.FavoriteSubjectSeconds = subjectSeconds[subjects[0]]
+	}
+	return stats
+}
@@ -0,0 +1,53 @@
+package progress_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/calendar"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+func TestUsageStats(t *testing.T) {
+	db := testutil.NewDB(t)
+	now := time.Now()
+	user := testutil.Seed(t, db, testutil.DefaultFixture(now))
+	if err := db.SaveDiaryEntry(&database.DiaryEntry{UserID: user.ID, Date: now.Format("2006-01-02"), Content: "一次関数をがんばった", UpdatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := progress.NewManager(db, nil, calendar.New(nil)).UsageStats(user.ID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 手動記録は数えず、数学20分・英語15分の2回
+	if stats.TotalSessions != 2 || stats.AverageSessionSeconds != (20+15)*60/2 {
+		t.Errorf("セッション = %d回・平均%d秒", stats.TotalSessions, stats.AverageSessionSeconds)
+	}
+	if stats.FavoriteSubject != "数学" || stats.FavoriteSubjectSeconds != 20*60 {
+		t.Errorf("よく学習する科目 = %s（%d秒）", stats.FavoriteSubject, stats.FavoriteSubjectSeconds)
+	}
+	if len(stats.Weeks) != progress.UsageWeeks {
+		t.Fatalf("週の数 = %d", len(stats.Weeks))
+	}
+	total := 0
+	for _, week := range stats.Weeks {
+		total += week.Sessions
+	}
+	if total != 2 || stats.Weeks[len(stats.Weeks)-1].Start.Weekday() != time.Monday {
+		t.Errorf("週ごとの集計 = %+v", stats.Weeks)
+	}
+	if stats.Features.ManualLogs != 1 || stats.Features.DiaryEntries != 1 || stats.Features.Exams != 0 {
+		t.Errorf("機能の利用回数 = %+v", stats.Features)
+	}
+}
+
+func TestSummarizeUsageWithoutSessions(t *testing.T) {
+	stats := progress.SummarizeUsage(nil, time.Now())
+	if stats.TotalSessions != 0 || stats.FavoriteSubject != "" || stats.SessionsPerWeek != 0 {
+		t.Errorf("学習していないときの集計 = %+v", stats)
+	}
+}
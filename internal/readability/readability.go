@@ -0,0 +1,126 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// JuniorHigh 小学校で習わない漢字（中学校以降で習う漢字・常用漢字表にない漢字）の学年
+const JuniorHigh = 7
+
+// levelCoverage 漢字の学年の目安にする割合（文章の漢字のこの割合を習い終える学年）
+const levelCoverage = 0.9
+
+// Stats 文章の読みやすさの集計（複数の文章をAddでまとめられる）
+type Stats struct {
+	Texts        int
+	Sentences    int
+	Characters   int // 空白・改行を除いた文字数
+	Kanji        int
+	KanjiByGrade [JuniorHigh + 1]int // 習う学年（1〜6は小学校、JuniorHighは中学以上）ごとの漢字の数
+}
+
+// KanjiGrade 漢字を習う学年（1〜6は小学校、JuniorHighは中学以上。漢字でなければ0）
+func KanjiGrade(r rune) int {
+	if !unicode.Is(unicode.Han, r) {
+		return 0
+	}
+	if grade, ok := kanjiGrades[r]; ok {
+		return grade
+	}
+	return JuniorHigh
+}
+
+// Analyze 文章の文の数・文字数・学年ごとの漢字の数を数える
+func Analyze(text string) Stats {
+	stats := Stats{Texts: 1}
+	inSentence := false
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune("。！？!?\n", r):
+			if inSentence {
+				stats.Sentences++
+			}
+			inSentence = false
+			if r != '\n' {
+				stats.Characters++
+			}
+			continue
+		case unicode.IsSpace(r):
+			continue
+		}
+		inSentence = true
+		stats.Characters++
+		if grade := KanjiGrade(r); grade > 0 {
+			stats.Kanji++
+			stats.KanjiByGrade[grade]++
+		}
+	}
+	if inSentence {
+		stats.Sentences++ // 句点で終わらない最後の文
+	}
+	return stats
+}
+
+// Add 別の文章の集計を加える
+func (s *Stats) Add(other Stats) {
+	s.Texts += other.Texts
+	s.Sentences += other.Sentences
+	s.Characters += other.Characters
+	s.Kanji += other.Kanji
+	for grade, count := range other.KanjiByGrade {
+		s.KanjiByGrade[grade] += count
+	}
+}
+
+// AverageSentenceLength 1文の平均の文字数
+func (s Stats) AverageSentenceLength() float64 {
+	if s.Sentences == 0 {
+		return 0
+	}
+	return float64(s.Characters) / float64(s.Sentences)
+}
+
+// KanjiRatio 文字のうち漢字の割合（0〜1）
+func (s Stats) KanjiRatio() float64 {
+	if s.Characters == 0 {
+		return 0
+	}
+	return float64(s.Kanji) / float64(s.Characters)
+}
+
+// JuniorHighRatio 漢字のうち、小学校で習わない漢字の割合（0〜1）
+func (s Stats) JuniorHighRatio() float64 {
+	if s.Kanji == 0 {
+		return 0
+	}
+	return float64(s.KanjiByGrade[JuniorHigh]) / float64(s.Kanji)
+}
+
+// KanjiLevel 文章の漢字の9割を習い終える学年（1〜6は小学校、JuniorHighは中学以上。漢字がなければ0）
+func (s Stats) KanjiLevel() int {
+	if s.Kanji == 0 {
+		return 0
+	}
+	covered := 0
+	for grade := 1; grade <= JuniorHigh; grade++ {
+		covered += s.KanjiByGrade[grade]
+		if float64(covered) >= levelCoverage*float64(s.Kanji) {
+			return grade
+		}
+	}
+	return JuniorHigh
+}
+
+// LevelLabel 漢字の学年の表示名（「小4」「中学以上」）
+func LevelLabel(level int) string {
+	switch {
+	case level <= 0:
+		return "－"
+	case level >= JuniorHigh:
+		return "中学以上"
+	default:
+		return fmt.Sprintf("小%d", level)
+	}
+}
@@ -0,0 +1,36 @@
+package readability
+
+import "testing"
+
+func TestKanjiGrade(t *testing.T) {
+	for r, want := range map[rune]int{'一': 1, '曜': 2, '湖': 3, '媛': 4, '潔': 5, '臓': 6, '碁': JuniorHigh, 'あ': 0, 'A': 0} {
+		if got := KanjiGrade(r); got != want {
+			t.Errorf("KanjiGrade(%q) = %d, want %d", r, got, want)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	stats := Analyze("右の図を見よう。三角形の内角の和は180度です！\n")
+	if stats.Sentences != 2 {
+		t.Errorf("文の数 = %d, want 2", stats.Sentences)
+	}
+	if stats.Characters != 24 {
+		t.Errorf("文字数 = %d, want 24", stats.Characters)
+	}
+	// 右・図・見・三・角・形・内・角・和・度
+	if stats.Kanji != 10 || stats.KanjiByGrade[1] != 3 || stats.KanjiByGrade[2] != 5 || stats.KanjiByGrade[3] != 2 {
+		t.Errorf("漢字 = %d %v", stats.Kanji, stats.KanjiByGrade)
+	}
+	if got := stats.KanjiLevel(); got != 3 {
+		t.Errorf("漢字の学年 = %d, want 3", got)
+	}
+
+	stats.Add(Analyze("累乗と媒介の計算をしよう"))
+	if stats.Texts != 2 || stats.Sentences != 3 || stats.KanjiByGrade[JuniorHigh] != 3 {
+		t.Errorf("まとめた集計 = %+v", stats)
+	}
+	if got := stats.KanjiLevel(); got != JuniorHigh {
+		t.Errorf("中学で習う漢字が1割を超えたときの学年 = %s", LevelLabel(got))
+	}
+}
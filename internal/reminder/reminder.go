@@ -0,0 +1,193 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/schedule"
+)
+
+// CheckInterval 通知の時刻になったか確かめる間隔
+const CheckInterval = 30 * time.Second
+
+// lateLimit 通知の時刻からこの時間をこえて過ぎていたら通知しない（スリープから戻ったときに古い通知を出さない）
+const lateLimit = 10 * time.Minute
+
+// Notification デスクトップに出す通知
+type Notification struct {
+	Title   string
+	Content string
+}
+
+// Status 通知を決めるための今日の学習の状況
+type Status struct {
+	StudiedToday bool
+	Streak       int // 連続で学習した日数（今日まだ学習していなければ昨日まで）
+}
+
+// Due lastより後、now以前に来た時刻の通知（今日もう学習していれば通知しない）
+// 設定で学習計画に合わせるときは、今日の学習計画のコマの開始時刻に通知する。
+// 例外日・祝日・長期休みは学習の時間の通知をしない（planがnilなら設定の時刻に通知する）
+func Due(cfg config.ReminderConfig, plan *schedule.DayPlan, status Status, last, now time.Time) []Notification {
+	if !cfg.Enabled || status.StudiedToday {
+		return nil
+	}
+
+	var notifications []Notification
+	for _, study := range studyTimes(cfg, plan, last, now) {
+		if slices.Contains(cfg.Weekdays, int(study.at.Weekday())) {
+			notifications = append(notifications, Notification{
+				Title:   "📚 学習の時間です",
+				Content: study.content,
+			})
+		}
+	}
+	if _, ok := reached(cfg.StreakAlertTime, last, now); ok && cfg.StreakAlert && status.Streak > 0 {
+		notifications = append(notifications, Notification{
+			Title:   "🔥 連続学習が途切れそうです",
+			Content: fmt.Sprintf("連続%d日の記録が続いています。今日も少しだけ学習しませんか？", status.Streak),
+		})
+	}
+	return notifications
+}
+
+// studyTime 学習の時間の通知の時刻と内容
+type studyTime struct {
+	at      time.Time
+	content string
+}
+
+// studyTimes lastより後、now以前に来た学習の時間（学習計画のコマか、設定の時刻）
+func studyTimes(cfg config.ReminderConfig, plan *schedule.DayPlan, last, now time.Time) []studyTime {
+	if plan != nil && plan.IsHoliday {
+		return nil
+	}
+
+	var times []studyTime
+	if cfg.FollowPlan && plan != nil {
+		for _, slot := range plan.Slots {
+			if !reachedAt(slot.Start, last, now) {
+				continue
+			}
+			content := fmt.Sprintf("%sから学習の予定です。今日の学習を始めましょう。", slot.Start.Format(config.ReminderTimeLayout))
+			if slot.Subject != "" {
+				content = fmt.Sprintf("%sから%sの学習の予定です。今日の学習を始めましょう。", slot.Start.Format(config.ReminderTimeLayout), slot.Subject)
+			}
+			times = append(times, studyTime{at: slot.Start, content: content})
+		}
+		return times
+	}
+
+	for _, clock := range cfg.Times {
+		if at, ok := reached(clock, last, now); ok {
+			times = append(times, studyTime{at: at, content: fmt.Sprintf("%sになりました。今日の学習を始めましょう。", clock)})
+		}
+	}
+	return times
+}
+
+// reached 時刻（"19:00"）がlastより後、now以前に来たかどうか（過ぎてから時間がたちすぎたものは除く）
+func reached(clock string, last, now time.Time) (time.Time, bool) {
+	t, err := time.Parse(config.ReminderTimeLayout, clock)
+	if err != nil {
+		return time.Time{}, false
+	}
+	// 日付をまたいだすぐあとでも前の日の時刻を確かめられるよう、前の日から見る
+	for _, day := range []time.Time{now.AddDate(0, 0, -1), now} {
+		at := time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if reachedAt(at, last, now) {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// reachedAt 日時がlastより後、now以前に来たかどうか（過ぎてから時間がたちすぎたものは除く）
+func reachedAt(at, last, now time.Time) bool {
+	return at.After(last) && !at.After(now) && now.Sub(at) <= lateLimit
+}
+
+// anyReached 通知の時刻のどれかが来たかどうか（来ていなければ学習の状況を確かめない）
+func anyReached(cfg config.ReminderConfig, plan *schedule.DayPlan, last, now time.Time) bool {
+	if _, ok := reached(cfg.StreakAlertTime, last, now); ok {
+		return true
+	}
+	return len(studyTimes(cfg, plan, last, now)) > 0
+}
+
+// planRefresh 学習計画を読み直す間隔（時間割や例外日を変えたときに反映する）
+const planRefresh = 10 * time.Minute
+
+// Scheduler 通知の時刻になったら、今日の学習の状況を確かめて通知する
+type Scheduler struct {
+	config func() config.ReminderConfig
+	plan   func(day time.Time) (*schedule.DayPlan, error)
+	status func(now time.Time) (Status, error)
+	notify func(Notification)
+	last   time.Time
+
+	today     *schedule.DayPlan // 読み込んだ今日の学習計画
+	plannedAt time.Time         // 今日の学習計画を読み込んだ時刻
+}
+
+// NewScheduler 通知のスケジューラを作成（作成した時刻より前の通知は出さない）
+// planはその日の学習計画（時間割・例外日・学校カレンダーから作ったもの）を返す
+func NewScheduler(cfg func() config.ReminderConfig, plan func(day time.Time) (*schedule.DayPlan, error), status func(now time.Time) (Status, error), notify func(Notification)) *Scheduler {
+	return &Scheduler{config: cfg, plan: plan, status: status, notify: notify, last: time.Now()}
+}
+
+// Run ctxが取り消されるまで、intervalごとに通知の時刻になったか確かめる
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Check(now)
+		}
+	}
+}
+
+// Check 前に確かめたときからnowまでに来た時刻の通知を出す
+func (s *Scheduler) Check(now time.Time) {
+	last := s.last
+	s.last = now
+
+	cfg := s.config()
+	if !cfg.Enabled {
+		return
+	}
+	plan := s.todayPlan(now)
+	if !anyReached(cfg, plan, last, now) {
+		return
+	}
+	status, err := s.status(now)
+	if err != nil {
+		slog.Error("学習リマインドの状況確認エラー", "error", err)
+		return
+	}
+	for _, notification := range Due(cfg, plan, status, last, now) {
+		slog.Info("🔔 学習リマインドを通知", "title", notification.Title)
+		s.notify(notification)
+	}
+}
+
+// todayPlan 今日の学習計画（日付が変わったときと、読み込んでから時間がたったときに読み直す。読めなければnil）
+func (s *Scheduler) todayPlan(now time.Time) *schedule.DayPlan {
+	if s.today != nil && s.today.Date.Format("2006-01-02") == now.Format("2006-01-02") && now.Sub(s.plannedAt) < planRefresh {
+		return s.today
+	}
+	plan, err := s.plan(now)
+	if err != nil {
+		slog.Error("学習リマインドの学習計画取得エラー", "error", err)
+		return nil
+	}
+	s.today, s.plannedAt = plan, now
+	return plan
+}
@@ -0,0 +1,146 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/schedule"
+)
+
+func TestDue(t *testing.T) {
+	cfg := config.ReminderConfig{
+		Enabled:         true,
+		Times:           []string{"19:00"},
+		Weekdays:        []int{1, 2, 3, 4, 5}, // 平日だけ
+		StreakAlert:     true,
+		StreakAlertTime: "20:30",
+	}
+	friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	at := func(day time.Time, hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	if got := Due(cfg, nil, Status{}, at(friday, 18, 59), at(friday, 19, 0)); len(got) != 1 {
+		t.Errorf("19:00の通知 = %+v", got)
+	}
+	if got := Due(cfg, nil, Status{}, at(friday, 19, 0), at(friday, 19, 1)); len(got) != 0 {
+		t.Errorf("同じ時刻の通知は1回だけのはず: %+v", got)
+	}
+	if got := Due(cfg, nil, Status{StudiedToday: true}, at(friday, 18, 59), at(friday, 19, 0)); len(got) != 0 {
+		t.Errorf("今日もう学習していれば通知しないはず: %+v", got)
+	}
+	saturday := friday.AddDate(0, 0, 1)
+	if got := Due(cfg, nil, Status{}, at(saturday, 18, 59), at(saturday, 19, 0)); len(got) != 0 {
+		t.Errorf("土曜日は通知しないはず: %+v", got)
+	}
+	if got := Due(cfg, nil, Status{}, at(friday, 9, 0), at(friday, 22, 0)); len(got) != 0 {
+		t.Errorf("スリープから戻ったときに古い通知を出さないはず: %+v", got)
+	}
+
+	// 連続学習が途切れそうな通知は曜日によらず、連続記録があるときだけ
+	if got := Due(cfg, nil, Status{Streak: 5}, at(saturday, 20, 29), at(saturday, 20, 30)); len(got) != 1 || got[0].Title != "🔥 連続学習が途切れそうです" {
+		t.Errorf("連続学習の通知 = %+v", got)
+	}
+	if got := Due(cfg, nil, Status{}, at(saturday, 20, 29), at(saturday, 20, 30)); len(got) != 0 {
+		t.Errorf("連続記録がなければ通知しないはず: %+v", got)
+	}
+
+	cfg.Times = []string{"23:59"}
+	if got := Due(cfg, nil, Status{}, at(friday, 23, 58), at(saturday, 0, 1)); len(got) != 1 {
+		t.Errorf("日付をまたいでも前の日の通知を出すはず: %+v", got)
+	}
+}
+
+func TestDueFollowsPlan(t *testing.T) {
+	cfg := config.ReminderConfig{
+		Enabled:         true,
+		FollowPlan:      true,
+		Times:           []string{"19:00"},
+		Weekdays:        []int{0, 1, 2, 3, 4, 5, 6},
+		StreakAlert:     true,
+		StreakAlertTime: "20:30",
+	}
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	plan := &schedule.DayPlan{Date: day, Slots: []schedule.StudySlot{
+		{Start: at(17, 30), End: at(18, 0), Subject: "数学"},
+		{Start: at(19, 40), End: at(20, 10), Subject: "英語"},
+	}}
+
+	got := Due(cfg, plan, Status{}, at(17, 29), at(17, 30))
+	if len(got) != 1 || got[0].Content != "17:30から数学の学習の予定です。今日の学習を始めましょう。" {
+		t.Errorf("学習計画のコマの通知 = %+v", got)
+	}
+	if got := Due(cfg, plan, Status{}, at(18, 59), at(19, 0)); len(got) != 0 {
+		t.Errorf("学習計画に合わせるときは設定の時刻に通知しないはず: %+v", got)
+	}
+
+	// 例外日・祝日・長期休みは学習の時間を通知しない（連続学習の通知はする）
+	holiday := &schedule.DayPlan{Date: day, IsHoliday: true, HolidayNote: "修学旅行", Slots: plan.Slots}
+	if got := Due(cfg, holiday, Status{}, at(17, 29), at(17, 30)); len(got) != 0 {
+		t.Errorf("例外日は通知しないはず: %+v", got)
+	}
+	if got := Due(cfg, holiday, Status{Streak: 3}, at(20, 29), at(20, 30)); len(got) != 1 {
+		t.Errorf("例外日でも連続学習の通知はするはず: %+v", got)
+	}
+
+	// 学習計画に合わせないときも、例外日は設定の時刻に通知しない
+	cfg.FollowPlan = false
+	if got := Due(cfg, plan, Status{}, at(18, 59), at(19, 0)); len(got) != 1 {
+		t.Errorf("設定の時刻の通知 = %+v", got)
+	}
+	if got := Due(cfg, holiday, Status{}, at(18, 59), at(19, 0)); len(got) != 0 {
+		t.Errorf("例外日は設定の時刻にも通知しないはず: %+v", got)
+	}
+}
+
+func TestSchedulerCheck(t *testing.T) {
+	cfg := config.ReminderConfig{Enabled: true, Times: []string{"19:00"}, Weekdays: []int{0, 1, 2, 3, 4, 5, 6}, StreakAlertTime: "20:30"}
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	checked := 0
+	var sent []Notification
+	scheduler := NewScheduler(
+		func() config.ReminderConfig { return cfg },
+		func(time.Time) (*schedule.DayPlan, error) { return nil, nil },
+		func(time.Time) (Status, error) { checked++; return Status{}, nil },
+		func(n Notification) { sent = append(sent, n) },
+	)
+	scheduler.last = day.Add(18 * time.Hour)
+
+	scheduler.Check(day.Add(18*time.Hour + 30*time.Minute))
+	if checked != 0 {
+		t.Errorf("通知の時刻でなければ学習の状況を確かめないはず: %d回", checked)
+	}
+	scheduler.Check(day.Add(19 * time.Hour))
+	scheduler.Check(day.Add(19*time.Hour + 30*time.Second))
+	if checked != 1 || len(sent) != 1 {
+		t.Errorf("状況の確認 %d回・通知 %+v", checked, sent)
+	}
+}
+
+func TestSchedulerReadsPlanOncePerDay(t *testing.T) {
+	cfg := config.ReminderConfig{Enabled: true, FollowPlan: true, Weekdays: []int{0, 1, 2, 3, 4, 5, 6}, StreakAlertTime: "20:30"}
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	planned := 0
+	var sent []Notification
+	scheduler := NewScheduler(
+		func() config.ReminderConfig { return cfg },
+		func(date time.Time) (*schedule.DayPlan, error) {
+			planned++
+			midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+			return &schedule.DayPlan{Date: midnight, Slots: []schedule.StudySlot{{Start: midnight.Add(17 * time.Hour), Subject: "理科"}}}, nil
+		},
+		func(time.Time) (Status, error) { return Status{}, nil },
+		func(n Notification) { sent = append(sent, n) },
+	)
+	scheduler.last = day.Add(16*time.Hour + 59*time.Minute)
+
+	scheduler.Check(day.Add(16*time.Hour + 59*time.Minute + 30*time.Second))
+	scheduler.Check(day.Add(17 * time.Hour))
+	if planned != 1 || len(sent) != 1 {
+		t.Errorf("学習計画の読み込み %d回・通知 %+v", planned, sent)
+	}
+}
@@ -0,0 +1,274 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/achievement"
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/calendar"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/pet"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+	"github.com/okamyuji/studybuddy-ai/internal/xp"
+)
+
+// 学習セッションの既定値
+const (
+	defaultSecondsPerAnswer = 60
+	sessionStartHour        = 17 // 学習を始める時刻（放課後）
+)
+
+// errNoNetwork シナリオでは外部に接続しない
+var errNoNetwork = errors.New("シナリオの実行中はAIに接続しません")
+
+// offlineTransport どの問い合わせにも接続エラーを返すトランスポート（AIエンジンを用意してある問題で動かす）
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errNoNetwork
+}
+
+// NewOfflineEngine シナリオ用のAIエンジン（Ollama・クラウドAIに接続せず、用意してある問題とフィードバックを返す）
+func NewOfflineEngine(cfg config.AIConfig) (*ai.Engine, error) {
+	cfg.Cloud.Provider = config.CloudProviderNone
+	engine, err := ai.NewEngine(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("AIエンジン作成エラー: %w", err)
+	}
+	engine.SetTransport(offlineTransport{})
+	engine.CheckHealth(context.Background())
+	return engine, nil
+}
+
+// Runner シナリオを、画面の学習と同じ順で記録・集計する
+type Runner struct {
+	db           *database.DB
+	engine       *ai.Engine
+	xpService    *xp.Service
+	pets         *pet.Manager
+	achievements *achievement.Manager
+	progress     *progress.Manager
+}
+
+// NewRunner シナリオの実行環境を作成
+// （学校の長期休みは設定によって連続学習日数の数え方が変わるため使わず、祝日だけを考慮する）
+func NewRunner(db *database.DB, engine *ai.Engine) *Runner {
+	return &Runner{
+		db:           db,
+		engine:       engine,
+		xpService:    xp.NewService(db),
+		pets:         pet.NewManager(db),
+		achievements: achievement.NewManager(db),
+		progress:     progress.NewManager(db, engine, calendar.New(nil)),
+	}
+}
+
+// Run シナリオを実行して結果を集計（プロフィールはシナリオごとに新しく作る）
+// 連続学習日数は実際の今日を基準に数えるため、セッションの日付も今日から数える
+func (r *Runner) Run(ctx context.Context, sc Scenario) (*Outcome, error) {
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+
+	user := &database.User{
+		ID:        uuid.New().String(),
+		Name:      sc.Profile.Name,
+		Grade:     max(sc.Profile.Grade, 1),
+		CreatedAt: time.Now(),
+	}
+	if user.Name == "" {
+		user.Name = "学習者"
+	}
+	if err := r.db.CreateUser(user); err != nil {
+		return nil, fmt.Errorf("ユーザー作成エラー: %w", err)
+	}
+	if sc.Profile.Pet != "" {
+		if _, err := r.pets.EnsurePet(user.ID, sc.Profile.Pet); err != nil {
+			return nil, err
+		}
+	}
+
+	today := time.Now()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	for _, session := range sc.Sessions {
+		for day := 0; day < max(session.Days, 1); day++ {
+			start := today.AddDate(0, 0, -session.DaysAgo+day).Add(sessionStartHour * time.Hour)
+			if err := r.runSession(ctx, user, sc.Profile.Pet != "", session, start); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return r.outcome(user.ID)
+}
+
+// runSession 学習セッションを1回行う（出題 → 解答の記録 → 経験値・ペット → セッションの終了）
+func (r *Runner) runSession(ctx context.Context, user *database.User, withPet bool, session Session, start time.Time) error {
+	record := &database.StudySession{
+		ID:             uuid.New().String(),
+		UserID:         user.ID,
+		Subject:        session.Subject,
+		StartTime:      start,
+		AverageEmotion: "neutral",
+		SessionType:    database.SessionTypeApp,
+		CreatedAt:      start,
+	}
+	if err := r.db.CreateStudySession(record); err != nil {
+		return fmt.Errorf("セッション作成エラー: %w", err)
+	}
+
+	seconds := session.SecondsPerAnswer
+	if seconds <= 0 {
+		seconds = defaultSecondsPerAnswer
+	}
+
+	answeredAt := start
+	consecutiveCorrect := 0
+	for _, answer := range session.Answers {
+		problem, err := r.engine.GeneratePersonalizedProblem(ctx, ai.StudyContext{
+			UserID:     user.ID,
+			Subject:    session.Subject,
+			Grade:      user.Grade,
+			Difficulty: session.Difficulty,
+			Topic:      session.Topic,
+		})
+		if err != nil {
+			return fmt.Errorf("問題生成エラー: %w", err)
+		}
+		difficulty := session.Difficulty
+		if difficulty == 0 {
+			difficulty = max(problem.Difficulty, 1)
+		}
+		topic := session.Topic
+		if topic == "" {
+			topic = problem.ProblemType
+		}
+
+		isCorrect := answer == answerCorrect
+		selected := problem.CorrectAnswer
+		if !isCorrect {
+			selected = (problem.CorrectAnswer + 1) % len(problem.Options)
+		}
+		answeredAt = answeredAt.Add(time.Duration(seconds) * time.Second)
+
+		result := &database.ProblemResult{
+			ID:              uuid.New().String(),
+			SessionID:       record.ID,
+			ProblemType:     topic,
+			Difficulty:      difficulty,
+			IsCorrect:       isCorrect,
+			TimeTaken:       seconds,
+			EmotionAtAnswer: "neutral",
+			ProblemContent:  problem.Description,
+			UserAnswer:      problem.Options[selected],
+			CorrectAnswer:   problem.Options[problem.CorrectAnswer],
+			CreatedAt:       answeredAt,
+		}
+		answers := []database.AnswerRecord{{Result: result, Subject: session.Subject, AnsweredAt: answeredAt}}
+		if err := r.db.RecordAnswers(user.ID, answers); err != nil {
+			return fmt.Errorf("結果保存エラー: %w", err)
+		}
+
+		record.TotalProblems++
+		if isCorrect {
+			record.CorrectAnswers++
+			consecutiveCorrect++
+		} else {
+			consecutiveCorrect = 0
+		}
+		record.MaxCombo = max(record.MaxCombo, consecutiveCorrect)
+
+		studyResult := pet.StudyResult{
+			IsCorrect:          isCorrect,
+			Difficulty:         difficulty,
+			TimeTaken:          seconds,
+			ConsecutiveCorrect: consecutiveCorrect,
+			SessionDuration:    int(answeredAt.Sub(start).Seconds()),
+		}
+		award, err := r.xpService.GrantAnswer(user.ID, studyResult.Answer())
+		if err != nil {
+			return err
+		}
+		if withPet {
+			if _, err := r.pets.FeedPet(user.ID, studyResult); err != nil {
+				return err
+			}
+		}
+		if err := r.checkAchievements(user.ID, award.After.Level); err != nil {
+			return err
+		}
+	}
+
+	record.EndTime = &answeredAt
+	if err := r.db.UpdateStudySession(record); err != nil {
+		return fmt.Errorf("セッション更新エラー: %w", err)
+	}
+	return nil
+}
+
+// checkAchievements 解答のたびに画面と同じ条件で実績を判定
+func (r *Runner) checkAchievements(userID string, level int) error {
+	stats := achievement.Stats{Level: level}
+	totalProblems, err := r.db.CountProblemResults(userID)
+	if err != nil {
+		return fmt.Errorf("解答数取得エラー: %w", err)
+	}
+	stats.TotalProblems = totalProblems
+
+	streak, err := r.progress.GetStudyStreak(userID)
+	if err != nil {
+		return fmt.Errorf("連続学習日数取得エラー: %w", err)
+	}
+	stats.CurrentStreak = streak.CurrentStreak
+	stats.LongestStreak = streak.LongestStreak
+
+	_, err = r.achievements.Check(userID, stats)
+	return err
+}
+
+// outcome 進捗タブ・ペット・実績と同じ集計で結果をまとめる
+func (r *Runner) outcome(userID string) (*Outcome, error) {
+	outcome := &Outcome{UserID: userID, Achievements: []string{}}
+
+	analysis, err := r.progress.AnalyzeProgress(userID)
+	if err != nil {
+		return nil, err
+	}
+	outcome.StudyDays = analysis.OverallProgress.StudyDaysCount
+	outcome.XP = analysis.OverallProgress.ExperiencePoints
+	outcome.Level = analysis.OverallProgress.CurrentLevel
+	if streak := analysis.StudyStreak; streak != nil {
+		outcome.CurrentStreak = streak.CurrentStreak
+		outcome.LongestStreak = streak.LongestStreak
+	}
+
+	sessions, err := r.db.GetStudySessionsBetween(userID, time.Time{}, time.Now().AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("セッション取得エラー: %w", err)
+	}
+	for _, session := range sessions {
+		outcome.TotalProblems += session.TotalProblems
+		outcome.CorrectAnswers += session.CorrectAnswers
+	}
+
+	if virtualPet, err := r.db.GetVirtualPet(userID); err == nil {
+		outcome.PetLevel = virtualPet.Level
+		outcome.PetEvolution = virtualPet.Evolution
+	}
+
+	earned, err := r.achievements.Earned(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range earned {
+		outcome.Achievements = append(outcome.Achievements, e.ID)
+	}
+	return outcome, nil
+}
@@ -0,0 +1,176 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ScenarioFileExtension シナリオファイルの拡張子
+const ScenarioFileExtension = ".json"
+
+// 解答の並びの書き方
+const (
+	answerCorrect   = 'o' // 正解
+	answerIncorrect = 'x' // 不正解
+)
+
+// Scenario 画面を使わずに実行する学習の流れ（プロフィール作成 → 学習セッション → 結果の確認）
+type Scenario struct {
+	Name     string    `json:"name"`
+	Profile  Profile   `json:"profile"`
+	Sessions []Session `json:"sessions"`
+	Expect   Expect    `json:"expect"`
+}
+
+// Profile シナリオで作成するプロフィール
+type Profile struct {
+	Name  string `json:"name"`
+	Grade int    `json:"grade"` // 1〜3（0なら1）
+	Pet   string `json:"pet"`   // ペットの種類（cat・dog・dragon・unicorn。空ならペットを育てない）
+}
+
+// Session シナリオで行う学習セッション
+type Session struct {
+	Subject          string `json:"subject"`
+	DaysAgo          int    `json:"days_ago"`           // 何日前に学習するか（0なら今日）
+	Days             int    `json:"days"`               // DaysAgoから1日ずつ何日続けるか（0なら1日）
+	Answers          string `json:"answers"`            // 解答の並び（o:正解 x:不正解。例 "ooxo"）
+	Difficulty       int    `json:"difficulty"`         // 難易度（0なら出題した問題の難易度）
+	Topic            string `json:"topic"`              // 単元（空なら出題した問題の単元）
+	SecondsPerAnswer int    `json:"seconds_per_answer"` // 1問にかける時間（0なら60秒）
+}
+
+// Expect シナリオの実行後に確認する結果（書いた項目だけ確認する）
+type Expect struct {
+	CurrentStreak  *int     `json:"current_streak,omitempty"`
+	LongestStreak  *int     `json:"longest_streak,omitempty"`
+	TotalProblems  *int     `json:"total_problems,omitempty"`
+	CorrectAnswers *int     `json:"correct_answers,omitempty"`
+	StudyDays      *int     `json:"study_days,omitempty"`
+	XP             *int     `json:"xp,omitempty"`
+	MinXP          *int     `json:"min_xp,omitempty"`
+	Level          *int     `json:"level,omitempty"`
+	PetLevel       *int     `json:"pet_level,omitempty"`
+	PetEvolution   string   `json:"pet_evolution,omitempty"` // basic・intermediate・advanced
+	Achievements   []string `json:"achievements,omitempty"`  // 獲得しているはずの実績のID
+}
+
+// Outcome シナリオを実行した結果
+type Outcome struct {
+	UserID         string   `json:"user_id"`
+	CurrentStreak  int      `json:"current_streak"`
+	LongestStreak  int      `json:"longest_streak"`
+	TotalProblems  int      `json:"total_problems"`
+	CorrectAnswers int      `json:"correct_answers"`
+	StudyDays      int      `json:"study_days"`
+	XP             int      `json:"xp"`
+	Level          int      `json:"level"`
+	PetLevel       int      `json:"pet_level"`
+	PetEvolution   string   `json:"pet_evolution"`
+	Achievements   []string `json:"achievements"`
+}
+
+// Load シナリオファイルを読み込む（ディレクトリなら中の .json をファイル名の順にすべて）
+func Load(path string) ([]Scenario, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("シナリオ読み込みエラー: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*"+ScenarioFileExtension))
+		if err != nil {
+			return nil, fmt.Errorf("シナリオ読み込みエラー: %w", err)
+		}
+		slices.Sort(files)
+	}
+
+	scenarios := make([]Scenario, 0, len(files))
+	for _, file := range files {
+		sc, err := loadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, sc)
+	}
+	return scenarios, nil
+}
+
+// loadFile シナリオファイルを1つ読み込む
+func loadFile(path string) (Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("シナリオ読み込みエラー: %w", err)
+	}
+	var sc Scenario
+	if err := json.Unmarshal(data, &sc); err != nil {
+		return Scenario{}, fmt.Errorf("シナリオ解析エラー: %s: %w", path, err)
+	}
+	if sc.Name == "" {
+		sc.Name = strings.TrimSuffix(filepath.Base(path), ScenarioFileExtension)
+	}
+	if err := sc.Validate(); err != nil {
+		return Scenario{}, fmt.Errorf("シナリオ %s: %w", sc.Name, err)
+	}
+	return sc, nil
+}
+
+// Validate シナリオの書き方を確認
+func (sc Scenario) Validate() error {
+	if sc.Profile.Grade < 0 || sc.Profile.Grade > 3 {
+		return fmt.Errorf("学年は1〜3で指定してください: %d", sc.Profile.Grade)
+	}
+	for i, session := range sc.Sessions {
+		if session.Subject == "" {
+			return fmt.Errorf("%d番目のセッションに科目がありません", i+1)
+		}
+		if session.DaysAgo < 0 || session.Days < 0 {
+			return fmt.Errorf("%d番目のセッションの日付が正しくありません", i+1)
+		}
+		if session.Difficulty < 0 || session.Difficulty > 5 {
+			return fmt.Errorf("%d番目のセッションの難易度は1〜5で指定してください: %d", i+1, session.Difficulty)
+		}
+		for _, answer := range session.Answers {
+			if answer != answerCorrect && answer != answerIncorrect {
+				return fmt.Errorf("%d番目のセッションの解答は o と x で書いてください: %q", i+1, session.Answers)
+			}
+		}
+	}
+	return nil
+}
+
+// Check 期待した結果と違う項目を返す（すべて合っていれば空）
+func (e Expect) Check(outcome *Outcome) []string {
+	var failures []string
+	checkInt := func(label string, want *int, got int) {
+		if want != nil && *want != got {
+			failures = append(failures, fmt.Sprintf("%s = %d, 期待値 %d", label, got, *want))
+		}
+	}
+
+	checkInt("連続学習日数", e.CurrentStreak, outcome.CurrentStreak)
+	checkInt("最長連続学習日数", e.LongestStreak, outcome.LongestStreak)
+	checkInt("解答数", e.TotalProblems, outcome.TotalProblems)
+	checkInt("正解数", e.CorrectAnswers, outcome.CorrectAnswers)
+	checkInt("学習日数", e.StudyDays, outcome.StudyDays)
+	checkInt("経験値", e.XP, outcome.XP)
+	checkInt("レベル", e.Level, outcome.Level)
+	checkInt("ペットのレベル", e.PetLevel, outcome.PetLevel)
+	if e.MinXP != nil && outcome.XP < *e.MinXP {
+		failures = append(failures, fmt.Sprintf("経験値 = %d, %d以上のはず", outcome.XP, *e.MinXP))
+	}
+	if e.PetEvolution != "" && e.PetEvolution != outcome.PetEvolution {
+		failures = append(failures, fmt.Sprintf("ペットの進化 = %q, 期待値 %q", outcome.PetEvolution, e.PetEvolution))
+	}
+	for _, id := range e.Achievements {
+		if !slices.Contains(outcome.Achievements, id) {
+			failures = append(failures, fmt.Sprintf("実績 %s を獲得していない（獲得済み: %v）", id, outcome.Achievements))
+		}
+	}
+	return failures
+}
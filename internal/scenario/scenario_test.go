@@ -0,0 +1,65 @@
+package scenario
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+func newTestRunner(t *testing.T) *Runner {
+	t.Helper()
+	engine, err := NewOfflineEngine(config.Default().AI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewRunner(testutil.NewDB(t), engine)
+}
+
+func TestScenarios(t *testing.T) {
+	scenarios, err := Load(filepath.Join("testdata", "scenarios"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scenarios) == 0 {
+		t.Fatal("シナリオがない")
+	}
+
+	runner := newTestRunner(t)
+	for _, sc := range scenarios {
+		t.Run(sc.Name, func(t *testing.T) {
+			outcome, err := runner.Run(context.Background(), sc)
+			if err != nil {
+				t.Fatalf("シナリオ実行エラー: %v", err)
+			}
+			for _, failure := range sc.Expect.Check(outcome) {
+				t.Error(failure)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsInvalidScenario(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.json")
+	data := `{"sessions": [{"subject": "数学", "answers": "o?x"}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "invalid") {
+		t.Errorf("解答の書き方が違うシナリオはファイル名つきのエラーになるはず: %v", err)
+	}
+}
+
+func TestCheckReportsMismatches(t *testing.T) {
+	streak, level := 3, 2
+	expect := Expect{CurrentStreak: &streak, Level: &level, PetEvolution: "intermediate", Achievements: []string{"streak_7"}}
+	outcome := &Outcome{CurrentStreak: 3, Level: 1, PetEvolution: "basic"}
+	if got := len(expect.Check(outcome)); got != 3 {
+		t.Errorf("違う項目 = %d, want 3: %v", got, expect.Check(outcome))
+	}
+}
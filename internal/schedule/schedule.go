@@ -0,0 +1,274 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/calendar"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 学習スロットの計画パラメータ
+const (
+	slotMinutes      = 30  // 1コマの学習時間（分）
+	minSlotMinutes   = 20  // これより短い空き時間には予定を入れない
+	slotGapMinutes   = 10  // コマ間の休憩（分）
+	afterBusyMinutes = 30  // 授業・部活動の後の移動・休憩時間（分）
+	examGoalPercent  = 150 // テスト期間中の学習目標（通常時に対する割合）
+)
+
+// 1日の学習可能時間帯（分単位）
+var (
+	schoolDayWindow = timeRange{start: 16 * 60, end: 21*60 + 30}
+	holidayWindow   = timeRange{start: 9 * 60, end: 21*60 + 30}
+	mealTimes       = []timeRange{
+		{start: 12 * 60, end: 13 * 60},       // 昼食
+		{start: 18*60 + 30, end: 19*60 + 30}, // 夕食
+	}
+)
+
+// Planner 時間割に合わせた学習計画システム
+type Planner struct {
+	db       *database.DB
+	calendar *calendar.Calendar
+}
+
+// StudySlot 学習予定のコマ
+type StudySlot struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Subject string    `json:"subject"`
+}
+
+// DayPlan 1日の学習計画
+type DayPlan struct {
+	Date         time.Time   `json:"date"`
+	IsHoliday    bool        `json:"is_holiday"`
+	HolidayNote  string      `json:"holiday_note"`
+	ExamNote     string      `json:"exam_note"`   // テスト期間名
+	BusyBlocks   []string    `json:"busy_blocks"` // 表示用（"08:30-15:30 授業"）
+	Slots        []StudySlot `json:"slots"`
+	TotalMinutes int         `json:"total_minutes"`
+}
+
+// timeRange 0時からの分で表した時間帯
+type timeRange struct {
+	start int
+	end   int
+}
+
+// NewPlanner 学習計画システムを作成
+func NewPlanner(db *database.DB, cal *calendar.Calendar) *Planner {
+	return &Planner{db: db, calendar: cal}
+}
+
+// PlanDay 指定日の学習計画を作成（subjectsは好きな科目順）
+func (p *Planner) PlanDay(userID string, date time.Time, goalMinutes int, subjects []string) (*DayPlan, error) {
+	entries, err := p.db.GetTimetableEntries(userID)
+	if err != nil {
+		return nil, fmt.Errorf("時間割取得エラー: %w", err)
+	}
+
+	exceptions, err := p.db.GetScheduleExceptions(userID)
+	if err != nil {
+		return nil, fmt.Errorf("例外日取得エラー: %w", err)
+	}
+
+	return ProposeDay(entries, exceptions, p.calendar, date, goalMinutes, subjects), nil
+}
+
+// PlanWeek 指定日から7日分の学習計画を作成
+func (p *Planner) PlanWeek(userID string, from time.Time, goalMinutes int, subjects []string) ([]*DayPlan, error) {
+	entries, err := p.db.GetTimetableEntries(userID)
+	if err != nil {
+		return nil, fmt.Errorf("時間割取得エラー: %w", err)
+	}
+
+	exceptions, err := p.db.GetScheduleExceptions(userID)
+	if err != nil {
+		return nil, fmt.Errorf("例外日取得エラー: %w", err)
+	}
+
+	return ProposeWeek(entries, exceptions, p.calendar, from, goalMinutes, subjects), nil
+}
+
+// NextSlot 現在時刻以降で最も近い学習予定を取得（当日に無ければ翌日以降を探索）
+func (p *Planner) NextSlot(userID string, now time.Time, goalMinutes int, subjects []string) (*StudySlot, error) {
+	plans, err := p.PlanWeek(userID, now, goalMinutes, subjects)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, plan := range plans {
+		for _, slot := range plan.Slots {
+			if slot.Start.After(now) {
+				return &slot, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// ProposeDay 時間割・例外日・学校カレンダーから1日の学習計画を提案
+func ProposeDay(entries []database.TimetableEntry, exceptions []database.ScheduleException, cal *calendar.Calendar, date time.Time, goalMinutes int, subjects []string) *DayPlan {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	plan := &DayPlan{Date: day}
+
+	dateKey := day.Format("2006-01-02")
+	exceptional := false
+	for _, exception := range exceptions {
+		if exception.Date == dateKey {
+			exceptional = true
+			plan.IsHoliday = true
+			plan.HolidayNote = exception.Note
+			break
+		}
+	}
+
+	// 祝日・長期休みは授業なし（部活動・塾は時間割どおり）
+	info := cal.Day(day)
+	if !plan.IsHoliday && info.IsGraceDay() {
+		plan.IsHoliday = true
+		plan.HolidayNote = info.Label()
+	}
+
+	// テスト期間は学習目標を増やす
+	if info.Exam != "" {
+		plan.ExamNote = info.Exam
+		goalMinutes = goalMinutes * examGoalPercent / 100
+	}
+
+	// 授業のある日は放課後、無い日（休日・例外日）は日中から計画
+	var busy []timeRange
+	hasSchool := false
+	if !exceptional {
+		for _, entry := range entries {
+			if entry.Weekday != int(day.Weekday()) {
+				continue
+			}
+			if entry.Kind == "school" && plan.IsHoliday {
+				continue
+			}
+			start, errStart := parseClock(entry.StartTime)
+			end, errEnd := parseClock(entry.EndTime)
+			if errStart != nil || errEnd != nil || end <= start {
+				continue
+			}
+			busy = append(busy, timeRange{start: start, end: end + afterBusyMinutes})
+			hasSchool = hasSchool || entry.Kind == "school"
+			plan.BusyBlocks = append(plan.BusyBlocks, fmt.Sprintf("%s-%s %s", entry.StartTime, entry.EndTime, entry.Label))
+		}
+	}
+
+	window := holidayWindow
+	if hasSchool || (!plan.IsHoliday && info.IsSchoolDay()) {
+		window = schoolDayWindow
+	}
+	busy = append(busy, mealTimes...)
+
+	free := subtractRanges(window, busy)
+
+	// 空き時間に学習コマを配置
+	remaining := goalMinutes
+	subjectIndex := day.YearDay() % max(len(subjects), 1)
+	for _, slot := range free {
+		cursor := slot.start
+		for remaining > 0 && slot.end-cursor >= minSlotMinutes {
+			length := min(slotMinutes, slot.end-cursor, remaining)
+
+			subject := ""
+			if len(subjects) > 0 {
+				subject = subjects[subjectIndex%len(subjects)]
+				subjectIndex++
+			}
+
+			plan.Slots = append(plan.Slots, StudySlot{
+				Start:   day.Add(time.Duration(cursor) * time.Minute),
+				End:     day.Add(time.Duration(cursor+length) * time.Minute),
+				Subject: subject,
+			})
+			plan.TotalMinutes += length
+			remaining -= length
+			cursor += length + slotGapMinutes
+		}
+		if remaining <= 0 {
+			break
+		}
+	}
+
+	return plan
+}
+
+// ProposeWeek 指定日から7日分の学習計画を提案
+func ProposeWeek(entries []database.TimetableEntry, exceptions []database.ScheduleException, cal *calendar.Calendar, from time.Time, goalMinutes int, subjects []string) []*DayPlan {
+	plans := make([]*DayPlan, 0, 7)
+	for i := 0; i < 7; i++ {
+		plans = append(plans, ProposeDay(entries, exceptions, cal, from.AddDate(0, 0, i), goalMinutes, subjects))
+	}
+	return plans
+}
+
+// subtractRanges 時間帯から予定のある時間帯を除いた空き時間を計算
+func subtractRanges(window timeRange, busy []timeRange) []timeRange {
+	sort.Slice(busy, func(i, j int) bool { return busy[i].start < busy[j].start })
+
+	var free []timeRange
+	cursor := window.start
+	for _, b := range busy {
+		if b.end <= cursor || b.start >= window.end {
+			continue
+		}
+		if b.start > cursor {
+			free = append(free, timeRange{start: cursor, end: b.start})
+		}
+		cursor = max(cursor, b.end)
+	}
+	if cursor < window.end {
+		free = append(free, timeRange{start: cursor, end: window.end})
+	}
+	return free
+}
+
+// parseClock "HH:MM"形式を0時からの分に変換
+func parseClock(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("時刻の形式が不正です: %s", value)
+	}
+
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("時刻の形式が不正です: %s", value)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("時刻の形式が不正です: %s", value)
+	}
+
+	return hour*60 + minute, nil
+}
+
+// ValidateClock 時刻文字列の妥当性チェック
+func ValidateClock(value string) error {
+	_, err := parseClock(value)
+	return err
+}
+
+// ValidateRange 開始・終了時刻の妥当性チェック
+func ValidateRange(start, end string) error {
+	startMinutes, err := parseClock(start)
+	if err != nil {
+		return err
+	}
+	endMinutes, err := parseClock(end)
+	if err != nil {
+		return err
+	}
+	if endMinutes <= startMinutes {
+		return fmt.Errorf("終了時刻は開始時刻より後にしてください")
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/calendar"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// testTimetable 金曜日に授業と部活動、火曜日に授業がある時間割
+var testTimetable = []database.TimetableEntry{
+	{Weekday: 5, StartTime: "08:30", EndTime: "15:30", Label: "授業", Kind: "school"},
+	{Weekday: 5, StartTime: "15:30", EndTime: "17:30", Label: "部活動", Kind: "club"},
+	{Weekday: 2, StartTime: "08:30", EndTime: "15:30", Label: "授業", Kind: "school"},
+}
+
+// plainCalendar 祝日だけの学校カレンダー（長期休み・テスト期間なし）
+func plainCalendar() *calendar.Calendar {
+	cfg := config.Default()
+	cfg.School = config.SchoolConfig{}
+	return calendar.New(cfg)
+}
+
+// slotTimes 学習予定のコマの時刻（"18:00-18:30"）
+func slotTimes(plan *DayPlan) []string {
+	var times []string
+	for _, slot := range plan.Slots {
+		times = append(times, slot.Start.Format("15:04")+"-"+slot.End.Format("15:04"))
+	}
+	return times
+}
+
+func TestProposeDayAfterSchoolAndClub(t *testing.T) {
+	cal := plainCalendar()
+	friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+
+	plan := ProposeDay(testTimetable, nil, cal, friday, 60, []string{"数学", "英語"})
+	if plan.IsHoliday || len(plan.BusyBlocks) != 2 {
+		t.Errorf("授業のある日の計画 = %+v", plan)
+	}
+	// 部活動のあとの休憩（30分）と夕食の時間を避ける
+	want := []string{"18:00-18:30", "19:30-20:00"}
+	if got := slotTimes(plan); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("学習予定 = %v, want %v", got, want)
+	}
+	if plan.TotalMinutes != 60 || plan.Slots[0].Subject == plan.Slots[1].Subject {
+		t.Errorf("合計 %d分・科目 %+v", plan.TotalMinutes, plan.Slots)
+	}
+}
+
+func TestProposeDayException(t *testing.T) {
+	cal := plainCalendar()
+	friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	exceptions := []database.ScheduleException{{Date: "2026-10-16", Note: "修学旅行"}}
+
+	// 例外日は時間割を使わず、日中から計画する
+	plan := ProposeDay(testTimetable, exceptions, cal, friday, 30, []string{"数学"})
+	if !plan.IsHoliday || plan.HolidayNote != "修学旅行" || len(plan.BusyBlocks) != 0 {
+		t.Errorf("例外日の計画 = %+v", plan)
+	}
+	if got := slotTimes(plan); len(got) != 1 || got[0] != "09:00-09:30" {
+		t.Errorf("例外日の学習予定 = %v", got)
+	}
+}
+
+func TestProposeDayNationalHoliday(t *testing.T) {
+	cal := plainCalendar()
+	culture := time.Date(2026, 11, 3, 0, 0, 0, 0, time.Local) // 文化の日（火曜日）
+
+	// 祝日は授業がない
+	plan := ProposeDay(testTimetable, nil, cal, culture, 30, nil)
+	if !plan.IsHoliday || plan.HolidayNote != "文化の日" || len(plan.BusyBlocks) != 0 {
+		t.Errorf("祝日の計画 = %+v", plan)
+	}
+	if got := slotTimes(plan); len(got) != 1 || got[0] != "09:00-09:30" {
+		t.Errorf("祝日の学習予定 = %v", got)
+	}
+}
+
+func TestProposeDayExamWeek(t *testing.T) {
+	cfg := config.Default()
+	cfg.School = config.SchoolConfig{ExamWeeks: []config.Period{{Name: "期末テスト", Start: "10-12", End: "10-18"}}}
+	friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+
+	// テスト期間は学習目標を増やす
+	plan := ProposeDay(testTimetable, nil, calendar.New(cfg), friday, 60, nil)
+	if plan.ExamNote != "期末テスト" || plan.TotalMinutes != 90 {
+		t.Errorf("テスト期間の計画 = %s・%d分", plan.ExamNote, plan.TotalMinutes)
+	}
+}
+
+func TestValidateRange(t *testing.T) {
+	if err := ValidateRange("08:30", "15:30"); err != nil {
+		t.Error(err)
+	}
+	for _, r := range [][2]string{{"15:30", "08:30"}, {"8時", "15:30"}, {"08:30", "24:00"}} {
+		if err := ValidateRange(r[0], r[1]); err == nil {
+			t.Errorf("ValidateRange(%q, %q) はエラーになるはず", r[0], r[1])
+		}
+	}
+}
@@ -0,0 +1,545 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/achievement"
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/pet"
+	"github.com/okamyuji/studybuddy-ai/internal/xp"
+)
+
+// apiSession 連携アプリの学習セッション（出題したがまだ解答していない問題を持つ）
+type apiSession struct {
+	mu                 sync.Mutex
+	record             *database.StudySession
+	problems           map[string]*ai.Problem
+	recentHashes       []string
+	consecutiveCorrect int
+	lastUsed           time.Time
+}
+
+// healthResponse サーバーとAIの状態
+type healthResponse struct {
+	Status     string `json:"status"`
+	APIVersion string `json:"api_version"`
+	AI         string `json:"ai"`
+	Model      string `json:"model"`
+}
+
+// startSessionRequest セッション開始のリクエスト
+type startSessionRequest struct {
+	Subject string `json:"subject"`
+}
+
+// sessionResponse セッションの状態
+type sessionResponse struct {
+	SessionID      string    `json:"session_id"`
+	Subject        string    `json:"subject"`
+	StartedAt      time.Time `json:"started_at"`
+	TotalProblems  int       `json:"total_problems"`
+	CorrectAnswers int       `json:"correct_answers"`
+	MaxCombo       int       `json:"max_combo"`
+	Seconds        int       `json:"seconds,omitempty"` // 終了したセッションの学習時間
+}
+
+// problemRequest 出題のリクエスト（省略すればアプリの科目別の難易度・学習範囲全体）
+type problemRequest struct {
+	Topic      string `json:"topic"`
+	Difficulty int    `json:"difficulty"`
+}
+
+// problemResponse 出題した問題（正解・解説は解答のレスポンスで返す）
+type problemResponse struct {
+	ProblemID   string   `json:"problem_id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Options     []string `json:"options"`
+	Difficulty  int      `json:"difficulty"`
+	Topic       string   `json:"topic"`
+}
+
+// answerRequest 解答のリクエスト
+type answerRequest struct {
+	ProblemID string `json:"problem_id"`
+	Answer    int    `json:"answer"`     // Optionsの添字（0から）
+	TimeTaken int    `json:"time_taken"` // 秒
+	Feedback  bool   `json:"feedback"`   // AIのフィードバックも作るかどうか
+}
+
+// answerResponse 解答の結果
+type answerResponse struct {
+	Correct       bool              `json:"correct"`
+	CorrectAnswer int               `json:"correct_answer"`
+	Explanation   string            `json:"explanation"`
+	XPAwarded     int               `json:"xp_awarded"`
+	Level         int               `json:"level"`
+	LeveledUp     bool              `json:"leveled_up"`
+	Combo         int               `json:"combo"`
+	Feedback      *feedbackResponse `json:"feedback,omitempty"`
+}
+
+// feedbackResponse AIのフィードバック
+type feedbackResponse struct {
+	Message       string `json:"message"`
+	Explanation   string `json:"explanation"`
+	Encouragement string `json:"encouragement"`
+	NextSteps     string `json:"next_steps"`
+}
+
+// progressResponse 学習の進み具合（進捗タブと同じ集計）
+type progressResponse struct {
+	TotalProblems   int                `json:"total_problems"`
+	TotalCorrect    int                `json:"total_correct"`
+	AccuracyRate    float64            `json:"accuracy_rate"`
+	StudyDays       int                `json:"study_days"`
+	StudySeconds    int                `json:"study_seconds"`
+	CurrentStreak   int                `json:"current_streak"`
+	LongestStreak   int                `json:"longest_streak"`
+	XP              xp.Progress        `json:"xp"`
+	SubjectProblems map[string]int     `json:"subject_problems"`
+	SubjectAccuracy map[string]float64 `json:"subject_accuracy"`
+}
+
+// handleHealth サーバーとAIの状態（トークンなしで確かめられる）
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, healthResponse{
+		Status:     "ok",
+		APIVersion: APIVersion,
+		AI:         s.engine.Health().Status.Label(),
+		Model:      s.engine.GetCurrentModel(),
+	})
+}
+
+// handleStartSession 学習セッションを始める
+func (s *Server) handleStartSession(w http.ResponseWriter, r *http.Request) {
+	var req startSessionRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !slices.Contains(config.Subjects, req.Subject) {
+		writeError(w, http.StatusBadRequest, "科目が正しくありません: "+req.Subject)
+		return
+	}
+
+	now := time.Now()
+	record := &database.StudySession{
+		ID:             uuid.New().String(),
+		UserID:         s.userID,
+		Subject:        req.Subject,
+		StartTime:      now,
+		AverageEmotion: "neutral",
+		SessionType:    database.SessionTypeApp,
+		CreatedAt:      now,
+	}
+	if err := s.db.CreateStudySession(record); err != nil {
+		slog.Error("セッション作成エラー", "error", err)
+		writeError(w, http.StatusInternalServerError, "セッションを開始できませんでした")
+		return
+	}
+
+	s.pruneSessions(now)
+	s.mu.Lock()
+	s.sessions[record.ID] = &apiSession{record: record, problems: make(map[string]*ai.Problem), lastUsed: now}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, sessionResponse{SessionID: record.ID, Subject: record.Subject, StartedAt: record.StartTime})
+}
+
+// handleNextProblem セッションの科目の問題を1問作る
+func (s *Server) handleNextProblem(w http.ResponseWriter, r *http.Request) {
+	session := s.lookupSession(w, r)
+	if session == nil {
+		return
+	}
+	var req problemRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Difficulty < 0 || req.Difficulty > 5 {
+		writeError(w, http.StatusBadRequest, "難易度は1〜5で指定してください")
+		return
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	subject := session.record.Subject
+	if req.Difficulty == 0 {
+		req.Difficulty = s.config.DifficultyFor(subject)
+	}
+	// 最近1週間に出題した問題とほぼ同じ問題は出さない（アプリの出題と同じ）
+	recentHashes, err := s.db.GetRecentSimilarityHashes(s.userID, subject, time.Now().Add(-ai.DuplicateWindow))
+	if err != nil {
+		slog.Error("類似ハッシュ取得エラー", "error", err)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), problemTimeout)
+	defer cancel()
+	problem, err := s.engine.GeneratePersonalizedProblem(ctx, ai.StudyContext{
+		UserID:       s.userID,
+		Subject:      subject,
+		Grade:        s.grade(),
+		Difficulty:   req.Difficulty,
+		Emotion:      "neutral",
+		Topic:        req.Topic,
+		RecentHashes: append(recentHashes, session.recentHashes...),
+	})
+	if err != nil {
+		slog.Error("問題生成エラー", "error", err)
+		writeError(w, http.StatusServiceUnavailable, "問題を作成できませんでした")
+		return
+	}
+	if req.Topic != "" {
+		problem.ProblemType = req.Topic
+	}
+
+	problemID := uuid.New().String()
+	session.problems[problemID] = problem
+	session.recentHashes = append(session.recentHashes, ai.SimilarityHash(problem.Description))
+	session.lastUsed = time.Now()
+
+	writeJSON(w, http.StatusOK, problemResponse{
+		ProblemID:   problemID,
+		Title:       problem.Title,
+		Description: problem.Description,
+		Options:     problem.Options,
+		Difficulty:  problem.Difficulty,
+		Topic:       problem.ProblemType,
+	})
+}
+
+// handleAnswer 解答を記録し、画面での解答と同じく経験値・ペット・実績に反映する
+func (s *Server) handleAnswer(w http.ResponseWriter, r *http.Request) {
+	session := s.lookupSession(w, r)
+	if session == nil {
+		return
+	}
+	var req answerRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	problem, ok := session.problems[req.ProblemID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "問題が見つかりません（解答済みか、出題していない問題です）")
+		return
+	}
+	if req.Answer < 0 || req.Answer >= len(problem.Options) {
+		writeError(w, http.StatusBadRequest, "解答の番号が正しくありません")
+		return
+	}
+	delete(session.problems, req.ProblemID)
+
+	now := time.Now()
+	record := session.record
+	isCorrect := req.Answer == problem.CorrectAnswer
+	difficulty := max(problem.Difficulty, 1)
+	timeTaken := max(req.TimeTaken, 0)
+
+	result := &database.ProblemResult{
+		ID:              uuid.New().String(),
+		SessionID:       record.ID,
+		ProblemType:     problem.ProblemType,
+		Difficulty:      difficulty,
+		IsCorrect:       isCorrect,
+		TimeTaken:       timeTaken,
+		EmotionAtAnswer: "neutral",
+		UserAnswer:      problem.Options[req.Answer],
+		CreatedAt:       now,
+	}
+	recordProblemContent(result, problem)
+	s.recordProblemQuality(result, problem)
+	answers := []database.AnswerRecord{{Result: result, Subject: record.Subject, AnsweredAt: now}}
+	if err := s.db.RecordAnswers(s.userID, answers); err != nil {
+		slog.Error("結果保存エラー", "error", err)
+		writeError(w, http.StatusInternalServerError, "解答を記録できませんでした")
+		return
+	}
+
+	record.TotalProblems++
+	if isCorrect {
+		record.CorrectAnswers++
+		session.consecutiveCorrect++
+	} else {
+		session.consecutiveCorrect = 0
+	}
+	record.MaxCombo = max(record.MaxCombo, session.consecutiveCorrect)
+	if err := s.db.UpdateStudySession(record); err != nil {
+		slog.Error("セッション更新エラー", "error", err)
+	}
+	session.lastUsed = now
+
+	studyResult := pet.StudyResult{
+		IsCorrect:          isCorrect,
+		Difficulty:         difficulty,
+		TimeTaken:          timeTaken,
+		ConsecutiveCorrect: session.consecutiveCorrect,
+		SessionDuration:    int(now.Sub(record.StartTime).Seconds()),
+		EnergyPercent:      s.energyPercent(now),
+	}
+	response := answerResponse{
+		Correct:       isCorrect,
+		CorrectAnswer: problem.CorrectAnswer,
+		Explanation:   problem.Explanation,
+		Combo:         session.consecutiveCorrect,
+	}
+	if award := s.grantAnswer(studyResult); award != nil {
+		response.XPAwarded = award.Amount
+		response.Level = award.After.Level
+		response.LeveledUp = award.LeveledUp()
+	}
+
+	if req.Feedback {
+		ctx, cancel := context.WithTimeout(r.Context(), problemTimeout)
+		defer cancel()
+		feedback, err := s.engine.GenerateFeedback(ctx, ai.FeedbackRequest{
+			Problem:      *problem,
+			UserAnswer:   result.UserAnswer,
+			IsCorrect:    isCorrect,
+			TimeTaken:    timeTaken,
+			Emotion:      "neutral",
+			StudyContext: ai.StudyContext{UserID: s.userID, Subject: record.Subject, Grade: s.grade()},
+		})
+		if err != nil {
+			slog.Error("フィードバック生成エラー", "error", err)
+		} else {
+			response.Feedback = &feedbackResponse{
+				Message:       feedback.Message,
+				Explanation:   feedback.Explanation,
+				Encouragement: feedback.Encouragement,
+				NextSteps:     feedback.NextSteps,
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// grantAnswer 解答の経験値を付与し、ペット・実績に反映（失敗しても解答の記録は残す）
+func (s *Server) grantAnswer(studyResult pet.StudyResult) *xp.Award {
+	award, err := s.xpService.GrantAnswer(s.userID, studyResult.Answer())
+	if err != nil {
+		slog.Error("経験値付与エラー", "error", err)
+		return nil
+	}
+	if s.config.Learning.PetEnabled {
+		if _, err := s.pets.FeedPet(s.userID, studyResult); err != nil {
+			slog.Error("ペット更新エラー", "error", err)
+		}
+	}
+	if err := s.checkAchievements(award.After.Level); err != nil {
+		slog.Error("実績判定エラー", "error", err)
+	}
+	return award
+}
+
+// energyPercent 元気による経験値の割合（元気のしくみを使わなければ0）
+func (s *Server) energyPercent(now time.Time) int {
+	if !s.config.Learning.EnergyEnabled {
+		return 0
+	}
+	energy, err := s.xpService.Energy(s.userID, now)
+	if err != nil {
+		slog.Error("元気の計算エラー", "error", err)
+		return 0
+	}
+	return energy.Percent
+}
+
+// checkAchievements 画面と同じ条件で実績を判定
+func (s *Server) checkAchievements(level int) error {
+	stats := achievement.Stats{Level: level}
+	totalProblems, err := s.db.CountProblemResults(s.userID)
+	if err != nil {
+		return err
+	}
+	stats.TotalProblems = totalProblems
+
+	streak, err := s.progress.GetStudyStreak(s.userID)
+	if err != nil {
+		return err
+	}
+	stats.CurrentStreak = streak.CurrentStreak
+	stats.LongestStreak = streak.LongestStreak
+
+	_, err = s.achievements.Check(s.userID, stats)
+	return err
+}
+
+// handleEndSession 学習セッションを終了して結果を返す
+func (s *Server) handleEndSession(w http.ResponseWriter, r *http.Request) {
+	session := s.lookupSession(w, r)
+	if session == nil {
+		return
+	}
+	s.mu.Lock()
+	delete(s.sessions, r.PathValue("id"))
+	s.mu.Unlock()
+
+	session.mu.Lock()
+	session.lastUsed = time.Now()
+	session.mu.Unlock()
+	s.finishSession(session)
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	record := session.record
+	response := sessionResponse{
+		SessionID:      record.ID,
+		Subject:        record.Subject,
+		StartedAt:      record.StartTime,
+		TotalProblems:  record.TotalProblems,
+		CorrectAnswers: record.CorrectAnswers,
+		MaxCombo:       record.MaxCombo,
+	}
+	if record.EndTime != nil {
+		response.Seconds = int(record.EndTime.Sub(record.StartTime).Seconds())
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleProgress 学習の進み具合
+func (s *Server) handleProgress(w http.ResponseWriter, r *http.Request) {
+	analysis, err := s.progress.AnalyzeProgress(s.userID)
+	if err != nil {
+		slog.Error("進捗分析エラー", "error", err)
+		writeError(w, http.StatusInternalServerError, "進み具合を取得できませんでした")
+		return
+	}
+	level, err := s.xpService.Progress(s.userID)
+	if err != nil {
+		slog.Error("経験値取得エラー", "error", err)
+	}
+
+	response := progressResponse{
+		XP:              level,
+		SubjectProblems: make(map[string]int),
+		SubjectAccuracy: make(map[string]float64),
+	}
+	if overall := analysis.OverallProgress; overall != nil {
+		response.StudyDays = overall.StudyDaysCount
+		response.StudySeconds = overall.TotalStudyTime
+	}
+	if streak := analysis.StudyStreak; streak != nil {
+		response.CurrentStreak = streak.CurrentStreak
+		response.LongestStreak = streak.LongestStreak
+	}
+
+	// 解答数・正答率は学習セッションの記録から数える
+	sessions, err := s.db.GetStudySessionsBetween(s.userID, time.Time{}, time.Now().AddDate(0, 0, 1))
+	if err != nil {
+		slog.Error("セッション取得エラー", "error", err)
+	}
+	subjectCorrect := make(map[string]int)
+	for _, session := range sessions {
+		response.TotalProblems += session.TotalProblems
+		response.TotalCorrect += session.CorrectAnswers
+		response.SubjectProblems[session.Subject] += session.TotalProblems
+		subjectCorrect[session.Subject] += session.CorrectAnswers
+	}
+	if response.TotalProblems > 0 {
+		response.AccuracyRate = float64(response.TotalCorrect) / float64(response.TotalProblems)
+	}
+	for subject, total := range response.SubjectProblems {
+		if total > 0 {
+			response.SubjectAccuracy[subject] = float64(subjectCorrect[subject]) / float64(total)
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// grade プロフィールの学年（取得できなければ設定の学年）
+func (s *Server) grade() int {
+	if user, err := s.db.GetUser(s.userID); err == nil && user != nil {
+		return user.Grade
+	}
+	return s.config.UserGrade
+}
+
+// lookupSession URLのセッションを探す（見つからなければ404を書いてnil）
+func (s *Server) lookupSession(w http.ResponseWriter, r *http.Request) *apiSession {
+	s.mu.Lock()
+	session := s.sessions[r.PathValue("id")]
+	s.mu.Unlock()
+	if session == nil {
+		writeError(w, http.StatusNotFound, "セッションが見つかりません（終了したか、長い間使われなかったセッションです）")
+	}
+	return session
+}
+
+// pruneSessions 長い間使われていないセッションを終了する
+func (s *Server) pruneSessions(now time.Time) {
+	var idle []*apiSession
+	s.mu.Lock()
+	for id, session := range s.sessions {
+		// 問題の作成中など、使われているセッションは待たずに残す
+		if !session.mu.TryLock() {
+			continue
+		}
+		if now.Sub(session.lastUsed) > sessionIdleLimit {
+			idle = append(idle, session)
+			delete(s.sessions, id)
+		}
+		session.mu.Unlock()
+	}
+	s.mu.Unlock()
+
+	for _, session := range idle {
+		s.finishSession(session)
+	}
+}
+
+// finishSession セッションの終了時刻を記録（最後に使われた時刻を終了時刻とする）
+func (s *Server) finishSession(session *apiSession) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.record.EndTime != nil {
+		return
+	}
+	end := session.lastUsed
+	session.record.EndTime = &end
+	if err := s.db.UpdateStudySession(session.record); err != nil {
+		slog.Error("セッション更新エラー", "error", err)
+	}
+}
+
+// recordProblemContent 画面での解答と同じく、問題の内容を解答結果に記録（復習・間違いノートで使う）
+func recordProblemContent(result *database.ProblemResult, problem *ai.Problem) {
+	result.ProblemTitle = problem.Title
+	result.ProblemContent = problem.Description
+	result.Explanation = problem.Explanation
+	result.CorrectAnswer = problem.Options[problem.CorrectAnswer]
+	result.SimilarityHash = ai.SimilarityHash(problem.Description)
+	result.Model = problem.Model
+	if options, err := json.Marshal(problem.Options); err == nil {
+		result.ProblemOptions = string(options)
+	}
+}
+
+// recordProblemQuality 同じ問題のこれまでの正答率（今回の解答を含む）も使って、問題の品質スコアを記録
+func (s *Server) recordProblemQuality(result *database.ProblemResult, problem *ai.Problem) {
+	attempts, correct, err := s.db.GetSimilarProblemAccuracy(result.SimilarityHash)
+	if err != nil {
+		slog.Error("正答率取得エラー", "error", err)
+	}
+	attempts++
+	if result.IsCorrect {
+		correct++
+	}
+	result.QualityScore = ai.QualityScore(problem, attempts, correct)
+}
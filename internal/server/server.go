@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/achievement"
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/calendar"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/pet"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+	"github.com/okamyuji/studybuddy-ai/internal/xp"
+)
+
+// APIのバージョン（URLの /api/v1 の部分。互換性のない変更をするときに上げる）
+const APIVersion = "v1"
+
+// サーバーの動作
+const (
+	listenHost        = "127.0.0.1" // このパソコンの中からだけ接続できる
+	tokenBytes        = 32
+	maxRequestBytes   = 64 * 1024
+	sessionIdleLimit  = 2 * time.Hour // これより長く使われていないセッションは終了する
+	readHeaderTimeout = 10 * time.Second
+	problemTimeout    = 90 * time.Second // 問題・フィードバックの生成を待つ時間
+)
+
+// Server 連携アプリ向けのAPIサーバー（アプリと同じデータベース・AIエンジン・経験値の計算を使う）
+type Server struct {
+	db     *database.DB
+	engine *ai.Engine
+	config *config.Config
+	userID string
+
+	xpService    *xp.Service
+	pets         *pet.Manager
+	achievements *achievement.Manager
+	progress     *progress.Manager
+
+	mu       sync.Mutex
+	sessions map[string]*apiSession
+	http     *http.Server
+	addr     string
+}
+
+// New APIサーバーを作成（userIDは連携アプリで学習するプロフィール）
+func New(db *database.DB, engine *ai.Engine, cfg *config.Config, userID string) *Server {
+	return &Server{
+		db:           db,
+		engine:       engine,
+		config:       cfg,
+		userID:       userID,
+		xpService:    xp.NewService(db),
+		pets:         pet.NewManager(db),
+		achievements: achievement.NewManager(db),
+		progress:     progress.NewManager(db, engine, calendar.New(cfg)),
+		sessions:     make(map[string]*apiSession),
+	}
+}
+
+// NewToken 接続に使うトークンを作成
+func NewToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("トークン作成エラー: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Start 設定のポート・トークンで待ち受けを始める（すでに動いていれば何もしない。設定を変えたらStopしてから呼ぶ）
+func (s *Server) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.http != nil {
+		return nil
+	}
+	if s.config.Server.Token == "" {
+		return errors.New("APIサーバーのトークンがありません")
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(listenHost, fmt.Sprint(s.config.Server.Port)))
+	if err != nil {
+		return fmt.Errorf("APIサーバー起動エラー: %w", err)
+	}
+	s.addr = listener.Addr().String()
+	s.http = &http.Server{Handler: s.Handler(), ReadHeaderTimeout: readHeaderTimeout}
+
+	srv := s.http
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("APIサーバーエラー", "error", err)
+		}
+	}()
+	slog.Info("📱 APIサーバーを起動しました", "addr", s.addr)
+	return nil
+}
+
+// Stop 待ち受けをやめ、進行中のセッションを終了する
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	srv := s.http
+	s.http = nil
+	s.addr = ""
+	sessions := s.sessions
+	s.sessions = make(map[string]*apiSession)
+	s.mu.Unlock()
+
+	for _, session := range sessions {
+		s.finishSession(session)
+	}
+	if srv == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		return fmt.Errorf("APIサーバー停止エラー: %w", err)
+	}
+	slog.Info("📱 APIサーバーを停止しました")
+	return nil
+}
+
+// Addr 待ち受けているアドレス（止まっていれば空）
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr
+}
+
+// Handler APIのハンドラー（/api/v1/health 以外は、作成した時点の設定のトークンが必要）
+func (s *Server) Handler() http.Handler {
+	token := s.config.Server.Token
+	authorized := func(next http.HandlerFunc) http.Handler {
+		return requireToken(token, next)
+	}
+
+	mux := http.NewServeMux()
+	prefix := "/api/" + APIVersion
+	mux.HandleFunc("GET "+prefix+"/health", s.handleHealth)
+	mux.Handle("POST "+prefix+"/sessions", authorized(s.handleStartSession))
+	mux.Handle("POST "+prefix+"/sessions/{id}/problems", authorized(s.handleNextProblem))
+	mux.Handle("POST "+prefix+"/sessions/{id}/answers", authorized(s.handleAnswer))
+	mux.Handle("POST "+prefix+"/sessions/{id}/end", authorized(s.handleEndSession))
+	mux.Handle("GET "+prefix+"/progress", authorized(s.handleProgress))
+	return mux
+}
+
+// requireToken Authorization: Bearer <トークン> を確かめる
+func requireToken(want string, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || want == "" || subtle.ConstantTimeCompare([]byte(token), []byte(want)) != 1 {
+			writeError(w, http.StatusUnauthorized, "トークンが正しくありません")
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBytes)
+		next(w, r)
+	})
+}
+
+// errorResponse エラーのレスポンス
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeJSON JSONのレスポンスを書く
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(body); err != nil {
+		slog.Error("APIレスポンス書き込みエラー", "error", err)
+	}
+}
+
+// writeError エラーのレスポンスを書く
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// readJSON リクエストのJSONを読む（本文が空なら何もしない）
+func readJSON(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("リクエストを読み取れません: %w", err)
+	}
+	return nil
+}
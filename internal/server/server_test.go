@@ -0,0 +1,141 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/pet"
+	"github.com/okamyuji/studybuddy-ai/internal/scenario"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+const testToken = "test-token"
+
+// newTestServer オフラインのAIとテスト用データベースでAPIサーバーを用意
+func newTestServer(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	user := testutil.Seed(t, db, testutil.Fixture{
+		User: database.User{ID: "user-api", Name: "連携太郎", Grade: 2, CreatedAt: time.Now()},
+	})
+
+	if _, err := pet.NewManager(db).EnsurePet(user.ID, "cat"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Server.Token = testToken
+	engine, err := scenario.NewOfflineEngine(cfg.AI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = engine.Close() })
+
+	srv := New(db, engine, cfg, user.ID)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return srv, ts
+}
+
+// call APIを呼んでレスポンスをoutに読み込む
+func call(t *testing.T, ts *httptest.Server, method, path, token string, body, out any) int {
+	t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, ts.URL+path, &payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: レスポンスを読み取れません: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestRequiresToken(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	var health healthResponse
+	if status := call(t, ts, http.MethodGet, "/api/v1/health", "", nil, &health); status != http.StatusOK || health.APIVersion != APIVersion {
+		t.Errorf("health = %d %+v", status, health)
+	}
+	for _, token := range []string{"", "wrong-token"} {
+		if status := call(t, ts, http.MethodGet, "/api/v1/progress", token, nil, nil); status != http.StatusUnauthorized {
+			t.Errorf("トークン %q: status = %d, want 401", token, status)
+		}
+	}
+}
+
+func TestStudyFlow(t *testing.T) {
+	srv, ts := newTestServer(t)
+
+	var session sessionResponse
+	if status := call(t, ts, http.MethodPost, "/api/v1/sessions", testToken, startSessionRequest{Subject: "体育"}, nil); status != http.StatusBadRequest {
+		t.Errorf("未知の科目: status = %d, want 400", status)
+	}
+	if status := call(t, ts, http.MethodPost, "/api/v1/sessions", testToken, startSessionRequest{Subject: "数学"}, &session); status != http.StatusCreated {
+		t.Fatalf("セッション開始: status = %d", status)
+	}
+	base := "/api/v1/sessions/" + session.SessionID
+
+	var problem problemResponse
+	if status := call(t, ts, http.MethodPost, base+"/problems", testToken, problemRequest{Difficulty: 2}, &problem); status != http.StatusOK {
+		t.Fatalf("出題: status = %d", status)
+	}
+	if problem.ProblemID == "" || len(problem.Options) == 0 {
+		t.Fatalf("問題 = %+v", problem)
+	}
+
+	// 正解は出題のレスポンスに含めないため、サーバーが覚えている問題から選ぶ
+	correct := srv.sessions[session.SessionID].problems[problem.ProblemID].CorrectAnswer
+	var answer answerResponse
+	req := answerRequest{ProblemID: problem.ProblemID, Answer: correct, TimeTaken: 30, Feedback: true}
+	if status := call(t, ts, http.MethodPost, base+"/answers", testToken, req, &answer); status != http.StatusOK {
+		t.Fatalf("解答: status = %d", status)
+	}
+	if !answer.Correct || answer.XPAwarded <= 0 || answer.Feedback == nil {
+		t.Errorf("解答の結果 = %+v", answer)
+	}
+	if status := call(t, ts, http.MethodPost, base+"/answers", testToken, req, nil); status != http.StatusNotFound {
+		t.Errorf("同じ問題への2回目の解答: status = %d, want 404", status)
+	}
+
+	var ended sessionResponse
+	if status := call(t, ts, http.MethodPost, base+"/end", testToken, nil, &ended); status != http.StatusOK {
+		t.Fatalf("セッション終了: status = %d", status)
+	}
+	if ended.TotalProblems != 1 || ended.CorrectAnswers != 1 {
+		t.Errorf("セッションの結果 = %+v", ended)
+	}
+	if status := call(t, ts, http.MethodPost, base+"/problems", testToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("終了したセッション: status = %d, want 404", status)
+	}
+
+	var progress progressResponse
+	if status := call(t, ts, http.MethodGet, "/api/v1/progress", testToken, nil, &progress); status != http.StatusOK {
+		t.Fatalf("進み具合: status = %d", status)
+	}
+	if progress.TotalProblems != 1 || progress.XP.Total != answer.XPAwarded || progress.CurrentStreak != 1 || progress.SubjectAccuracy["数学"] != 1 {
+		t.Errorf("進み具合 = %+v", progress)
+	}
+}
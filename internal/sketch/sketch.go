@@ -0,0 +1,77 @@
+package sketch
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+)
+
+// 保存する画像の線の太さ（ピクセル）と最大の大きさ
+const (
+	LineWidth = 3
+	MaxSize   = 1200 // 長い辺がこれより大きければ縮める
+)
+
+// Point 手書きの線の1点（描いたエリアの左上からの位置）
+type Point struct {
+	X, Y float32
+}
+
+// Stroke ペンを置いてから離すまでの1本の線
+type Stroke []Point
+
+// Render 手書きの線を白い背景に黒い線で描いた画像にする（長い辺がMaxSizeをこえれば縮める）
+func Render(strokes []Stroke, width, height float32) *image.RGBA {
+	scale := float32(1)
+	if longest := max(width, height); longest > MaxSize {
+		scale = MaxSize / longest
+	}
+	w, h := max(int(width*scale), 1), max(int(height*scale), 1)
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for _, stroke := range strokes {
+		for i := range stroke {
+			from := stroke[max(i-1, 0)]
+			drawLine(img, from.X*scale, from.Y*scale, stroke[i].X*scale, stroke[i].Y*scale)
+		}
+	}
+	return img
+}
+
+// EncodePNG 手書きの線をPNG画像にする
+func EncodePNG(strokes []Stroke, width, height float32) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, Render(strokes, width, height)); err != nil {
+		return nil, fmt.Errorf("計算メモの画像変換エラー: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLine 2点の間にLineWidthの太さの線を描く
+func drawLine(img *image.RGBA, x0, y0, x1, y1 float32) {
+	length := math.Hypot(float64(x1-x0), float64(y1-y0))
+	steps := max(int(math.Ceil(length)), 1)
+	for i := 0; i <= steps; i++ {
+		t := float32(i) / float32(steps)
+		drawDot(img, x0+(x1-x0)*t, y0+(y1-y0)*t)
+	}
+}
+
+// drawDot 点の周りを線の太さの円で塗る
+func drawDot(img *image.RGBA, x, y float32) {
+	radius := float32(LineWidth) / 2
+	for py := int(y - radius); py <= int(y+radius); py++ {
+		for px := int(x - radius); px <= int(x+radius); px++ {
+			dx, dy := float32(px)+0.5-x, float32(py)+0.5-y
+			if dx*dx+dy*dy <= radius*radius+0.25 && image.Pt(px, py).In(img.Rect) {
+				img.SetRGBA(px, py, color.RGBA{A: 0xff})
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package sketch
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	strokes := []Stroke{{{X: 10, Y: 10}, {X: 90, Y: 10}}, {{X: 50, Y: 40}}}
+	img := Render(strokes, 100, 50)
+	if img.Bounds().Dx() != 100 || img.Bounds().Dy() != 50 {
+		t.Fatalf("大きさ = %v", img.Bounds())
+	}
+	for _, p := range [][2]int{{10, 10}, {50, 10}, {90, 10}, {50, 40}} {
+		if c := img.RGBAAt(p[0], p[1]); c.R != 0 {
+			t.Errorf("(%d, %d) に線がありません: %v", p[0], p[1], c)
+		}
+	}
+	if c := img.RGBAAt(50, 25); c.R != 0xff {
+		t.Errorf("線のないところは白のはず: %v", c)
+	}
+
+	// 大きすぎるエリアは縮めて保存する
+	if big := Render(strokes, MaxSize*2, MaxSize); big.Bounds().Dx() != MaxSize || big.Bounds().Dy() != MaxSize/2 {
+		t.Errorf("縮めた大きさ = %v", big.Bounds())
+	}
+}
+
+func TestEncodePNG(t *testing.T) {
+	data, err := EncodePNG([]Stroke{{{X: 1, Y: 1}, {X: 5, Y: 5}}}, 20, 20)
+	if err != nil {
+		t.Fatalf("PNG変換エラー: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("PNG読み込みエラー: %v", err)
+	}
+	if img.Bounds().Dx() != 20 {
+		t.Errorf("大きさ = %v", img.Bounds())
+	}
+}
@@ -0,0 +1,145 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Speed 読み上げの速さ
+type Speed int
+
+// 読み上げの速さ
+const (
+	Normal Speed = iota // ふつう
+	Slow                // ゆっくり（聞き取れなかったとき用）
+)
+
+// Language 読み上げる言語
+type Language string
+
+// 読み上げる言語
+const (
+	English  Language = "en" // リスニング問題の英文
+	Japanese Language = "ja" // 問題文・フィードバックの読み上げ
+)
+
+// Speaker 端末に入っている読み上げ機能（macOS: say、Windows: System.Speech、Linux: espeak-ng / espeak）で文章を読み上げる
+type Speaker struct {
+	mu       sync.Mutex
+	lookPath func(file string) (string, error)
+	goos     string
+	cancel   context.CancelFunc // 読み上げ中の音声を止める
+}
+
+// New 読み上げ機能を作成
+func New() *Speaker {
+	return &Speaker{lookPath: exec.LookPath, goos: runtime.GOOS}
+}
+
+// Available 指定した言語で読み上げられるかどうか（読み上げ機能が入っていなければfalse）
+func (s *Speaker) Available(lang Language) bool {
+	_, _, ok := command(s.goos, s.lookPath, lang, Normal)
+	return ok
+}
+
+// Speak 文章を読み上げ、読み終わるまで待つ（読み上げ中の音声は止めてから読む）
+func (s *Speaker) Speak(ctx context.Context, lang Language, text string, speed Speed) error {
+	name, args, ok := command(s.goos, s.lookPath, lang, speed)
+	if !ok {
+		return fmt.Errorf("読み上げ機能が見つかりません（言語: %s）", lang)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.cancel = cancel
+	s.mu.Unlock()
+	defer cancel()
+
+	// 文章は引数ではなく標準入力で渡す（記号を含む文章でもコマンドとして解釈されない）
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(text)
+	if output, err := cmd.CombinedOutput(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("読み上げエラー: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Stop 読み上げ中の音声を止める
+func (s *Speaker) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+// command OSごとの読み上げコマンドと引数（標準入力の文章を読む。使えるコマンドがなければok=false）
+func command(goos string, lookPath func(string) (string, error), lang Language, speed Speed) (name string, args []string, ok bool) {
+	found := func(name string) bool {
+		_, err := lookPath(name)
+		return err == nil
+	}
+
+	switch goos {
+	case "darwin":
+		// 日本語の環境でも英語の発音で読むよう、言語ごとに声を指定する
+		voice := "Samantha"
+		if lang == Japanese {
+			voice = "Kyoko"
+		}
+		rate := "175"
+		if speed == Slow {
+			rate = "120"
+		}
+		return "say", []string{"-v", voice, "-r", rate, "-f", "-"}, found("say")
+	case "windows":
+		rate := "0"
+		if speed == Slow {
+			rate = "-4"
+		}
+		script := "Add-Type -AssemblyName System.Speech; " +
+			"$s = New-Object System.Speech.Synthesis.SpeechSynthesizer; " +
+			"$v = $s.GetInstalledVoices() | Where-Object { $_.VoiceInfo.Culture.Name -like '" + string(lang) + "-*' } | Select-Object -First 1; " +
+			"if ($v) { $s.SelectVoice($v.VoiceInfo.Name) }; " +
+			"$s.Rate = " + rate + "; $s.Speak([Console]::In.ReadToEnd())"
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}, found("powershell")
+	default:
+		rate := "150"
+		if speed == Slow {
+			rate = "110"
+		}
+		// 日本語の声はespeak-ngにしかない
+		voice, names := "en-us", []string{"espeak-ng", "espeak"}
+		if lang == Japanese {
+			voice, names = "ja", []string{"espeak-ng"}
+		}
+		for _, name := range names {
+			if found(name) {
+				return name, []string{"-v", voice, "-s", rate, "--stdin"}, true
+			}
+		}
+		return "", nil, false
+	}
+}
+
+// readableReplacer 読み上げで読めない・読み違える記号の読み方
+var readableReplacer = strings.NewReplacer(
+	"**", "", "__", "", "`", "", "#", "",
+	"²", "の2乗", "³", "の3乗", "√", "ルート",
+	"×", "かける", "÷", "わる", "±", "プラスマイナス",
+	"≦", "小なりイコール", "≧", "大なりイコール", "≠", "ノットイコール",
+	"=", "イコール", "＝", "イコール", "π", "パイ", "°", "度",
+)
+
+// Readable 問題文やフィードバックを読み上げ用の文章にする（マークダウンの記号を除き、数式の記号を読み方にする）
+func Readable(text string) string {
+	return strings.Join(strings.Fields(readableReplacer.Replace(text)), " ")
+}
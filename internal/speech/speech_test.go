@@ -0,0 +1,56 @@
+package speech
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	installed := func(names ...string) func(string) (string, error) {
+		return func(file string) (string, error) {
+			if slices.Contains(names, file) {
+				return "/usr/bin/" + file, nil
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	name, args, ok := command("linux", installed("espeak"), English, Slow)
+	if !ok || name != "espeak" || !slices.Contains(args, "--stdin") || !slices.Contains(args, "110") {
+		t.Errorf("espeakだけ入っているLinux = %s %v %v", name, args, ok)
+	}
+	if name, _, _ := command("linux", installed("espeak", "espeak-ng"), English, Normal); name != "espeak-ng" {
+		t.Errorf("espeak-ngを優先するはず: %s", name)
+	}
+	if _, _, ok := command("linux", installed(), English, Normal); ok {
+		t.Error("読み上げ機能がなければ使えないはず")
+	}
+
+	name, args, ok = command("darwin", installed("say"), English, Normal)
+	if !ok || name != "say" || !slices.Contains(args, "Samantha") || args[len(args)-1] != "-" {
+		t.Errorf("macOS = %s %v %v", name, args, ok)
+	}
+	if _, _, ok := command("windows", installed("powershell"), English, Normal); !ok {
+		t.Error("WindowsはPowerShellで読み上げるはず")
+	}
+
+	// 日本語の声はespeak-ngとmacOSのKyokoで読む
+	if _, _, ok := command("linux", installed("espeak"), Japanese, Normal); ok {
+		t.Error("espeakには日本語の声がないはず")
+	}
+	if name, args, ok := command("linux", installed("espeak-ng"), Japanese, Normal); !ok || name != "espeak-ng" || !slices.Contains(args, "ja") {
+		t.Errorf("espeak-ngの日本語 = %s %v %v", name, args, ok)
+	}
+	if _, args, _ := command("darwin", installed("say"), Japanese, Normal); !slices.Contains(args, "Kyoko") {
+		t.Errorf("macOSの日本語 = %v", args)
+	}
+}
+
+func TestReadable(t *testing.T) {
+	got := Readable("**問題:** x² + 3 = 12 のとき、x ≧ 0 なら\n√9 × 2 は？")
+	want := "問題: xの2乗 + 3 イコール 12 のとき、x 大なりイコール 0 なら ルート9 かける 2 は？"
+	if got != want {
+		t.Errorf("Readable = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,264 @@
+package testutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+)
+
+// 記録モード（STUDYBUDDY_RECORD_AI=1）では本物のOllamaに問い合わせ、応答をカセットに保存する
+const (
+	recordEnv    = "STUDYBUDDY_RECORD_AI"
+	ollamaURLEnv = "OLLAMA_URL"         // 記録に使うOllamaのURL（空なら既定のURL）
+	fakeAIURL    = "http://ollama.test" // テスト用エンジンの接続先（実際には通信しない）
+	cassetteDir  = "testdata/cassettes" // テストのパッケージからの相対パス
+	generatePath = "/api/generate"
+)
+
+// Interaction 記録したAIへの問い合わせと応答
+type Interaction struct {
+	Method         string `json:"method"`
+	Path           string `json:"path"`
+	Prompt         string `json:"prompt,omitempty"`          // 記録したときのプロンプト（確認用。照合には使わない）
+	PromptContains string `json:"prompt_contains,omitempty"` // プロンプトに含まれるべき文字列（空なら照合しない）
+	Response       string `json:"response,omitempty"`        // /api/generate の生成結果（ストリームをつなげたもの）
+	Body           string `json:"body,omitempty"`            // それ以外のAPIのレスポンス本文
+	Status         int    `json:"status,omitempty"`          // 0なら200
+}
+
+// Cassette 記録した応答を返すトランスポート（Engine.SetTransportに渡す）
+type Cassette struct {
+	t            testing.TB
+	path         string
+	recording    bool
+	upstream     string
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// LoadCassette testdata/cassettes/<name>.json の応答を返すカセットを読み込む
+func LoadCassette(t testing.TB, name string) *Cassette {
+	t.Helper()
+
+	c := &Cassette{
+		t:         t,
+		path:      filepath.Join(cassetteDir, name+".json"),
+		recording: os.Getenv(recordEnv) == "1",
+	}
+	if c.recording {
+		c.upstream = strings.TrimRight(os.Getenv(ollamaURLEnv), "/")
+		if c.upstream == "" {
+			c.upstream = config.Default().AI.OllamaURL
+		}
+		t.Cleanup(c.save)
+		return c
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		t.Fatalf("カセット読み込みエラー（%s=1 で記録できます）: %v", recordEnv, err)
+	}
+	if err := json.Unmarshal(data, &c.interactions); err != nil {
+		t.Fatalf("カセット解析エラー: %s: %v", c.path, err)
+	}
+	c.used = make([]bool, len(c.interactions))
+	return c
+}
+
+// NewCassette 記録ファイルを使わず、与えた応答を返すカセットを作成
+func NewCassette(t testing.TB, interactions ...Interaction) *Cassette {
+	return &Cassette{t: t, interactions: interactions, used: make([]bool, len(interactions))}
+}
+
+// RoundTrip まだ使っていない応答のうち、メソッド・パス・プロンプトが合う最初のものを返す
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("リクエスト読み取りエラー: %w", err)
+		}
+	}
+	prompt := requestPrompt(body)
+
+	if c.recording {
+		return c.record(req, body, prompt)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, interaction := range c.interactions {
+		if c.used[i] || !interaction.matches(req.Method, req.URL.Path, prompt) {
+			continue
+		}
+		c.used[i] = true
+		return interaction.response(req), nil
+	}
+
+	// 記録にない問い合わせはテストの失敗とし、エンジンにはオフライン時の処理をさせる
+	c.t.Errorf("カセットに記録のない問い合わせ: %s %s %q", req.Method, req.URL.Path, truncate(prompt, 80))
+	return nil, fmt.Errorf("カセットに記録のない問い合わせ: %s %s", req.Method, req.URL.Path)
+}
+
+// Unused まだ使われていない応答の数
+func (c *Cassette) Unused() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	unused := 0
+	for _, used := range c.used {
+		if !used {
+			unused++
+		}
+	}
+	return unused
+}
+
+// matches 問い合わせがこの記録に合うか
+func (i Interaction) matches(method, path, prompt string) bool {
+	if !strings.EqualFold(i.Method, method) || i.Path != path {
+		return false
+	}
+	return i.PromptContains == "" || strings.Contains(prompt, i.PromptContains)
+}
+
+// response 記録からHTTPレスポンスを作成（生成結果はOllamaと同じNDJSON形式にする）
+func (i Interaction) response(req *http.Request) *http.Response {
+	status := i.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	body := i.Body
+	if i.Path == generatePath && status == http.StatusOK && body == "" {
+		line, _ := json.Marshal(ai.OllamaResponse{Response: i.Response, Done: true})
+		body = string(line) + "\n"
+	}
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+// record 本物のOllamaに問い合わせ、応答を記録してそのまま返す
+func (c *Cassette) record(req *http.Request, body []byte, prompt string) (*http.Response, error) {
+	target, err := url.Parse(c.upstream + req.URL.RequestURI())
+	if err != nil {
+		return nil, fmt.Errorf("記録先URLエラー: %w", err)
+	}
+	forward := req.Clone(req.Context())
+	forward.URL = target
+	forward.Host = target.Host
+	forward.Body = io.NopCloser(bytes.NewReader(body))
+	forward.ContentLength = int64(len(body))
+
+	resp, err := http.DefaultTransport.RoundTrip(forward)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("レスポンス読み取りエラー: %w", err)
+	}
+
+	interaction := Interaction{Method: req.Method, Path: req.URL.Path, Prompt: prompt}
+	if resp.StatusCode != http.StatusOK {
+		interaction.Status = resp.StatusCode
+		interaction.Body = string(respBody)
+	} else if req.URL.Path == generatePath {
+		interaction.Response = joinStream(respBody)
+	} else {
+		interaction.Body = string(respBody)
+	}
+	c.mu.Lock()
+	c.interactions = append(c.interactions, interaction)
+	c.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// save 記録した応答をカセットに保存
+func (c *Cassette) save() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		c.t.Errorf("カセット作成エラー: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		c.t.Errorf("カセット保存エラー: %v", err)
+		return
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0644); err != nil {
+		c.t.Errorf("カセット保存エラー: %v", err)
+	}
+}
+
+// NewEngine カセットの応答を返すAIエンジンを作成（既定の設定。クラウドAIは使わない）
+func NewEngine(t testing.TB, cassette *Cassette) *ai.Engine {
+	t.Helper()
+
+	cfg := config.Default().AI
+	cfg.OllamaURL = fakeAIURL
+	cfg.Cloud.Provider = config.CloudProviderNone
+	engine, err := ai.NewEngine(cfg)
+	if err != nil {
+		t.Fatalf("テスト用AIエンジン作成エラー: %v", err)
+	}
+	engine.SetTransport(cassette)
+	return engine
+}
+
+// requestPrompt リクエスト本文のプロンプト（/api/generate 以外は空）
+func requestPrompt(body []byte) string {
+	var request struct {
+		Prompt string `json:"prompt"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return ""
+	}
+	return request.Prompt
+}
+
+// joinStream NDJSON形式の生成結果をつなげる
+func joinStream(body []byte) string {
+	var text strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var line ai.OllamaResponse
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		text.WriteString(line.Response)
+	}
+	return text.String()
+}
+
+// truncate 長い文字列を先頭だけにする
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
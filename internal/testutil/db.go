@@ -0,0 +1,81 @@
+package testutil
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// PostgresURLEnv 設定すると、テスト用データベースをこのPostgreSQLのサーバーに作る（-tags postgres でテストする）
+const PostgresURLEnv = "STUDYBUDDY_TEST_POSTGRES_URL"
+
+// dbCount テストごとに別のメモリ上のデータベースにするための通し番号
+var dbCount atomic.Int64
+
+// NewDB スキーマを作成したメモリ上のSQLiteデータベースを作成（テストが終わると閉じる）
+// STUDYBUDDY_TEST_POSTGRES_URLを設定したときは、PostgreSQLのテストごとのスキーマに作成する
+func NewDB(t testing.TB) *database.DB {
+	t.Helper()
+
+	if serverURL := os.Getenv(PostgresURLEnv); serverURL != "" {
+		return newPostgresDB(t, serverURL)
+	}
+
+	// 接続ごとに別のデータベースにならないよう、名前付きの共有キャッシュにする
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	dsn := fmt.Sprintf("file:%s_%d?mode=memory&cache=shared", name, dbCount.Add(1))
+
+	db, err := database.Initialize(dsn)
+	if err != nil {
+		t.Fatalf("テスト用データベース作成エラー: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// newPostgresDB PostgreSQLのサーバーにテストごとのスキーマを作り、そこにスキーマを作成したデータベースを作成
+// （テストが終わるとスキーマごと削除する）
+func newPostgresDB(t testing.TB, serverURL string) *database.DB {
+	t.Helper()
+
+	if !slices.Contains(sql.Drivers(), "pgx") {
+		t.Fatalf("%sを使うテストは -tags postgres で実行してください", PostgresURLEnv)
+	}
+	admin, err := sql.Open("pgx", serverURL)
+	if err != nil {
+		t.Fatalf("テスト用サーバー接続エラー: %v", err)
+	}
+	schema := fmt.Sprintf("studybuddy_test_%d_%d", time.Now().UnixNano(), dbCount.Add(1))
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		_ = admin.Close()
+		t.Fatalf("テスト用スキーマ作成エラー: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		_ = admin.Close()
+	})
+
+	// 接続ごとにテスト用のスキーマを使う
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatalf("%sの解析エラー: %v", PostgresURLEnv, err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+
+	db, err := database.Open(database.DriverPostgres, u.String())
+	if err != nil {
+		t.Fatalf("テスト用データベース作成エラー: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
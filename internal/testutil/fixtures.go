@@ -0,0 +1,144 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// Fixture テスト用データベースに登録する生徒と学習の記録
+type Fixture struct {
+	User     database.User
+	Sessions []SessionFixture
+}
+
+// SessionFixture 1回の学習セッション（解答はStartから順に、それぞれの解答時間をあけて記録する）
+type SessionFixture struct {
+	Subject     string
+	Start       time.Time
+	Minutes     int    // 0なら最後の解答の時刻で終わる
+	SessionType string // 空ならアプリでの学習
+	Note        string
+	Results     []ResultFixture
+}
+
+// ResultFixture 1問の解答
+type ResultFixture struct {
+	ProblemType string
+	Difficulty  int
+	Correct     bool
+	TimeTaken   int // 秒（0なら60秒）
+}
+
+// DefaultFixture 中学2年生が直近3日間に数学と英語を学習した記録（nowの前日から順に）
+func DefaultFixture(now time.Time) Fixture {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return Fixture{
+		User: database.User{ID: "user-test", Name: "テスト太郎", Grade: 2, CreatedAt: day.AddDate(0, -1, 0)},
+		Sessions: []SessionFixture{
+			{
+				Subject: "数学",
+				Start:   day.AddDate(0, 0, -3).Add(17 * time.Hour),
+				Minutes: 20,
+				Results: []ResultFixture{
+					{ProblemType: "一次関数", Difficulty: 2, Correct: true},
+					{ProblemType: "一次関数", Difficulty: 2, Correct: false},
+					{ProblemType: "連立方程式", Difficulty: 3, Correct: true},
+				},
+			},
+			{
+				Subject: "英語",
+				Start:   day.AddDate(0, 0, -2).Add(19 * time.Hour),
+				Minutes: 15,
+				Results: []ResultFixture{
+					{ProblemType: "過去進行形", Difficulty: 2, Correct: true},
+					{ProblemType: "不定詞", Difficulty: 3, Correct: false},
+				},
+			},
+			{
+				Subject:     "数学",
+				Start:       day.AddDate(0, 0, -1).Add(18 * time.Hour),
+				Minutes:     45,
+				SessionType: database.SessionTypeManual,
+				Note:        "塾で連立方程式のプリント",
+			},
+		},
+	}
+}
+
+// Seed フィクスチャをデータベースに登録し、登録した生徒を返す
+func Seed(t testing.TB, db *database.DB, fixture Fixture) *database.User {
+	t.Helper()
+
+	user := fixture.User
+	if user.ID == "" {
+		user.ID = "user-test"
+	}
+	if user.Grade == 0 {
+		user.Grade = 1
+	}
+	if err := db.CreateUser(&user); err != nil {
+		t.Fatalf("テスト用ユーザー作成エラー: %v", err)
+	}
+
+	for i, session := range fixture.Sessions {
+		SeedSession(t, db, user.ID, fmt.Sprintf("%s-session-%d", user.ID, i+1), session)
+	}
+	return &user
+}
+
+// SeedSession 学習セッションと解答をデータベースに登録
+func SeedSession(t testing.TB, db *database.DB, userID, sessionID string, fixture SessionFixture) *database.StudySession {
+	t.Helper()
+
+	session := &database.StudySession{
+		ID:          sessionID,
+		UserID:      userID,
+		Subject:     fixture.Subject,
+		StartTime:   fixture.Start,
+		SessionType: fixture.SessionType,
+		Note:        fixture.Note,
+		CreatedAt:   fixture.Start,
+	}
+	if err := db.CreateStudySession(session); err != nil {
+		t.Fatalf("テスト用セッション作成エラー: %v", err)
+	}
+
+	answeredAt := fixture.Start
+	for i, fixtureResult := range fixture.Results {
+		timeTaken := fixtureResult.TimeTaken
+		if timeTaken == 0 {
+			timeTaken = 60
+		}
+		answeredAt = answeredAt.Add(time.Duration(timeTaken) * time.Second)
+
+		result := &database.ProblemResult{
+			ID:          fmt.Sprintf("%s-result-%d", sessionID, i+1),
+			SessionID:   sessionID,
+			ProblemType: fixtureResult.ProblemType,
+			Difficulty:  fixtureResult.Difficulty,
+			IsCorrect:   fixtureResult.Correct,
+			TimeTaken:   timeTaken,
+			CreatedAt:   answeredAt,
+		}
+		if err := db.CreateProblemResult(result); err != nil {
+			t.Fatalf("テスト用解答作成エラー: %v", err)
+		}
+		session.TotalProblems++
+		if result.IsCorrect {
+			session.CorrectAnswers++
+		}
+	}
+
+	end := answeredAt
+	if fixture.Minutes > 0 {
+		end = fixture.Start.Add(time.Duration(fixture.Minutes) * time.Minute)
+	}
+	session.EndTime = &end
+	if err := db.UpdateStudySession(session); err != nil {
+		t.Fatalf("テスト用セッション更新エラー: %v", err)
+	}
+	return session
+}
@@ -0,0 +1,113 @@
+package testutil
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 生成する学習履歴の形（1セッション25問・1日3セッション）
+const (
+	historyResultsPerSession = 25
+	historySessionsPerDay    = 3
+)
+
+// historyTopics 科目ごとの単元（生成する解答に順に割り当てる）
+var historyTopics = map[string][]string{
+	"数学": {"正負の数", "文字と式", "一次方程式", "比例と反比例", "一次関数", "連立方程式"},
+	"英語": {"be動詞", "一般動詞", "過去形", "過去進行形", "不定詞", "比較"},
+	"国語": {"漢字", "文法", "古文", "説明文", "小説"},
+	"理科": {"植物のつくり", "物質の性質", "電流", "化学変化", "天気"},
+	"社会": {"世界の地理", "日本の地理", "古代", "中世", "近世"},
+}
+
+// historySubjects 生成する科目の順
+var historySubjects = []string{"数学", "英語", "国語", "理科", "社会"}
+
+// GenerateHistory ベンチマーク用に、nowの前日までさかのぼってresults問分の学習履歴を登録する
+// （セッション・解答・単元別の統計・経験値・科目別の進捗。乱数は固定なので毎回同じデータになる。解答はセッションごとにまとめて書き込む）
+func GenerateHistory(tb testing.TB, db *database.DB, userID string, results int, now time.Time) {
+	tb.Helper()
+
+	rng := rand.New(rand.NewSource(1))
+	sessions := (results + historyResultsPerSession - 1) / historyResultsPerSession
+	days := (sessions + historySessionsPerDay - 1) / historySessionsPerDay
+	firstDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -days)
+
+	progress := make(map[string]*database.LearningProgress)
+	remaining := results
+	for i := 0; i < sessions; i++ {
+		subject := historySubjects[i%len(historySubjects)]
+		start := firstDay.AddDate(0, 0, i/historySessionsPerDay).Add(time.Duration(16+2*(i%historySessionsPerDay)) * time.Hour)
+		count := min(historyResultsPerSession, remaining)
+		remaining -= count
+
+		session := &database.StudySession{
+			ID:        fmt.Sprintf("%s-history-%d", userID, i),
+			UserID:    userID,
+			Subject:   subject,
+			StartTime: start,
+			CreatedAt: start,
+		}
+		if err := db.CreateStudySession(session); err != nil {
+			tb.Fatalf("テスト用セッション作成エラー: %v", err)
+		}
+
+		answeredAt := start
+		topics := historyTopics[subject]
+		records := make([]database.AnswerRecord, 0, count)
+		var events []*database.XPEvent
+		for j := 0; j < count; j++ {
+			timeTaken := 20 + rng.Intn(100)
+			answeredAt = answeredAt.Add(time.Duration(timeTaken) * time.Second)
+			result := &database.ProblemResult{
+				ID:          fmt.Sprintf("%s-r%d", session.ID, j),
+				SessionID:   session.ID,
+				ProblemType: topics[(i/len(historySubjects)+j)%len(topics)],
+				Difficulty:  1 + rng.Intn(5),
+				IsCorrect:   rng.Float64() < 0.7,
+				TimeTaken:   timeTaken,
+				CreatedAt:   answeredAt,
+			}
+			records = append(records, database.AnswerRecord{Result: result, Subject: subject, AnsweredAt: answeredAt})
+			if result.IsCorrect {
+				events = append(events, &database.XPEvent{ID: result.ID, UserID: userID, Source: "answer", Amount: 10, CreatedAt: answeredAt})
+				session.CorrectAnswers++
+			}
+			session.TotalProblems++
+		}
+		if err := db.RecordAnswers(userID, records); err != nil {
+			tb.Fatalf("テスト用解答作成エラー: %v", err)
+		}
+		if err := db.CreateXPEvents(events); err != nil {
+			tb.Fatalf("テスト用経験値作成エラー: %v", err)
+		}
+
+		end := answeredAt
+		session.EndTime = &end
+		if err := db.UpdateStudySession(session); err != nil {
+			tb.Fatalf("テスト用セッション更新エラー: %v", err)
+		}
+
+		p, ok := progress[subject]
+		if !ok {
+			p = &database.LearningProgress{UserID: userID, Subject: subject}
+			progress[subject] = p
+		}
+		p.TotalProblems += session.TotalProblems
+		p.CorrectAnswers += session.CorrectAnswers
+		p.TotalStudyTime += session.DurationSeconds()
+		lastStudy := start
+		p.LastStudyDate = &lastStudy
+		p.UpdatedAt = end
+	}
+
+	for _, p := range progress {
+		if err := db.UpsertLearningProgress(p); err != nil {
+			tb.Fatalf("テスト用学習進捗作成エラー: %v", err)
+		}
+	}
+}
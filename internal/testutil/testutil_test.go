@@ -0,0 +1,82 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSeedDefaultFixture(t *testing.T) {
+	db := NewDB(t)
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	user := Seed(t, db, DefaultFixture(now))
+
+	sessions, err := db.GetStudySessionsBetween(user.ID, now.AddDate(0, 0, -7), now)
+	if err != nil {
+		t.Fatalf("GetStudySessionsBetween: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("セッション数 = %d, want 3", len(sessions))
+	}
+
+	results, err := db.GetProblemResultsBetween(user.ID, now.AddDate(0, 0, -7), now)
+	if err != nil {
+		t.Fatalf("GetProblemResultsBetween: %v", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("解答数 = %d, want 5", len(results))
+	}
+}
+
+func TestNewDBIsolatesTests(t *testing.T) {
+	now := time.Now()
+	first := NewDB(t)
+	Seed(t, first, DefaultFixture(now))
+
+	// 同じテストで作った別のデータベースには、最初のデータベースの記録がない
+	second := NewDB(t)
+	user := Seed(t, second, DefaultFixture(now))
+	results, err := second.GetProblemResultsBetween(user.ID, now.AddDate(0, 0, -7), now)
+	if err != nil {
+		t.Fatalf("GetProblemResultsBetween: %v", err)
+	}
+	if len(results) != 5 {
+		t.Errorf("解答数 = %d, want 5", len(results))
+	}
+}
+
+func TestCassetteReplay(t *testing.T) {
+	cassette := LoadCassette(t, "example")
+	engine := NewEngine(t, cassette)
+	ctx := context.Background()
+
+	models, err := engine.ListModels(ctx)
+	if err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	if len(models) != 1 || models[0].ParameterSize != "2.6B" {
+		t.Errorf("models = %+v", models)
+	}
+	if err := engine.TestModel(ctx); err != nil {
+		t.Errorf("TestModel: %v", err)
+	}
+	if unused := cassette.Unused(); unused != 0 {
+		t.Errorf("使われていない応答 = %d", unused)
+	}
+}
+
+func TestCassetteMatchesPrompt(t *testing.T) {
+	cassette := NewCassette(t,
+		Interaction{Method: "POST", Path: "/api/generate", PromptContains: "英語", Response: "英語の応答です"},
+		Interaction{Method: "POST", Path: "/api/generate", PromptContains: "こんにちは", Response: "こんにちは！"},
+	)
+	engine := NewEngine(t, cassette)
+
+	// プロンプトに合う2番目の応答が使われ、1番目は残る
+	if err := engine.TestModel(context.Background()); err != nil {
+		t.Fatalf("TestModel: %v", err)
+	}
+	if unused := cassette.Unused(); unused != 1 {
+		t.Errorf("使われていない応答 = %d, want 1", unused)
+	}
+}
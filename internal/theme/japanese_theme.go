@@ -7,24 +7,101 @@ import (
 	"fyne.io/fyne/v2/theme"
 )
 
+// テーマの種類（config.UIConfig.Themeに保存）
+const (
+	VariantSystem       = "system"        // OSの設定に合わせる
+	VariantLight        = "light"         // ライト
+	VariantDark         = "dark"          // ダーク
+	VariantHighContrast = "high_contrast" // ハイコントラスト（黒背景・白文字）
+)
+
+// Variants 選択できるテーマの種類
+var Variants = []string{VariantSystem, VariantLight, VariantDark, VariantHighContrast}
+
+// VariantLabels テーマの表示名
+var VariantLabels = map[string]string{
+	VariantSystem:       "システムに合わせる",
+	VariantLight:        "ライト",
+	VariantDark:         "ダーク",
+	VariantHighContrast: "ハイコントラスト",
+}
+
+// highContrastColors ハイコントラストテーマの配色（未定義の色はダークテーマを使用）
+var highContrastColors = map[fyne.ThemeColorName]color.Color{
+	theme.ColorNameBackground:          color.Black,
+	theme.ColorNameForeground:          color.White,
+	theme.ColorNamePrimary:             color.NRGBA{R: 0xff, G: 0xd6, B: 0x00, A: 0xff},
+	theme.ColorNameFocus:               color.NRGBA{R: 0xff, G: 0xd6, B: 0x00, A: 0xff},
+	theme.ColorNameHover:               color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x33},
+	theme.ColorNameButton:              color.NRGBA{R: 0x1a, G: 0x1a, B: 0x1a, A: 0xff},
+	theme.ColorNameInputBackground:     color.NRGBA{R: 0x10, G: 0x10, B: 0x10, A: 0xff},
+	theme.ColorNameInputBorder:         color.White,
+	theme.ColorNameOverlayBackground:   color.Black,
+	theme.ColorNameMenuBackground:      color.Black,
+	theme.ColorNameHeaderBackground:    color.Black,
+	theme.ColorNamePlaceHolder:         color.NRGBA{R: 0xcc, G: 0xcc, B: 0xcc, A: 0xff},
+	theme.ColorNameDisabled:            color.NRGBA{R: 0x99, G: 0x99, B: 0x99, A: 0xff},
+	theme.ColorNameSeparator:           color.White,
+	theme.ColorNameSelection:           color.NRGBA{R: 0xff, G: 0xd6, B: 0x00, A: 0x66},
+	theme.ColorNameSuccess:             color.NRGBA{R: 0x00, G: 0xff, B: 0x7f, A: 0xff},
+	theme.ColorNameError:               color.NRGBA{R: 0xff, G: 0x55, B: 0x55, A: 0xff},
+	theme.ColorNameForegroundOnPrimary: color.Black,
+}
+
+// baseTextSize デフォルトテーマの本文サイズ（フォントサイズ設定の基準）
+const baseTextSize = 14
+
+// scaledSizes フォントサイズ設定に合わせて拡大縮小するサイズ
+var scaledSizes = map[fyne.ThemeSizeName]bool{
+	theme.SizeNameText:           true,
+	theme.SizeNameHeadingText:    true,
+	theme.SizeNameSubHeadingText: true,
+	theme.SizeNameCaptionText:    true,
+	theme.SizeNameInlineIcon:     true,
+}
+
 // JapaneseTheme M+フォントを使用した日本語対応テーマ
-type JapaneseTheme struct{}
+type JapaneseTheme struct {
+	variant  string
+	fontSize float32
+}
 
-// NewJapaneseTheme 新しい日本語テーマを作成
+// NewJapaneseTheme 新しい日本語テーマを作成（OSの設定に合わせる）
 func NewJapaneseTheme() fyne.Theme {
-	return &JapaneseTheme{}
+	return &JapaneseTheme{variant: VariantSystem, fontSize: baseTextSize}
 }
 
-// Font フォントリソースを返す
-func (t *JapaneseTheme) Font(style fyne.TextStyle) fyne.Resource {
-	if style.Bold {
-		return resourceMplus1BoldTtf
+// NewJapaneseThemeWithVariant 種類を指定して日本語テーマを作成
+func NewJapaneseThemeWithVariant(variant string) fyne.Theme {
+	return &JapaneseTheme{variant: variant, fontSize: baseTextSize}
+}
+
+// NewJapaneseThemeWithSettings 種類とフォントサイズ（ポイント）を指定して日本語テーマを作成
+func NewJapaneseThemeWithSettings(variant string, fontSize int) fyne.Theme {
+	if fontSize <= 0 {
+		fontSize = baseTextSize
 	}
-	return resourceMplus1RegularTtf
+	return &JapaneseTheme{variant: variant, fontSize: float32(fontSize)}
+}
+
+// Font フォントリソースを返す（FYNE_FONTで設定したM+フォントをデフォルトテーマ経由で使用）
+func (t *JapaneseTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return theme.DefaultTheme().Font(style)
 }
 
-// Color 色を返す（デフォルトテーマを使用）
+// Color 色を返す（テーマの種類に応じてライト・ダーク・ハイコントラストを切り替え）
 func (t *JapaneseTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	switch t.variant {
+	case VariantLight:
+		variant = theme.VariantLight
+	case VariantDark:
+		variant = theme.VariantDark
+	case VariantHighContrast:
+		if c, exists := highContrastColors[name]; exists {
+			return c
+		}
+		variant = theme.VariantDark
+	}
 	return theme.DefaultTheme().Color(name, variant)
 }
 
@@ -33,7 +110,11 @@ func (t *JapaneseTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
 	return theme.DefaultTheme().Icon(name)
 }
 
-// Size サイズを返す（デフォルトテーマを使用）
+// Size サイズを返す（文字・インラインアイコンはフォントサイズ設定に合わせて拡大縮小）
 func (t *JapaneseTheme) Size(name fyne.ThemeSizeName) float32 {
-	return theme.DefaultTheme().Size(name)
-}
\ No newline at end of file
+	size := theme.DefaultTheme().Size(name)
+	if scaledSizes[name] {
+		return size * t.fontSize / baseTextSize
+	}
+	return size
+}
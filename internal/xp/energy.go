@@ -0,0 +1,123 @@
+package xp
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// 元気のルール（一度に詰め込むより、休憩をはさんで分けて学習するほうが経験値がたまる）
+//   - 解答の間隔が5分未満なら学習を続けているとみなし、連続学習時間に加える
+//   - 5〜15分の間隔は短い休憩とし、休んだ時間の3倍だけ連続学習時間を減らす
+//   - 15分以上休むと連続学習時間は0に戻る（元気が満タン）
+//   - 連続学習時間が60分をこえると解答の経験値が75%、90分で50%、120分で25%になる
+const (
+	energyStudyGap     = 5 * time.Minute
+	energyRestGap      = 15 * time.Minute
+	energyRecoveryRate = 3
+	energyWindow       = 8 * time.Hour // 連続学習時間の計算に使う解答の範囲
+)
+
+// energyTiers 連続学習時間に応じた経験値の割合（連続学習時間の短い順）
+var energyTiers = []struct {
+	continuous time.Duration
+	percent    int
+}{
+	{60 * time.Minute, 75},
+	{90 * time.Minute, 50},
+	{120 * time.Minute, 25},
+}
+
+// EnergyRules 元気のルールの説明（画面に表示する）
+const EnergyRules = "長い時間続けて学習すると元気が減り、解答で獲得できる経験値が少なくなります。\n" +
+	"・続けて60分をこえると75%、90分で50%、120分で25%\n" +
+	"・5〜15分の休憩は、休んだ時間の3倍だけ元気が回復します\n" +
+	"・15分以上休むと元気が満タンに戻ります\n" +
+	"一度にまとめて学習するより、休憩をはさんで何回かに分けるほうが記憶に残りやすいためです。"
+
+// Energy 元気の状態（長時間の連続学習で経験値が減るしくみ）
+type Energy struct {
+	Continuous time.Duration // 休憩を除いた連続学習時間
+	Percent    int           // 解答の経験値の割合（%）
+	NextDrop   time.Duration // 次に割合が下がるまでの学習時間（もう下がらなければ0）
+	RestToFull time.Duration // 元気が満タンに戻るのに必要な休憩の時間（満タンなら0）
+}
+
+// EnergyPercent 連続学習時間に応じた経験値の割合（%）
+func EnergyPercent(continuous time.Duration) int {
+	percent := 100
+	for _, tier := range energyTiers {
+		if continuous >= tier.continuous {
+			percent = tier.percent
+		}
+	}
+	return percent
+}
+
+// EnergyAt 解答した時刻（古い順）から、nowの時点の元気を計算
+func EnergyAt(answeredAt []time.Time, now time.Time) Energy {
+	var continuous time.Duration
+	addGap := func(gap time.Duration) {
+		switch {
+		case gap >= energyRestGap:
+			continuous = 0
+		case gap >= energyStudyGap:
+			continuous = max(continuous-gap*energyRecoveryRate, 0)
+		case gap > 0:
+			continuous += gap
+		}
+	}
+	for i := 1; i < len(answeredAt); i++ {
+		addGap(answeredAt[i].Sub(answeredAt[i-1]))
+	}
+	var resting time.Duration // 最後の解答から休んでいる時間
+	if len(answeredAt) > 0 {
+		gap := now.Sub(answeredAt[len(answeredAt)-1])
+		addGap(gap)
+		if gap >= energyStudyGap {
+			resting = gap
+		}
+	}
+
+	energy := Energy{Continuous: continuous, Percent: EnergyPercent(continuous)}
+	for _, tier := range energyTiers {
+		if continuous < tier.continuous {
+			energy.NextDrop = tier.continuous - continuous
+			break
+		}
+	}
+	if continuous > 0 {
+		// 休憩中なら、休んだ時間の分だけ短くなる
+		energy.RestToFull = min(max(continuous/energyRecoveryRate, energyStudyGap-resting), energyRestGap-resting)
+	}
+	return energy
+}
+
+// Summary 元気の状態の短い説明
+func (e Energy) Summary() string {
+	if e.Percent == 100 {
+		if e.NextDrop > 0 && e.NextDrop <= 15*time.Minute {
+			return fmt.Sprintf("⚡ 元気 100%%（あと%d分で減り始めます）", ceilMinutes(e.NextDrop))
+		}
+		return "⚡ 元気 100%"
+	}
+	return fmt.Sprintf("🔋 元気 %d%%（あと%d分休むと満タン）", e.Percent, ceilMinutes(e.RestToFull))
+}
+
+// ceilMinutes 分単位に切り上げ
+func ceilMinutes(d time.Duration) int {
+	return int(math.Ceil(d.Minutes()))
+}
+
+// Energy ユーザーの今の元気（直近の解答の記録から計算）
+func (s *Service) Energy(userID string, now time.Time) (Energy, error) {
+	results, err := s.db.GetProblemResultsBetween(userID, now.Add(-energyWindow), now.Add(time.Second))
+	if err != nil {
+		return Energy{}, fmt.Errorf("解答結果取得エラー: %w", err)
+	}
+	answeredAt := make([]time.Time, len(results))
+	for i, result := range results {
+		answeredAt[i] = result.CreatedAt
+	}
+	return EnergyAt(answeredAt, now), nil
+}
@@ -0,0 +1,54 @@
+package xp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+	"github.com/okamyuji/studybuddy-ai/internal/xp"
+)
+
+// answers 30秒おきにcount問続けて解答する
+func answers(count int) []testutil.ResultFixture {
+	results := make([]testutil.ResultFixture, count)
+	for i := range results {
+		results[i] = testutil.ResultFixture{ProblemType: "一次関数", Difficulty: 2, Correct: true, TimeTaken: 30}
+	}
+	return results
+}
+
+func TestServiceEnergy(t *testing.T) {
+	db := testutil.NewDB(t)
+	start := time.Date(2026, 10, 16, 17, 0, 0, 0, time.Local)
+	user := testutil.Seed(t, db, testutil.Fixture{
+		Sessions: []testutil.SessionFixture{
+			// 前日の学習は元気に影響しない
+			{Subject: "数学", Start: start.AddDate(0, 0, -1), Results: answers(200)},
+			// 91分続けて解答した（30秒×183問 = 91分30秒）
+			{Subject: "数学", Start: start, Results: answers(183)},
+		},
+	})
+	service := xp.NewService(db)
+
+	last := start.Add(183 * 30 * time.Second)
+	tests := []struct {
+		name    string
+		now     time.Time
+		percent int
+	}{
+		{"解答の直後", last.Add(time.Minute), 50},
+		{"短い休憩中", last.Add(6 * time.Minute), 75}, // 91分 - 6分×3 = 73分
+		{"長い休憩のあと", last.Add(20 * time.Minute), 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			energy, err := service.Energy(user.ID, tt.now)
+			if err != nil {
+				t.Fatalf("Energy: %v", err)
+			}
+			if energy.Percent != tt.percent {
+				t.Errorf("Percent = %d, want %d (Continuous %v)", energy.Percent, tt.percent, energy.Continuous)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package xp
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// 経験値の獲得元
+const (
+	SourceAnswer   = "answer"   // 問題への解答
+	SourceManual   = "manual"   // アプリ外の学習の記録
+	SourceBackfill = "backfill" // 経験値の導入前の学習記録からの換算
+)
+
+// 経験値の獲得ルール
+//   - 問題に解答: 基本10 + 正解15（不正解でも5）+ 難易度×3
+//   - 解答時のじっくり解いたボーナス5（解答時間30秒〜5分）
+//   - 解答時の長時間学習ボーナス10（セッション10分以上）
+//   - 連続正解中は解答の経験値にコンボ倍率をかける（3連続×1.2、5連続×1.5、10連続×2.0）
+//   - 元気のしくみを使う場合は、長時間の連続学習で解答の経験値を減らす（energy.go）
+//   - アプリ外の学習の記録: 1分につき1（1回の記録で上限60）
+//   - レベルnからn+1に上がるには 100 + (n-1)×50 の経験値が必要
+//
+// ユーザーの経験値は獲得履歴（xp_events）の合計。ペットも同じ獲得量・レベル曲線で成長する。
+const (
+	answerBase          = 10
+	correctBonus        = 15
+	incorrectBonus      = 5
+	difficultyBonus     = 3
+	paceBonus           = 5
+	longSessionBonus    = 10
+	longSessionSeconds  = 600
+	minPaceSeconds      = 30
+	maxPaceSeconds      = 300
+	maxManualStudyXP    = 60
+	firstLevelRequired  = 100
+	levelRequiredGrowth = 50
+)
+
+// comboTiers コンボ倍率の段階（連続正解数の少ない順）
+var comboTiers = []struct {
+	streak     int
+	multiplier float64
+}{
+	{3, 1.2},
+	{5, 1.5},
+	{10, 2.0},
+}
+
+// ComboMultiplier 連続正解数に応じた経験値の倍率
+func ComboMultiplier(consecutiveCorrect int) float64 {
+	multiplier := 1.0
+	for _, tier := range comboTiers {
+		if consecutiveCorrect >= tier.streak {
+			multiplier = tier.multiplier
+		}
+	}
+	return multiplier
+}
+
+// Answer 経験値の計算に使う解答結果
+type Answer struct {
+	IsCorrect          bool
+	Difficulty         int
+	TimeTaken          int // 秒
+	ConsecutiveCorrect int // この解答を含む連続正解数（不正解なら0）
+	SessionDuration    int // 秒
+	EnergyPercent      int // 元気による経験値の割合（%）。0なら元気のしくみを使わない
+}
+
+// ForAnswer 解答で獲得する経験値を計算
+func ForAnswer(answer Answer) int {
+	amount := answerBase + answer.Difficulty*difficultyBonus
+	if answer.IsCorrect {
+		amount += correctBonus
+	} else {
+		amount += incorrectBonus
+	}
+
+	if answer.TimeTaken > minPaceSeconds && answer.TimeTaken < maxPaceSeconds {
+		amount += paceBonus
+	}
+	if answer.SessionDuration > longSessionSeconds {
+		amount += longSessionBonus
+	}
+
+	if answer.IsCorrect {
+		amount = int(math.Round(float64(amount) * ComboMultiplier(answer.ConsecutiveCorrect)))
+	}
+	if answer.EnergyPercent > 0 {
+		amount = max(int(math.Round(float64(amount*answer.EnergyPercent)/100)), 1)
+	}
+	return amount
+}
+
+// ForManualStudy アプリ外の学習の記録で獲得する経験値を計算
+func ForManualStudy(minutes int) int {
+	return min(max(minutes, 0), maxManualStudyXP)
+}
+
+// RequiredForLevel レベルlevelから次のレベルに上がるのに必要な経験値
+func RequiredForLevel(level int) int {
+	return firstLevelRequired + (max(level, 1)-1)*levelRequiredGrowth
+}
+
+// Progress 累計経験値から見たレベルの状況
+type Progress struct {
+	Total     int `json:"total"`      // 累計経験値
+	Level     int `json:"level"`      // 現在のレベル（1から）
+	IntoLevel int `json:"into_level"` // 現在のレベルで獲得済みの経験値
+	ToNext    int `json:"to_next"`    // 次のレベルまでに必要な残りの経験値
+}
+
+// ProgressFor 累計経験値からレベルを計算
+func ProgressFor(total int) Progress {
+	progress := Progress{Total: total, Level: 1, IntoLevel: max(total, 0)}
+	for progress.IntoLevel >= RequiredForLevel(progress.Level) {
+		progress.IntoLevel -= RequiredForLevel(progress.Level)
+		progress.Level++
+	}
+	progress.ToNext = RequiredForLevel(progress.Level) - progress.IntoLevel
+	return progress
+}
+
+// Award 経験値の獲得結果
+type Award struct {
+	UserID string
+	Source string
+	Amount int
+	Before Progress
+	After  Progress
+}
+
+// LeveledUp この獲得でレベルが上がったかどうか
+func (a *Award) LeveledUp() bool {
+	return a.After.Level > a.Before.Level
+}
+
+// Hook 経験値を獲得したときに呼ばれる処理（実績・ペット・着せ替えなどの連携用）
+type Hook func(award *Award)
+
+// Service 経験値の付与と集計
+type Service struct {
+	db    *database.DB
+	hooks []Hook
+}
+
+// NewService 経験値サービスを作成
+func NewService(db *database.DB) *Service {
+	return &Service{db: db}
+}
+
+// OnAward 経験値を獲得したときの処理を登録
+func (s *Service) OnAward(hook Hook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+// Progress ユーザーの現在のレベルを取得
+func (s *Service) Progress(userID string) (Progress, error) {
+	total, err := s.db.GetTotalXP(userID)
+	if err != nil {
+		return Progress{}, fmt.Errorf("経験値取得エラー: %w", err)
+	}
+	return ProgressFor(total), nil
+}
+
+// Grant 経験値を付与して登録済みの処理に通知
+func (s *Service) Grant(userID, source string, amount int, reason string) (*Award, error) {
+	return s.grantEvents(userID, source, []*database.XPEvent{newEvent(userID, source, amount, reason)})
+}
+
+// grantEvents 経験値の獲得記録をまとめて書き込み、合計を1回だけ通知
+func (s *Service) grantEvents(userID, source string, events []*database.XPEvent) (*Award, error) {
+	before, err := s.Progress(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := 0
+	for _, event := range events {
+		amount += event.Amount
+	}
+	if len(events) == 1 {
+		err = s.db.CreateXPEvent(events[0])
+	} else {
+		err = s.db.CreateXPEvents(events)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("経験値記録エラー: %w", err)
+	}
+
+	award := &Award{
+		UserID: userID,
+		Source: source,
+		Amount: amount,
+		Before: before,
+		After:  ProgressFor(before.Total + amount),
+	}
+	for _, hook := range s.hooks {
+		hook(award)
+	}
+
+	return award, nil
+}
+
+// newEvent 経験値の獲得記録を作成
+func newEvent(userID, source string, amount int, reason string) *database.XPEvent {
+	return &database.XPEvent{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Source:    source,
+		Amount:    amount,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+}
+
+// GrantAnswer 解答の経験値を付与
+func (s *Service) GrantAnswer(userID string, answer Answer) (*Award, error) {
+	return s.Grant(userID, SourceAnswer, ForAnswer(answer), answerReason(answer))
+}
+
+// GrantAnswers 複数の解答の経験値をまとめて付与（模擬テストの採点など。通知は合計で1回）
+func (s *Service) GrantAnswers(userID string, answers []Answer) (*Award, error) {
+	if len(answers) == 0 {
+		return nil, nil
+	}
+	events := make([]*database.XPEvent, 0, len(answers))
+	for _, answer := range answers {
+		events = append(events, newEvent(userID, SourceAnswer, ForAnswer(answer), answerReason(answer)))
+	}
+	return s.grantEvents(userID, SourceAnswer, events)
+}
+
+// answerReason 解答の経験値の獲得理由
+func answerReason(answer Answer) string {
+	reason := "不正解"
+	if answer.IsCorrect {
+		reason = "正解"
+	}
+	if answer.EnergyPercent > 0 && answer.EnergyPercent < 100 {
+		reason += fmt.Sprintf("（元気%d%%）", answer.EnergyPercent)
+	}
+	return reason
+}
+
+// GrantManualStudy アプリ外の学習の経験値を付与
+func (s *Service) GrantManualStudy(userID string, minutes int) (*Award, error) {
+	return s.Grant(userID, SourceManual, ForManualStudy(minutes), fmt.Sprintf("アプリ外の学習 %d分", minutes))
+}
+
+// Backfill 経験値の導入前の学習記録を経験値に換算（獲得記録がまだないユーザーのみ）
+func (s *Service) Backfill(userID string) error {
+	count, err := s.db.CountXPEvents(userID)
+	if err != nil {
+		return fmt.Errorf("経験値取得エラー: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	until := time.Now().AddDate(0, 0, 1)
+	results, err := s.db.GetProblemResultsBetween(userID, time.Time{}, until)
+	if err != nil {
+		return fmt.Errorf("解答結果取得エラー: %w", err)
+	}
+	sessions, err := s.db.GetStudySessionsBetween(userID, time.Time{}, until)
+	if err != nil {
+		return fmt.Errorf("セッション取得エラー: %w", err)
+	}
+
+	total := 0
+	for _, result := range results {
+		total += ForAnswer(Answer{IsCorrect: result.IsCorrect, Difficulty: result.Difficulty, TimeTaken: result.TimeTaken})
+	}
+	for _, session := range sessions {
+		if session.IsManual() {
+			total += ForManualStudy(session.DurationSeconds() / 60)
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	// 換算時は通知しない（レベルアップ演出が大量に出ないように）
+	event := newEvent(userID, SourceBackfill, total, fmt.Sprintf("これまでの学習記録（%d問）", len(results)))
+	if err := s.db.CreateXPEvent(event); err != nil {
+		return fmt.Errorf("経験値記録エラー: %w", err)
+	}
+
+	return nil
+}
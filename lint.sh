@@ -13,6 +13,7 @@ echo "=== Go静的解析実行 ==="
 # go vet
 echo "📋 go vet実行中..."
 go vet ./... || echo "⚠️  go vetで問題が見つかりました"
+go vet -tags postgres ./... || echo "⚠️  go vet（-tags postgres）で問題が見つかりました"
 
 echo ""
 
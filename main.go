@@ -2,24 +2,30 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"runtime"
+	"runtime/pprof"
 	"sync"
 	"syscall"
 	"time"
 
-	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
-	"fyne.io/fyne/v2/container"
-	"fyne.io/fyne/v2/widget"
 
-	"studybuddy-ai/internal/ai"
-	"studybuddy-ai/internal/config"
-	"studybuddy-ai/internal/database"
-	"studybuddy-ai/internal/gui"
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/crash"
+	"github.com/okamyuji/studybuddy-ai/internal/curriculum"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/gui"
+	"github.com/okamyuji/studybuddy-ai/internal/logging"
+	"github.com/okamyuji/studybuddy-ai/internal/pack"
+	"github.com/okamyuji/studybuddy-ai/internal/scenario"
+	apptheme "github.com/okamyuji/studybuddy-ai/internal/theme"
 )
 
 const (
@@ -56,7 +62,7 @@ func (ac *AppContext) AddCleanup(fn func() error) {
 
 // Shutdown アプリケーションを適切に終了
 func (ac *AppContext) Shutdown() {
-	log.Println("🛑 アプリケーション終了プロセス開始...")
+	slog.Info("🛑 アプリケーション終了プロセス開始...")
 
 	// コンテキストをキャンセル
 	ac.cancel()
@@ -70,9 +76,9 @@ func (ac *AppContext) Shutdown() {
 
 	select {
 	case <-done:
-		log.Println("✅ すべてのgoroutineが正常終了")
+		slog.Info("✅ すべてのgoroutineが正常終了")
 	case <-time.After(5 * time.Second):
-		log.Println("⚠️ goroutine終了タイムアウト（強制終了）")
+		slog.Info("⚠️ goroutine終了タイムアウト（強制終了）")
 	}
 
 	// クリーンアップ関数を逆順で実行
@@ -81,25 +87,43 @@ func (ac *AppContext) Shutdown() {
 
 	for i := len(ac.cleanupFns) - 1; i >= 0; i-- {
 		if err := ac.cleanupFns[i](); err != nil {
-			log.Printf("⚠️ クリーンアップエラー: %v", err)
+			slog.Error("⚠️ クリーンアップエラー", "error", err)
 		}
 	}
 
-	log.Println("✅ アプリケーション終了完了")
+	slog.Info("✅ アプリケーション終了完了")
 }
 
 func main() {
+	kiosk := flag.Bool("kiosk", false, "学校の共用パソコン向けの制限モード（プロフィールコードでサインインし、設定・取り込み・データの削除を無効化）")
+	cpuProfile := flag.String("cpuprofile", "", "CPUプロファイルを書き出すファイル（集計や画面の重さを調べるとき）")
+	memProfile := flag.String("memprofile", "", "終了時のメモリプロファイルを書き出すファイル")
+	scenarioPath := flag.String("scenario", "", "画面を開かずに実行するシナリオのファイル・ディレクトリ（学習の流れの回帰テスト）")
+	flag.Parse()
+
+	// シナリオはメモリ上のデータベースで実行し、利用者の学習記録には触れない
+	if *scenarioPath != "" {
+		os.Exit(runScenarios(*scenarioPath))
+	}
+
 	// アプリケーションコンテキスト初期化
 	appCtx := NewAppContext()
 	defer appCtx.Shutdown() // メイン終了時のクリーンアップ保証
 
+	// 画面の処理でパニックしたときも、クラッシュレポートを残してから終了処理を行う
+	crash.Configure(config.GetCrashDir(), AppVersion)
+	defer crash.Recover("メイン画面", nil)
+
+	// プロファイル（go tool pprof で確認する）。終了時に書き出すため最初に登録する
+	startProfiling(appCtx, *cpuProfile, *memProfile)
+
 	// シグナルハンドラー設定（Ctrl+C、強制終了対応）
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		<-sigChan
-		log.Println("🛑 終了シグナル受信")
+		slog.Info("🛑 終了シグナル受信")
 		appCtx.Shutdown()
 		os.Exit(0)
 	}()
@@ -116,53 +140,189 @@ func main() {
 	// 設定読み込み
 	cfg, err := config.Load()
 	if err != nil {
-		log.Printf("設定読み込みエラー: %v", err)
-		// デフォルト設定で続行
+		// デフォルト設定で続行（エラーはログの準備ができてから記録）
 		cfg = config.Default()
 	}
+	cfg.Kiosk = *kiosk
 
-	// データベース初期化
-	db, err := database.Initialize(cfg.DatabasePath)
+	// ログ（~/.studybuddy-ai/logs にJSON形式で出力し、大きくなったら新しいファイルに切り替える）
+	if logErr := logging.Setup(config.GetLogDir(), cfg.Logging.Level); logErr != nil {
+		slog.Error("ログ初期化エラー", "error", logErr)
+	}
 	if err != nil {
-		log.Fatalf("データベース初期化エラー: %v", err)
+		slog.Error("設定読み込みエラー", "error", err)
+	}
+
+	// テーマ適用（ライト・ダーク・ハイコントラスト）
+	myApp.Settings().SetTheme(apptheme.NewJapaneseThemeWithSettings(cfg.ThemeName(), cfg.UI.FontSize))
+
+	// データベース初期化（教室のサーバーで共有するときはPostgreSQL）
+	db, err := database.Open(cfg.DatabaseSource())
+	if err != nil {
+		slog.Error("データベース初期化エラー", "error", err)
+		os.Exit(1)
 	}
 	appCtx.AddCleanup(func() error {
-		log.Println("📊 データベース接続クローズ")
+		slog.Info("📊 データベース接続クローズ")
 		return db.Close()
 	})
 
 	// AIエンジン初期化
 	aiEngine, err := ai.NewEngine(cfg.AI)
 	if err != nil {
-		log.Printf("AI初期化エラー: %v", err)
-		showAISetupDialog(myApp, appCtx)
-		return
+		slog.Error("AI初期化エラー", "error", err)
+		os.Exit(1)
+	}
+	// 学年・科目ごとの学習範囲（保護者・先生が編集した curriculum.json があれば使う）
+	if c, err := curriculum.Load(curriculum.Path()); err != nil {
+		slog.Error("学習範囲読み込みエラー（標準の学習範囲を使います）", "error", err)
+	} else {
+		aiEngine.SetCurriculum(c)
+	}
+	// 内容パックで配られた問題（AIを使えないときに出題する）
+	if problems, err := pack.LoadProblems(pack.ProblemsPath()); err != nil {
+		slog.Error("内容パックの問題の読み込みエラー", "error", err)
+	} else {
+		aiEngine.SetOfflineProblems(problems)
+	}
+	// 内蔵の問題集は、再起動しても出題済みの問題から出題し直さないよう、出題した位置をプロフィールごとに保存
+	aiEngine.SetRotationStore(db)
+	// クラウドAIの月ごとの使用量はデータベースに記録
+	aiEngine.SetUsageStore(db)
+	aiEngine.SetEmbeddingStore(db)
+	// 学習のコツ・同じ問題へのフィードバック・モデル一覧は保存して使い回す（古くなったものは起動時に削除）
+	aiEngine.SetResponseCache(db)
+	if pruned, err := db.PruneCachedResponses(time.Now().Add(-ai.ResponseCacheRetention)); err != nil {
+		slog.Error("AIの応答の整理エラー", "error", err)
+	} else if pruned > 0 {
+		slog.Info("🧹 古いAIの応答を削除しました", "count", pruned)
+	}
+	// 生成のトークン数と時間を記録し、モデルの管理画面でモデルごとの速さを表示
+	aiEngine.SetMetricsStore(db)
+	if _, err := db.PruneAIMetrics(time.Now().Add(-ai.MetricsRetention)); err != nil {
+		slog.Error("AIの計測の整理エラー", "error", err)
+	}
+	// AIとのやりとりの記録は、保護者が決めた日数を過ぎたら削除
+	if pruned, err := db.PruneTutorTranscripts(time.Now().AddDate(0, 0, -cfg.Parent.TranscriptDays)); err != nil {
+		slog.Error("AIとのやりとりの記録の整理エラー", "error", err)
+	} else if pruned > 0 {
+		slog.Info("🧹 古いAIとのやりとりの記録を削除しました", "count", pruned)
 	}
 	appCtx.AddCleanup(func() error {
-		log.Println("🤖 AIエンジンクローズ")
+		slog.Info("🤖 AIエンジンクローズ")
 		return aiEngine.Close()
 	})
 
-	// メインアプリケーション構築
+	// メインアプリケーション構築（初回起動では、はじめての設定から始まる）
 	mainApp := gui.NewMainApp(myApp, db, aiEngine, cfg)
 	appCtx.AddCleanup(func() error {
-		log.Println("🖥️ GUIシステムクローズ")
+		slog.Info("🖥️ GUIシステムクローズ")
 		return mainApp.Close()
 	})
 
-	// 起動確認ダイアログ
-	if cfg.FirstRun {
-		showWelcomeDialog(myApp, mainApp, appCtx)
-	} else {
-		mainApp.Show()
-	}
+	// AIの状態を定期的に確認（画面の表示と、Ollamaに接続できないときのオフライン動作に使う）
+	appCtx.wg.Add(1)
+	go func() {
+		defer appCtx.wg.Done()
+		aiEngine.MonitorHealth(appCtx.ctx, ai.HealthCheckInterval)
+	}()
+
+	// 設定した時刻に学習リマインドを通知
+	appCtx.wg.Add(1)
+	go func() {
+		defer appCtx.wg.Done()
+		mainApp.RunReminders(appCtx.ctx)
+	}()
+
+	mainApp.Show()
 
 	// アプリケーション実行
-	log.Println("🚀 StudyBuddy AI 起動完了")
+	slog.Info("🚀 StudyBuddy AI 起動完了")
 	myApp.Run()
 
 	// Run()終了後はdefer appCtx.Shutdown()が自動実行される
-	log.Println("🏁 メインループ終了")
+	slog.Info("🏁 メインループ終了")
+}
+
+// runScenarios シナリオを順に実行して結果を表示し、終了コードを返す（1つでも期待と違えば1）
+func runScenarios(path string) int {
+	scenarios, err := scenario.Load(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	db, err := database.Initialize("file:scenario?mode=memory&cache=shared")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer func() { _ = db.Close() }()
+
+	engine, err := scenario.NewOfflineEngine(config.Default().AI)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer func() { _ = engine.Close() }()
+
+	runner := scenario.NewRunner(db, engine)
+	failed := 0
+	for _, sc := range scenarios {
+		outcome, err := runner.Run(context.Background(), sc)
+		if err != nil {
+			failed++
+			fmt.Printf("❌ %s: %v\n", sc.Name, err)
+			continue
+		}
+		failures := sc.Expect.Check(outcome)
+		if len(failures) > 0 {
+			failed++
+			fmt.Printf("❌ %s\n", sc.Name)
+			for _, failure := range failures {
+				fmt.Printf("    %s\n", failure)
+			}
+			continue
+		}
+		fmt.Printf("✅ %s（連続%d日・レベル%d・%d問）\n", sc.Name, outcome.CurrentStreak, outcome.Level, outcome.TotalProblems)
+	}
+
+	fmt.Printf("%d件中 %d件成功\n", len(scenarios), len(scenarios)-failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// startProfiling CPUプロファイルの記録を始め、終了時にCPU・メモリのプロファイルを書き出す
+func startProfiling(appCtx *AppContext, cpuPath, memPath string) {
+	if cpuPath != "" {
+		f, err := os.Create(cpuPath)
+		if err != nil {
+			slog.Error("CPUプロファイル作成エラー", "error", err)
+		} else if err := pprof.StartCPUProfile(f); err != nil {
+			slog.Error("CPUプロファイル開始エラー", "error", err)
+			_ = f.Close()
+		} else {
+			slog.Info("CPUプロファイルを記録します", "path", cpuPath)
+			appCtx.AddCleanup(func() error {
+				pprof.StopCPUProfile()
+				return f.Close()
+			})
+		}
+	}
+
+	if memPath != "" {
+		appCtx.AddCleanup(func() error {
+			f, err := os.Create(memPath)
+			if err != nil {
+				return fmt.Errorf("メモリプロファイル作成エラー: %w", err)
+			}
+			defer func() { _ = f.Close() }()
+			runtime.GC() // 使い終わったメモリを除いて記録する
+			return pprof.WriteHeapProfile(f)
+		})
+	}
 }
 
 // setupJapaneseFonts 日本語フォント設定（ビルド後も動作する）
@@ -170,7 +330,7 @@ func setupJapaneseFonts() {
 	// 実行ファイルのディレクトリを取得
 	execPath, err := os.Executable()
 	if err != nil {
-		log.Printf("実行ファイルパス取得エラー: %v", err)
+		slog.Error("実行ファイルパス取得エラー", "error", err)
 		return
 	}
 	execDir := filepath.Dir(execPath)
@@ -186,115 +346,13 @@ func setupJapaneseFonts() {
 	for _, fontPath := range fontPaths {
 		if _, err := os.Stat(fontPath); err == nil {
 			if err := os.Setenv("FYNE_FONT", fontPath); err != nil {
-				log.Printf("フォント環境変数設定エラー: %v", err)
+				slog.Error("フォント環境変数設定エラー", "error", err)
 				continue
 			}
-			log.Printf("日本語フォント設定: %s", fontPath)
+			slog.Info("日本語フォント設定", "path", fontPath)
 			return
 		}
 	}
 
-	log.Printf("警告: 日本語フォントファイルが見つかりません。デフォルトフォントを使用します。")
-}
-
-// AI設定ダイアログ
-func showAISetupDialog(app fyne.App, appCtx *AppContext) {
-	w := app.NewWindow("AI設定 - StudyBuddy AI")
-	w.Resize(fyne.NewSize(500, 300))
-	w.CenterOnScreen()
-
-	content := container.NewVBox(
-		widget.NewCard("AI設定が必要です", "",
-			container.NewVBox(
-				widget.NewLabel("StudyBuddy AIを使用するには、ローカルAI (Ollama) の設定が必要です。"),
-				widget.NewSeparator(),
-				widget.NewRichTextFromMarkdown(`
-**必要な手順:**
-
-1. **Ollama をインストール**
-   - https://ollama.ai からダウンロード
-   - インストール後、ターミナルでOllamaを起動
-
-2. **日本語対応モデルをダウンロード**
-   `+"```bash"+`
-   ollama pull dsasai/llama3-elyza-jp-8b:latest
-   # または
-   ollama pull 7shi/ezo-gemma-2-jpn:2b-instruct-q8_0
-   `+"```"+`
-
-3. **StudyBuddy AI を再起動**
-
-設定完了後、このアプリケーションを再起動してください。
-				`),
-			),
-		),
-		widget.NewButton("設定方法を確認しました", func() {
-			log.Println("🛑 AI設定ダイアログから終了")
-			appCtx.Shutdown()
-			app.Quit()
-		}),
-	)
-
-	w.SetContent(content)
-	w.Show()
-}
-
-// ウェルカムダイアログ
-func showWelcomeDialog(app fyne.App, mainApp *gui.MainApp, appCtx *AppContext) {
-	w := app.NewWindow("ようこそ StudyBuddy AI へ！")
-	w.Resize(fyne.NewSize(600, 400))
-	w.CenterOnScreen()
-
-	content := container.NewVBox(
-		widget.NewCard("🎓 StudyBuddy AI へようこそ！", "",
-			container.NewVBox(
-				widget.NewRichTextFromMarkdown(`
-# あなた専用のAI学習コンパニオン
-
-StudyBuddy AIは、中学生の学習をサポートする革新的なアプリです。
-
-## ✨ 主な機能
-
-- **🤖 AIチューター**: あなたの理解度に合わせた個別指導
-- **📊 学習分析**: リアルタイムで学習進捗を追跡
-- **🎯 カスタム問題**: 弱点を克服する専用練習問題
-- **🔒 プライバシー保護**: すべてのデータは端末内で安全に管理
-
-## 🚀 はじめましょう
-
-最初に、あなたの学習プロファイルを設定します。
-どの学年ですか？
-				`),
-			),
-		),
-		widget.NewButton("中学1年生", func() {
-			startApp(w, mainApp, 1, appCtx)
-		}),
-		widget.NewButton("中学2年生", func() {
-			startApp(w, mainApp, 2, appCtx)
-		}),
-		widget.NewButton("中学3年生", func() {
-			startApp(w, mainApp, 3, appCtx)
-		}),
-	)
-
-	w.SetContent(content)
-	w.Show()
-}
-
-func startApp(welcomeWindow fyne.Window, mainApp *gui.MainApp, grade int, _ *AppContext) {
-	// 初期設定を保存
-	cfg := config.Default()
-	cfg.FirstRun = false
-	cfg.UserGrade = grade
-
-	if err := config.Save(cfg); err != nil {
-		log.Printf("設定保存エラー: %v", err)
-	}
-
-	welcomeWindow.Close()
-	mainApp.Show()
-
-	// 初回セットアップ完了メッセージ
-	fmt.Printf("StudyBuddy AI 初期化完了 - 中学%d年生\n", grade)
+	slog.Warn("日本語フォントファイルが見つかりません。デフォルトフォントを使用します")
 }
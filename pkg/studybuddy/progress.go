@@ -0,0 +1,133 @@
+package studybuddy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+)
+
+// Overview 学習全体の進み具合
+type Overview struct {
+	TotalStudySeconds int               `json:"total_study_seconds"` // アプリ外の学習の記録を含む
+	TotalProblems     int               `json:"total_problems"`
+	TotalCorrect      int               `json:"total_correct"`
+	AccuracyRate      float64           `json:"accuracy_rate"` // 0-1
+	StudyDays         int               `json:"study_days"`
+	CurrentStreak     int               `json:"current_streak"` // 続けて学習した日数
+	LongestStreak     int               `json:"longest_streak"`
+	Subjects          []SubjectProgress `json:"subjects"`
+}
+
+// SubjectProgress 科目ごとの進み具合
+type SubjectProgress struct {
+	Subject        string   `json:"subject"`
+	TotalProblems  int      `json:"total_problems"`
+	CorrectAnswers int      `json:"correct_answers"`
+	AccuracyRate   float64  `json:"accuracy_rate"`  // 0-1
+	ProgressLevel  int      `json:"progress_level"` // 1-5
+	Strengths      []string `json:"strengths,omitempty"`
+	Weaknesses     []string `json:"weaknesses,omitempty"`
+}
+
+// TopicMastery 単元ごとの習熟度
+type TopicMastery struct {
+	Subject      string    `json:"subject"`
+	Topic        string    `json:"topic"`
+	Attempts     int       `json:"attempts"`
+	AccuracyRate float64   `json:"accuracy_rate"` // 0-1
+	Mastery      float64   `json:"mastery"`       // 0-1（正解率×問題数の確かさ×記憶の新しさ）
+	Status       string    `json:"status"`        // "strong" | "learning" | "weak"
+	LastStudied  time.Time `json:"last_studied"`
+}
+
+// ProgressAnalyzer 学習の分析（アプリの進捗タブと同じ計算）
+type ProgressAnalyzer interface {
+	Overview(userID string) (*Overview, error)
+	TopicMastery(userID string) ([]TopicMastery, error)
+}
+
+// managerProgress アプリの進捗の分析を使うProgressAnalyzer
+type managerProgress struct {
+	manager *progress.Manager
+}
+
+func (p managerProgress) Overview(userID string) (*Overview, error) {
+	analysis, err := p.manager.AnalyzeProgress(userID)
+	if err != nil {
+		return nil, fmt.Errorf("学習の分析エラー: %w", err)
+	}
+
+	overview := &Overview{}
+	if o := analysis.OverallProgress; o != nil {
+		overview.TotalStudySeconds = o.TotalStudyTime
+		overview.TotalProblems = o.TotalProblems
+		overview.TotalCorrect = o.TotalCorrect
+		overview.AccuracyRate = o.AccuracyRate
+		overview.StudyDays = o.StudyDaysCount
+	}
+	if s := analysis.StudyStreak; s != nil {
+		overview.CurrentStreak = s.CurrentStreak
+		overview.LongestStreak = s.LongestStreak
+	}
+	for _, s := range analysis.SubjectProgress {
+		overview.Subjects = append(overview.Subjects, SubjectProgress{
+			Subject:        s.Subject,
+			TotalProblems:  s.TotalProblems,
+			CorrectAnswers: s.CorrectAnswers,
+			AccuracyRate:   s.AccuracyRate,
+			ProgressLevel:  s.ProgressLevel,
+			Strengths:      s.StrengthAreas,
+			Weaknesses:     s.WeaknessAreas,
+		})
+	}
+	slices.SortFunc(overview.Subjects, func(a, b SubjectProgress) int { return compareSubjects(a.Subject, b.Subject) })
+	return overview, nil
+}
+
+func (p managerProgress) TopicMastery(userID string) ([]TopicMastery, error) {
+	bySubject, err := p.manager.GetTopicMastery(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var topics []TopicMastery
+	for _, subjectTopics := range bySubject {
+		for _, t := range subjectTopics {
+			topics = append(topics, TopicMastery{
+				Subject:      t.Subject,
+				Topic:        t.Topic,
+				Attempts:     t.Attempts,
+				AccuracyRate: t.AccuracyRate,
+				Mastery:      t.Mastery,
+				Status:       t.Status,
+				LastStudied:  t.LastStudied,
+			})
+		}
+	}
+	slices.SortFunc(topics, func(a, b TopicMastery) int {
+		if c := compareSubjects(a.Subject, b.Subject); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Topic, b.Topic)
+	})
+	return topics, nil
+}
+
+// compareSubjects 科目をアプリの既定の並び順で比べる（対応していない科目は後ろに名前順）
+func compareSubjects(a, b string) int {
+	ia, ib := slices.Index(config.Subjects, a), slices.Index(config.Subjects, b)
+	if ia < 0 {
+		ia = len(config.Subjects)
+	}
+	if ib < 0 {
+		ib = len(config.Subjects)
+	}
+	if ia != ib {
+		return ia - ib
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,142 @@
+package studybuddy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+)
+
+// User プロフィール
+type User struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Grade     int        `json:"grade"` // 1:中1, 2:中2, 3:中3
+	CreatedAt time.Time  `json:"created_at"`
+	LastLogin *time.Time `json:"last_login,omitempty"`
+}
+
+// Session 学習セッション
+type Session struct {
+	ID              string     `json:"id"`
+	UserID          string     `json:"user_id"`
+	Subject         string     `json:"subject"`
+	Type            string     `json:"type"` // "app" | "manual" | "exam"
+	StartTime       time.Time  `json:"start_time"`
+	EndTime         *time.Time `json:"end_time,omitempty"`
+	DurationSeconds int        `json:"duration_seconds"`
+	TotalProblems   int        `json:"total_problems"`
+	CorrectAnswers  int        `json:"correct_answers"`
+	MaxCombo        int        `json:"max_combo"`
+	Note            string     `json:"note,omitempty"`
+}
+
+// Answer 問題への解答
+type Answer struct {
+	ID               string    `json:"id"`
+	SessionID        string    `json:"session_id"`
+	Topic            string    `json:"topic"`
+	Difficulty       int       `json:"difficulty"` // 1-5
+	Correct          bool      `json:"correct"`
+	TimeTakenSeconds int       `json:"time_taken_seconds"`
+	ErrorCategory    string    `json:"error_category,omitempty"`
+	Problem          string    `json:"problem,omitempty"`
+	UserAnswer       string    `json:"user_answer,omitempty"`
+	CorrectAnswer    string    `json:"correct_answer,omitempty"`
+	AnsweredAt       time.Time `json:"answered_at"`
+}
+
+// Store 学習データの読み出し（Storeのメソッドは学習データを書き換えない。スキーマの作成・更新はOpenで行う）
+type Store interface {
+	Users() ([]User, error)
+	User(userID string) (*User, error)
+	// Sessions from以降、to より前に始めたセッション（古い順）
+	Sessions(userID string, from, to time.Time) ([]Session, error)
+	// Answers from以降、to より前の解答（古い順）
+	Answers(userID string, from, to time.Time) ([]Answer, error)
+}
+
+// dbStore アプリのデータベースを使うStore
+type dbStore struct {
+	db *database.DB
+}
+
+func (s dbStore) Users() ([]User, error) {
+	users, err := s.db.GetUsers()
+	if err != nil {
+		return nil, fmt.Errorf("プロフィール取得エラー: %w", err)
+	}
+	result := make([]User, len(users))
+	for i, user := range users {
+		result[i] = newUser(user)
+	}
+	return result, nil
+}
+
+func (s dbStore) User(userID string) (*User, error) {
+	user, err := s.db.GetUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("プロフィール取得エラー: %w", err)
+	}
+	result := newUser(*user)
+	return &result, nil
+}
+
+func (s dbStore) Sessions(userID string, from, to time.Time) ([]Session, error) {
+	sessions, err := s.db.GetStudySessionsBetween(userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("学習記録取得エラー: %w", err)
+	}
+	result := make([]Session, len(sessions))
+	for i, session := range sessions {
+		result[i] = Session{
+			ID:              session.ID,
+			UserID:          session.UserID,
+			Subject:         session.Subject,
+			Type:            session.SessionType,
+			StartTime:       session.StartTime,
+			EndTime:         session.EndTime,
+			DurationSeconds: session.DurationSeconds(),
+			TotalProblems:   session.TotalProblems,
+			CorrectAnswers:  session.CorrectAnswers,
+			MaxCombo:        session.MaxCombo,
+			Note:            session.Note,
+		}
+	}
+	return result, nil
+}
+
+func (s dbStore) Answers(userID string, from, to time.Time) ([]Answer, error) {
+	results, err := s.db.GetProblemResultsBetween(userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("解答取得エラー: %w", err)
+	}
+	answers := make([]Answer, len(results))
+	for i, r := range results {
+		answers[i] = Answer{
+			ID:               r.ID,
+			SessionID:        r.SessionID,
+			Topic:            r.ProblemType,
+			Difficulty:       r.Difficulty,
+			Correct:          r.IsCorrect,
+			TimeTakenSeconds: r.TimeTaken,
+			ErrorCategory:    r.ErrorCategory,
+			Problem:          r.ProblemContent,
+			UserAnswer:       r.UserAnswer,
+			CorrectAnswer:    r.CorrectAnswer,
+			AnsweredAt:       r.CreatedAt,
+		}
+	}
+	return answers, nil
+}
+
+// newUser データベースのプロフィールから作る
+func newUser(user database.User) User {
+	return User{
+		ID:        user.ID,
+		Name:      user.Name,
+		Grade:     user.Grade,
+		CreatedAt: user.CreatedAt,
+		LastLogin: user.LastLogin,
+	}
+}
@@ -0,0 +1,119 @@
+package studybuddy
+
+import (
+	"fmt"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+	"github.com/okamyuji/studybuddy-ai/internal/calendar"
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/database"
+	"github.com/okamyuji/studybuddy-ai/internal/progress"
+)
+
+// APIVersion このパッケージの公開APIのバージョン（セマンティックバージョニング）
+// メジャーバージョンが同じあいだは、型・メソッドの削除や意味の変更をせず、追加だけを行う
+const APIVersion = "1.0.0"
+
+// DefaultUserID アプリのプロフィール（学校の共用パソコン向けの制限モード以外で使う）
+const DefaultUserID = "default-user"
+
+// Options 接続するデータベースとAIの設定
+type Options struct {
+	DatabaseDriver string // "sqlite"（既定） | "postgres"（DatabaseSourceと一緒に指定する）
+	DatabaseSource string // SQLiteならファイルのパス、PostgreSQLなら接続URL（空ならアプリの設定ファイルの接続先）
+	OllamaURL      string // 空ならアプリの既定（http://localhost:11434）
+	Model          string // 空ならアプリの既定のモデル
+}
+
+// DefaultOptions アプリの設定ファイル（~/.studybuddy-ai/config.json）と同じ接続先
+func DefaultOptions() (Options, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return Options{}, fmt.Errorf("設定読み込みエラー: %w", err)
+	}
+	driver, dsn := cfg.DatabaseSource()
+	return Options{
+		DatabaseDriver: driver,
+		DatabaseSource: dsn,
+		OllamaURL:      cfg.AI.OllamaURL,
+		Model:          cfg.AI.Model,
+	}, nil
+}
+
+// Client 学習データ・AI・学習の分析をまとめて使うための入り口
+type Client struct {
+	db       *database.DB
+	engine   *ai.Engine
+	progress *progress.Manager
+}
+
+// Open データベースに接続し、AIと学習の分析を使えるようにする
+// （アプリの起動時と同じく、データベースのスキーマを作成・更新する）
+func Open(opts Options) (*Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
+	}
+	if opts.OllamaURL != "" {
+		cfg.AI.OllamaURL = opts.OllamaURL
+	}
+	if opts.Model != "" {
+		cfg.AI.Model = opts.Model
+	}
+	driver, dsn := cfg.DatabaseSource()
+	switch {
+	case opts.DatabaseSource != "":
+		driver, dsn = opts.DatabaseDriver, opts.DatabaseSource
+	case opts.DatabaseDriver != "" && opts.DatabaseDriver != driver:
+		// 接続先がないまま、設定ファイルと違うデータベースを使うことはできない
+		return nil, fmt.Errorf("DatabaseDriver（%s）を指定するときはDatabaseSourceも指定してください", opts.DatabaseDriver)
+	}
+
+	db, err := database.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("データベース接続エラー: %w", err)
+	}
+	engine, err := ai.NewEngine(cfg.AI)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("AI初期化エラー: %w", err)
+	}
+	return newClient(db, engine, cfg), nil
+}
+
+// newClient 接続済みのデータベースとAIエンジンから作る（アプリと同じく使用量・応答・計測をデータベースに記録）
+func newClient(db *database.DB, engine *ai.Engine, cfg *config.Config) *Client {
+	engine.SetUsageStore(db)
+	engine.SetEmbeddingStore(db)
+	engine.SetResponseCache(db)
+	engine.SetMetricsStore(db)
+	return &Client{
+		db:       db,
+		engine:   engine,
+		progress: progress.NewManager(db, engine, calendar.New(cfg)),
+	}
+}
+
+// Store 学習データの読み出し
+func (c *Client) Store() Store {
+	return dbStore{db: c.db}
+}
+
+// Tutor 問題・フィードバックの生成
+func (c *Client) Tutor() Tutor {
+	return engineTutor{engine: c.engine}
+}
+
+// Progress 学習の分析
+func (c *Client) Progress() ProgressAnalyzer {
+	return managerProgress{manager: c.progress}
+}
+
+// Close データベースとAIエンジンを閉じる
+func (c *Client) Close() error {
+	if err := c.engine.Close(); err != nil {
+		_ = c.db.Close()
+		return err
+	}
+	return c.db.Close()
+}
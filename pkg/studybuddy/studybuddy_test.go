@@ -0,0 +1,79 @@
+package studybuddy
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/config"
+	"github.com/okamyuji/studybuddy-ai/internal/testutil"
+)
+
+func newTestClient(t *testing.T) (*Client, string) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	user := testutil.Seed(t, db, testutil.DefaultFixture(time.Now()))
+	engine := testutil.NewEngine(t, testutil.NewCassette(t))
+	return newClient(db, engine, config.Default()), user.ID
+}
+
+func TestStoreReadsStudyData(t *testing.T) {
+	client, userID := newTestClient(t)
+	store := client.Store()
+
+	users, err := store.Users()
+	if err != nil || len(users) != 1 || users[0].ID != userID || users[0].Grade != 2 {
+		t.Fatalf("Users() = %+v, %v", users, err)
+	}
+
+	now := time.Now()
+	sessions, err := store.Sessions(userID, now.AddDate(0, 0, -7), now)
+	if err != nil || len(sessions) != 3 {
+		t.Fatalf("Sessions() = %d件, %v", len(sessions), err)
+	}
+	if sessions[2].Type != "manual" || sessions[2].DurationSeconds != 45*60 {
+		t.Errorf("手動記録 = %+v", sessions[2])
+	}
+
+	answers, err := store.Answers(userID, now.AddDate(0, 0, -7), now)
+	if err != nil || len(answers) != 5 {
+		t.Fatalf("Answers() = %d件, %v", len(answers), err)
+	}
+	if answers[0].Topic != "一次関数" || !answers[0].Correct {
+		t.Errorf("最初の解答 = %+v", answers[0])
+	}
+}
+
+func TestProgressOverview(t *testing.T) {
+	client, userID := newTestClient(t)
+
+	overview, err := client.Progress().Overview(userID)
+	if err != nil {
+		t.Fatalf("Overview() エラー: %v", err)
+	}
+	if overview.StudyDays != 3 || overview.TotalStudySeconds != 80*60 {
+		t.Errorf("学習 = %d日・%d秒, want 3日・%d秒（手動記録を含む）", overview.StudyDays, overview.TotalStudySeconds, 80*60)
+	}
+	for i := 1; i < len(overview.Subjects); i++ {
+		if compareSubjects(overview.Subjects[i-1].Subject, overview.Subjects[i].Subject) > 0 {
+			t.Errorf("科目がアプリの並び順でない: %+v", overview.Subjects)
+		}
+	}
+}
+
+func TestAPIVersionIsSemantic(t *testing.T) {
+	var major, minor, patch int
+	if n, err := fmt.Sscanf(APIVersion, "%d.%d.%d", &major, &minor, &patch); n != 3 || err != nil {
+		t.Errorf("APIVersion = %q はセマンティックバージョンでない", APIVersion)
+	}
+}
+
+func TestOpenRejectsDriverWithoutSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // 設定ファイルがなく、既定のSQLiteを使う
+
+	// 接続先がないまま、設定ファイルと違うデータベースを選んでもSQLiteを開かない
+	if client, err := Open(Options{DatabaseDriver: "postgres"}); err == nil {
+		_ = client.Close()
+		t.Error("DatabaseSourceのないDatabaseDriverはエラーになるはず")
+	}
+}
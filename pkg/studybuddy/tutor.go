@@ -0,0 +1,128 @@
+package studybuddy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/okamyuji/studybuddy-ai/internal/ai"
+)
+
+// Problem 4択などの選択式の問題
+type Problem struct {
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Options       []string `json:"options"`
+	CorrectAnswer int      `json:"correct_answer"` // Optionsの添字（0から）
+	Explanation   string   `json:"explanation"`
+	Difficulty    int      `json:"difficulty"` // 1-5
+	Topic         string   `json:"topic"`
+	Model         string   `json:"model,omitempty"` // 問題を作ったモデル（内蔵の問題なら空）
+}
+
+// ProblemRequest 作る問題の条件
+type ProblemRequest struct {
+	UserID     string `json:"user_id"`
+	Subject    string `json:"subject"`         // 数学・英語・国語・理科・社会
+	Grade      int    `json:"grade"`           // 1:中1, 2:中2, 3:中3
+	Difficulty int    `json:"difficulty"`      // 1-5
+	Topic      string `json:"topic,omitempty"` // 空なら学年の学習範囲全体
+}
+
+// Feedback 解答へのフィードバック
+type Feedback struct {
+	Message       string `json:"message"`
+	Explanation   string `json:"explanation"`
+	Encouragement string `json:"encouragement,omitempty"`
+	NextSteps     string `json:"next_steps,omitempty"`
+}
+
+// Model インストール済みのAIモデル
+type Model struct {
+	Name          string    `json:"name"`
+	Size          int64     `json:"size"` // バイト
+	ParameterSize string    `json:"parameter_size,omitempty"`
+	Quantization  string    `json:"quantization,omitempty"`
+	ModifiedAt    time.Time `json:"modified_at"`
+}
+
+// Tutor 問題・フィードバックの生成（AIに接続できないときは、アプリと同じく内蔵の問題・定型の文を返す）
+type Tutor interface {
+	GenerateProblem(ctx context.Context, req ProblemRequest) (*Problem, error)
+	GenerateFeedback(ctx context.Context, problem Problem, answer int) (*Feedback, error)
+	Models(ctx context.Context) ([]Model, error)
+}
+
+// engineTutor アプリのAIエンジンを使うTutor
+type engineTutor struct {
+	engine *ai.Engine
+}
+
+func (t engineTutor) GenerateProblem(ctx context.Context, req ProblemRequest) (*Problem, error) {
+	problem, err := t.engine.GeneratePersonalizedProblem(ctx, ai.StudyContext{
+		UserID:     req.UserID,
+		Subject:    req.Subject,
+		Grade:      req.Grade,
+		Difficulty: req.Difficulty,
+		Topic:      req.Topic,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("問題生成エラー: %w", err)
+	}
+	return &Problem{
+		Title:         problem.Title,
+		Description:   problem.Description,
+		Options:       problem.Options,
+		CorrectAnswer: problem.CorrectAnswer,
+		Explanation:   problem.Explanation,
+		Difficulty:    problem.Difficulty,
+		Topic:         problem.ProblemType,
+		Model:         problem.Model,
+	}, nil
+}
+
+func (t engineTutor) GenerateFeedback(ctx context.Context, problem Problem, answer int) (*Feedback, error) {
+	if answer < 0 || answer >= len(problem.Options) {
+		return nil, fmt.Errorf("選択肢の範囲外の解答: %d", answer)
+	}
+	feedback, err := t.engine.GenerateFeedback(ctx, ai.FeedbackRequest{
+		Problem: ai.Problem{
+			Title:         problem.Title,
+			Description:   problem.Description,
+			Options:       problem.Options,
+			CorrectAnswer: problem.CorrectAnswer,
+			Explanation:   problem.Explanation,
+			Difficulty:    problem.Difficulty,
+			ProblemType:   problem.Topic,
+		},
+		UserAnswer: problem.Options[answer],
+		IsCorrect:  answer == problem.CorrectAnswer,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("フィードバック生成エラー: %w", err)
+	}
+	return &Feedback{
+		Message:       feedback.Message,
+		Explanation:   feedback.Explanation,
+		Encouragement: feedback.Encouragement,
+		NextSteps:     feedback.NextSteps,
+	}, nil
+}
+
+func (t engineTutor) Models(ctx context.Context) ([]Model, error) {
+	models, err := t.engine.ListModels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("モデル一覧取得エラー: %w", err)
+	}
+	result := make([]Model, len(models))
+	for i, model := range models {
+		result[i] = Model{
+			Name:          model.Name,
+			Size:          model.Size,
+			ParameterSize: model.ParameterSize,
+			Quantization:  model.Quantization,
+			ModifiedAt:    model.ModifiedAt,
+		}
+	}
+	return result, nil
+}